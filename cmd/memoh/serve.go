@@ -61,8 +61,12 @@ import (
 	"github.com/memohai/memoh/internal/handlers"
 	"github.com/memohai/memoh/internal/healthcheck"
 	channelchecker "github.com/memohai/memoh/internal/healthcheck/checkers/channel"
+	containerchecker "github.com/memohai/memoh/internal/healthcheck/checkers/container"
+	gatewaychecker "github.com/memohai/memoh/internal/healthcheck/checkers/gateway"
 	mcpchecker "github.com/memohai/memoh/internal/healthcheck/checkers/mcp"
 	modelchecker "github.com/memohai/memoh/internal/healthcheck/checkers/model"
+	schedulechecker "github.com/memohai/memoh/internal/healthcheck/checkers/schedule"
+	skillchecker "github.com/memohai/memoh/internal/healthcheck/checkers/skill"
 	"github.com/memohai/memoh/internal/heartbeat"
 	"github.com/memohai/memoh/internal/logger"
 	"github.com/memohai/memoh/internal/mcp"
@@ -90,6 +94,8 @@ import (
 	"github.com/memohai/memoh/internal/storage/providers/containerfs"
 	"github.com/memohai/memoh/internal/storage/providers/fallback"
 	"github.com/memohai/memoh/internal/storage/providers/localfs"
+	"github.com/memohai/memoh/internal/storage/providers/mirror"
+	"github.com/memohai/memoh/internal/storage/providers/s3"
 	ttspkg "github.com/memohai/memoh/internal/tts"
 	ttsedge "github.com/memohai/memoh/internal/tts/adapter/edge"
 	"github.com/memohai/memoh/internal/version"
@@ -105,6 +111,7 @@ func runServe() {
 			provideContainerService,
 			provideDBConn,
 			provideDBQueries,
+			provideDBHealthMonitor,
 			provideWorkspaceManager,
 			provideMemoryLLM,
 			memprovider.NewService,
@@ -170,12 +177,15 @@ func runServe() {
 			provideServerHandler(handlers.NewSearchProvidersHandler),
 			provideServerHandler(handlers.NewModelsHandler),
 			provideServerHandler(handlers.NewSettingsHandler),
+			provideServerHandler(handlers.NewBotCloneHandler),
+			provideServerHandler(handlers.NewBotBundleHandler),
 			provideServerHandler(handlers.NewACLHandler),
 			provideServerHandler(handlers.NewBindHandler),
 			provideServerHandler(handlers.NewScheduleHandler),
 			provideServerHandler(handlers.NewHeartbeatHandler),
 			provideServerHandler(handlers.NewCompactionHandler),
 			provideServerHandler(handlers.NewChannelHandler),
+			provideServerHandler(handlers.NewChannelConnectionsHandler),
 			provideServerHandler(channel.NewWebhookServerHandler),
 			provideServerHandler(weixin.NewQRServerHandler),
 			provideServerHandler(provideUsersHandler),
@@ -193,9 +203,14 @@ func runServe() {
 			provideOAuthService,
 			provideServerHandler(handlers.NewTokenUsageHandler),
 			provideServerHandler(handlers.NewSessionInfoHandler),
+			provideServerHandler(handlers.NewRequestPreviewHandler),
+			provideServerHandler(handlers.NewBroadcastHandler),
+			provideServerHandler(handlers.NewRouteHandler),
+			provideServerHandler(handlers.NewMaintenanceHandler),
 			provideServerHandler(handlers.NewBrowserContextsHandler),
 			provideServerHandler(provideWebHandler),
 			provideServerHandler(handlers.NewEmbeddedWebHandler),
+			provideMaintenance,
 			provideServer,
 		),
 		fx.Invoke(
@@ -211,6 +226,11 @@ func runServe() {
 			startContainerReconciliation,
 			startBackgroundTaskCleanup,
 			startTtsTempStoreCleanup,
+			startPassiveMessagePruning,
+			startRouteSweep,
+			startDBHealthMonitor,
+			startBotDeletePurge,
+			stopMemoryStores,
 			startServer,
 		),
 		fx.WithLogger(func(logger *slog.Logger) fxevent.Logger {
@@ -259,7 +279,22 @@ func provideDBConn(lc fx.Lifecycle, cfg config.Config) (*pgxpool.Pool, error) {
 	return conn, nil
 }
 
-func provideDBQueries(conn *pgxpool.Pool) *dbsqlc.Queries { return dbsqlc.New(conn) }
+func provideDBQueries(lc fx.Lifecycle, cfg config.Config, conn *pgxpool.Pool) (*dbsqlc.Queries, error) {
+	replica, err := db.OpenReadReplica(context.Background(), cfg.Postgres)
+	if err != nil {
+		return nil, fmt.Errorf("db connect: %w", err)
+	}
+	if replica == nil {
+		return dbsqlc.New(conn), nil
+	}
+	lc.Append(fx.Hook{OnStop: func(_ context.Context) error { replica.Close(); return nil }})
+	return dbsqlc.New(db.NewQueryRouter(conn, replica, db.ReplicaReadQueries...)), nil
+}
+
+func provideDBHealthMonitor(log *slog.Logger, conn *pgxpool.Pool) *db.HealthMonitor {
+	return db.NewHealthMonitor(log, conn)
+}
+
 func provideWorkspaceManager(log *slog.Logger, service ctr.Service, cfg config.Config, conn *pgxpool.Pool) *workspace.Manager {
 	return workspace.NewManager(log, service, cfg.Workspace, cfg.Containerd.Namespace, conn)
 }
@@ -366,8 +401,10 @@ func provideSessionService(log *slog.Logger, queries *dbsqlc.Queries) *sessionpk
 	return sessionpkg.NewService(log, queries)
 }
 
-func provideMessageService(log *slog.Logger, queries *dbsqlc.Queries, hub *event.Hub) *message.DBService {
-	return message.NewService(log, queries, hub)
+func provideMessageService(log *slog.Logger, queries *dbsqlc.Queries, conn *pgxpool.Pool, hub *event.Hub) *message.DBService {
+	svc := message.NewService(log, queries, hub)
+	svc.SetDB(conn)
+	return svc
 }
 
 func provideScheduleTriggerer(resolver *flow.Resolver) schedule.Triggerer {
@@ -420,17 +457,24 @@ func injectToolProviders(a *agentpkg.Agent, msgService *message.DBService, provi
 	}
 }
 
-func provideChatResolver(log *slog.Logger, a *agentpkg.Agent, modelsService *models.Service, queries *dbsqlc.Queries, chatService *conversation.Service, msgService *message.DBService, settingsService *settings.Service, accountService *accounts.Service, mediaService *media.Service, containerdHandler *handlers.ContainerdHandler, memoryRegistry *memprovider.Registry, routeService *route.DBService, sessionService *sessionpkg.Service, eventHub *event.Hub, compactionService *compaction.Service, pipeline *pipelinepkg.Pipeline, rc *boot.RuntimeConfig, bgManager *background.Manager) *flow.Resolver {
+func provideChatResolver(log *slog.Logger, a *agentpkg.Agent, modelsService *models.Service, queries *dbsqlc.Queries, chatService *conversation.Service, msgService *message.DBService, settingsService *settings.Service, accountService *accounts.Service, mediaService *media.Service, containerdHandler *handlers.ContainerdHandler, memoryRegistry *memprovider.Registry, routeService *route.DBService, sessionService *sessionpkg.Service, eventHub *event.Hub, compactionService *compaction.Service, pipeline *pipelinepkg.Pipeline, rc *boot.RuntimeConfig, cfg config.Config, bgManager *background.Manager) *flow.Resolver {
 	resolver := flow.NewResolver(log, modelsService, queries, chatService, msgService, settingsService, accountService, a, rc.TimezoneLocation, 120*time.Second)
 	resolver.SetMemoryRegistry(memoryRegistry)
 	resolver.SetSkillLoader(&skillLoaderAdapter{handler: containerdHandler})
 	resolver.SetGatewayAssetLoader(&gatewayAssetLoaderAdapter{media: mediaService})
+	resolver.SetAttachmentFallback(mediaService, cfg.Workspace.AttachmentFallbackPath)
+	if cfg.Workspace.VerifyPublicAttachmentURLs {
+		resolver.SetPublicURLCheckClient(flow.NewPublicURLCheckClient())
+	}
 	resolver.SetRouteService(routeService)
 	resolver.SetSessionService(sessionService)
 	resolver.SetEventPublisher(eventHub)
 	resolver.SetCompactionService(compactionService)
 	resolver.SetPipeline(pipeline)
 	resolver.SetBackgroundManager(bgManager)
+	resolver.SetMemoryStoreLimits(cfg.Memory.StoreWorkers, cfg.Memory.StoreQueueSize)
+	resolver.SetGroupContextMinutes(cfg.Bots.GroupContextMinutes)
+	resolver.SetRequestCompression(cfg.Bots.CompressModelRequests)
 	bgManager.SetWakeFunc(func(botID, sessionID string) {
 		resolver.TriggerBackgroundNotification(context.Background(), botID, sessionID)
 	})
@@ -486,6 +530,7 @@ func provideChannelRouter(log *slog.Logger, registry *channel.Registry, hub *loc
 	discussDriver.SetResolver(resolver)
 	discussDriver.SetBroadcaster(hub)
 	processor.SetACLService(aclService)
+	processor.SetBlockList(aclService)
 	processor.SetMediaService(mediaService)
 	processor.SetStreamObserver(local.NewRouteHubBroadcaster(hub))
 	processor.SetDispatcher(inbound.NewRouteDispatcher(log))
@@ -520,6 +565,8 @@ func provideChannelManager(log *slog.Logger, registry *channel.Registry, channel
 	}
 	mgr := channel.NewManager(log, registry, channelStore, channelRouter)
 	mgr.SetAttachmentStore(mediaService)
+	mgr.SetDeliveryRecorder(channelStore)
+	mgr.SetOutboundRetryStore(channelStore)
 	if mw := channelRouter.IdentityMiddleware(); mw != nil {
 		mgr.Use(mw)
 	}
@@ -601,9 +648,11 @@ func provideMemohAuthHandler(log *slog.Logger, accountService *accounts.Service,
 	return &memohAuthHandler{inner: handlers.NewAuthHandler(log, accountService, rc.JwtSecret, rc.JwtExpiresIn)}
 }
 
-func provideMessageHandler(log *slog.Logger, chatService *conversation.Service, msgService *message.DBService, mediaService *media.Service, botService *bots.Service, accountService *accounts.Service, hub *event.Hub) *handlers.MessageHandler {
+func provideMessageHandler(log *slog.Logger, chatService *conversation.Service, msgService *message.DBService, mediaService *media.Service, botService *bots.Service, accountService *accounts.Service, hub *event.Hub, pipeline *pipelinepkg.Pipeline, memoryRegistry *memprovider.Registry, settingsService *settings.Service) *handlers.MessageHandler {
 	h := handlers.NewMessageHandler(log, chatService, msgService, botService, accountService, hub)
 	h.SetMediaService(mediaService)
+	h.SetSessionDropper(pipeline)
+	h.SetMemoryProviders(memoryRegistry, settingsService)
 	return h
 }
 
@@ -618,15 +667,43 @@ func (h *memohAuthHandler) Register(e *echo.Echo) {
 	e.POST("/api/auth/refresh", h.inner.Refresh)
 }
 
-func provideMediaService(log *slog.Logger, manager *workspace.Manager, cfg config.Config) *media.Service {
+func provideMediaService(log *slog.Logger, manager *workspace.Manager, cfg config.Config) (*media.Service, error) {
 	primary := containerfs.New(manager)
+	primary.SetQuota(cfg.Workspace.StorageQuotaBytes())
+	primary.SetVerifyOnRead(cfg.Workspace.VerifyMediaOnRead)
 	dataRoot := cfg.Workspace.DataRoot
 	if dataRoot == "" {
 		dataRoot = config.DefaultDataRoot
 	}
 	secondary := localfs.New(filepath.Join(dataRoot, "media"))
 	provider := fallback.New(primary, secondary)
-	return media.NewService(log, provider)
+	switch cfg.MediaStorage.Provider {
+	case "", config.MediaStorageContainer:
+	case config.MediaStorageS3:
+		s3cfg := cfg.MediaStorage.S3
+		objectStore, err := s3.New(s3.Config{
+			Endpoint:        s3cfg.Endpoint,
+			Region:          s3cfg.Region,
+			Bucket:          s3cfg.Bucket,
+			AccessKeyID:     s3cfg.AccessKeyID,
+			SecretAccessKey: s3cfg.SecretAccessKey,
+			Prefix:          s3cfg.Prefix,
+			PathStyle:       s3cfg.PathStyle,
+		}, nil)
+		if err != nil {
+			return nil, fmt.Errorf("media storage: %w", err)
+		}
+		// Assets stay in the bot container, where attachment paths point,
+		// and are copied to the bucket so they outlive the container.
+		provider = fallback.New(mirror.New(primary, objectStore), secondary)
+	default:
+		return nil, fmt.Errorf("unknown media storage provider %q", cfg.MediaStorage.Provider)
+	}
+	svc := media.NewService(log, provider)
+	svc.SetURLSigningKey(cfg.Auth.JWTSecret)
+	// The API is served under /api next to the web UI.
+	svc.SetURLBasePath("/api")
+	return svc, nil
 }
 
 func provideUsersHandler(log *slog.Logger, accountService *accounts.Service, identityService *identities.Service, botService *bots.Service, routeService *route.DBService, channelStore *channel.Store, channelLifecycle *channel.Lifecycle, channelManager *channel.Manager, registry *channel.Registry) *handlers.UsersHandler {
@@ -648,6 +725,7 @@ type serverParams struct {
 	Config            config.Config
 	ServerHandlers    []server.Handler `group:"server_handlers"`
 	ContainerdHandler *handlers.ContainerdHandler
+	Maintenance       *server.Maintenance
 }
 
 type memohServer struct {
@@ -707,6 +785,10 @@ var (
 func (s *memohServer) Start() error                   { return s.echo.Start(s.addr) }
 func (s *memohServer) Stop(ctx context.Context) error { return s.echo.Shutdown(ctx) }
 
+func provideMaintenance(cfg config.Config) *server.Maintenance {
+	return server.NewMaintenance(cfg.Server.Maintenance)
+}
+
 func provideServer(params serverParams) *memohServer {
 	allHandlers := make([]server.Handler, 0, len(params.ServerHandlers)+1)
 	allHandlers = append(allHandlers, params.ServerHandlers...)
@@ -718,6 +800,7 @@ func provideServer(params serverParams) *memohServer {
 	}
 	e := echo.New()
 	e.HideBanner = true
+	e.IPExtractor = server.IPExtractor(params.Config.Server.TrustedProxies)
 	e.Pre(func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
 			rewriteAPIPathForMemoh(c.Request())
@@ -725,25 +808,25 @@ func provideServer(params serverParams) *memohServer {
 		}
 	})
 	e.Use(middleware.Recover())
-	e.Use(middleware.RequestLoggerWithConfig(middleware.RequestLoggerConfig{
-		LogStatus: true,
-		LogURI:    true,
-		LogMethod: true,
-		LogValuesFunc: func(c echo.Context, v middleware.RequestLoggerValues) error {
-			params.Logger.Info("request",
-				slog.String("method", v.Method),
-				slog.String("uri", v.URI),
-				slog.Int("status", v.Status),
-				slog.Duration("latency", v.Latency),
-				slog.String("remote_ip", c.RealIP()),
-			)
-			return nil
-		},
-	}))
+	e.Use(middleware.RequestID())
+	e.Use(server.AccessLogMiddleware(params.Logger, params.Config.Server.AccessLog))
+	for _, mw := range []echo.MiddlewareFunc{
+		server.CORSMiddleware(params.Config.Server.CORS),
+		server.BodyLimitMiddleware(params.Config.Server.MaxBodyMB),
+		server.RequestTimeoutMiddleware(params.Config.Server.RequestTimeoutSeconds),
+	} {
+		if mw != nil {
+			e.Use(mw)
+		}
+	}
 	e.Use(auth.JWTMiddleware(params.Config.Auth.JWTSecret, func(c echo.Context) bool {
-		return shouldSkipJWTForMemoh(c.Request().URL.Path)
+		return shouldSkipJWTForMemoh(c.Request().URL.Path) || server.IsSignedMediaRequest(c.Request().URL)
 	}))
-	for _, h := range allHandlers {
+	if mw := server.RateLimitMiddleware(params.Config.Server.RateLimit); mw != nil {
+		e.Use(mw)
+	}
+	e.Use(params.Maintenance.Middleware)
+	for _, h := range server.FilterHandlers(params.Logger, allHandlers, params.Config.Server.DisabledHandlers) {
 		if h != nil {
 			h.Register(e)
 		}
@@ -771,7 +854,7 @@ func startContainerReconciliation(lc fx.Lifecycle, manager *workspace.Manager, _
 	lc.Append(fx.Hook{OnStart: func(ctx context.Context) error { go manager.ReconcileContainers(ctx); return nil }})
 }
 
-func startServer(lc fx.Lifecycle, logger *slog.Logger, srv *memohServer, shutdowner fx.Shutdowner, cfg config.Config, queries *dbsqlc.Queries, botService *bots.Service, _ *handlers.ContainerdHandler, manager *workspace.Manager, mcpConnService *mcp.ConnectionService, toolGateway *mcp.ToolGatewayService, channelManager *channel.Manager, modelsService *models.Service) {
+func startServer(lc fx.Lifecycle, logger *slog.Logger, srv *memohServer, shutdowner fx.Shutdowner, cfg config.Config, queries *dbsqlc.Queries, botService *bots.Service, containerdHandler *handlers.ContainerdHandler, manager *workspace.Manager, mcpConnService *mcp.ConnectionService, toolGateway *mcp.ToolGatewayService, channelManager *channel.Manager, modelsService *models.Service, scheduleService *schedule.Service) {
 	fmt.Printf("Starting Memoh Agent %s\n", version.GetInfo())
 	lc.Append(fx.Hook{
 		OnStart: func(ctx context.Context) error {
@@ -779,6 +862,10 @@ func startServer(lc fx.Lifecycle, logger *slog.Logger, srv *memohServer, shutdow
 				return err
 			}
 			botService.SetContainerLifecycle(manager)
+			botService.SetCreateDefaults(bots.CreateDefaults{
+				ChatModel:      cfg.Bots.DefaultChatModel,
+				MemoryProvider: cfg.Bots.DefaultMemoryProvider,
+			})
 			botService.SetContainerReachability(func(ctx context.Context, botID string) error {
 				_, err := manager.MCPClient(ctx, botID)
 				return err
@@ -786,6 +873,10 @@ func startServer(lc fx.Lifecycle, logger *slog.Logger, srv *memohServer, shutdow
 			botService.AddRuntimeChecker(healthcheck.NewRuntimeCheckerAdapter(mcpchecker.NewChecker(logger, mcpConnService, toolGateway)))
 			botService.AddRuntimeChecker(healthcheck.NewRuntimeCheckerAdapter(channelchecker.NewChecker(logger, channelManager)))
 			botService.AddRuntimeChecker(healthcheck.NewRuntimeCheckerAdapter(modelchecker.NewChecker(logger, modelchecker.NewQueriesLookup(queries), modelsService)))
+			botService.AddRuntimeChecker(healthcheck.NewRuntimeCheckerAdapter(containerchecker.NewChecker(logger, manager)))
+			botService.AddRuntimeChecker(healthcheck.NewRuntimeCheckerAdapter(skillchecker.NewChecker(logger, &skillLoaderAdapter{handler: containerdHandler})))
+			botService.AddRuntimeChecker(healthcheck.NewRuntimeCheckerAdapter(gatewaychecker.NewChecker(logger, cfg.BrowserGateway.BaseURL(), nil)))
+			botService.AddRuntimeChecker(healthcheck.NewRuntimeCheckerAdapter(schedulechecker.NewChecker(logger, scheduleService, schedule.DefaultFailureAlertThreshold)))
 			go func() {
 				if err := srv.Start(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 					logger.Error("server failed", slog.Any("error", err))
@@ -881,6 +972,69 @@ func startTtsTempStoreCleanup(lc fx.Lifecycle, store *ttspkg.TempStore) {
 	})
 }
 
+func startPassiveMessagePruning(lc fx.Lifecycle, messageService *message.DBService) {
+	ctx, cancel := context.WithCancel(context.Background())
+	lc.Append(fx.Hook{
+		OnStart: func(_ context.Context) error {
+			go messageService.StartPassivePruneLoop(ctx, message.DefaultPassivePruneInterval)
+			return nil
+		},
+		OnStop: func(_ context.Context) error {
+			cancel()
+			return nil
+		},
+	})
+}
+
+func startRouteSweep(lc fx.Lifecycle, cfg config.Config, routeService *route.DBService) {
+	ctx, cancel := context.WithCancel(context.Background())
+	lc.Append(fx.Hook{
+		OnStart: func(_ context.Context) error {
+			go routeService.StartStaleSweepLoop(ctx, cfg.Bots.RouteTTL(), route.DefaultStaleSweepInterval)
+			return nil
+		},
+		OnStop: func(_ context.Context) error {
+			cancel()
+			return nil
+		},
+	})
+}
+
+func startDBHealthMonitor(lc fx.Lifecycle, monitor *db.HealthMonitor) {
+	ctx, cancel := context.WithCancel(context.Background())
+	lc.Append(fx.Hook{
+		OnStart: func(_ context.Context) error { go monitor.StartLoop(ctx, db.DefaultHealthCheckInterval); return nil },
+		OnStop:  func(_ context.Context) error { cancel(); return nil },
+	})
+}
+
+func startBotDeletePurge(lc fx.Lifecycle, cfg config.Config, botService *bots.Service, channelManager *channel.Manager, scheduleService *schedule.Service, heartbeatService *heartbeat.Service) {
+	botService.SetDeleteGracePeriod(cfg.Bots.DeleteGracePeriod())
+	botService.AddRuntimeController(channelManager)
+	botService.AddRuntimeController(scheduleService)
+	botService.AddRuntimeController(heartbeatService)
+	ctx, cancel := context.WithCancel(context.Background())
+	lc.Append(fx.Hook{
+		OnStart: func(_ context.Context) error {
+			go botService.StartDeletePurgeLoop(ctx, bots.DefaultDeletePurgeInterval)
+			return nil
+		},
+		OnStop: func(_ context.Context) error {
+			cancel()
+			return nil
+		},
+	})
+}
+
+// stopMemoryStores lets queued memory stores finish before shutdown.
+func stopMemoryStores(lc fx.Lifecycle, resolver *flow.Resolver) {
+	lc.Append(fx.Hook{
+		OnStop: func(ctx context.Context) error {
+			return resolver.CloseMemoryStores(ctx)
+		},
+	})
+}
+
 func startBackgroundTaskCleanup(lc fx.Lifecycle, mgr *background.Manager) {
 	done := make(chan struct{})
 	lc.Append(fx.Hook{
@@ -1103,6 +1257,7 @@ func (c *lazyLLMClient) resolve(ctx context.Context, botID string) (memprovider.
 		BaseURL:    strings.TrimRight(providers.ProviderConfigString(memoryProvider, "base_url"), "/"),
 		APIKey:     providers.ProviderConfigString(memoryProvider, "api_key"),
 		ClientType: memoryProvider.ClientType,
+		Headers:    providers.ProviderConfigHeaders(memoryProvider),
 		Timeout:    c.timeout,
 	}), nil
 }
@@ -1116,7 +1271,7 @@ func (a *skillLoaderAdapter) LoadSkills(ctx context.Context, botID string) ([]fl
 	}
 	entries := make([]flow.SkillEntry, len(items))
 	for i, item := range items {
-		entries[i] = flow.SkillEntry{Name: item.Name, Description: item.Description, Content: item.Content, Metadata: item.Metadata}
+		entries[i] = flow.SkillEntry{Name: item.Name, Description: item.Description, Content: item.Content, Metadata: item.Metadata, Error: item.Error}
 	}
 	return entries, nil
 }
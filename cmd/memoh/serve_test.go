@@ -0,0 +1,74 @@
+package main
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/memohai/memoh/internal/boot"
+	"github.com/memohai/memoh/internal/config"
+	"github.com/memohai/memoh/internal/handlers"
+	"github.com/memohai/memoh/internal/server"
+)
+
+type routeHandler func(e *echo.Echo)
+
+func (f routeHandler) Register(e *echo.Echo) { f(e) }
+
+func newTestMemohServer(t *testing.T, cfg config.Config) *memohServer {
+	t.Helper()
+	ok := func(c echo.Context) error { return c.NoContent(http.StatusOK) }
+	return provideServer(serverParams{
+		Logger:        slog.New(slog.DiscardHandler),
+		RuntimeConfig: &boot.RuntimeConfig{},
+		Config:        cfg,
+		ServerHandlers: []server.Handler{routeHandler(func(e *echo.Echo) {
+			e.POST("/api/auth/login", ok)
+			e.POST("/api/auth/refresh", ok)
+		})},
+		ContainerdHandler: &handlers.ContainerdHandler{},
+		Maintenance:       server.NewMaintenance(cfg.Server.Maintenance),
+	})
+}
+
+func serveMemoh(srv *memohServer, method, path string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	req.RemoteAddr = "10.0.0.1:1234"
+	rec := httptest.NewRecorder()
+	srv.echo.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestMemohServerRateLimitsLogin(t *testing.T) {
+	var cfg config.Config
+	cfg.Auth.JWTSecret = "test-secret"
+	cfg.Server.RateLimit.LoginPerMinute = 1
+	srv := newTestMemohServer(t, cfg)
+
+	if rec := serveMemoh(srv, http.MethodPost, "/api/auth/login"); rec.Code != http.StatusOK {
+		t.Fatalf("first login status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	rec := serveMemoh(srv, http.MethodPost, "/api/auth/login")
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("second login status = %d, want %d", rec.Code, http.StatusTooManyRequests)
+	}
+	if rec.Header().Get("Retry-After") == "" {
+		t.Fatal("expected Retry-After header")
+	}
+}
+
+func TestMemohServerMaintenanceAllowsSignIn(t *testing.T) {
+	var cfg config.Config
+	cfg.Auth.JWTSecret = "test-secret"
+	cfg.Server.Maintenance.Enabled = true
+	srv := newTestMemohServer(t, cfg)
+
+	for _, path := range []string{"/api/auth/login", "/api/auth/refresh"} {
+		if rec := serveMemoh(srv, http.MethodPost, path); rec.Code == http.StatusServiceUnavailable {
+			t.Fatalf("%s blocked in maintenance mode", path)
+		}
+	}
+}
@@ -156,7 +156,13 @@ CREATE TABLE IF NOT EXISTS bots (
   tts_model_id UUID REFERENCES models(id) ON DELETE SET NULL,
   browser_context_id UUID REFERENCES browser_contexts(id) ON DELETE SET NULL,
   context_token_budget INTEGER,
+  system_prompt_reserve INTEGER,
   persist_full_tool_results BOOLEAN NOT NULL DEFAULT false,
+  persist_reasoning BOOLEAN NOT NULL DEFAULT false,
+  monthly_token_cap BIGINT NOT NULL DEFAULT 0,
+  monthly_cost_cap_usd DOUBLE PRECISION NOT NULL DEFAULT 0,
+  delete_requested_at TIMESTAMPTZ,
+  search_fallback_provider_ids UUID[] NOT NULL DEFAULT '{}',
   metadata JSONB NOT NULL DEFAULT '{}'::jsonb,
   created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
   updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
@@ -214,6 +220,18 @@ CREATE INDEX IF NOT EXISTS idx_bot_acl_rules_bot_id ON bot_acl_rules(bot_id);
 CREATE INDEX IF NOT EXISTS idx_bot_acl_rules_user_id ON bot_acl_rules(user_id);
 CREATE INDEX IF NOT EXISTS idx_bot_acl_rules_channel_identity_id ON bot_acl_rules(channel_identity_id);
 
+-- bot_blocked_senders: channel identities whose inbound messages a bot drops
+CREATE TABLE IF NOT EXISTS bot_blocked_senders (
+  bot_id UUID NOT NULL REFERENCES bots(id) ON DELETE CASCADE,
+  channel_identity_id UUID NOT NULL REFERENCES channel_identities(id) ON DELETE CASCADE,
+  reason TEXT NOT NULL DEFAULT '',
+  notify BOOLEAN NOT NULL DEFAULT false,
+  notified_at TIMESTAMPTZ,
+  created_by_user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  PRIMARY KEY (bot_id, channel_identity_id)
+);
+
 CREATE TABLE IF NOT EXISTS mcp_connections (
   id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
   bot_id UUID NOT NULL REFERENCES bots(id) ON DELETE CASCADE,
@@ -283,6 +301,25 @@ CREATE UNIQUE INDEX IF NOT EXISTS idx_bot_channel_external_identity
 
 CREATE INDEX IF NOT EXISTS idx_bot_channel_bot_id ON bot_channel_configs(bot_id);
 
+-- bot_channel_outbound_retries: outbound sends that failed transiently, drained in the background.
+CREATE TABLE IF NOT EXISTS bot_channel_outbound_retries (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  bot_id UUID NOT NULL REFERENCES bots(id) ON DELETE CASCADE,
+  channel_type TEXT NOT NULL,
+  request JSONB NOT NULL,
+  status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'sending', 'failed')),
+  attempts INTEGER NOT NULL DEFAULT 0,
+  max_attempts INTEGER NOT NULL,
+  next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  last_error TEXT NOT NULL DEFAULT '',
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_channel_outbound_retries_due
+  ON bot_channel_outbound_retries(next_attempt_at)
+  WHERE status IN ('pending', 'sending');
+
 -- channel_identity_bind_codes: one-time codes for channel identity->user linking
 CREATE TABLE IF NOT EXISTS channel_identity_bind_codes (
   id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
@@ -310,6 +347,9 @@ CREATE TABLE IF NOT EXISTS bot_channel_routes (
   default_reply_target TEXT,
   active_session_id UUID,
   metadata JSONB NOT NULL DEFAULT '{}'::jsonb,
+  muted BOOLEAN NOT NULL DEFAULT false,
+  pinned BOOLEAN NOT NULL DEFAULT false,
+  annotations JSONB NOT NULL DEFAULT '{}'::jsonb,
   created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
   updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
 );
@@ -390,6 +430,9 @@ CREATE INDEX IF NOT EXISTS idx_bot_history_messages_session_source
   ON bot_history_messages(session_id, source_message_id);
 CREATE INDEX IF NOT EXISTS idx_bot_history_messages_session_reply
   ON bot_history_messages(session_id, source_reply_to_message_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_bot_history_messages_external_dedup
+  ON bot_history_messages (bot_id, session_id, (COALESCE(metadata->>'platform', '')), source_message_id, role)
+  WHERE session_id IS NOT NULL AND source_message_id IS NOT NULL AND source_message_id != '';
 
 CREATE TABLE IF NOT EXISTS containers (
   id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
@@ -463,7 +506,10 @@ CREATE TABLE IF NOT EXISTS schedule (
   updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
   enabled BOOLEAN NOT NULL DEFAULT true,
   command TEXT NOT NULL,
-  bot_id UUID NOT NULL REFERENCES bots(id) ON DELETE CASCADE
+  bot_id UUID NOT NULL REFERENCES bots(id) ON DELETE CASCADE,
+  max_runtime_seconds INTEGER,
+  condition TEXT NOT NULL DEFAULT '',
+  skipped_calls INTEGER NOT NULL DEFAULT 0
 );
 
 CREATE INDEX IF NOT EXISTS idx_schedule_bot_id ON schedule(bot_id);
@@ -510,6 +556,47 @@ CREATE TABLE IF NOT EXISTS bot_history_message_assets (
 
 CREATE INDEX IF NOT EXISTS idx_message_assets_message_id ON bot_history_message_assets(message_id);
 
+-- bot_message_delivery_receipts: outbound delivery results reported by channel adapters.
+CREATE TABLE IF NOT EXISTS bot_message_delivery_receipts (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  bot_id UUID NOT NULL REFERENCES bots(id) ON DELETE CASCADE,
+  message_id UUID REFERENCES bot_history_messages(id) ON DELETE CASCADE,
+  channel_type TEXT NOT NULL,
+  target TEXT NOT NULL DEFAULT '',
+  platform_message_id TEXT NOT NULL DEFAULT '',
+  status TEXT NOT NULL CHECK (status IN ('delivered', 'failed')),
+  error TEXT NOT NULL DEFAULT '',
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_message_delivery_receipts_message_id
+  ON bot_message_delivery_receipts(message_id)
+  WHERE message_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_message_delivery_receipts_bot_created
+  ON bot_message_delivery_receipts(bot_id, created_at DESC);
+
+-- bot_message_tool_calls: structured tool calls linked to the assistant message that issued them.
+CREATE TABLE IF NOT EXISTS bot_message_tool_calls (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  bot_id UUID NOT NULL REFERENCES bots(id) ON DELETE CASCADE,
+  session_id UUID REFERENCES bot_sessions(id) ON DELETE SET NULL,
+  message_id UUID NOT NULL REFERENCES bot_history_messages(id) ON DELETE CASCADE,
+  tool_call_id TEXT NOT NULL DEFAULT '',
+  tool_name TEXT NOT NULL,
+  arguments JSONB,
+  result JSONB,
+  is_error BOOLEAN NOT NULL DEFAULT false,
+  duration_ms BIGINT,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_message_tool_calls_message_id
+  ON bot_message_tool_calls(message_id);
+CREATE INDEX IF NOT EXISTS idx_message_tool_calls_bot_created
+  ON bot_message_tool_calls(bot_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_message_tool_calls_bot_tool_created
+  ON bot_message_tool_calls(bot_id, tool_name, created_at DESC);
+
 
 -- bot_heartbeat_logs: structured execution records for periodic heartbeat checks.
 CREATE TABLE IF NOT EXISTS bot_heartbeat_logs (
@@ -550,7 +637,7 @@ CREATE TABLE IF NOT EXISTS schedule_logs (
   schedule_id UUID NOT NULL REFERENCES schedule(id) ON DELETE CASCADE,
   bot_id UUID NOT NULL REFERENCES bots(id) ON DELETE CASCADE,
   session_id UUID REFERENCES bot_sessions(id) ON DELETE SET NULL,
-  status TEXT NOT NULL DEFAULT 'ok' CHECK (status IN ('ok', 'error')),
+  status TEXT NOT NULL DEFAULT 'ok' CHECK (status IN ('ok', 'error', 'timeout')),
   result_text TEXT NOT NULL DEFAULT '',
   error_message TEXT NOT NULL DEFAULT '',
   usage JSONB,
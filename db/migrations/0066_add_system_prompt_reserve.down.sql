@@ -0,0 +1,6 @@
+-- 0066_add_system_prompt_reserve (down)
+-- NOTE: After rolling back this migration, re-run `sqlc generate` to update the
+-- generated Go code in internal/db/sqlc/. The Go structs will still contain the
+-- new columns until regenerated.
+
+ALTER TABLE bots DROP COLUMN IF EXISTS system_prompt_reserve;
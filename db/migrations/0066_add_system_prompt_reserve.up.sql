@@ -0,0 +1,4 @@
+-- 0066_add_system_prompt_reserve
+-- Add a per-bot token reserve for the system prompt, subtracted from the context token budget before trimming history.
+
+ALTER TABLE bots ADD COLUMN IF NOT EXISTS system_prompt_reserve INTEGER;
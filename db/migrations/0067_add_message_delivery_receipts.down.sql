@@ -0,0 +1,6 @@
+-- 0067_add_message_delivery_receipts (rollback)
+-- Remove outbound delivery receipts.
+
+DROP INDEX IF EXISTS idx_message_delivery_receipts_bot_created;
+DROP INDEX IF EXISTS idx_message_delivery_receipts_message_id;
+DROP TABLE IF EXISTS bot_message_delivery_receipts;
@@ -0,0 +1,20 @@
+-- 0067_add_message_delivery_receipts
+-- Record per-channel outbound delivery receipts (platform message ID, status) against message rows.
+
+CREATE TABLE IF NOT EXISTS bot_message_delivery_receipts (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  bot_id UUID NOT NULL REFERENCES bots(id) ON DELETE CASCADE,
+  message_id UUID REFERENCES bot_history_messages(id) ON DELETE CASCADE,
+  channel_type TEXT NOT NULL,
+  target TEXT NOT NULL DEFAULT '',
+  platform_message_id TEXT NOT NULL DEFAULT '',
+  status TEXT NOT NULL CHECK (status IN ('delivered', 'failed')),
+  error TEXT NOT NULL DEFAULT '',
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_message_delivery_receipts_message_id
+  ON bot_message_delivery_receipts(message_id)
+  WHERE message_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_message_delivery_receipts_bot_created
+  ON bot_message_delivery_receipts(bot_id, created_at DESC);
@@ -0,0 +1,5 @@
+-- 0068_add_channel_outbound_retries (rollback)
+-- Remove the outbound send retry queue.
+
+DROP INDEX IF EXISTS idx_channel_outbound_retries_due;
+DROP TABLE IF EXISTS bot_channel_outbound_retries;
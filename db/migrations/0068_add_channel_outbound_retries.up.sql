@@ -0,0 +1,20 @@
+-- 0068_add_channel_outbound_retries
+-- Persist outbound channel sends that failed transiently so they can be retried in the background.
+
+CREATE TABLE IF NOT EXISTS bot_channel_outbound_retries (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  bot_id UUID NOT NULL REFERENCES bots(id) ON DELETE CASCADE,
+  channel_type TEXT NOT NULL,
+  request JSONB NOT NULL,
+  status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'sending', 'failed')),
+  attempts INTEGER NOT NULL DEFAULT 0,
+  max_attempts INTEGER NOT NULL,
+  next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  last_error TEXT NOT NULL DEFAULT '',
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_channel_outbound_retries_due
+  ON bot_channel_outbound_retries(next_attempt_at)
+  WHERE status IN ('pending', 'sending');
@@ -0,0 +1,6 @@
+-- 0069_add_route_muted (down)
+-- NOTE: After rolling back this migration, re-run `sqlc generate` to update the
+-- generated Go code in internal/db/sqlc/. The Go structs will still contain the
+-- new columns until regenerated.
+
+ALTER TABLE bot_channel_routes DROP COLUMN IF EXISTS muted;
@@ -0,0 +1,4 @@
+-- 0069_add_route_muted
+-- Add a muted flag to channel routes; muted conversations are still recorded but the bot does not reply.
+
+ALTER TABLE bot_channel_routes ADD COLUMN IF NOT EXISTS muted BOOLEAN NOT NULL DEFAULT false;
@@ -0,0 +1,4 @@
+-- 0070_add_bot_blocked_senders (rollback)
+-- Remove the per-bot sender block list.
+
+DROP TABLE IF EXISTS bot_blocked_senders;
@@ -0,0 +1,13 @@
+-- 0070_add_bot_blocked_senders
+-- Per-bot block list of channel identities whose inbound messages are dropped.
+
+CREATE TABLE IF NOT EXISTS bot_blocked_senders (
+  bot_id UUID NOT NULL REFERENCES bots(id) ON DELETE CASCADE,
+  channel_identity_id UUID NOT NULL REFERENCES channel_identities(id) ON DELETE CASCADE,
+  reason TEXT NOT NULL DEFAULT '',
+  notify BOOLEAN NOT NULL DEFAULT false,
+  notified_at TIMESTAMPTZ,
+  created_by_user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  PRIMARY KEY (bot_id, channel_identity_id)
+);
@@ -0,0 +1,4 @@
+-- 0071_add_route_annotations
+-- Add operator-maintained notes and tags to channel routes, surfaced to the agent for personalization.
+
+ALTER TABLE bot_channel_routes ADD COLUMN IF NOT EXISTS annotations JSONB NOT NULL DEFAULT '{}'::jsonb;
@@ -0,0 +1,7 @@
+-- 0072_add_message_tool_calls (rollback)
+-- Remove structured tool call rows.
+
+DROP INDEX IF EXISTS idx_message_tool_calls_bot_tool_created;
+DROP INDEX IF EXISTS idx_message_tool_calls_bot_created;
+DROP INDEX IF EXISTS idx_message_tool_calls_message_id;
+DROP TABLE IF EXISTS bot_message_tool_calls;
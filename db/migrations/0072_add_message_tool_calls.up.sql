@@ -0,0 +1,23 @@
+-- 0072_add_message_tool_calls
+-- Store tool calls (name, arguments, result, duration) as structured rows linked to the assistant message that issued them.
+
+CREATE TABLE IF NOT EXISTS bot_message_tool_calls (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  bot_id UUID NOT NULL REFERENCES bots(id) ON DELETE CASCADE,
+  session_id UUID REFERENCES bot_sessions(id) ON DELETE SET NULL,
+  message_id UUID NOT NULL REFERENCES bot_history_messages(id) ON DELETE CASCADE,
+  tool_call_id TEXT NOT NULL DEFAULT '',
+  tool_name TEXT NOT NULL,
+  arguments JSONB,
+  result JSONB,
+  is_error BOOLEAN NOT NULL DEFAULT false,
+  duration_ms BIGINT,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_message_tool_calls_message_id
+  ON bot_message_tool_calls(message_id);
+CREATE INDEX IF NOT EXISTS idx_message_tool_calls_bot_created
+  ON bot_message_tool_calls(bot_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_message_tool_calls_bot_tool_created
+  ON bot_message_tool_calls(bot_id, tool_name, created_at DESC);
@@ -0,0 +1,4 @@
+-- 0073_add_persist_reasoning
+-- Add opt-in setting for storing the model's reasoning trace with assistant messages.
+
+ALTER TABLE bots ADD COLUMN IF NOT EXISTS persist_reasoning BOOLEAN NOT NULL DEFAULT false;
@@ -0,0 +1,10 @@
+-- 0074_add_schedule_max_runtime (down)
+-- NOTE: After rolling back this migration, re-run `sqlc generate` to update the
+-- generated Go code in internal/db/sqlc/. The Go structs will still contain the
+-- new columns until regenerated.
+
+UPDATE schedule_logs SET status = 'error' WHERE status = 'timeout';
+ALTER TABLE schedule_logs DROP CONSTRAINT IF EXISTS schedule_logs_status_check;
+ALTER TABLE schedule_logs ADD CONSTRAINT schedule_logs_status_check CHECK (status IN ('ok', 'error'));
+
+ALTER TABLE schedule DROP COLUMN IF EXISTS max_runtime_seconds;
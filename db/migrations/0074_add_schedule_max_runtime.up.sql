@@ -0,0 +1,7 @@
+-- 0074_add_schedule_max_runtime
+-- Add a per-schedule run time cap and record runs cut off by it as 'timeout'.
+
+ALTER TABLE schedule ADD COLUMN IF NOT EXISTS max_runtime_seconds INTEGER;
+
+ALTER TABLE schedule_logs DROP CONSTRAINT IF EXISTS schedule_logs_status_check;
+ALTER TABLE schedule_logs ADD CONSTRAINT schedule_logs_status_check CHECK (status IN ('ok', 'error', 'timeout'));
@@ -0,0 +1,7 @@
+-- 0075_add_schedule_condition (down)
+-- NOTE: After rolling back this migration, re-run `sqlc generate` to update the
+-- generated Go code in internal/db/sqlc/. The Go structs will still contain the
+-- new columns until regenerated.
+
+ALTER TABLE schedule DROP COLUMN IF EXISTS skipped_calls;
+ALTER TABLE schedule DROP COLUMN IF EXISTS condition;
@@ -0,0 +1,5 @@
+-- 0075_add_schedule_condition
+-- Add an optional pre-condition to schedules and count runs skipped because it was unmet.
+
+ALTER TABLE schedule ADD COLUMN IF NOT EXISTS condition TEXT NOT NULL DEFAULT '';
+ALTER TABLE schedule ADD COLUMN IF NOT EXISTS skipped_calls INTEGER NOT NULL DEFAULT 0;
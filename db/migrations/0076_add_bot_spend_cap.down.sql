@@ -0,0 +1,7 @@
+-- 0076_add_bot_spend_cap (down)
+-- NOTE: After rolling back this migration, re-run `sqlc generate` to update the
+-- generated Go code in internal/db/sqlc/. The Go structs will still contain the
+-- new columns until regenerated.
+
+ALTER TABLE bots DROP COLUMN IF EXISTS monthly_cost_cap_usd;
+ALTER TABLE bots DROP COLUMN IF EXISTS monthly_token_cap;
@@ -0,0 +1,5 @@
+-- 0076_add_bot_spend_cap
+-- Add monthly token and estimated cost caps per bot. Zero disables a cap.
+
+ALTER TABLE bots ADD COLUMN IF NOT EXISTS monthly_token_cap BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE bots ADD COLUMN IF NOT EXISTS monthly_cost_cap_usd DOUBLE PRECISION NOT NULL DEFAULT 0;
@@ -0,0 +1,4 @@
+-- 0077_dedup_history_messages_by_external_id (down)
+-- Drop the external message dedup index.
+
+DROP INDEX IF EXISTS idx_bot_history_messages_external_dedup;
@@ -0,0 +1,29 @@
+-- 0077_dedup_history_messages_by_external_id
+-- Make message persistence idempotent per external message: a re-delivered
+-- platform message must not be stored twice in the same conversation.
+-- Platform message IDs are only unique within one chat, so the key includes
+-- the session. Existing rows are never deleted: when duplicates are already
+-- stored the index is not built and a warning reports how many there are.
+
+DO $$
+DECLARE
+  duplicate_count BIGINT;
+BEGIN
+  SELECT COUNT(*) INTO duplicate_count
+  FROM (
+    SELECT 1
+    FROM bot_history_messages
+    WHERE session_id IS NOT NULL
+      AND source_message_id IS NOT NULL AND source_message_id != ''
+    GROUP BY bot_id, session_id, COALESCE(metadata->>'platform', ''), source_message_id, role
+    HAVING COUNT(*) > 1
+  ) duplicates;
+
+  IF duplicate_count > 0 THEN
+    RAISE WARNING 'bot_history_messages has % duplicated external messages; skipping idx_bot_history_messages_external_dedup', duplicate_count;
+  ELSE
+    CREATE UNIQUE INDEX IF NOT EXISTS idx_bot_history_messages_external_dedup
+      ON bot_history_messages (bot_id, session_id, (COALESCE(metadata->>'platform', '')), source_message_id, role)
+      WHERE session_id IS NOT NULL AND source_message_id IS NOT NULL AND source_message_id != '';
+  END IF;
+END $$;
@@ -0,0 +1,10 @@
+-- 0078_add_bot_delete_requested_at
+-- Record when a bot delete was requested so it can be undone within a grace window.
+
+ALTER TABLE bots ADD COLUMN IF NOT EXISTS delete_requested_at TIMESTAMPTZ;
+
+-- Bots already being deleted have no request time; use their last update so
+-- they are still purged once the grace window passes.
+UPDATE bots
+SET delete_requested_at = updated_at
+WHERE status = 'deleting' AND delete_requested_at IS NULL;
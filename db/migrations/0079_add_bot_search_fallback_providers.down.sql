@@ -0,0 +1,6 @@
+-- 0079_add_bot_search_fallback_providers (down)
+-- NOTE: After rolling back this migration, re-run `sqlc generate` to update the
+-- generated Go code in internal/db/sqlc/. The Go structs will still contain the
+-- new columns until regenerated.
+
+ALTER TABLE bots DROP COLUMN IF EXISTS search_fallback_provider_ids;
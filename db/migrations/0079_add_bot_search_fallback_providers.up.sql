@@ -0,0 +1,4 @@
+-- 0079_add_bot_search_fallback_providers
+-- Add an ordered list of search providers tried when the bot's search provider fails.
+
+ALTER TABLE bots ADD COLUMN IF NOT EXISTS search_fallback_provider_ids UUID[] NOT NULL DEFAULT '{}';
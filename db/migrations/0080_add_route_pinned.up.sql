@@ -0,0 +1,4 @@
+-- 0080_add_route_pinned
+-- Add a pinned flag to channel routes; pinned conversations are skipped by retention sweeps.
+
+ALTER TABLE bot_channel_routes ADD COLUMN IF NOT EXISTS pinned BOOLEAN NOT NULL DEFAULT false;
@@ -0,0 +1,43 @@
+-- name: UpsertBotBlockedSender :one
+INSERT INTO bot_blocked_senders (bot_id, channel_identity_id, reason, notify, created_by_user_id)
+VALUES ($1, $2, $3, $4, sqlc.narg(created_by_user_id)::uuid)
+ON CONFLICT (bot_id, channel_identity_id) DO UPDATE SET
+  reason = EXCLUDED.reason,
+  notify = EXCLUDED.notify
+RETURNING bot_id, channel_identity_id, reason, notify, notified_at, created_by_user_id, created_at;
+
+-- name: DeleteBotBlockedSender :execrows
+DELETE FROM bot_blocked_senders
+WHERE bot_id = $1 AND channel_identity_id = $2;
+
+-- name: GetBotBlockedSender :one
+SELECT bot_id, channel_identity_id, reason, notify, notified_at, created_by_user_id, created_at
+FROM bot_blocked_senders
+WHERE bot_id = $1 AND channel_identity_id = $2;
+
+-- name: MarkBotBlockedSenderNotified :execrows
+-- Claims the one-time block notice; affects no rows once it has been sent.
+UPDATE bot_blocked_senders
+SET notified_at = now()
+WHERE bot_id = $1
+  AND channel_identity_id = $2
+  AND notify = true
+  AND notified_at IS NULL;
+
+-- name: ListBotBlockedSenders :many
+SELECT
+  b.bot_id,
+  b.channel_identity_id,
+  b.reason,
+  b.notify,
+  b.notified_at,
+  b.created_by_user_id,
+  b.created_at,
+  ci.channel_type,
+  ci.channel_subject_id,
+  ci.display_name AS channel_identity_display_name,
+  ci.avatar_url AS channel_identity_avatar_url
+FROM bot_blocked_senders b
+LEFT JOIN channel_identities ci ON ci.id = b.channel_identity_id
+WHERE b.bot_id = $1
+ORDER BY b.created_at DESC;
@@ -1,6 +1,6 @@
 -- name: CreateBot :one
-INSERT INTO bots (owner_user_id, display_name, avatar_url, timezone, is_active, metadata, status)
-VALUES ($1, $2, $3, $4, $5, $6, $7)
+INSERT INTO bots (owner_user_id, display_name, avatar_url, timezone, is_active, metadata, status, chat_model_id, memory_provider_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, sqlc.narg(chat_model_id)::uuid, sqlc.narg(memory_provider_id)::uuid)
 RETURNING id, owner_user_id, display_name, avatar_url, timezone, is_active, status, language, reasoning_enabled, reasoning_effort, chat_model_id, search_provider_id, memory_provider_id, heartbeat_enabled, heartbeat_interval, heartbeat_prompt, metadata, created_at, updated_at;
 
 -- name: GetBotByID :one
@@ -14,6 +14,14 @@ FROM bots
 WHERE owner_user_id = $1
 ORDER BY created_at DESC;
 
+-- name: ListBotActivityByOwner :many
+SELECT
+  b.id,
+  (SELECT max(m.created_at) FROM bot_history_messages m WHERE m.bot_id = b.id)::timestamptz AS last_message_at,
+  (SELECT max(l.started_at) FROM schedule_logs l WHERE l.bot_id = b.id)::timestamptz AS last_schedule_run_at
+FROM bots b
+WHERE b.owner_user_id = $1;
+
 -- name: UpdateBotProfile :one
 UPDATE bots
 SET display_name = $2,
@@ -38,6 +46,35 @@ SET status = $2,
     updated_at = now()
 WHERE id = $1;
 
+-- name: MarkBotDeleting :exec
+UPDATE bots
+SET status = 'deleting',
+    delete_requested_at = now(),
+    updated_at = now()
+WHERE id = $1;
+
+-- name: GetBotDeleteState :one
+SELECT status, delete_requested_at
+FROM bots
+WHERE id = $1;
+
+-- name: RestoreDeletingBot :execrows
+UPDATE bots
+SET status = 'ready',
+    delete_requested_at = NULL,
+    updated_at = now()
+WHERE id = $1
+  AND status = 'deleting'
+  AND delete_requested_at > sqlc.arg(cutoff)::timestamptz;
+
+-- name: ListBotsPendingPurge :many
+SELECT id
+FROM bots
+WHERE status = 'deleting'
+  AND delete_requested_at IS NOT NULL
+  AND delete_requested_at <= sqlc.arg(cutoff)::timestamptz
+ORDER BY delete_requested_at ASC;
+
 -- name: DeleteBotByID :exec
 DELETE FROM bots WHERE id = $1;
 
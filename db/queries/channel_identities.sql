@@ -8,6 +8,16 @@ SELECT id, user_id, channel_type, channel_subject_id, display_name, avatar_url,
 FROM channel_identities
 WHERE id = $1;
 
+-- name: GetChannelIdentityWithUser :one
+SELECT
+  ci.id,
+  ci.display_name,
+  u.id AS linked_user_id,
+  u.display_name AS user_display_name
+FROM channel_identities ci
+LEFT JOIN users u ON u.id = ci.user_id
+WHERE ci.id = $1;
+
 -- name: GetChannelIdentityByIDForUpdate :one
 SELECT id, user_id, channel_type, channel_subject_id, display_name, avatar_url, metadata, created_at, updated_at
 FROM channel_identities
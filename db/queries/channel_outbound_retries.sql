@@ -0,0 +1,44 @@
+-- name: EnqueueChannelOutboundRetry :exec
+INSERT INTO bot_channel_outbound_retries (bot_id, channel_type, request, attempts, max_attempts, next_attempt_at, last_error)
+VALUES (
+  sqlc.arg(bot_id),
+  sqlc.arg(channel_type),
+  sqlc.arg(request),
+  sqlc.arg(attempts),
+  sqlc.arg(max_attempts),
+  sqlc.arg(next_attempt_at),
+  sqlc.arg(last_error)
+);
+
+-- name: ClaimDueChannelOutboundRetries :many
+UPDATE bot_channel_outbound_retries
+SET status = 'sending', updated_at = now()
+WHERE id IN (
+  SELECT r.id FROM bot_channel_outbound_retries r
+  WHERE r.next_attempt_at <= sqlc.arg(now)
+    AND (r.status = 'pending' OR (r.status = 'sending' AND r.updated_at < sqlc.arg(stale_before)))
+  ORDER BY r.next_attempt_at ASC, r.created_at ASC
+  LIMIT sqlc.arg(max_count)
+  FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: RescheduleChannelOutboundRetry :exec
+UPDATE bot_channel_outbound_retries
+SET status = 'pending',
+    attempts = sqlc.arg(attempts),
+    next_attempt_at = sqlc.arg(next_attempt_at),
+    last_error = sqlc.arg(last_error),
+    updated_at = now()
+WHERE id = sqlc.arg(id);
+
+-- name: FailChannelOutboundRetry :exec
+UPDATE bot_channel_outbound_retries
+SET status = 'failed',
+    attempts = sqlc.arg(attempts),
+    last_error = sqlc.arg(last_error),
+    updated_at = now()
+WHERE id = sqlc.arg(id);
+
+-- name: DeleteChannelOutboundRetry :exec
+DELETE FROM bot_channel_outbound_retries WHERE id = sqlc.arg(id);
@@ -24,6 +24,9 @@ RETURNING
   default_reply_target AS reply_target,
   active_session_id,
   metadata,
+  muted,
+  pinned,
+  annotations,
   created_at,
   updated_at;
 
@@ -40,6 +43,9 @@ SELECT
   default_reply_target AS reply_target,
   active_session_id,
   metadata,
+  muted,
+  pinned,
+  annotations,
   created_at,
   updated_at
 FROM bot_channel_routes
@@ -62,6 +68,9 @@ SELECT
   default_reply_target AS reply_target,
   active_session_id,
   metadata,
+  muted,
+  pinned,
+  annotations,
   created_at,
   updated_at
 FROM bot_channel_routes
@@ -80,6 +89,9 @@ SELECT
   default_reply_target AS reply_target,
   active_session_id,
   metadata,
+  muted,
+  pinned,
+  annotations,
   created_at,
   updated_at
 FROM bot_channel_routes
@@ -96,6 +108,21 @@ UPDATE bot_channel_routes
 SET metadata = sqlc.arg(metadata), updated_at = now()
 WHERE id = sqlc.arg(id);
 
+-- name: SetChatRouteMuted :exec
+UPDATE bot_channel_routes
+SET muted = sqlc.arg(muted), updated_at = now()
+WHERE id = sqlc.arg(id);
+
+-- name: SetChatRoutePinned :exec
+UPDATE bot_channel_routes
+SET pinned = sqlc.arg(pinned), updated_at = now()
+WHERE id = sqlc.arg(id);
+
+-- name: SetChatRouteAnnotations :exec
+UPDATE bot_channel_routes
+SET annotations = sqlc.arg(annotations), updated_at = now()
+WHERE id = sqlc.arg(id);
+
 -- name: SetRouteActiveSession :exec
 UPDATE bot_channel_routes
 SET active_session_id = sqlc.narg(active_session_id)::uuid, updated_at = now()
@@ -104,3 +131,28 @@ WHERE id = sqlc.arg(id);
 -- name: DeleteChatRoute :exec
 DELETE FROM bot_channel_routes
 WHERE id = $1;
+
+-- name: DeleteStaleChatRoutes :execrows
+-- Removes routes with no activity since the cutoff. Pinned and muted routes
+-- and routes carrying operator notes or tags are kept. Sessions of a removed
+-- route keep their history; their route_id is cleared by the foreign key.
+DELETE FROM bot_channel_routes r
+WHERE r.updated_at < sqlc.arg(cutoff)::timestamptz
+  AND r.pinned = false
+  AND r.muted = false
+  AND COALESCE(r.annotations->>'notes', '') = ''
+  AND COALESCE(NULLIF(r.annotations->'tags', 'null'::jsonb), '[]'::jsonb) = '[]'::jsonb
+  AND NOT EXISTS (
+    SELECT 1
+    FROM bot_sessions s
+    WHERE s.route_id = r.id
+      AND s.deleted_at IS NULL
+      AND s.updated_at >= sqlc.arg(cutoff)::timestamptz
+  )
+  AND NOT EXISTS (
+    SELECT 1
+    FROM bot_sessions s
+    JOIN bot_history_messages m ON m.session_id = s.id
+    WHERE s.route_id = r.id
+      AND m.created_at >= sqlc.arg(cutoff)::timestamptz
+  );
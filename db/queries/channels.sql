@@ -51,6 +51,7 @@ WHERE id = $1;
 SELECT id, bot_id, channel_type, credentials, external_identity, self_identity, routing, capabilities, disabled, verified_at, created_at, updated_at
 FROM bot_channel_configs
 WHERE channel_type = $1
+  AND bot_id NOT IN (SELECT id FROM bots WHERE status = 'deleting')
 ORDER BY created_at DESC;
 
 -- name: GetUserChannelBinding :one
@@ -0,0 +1,18 @@
+-- name: CreateDeliveryReceipt :one
+INSERT INTO bot_message_delivery_receipts (bot_id, message_id, channel_type, target, platform_message_id, status, error)
+VALUES (
+  sqlc.arg(bot_id),
+  sqlc.narg(message_id)::uuid,
+  sqlc.arg(channel_type),
+  sqlc.arg(target),
+  sqlc.arg(platform_message_id),
+  sqlc.arg(status),
+  sqlc.arg(error)
+)
+RETURNING *;
+
+-- name: ListDeliveryReceiptsBatch :many
+SELECT id, message_id, channel_type, target, platform_message_id, status, error, created_at
+FROM bot_message_delivery_receipts
+WHERE message_id = ANY(sqlc.arg(message_ids)::uuid[])
+ORDER BY message_id, created_at ASC;
@@ -25,3 +25,6 @@ DELETE FROM memory_providers WHERE id = $1;
 
 -- name: CountMemoryProvidersByDefault :one
 SELECT COUNT(*) FROM memory_providers WHERE is_default = true;
+
+-- name: ListBotIDsByMemoryProvider :many
+SELECT id FROM bots WHERE memory_provider_id = $1 ORDER BY created_at ASC;
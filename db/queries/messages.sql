@@ -12,7 +12,8 @@ INSERT INTO bot_history_messages (
   usage,
   model_id,
   event_id,
-  display_text
+  display_text,
+  created_at
 )
 VALUES (
   sqlc.arg(bot_id),
@@ -27,8 +28,81 @@ VALUES (
   sqlc.arg(usage),
   sqlc.narg(model_id)::uuid,
   sqlc.narg(event_id)::uuid,
-  sqlc.narg(display_text)::text
+  sqlc.narg(display_text)::text,
+  -- clock_timestamp keeps messages written in one transaction in order;
+  -- now() would give them all the transaction start time.
+  clock_timestamp()
 )
+ON CONFLICT DO NOTHING
+RETURNING
+  id,
+  bot_id,
+  session_id,
+  sender_channel_identity_id,
+  sender_account_user_id AS sender_user_id,
+  source_message_id AS external_message_id,
+  source_reply_to_message_id,
+  role,
+  content,
+  metadata,
+  usage,
+  event_id,
+  display_text,
+  created_at;
+
+-- name: GetMessageByExternalID :one
+-- Returns the message already stored for an external platform message in a
+-- session, which CreateMessage skips as a duplicate.
+SELECT
+  id,
+  bot_id,
+  session_id,
+  sender_channel_identity_id,
+  sender_account_user_id AS sender_user_id,
+  source_message_id AS external_message_id,
+  source_reply_to_message_id,
+  role,
+  content,
+  metadata,
+  usage,
+  event_id,
+  display_text,
+  created_at
+FROM bot_history_messages
+WHERE bot_id = sqlc.arg(bot_id)
+  AND session_id = sqlc.arg(session_id)
+  AND COALESCE(metadata->>'platform', '') = sqlc.arg(platform)::text
+  AND source_message_id = sqlc.arg(external_message_id)::text
+  AND role = sqlc.arg(role);
+
+-- name: GetMessageByID :one
+SELECT
+  id,
+  bot_id,
+  session_id,
+  sender_channel_identity_id,
+  sender_account_user_id AS sender_user_id,
+  source_message_id AS external_message_id,
+  source_reply_to_message_id,
+  role,
+  content,
+  metadata,
+  usage,
+  event_id,
+  display_text,
+  created_at
+FROM bot_history_messages
+WHERE id = sqlc.arg(id)
+  AND bot_id = sqlc.arg(bot_id);
+
+-- name: RedactMessage :one
+UPDATE bot_history_messages
+SET
+  content = sqlc.arg(content),
+  display_text = sqlc.narg(display_text)::text,
+  metadata = metadata || sqlc.arg(metadata)::jsonb
+WHERE id = sqlc.arg(id)
+  AND bot_id = sqlc.arg(bot_id)
 RETURNING
   id,
   bot_id,
@@ -319,6 +393,33 @@ WHERE bot_id = sqlc.arg(bot_id);
 DELETE FROM bot_history_messages
 WHERE session_id = sqlc.arg(session_id);
 
+-- name: ListBotsWithPassiveMessageLimit :many
+SELECT id, metadata
+FROM bots
+WHERE metadata->'features' ? 'passive_message_limit';
+
+-- name: PrunePassiveMessages :execrows
+DELETE FROM bot_history_messages
+WHERE id IN (
+  SELECT ranked.id
+  FROM (
+    SELECT
+      m.id,
+      ROW_NUMBER() OVER (PARTITION BY m.session_id ORDER BY m.created_at DESC, m.id DESC) AS position
+    FROM bot_history_messages m
+    WHERE m.bot_id = sqlc.arg(bot_id)
+      AND m.metadata->>'passive' = 'true'
+      AND NOT EXISTS (
+        SELECT 1
+        FROM bot_sessions s
+        JOIN bot_channel_routes r ON r.id = s.route_id
+        WHERE s.id = m.session_id
+          AND r.pinned
+      )
+  ) ranked
+  WHERE ranked.position > sqlc.arg(keep)::bigint
+);
+
 -- name: ListObservedConversationsByChannelIdentity :many
 WITH observed_routes AS (
   SELECT
@@ -1,23 +1,24 @@
 -- name: CreateSchedule :one
-INSERT INTO schedule (name, description, pattern, max_calls, enabled, command, bot_id)
-VALUES ($1, $2, $3, $4, $5, $6, $7)
-RETURNING id, name, description, pattern, max_calls, current_calls, created_at, updated_at, enabled, command, bot_id;
+INSERT INTO schedule (name, description, pattern, max_calls, enabled, command, bot_id, max_runtime_seconds, condition)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING id, name, description, pattern, max_calls, current_calls, created_at, updated_at, enabled, command, bot_id, max_runtime_seconds, condition, skipped_calls;
 
 -- name: GetScheduleByID :one
-SELECT id, name, description, pattern, max_calls, current_calls, created_at, updated_at, enabled, command, bot_id
+SELECT id, name, description, pattern, max_calls, current_calls, created_at, updated_at, enabled, command, bot_id, max_runtime_seconds, condition, skipped_calls
 FROM schedule
 WHERE id = $1;
 
 -- name: ListSchedulesByBot :many
-SELECT id, name, description, pattern, max_calls, current_calls, created_at, updated_at, enabled, command, bot_id
+SELECT id, name, description, pattern, max_calls, current_calls, created_at, updated_at, enabled, command, bot_id, max_runtime_seconds, condition, skipped_calls
 FROM schedule
 WHERE bot_id = $1
 ORDER BY created_at DESC;
 
 -- name: ListEnabledSchedules :many
-SELECT id, name, description, pattern, max_calls, current_calls, created_at, updated_at, enabled, command, bot_id
+SELECT id, name, description, pattern, max_calls, current_calls, created_at, updated_at, enabled, command, bot_id, max_runtime_seconds, condition, skipped_calls
 FROM schedule
 WHERE enabled = true
+  AND bot_id NOT IN (SELECT id FROM bots WHERE status = 'deleting')
 ORDER BY created_at DESC;
 
 -- name: UpdateSchedule :one
@@ -28,9 +29,11 @@ SET name = $2,
     max_calls = $5,
     enabled = $6,
     command = $7,
+    max_runtime_seconds = $8,
+    condition = $9,
     updated_at = now()
 WHERE id = $1
-RETURNING id, name, description, pattern, max_calls, current_calls, created_at, updated_at, enabled, command, bot_id;
+RETURNING id, name, description, pattern, max_calls, current_calls, created_at, updated_at, enabled, command, bot_id, max_runtime_seconds, condition, skipped_calls;
 
 -- name: DeleteSchedule :exec
 DELETE FROM schedule
@@ -45,5 +48,21 @@ SET current_calls = current_calls + 1,
     END,
     updated_at = now()
 WHERE id = $1
-RETURNING id, name, description, pattern, max_calls, current_calls, created_at, updated_at, enabled, command, bot_id;
+RETURNING id, name, description, pattern, max_calls, current_calls, created_at, updated_at, enabled, command, bot_id, max_runtime_seconds, condition, skipped_calls;
 
+-- name: IncrementScheduleSkips :exec
+UPDATE schedule
+SET skipped_calls = skipped_calls + 1,
+    updated_at = now()
+WHERE id = $1;
+
+-- name: CountBotUserMessagesSince :one
+-- Counts user messages in conversation sessions, leaving out the messages
+-- written by schedule and heartbeat runs themselves.
+SELECT count(*)
+FROM bot_history_messages m
+LEFT JOIN bot_sessions s ON s.id = m.session_id
+WHERE m.bot_id = sqlc.arg(bot_id)
+  AND m.role = 'user'
+  AND m.created_at > sqlc.arg(since)
+  AND (s.type IS NULL OR s.type IN ('chat', 'discuss'));
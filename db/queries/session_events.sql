@@ -24,3 +24,12 @@ ORDER BY received_at_ms ASC;
 -- name: CountSessionEvents :one
 SELECT COUNT(*) FROM bot_session_events
 WHERE session_id = $1;
+
+-- name: GetSessionEventData :one
+SELECT session_id, event_data FROM bot_session_events
+WHERE id = $1;
+
+-- name: UpdateSessionEventData :exec
+UPDATE bot_session_events
+SET event_data = $2
+WHERE id = $1;
@@ -21,7 +21,12 @@ SELECT
   tts_models.id AS tts_model_id,
   browser_contexts.id AS browser_context_id,
   bots.context_token_budget,
-  bots.persist_full_tool_results
+  bots.system_prompt_reserve,
+  bots.persist_full_tool_results,
+  bots.persist_reasoning,
+  bots.monthly_token_cap,
+  bots.monthly_cost_cap_usd,
+  bots.search_fallback_provider_ids
 FROM bots
 LEFT JOIN models AS chat_models ON chat_models.id = bots.chat_model_id
 LEFT JOIN models AS heartbeat_models ON heartbeat_models.id = bots.heartbeat_model_id
@@ -52,15 +57,20 @@ WITH updated AS (
       compaction_model_id = COALESCE(sqlc.narg(compaction_model_id)::uuid, bots.compaction_model_id),
       title_model_id = COALESCE(sqlc.narg(title_model_id)::uuid, bots.title_model_id),
       search_provider_id = COALESCE(sqlc.narg(search_provider_id)::uuid, bots.search_provider_id),
+      search_fallback_provider_ids = COALESCE(sqlc.narg(search_fallback_provider_ids)::uuid[], bots.search_fallback_provider_ids),
       memory_provider_id = COALESCE(sqlc.narg(memory_provider_id)::uuid, bots.memory_provider_id),
       image_model_id = COALESCE(sqlc.narg(image_model_id)::uuid, bots.image_model_id),
       tts_model_id = COALESCE(sqlc.narg(tts_model_id)::uuid, bots.tts_model_id),
       browser_context_id = COALESCE(sqlc.narg(browser_context_id)::uuid, bots.browser_context_id),
       context_token_budget = COALESCE(sqlc.narg(context_token_budget), bots.context_token_budget),
+      system_prompt_reserve = COALESCE(sqlc.narg(system_prompt_reserve), bots.system_prompt_reserve),
       persist_full_tool_results = sqlc.arg(persist_full_tool_results),
+      persist_reasoning = sqlc.arg(persist_reasoning),
+      monthly_token_cap = sqlc.arg(monthly_token_cap),
+      monthly_cost_cap_usd = sqlc.arg(monthly_cost_cap_usd),
       updated_at = now()
   WHERE bots.id = sqlc.arg(id)
-  RETURNING bots.id, bots.language, bots.reasoning_enabled, bots.reasoning_effort, bots.heartbeat_enabled, bots.heartbeat_interval, bots.heartbeat_prompt, bots.compaction_enabled, bots.compaction_threshold, bots.compaction_ratio, bots.timezone, bots.chat_model_id, bots.heartbeat_model_id, bots.compaction_model_id, bots.title_model_id, bots.image_model_id, bots.search_provider_id, bots.memory_provider_id, bots.tts_model_id, bots.browser_context_id, bots.context_token_budget, bots.persist_full_tool_results
+  RETURNING bots.id, bots.language, bots.reasoning_enabled, bots.reasoning_effort, bots.heartbeat_enabled, bots.heartbeat_interval, bots.heartbeat_prompt, bots.compaction_enabled, bots.compaction_threshold, bots.compaction_ratio, bots.timezone, bots.chat_model_id, bots.heartbeat_model_id, bots.compaction_model_id, bots.title_model_id, bots.image_model_id, bots.search_provider_id, bots.memory_provider_id, bots.tts_model_id, bots.browser_context_id, bots.context_token_budget, bots.system_prompt_reserve, bots.persist_full_tool_results, bots.persist_reasoning, bots.monthly_token_cap, bots.monthly_cost_cap_usd, bots.search_fallback_provider_ids
 )
 SELECT
   updated.id AS bot_id,
@@ -84,7 +94,12 @@ SELECT
   tts_models.id AS tts_model_id,
   browser_contexts.id AS browser_context_id,
   updated.context_token_budget,
-  updated.persist_full_tool_results
+  updated.system_prompt_reserve,
+  updated.persist_full_tool_results,
+  updated.persist_reasoning,
+  updated.monthly_token_cap,
+  updated.monthly_cost_cap_usd,
+  updated.search_fallback_provider_ids
 FROM updated
 LEFT JOIN models AS chat_models ON chat_models.id = updated.chat_model_id
 LEFT JOIN models AS heartbeat_models ON heartbeat_models.id = updated.heartbeat_model_id
@@ -113,10 +128,15 @@ SET language = 'auto',
     title_model_id = NULL,
     image_model_id = NULL,
     search_provider_id = NULL,
+    search_fallback_provider_ids = '{}',
     memory_provider_id = NULL,
     tts_model_id = NULL,
     browser_context_id = NULL,
     context_token_budget = NULL,
+    system_prompt_reserve = NULL,
     persist_full_tool_results = false,
+    persist_reasoning = false,
+    monthly_token_cap = 0,
+    monthly_cost_cap_usd = 0,
     updated_at = now()
 WHERE id = $1;
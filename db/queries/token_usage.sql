@@ -38,3 +38,43 @@ WHERE m.bot_id = sqlc.arg(bot_id)
   AND m.created_at < sqlc.arg(to_time)
 GROUP BY m.model_id, mo.model_id, mo.name, lp.name
 ORDER BY input_tokens DESC;
+
+-- name: GetTokenUsageReport :many
+-- Usage is attributed to the sender of the latest user message in the same
+-- session at or before the usage-bearing message. Rows without one (e.g.
+-- heartbeats and schedules) have a NULL user_id.
+SELECT
+  m.bot_id,
+  COALESCE(b.display_name, '')::text AS bot_name,
+  attr.user_id,
+  COALESCE(u.display_name, u.username, '')::text AS user_name,
+  date_trunc('day', m.created_at)::date AS day,
+  m.model_id,
+  COALESCE(mo.model_id, 'unknown') AS model_slug,
+  COALESCE(SUM((m.usage->>'inputTokens')::bigint), 0)::bigint AS input_tokens,
+  COALESCE(SUM((m.usage->>'outputTokens')::bigint), 0)::bigint AS output_tokens,
+  COALESCE(SUM((m.usage->'inputTokenDetails'->>'cacheReadTokens')::bigint), 0)::bigint AS cache_read_tokens,
+  COALESCE(SUM((m.usage->'inputTokenDetails'->>'cacheWriteTokens')::bigint), 0)::bigint AS cache_write_tokens,
+  COALESCE(SUM((m.usage->'outputTokenDetails'->>'reasoningTokens')::bigint), 0)::bigint AS reasoning_tokens
+FROM bot_history_messages m
+JOIN bots b ON b.id = m.bot_id
+LEFT JOIN LATERAL (
+  SELECT um.sender_account_user_id AS user_id
+  FROM bot_history_messages um
+  WHERE um.bot_id = m.bot_id
+    AND um.session_id IS NOT DISTINCT FROM m.session_id
+    AND um.role = 'user'
+    AND um.sender_account_user_id IS NOT NULL
+    AND um.created_at <= m.created_at
+  ORDER BY um.created_at DESC
+  LIMIT 1
+) attr ON true
+LEFT JOIN users u ON u.id = attr.user_id
+LEFT JOIN models mo ON mo.id = m.model_id
+WHERE m.usage IS NOT NULL
+  AND m.created_at >= sqlc.arg(from_time)
+  AND m.created_at < sqlc.arg(to_time)
+  AND (sqlc.narg(bot_id)::uuid IS NULL OR m.bot_id = sqlc.narg(bot_id)::uuid)
+  AND (sqlc.narg(user_id)::uuid IS NULL OR attr.user_id = sqlc.narg(user_id)::uuid)
+GROUP BY m.bot_id, b.display_name, attr.user_id, u.display_name, u.username, day, m.model_id, mo.model_id
+ORDER BY day, m.bot_id, attr.user_id, model_slug;
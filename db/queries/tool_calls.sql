@@ -0,0 +1,36 @@
+-- name: CreateMessageToolCall :exec
+INSERT INTO bot_message_tool_calls (bot_id, session_id, message_id, tool_call_id, tool_name, arguments, result, is_error, duration_ms)
+VALUES (
+  sqlc.arg(bot_id),
+  sqlc.narg(session_id)::uuid,
+  sqlc.arg(message_id),
+  sqlc.arg(tool_call_id),
+  sqlc.arg(tool_name),
+  sqlc.narg(arguments)::jsonb,
+  sqlc.narg(result)::jsonb,
+  sqlc.arg(is_error),
+  sqlc.narg(duration_ms)::bigint
+);
+
+-- name: ListMessageToolCalls :many
+SELECT id, bot_id, session_id, message_id, tool_call_id, tool_name, arguments, result, is_error, duration_ms, created_at
+FROM bot_message_tool_calls
+WHERE bot_id = sqlc.arg(bot_id)
+  AND (sqlc.narg(tool_name)::text IS NULL OR tool_name = sqlc.narg(tool_name)::text)
+  AND (sqlc.narg(session_id)::uuid IS NULL OR session_id = sqlc.narg(session_id)::uuid)
+  AND (sqlc.narg(before)::timestamptz IS NULL OR created_at < sqlc.narg(before)::timestamptz)
+ORDER BY created_at DESC
+LIMIT sqlc.arg(max_count);
+
+-- name: ListToolCallsByMessage :many
+SELECT id, arguments, result
+FROM bot_message_tool_calls
+WHERE message_id = sqlc.arg(message_id)
+ORDER BY created_at;
+
+-- name: RedactMessageToolCall :exec
+UPDATE bot_message_tool_calls
+SET
+  arguments = sqlc.narg(arguments)::jsonb,
+  result = sqlc.narg(result)::jsonb
+WHERE id = sqlc.arg(id);
@@ -0,0 +1,156 @@
+package acl
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/memohai/memoh/internal/db"
+	"github.com/memohai/memoh/internal/db/sqlc"
+)
+
+var (
+	ErrInvalidBlockedSender  = errors.New("a valid channel_identity_id is required")
+	ErrBlockedSenderNotFound = errors.New("blocked sender not found")
+)
+
+// ListBlockedSenders returns the bot's blocked senders, newest first.
+func (s *Service) ListBlockedSenders(ctx context.Context, botID string) ([]BlockedSender, error) {
+	if s == nil || s.queries == nil {
+		return nil, errors.New("acl service not configured")
+	}
+	pgBotID, err := db.ParseUUID(botID)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.queries.ListBotBlockedSenders(ctx, pgBotID)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]BlockedSender, 0, len(rows))
+	for _, row := range rows {
+		item := blockedSenderFromRow(sqlc.BotBlockedSender{
+			BotID:             row.BotID,
+			ChannelIdentityID: row.ChannelIdentityID,
+			Reason:            row.Reason,
+			Notify:            row.Notify,
+			NotifiedAt:        row.NotifiedAt,
+			CreatedAt:         row.CreatedAt,
+		})
+		item.ChannelType = strings.TrimSpace(row.ChannelType.String)
+		item.ChannelSubjectID = strings.TrimSpace(row.ChannelSubjectID.String)
+		item.ChannelIdentityDisplayName = strings.TrimSpace(row.ChannelIdentityDisplayName.String)
+		item.ChannelIdentityAvatarURL = strings.TrimSpace(row.ChannelIdentityAvatarUrl.String)
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// BlockSender adds a channel identity to the bot's block list, updating the
+// reason and notice flag if it is already blocked.
+func (s *Service) BlockSender(ctx context.Context, botID, createdByUserID string, req BlockSenderRequest) (BlockedSender, error) {
+	if s == nil || s.queries == nil {
+		return BlockedSender{}, errors.New("acl service not configured")
+	}
+	pgBotID, err := db.ParseUUID(botID)
+	if err != nil {
+		return BlockedSender{}, err
+	}
+	pgIdentityID, err := db.ParseUUID(strings.TrimSpace(req.ChannelIdentityID))
+	if err != nil {
+		return BlockedSender{}, ErrInvalidBlockedSender
+	}
+	row, err := s.queries.UpsertBotBlockedSender(ctx, sqlc.UpsertBotBlockedSenderParams{
+		BotID:             pgBotID,
+		ChannelIdentityID: pgIdentityID,
+		Reason:            strings.TrimSpace(req.Reason),
+		Notify:            req.Notify,
+		CreatedByUserID:   optionalUUID(createdByUserID),
+	})
+	if err != nil {
+		return BlockedSender{}, err
+	}
+	return blockedSenderFromRow(row), nil
+}
+
+// UnblockSender removes a channel identity from the bot's block list.
+func (s *Service) UnblockSender(ctx context.Context, botID, channelIdentityID string) error {
+	if s == nil || s.queries == nil {
+		return errors.New("acl service not configured")
+	}
+	pgBotID, err := db.ParseUUID(botID)
+	if err != nil {
+		return err
+	}
+	pgIdentityID, err := db.ParseUUID(strings.TrimSpace(channelIdentityID))
+	if err != nil {
+		return err
+	}
+	affected, err := s.queries.DeleteBotBlockedSender(ctx, sqlc.DeleteBotBlockedSenderParams{
+		BotID:             pgBotID,
+		ChannelIdentityID: pgIdentityID,
+	})
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrBlockedSenderNotFound
+	}
+	return nil
+}
+
+// CheckBlockedSender reports whether the channel identity is blocked by the bot,
+// and whether the caller should send the one-time block notice. The notice is
+// claimed atomically so concurrent messages send it at most once.
+func (s *Service) CheckBlockedSender(ctx context.Context, botID, channelIdentityID string) (bool, bool, error) {
+	if s == nil || s.queries == nil {
+		return false, false, errors.New("acl service not configured")
+	}
+	pgBotID, err := db.ParseUUID(botID)
+	if err != nil {
+		return false, false, err
+	}
+	pgIdentityID, err := db.ParseUUID(strings.TrimSpace(channelIdentityID))
+	if err != nil {
+		return false, false, err
+	}
+	row, err := s.queries.GetBotBlockedSender(ctx, sqlc.GetBotBlockedSenderParams{
+		BotID:             pgBotID,
+		ChannelIdentityID: pgIdentityID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, false, nil
+		}
+		return false, false, err
+	}
+	if !row.Notify || row.NotifiedAt.Valid {
+		return true, false, nil
+	}
+	claimed, err := s.queries.MarkBotBlockedSenderNotified(ctx, sqlc.MarkBotBlockedSenderNotifiedParams{
+		BotID:             pgBotID,
+		ChannelIdentityID: pgIdentityID,
+	})
+	if err != nil {
+		return true, false, err
+	}
+	return true, claimed > 0, nil
+}
+
+func blockedSenderFromRow(row sqlc.BotBlockedSender) BlockedSender {
+	item := BlockedSender{
+		BotID:             uuid.UUID(row.BotID.Bytes).String(),
+		ChannelIdentityID: uuid.UUID(row.ChannelIdentityID.Bytes).String(),
+		Reason:            row.Reason,
+		Notify:            row.Notify,
+		CreatedAt:         timeFromPg(row.CreatedAt),
+	}
+	if row.NotifiedAt.Valid {
+		notifiedAt := row.NotifiedAt.Time
+		item.NotifiedAt = &notifiedAt
+	}
+	return item
+}
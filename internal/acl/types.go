@@ -132,6 +132,32 @@ type ObservedConversationCandidateListResponse struct {
 	Items []ObservedConversationCandidate `json:"items"`
 }
 
+// BlockedSender is a channel identity whose inbound messages the bot drops.
+type BlockedSender struct {
+	BotID                      string     `json:"bot_id"`
+	ChannelIdentityID          string     `json:"channel_identity_id"`
+	Reason                     string     `json:"reason,omitempty"`
+	Notify                     bool       `json:"notify"`
+	NotifiedAt                 *time.Time `json:"notified_at,omitempty"`
+	ChannelType                string     `json:"channel_type,omitempty"`
+	ChannelSubjectID           string     `json:"channel_subject_id,omitempty"`
+	ChannelIdentityDisplayName string     `json:"channel_identity_display_name,omitempty"`
+	ChannelIdentityAvatarURL   string     `json:"channel_identity_avatar_url,omitempty"`
+	CreatedAt                  time.Time  `json:"created_at"`
+}
+
+type BlockedSenderListResponse struct {
+	Items []BlockedSender `json:"items"`
+}
+
+// BlockSenderRequest blocks a channel identity. When Notify is set the sender
+// is told once that they have been blocked.
+type BlockSenderRequest struct {
+	ChannelIdentityID string `json:"channel_identity_id"`
+	Reason            string `json:"reason,omitempty"`
+	Notify            bool   `json:"notify"`
+}
+
 func (s SourceScope) Normalize() SourceScope {
 	scope := SourceScope{
 		ConversationID: strings.TrimSpace(s.ConversationID),
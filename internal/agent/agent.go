@@ -101,14 +101,14 @@ func (a *Agent) runStream(ctx context.Context, cfg RunConfig, ch chan<- StreamEv
 	var toolLoopGuard *ToolLoopGuard
 	toolLoopAbortCallIDs := make(map[string]struct{})
 	if cfg.LoopDetection.Enabled {
-		textLoopGuard = NewTextLoopGuard(LoopDetectedStreakThreshold, LoopDetectedMinNewGramsPerChunk, SentialOptions{})
+		textLoopGuard = cfg.LoopDetection.newTextLoopGuard()
 		textLoopProbeBuffer = NewTextLoopProbeBuffer(LoopDetectedProbeChars, func(text string) {
 			result := textLoopGuard.Inspect(text)
 			if result.Abort {
 				a.logger.Warn("text loop detected, will abort")
 			}
 		})
-		toolLoopGuard = NewToolLoopGuard(ToolLoopRepeatThreshold, ToolLoopWarningsBeforeAbort)
+		toolLoopGuard = cfg.LoopDetection.newToolLoopGuard()
 	}
 
 	// Wrap tools with loop detection
@@ -202,7 +202,7 @@ func (a *Agent) runStream(ctx context.Context, cfg RunConfig, ch chan<- StreamEv
 			break
 		}
 		if !isRetryableStreamError(err) {
-			sendEvent(ctx, ch, StreamEvent{Type: EventError, Error: fmt.Sprintf("stream start: %v", err)})
+			sendEvent(ctx, ch, StreamEvent{Type: EventError, Error: fmt.Sprintf("stream start: %v", err), ErrorCode: providerErrorCode(err)})
 			return
 		}
 		a.logger.Warn("stream start failed, retrying",
@@ -292,6 +292,18 @@ func (a *Agent) runStream(ctx context.Context, cfg RunConfig, ch chan<- StreamEv
 				aborted = true
 			}
 
+		case *sdk.ToolInputStartPart:
+			// Stream argument text ahead of the complete tool call: the first
+			// event names the tool, later ones carry the argument deltas.
+			if !sendEvent(ctx, ch, StreamEvent{Type: EventToolInputDelta, ToolName: p.ToolName, ToolCallID: p.ID}) {
+				aborted = true
+			}
+
+		case *sdk.ToolInputDeltaPart:
+			if p.Delta != "" && !sendEvent(ctx, ch, StreamEvent{Type: EventToolInputDelta, ToolCallID: p.ID, Delta: p.Delta}) {
+				aborted = true
+			}
+
 		case *sdk.StreamToolCallPart:
 			if textLoopProbeBuffer != nil {
 				textLoopProbeBuffer.Flush()
@@ -306,11 +318,13 @@ func (a *Agent) runStream(ctx context.Context, cfg RunConfig, ch chan<- StreamEv
 			}
 
 		case *sdk.ToolProgressPart:
-			ch <- StreamEvent{
+			if !sendEvent(ctx, ch, StreamEvent{
 				Type:       EventToolCallProgress,
 				ToolName:   p.ToolName,
 				ToolCallID: p.ToolCallID,
 				Progress:   p.Content,
+			}) {
+				aborted = true
 			}
 
 		case *sdk.StreamToolResultPart:
@@ -367,7 +381,7 @@ func (a *Agent) runStream(ctx context.Context, cfg RunConfig, ch chan<- StreamEv
 
 		case *sdk.ErrorPart:
 			errMsg := p.Error.Error()
-			sendEvent(ctx, ch, StreamEvent{Type: EventError, Error: errMsg})
+			sendEvent(ctx, ch, StreamEvent{Type: EventError, Error: errMsg, ErrorCode: providerErrorCode(p.Error)})
 
 			// Mid-stream retry: if the error is retryable, attempt to continue
 			// the agent run from the accumulated state. This also handles
@@ -458,8 +472,8 @@ func (a *Agent) runGenerate(ctx context.Context, cfg RunConfig) (*GenerateResult
 	var textLoopGuard *TextLoopGuard
 	toolLoopAbortCallIDs := make(map[string]struct{})
 	if cfg.LoopDetection.Enabled {
-		toolLoopGuard = NewToolLoopGuard(ToolLoopRepeatThreshold, ToolLoopWarningsBeforeAbort)
-		textLoopGuard = NewTextLoopGuard(LoopDetectedStreakThreshold, LoopDetectedMinNewGramsPerChunk, SentialOptions{})
+		toolLoopGuard = cfg.LoopDetection.newToolLoopGuard()
+		textLoopGuard = cfg.LoopDetection.newTextLoopGuard()
 	}
 
 	if toolLoopGuard != nil {
@@ -621,6 +635,11 @@ func (a *Agent) assembleTools(ctx context.Context, cfg RunConfig, emitter tools.
 		Skills:             skillsMap,
 		TimezoneLocation:   cfg.Identity.TimezoneLocation,
 		Emitter:            emitter,
+		LoopDetection: tools.SpawnLoopConfig{
+			Enabled:         cfg.LoopDetection.Enabled,
+			WindowSize:      cfg.LoopDetection.WindowSize,
+			RepeatThreshold: cfg.LoopDetection.RepeatThreshold,
+		},
 	}
 
 	var allTools []sdk.Tool
@@ -877,6 +896,10 @@ func (a *Agent) runMidStreamRetry(
 		retryOpts := a.buildGenerateOptions(retryCfgCopy, sdkTools, prepareStep)
 
 		retryResult, retryErr := a.client.StreamText(ctx, retryOpts...)
+		if IsQuotaExhaustedError(retryErr) {
+			sendEvent(ctx, ch, StreamEvent{Type: EventError, Error: retryErr.Error(), ErrorCode: ErrorCodeQuotaExhausted})
+			return prevResult, true
+		}
 		if retryErr != nil {
 			a.logger.Warn("mid-stream retry failed to start",
 				slog.Int("attempt", attempt+1),
@@ -913,6 +936,14 @@ func (a *Agent) runMidStreamRetry(
 				if !sendEvent(ctx, ch, StreamEvent{Type: EventTextEnd}) {
 					aborted = true
 				}
+			case *sdk.ToolInputStartPart:
+				if !sendEvent(ctx, ch, StreamEvent{Type: EventToolInputDelta, ToolName: rp.ToolName, ToolCallID: rp.ID}) {
+					aborted = true
+				}
+			case *sdk.ToolInputDeltaPart:
+				if rp.Delta != "" && !sendEvent(ctx, ch, StreamEvent{Type: EventToolInputDelta, ToolCallID: rp.ID, Delta: rp.Delta}) {
+					aborted = true
+				}
 			case *sdk.StreamToolCallPart:
 				if textLoopProbeBuffer != nil {
 					textLoopProbeBuffer.Flush()
@@ -951,7 +982,7 @@ func (a *Agent) runMidStreamRetry(
 					aborted = true
 				}
 			case *sdk.ErrorPart:
-				sendEvent(ctx, ch, StreamEvent{Type: EventError, Error: rp.Error.Error()})
+				sendEvent(ctx, ch, StreamEvent{Type: EventError, Error: rp.Error.Error(), ErrorCode: providerErrorCode(rp.Error)})
 				aborted = true
 			case *sdk.AbortPart:
 				aborted = true
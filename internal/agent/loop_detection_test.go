@@ -0,0 +1,54 @@
+package agent
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestLoopDetectionConfigSerialization(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name   string
+		config LoopDetectionConfig
+		want   string
+	}{
+		{
+			name:   "tuned",
+			config: LoopDetectionConfig{Enabled: true, WindowSize: 500, RepeatThreshold: 3},
+			want:   `{"enabled":true,"window_size":500,"repeat_threshold":3}`,
+		},
+		{
+			name:   "unset tuning is omitted",
+			config: LoopDetectionConfig{Enabled: true},
+			want:   `{"enabled":true}`,
+		},
+	}
+	for _, tt := range tests {
+		got, err := json.Marshal(tt.config)
+		if err != nil {
+			t.Fatalf("%s: marshal: %v", tt.name, err)
+		}
+		if string(got) != tt.want {
+			t.Fatalf("%s: got %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestLoopDetectionConfigGuards(t *testing.T) {
+	t.Parallel()
+	defaults := LoopDetectionConfig{Enabled: true}
+	if got := defaults.newToolLoopGuard().repeatThreshold; got != ToolLoopRepeatThreshold {
+		t.Fatalf("expected default repeat threshold %d, got %d", ToolLoopRepeatThreshold, got)
+	}
+	if got := defaults.newTextLoopGuard().sential.windowSize; got != defaultWindowSize {
+		t.Fatalf("expected default window size %d, got %d", defaultWindowSize, got)
+	}
+
+	tuned := LoopDetectionConfig{Enabled: true, WindowSize: 500, RepeatThreshold: 3}
+	if got := tuned.newToolLoopGuard().repeatThreshold; got != 3 {
+		t.Fatalf("expected configured repeat threshold 3, got %d", got)
+	}
+	if got := tuned.newTextLoopGuard().sential.windowSize; got != 500 {
+		t.Fatalf("expected configured window size 500, got %d", got)
+	}
+}
@@ -128,6 +128,7 @@ func GenerateSystemPrompt(params SystemPromptParams) string {
 	)
 
 	skillsSection := buildSkillsSection(params.Skills)
+	conversationSection := buildConversationSection(params.ConversationNotes, params.ConversationTags)
 
 	fileSections := ""
 	var fileSectionsSb strings.Builder
@@ -142,12 +143,13 @@ func GenerateSystemPrompt(params SystemPromptParams) string {
 	tmpl := selectSystemTemplate(params.SessionType)
 
 	return render(tmpl, map[string]string{
-		"home":          home,
-		"currentTime":   now.Format(time.RFC3339),
-		"timezone":      timezoneName,
-		"basicTools":    strings.Join(basicTools, "\n"),
-		"skillsSection": skillsSection,
-		"fileSections":  fileSections,
+		"home":                home,
+		"currentTime":         now.Format(time.RFC3339),
+		"timezone":            timezoneName,
+		"basicTools":          strings.Join(basicTools, "\n"),
+		"skillsSection":       skillsSection,
+		"fileSections":        fileSections,
+		"conversationSection": conversationSection,
 	})
 }
 
@@ -159,6 +161,8 @@ type SystemPromptParams struct {
 	Now                time.Time
 	Timezone           string
 	SupportsImageInput bool
+	ConversationNotes  string
+	ConversationTags   []string
 }
 
 // GenerateSchedulePrompt builds the user message for a scheduled task trigger.
@@ -217,6 +221,24 @@ func buildSkillsSection(skills []SkillEntry) string {
 	return sb.String()
 }
 
+// buildConversationSection renders the operator notes and tags attached to
+// the current conversation, or "" when there are none.
+func buildConversationSection(notes string, tags []string) string {
+	notes = strings.TrimSpace(notes)
+	if notes == "" && len(tags) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString("## Current conversation\n\nNotes and tags your operator keeps about this conversation. Use them to personalize your replies.\n")
+	if len(tags) > 0 {
+		sb.WriteString("\nTags: " + strings.Join(tags, ", ") + "\n")
+	}
+	if notes != "" {
+		sb.WriteString("\n" + notes + "\n")
+	}
+	return sb.String()
+}
+
 func formatSystemFile(file SystemFile) string {
 	return fmt.Sprintf("## %s\n\n%s", file.Filename, file.Content)
 }
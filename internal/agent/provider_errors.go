@@ -0,0 +1,54 @@
+package agent
+
+import (
+	"errors"
+	"regexp"
+)
+
+// ErrorCodeQuotaExhausted marks an EventError caused by the provider
+// rejecting the request because the account is out of quota or credit.
+const ErrorCodeQuotaExhausted = "quota_exhausted"
+
+// err402Pattern matches the HTTP 402 Payment Required status in SDK errors.
+var err402Pattern = regexp.MustCompile(`api error 402($|[^0-9])`)
+
+// quotaExhaustedPattern matches the quota and billing errors returned by
+// common providers. Per-minute rate limits that merely mention a quota are
+// deliberately not matched; those are retried.
+var quotaExhaustedPattern = regexp.MustCompile(`(?i)` +
+	`insufficient_quota|exceeded your current quota|exceeded_current_quota` +
+	`|credit balance is too low|insufficient (credits?|balance|funds)` +
+	`|billing_hard_limit|out of credits|account is in arrears|余额不足|欠费`)
+
+// errorDetailer is implemented by SDK API errors that can append the raw
+// response body, which carries provider error codes the message may omit.
+type errorDetailer interface {
+	Detail() string
+}
+
+// IsQuotaExhaustedError reports whether err is a provider error saying the
+// account has run out of quota or credit. Such errors are not retried.
+func IsQuotaExhaustedError(err error) bool {
+	if err == nil {
+		return false
+	}
+	text := err.Error()
+	var detailed errorDetailer
+	if errors.As(err, &detailed) {
+		text += " " + detailed.Detail()
+	}
+	return isQuotaExhaustedText(text)
+}
+
+func isQuotaExhaustedText(text string) bool {
+	return err402Pattern.MatchString(text) || quotaExhaustedPattern.MatchString(text)
+}
+
+// providerErrorCode returns the StreamEvent error code for err, or "" when
+// the error has no specific code.
+func providerErrorCode(err error) string {
+	if IsQuotaExhaustedError(err) {
+		return ErrorCodeQuotaExhausted
+	}
+	return ""
+}
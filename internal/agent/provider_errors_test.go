@@ -0,0 +1,124 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	sdk "github.com/memohai/twilight-ai/sdk"
+
+	"github.com/memohai/memoh/internal/models"
+)
+
+func TestIsQuotaExhaustedText(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		text string
+		want bool
+	}{
+		{
+			name: "openai insufficient quota",
+			text: `api error 429: You exceeded your current quota, please check your plan and billing details. [body: {"error":{"message":"You exceeded your current quota, please check your plan and billing details.","type":"insufficient_quota","param":null,"code":"insufficient_quota"}}]`,
+			want: true,
+		},
+		{
+			name: "anthropic credit balance",
+			text: `api error 400: Your credit balance is too low to access the Anthropic API. Please go to Plans & Billing to upgrade or purchase credits.`,
+			want: true,
+		},
+		{
+			name: "openrouter payment required",
+			text: `api error 402: Insufficient credits. Add more using https://openrouter.ai/settings/credits`,
+			want: true,
+		},
+		{
+			name: "deepseek insufficient balance",
+			text: `api error 402: Insufficient Balance`,
+			want: true,
+		},
+		{
+			name: "zhipu balance",
+			text: `api error 429: 余额不足或无可用资源包,请充值。`,
+			want: true,
+		},
+		{
+			name: "plain rate limit",
+			text: `api error 429: Rate limit reached for gpt-4o in organization org-x on requests per min (RPM): Limit 500, Used 500, Requested 1.`,
+			want: false,
+		},
+		{
+			name: "per-minute quota metric",
+			text: `api error 429: Quota exceeded for quota metric 'Generate Content API requests per minute'`,
+			want: false,
+		},
+		{
+			name: "server error",
+			text: `api error 500: internal server error`,
+			want: false,
+		},
+		{
+			name: "402 inside a larger number",
+			text: `api error 4021: unknown`,
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := isQuotaExhaustedText(tt.text); got != tt.want {
+				t.Fatalf("isQuotaExhaustedText(%q) = %v, want %v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuotaExhaustedErrorIsNotRetried(t *testing.T) {
+	t.Parallel()
+	err := errors.New("api error 429: You exceeded your current quota, please check your plan and billing details")
+	if !IsQuotaExhaustedError(err) {
+		t.Fatal("expected quota error to be detected")
+	}
+	if isRetryableStreamError(err) {
+		t.Fatal("expected quota error not to be retried")
+	}
+	if providerErrorCode(fmt.Errorf("generate: %w", err)) != ErrorCodeQuotaExhausted {
+		t.Fatal("expected wrapped quota error to carry the quota error code")
+	}
+	if !isRetryableStreamError(errors.New("api error 429: Rate limit reached")) {
+		t.Fatal("expected plain rate limits to stay retryable")
+	}
+}
+
+// TestIsQuotaExhaustedErrorReadsResponseBody checks detection of a provider
+// error whose code is only present in the response body, as returned by the
+// SDK for an OpenAI-compatible endpoint.
+func TestIsQuotaExhaustedErrorReadsResponseBody(t *testing.T) {
+	t.Parallel()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = w.Write([]byte(`{"error":{"message":"Request rejected.","type":"insufficient_quota","code":"insufficient_quota"}}`))
+	}))
+	defer srv.Close()
+
+	model := models.NewSDKChatModel(models.SDKModelConfig{
+		ModelID:    "gpt-test",
+		ClientType: string(models.ClientTypeOpenAICompletions),
+		APIKey:     "sk-test",
+		BaseURL:    srv.URL,
+	})
+	_, err := sdk.GenerateTextResult(context.Background(),
+		sdk.WithModel(model),
+		sdk.WithMessages([]sdk.Message{sdk.UserMessage("hi")}),
+	)
+	if err == nil {
+		t.Fatal("expected provider error")
+	}
+	if !IsQuotaExhaustedError(err) {
+		t.Fatalf("expected quota error to be detected from the body, got: %v", err)
+	}
+}
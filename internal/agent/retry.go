@@ -49,6 +49,11 @@ func isRetryableStreamError(err error) bool {
 	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
 		return false
 	}
+	// Exhausted quota or credit will not recover on retry, even when the
+	// provider reports it as a 429.
+	if IsQuotaExhaustedError(err) {
+		return false
+	}
 	// Network-level errors (connection refused, timeout, DNS)
 	var netErr net.Error
 	if errors.As(err, &netErr) {
@@ -50,7 +50,9 @@ func (s *SpawnAdapter) Generate(ctx context.Context, cfg tools.SpawnRunConfig) (
 			IsSubagent:        cfg.Identity.IsSubagent,
 		},
 		LoopDetection: LoopDetectionConfig{
-			Enabled: cfg.LoopDetection.Enabled,
+			Enabled:         cfg.LoopDetection.Enabled,
+			WindowSize:      cfg.LoopDetection.WindowSize,
+			RepeatThreshold: cfg.LoopDetection.RepeatThreshold,
 		},
 	}
 
@@ -97,7 +99,9 @@ func (s *SpawnAdapter) GenerateWithWatchdog(ctx context.Context, cfg tools.Spawn
 			IsSubagent:        cfg.Identity.IsSubagent,
 		},
 		LoopDetection: LoopDetectionConfig{
-			Enabled: cfg.LoopDetection.Enabled,
+			Enabled:         cfg.LoopDetection.Enabled,
+			WindowSize:      cfg.LoopDetection.WindowSize,
+			RepeatThreshold: cfg.LoopDetection.RepeatThreshold,
 		},
 	}
 
@@ -14,6 +14,7 @@ const (
 	EventReasoningDelta   StreamEventType = "reasoning_delta"
 	EventReasoningEnd     StreamEventType = "reasoning_end"
 	EventToolCallStart    StreamEventType = "tool_call_start"
+	EventToolInputDelta   StreamEventType = "tool_input_delta"
 	EventToolCallProgress StreamEventType = "tool_call_progress"
 	EventToolCallEnd      StreamEventType = "tool_call_end"
 	EventAttachment       StreamEventType = "attachment_delta"
@@ -42,6 +43,7 @@ type StreamEvent struct {
 	Usage          json.RawMessage  `json:"usage,omitempty"`
 	Reasoning      []string         `json:"reasoning,omitempty"`
 	Error          string           `json:"error,omitempty"`
+	ErrorCode      string           `json:"errorCode,omitempty"`
 	Attempt        int              `json:"attempt,omitempty"`
 	MaxAttempt     int              `json:"maxAttempt,omitempty"`
 	RetryError     string           `json:"retryError,omitempty"`
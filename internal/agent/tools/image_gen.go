@@ -132,6 +132,7 @@ func (p *ImageGenProvider) execGenerateImage(ctx context.Context, session Sessio
 		ClientType: provider.ClientType,
 		APIKey:     creds.APIKey,
 		BaseURL:    providers.ProviderConfigString(provider, "base_url"),
+		Headers:    providers.ProviderConfigHeaders(provider),
 	})
 
 	userMsg := fmt.Sprintf("Generate an image with the following description. Size: %s\n\n%s", size, prompt)
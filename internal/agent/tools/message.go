@@ -101,6 +101,9 @@ func (p *MessageProvider) execSend(ctx context.Context, session SessionContext,
 		if sendResult.MessageID != "" {
 			resp["message_id"] = sendResult.MessageID
 		}
+		if sendResult.Queued {
+			resp["queued"] = true
+		}
 		return resp, nil
 	}
 	if result.Local && session.Emitter != nil {
@@ -127,6 +130,9 @@ func (p *MessageProvider) execSend(ctx context.Context, session SessionContext,
 	if result.MessageID != "" {
 		resp["message_id"] = result.MessageID
 	}
+	if result.Queued {
+		resp["queued"] = true
+	}
 	return resp, nil
 }
 
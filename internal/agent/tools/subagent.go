@@ -56,7 +56,9 @@ type SpawnIdentity struct {
 
 // SpawnLoopConfig mirrors agent.LoopDetectionConfig.
 type SpawnLoopConfig struct {
-	Enabled bool
+	Enabled         bool
+	WindowSize      int
+	RepeatThreshold int
 }
 
 // SpawnResult mirrors agent.GenerateResult.
@@ -429,7 +431,12 @@ func (p *SpawnProvider) runSubagentTask(
 			SessionToken:      parentSession.SessionToken,
 			IsSubagent:        true,
 		},
-		LoopDetection: SpawnLoopConfig{Enabled: true},
+		// Subagents always detect loops, tuned like their parent.
+		LoopDetection: SpawnLoopConfig{
+			Enabled:         true,
+			WindowSize:      parentSession.LoopDetection.WindowSize,
+			RepeatThreshold: parentSession.LoopDetection.RepeatThreshold,
+		},
 	}
 
 	var lastErr error
@@ -630,7 +637,7 @@ func (p *SpawnProvider) resolveModel(ctx context.Context, botID string) (*sdk.Mo
 		creds.APIKey,
 		creds.CodexAccountID,
 		providers.ProviderConfigString(provider, "base_url"),
-		nil,
+		models.WithHeaders(nil, providers.ProviderConfigHeaders(provider)),
 	)
 	return sdkModel, modelInfo.ID, nil
 }
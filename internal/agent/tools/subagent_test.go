@@ -0,0 +1,43 @@
+package tools
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+)
+
+// recordingSpawnAgent records the run config of each subagent run.
+type recordingSpawnAgent struct {
+	cfgs []SpawnRunConfig
+}
+
+func (a *recordingSpawnAgent) Generate(_ context.Context, cfg SpawnRunConfig) (*SpawnResult, error) {
+	a.cfgs = append(a.cfgs, cfg)
+	return &SpawnResult{Text: "done"}, nil
+}
+
+func (a *recordingSpawnAgent) GenerateWithWatchdog(ctx context.Context, cfg SpawnRunConfig, _ func()) (*SpawnResult, error) {
+	return a.Generate(ctx, cfg)
+}
+
+func TestRunSubagentTaskInheritsLoopDetectionTuning(t *testing.T) {
+	agent := &recordingSpawnAgent{}
+	p := NewSpawnProvider(slog.New(slog.DiscardHandler), nil, nil, nil, nil)
+	p.SetAgent(agent)
+	parent := SessionContext{
+		BotID:         "bot-1",
+		LoopDetection: SpawnLoopConfig{WindowSize: 500, RepeatThreshold: 7},
+	}
+
+	res := p.runSubagentTask(context.Background(), parent, nil, "", "", "summarize")
+	if !res.Success {
+		t.Fatalf("subagent failed: %s", res.Error)
+	}
+	if len(agent.cfgs) != 1 {
+		t.Fatalf("expected one run, got %d", len(agent.cfgs))
+	}
+	want := SpawnLoopConfig{Enabled: true, WindowSize: 500, RepeatThreshold: 7}
+	if got := agent.cfgs[0].LoopDetection; got != want {
+		t.Fatalf("loop detection = %+v, want %+v", got, want)
+	}
+}
@@ -148,6 +148,12 @@ func (p *TTSProvider) execSpeak(ctx context.Context, session SessionContext, arg
 		msg.Reply = &channel.ReplyRef{MessageID: replyTo}
 	}
 	if err := p.sender.Send(ctx, botID, channelType, channel.SendRequest{Target: target, Message: msg}); err != nil {
+		if errors.Is(err, channel.ErrOutboundQueued) {
+			return map[string]any{
+				"ok": true, "queued": true, "bot_id": botID, "platform": channelType.String(), "target": target,
+				"instruction": "Voice message could not be delivered yet and was queued for retry. Do not send it again.",
+			}, nil
+		}
 		return nil, err
 	}
 	return map[string]any{
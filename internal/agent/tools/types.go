@@ -81,6 +81,9 @@ type SessionContext struct {
 	Skills             map[string]SkillDetail
 	TimezoneLocation   *time.Location
 	Emitter            StreamEmitter
+	// LoopDetection is the parent run's loop detection tuning, passed on to
+	// subagents it spawns.
+	LoopDetection SpawnLoopConfig
 }
 
 // IsSameConversation reports whether the given platform+target pair refers to
@@ -59,8 +59,11 @@ func (p *WebProvider) Tools(_ context.Context, session SessionContext) ([]sdk.To
 			Parameters: map[string]any{
 				"type": "object",
 				"properties": map[string]any{
-					"query": map[string]any{"type": "string", "description": "Search query"},
-					"count": map[string]any{"type": "integer", "description": "Number of results, default 5"},
+					"query":       map[string]any{"type": "string", "description": "Search query"},
+					"count":       map[string]any{"type": "integer", "description": "Number of results, default 5"},
+					"locale":      map[string]any{"type": "string", "description": "Result language as a language tag, e.g. de or de-AT. Defaults to the bot language"},
+					"region":      map[string]any{"type": "string", "description": "Two-letter country code to localize results, e.g. AT"},
+					"safe_search": map[string]any{"type": "string", "enum": []string{safeSearchOff, safeSearchModerate, safeSearchStrict}, "description": "Safe search level; provider default when omitted"},
 				},
 				"required": []string{"query"},
 			},
@@ -84,11 +87,10 @@ func (p *WebProvider) execWebSearch(ctx context.Context, session SessionContext,
 	if searchProviderID == "" {
 		return nil, errors.New("search provider not configured for this bot")
 	}
-	provider, err := p.searchProviders.GetRawByID(ctx, searchProviderID)
+	providers, err := p.loadSearchProviders(ctx, searchProviderID, botSettings.SearchFallbackProviderIDs)
 	if err != nil {
 		return nil, err
 	}
-	registerSearchProviderSecrets(provider)
 
 	query := strings.TrimSpace(StringArg(args, "query"))
 	if query == "" {
@@ -103,25 +105,102 @@ func (p *WebProvider) execWebSearch(ctx context.Context, session SessionContext,
 	if count > 20 {
 		count = 20
 	}
-	return p.callSearch(ctx, provider.Provider, provider.Config, query, count)
+	loc, err := searchLocaleFromArgs(args, defaultSearchLocale(botSettings.Language))
+	if err != nil {
+		return nil, err
+	}
+	return searchWithFailover(ctx, p.logger, providers, query, count, loc, p.callSearch)
+}
+
+// loadSearchProviders returns the bot's search provider followed by its
+// enabled fallbacks, in order. Fallbacks that cannot be loaded are skipped.
+func (p *WebProvider) loadSearchProviders(ctx context.Context, primaryID string, fallbackIDs []string) ([]sqlc.SearchProvider, error) {
+	providers := make([]sqlc.SearchProvider, 0, 1+len(fallbackIDs))
+	primary, primaryErr := p.searchProviders.GetRawByID(ctx, primaryID)
+	if primaryErr == nil {
+		providers = append(providers, primary)
+	} else {
+		p.logger.Warn("load search provider failed", slog.String("search_provider_id", primaryID), slog.Any("error", primaryErr))
+	}
+	for _, id := range fallbackIDs {
+		id = strings.TrimSpace(id)
+		if id == "" || id == primaryID {
+			continue
+		}
+		provider, err := p.searchProviders.GetRawByID(ctx, id)
+		if err != nil {
+			p.logger.Warn("load fallback search provider failed", slog.String("search_provider_id", id), slog.Any("error", err))
+			continue
+		}
+		if !provider.Enable {
+			continue
+		}
+		providers = append(providers, provider)
+	}
+	if len(providers) == 0 {
+		return nil, primaryErr
+	}
+	for _, provider := range providers {
+		registerSearchProviderSecrets(provider)
+	}
+	return providers, nil
+}
+
+type searchFunc func(ctx context.Context, providerName string, configJSON []byte, query string, count int, loc searchLocale) (any, error)
+
+// searchWithFailover runs the query against each provider in order until
+// one succeeds. The result names the provider that served it.
+func searchWithFailover(ctx context.Context, logger *slog.Logger, providers []sqlc.SearchProvider, query string, count int, loc searchLocale, search searchFunc) (any, error) {
+	errs := make([]error, 0, len(providers))
+	for i, provider := range providers {
+		result, err := search(ctx, provider.Provider, provider.Config, query, count, loc)
+		if err == nil {
+			if i > 0 {
+				logger.Info("web search served by fallback provider",
+					slog.String("search_provider_id", provider.ID.String()),
+					slog.String("search_provider", provider.Name),
+					slog.Int("attempt", i+1),
+				)
+			}
+			if m, ok := result.(map[string]any); ok {
+				m["provider"] = provider.Name
+			}
+			return result, nil
+		}
+		logger.Warn("search provider failed",
+			slog.String("search_provider_id", provider.ID.String()),
+			slog.String("search_provider", provider.Name),
+			slog.Any("error", err),
+		)
+		errs = append(errs, fmt.Errorf("%s: %w", provider.Name, err))
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	if len(providers) == 1 {
+		return nil, errors.Unwrap(errs[0])
+	}
+	return nil, errors.Join(errs...)
 }
 
-func (*WebProvider) callSearch(ctx context.Context, providerName string, configJSON []byte, query string, count int) (any, error) {
+// callSearch runs one provider query. Locale parameters are passed to the
+// providers that support them and ignored by the rest.
+func (*WebProvider) callSearch(ctx context.Context, providerName string, configJSON []byte, query string, count int, loc searchLocale) (any, error) {
 	switch strings.TrimSpace(providerName) {
 	case string(searchproviders.ProviderBrave):
-		return callBraveSearch(ctx, configJSON, query, count)
+		return callBraveSearch(ctx, configJSON, query, count, loc)
 	case string(searchproviders.ProviderBing):
-		return callBingSearch(ctx, configJSON, query, count)
+		return callBingSearch(ctx, configJSON, query, count, loc)
 	case string(searchproviders.ProviderGoogle):
-		return callGoogleSearch(ctx, configJSON, query, count)
+		return callGoogleSearch(ctx, configJSON, query, count, loc)
 	case string(searchproviders.ProviderTavily):
 		return callTavilySearch(ctx, configJSON, query, count)
 	case string(searchproviders.ProviderSogou):
 		return callSogouSearch(ctx, configJSON, query, count)
 	case string(searchproviders.ProviderSerper):
-		return callSerperSearch(ctx, configJSON, query, count)
+		return callSerperSearch(ctx, configJSON, query, count, loc)
 	case string(searchproviders.ProviderSearXNG):
-		return callSearXNGSearch(ctx, configJSON, query, count)
+		return callSearXNGSearch(ctx, configJSON, query, count, loc)
 	case string(searchproviders.ProviderJina):
 		return callJinaSearch(ctx, configJSON, query, count)
 	case string(searchproviders.ProviderExa):
@@ -129,7 +208,7 @@ func (*WebProvider) callSearch(ctx context.Context, providerName string, configJ
 	case string(searchproviders.ProviderBocha):
 		return callBochaSearch(ctx, configJSON, query, count)
 	case string(searchproviders.ProviderDuckDuckGo):
-		return callDuckDuckGoSearch(ctx, configJSON, query, count)
+		return callDuckDuckGoSearch(ctx, configJSON, query, count, loc)
 	case string(searchproviders.ProviderYandex):
 		return callYandexSearch(ctx, configJSON, query, count)
 	default:
@@ -139,7 +218,7 @@ func (*WebProvider) callSearch(ctx context.Context, providerName string, configJ
 
 // ---- search provider implementations ----
 
-func callBraveSearch(ctx context.Context, configJSON []byte, query string, count int) (any, error) {
+func callBraveSearch(ctx context.Context, configJSON []byte, query string, count int, loc searchLocale) (any, error) {
 	cfg := parseSearchConfig(configJSON)
 	endpoint := strings.TrimRight(firstNonEmpty(stringValue(cfg["base_url"]), "https://api.search.brave.com/res/v1/web/search"), "/")
 	reqURL, err := url.Parse(endpoint)
@@ -149,6 +228,7 @@ func callBraveSearch(ctx context.Context, configJSON []byte, query string, count
 	params := reqURL.Query()
 	params.Set("q", query)
 	params.Set("count", strconv.Itoa(count))
+	loc.applyBrave(params)
 	reqURL.RawQuery = params.Encode()
 	timeout := parseSearchTimeout(configJSON, 15*time.Second)
 	client := &http.Client{Timeout: timeout}
@@ -187,13 +267,14 @@ func callBraveSearch(ctx context.Context, configJSON []byte, query string, count
 	}), nil
 }
 
-func callBingSearch(ctx context.Context, configJSON []byte, query string, count int) (any, error) {
+func callBingSearch(ctx context.Context, configJSON []byte, query string, count int, loc searchLocale) (any, error) {
 	cfg := parseSearchConfig(configJSON)
 	endpoint := strings.TrimRight(firstNonEmpty(stringValue(cfg["base_url"]), "https://api.bing.microsoft.com/v7.0/search"), "/")
 	reqURL, _ := url.Parse(endpoint)
 	params := reqURL.Query()
 	params.Set("q", query)
 	params.Set("count", strconv.Itoa(count))
+	loc.applyBing(params)
 	reqURL.RawQuery = params.Encode()
 	timeout := parseSearchTimeout(configJSON, 15*time.Second)
 	client := &http.Client{Timeout: timeout}
@@ -228,7 +309,7 @@ func callBingSearch(ctx context.Context, configJSON []byte, query string, count
 	return map[string]any{"query": query, "results": results}, nil
 }
 
-func callGoogleSearch(ctx context.Context, configJSON []byte, query string, count int) (any, error) {
+func callGoogleSearch(ctx context.Context, configJSON []byte, query string, count int, loc searchLocale) (any, error) {
 	cfg := parseSearchConfig(configJSON)
 	endpoint := strings.TrimRight(firstNonEmpty(stringValue(cfg["base_url"]), "https://customsearch.googleapis.com/customsearch/v1"), "/")
 	reqURL, _ := url.Parse(endpoint)
@@ -246,6 +327,7 @@ func callGoogleSearch(ctx context.Context, configJSON []byte, query string, coun
 	if apiKey := stringValue(cfg["api_key"]); apiKey != "" {
 		params.Set("key", apiKey)
 	}
+	loc.applyGoogle(params)
 	reqURL.RawQuery = params.Encode()
 	timeout := parseSearchTimeout(configJSON, 15*time.Second)
 	client := &http.Client{Timeout: timeout}
@@ -399,14 +481,16 @@ func callSogouSearch(ctx context.Context, configJSON []byte, query string, count
 	return map[string]any{"query": query, "results": results}, nil
 }
 
-func callSerperSearch(ctx context.Context, configJSON []byte, query string, count int) (any, error) {
+func callSerperSearch(ctx context.Context, configJSON []byte, query string, count int, loc searchLocale) (any, error) {
 	cfg := parseSearchConfig(configJSON)
 	endpoint := firstNonEmpty(stringValue(cfg["base_url"]), "https://google.serper.dev/search")
 	apiKey := stringValue(cfg["api_key"])
 	if apiKey == "" {
 		return nil, errors.New("serper API key is required")
 	}
-	payload, _ := json.Marshal(map[string]any{"q": query})
+	reqBody := map[string]any{"q": query}
+	loc.applySerper(reqBody)
+	payload, _ := json.Marshal(reqBody)
 	timeout := parseSearchTimeout(configJSON, 15*time.Second)
 	client := &http.Client{Timeout: timeout}
 	req, _ := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(payload))
@@ -442,7 +526,7 @@ func callSerperSearch(ctx context.Context, configJSON []byte, query string, coun
 	return map[string]any{"query": query, "results": results}, nil
 }
 
-func callSearXNGSearch(ctx context.Context, configJSON []byte, query string, count int) (any, error) {
+func callSearXNGSearch(ctx context.Context, configJSON []byte, query string, count int, loc searchLocale) (any, error) {
 	cfg := parseSearchConfig(configJSON)
 	baseURL := stringValue(cfg["base_url"])
 	if baseURL == "" {
@@ -462,6 +546,7 @@ func callSearXNGSearch(ctx context.Context, configJSON []byte, query string, cou
 	if cats := stringValue(cfg["categories"]); cats != "" {
 		params.Set("categories", cats)
 	}
+	loc.applySearXNG(params)
 	reqURL.RawQuery = params.Encode()
 	timeout := parseSearchTimeout(configJSON, 15*time.Second)
 	client := &http.Client{Timeout: timeout}
@@ -612,7 +697,7 @@ func callBochaSearch(ctx context.Context, configJSON []byte, query string, count
 	return map[string]any{"query": query, "results": results}, nil
 }
 
-func callDuckDuckGoSearch(ctx context.Context, configJSON []byte, query string, count int) (any, error) {
+func callDuckDuckGoSearch(ctx context.Context, configJSON []byte, query string, count int, loc searchLocale) (any, error) {
 	cfg := parseSearchConfig(configJSON)
 	endpoint := firstNonEmpty(stringValue(cfg["base_url"]), "https://html.duckduckgo.com/html/")
 	timeout := parseSearchTimeout(configJSON, 15*time.Second)
@@ -621,6 +706,7 @@ func callDuckDuckGoSearch(ctx context.Context, configJSON []byte, query string,
 	form.Set("q", query)
 	form.Set("b", "")
 	form.Set("kl", "")
+	loc.applyDuckDuckGo(form)
 	req, _ := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36")
@@ -0,0 +1,186 @@
+package tools
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Safe search levels accepted by web_search.
+const (
+	safeSearchOff      = "off"
+	safeSearchModerate = "moderate"
+	safeSearchStrict   = "strict"
+)
+
+// searchLocale narrows web search results to a language, a country and a
+// safe search level. Empty fields leave the provider defaults in place.
+type searchLocale struct {
+	// Language is a lowercase ISO 639 code such as "de".
+	Language string
+	// Region is an uppercase ISO 3166-1 alpha-2 code such as "AT".
+	Region string
+	// SafeSearch is one of the safeSearch constants.
+	SafeSearch string
+}
+
+// defaultSearchLocale derives the search locale from the bot language
+// setting. Values that are not a language tag, such as "auto" or a language
+// name, give no default.
+func defaultSearchLocale(botLanguage string) searchLocale {
+	language, region, err := parseLanguageTag(botLanguage)
+	if err != nil {
+		return searchLocale{}
+	}
+	return searchLocale{Language: language, Region: region}
+}
+
+// searchLocaleFromArgs applies the locale, region and safe_search tool
+// arguments on top of defaults.
+func searchLocaleFromArgs(args map[string]any, defaults searchLocale) (searchLocale, error) {
+	loc := defaults
+	if raw := strings.TrimSpace(StringArg(args, "locale")); raw != "" {
+		language, region, err := parseLanguageTag(raw)
+		if err != nil {
+			return searchLocale{}, fmt.Errorf("invalid locale %q", raw)
+		}
+		loc.Language = language
+		// A bare language drops a region that only came with the default.
+		loc.Region = region
+	}
+	if raw := strings.TrimSpace(StringArg(args, "region")); raw != "" {
+		if !isASCIILetters(raw, 2, 2) {
+			return searchLocale{}, fmt.Errorf("invalid region %q", raw)
+		}
+		loc.Region = strings.ToUpper(raw)
+	}
+	if raw := strings.TrimSpace(StringArg(args, "safe_search")); raw != "" {
+		switch value := strings.ToLower(raw); value {
+		case safeSearchOff, safeSearchModerate, safeSearchStrict:
+			loc.SafeSearch = value
+		default:
+			return searchLocale{}, fmt.Errorf("invalid safe_search %q", raw)
+		}
+	}
+	return loc, nil
+}
+
+// parseLanguageTag accepts "de", "de-AT" and "de_AT".
+func parseLanguageTag(tag string) (string, string, error) {
+	parts := strings.FieldsFunc(strings.TrimSpace(tag), func(r rune) bool { return r == '-' || r == '_' })
+	if len(parts) == 0 || len(parts) > 2 || !isASCIILetters(parts[0], 2, 3) {
+		return "", "", fmt.Errorf("invalid language tag %q", tag)
+	}
+	language := strings.ToLower(parts[0])
+	if len(parts) == 1 {
+		return language, "", nil
+	}
+	if !isASCIILetters(parts[1], 2, 2) {
+		return "", "", fmt.Errorf("invalid language tag %q", tag)
+	}
+	return language, strings.ToUpper(parts[1]), nil
+}
+
+func isASCIILetters(value string, minLen, maxLen int) bool {
+	if len(value) < minLen || len(value) > maxLen {
+		return false
+	}
+	for _, r := range value {
+		if (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') {
+			return false
+		}
+	}
+	return true
+}
+
+// tag returns the locale as a BCP 47 tag, e.g. "de-AT".
+func (l searchLocale) tag() string {
+	if l.Language == "" || l.Region == "" {
+		return l.Language
+	}
+	return l.Language + "-" + l.Region
+}
+
+func (l searchLocale) applyBrave(params url.Values) {
+	if l.Language != "" {
+		params.Set("search_lang", l.Language)
+	}
+	if l.Region != "" {
+		params.Set("country", l.Region)
+	}
+	if l.SafeSearch != "" {
+		params.Set("safesearch", l.SafeSearch)
+	}
+}
+
+func (l searchLocale) applyBing(params url.Values) {
+	if l.Language != "" {
+		params.Set("setLang", l.Language)
+	}
+	if l.Region != "" {
+		params.Set("cc", l.Region)
+	}
+	switch l.SafeSearch {
+	case safeSearchOff:
+		params.Set("safeSearch", "Off")
+	case safeSearchModerate:
+		params.Set("safeSearch", "Moderate")
+	case safeSearchStrict:
+		params.Set("safeSearch", "Strict")
+	}
+}
+
+func (l searchLocale) applyGoogle(params url.Values) {
+	if l.Language != "" {
+		params.Set("hl", l.Language)
+		params.Set("lr", "lang_"+l.Language)
+	}
+	if l.Region != "" {
+		params.Set("gl", strings.ToLower(l.Region))
+	}
+	// Google only distinguishes filtered from unfiltered results.
+	switch l.SafeSearch {
+	case safeSearchOff:
+		params.Set("safe", "off")
+	case safeSearchStrict:
+		params.Set("safe", "active")
+	}
+}
+
+func (l searchLocale) applySerper(payload map[string]any) {
+	if l.Language != "" {
+		payload["hl"] = l.Language
+	}
+	if l.Region != "" {
+		payload["gl"] = strings.ToLower(l.Region)
+	}
+}
+
+func (l searchLocale) applySearXNG(params url.Values) {
+	if tag := l.tag(); tag != "" {
+		params.Set("language", tag)
+	}
+	switch l.SafeSearch {
+	case safeSearchOff:
+		params.Set("safesearch", "0")
+	case safeSearchModerate:
+		params.Set("safesearch", "1")
+	case safeSearchStrict:
+		params.Set("safesearch", "2")
+	}
+}
+
+func (l searchLocale) applyDuckDuckGo(form url.Values) {
+	// DuckDuckGo regions pair a country with a language, e.g. "at-de".
+	if l.Language != "" && l.Region != "" {
+		form.Set("kl", strings.ToLower(l.Region)+"-"+l.Language)
+	}
+	switch l.SafeSearch {
+	case safeSearchOff:
+		form.Set("kp", "-2")
+	case safeSearchModerate:
+		form.Set("kp", "-1")
+	case safeSearchStrict:
+		form.Set("kp", "1")
+	}
+}
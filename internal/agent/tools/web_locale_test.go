@@ -0,0 +1,171 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+)
+
+func TestSearchLocaleFromArgs(t *testing.T) {
+	t.Parallel()
+
+	defaults := defaultSearchLocale("de-AT")
+	tests := []struct {
+		name    string
+		args    map[string]any
+		want    searchLocale
+		wantErr bool
+	}{
+		{name: "bot defaults", args: map[string]any{}, want: searchLocale{Language: "de", Region: "AT"}},
+		{name: "locale replaces default region", args: map[string]any{"locale": "fr"}, want: searchLocale{Language: "fr"}},
+		{name: "locale with region", args: map[string]any{"locale": "en_gb"}, want: searchLocale{Language: "en", Region: "GB"}},
+		{name: "region override", args: map[string]any{"locale": "en", "region": "ca"}, want: searchLocale{Language: "en", Region: "CA"}},
+		{name: "safe search", args: map[string]any{"safe_search": "Strict"}, want: searchLocale{Language: "de", Region: "AT", SafeSearch: safeSearchStrict}},
+		{name: "invalid locale", args: map[string]any{"locale": "english"}, wantErr: true},
+		{name: "invalid region", args: map[string]any{"region": "USA"}, wantErr: true},
+		{name: "invalid safe search", args: map[string]any{"safe_search": "medium"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := searchLocaleFromArgs(tt.args, defaults)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("searchLocaleFromArgs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Fatalf("searchLocaleFromArgs() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDefaultSearchLocaleIgnoresNonTags(t *testing.T) {
+	t.Parallel()
+
+	for _, language := range []string{"", "auto", "English", "中文"} {
+		if got := defaultSearchLocale(language); got != (searchLocale{}) {
+			t.Fatalf("defaultSearchLocale(%q) = %+v, want empty", language, got)
+		}
+	}
+	if got := defaultSearchLocale("ja"); got != (searchLocale{Language: "ja"}) {
+		t.Fatalf("defaultSearchLocale(ja) = %+v", got)
+	}
+}
+
+// captureSearchRequest serves an empty result and returns the query string
+// (or form) and JSON body of the last request.
+func captureSearchRequest(t *testing.T, response string) (*httptest.Server, func() (url.Values, map[string]any)) {
+	t.Helper()
+	var (
+		mu     sync.Mutex
+		params url.Values
+		body   map[string]any
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		params = r.URL.Query()
+		if r.Header.Get("Content-Type") == "application/x-www-form-urlencoded" {
+			_ = r.ParseForm()
+			params = r.PostForm
+		} else if raw, _ := io.ReadAll(r.Body); len(raw) > 0 {
+			_ = json.Unmarshal(raw, &body)
+		}
+		_, _ = w.Write([]byte(response))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, func() (url.Values, map[string]any) {
+		mu.Lock()
+		defer mu.Unlock()
+		return params, body
+	}
+}
+
+func TestSearchLocaleReachesProviderRequests(t *testing.T) {
+	t.Parallel()
+
+	loc := searchLocale{Language: "de", Region: "AT", SafeSearch: safeSearchStrict}
+	tests := []struct {
+		provider   string
+		response   string
+		config     string
+		wantParams map[string]string
+		wantBody   map[string]any
+	}{
+		{
+			provider:   "brave",
+			response:   `{}`,
+			wantParams: map[string]string{"search_lang": "de", "country": "AT", "safesearch": "strict"},
+		},
+		{
+			provider:   "bing",
+			response:   `{}`,
+			wantParams: map[string]string{"setLang": "de", "cc": "AT", "safeSearch": "Strict"},
+		},
+		{
+			provider:   "google",
+			response:   `{}`,
+			config:     `"cx":"engine",`,
+			wantParams: map[string]string{"hl": "de", "lr": "lang_de", "gl": "at", "safe": "active"},
+		},
+		{
+			provider:   "searxng",
+			response:   `{}`,
+			config:     `"language":"en","safesearch":"0",`,
+			wantParams: map[string]string{"language": "de-AT", "safesearch": "2"},
+		},
+		{
+			provider:   "duckduckgo",
+			response:   ``,
+			wantParams: map[string]string{"kl": "at-de", "kp": "1"},
+		},
+		{
+			provider: "serper",
+			response: `{}`,
+			config:   `"api_key":"key",`,
+			wantBody: map[string]any{"hl": "de", "gl": "at"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.provider, func(t *testing.T) {
+			t.Parallel()
+			srv, captured := captureSearchRequest(t, tt.response)
+			cfg := []byte(`{` + tt.config + `"base_url":"` + srv.URL + `"}`)
+			if _, err := (&WebProvider{}).callSearch(context.Background(), tt.provider, cfg, "wetter", 5, loc); err != nil {
+				t.Fatalf("callSearch: %v", err)
+			}
+			params, body := captured()
+			for key, want := range tt.wantParams {
+				if got := params.Get(key); got != want {
+					t.Errorf("param %s = %q, want %q", key, got, want)
+				}
+			}
+			for key, want := range tt.wantBody {
+				if got := body[key]; got != want {
+					t.Errorf("body %s = %v, want %v", key, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestSearchLocaleOmittedWhenUnset(t *testing.T) {
+	t.Parallel()
+
+	srv, captured := captureSearchRequest(t, `{}`)
+	cfg := []byte(`{"base_url":"` + srv.URL + `"}`)
+	if _, err := callBraveSearch(context.Background(), cfg, "weather", 5, searchLocale{}); err != nil {
+		t.Fatalf("callBraveSearch: %v", err)
+	}
+	params, _ := captured()
+	for _, key := range []string{"search_lang", "country", "safesearch"} {
+		if params.Has(key) {
+			t.Errorf("expected %s to be omitted, got %q", key, params.Get(key))
+		}
+	}
+}
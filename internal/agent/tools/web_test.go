@@ -0,0 +1,129 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/memohai/memoh/internal/db/sqlc"
+)
+
+func searxngProvider(name, baseURL, timeoutSeconds string) sqlc.SearchProvider {
+	cfg := `{"base_url":"` + baseURL + `"`
+	if timeoutSeconds != "" {
+		cfg += `,"timeout_seconds":` + timeoutSeconds
+	}
+	cfg += `}`
+	return sqlc.SearchProvider{Name: name, Provider: "searxng", Config: []byte(cfg), Enable: true}
+}
+
+func TestSearchWithFailoverUsesSecondaryWhenPrimaryFails(t *testing.T) {
+	t.Parallel()
+
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "upstream down", http.StatusBadGateway)
+	}))
+	defer primary.Close()
+	secondary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("q") != "memoh" {
+			t.Errorf("unexpected query %q", r.URL.Query().Get("q"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"results":[{"title":"Memoh","url":"https://example.com","content":"hit","score":1}]}`))
+	}))
+	defer secondary.Close()
+
+	wp := &WebProvider{}
+	providers := []sqlc.SearchProvider{
+		searxngProvider("primary", primary.URL, ""),
+		searxngProvider("secondary", secondary.URL, ""),
+	}
+	result, err := searchWithFailover(context.Background(), slog.Default(), providers, "memoh", 5, searchLocale{}, wp.callSearch)
+	if err != nil {
+		t.Fatalf("searchWithFailover: %v", err)
+	}
+	got, ok := result.(map[string]any)
+	if !ok {
+		t.Fatalf("unexpected result type %T", result)
+	}
+	if got["provider"] != "secondary" {
+		t.Fatalf("expected secondary to serve the query, got %v", got["provider"])
+	}
+	results, _ := got["results"].([]map[string]any)
+	if len(results) != 1 || results[0]["title"] != "Memoh" {
+		t.Fatalf("unexpected results %v", got["results"])
+	}
+}
+
+func TestSearchWithFailoverSkipsTimedOutProvider(t *testing.T) {
+	t.Parallel()
+
+	release := make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer slow.Close()
+	defer close(release)
+
+	var calls []string
+	search := func(ctx context.Context, _ string, configJSON []byte, query string, count int, loc searchLocale) (any, error) {
+		calls = append(calls, string(configJSON))
+		if strings.Contains(string(configJSON), slow.URL) {
+			return callSearXNGSearch(ctx, configJSON, query, count, loc)
+		}
+		return map[string]any{"query": query, "results": []map[string]any{}}, nil
+	}
+	providers := []sqlc.SearchProvider{
+		searxngProvider("slow", slow.URL, "0.05"),
+		searxngProvider("backup", "http://backup.invalid", ""),
+	}
+
+	start := time.Now()
+	result, err := searchWithFailover(context.Background(), slog.Default(), providers, "memoh", 5, searchLocale{}, search)
+	if err != nil {
+		t.Fatalf("searchWithFailover: %v", err)
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Fatal("expected the slow provider to time out quickly")
+	}
+	if len(calls) != 2 {
+		t.Fatalf("expected both providers to be tried, got %d calls", len(calls))
+	}
+	if got := result.(map[string]any)["provider"]; got != "backup" {
+		t.Fatalf("expected backup to serve the query, got %v", got)
+	}
+}
+
+func TestSearchWithFailoverReturnsAllErrors(t *testing.T) {
+	t.Parallel()
+
+	errPrimary := errors.New("primary down")
+	errSecondary := errors.New("secondary down")
+	search := func(_ context.Context, _ string, configJSON []byte, _ string, _ int, _ searchLocale) (any, error) {
+		if strings.Contains(string(configJSON), "primary") {
+			return nil, errPrimary
+		}
+		return nil, errSecondary
+	}
+	providers := []sqlc.SearchProvider{
+		searxngProvider("primary", "http://primary.invalid", ""),
+		searxngProvider("secondary", "http://secondary.invalid", ""),
+	}
+	_, err := searchWithFailover(context.Background(), slog.Default(), providers, "memoh", 5, searchLocale{}, search)
+	if !errors.Is(err, errPrimary) || !errors.Is(err, errSecondary) {
+		t.Fatalf("expected both provider errors, got %v", err)
+	}
+
+	_, err = searchWithFailover(context.Background(), slog.Default(), providers[:1], "memoh", 5, searchLocale{}, search)
+	if !errors.Is(err, errPrimary) || err.Error() != errPrimary.Error() {
+		t.Fatalf("expected the single provider error unchanged, got %v", err)
+	}
+}
@@ -19,6 +19,8 @@ type SessionContext struct {
 	CurrentPlatform   string
 	ReplyTarget       string
 	ConversationType  string
+	ConversationNotes string
+	ConversationTags  []string
 	Timezone          string
 	TimezoneLocation  *time.Location
 	SessionToken      string //nolint:gosec // carries session credential material at runtime
@@ -43,9 +45,24 @@ type Schedule struct {
 	Command     string `json:"command"`
 }
 
-// LoopDetectionConfig controls loop detection behavior.
+// LoopDetectionConfig controls loop detection behavior. Zero tuning values
+// fall back to the built-in defaults.
 type LoopDetectionConfig struct {
-	Enabled bool
+	Enabled bool `json:"enabled"`
+	// WindowSize is the number of recent characters the text loop detector
+	// compares new output against.
+	WindowSize int `json:"window_size,omitempty"`
+	// RepeatThreshold is the number of identical tool calls tolerated before
+	// the tool loop detector warns and then aborts.
+	RepeatThreshold int `json:"repeat_threshold,omitempty"`
+}
+
+func (c LoopDetectionConfig) newTextLoopGuard() *TextLoopGuard {
+	return NewTextLoopGuard(LoopDetectedStreakThreshold, LoopDetectedMinNewGramsPerChunk, SentialOptions{WindowSize: c.WindowSize})
+}
+
+func (c LoopDetectionConfig) newToolLoopGuard() *ToolLoopGuard {
+	return NewToolLoopGuard(c.RepeatThreshold, ToolLoopWarningsBeforeAbort)
 }
 
 // InjectMessage carries a user message to be injected into a running agent
@@ -0,0 +1,197 @@
+package bots
+
+import (
+	"math"
+	"strings"
+)
+
+// Features holds the per-bot feature flags stored under "features" in the
+// bot metadata. Flags that are missing or malformed keep their defaults.
+type Features struct {
+	LoopDetection LoopDetectionFeature
+	// MaxToolRounds caps the tool calls completed in one turn. Zero means no cap.
+	MaxToolRounds int
+	// StripHistoryHeaders keeps the XML message header out of stored and
+	// replayed user messages; only the current query is sent with it.
+	StripHistoryHeaders bool
+	// MemoryPriming adds the bot's most recent memories to the memory
+	// context on the first turn of each session.
+	MemoryPriming bool
+	// PassiveMessageLimit caps the passively synced group messages kept per
+	// session; older ones are pruned in the background. Zero keeps them all.
+	PassiveMessageLimit int
+	// HistoryTrim selects how replayed history is trimmed to fit the context.
+	HistoryTrim HistoryTrimFeature
+	// HistorySystemMessages is one of the HistorySystem constants and
+	// controls how stored system and developer messages are replayed; empty
+	// means HistorySystemPin.
+	HistorySystemMessages string
+	// ModelExperiment splits turns between chat models by weight when no
+	// model is chosen by the request or chat. Nil means no experiment.
+	ModelExperiment []ModelVariant
+}
+
+// ModelVariant is one arm of a model experiment. Model is a model UUID or
+// model_id; Weight is its relative share of turns.
+type ModelVariant struct {
+	Model  string
+	Weight int
+}
+
+// Handling of stored system and developer messages in replayed history.
+// HistorySystemPin keeps only the most recent one, ahead of the trimmed
+// history, and HistorySystemExclude leaves them all out.
+const (
+	HistorySystemPin     = "pin"
+	HistorySystemExclude = "exclude"
+)
+
+// History trimming strategies. HistoryTrimTokens trims by the estimated
+// token budget only, HistoryTrimCount keeps at most MaxMessages messages and
+// HistoryTrimCombined applies whichever of the two cuts more.
+const (
+	HistoryTrimTokens   = "tokens"
+	HistoryTrimCount    = "count"
+	HistoryTrimCombined = "combined"
+)
+
+// HistoryTrimFeature configures history trimming. The message cap guards
+// against overflow when the token budget is unset or token estimates are
+// unreliable.
+type HistoryTrimFeature struct {
+	// Strategy is one of the HistoryTrim constants; empty means
+	// HistoryTrimTokens.
+	Strategy string
+	// MaxMessages is the message cap used by the count and combined
+	// strategies. Zero means no cap.
+	MaxMessages int
+}
+
+// LoopDetectionFeature controls detection of repeated text and tool-call
+// loops. Zero tuning values leave the agent defaults in place.
+type LoopDetectionFeature struct {
+	Enabled         bool
+	WindowSize      int
+	RepeatThreshold int
+}
+
+// Accepted ranges for the loop detection tuning parameters, the tool round
+// cap, the passive message limit, the history message cap and model
+// experiment weights. Values outside these ranges are ignored.
+const (
+	MinLoopDetectionWindowSize      = 100
+	MaxLoopDetectionWindowSize      = 10000
+	MinLoopDetectionRepeatThreshold = 2
+	MaxLoopDetectionRepeatThreshold = 50
+	MinMaxToolRounds                = 1
+	MaxMaxToolRounds                = 1000
+	MinPassiveMessageLimit          = 1
+	MaxPassiveMessageLimit          = 100000
+	MinHistoryMaxMessages           = 1
+	MaxHistoryMaxMessages           = 10000
+	MinModelVariantWeight           = 1
+	MaxModelVariantWeight           = 1000
+)
+
+// DefaultFeatures returns the feature flags used when a bot sets none.
+func DefaultFeatures() Features {
+	return Features{
+		LoopDetection: LoopDetectionFeature{Enabled: false},
+	}
+}
+
+// ParseFeatures decodes raw bot metadata JSON and reads its feature flags.
+func ParseFeatures(payload []byte) Features {
+	metadata, err := decodeMetadata(payload)
+	if err != nil {
+		return DefaultFeatures()
+	}
+	return FeaturesFromMetadata(metadata)
+}
+
+// FeaturesFromMetadata reads all feature flags from decoded bot metadata in
+// a single pass.
+func FeaturesFromMetadata(metadata map[string]any) Features {
+	features := DefaultFeatures()
+	raw, ok := metadata["features"].(map[string]any)
+	if !ok {
+		return features
+	}
+	if loop, ok := raw["loop_detection"].(map[string]any); ok {
+		if enabled, ok := loop["enabled"].(bool); ok {
+			features.LoopDetection.Enabled = enabled
+		}
+		if size, ok := intInRange(loop["window_size"], MinLoopDetectionWindowSize, MaxLoopDetectionWindowSize); ok {
+			features.LoopDetection.WindowSize = size
+		}
+		if threshold, ok := intInRange(loop["repeat_threshold"], MinLoopDetectionRepeatThreshold, MaxLoopDetectionRepeatThreshold); ok {
+			features.LoopDetection.RepeatThreshold = threshold
+		}
+	}
+	if rounds, ok := intInRange(raw["max_tool_rounds"], MinMaxToolRounds, MaxMaxToolRounds); ok {
+		features.MaxToolRounds = rounds
+	}
+	if strip, ok := raw["strip_history_headers"].(bool); ok {
+		features.StripHistoryHeaders = strip
+	}
+	if priming, ok := raw["memory_priming"].(bool); ok {
+		features.MemoryPriming = priming
+	}
+	if limit, ok := intInRange(raw["passive_message_limit"], MinPassiveMessageLimit, MaxPassiveMessageLimit); ok {
+		features.PassiveMessageLimit = limit
+	}
+	if trim, ok := raw["history_trim"].(map[string]any); ok {
+		switch strategy, _ := trim["strategy"].(string); strategy {
+		case HistoryTrimTokens, HistoryTrimCount, HistoryTrimCombined:
+			features.HistoryTrim.Strategy = strategy
+		}
+		if limit, ok := intInRange(trim["max_messages"], MinHistoryMaxMessages, MaxHistoryMaxMessages); ok {
+			features.HistoryTrim.MaxMessages = limit
+		}
+	}
+	switch mode, _ := raw["history_system_messages"].(string); mode {
+	case HistorySystemPin, HistorySystemExclude:
+		features.HistorySystemMessages = mode
+	}
+	if experiment, ok := raw["model_experiment"].(map[string]any); ok {
+		features.ModelExperiment = parseModelVariants(experiment["variants"])
+	}
+	return features
+}
+
+// parseModelVariants keeps the well-formed variants of a model experiment.
+// Fewer than two leaves nothing to compare, so the experiment is dropped.
+func parseModelVariants(value any) []ModelVariant {
+	items, ok := value.([]any)
+	if !ok {
+		return nil
+	}
+	variants := make([]ModelVariant, 0, len(items))
+	for _, item := range items {
+		entry, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+		model, _ := entry["model"].(string)
+		model = strings.TrimSpace(model)
+		weight, ok := intInRange(entry["weight"], MinModelVariantWeight, MaxModelVariantWeight)
+		if model == "" || !ok {
+			continue
+		}
+		variants = append(variants, ModelVariant{Model: model, Weight: weight})
+	}
+	if len(variants) < 2 {
+		return nil
+	}
+	return variants
+}
+
+// intInRange reports whether a decoded JSON value is a whole number within
+// [lo, hi].
+func intInRange(value any, lo, hi int) (int, bool) {
+	n, ok := value.(float64)
+	if !ok || n != math.Trunc(n) || n < float64(lo) || n > float64(hi) {
+		return 0, false
+	}
+	return int(n), true
+}
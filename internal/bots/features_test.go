@@ -0,0 +1,160 @@
+package bots
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseFeatures(t *testing.T) {
+	tests := []struct {
+		name     string
+		payload  []byte
+		expected Features
+	}{
+		{
+			name:     "empty payload uses defaults",
+			payload:  nil,
+			expected: DefaultFeatures(),
+		},
+		{
+			name:     "invalid json uses defaults",
+			payload:  []byte("{"),
+			expected: DefaultFeatures(),
+		},
+		{
+			name:     "null metadata uses defaults",
+			payload:  []byte("null"),
+			expected: DefaultFeatures(),
+		},
+		{
+			name:     "missing features uses defaults",
+			payload:  []byte(`{"other":1}`),
+			expected: DefaultFeatures(),
+		},
+		{
+			name:     "features not an object uses defaults",
+			payload:  []byte(`{"features":["loop_detection"]}`),
+			expected: DefaultFeatures(),
+		},
+		{
+			name:     "missing nested path uses defaults",
+			payload:  []byte(`{"features":{}}`),
+			expected: DefaultFeatures(),
+		},
+		{
+			name:     "explicit false",
+			payload:  []byte(`{"features":{"loop_detection":{"enabled":false}}}`),
+			expected: Features{LoopDetection: LoopDetectionFeature{Enabled: false}},
+		},
+		{
+			name:     "explicit true",
+			payload:  []byte(`{"features":{"loop_detection":{"enabled":true}}}`),
+			expected: Features{LoopDetection: LoopDetectionFeature{Enabled: true}},
+		},
+		{
+			name:     "non-boolean value uses default",
+			payload:  []byte(`{"features":{"loop_detection":{"enabled":"true"}}}`),
+			expected: DefaultFeatures(),
+		},
+		{
+			name:     "tuning parameters within range",
+			payload:  []byte(`{"features":{"loop_detection":{"enabled":true,"window_size":500,"repeat_threshold":3}}}`),
+			expected: Features{LoopDetection: LoopDetectionFeature{Enabled: true, WindowSize: 500, RepeatThreshold: 3}},
+		},
+		{
+			name:     "out of range tuning parameters use defaults",
+			payload:  []byte(`{"features":{"loop_detection":{"enabled":true,"window_size":10,"repeat_threshold":1000}}}`),
+			expected: Features{LoopDetection: LoopDetectionFeature{Enabled: true}},
+		},
+		{
+			name:     "non-integer tuning parameters use defaults",
+			payload:  []byte(`{"features":{"loop_detection":{"window_size":"500","repeat_threshold":2.5}}}`),
+			expected: DefaultFeatures(),
+		},
+		{
+			name:     "max tool rounds",
+			payload:  []byte(`{"features":{"max_tool_rounds":25}}`),
+			expected: Features{MaxToolRounds: 25},
+		},
+		{
+			name:     "out of range max tool rounds uses default",
+			payload:  []byte(`{"features":{"max_tool_rounds":0}}`),
+			expected: DefaultFeatures(),
+		},
+		{
+			name:     "strip history headers",
+			payload:  []byte(`{"features":{"strip_history_headers":true}}`),
+			expected: Features{StripHistoryHeaders: true},
+		},
+		{
+			name:     "memory priming",
+			payload:  []byte(`{"features":{"memory_priming":true}}`),
+			expected: Features{MemoryPriming: true},
+		},
+		{
+			name:     "passive message limit",
+			payload:  []byte(`{"features":{"passive_message_limit":200}}`),
+			expected: Features{PassiveMessageLimit: 200},
+		},
+		{
+			name:     "passive message limit out of range is ignored",
+			payload:  []byte(`{"features":{"passive_message_limit":0}}`),
+			expected: DefaultFeatures(),
+		},
+		{
+			name:     "history trim",
+			payload:  []byte(`{"features":{"history_trim":{"strategy":"combined","max_messages":40}}}`),
+			expected: Features{HistoryTrim: HistoryTrimFeature{Strategy: HistoryTrimCombined, MaxMessages: 40}},
+		},
+		{
+			name:     "unknown history trim strategy is ignored",
+			payload:  []byte(`{"features":{"history_trim":{"strategy":"random","max_messages":0}}}`),
+			expected: DefaultFeatures(),
+		},
+		{
+			name:     "history system messages excluded",
+			payload:  []byte(`{"features":{"history_system_messages":"exclude"}}`),
+			expected: Features{HistorySystemMessages: HistorySystemExclude},
+		},
+		{
+			name:     "unknown history system messages mode is ignored",
+			payload:  []byte(`{"features":{"history_system_messages":"all"}}`),
+			expected: DefaultFeatures(),
+		},
+		{
+			name:    "model experiment",
+			payload: []byte(`{"features":{"model_experiment":{"variants":[{"model":" gpt-4o ","weight":70},{"model":"claude","weight":30}]}}}`),
+			expected: Features{ModelExperiment: []ModelVariant{
+				{Model: "gpt-4o", Weight: 70},
+				{Model: "claude", Weight: 30},
+			}},
+		},
+		{
+			name:    "malformed model variants are dropped",
+			payload: []byte(`{"features":{"model_experiment":{"variants":[{"model":"a","weight":1},{"model":"","weight":5},{"model":"b","weight":0},{"model":"c","weight":2.5},"d",{"model":"e","weight":9}]}}}`),
+			expected: Features{ModelExperiment: []ModelVariant{
+				{Model: "a", Weight: 1},
+				{Model: "e", Weight: 9},
+			}},
+		},
+		{
+			name:     "model experiment with one variant is ignored",
+			payload:  []byte(`{"features":{"model_experiment":{"variants":[{"model":"a","weight":1},{"model":"b","weight":-1}]}}}`),
+			expected: DefaultFeatures(),
+		},
+		{
+			name:     "non-object feature uses default",
+			payload:  []byte(`{"features":{"loop_detection":true}}`),
+			expected: DefaultFeatures(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseFeatures(tt.payload)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Fatalf("expected %+v, got %+v", tt.expected, got)
+			}
+		})
+	}
+}
@@ -24,17 +24,28 @@ type Service struct {
 	logger                *slog.Logger
 	containerLifecycle    ContainerLifecycle
 	checkers              []RuntimeChecker
+	controllers           []RuntimeController
 	containerReachability func(ctx context.Context, botID string) error
+	deleteGracePeriod     time.Duration
+	createDefaults        CreateDefaults
+	now                   func() time.Time
 }
 
 const (
 	botLifecycleOperationTimeout = 5 * time.Minute
+	botReadyPollInterval         = 500 * time.Millisecond
+
+	// DefaultDeletePurgeInterval is how often bots past their undelete
+	// window are purged.
+	DefaultDeletePurgeInterval = time.Minute
 )
 
 var (
 	ErrBotNotFound       = errors.New("bot not found")
 	ErrBotAccessDenied   = errors.New("bot access denied")
 	ErrOwnerUserNotFound = errors.New("owner user not found")
+	ErrBotNotDeleted     = errors.New("bot is not pending deletion")
+	ErrUndeleteExpired   = errors.New("bot undelete window has expired")
 )
 
 // NewService creates a new bot service.
@@ -45,9 +56,27 @@ func NewService(log *slog.Logger, queries *sqlc.Queries) *Service {
 	return &Service{
 		queries: queries,
 		logger:  log.With(slog.String("service", "bots")),
+		now:     time.Now,
+	}
+}
+
+// SetCreateDefaults sets the model assignments applied to new bots.
+func (s *Service) SetCreateDefaults(defaults CreateDefaults) {
+	s.createDefaults = CreateDefaults{
+		ChatModel:      strings.TrimSpace(defaults.ChatModel),
+		MemoryProvider: strings.TrimSpace(defaults.MemoryProvider),
 	}
 }
 
+// SetDeleteGracePeriod sets how long a deleted bot stays undeletable before
+// its resources are purged. Zero or less purges on delete.
+func (s *Service) SetDeleteGracePeriod(d time.Duration) {
+	if d < 0 {
+		d = 0
+	}
+	s.deleteGracePeriod = d
+}
+
 // SetContainerLifecycle registers a container lifecycle handler for bot operations.
 func (s *Service) SetContainerLifecycle(lc ContainerLifecycle) {
 	s.containerLifecycle = lc
@@ -66,6 +95,14 @@ func (s *Service) AddRuntimeChecker(c RuntimeChecker) {
 	}
 }
 
+// AddRuntimeController registers a controller paused while a bot is pending
+// deletion and resumed when it is undeleted.
+func (s *Service) AddRuntimeController(c RuntimeController) {
+	if c != nil {
+		s.controllers = append(s.controllers, c)
+	}
+}
+
 // AuthorizeAccess checks whether userID may access the given bot (owner or admin only).
 func (s *Service) AuthorizeAccess(ctx context.Context, userID, botID string, isAdmin bool) (Bot, error) {
 	if s.queries == nil {
@@ -122,13 +159,15 @@ func (s *Service) Create(ctx context.Context, ownerUserID string, req CreateBotR
 		return Bot{}, err
 	}
 	row, err := s.queries.CreateBot(ctx, sqlc.CreateBotParams{
-		OwnerUserID: ownerUUID,
-		DisplayName: pgtype.Text{String: displayName, Valid: displayName != ""},
-		AvatarUrl:   pgtype.Text{String: avatarURL, Valid: avatarURL != ""},
-		Timezone:    timezoneValue,
-		IsActive:    isActive,
-		Metadata:    payload,
-		Status:      BotStatusCreating,
+		OwnerUserID:      ownerUUID,
+		DisplayName:      pgtype.Text{String: displayName, Valid: displayName != ""},
+		AvatarUrl:        pgtype.Text{String: avatarURL, Valid: avatarURL != ""},
+		Timezone:         timezoneValue,
+		IsActive:         isActive,
+		Metadata:         payload,
+		Status:           BotStatusCreating,
+		ChatModelID:      s.defaultChatModel(ctx),
+		MemoryProviderID: s.defaultMemoryProvider(ctx),
 	})
 	if err != nil {
 		return Bot{}, err
@@ -144,6 +183,52 @@ func (s *Service) Create(ctx context.Context, ownerUserID string, req CreateBotR
 	return bot, nil
 }
 
+// Clone creates a bot with the source bot's profile and metadata, owned by
+// the source bot's owner. Settings, channel configs, skills and memories are
+// copied by the caller.
+func (s *Service) Clone(ctx context.Context, sourceBotID string, req CloneBotRequest) (Bot, error) {
+	source, err := s.Get(ctx, sourceBotID)
+	if err != nil {
+		return Bot{}, err
+	}
+	createReq, err := cloneCreateRequest(source, req)
+	if err != nil {
+		return Bot{}, err
+	}
+	return s.Create(ctx, source.OwnerUserID, createReq)
+}
+
+// cloneCreateRequest builds the create request for a clone of source. The
+// metadata is deep-copied so the clone never shares maps with the source.
+func cloneCreateRequest(source Bot, req CloneBotRequest) (CreateBotRequest, error) {
+	displayName := strings.TrimSpace(req.DisplayName)
+	if displayName == "" {
+		displayName = strings.TrimSpace(source.DisplayName + " (copy)")
+	}
+	metadata := map[string]any{}
+	if len(source.Metadata) > 0 {
+		payload, err := json.Marshal(source.Metadata)
+		if err != nil {
+			return CreateBotRequest{}, err
+		}
+		if err := json.Unmarshal(payload, &metadata); err != nil {
+			return CreateBotRequest{}, err
+		}
+	}
+	isActive := source.IsActive
+	createReq := CreateBotRequest{
+		DisplayName: displayName,
+		AvatarURL:   source.AvatarURL,
+		IsActive:    &isActive,
+		Metadata:    metadata,
+	}
+	if source.Timezone != "" {
+		timezone := source.Timezone
+		createReq.Timezone = &timezone
+	}
+	return createReq, nil
+}
+
 // Get returns a bot by its ID.
 func (s *Service) Get(ctx context.Context, botID string) (Bot, error) {
 	if s.queries == nil {
@@ -191,9 +276,45 @@ func (s *Service) ListByOwner(ctx context.Context, ownerUserID string) ([]Bot, e
 		}
 		items = append(items, item)
 	}
+	if err := s.attachActivity(ctx, ownerUUID, items); err != nil {
+		return nil, err
+	}
 	return items, nil
 }
 
+// attachActivity fills the last message and schedule run times of an
+// owner's bots.
+func (s *Service) attachActivity(ctx context.Context, ownerUUID pgtype.UUID, items []Bot) error {
+	if len(items) == 0 {
+		return nil
+	}
+	rows, err := s.queries.ListBotActivityByOwner(ctx, ownerUUID)
+	if err != nil {
+		return err
+	}
+	byID := make(map[string]sqlc.ListBotActivityByOwnerRow, len(rows))
+	for _, row := range rows {
+		byID[row.ID.String()] = row
+	}
+	for i := range items {
+		row, ok := byID[items[i].ID]
+		if !ok {
+			continue
+		}
+		items[i].LastMessageAt = optionalTime(row.LastMessageAt)
+		items[i].LastScheduleRunAt = optionalTime(row.LastScheduleRunAt)
+	}
+	return nil
+}
+
+func optionalTime(value pgtype.Timestamptz) *time.Time {
+	if !value.Valid {
+		return nil
+	}
+	t := value.Time
+	return &t
+}
+
 // ListAccessible returns all bots owned by the user.
 func (s *Service) ListAccessible(ctx context.Context, channelIdentityID string) ([]Bot, error) {
 	return s.ListByOwner(ctx, channelIdentityID)
@@ -299,7 +420,8 @@ func (s *Service) TransferOwner(ctx context.Context, botID string, ownerUserID s
 	return bot, nil
 }
 
-// Delete removes a bot and its associated resources.
+// Delete marks a bot as deleting. Its container and data are removed right
+// away, or once the delete grace period passes if one is configured.
 func (s *Service) Delete(ctx context.Context, botID string) error {
 	if s.queries == nil {
 		return errors.New("bot queries not configured")
@@ -315,16 +437,174 @@ func (s *Service) Delete(ctx context.Context, botID string) error {
 	if strings.TrimSpace(row.Status) == BotStatusDeleting {
 		return nil
 	}
-	if err := s.queries.UpdateBotStatus(ctx, sqlc.UpdateBotStatusParams{
-		ID:     botUUID,
-		Status: BotStatusDeleting,
-	}); err != nil {
+	if err := s.queries.MarkBotDeleting(ctx, botUUID); err != nil {
 		return err
 	}
-	s.enqueueDeleteLifecycle(ctx, botID)
+	s.pauseRuntime(ctx, botID)
+	if s.deleteGracePeriod <= 0 {
+		s.enqueueDeleteLifecycle(ctx, botID)
+	}
 	return nil
 }
 
+// WaitReady blocks until a bot has left the creating status, i.e. its
+// create lifecycle has set up the container.
+func (s *Service) WaitReady(ctx context.Context, botID string) (Bot, error) {
+	ticker := time.NewTicker(botReadyPollInterval)
+	defer ticker.Stop()
+	for {
+		bot, err := s.Get(ctx, botID)
+		if err != nil {
+			return Bot{}, err
+		}
+		if bot.Status != BotStatusCreating {
+			return bot, nil
+		}
+		select {
+		case <-ctx.Done():
+			return Bot{}, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Discard removes a bot right away, ignoring the delete grace period. It
+// rolls back a bot whose creation could not be completed, so it waits for
+// the create lifecycle first and runs even if ctx is already canceled.
+func (s *Service) Discard(ctx context.Context, botID string) error {
+	if s.queries == nil {
+		return errors.New("bot queries not configured")
+	}
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), botLifecycleOperationTimeout)
+	defer cancel()
+	botUUID, err := db.ParseUUID(botID)
+	if err != nil {
+		return err
+	}
+	if _, err := s.WaitReady(ctx, botID); err != nil {
+		return err
+	}
+	if err := s.queries.MarkBotDeleting(ctx, botUUID); err != nil {
+		return err
+	}
+	s.pauseRuntime(ctx, botID)
+	if !s.purgeBot(ctx, botID) {
+		return fmt.Errorf("purge bot %s failed", botID)
+	}
+	return nil
+}
+
+// Undelete restores a bot deleted within the grace period.
+func (s *Service) Undelete(ctx context.Context, botID string) (Bot, error) {
+	if s.queries == nil {
+		return Bot{}, errors.New("bot queries not configured")
+	}
+	botUUID, err := db.ParseUUID(botID)
+	if err != nil {
+		return Bot{}, err
+	}
+	state, err := s.queries.GetBotDeleteState(ctx, botUUID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Bot{}, ErrBotNotFound
+		}
+		return Bot{}, err
+	}
+	if strings.TrimSpace(state.Status) != BotStatusDeleting {
+		return Bot{}, ErrBotNotDeleted
+	}
+	if s.deleteGracePeriod <= 0 || !state.DeleteRequestedAt.Valid {
+		return Bot{}, ErrUndeleteExpired
+	}
+	restored, err := s.queries.RestoreDeletingBot(ctx, sqlc.RestoreDeletingBotParams{
+		ID:     botUUID,
+		Cutoff: s.deleteCutoff(),
+	})
+	if err != nil {
+		return Bot{}, err
+	}
+	if restored == 0 {
+		return Bot{}, ErrUndeleteExpired
+	}
+	s.resumeRuntime(ctx, botID)
+	return s.Get(ctx, botID)
+}
+
+// pauseRuntime stops the background activity of a bot pending deletion.
+func (s *Service) pauseRuntime(ctx context.Context, botID string) {
+	for _, c := range s.controllers {
+		if err := c.PauseBot(ctx, botID); err != nil {
+			s.logger.Warn("pause bot runtime failed", slog.String("bot_id", botID), slog.Any("error", err))
+		}
+	}
+}
+
+// resumeRuntime restarts the background activity of an undeleted bot.
+func (s *Service) resumeRuntime(ctx context.Context, botID string) {
+	for _, c := range s.controllers {
+		if err := c.ResumeBot(ctx, botID); err != nil {
+			s.logger.Warn("resume bot runtime failed", slog.String("bot_id", botID), slog.Any("error", err))
+		}
+	}
+}
+
+// PurgeExpiredDeletes removes the resources of bots whose undelete window
+// has passed and returns how many were purged.
+func (s *Service) PurgeExpiredDeletes(ctx context.Context) (int, error) {
+	if s.queries == nil {
+		return 0, errors.New("bot queries not configured")
+	}
+	if s.deleteGracePeriod <= 0 {
+		return 0, nil
+	}
+	ids, err := s.queries.ListBotsPendingPurge(ctx, s.deleteCutoff())
+	if err != nil {
+		return 0, err
+	}
+	purged := 0
+	for _, id := range ids {
+		if ctx.Err() != nil {
+			return purged, ctx.Err()
+		}
+		purgeCtx, cancel := context.WithTimeout(ctx, botLifecycleOperationTimeout)
+		if s.purgeBot(purgeCtx, id.String()) {
+			purged++
+		}
+		cancel()
+	}
+	return purged, nil
+}
+
+// StartDeletePurgeLoop periodically purges expired bot deletes until ctx is done.
+func (s *Service) StartDeletePurgeLoop(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultDeletePurgeInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			purged, err := s.PurgeExpiredDeletes(ctx)
+			if err != nil {
+				s.logger.Warn("purge expired bot deletes failed", slog.Any("error", err))
+				continue
+			}
+			if purged > 0 {
+				s.logger.Info("purged deleted bots", slog.Int("count", purged))
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (s *Service) deleteCutoff() pgtype.Timestamptz {
+	return pgtype.Timestamptz{Time: s.now().Add(-s.deleteGracePeriod), Valid: true}
+}
+
 // ListChecks evaluates runtime resource checks for a bot.
 func (s *Service) ListChecks(ctx context.Context, botID string) ([]BotCheck, error) {
 	if s.queries == nil {
@@ -368,38 +648,44 @@ func (s *Service) enqueueDeleteLifecycle(ctx context.Context, botID string) {
 	go func() {
 		lifecycleCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), botLifecycleOperationTimeout)
 		defer cancel()
+		s.purgeBot(lifecycleCtx, botID)
+	}()
+}
 
-		if s.containerLifecycle != nil {
-			if err := s.containerLifecycle.CleanupBotContainer(lifecycleCtx, botID, false); err != nil {
-				s.logger.Error("bot container cleanup failed",
-					slog.String("bot_id", botID),
-					slog.Any("error", err),
-				)
-			}
-		}
-
-		botUUID, err := db.ParseUUID(botID)
-		if err != nil {
-			s.logger.Error("invalid bot id while finalizing delete",
+// purgeBot removes a bot's container, data and row. On failure the bot is
+// reverted to ready so it is not left half-deleted.
+func (s *Service) purgeBot(ctx context.Context, botID string) bool {
+	if s.containerLifecycle != nil {
+		if err := s.containerLifecycle.CleanupBotContainer(ctx, botID, false); err != nil {
+			s.logger.Error("bot container cleanup failed",
 				slog.String("bot_id", botID),
 				slog.Any("error", err),
 			)
-			if err := s.updateStatus(lifecycleCtx, botID, BotStatusReady); err != nil {
-				s.logger.Error("revert bot status failed", slog.String("bot_id", botID), slog.Any("error", err))
-			}
-			return
 		}
-		if err := s.queries.DeleteBotByID(lifecycleCtx, botUUID); err != nil {
-			s.logger.Error("failed to delete bot after cleanup",
-				slog.String("bot_id", botID),
-				slog.Any("error", err),
-			)
-			if err := s.updateStatus(lifecycleCtx, botID, BotStatusReady); err != nil {
-				s.logger.Error("revert bot status failed", slog.String("bot_id", botID), slog.Any("error", err))
-			}
-			return
+	}
+
+	botUUID, err := db.ParseUUID(botID)
+	if err != nil {
+		s.logger.Error("invalid bot id while finalizing delete",
+			slog.String("bot_id", botID),
+			slog.Any("error", err),
+		)
+		if err := s.updateStatus(ctx, botID, BotStatusReady); err != nil {
+			s.logger.Error("revert bot status failed", slog.String("bot_id", botID), slog.Any("error", err))
 		}
-	}()
+		return false
+	}
+	if err := s.queries.DeleteBotByID(ctx, botUUID); err != nil {
+		s.logger.Error("failed to delete bot after cleanup",
+			slog.String("bot_id", botID),
+			slog.Any("error", err),
+		)
+		if err := s.updateStatus(ctx, botID, BotStatusReady); err != nil {
+			s.logger.Error("revert bot status failed", slog.String("bot_id", botID), slog.Any("error", err))
+		}
+		return false
+	}
+	return true
 }
 
 func (s *Service) updateStatus(ctx context.Context, botID, status string) error {
@@ -416,6 +702,74 @@ func (s *Service) updateStatus(ctx context.Context, botID, status string) error
 	})
 }
 
+// defaultChatModel resolves the configured default chat model. A default
+// that no longer matches a single chat model is skipped with a warning so
+// bot creation still succeeds.
+func (s *Service) defaultChatModel(ctx context.Context) pgtype.UUID {
+	ref := s.createDefaults.ChatModel
+	if ref == "" {
+		return pgtype.UUID{}
+	}
+	model, err := s.lookupModel(ctx, ref)
+	if err != nil {
+		s.logger.Warn("default chat model not applied",
+			slog.String("model", ref),
+			slog.Any("error", err),
+		)
+		return pgtype.UUID{}
+	}
+	if model.Type != "chat" {
+		s.logger.Warn("default chat model not applied",
+			slog.String("model", ref),
+			slog.String("type", model.Type),
+		)
+		return pgtype.UUID{}
+	}
+	return model.ID
+}
+
+func (s *Service) lookupModel(ctx context.Context, ref string) (sqlc.Model, error) {
+	if parsed, err := db.ParseUUID(ref); err == nil {
+		model, err := s.queries.GetModelByID(ctx, parsed)
+		if err == nil || !errors.Is(err, pgx.ErrNoRows) {
+			return model, err
+		}
+	}
+	rows, err := s.queries.ListModelsByModelID(ctx, ref)
+	if err != nil {
+		return sqlc.Model{}, err
+	}
+	switch len(rows) {
+	case 0:
+		return sqlc.Model{}, errors.New("model not found")
+	case 1:
+		return rows[0], nil
+	default:
+		return sqlc.Model{}, errors.New("model_id matches more than one model")
+	}
+}
+
+// defaultMemoryProvider resolves the configured default memory provider,
+// skipping it with a warning when it does not exist.
+func (s *Service) defaultMemoryProvider(ctx context.Context) pgtype.UUID {
+	ref := s.createDefaults.MemoryProvider
+	if ref == "" {
+		return pgtype.UUID{}
+	}
+	providerID, err := db.ParseUUID(ref)
+	if err == nil {
+		_, err = s.queries.GetMemoryProviderByID(ctx, providerID)
+	}
+	if err != nil {
+		s.logger.Warn("default memory provider not applied",
+			slog.String("memory_provider", ref),
+			slog.Any("error", err),
+		)
+		return pgtype.UUID{}
+	}
+	return providerID
+}
+
 func (s *Service) ensureUserExists(ctx context.Context, userID pgtype.UUID) error {
 	if s.queries == nil {
 		return errors.New("bot queries not configured")
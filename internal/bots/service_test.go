@@ -2,7 +2,10 @@ package bots
 
 import (
 	"context"
+	"errors"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
@@ -145,3 +148,506 @@ func TestAuthorizeAccess(t *testing.T) {
 		})
 	}
 }
+
+func TestCloneCreateRequestCopiesProfile(t *testing.T) {
+	source := Bot{
+		ID:          "11111111-1111-1111-1111-111111111111",
+		OwnerUserID: "22222222-2222-2222-2222-222222222222",
+		DisplayName: "Support",
+		AvatarURL:   "https://example.com/a.png",
+		Timezone:    "Europe/Berlin",
+		IsActive:    false,
+		Metadata: map[string]any{
+			"features": map[string]any{"max_tool_rounds": float64(5)},
+		},
+	}
+
+	req, err := cloneCreateRequest(source, CloneBotRequest{})
+	if err != nil {
+		t.Fatalf("clone request: %v", err)
+	}
+	if req.DisplayName != "Support (copy)" || req.AvatarURL != source.AvatarURL {
+		t.Fatalf("unexpected profile: %+v", req)
+	}
+	if req.Timezone == nil || *req.Timezone != "Europe/Berlin" {
+		t.Fatalf("expected timezone to be copied, got %v", req.Timezone)
+	}
+	if req.IsActive == nil || *req.IsActive {
+		t.Fatalf("expected is_active=false to be copied, got %v", req.IsActive)
+	}
+	if got := FeaturesFromMetadata(req.Metadata).MaxToolRounds; got != 5 {
+		t.Fatalf("expected features to be copied, max_tool_rounds = %d", got)
+	}
+
+	req.Metadata["features"].(map[string]any)["max_tool_rounds"] = float64(9)
+	if source.Metadata["features"].(map[string]any)["max_tool_rounds"] != float64(5) {
+		t.Fatal("clone metadata shares maps with the source bot")
+	}
+
+	named, err := cloneCreateRequest(source, CloneBotRequest{DisplayName: "  Sales  "})
+	if err != nil {
+		t.Fatalf("clone request: %v", err)
+	}
+	if named.DisplayName != "Sales" {
+		t.Fatalf("display name = %q, want Sales", named.DisplayName)
+	}
+}
+
+func TestCloneBotRequestDefaultsToCopyingEverything(t *testing.T) {
+	no := false
+	if req := (CloneBotRequest{}); !req.CopySettings() || !req.CopyChannels() || !req.CopySkills() || !req.CopyMemories() {
+		t.Fatal("expected settings, channels, skills and memories to be copied by default")
+	}
+	req := CloneBotRequest{Settings: &no, Channels: &no, Skills: &no, Memories: &no}
+	if req.CopySettings() || req.CopyChannels() || req.CopySkills() || req.CopyMemories() {
+		t.Fatal("expected explicit false to skip settings, channels, skills and memories")
+	}
+}
+
+// deleteStateDB is a stateful sqlc.DBTX covering the soft-delete queries.
+type deleteStateDB struct {
+	botID       pgtype.UUID
+	ownerID     pgtype.UUID
+	exists      bool
+	status      string
+	requestedAt pgtype.Timestamptz
+	now         func() time.Time
+}
+
+func (d *deleteStateDB) Exec(_ context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	switch {
+	case strings.Contains(sql, "name: MarkBotDeleting "):
+		d.status = BotStatusDeleting
+		d.requestedAt = pgtype.Timestamptz{Time: d.now(), Valid: true}
+	case strings.Contains(sql, "name: RestoreDeletingBot "):
+		cutoff := args[1].(pgtype.Timestamptz)
+		if !d.exists || d.status != BotStatusDeleting || !d.requestedAt.Time.After(cutoff.Time) {
+			return pgconn.NewCommandTag("UPDATE 0"), nil
+		}
+		d.status = BotStatusReady
+		d.requestedAt = pgtype.Timestamptz{}
+		return pgconn.NewCommandTag("UPDATE 1"), nil
+	case strings.Contains(sql, "name: DeleteBotByID "):
+		d.exists = false
+	case strings.Contains(sql, "name: UpdateBotStatus "):
+		d.status = args[1].(string)
+	}
+	return pgconn.CommandTag{}, nil
+}
+
+func (d *deleteStateDB) Query(_ context.Context, sql string, args ...any) (pgx.Rows, error) {
+	if strings.Contains(sql, "name: ListBotsPendingPurge ") {
+		cutoff := args[0].(pgtype.Timestamptz)
+		if d.exists && d.status == BotStatusDeleting && !d.requestedAt.Time.After(cutoff.Time) {
+			return newFakeRows(func(dest ...any) error {
+				*dest[0].(*pgtype.UUID) = d.botID
+				return nil
+			}), nil
+		}
+	}
+	return newFakeRows(), nil
+}
+
+func (d *deleteStateDB) QueryRow(_ context.Context, sql string, _ ...any) pgx.Row {
+	if !d.exists {
+		return &fakeRow{scanFunc: func(_ ...any) error { return pgx.ErrNoRows }}
+	}
+	switch {
+	case strings.Contains(sql, "name: GetBotByID "):
+		return makeBotRow(d.botID, d.ownerID)
+	case strings.Contains(sql, "name: GetBotDeleteState "):
+		return &fakeRow{scanFunc: func(dest ...any) error {
+			*dest[0].(*string) = d.status
+			*dest[1].(*pgtype.Timestamptz) = d.requestedAt
+			return nil
+		}}
+	}
+	return &fakeRow{scanFunc: func(_ ...any) error { return pgx.ErrNoRows }}
+}
+
+// fakeRows implements pgx.Rows with one scan function per row.
+type fakeRows struct {
+	scans []func(dest ...any) error
+	pos   int
+}
+
+func newFakeRows(scans ...func(dest ...any) error) *fakeRows {
+	return &fakeRows{scans: scans, pos: -1}
+}
+
+func (*fakeRows) Close()                                       {}
+func (*fakeRows) Err() error                                   { return nil }
+func (*fakeRows) CommandTag() pgconn.CommandTag                { return pgconn.CommandTag{} }
+func (*fakeRows) FieldDescriptions() []pgconn.FieldDescription { return nil }
+func (*fakeRows) Values() ([]any, error)                       { return nil, nil }
+func (*fakeRows) RawValues() [][]byte                          { return nil }
+func (*fakeRows) Conn() *pgx.Conn                              { return nil }
+
+func (r *fakeRows) Next() bool {
+	r.pos++
+	return r.pos < len(r.scans)
+}
+
+func (r *fakeRows) Scan(dest ...any) error {
+	return r.scans[r.pos](dest...)
+}
+
+type recordingLifecycle struct {
+	cleanups []bool
+}
+
+func (*recordingLifecycle) SetupBotContainer(context.Context, string) error { return nil }
+
+func (l *recordingLifecycle) CleanupBotContainer(_ context.Context, _ string, preserveData bool) error {
+	l.cleanups = append(l.cleanups, preserveData)
+	return nil
+}
+
+type recordingController struct {
+	calls []string
+}
+
+func (c *recordingController) PauseBot(_ context.Context, botID string) error {
+	c.calls = append(c.calls, "pause:"+botID)
+	return nil
+}
+
+func (c *recordingController) ResumeBot(_ context.Context, botID string) error {
+	c.calls = append(c.calls, "resume:"+botID)
+	return nil
+}
+
+func newDeleteTestService(t *testing.T) (*Service, *deleteStateDB, *recordingLifecycle, *time.Time) {
+	t.Helper()
+	clock := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	now := func() time.Time { return clock }
+	db := &deleteStateDB{
+		botID:   mustParseUUID("00000000-0000-0000-0000-000000000002"),
+		ownerID: mustParseUUID("00000000-0000-0000-0000-000000000001"),
+		exists:  true,
+		status:  BotStatusReady,
+		now:     now,
+	}
+	lifecycle := &recordingLifecycle{}
+	svc := NewService(nil, sqlc.New(db))
+	svc.now = now
+	svc.SetContainerLifecycle(lifecycle)
+	svc.SetDeleteGracePeriod(time.Hour)
+	return svc, db, lifecycle, &clock
+}
+
+func TestUndeleteWithinGracePeriod(t *testing.T) {
+	svc, db, lifecycle, clock := newDeleteTestService(t)
+	controller := &recordingController{}
+	svc.AddRuntimeController(controller)
+	ctx := context.Background()
+	botID := db.botID.String()
+
+	if err := svc.Delete(ctx, botID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if db.status != BotStatusDeleting {
+		t.Fatalf("status after delete = %q, want %q", db.status, BotStatusDeleting)
+	}
+	if len(controller.calls) != 1 || controller.calls[0] != "pause:"+botID {
+		t.Fatalf("runtime calls after delete = %v, want pause", controller.calls)
+	}
+
+	*clock = clock.Add(30 * time.Minute)
+	purged, err := svc.PurgeExpiredDeletes(ctx)
+	if err != nil {
+		t.Fatalf("purge: %v", err)
+	}
+	if purged != 0 || len(lifecycle.cleanups) != 0 {
+		t.Fatalf("purged %d bots within the grace period, cleanups = %v", purged, lifecycle.cleanups)
+	}
+
+	bot, err := svc.Undelete(ctx, botID)
+	if err != nil {
+		t.Fatalf("undelete: %v", err)
+	}
+	if bot.ID != botID {
+		t.Fatalf("undeleted bot id = %q, want %q", bot.ID, botID)
+	}
+	if db.status != BotStatusReady || db.requestedAt.Valid {
+		t.Fatalf("bot not restored: status=%q requested_at=%v", db.status, db.requestedAt)
+	}
+	if len(controller.calls) != 2 || controller.calls[1] != "resume:"+botID {
+		t.Fatalf("runtime calls after undelete = %v, want pause then resume", controller.calls)
+	}
+
+	if _, err := svc.Undelete(ctx, botID); !errors.Is(err, ErrBotNotDeleted) {
+		t.Fatalf("undelete of an active bot: got %v, want %v", err, ErrBotNotDeleted)
+	}
+}
+
+func TestUndeleteFailsAfterPurge(t *testing.T) {
+	svc, db, lifecycle, clock := newDeleteTestService(t)
+	ctx := context.Background()
+	botID := db.botID.String()
+
+	if err := svc.Delete(ctx, botID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+
+	*clock = clock.Add(2 * time.Hour)
+	if _, err := svc.Undelete(ctx, botID); !errors.Is(err, ErrUndeleteExpired) {
+		t.Fatalf("undelete after the window: got %v, want %v", err, ErrUndeleteExpired)
+	}
+
+	purged, err := svc.PurgeExpiredDeletes(ctx)
+	if err != nil {
+		t.Fatalf("purge: %v", err)
+	}
+	if purged != 1 {
+		t.Fatalf("purged = %d, want 1", purged)
+	}
+	if len(lifecycle.cleanups) != 1 || lifecycle.cleanups[0] {
+		t.Fatalf("expected one cleanup without preserving data, got %v", lifecycle.cleanups)
+	}
+	if db.exists {
+		t.Fatal("bot row still exists after purge")
+	}
+
+	if _, err := svc.Undelete(ctx, botID); !errors.Is(err, ErrBotNotFound) {
+		t.Fatalf("undelete after purge: got %v, want %v", err, ErrBotNotFound)
+	}
+}
+
+func TestDiscardIgnoresGracePeriod(t *testing.T) {
+	svc, db, lifecycle, _ := newDeleteTestService(t)
+	controller := &recordingController{}
+	svc.AddRuntimeController(controller)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := svc.Discard(ctx, db.botID.String()); err != nil {
+		t.Fatalf("discard: %v", err)
+	}
+	if db.exists {
+		t.Fatal("bot row still exists after discard")
+	}
+	if len(lifecycle.cleanups) != 1 || lifecycle.cleanups[0] {
+		t.Fatalf("expected one cleanup without preserving data, got %v", lifecycle.cleanups)
+	}
+	if len(controller.calls) != 1 {
+		t.Fatalf("runtime calls = %v, want one pause", controller.calls)
+	}
+}
+
+// activityDB serves ListBotsByOwner and ListBotActivityByOwner for two bots.
+type activityDB struct {
+	fakeDBTX
+	ownerID  pgtype.UUID
+	botIDs   []pgtype.UUID
+	activity map[pgtype.UUID][2]pgtype.Timestamptz
+}
+
+func (d *activityDB) Query(_ context.Context, sql string, _ ...any) (pgx.Rows, error) {
+	var scans []func(dest ...any) error
+	for _, id := range d.botIDs {
+		switch {
+		case strings.Contains(sql, "name: ListBotsByOwner "):
+			scans = append(scans, func(dest ...any) error {
+				*dest[0].(*pgtype.UUID) = id
+				*dest[1].(*pgtype.UUID) = d.ownerID
+				*dest[6].(*string) = BotStatusReady
+				*dest[16].(*[]byte) = []byte(`{}`)
+				return nil
+			})
+		case strings.Contains(sql, "name: ListBotActivityByOwner "):
+			scans = append(scans, func(dest ...any) error {
+				*dest[0].(*pgtype.UUID) = id
+				*dest[1].(*pgtype.Timestamptz) = d.activity[id][0]
+				*dest[2].(*pgtype.Timestamptz) = d.activity[id][1]
+				return nil
+			})
+		}
+	}
+	return newFakeRows(scans...), nil
+}
+
+func TestListByOwnerAttachesActivity(t *testing.T) {
+	ownerUUID := mustParseUUID("00000000-0000-0000-0000-000000000001")
+	idleUUID := mustParseUUID("00000000-0000-0000-0000-000000000002")
+	busyUUID := mustParseUUID("00000000-0000-0000-0000-000000000003")
+	messageAt := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	scheduleAt := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+
+	db := &activityDB{
+		ownerID: ownerUUID,
+		botIDs:  []pgtype.UUID{idleUUID, busyUUID},
+		activity: map[pgtype.UUID][2]pgtype.Timestamptz{
+			busyUUID: {
+				{Time: messageAt, Valid: true},
+				{Time: scheduleAt, Valid: true},
+			},
+		},
+	}
+	svc := NewService(nil, sqlc.New(db))
+
+	items, err := svc.ListByOwner(context.Background(), ownerUUID.String())
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("got %d bots, want 2", len(items))
+	}
+	idle, busy := items[0], items[1]
+	if idle.LastMessageAt != nil || idle.LastScheduleRunAt != nil {
+		t.Fatalf("idle bot has activity: %v %v", idle.LastMessageAt, idle.LastScheduleRunAt)
+	}
+	if busy.LastMessageAt == nil || !busy.LastMessageAt.Equal(messageAt) {
+		t.Fatalf("last_message_at = %v, want %v", busy.LastMessageAt, messageAt)
+	}
+	if busy.LastScheduleRunAt == nil || !busy.LastScheduleRunAt.Equal(scheduleAt) {
+		t.Fatalf("last_schedule_run_at = %v, want %v", busy.LastScheduleRunAt, scheduleAt)
+	}
+	if !busy.LastActivityAt().Equal(scheduleAt) {
+		t.Fatalf("last activity = %v, want %v", busy.LastActivityAt(), scheduleAt)
+	}
+}
+
+func TestSortByActivity(t *testing.T) {
+	at := func(day int) *time.Time {
+		v := time.Date(2026, 3, day, 0, 0, 0, 0, time.UTC)
+		return &v
+	}
+	items := []Bot{
+		{ID: "never"},
+		{ID: "message", LastMessageAt: at(2)},
+		{ID: "dormant", LastMessageAt: at(1)},
+		{ID: "schedule", LastMessageAt: at(1), LastScheduleRunAt: at(5)},
+		{ID: "never-2"},
+	}
+
+	SortByActivity(items)
+
+	want := []string{"schedule", "message", "dormant", "never", "never-2"}
+	for i, id := range want {
+		if items[i].ID != id {
+			t.Fatalf("position %d = %q, want %q (order %v)", i, items[i].ID, id, items)
+		}
+	}
+}
+
+// createDefaultsDB serves the queries used by Create and records the
+// model assignments passed to CreateBot.
+type createDefaultsDB struct {
+	fakeDBTX
+	models         []sqlc.Model
+	providerID     pgtype.UUID
+	chatModelID    pgtype.UUID
+	memoryProvider pgtype.UUID
+}
+
+func modelScan(m sqlc.Model) func(dest ...any) error {
+	return func(dest ...any) error {
+		*dest[0].(*pgtype.UUID) = m.ID
+		*dest[1].(*string) = m.ModelID
+		*dest[4].(*string) = m.Type
+		return nil
+	}
+}
+
+func (d *createDefaultsDB) Query(_ context.Context, sql string, args ...any) (pgx.Rows, error) {
+	var scans []func(dest ...any) error
+	if strings.Contains(sql, "name: ListModelsByModelID ") {
+		for _, m := range d.models {
+			if m.ModelID == args[0].(string) {
+				scans = append(scans, modelScan(m))
+			}
+		}
+	}
+	return newFakeRows(scans...), nil
+}
+
+func (d *createDefaultsDB) QueryRow(_ context.Context, sql string, args ...any) pgx.Row {
+	noRows := &fakeRow{scanFunc: func(_ ...any) error { return pgx.ErrNoRows }}
+	switch {
+	case strings.Contains(sql, "name: GetUserByID "):
+		return &fakeRow{scanFunc: func(_ ...any) error { return nil }}
+	case strings.Contains(sql, "name: GetModelByID "):
+		for _, m := range d.models {
+			if m.ID == args[0].(pgtype.UUID) {
+				return &fakeRow{scanFunc: modelScan(m)}
+			}
+		}
+		return noRows
+	case strings.Contains(sql, "name: GetMemoryProviderByID "):
+		if args[0].(pgtype.UUID) != d.providerID {
+			return noRows
+		}
+		return &fakeRow{scanFunc: func(_ ...any) error { return nil }}
+	case strings.Contains(sql, "name: CreateBot "):
+		d.chatModelID = args[7].(pgtype.UUID)
+		d.memoryProvider = args[8].(pgtype.UUID)
+		return &fakeRow{scanFunc: func(dest ...any) error {
+			*dest[0].(*pgtype.UUID) = mustParseUUID("00000000-0000-0000-0000-000000000009")
+			*dest[1].(*pgtype.UUID) = args[0].(pgtype.UUID)
+			*dest[6].(*string) = BotStatusCreating
+			*dest[16].(*[]byte) = []byte(`{}`)
+			return nil
+		}}
+	}
+	return noRows
+}
+
+func TestCreateAppliesDefaults(t *testing.T) {
+	owner := "00000000-0000-0000-0000-000000000001"
+	chatModel := sqlc.Model{ID: mustParseUUID("00000000-0000-0000-0000-0000000000a1"), ModelID: "gpt-4o", Type: "chat"}
+	embedModel := sqlc.Model{ID: mustParseUUID("00000000-0000-0000-0000-0000000000a2"), ModelID: "text-embedding-3-small", Type: "embedding"}
+	providerID := mustParseUUID("00000000-0000-0000-0000-0000000000b1")
+
+	tests := []struct {
+		name         string
+		defaults     CreateDefaults
+		wantChat     pgtype.UUID
+		wantProvider pgtype.UUID
+	}{
+		{
+			name:         "model id and provider",
+			defaults:     CreateDefaults{ChatModel: "gpt-4o", MemoryProvider: providerID.String()},
+			wantChat:     chatModel.ID,
+			wantProvider: providerID,
+		},
+		{
+			name:     "model uuid",
+			defaults: CreateDefaults{ChatModel: chatModel.ID.String()},
+			wantChat: chatModel.ID,
+		},
+		{
+			name:     "no defaults",
+			defaults: CreateDefaults{},
+		},
+		{
+			name:     "missing model and provider are skipped",
+			defaults: CreateDefaults{ChatModel: "unknown", MemoryProvider: "00000000-0000-0000-0000-0000000000ff"},
+		},
+		{
+			name:     "non-chat model is skipped",
+			defaults: CreateDefaults{ChatModel: embedModel.ModelID},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := &createDefaultsDB{
+				models:     []sqlc.Model{chatModel, embedModel},
+				providerID: providerID,
+			}
+			svc := NewService(nil, sqlc.New(db))
+			svc.SetCreateDefaults(tt.defaults)
+
+			if _, err := svc.Create(context.Background(), owner, CreateBotRequest{DisplayName: "new"}); err != nil {
+				t.Fatalf("create: %v", err)
+			}
+			if db.chatModelID != tt.wantChat {
+				t.Fatalf("chat_model_id = %v, want %v", db.chatModelID, tt.wantChat)
+			}
+			if db.memoryProvider != tt.wantProvider {
+				t.Fatalf("memory_provider_id = %v, want %v", db.memoryProvider, tt.wantProvider)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package bots
 
 import (
 	"context"
+	"sort"
 	"time"
 )
 
@@ -19,6 +20,22 @@ type Bot struct {
 	Metadata        map[string]any `json:"metadata,omitempty"`
 	CreatedAt       time.Time      `json:"created_at"`
 	UpdatedAt       time.Time      `json:"updated_at"`
+	// LastMessageAt and LastScheduleRunAt are only set on list responses.
+	LastMessageAt     *time.Time `json:"last_message_at,omitempty"`
+	LastScheduleRunAt *time.Time `json:"last_schedule_run_at,omitempty"`
+}
+
+// LastActivityAt returns the later of the bot's last message and last
+// schedule run, or the zero time when it has neither.
+func (b Bot) LastActivityAt() time.Time {
+	var last time.Time
+	if b.LastMessageAt != nil {
+		last = *b.LastMessageAt
+	}
+	if b.LastScheduleRunAt != nil && b.LastScheduleRunAt.After(last) {
+		last = *b.LastScheduleRunAt
+	}
+	return last
 }
 
 // BotCheck represents one resource check row for a bot.
@@ -42,6 +59,40 @@ type CreateBotRequest struct {
 	Metadata    map[string]any `json:"metadata,omitempty"`
 }
 
+// CloneBotRequest is the input for cloning a bot. The clone always gets the
+// source bot's profile and metadata; Settings, Channels, Skills and Memories
+// select whether its settings, channel configs, skills and shared memories
+// are copied too, and default to true.
+type CloneBotRequest struct {
+	DisplayName string `json:"display_name,omitempty"`
+	Settings    *bool  `json:"settings,omitempty"`
+	Channels    *bool  `json:"channels,omitempty"`
+	Skills      *bool  `json:"skills,omitempty"`
+	Memories    *bool  `json:"memories,omitempty"`
+}
+
+// CopySettings reports whether the clone gets the source bot's settings.
+func (r CloneBotRequest) CopySettings() bool {
+	return r.Settings == nil || *r.Settings
+}
+
+// CopyChannels reports whether the clone gets the source bot's channel
+// configs.
+func (r CloneBotRequest) CopyChannels() bool {
+	return r.Channels == nil || *r.Channels
+}
+
+// CopySkills reports whether the clone gets the source bot's skills.
+func (r CloneBotRequest) CopySkills() bool {
+	return r.Skills == nil || *r.Skills
+}
+
+// CopyMemories reports whether the clone gets the source bot's shared
+// memories.
+func (r CloneBotRequest) CopyMemories() bool {
+	return r.Memories == nil || *r.Memories
+}
+
 // UpdateBotRequest is the input for updating a bot.
 type UpdateBotRequest struct {
 	DisplayName *string        `json:"display_name,omitempty"`
@@ -56,6 +107,20 @@ type TransferBotRequest struct {
 	OwnerUserID string `json:"owner_user_id"`
 }
 
+// List sort orders accepted by the bot list endpoint.
+const (
+	ListSortCreated  = "created"
+	ListSortActivity = "activity"
+)
+
+// SortByActivity orders bots by most recent activity first. Bots with no
+// activity keep their relative order at the end.
+func SortByActivity(items []Bot) {
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].LastActivityAt().After(items[j].LastActivityAt())
+	})
+}
+
 // ListBotsResponse wraps a list of bots.
 type ListBotsResponse struct {
 	Items []Bot `json:"items"`
@@ -66,12 +131,27 @@ type ListChecksResponse struct {
 	Items []BotCheck `json:"items"`
 }
 
+// CreateDefaults are assignments applied to newly created bots. ChatModel
+// may be a model UUID or a unique model_id; MemoryProvider is a memory
+// provider UUID. Empty values are left unset.
+type CreateDefaults struct {
+	ChatModel      string
+	MemoryProvider string
+}
+
 // ContainerLifecycle handles container lifecycle events bound to bot operations.
 type ContainerLifecycle interface {
 	SetupBotContainer(ctx context.Context, botID string) error
 	CleanupBotContainer(ctx context.Context, botID string, preserveData bool) error
 }
 
+// RuntimeController pauses and resumes a bot's background activity, such as
+// channel connections, schedules and heartbeats, while it is pending deletion.
+type RuntimeController interface {
+	PauseBot(ctx context.Context, botID string) error
+	ResumeBot(ctx context.Context, botID string) error
+}
+
 // RuntimeChecker produces runtime check items for a bot.
 type RuntimeChecker interface {
 	// ListChecks evaluates dynamic runtime checks for a bot.
@@ -117,6 +117,12 @@ type Sender interface {
 	Send(ctx context.Context, cfg ChannelConfig, msg PreparedOutboundMessage) error
 }
 
+// ReceiptSender is a Sender that also reports the platform message ID assigned
+// to a delivered message. The manager prefers it over Send when available.
+type ReceiptSender interface {
+	SendWithReceipt(ctx context.Context, cfg ChannelConfig, msg PreparedOutboundMessage) (string, error)
+}
+
 // StreamSender is an adapter capable of opening outbound stream sessions.
 type StreamSender interface {
 	OpenStream(ctx context.Context, cfg ChannelConfig, target string, opts StreamOptions) (PreparedOutboundStream, error)
@@ -45,12 +45,14 @@ func (s *dingtalkOutboundStream) Push(ctx context.Context, event channel.Prepare
 		channel.StreamEventPhaseStart,
 		channel.StreamEventPhaseEnd,
 		channel.StreamEventToolCallStart,
+		channel.StreamEventToolCallDelta,
 		channel.StreamEventToolCallEnd,
 		channel.StreamEventAgentStart,
 		channel.StreamEventAgentEnd,
 		channel.StreamEventProcessingStarted,
 		channel.StreamEventProcessingCompleted,
-		channel.StreamEventProcessingFailed:
+		channel.StreamEventProcessingFailed,
+		channel.StreamEventUnknown:
 		// Non-content events: no-op.
 		return nil
 
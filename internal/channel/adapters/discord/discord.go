@@ -22,6 +22,10 @@ import (
 const (
 	inboundDedupTTL  = time.Minute
 	discordMaxLength = 2000
+
+	discordMaxThreadName        = 100
+	discordDefaultThreadName    = "Reply"
+	discordThreadArchiveMinutes = 1440
 )
 
 // assetOpener reads stored asset bytes by content hash.
@@ -70,10 +74,13 @@ func (*DiscordAdapter) Descriptor() channel.Descriptor {
 			Markdown:       true,
 			Reply:          true,
 			Attachments:    true,
+			MaxAttachments: 10,
 			Media:          true,
 			Streaming:      true,
 			BlockStreaming: true,
 			Reactions:      true,
+			Threads:        true,
+			Ephemeral:      true,
 		},
 		ConfigSchema: channel.ConfigSchema{
 			Version: 1,
@@ -293,7 +300,16 @@ func sendDiscordMessage(ctx context.Context, session *discordgo.Session, channel
 		Content: content,
 	}
 
-	if msg.Message.Message.Reply != nil && msg.Message.Message.Reply.MessageID != "" {
+	// Bots can only post ephemeral messages in answer to interactions, so
+	// an ephemeral reply goes to the user's DMs instead.
+	rerouted := false
+	if msg.Message.Message.Ephemeral {
+		channelID, rerouted = discordDirectChannel(session, channelID, msg.Message.Message.Reply)
+	}
+	if thread := msg.Message.Message.Thread; thread != nil && !rerouted {
+		channelID, rerouted = startDiscordThread(session, channelID, thread.ID)
+	}
+	if !rerouted && msg.Message.Message.Reply != nil && msg.Message.Message.Reply.MessageID != "" {
 		messageSend.Reference = &discordgo.MessageReference{
 			ChannelID: channelID,
 			MessageID: msg.Message.Message.Reply.MessageID,
@@ -327,6 +343,61 @@ func sendDiscordMessage(ctx context.Context, session *discordgo.Session, channel
 	return err
 }
 
+// discordDirectChannel returns the DM channel with the author of the
+// message reply answers. It reports false when the author is unknown or is
+// already the only other member of channelID.
+func discordDirectChannel(session *discordgo.Session, channelID string, reply *channel.ReplyRef) (string, bool) {
+	if reply == nil || strings.TrimSpace(reply.MessageID) == "" {
+		return channelID, false
+	}
+	source, err := session.ChannelMessage(channelID, strings.TrimSpace(reply.MessageID))
+	if err != nil || source.Author == nil {
+		return channelID, false
+	}
+	dm, err := session.UserChannelCreate(source.Author.ID)
+	if err != nil || dm.ID == channelID {
+		return channelID, false
+	}
+	return dm.ID, true
+}
+
+// startDiscordThread returns the channel a threaded reply is posted in. It
+// starts a thread on message messageID in channelID, or reuses the one the
+// message already has (a message's thread shares its ID). It reports false
+// when no thread is available, e.g. when channelID is a thread itself.
+func startDiscordThread(session *discordgo.Session, channelID, messageID string) (string, bool) {
+	messageID = strings.TrimSpace(messageID)
+	if messageID == "" || messageID == channelID {
+		return channelID, false
+	}
+	thread, err := session.MessageThreadStartComplex(channelID, messageID, &discordgo.ThreadStart{
+		Name:                discordThreadName(session, channelID, messageID),
+		AutoArchiveDuration: discordThreadArchiveMinutes,
+	})
+	if err == nil {
+		return thread.ID, true
+	}
+	if existing, err := session.Channel(messageID); err == nil && existing.IsThread() {
+		return existing.ID, true
+	}
+	return channelID, false
+}
+
+// discordThreadName names a thread after the message it starts from.
+func discordThreadName(session *discordgo.Session, channelID, messageID string) string {
+	name := ""
+	if msg, err := session.ChannelMessage(channelID, messageID); err == nil {
+		name = strings.Join(strings.Fields(msg.Content), " ")
+	}
+	if name == "" {
+		return discordDefaultThreadName
+	}
+	if runes := []rune(name); len(runes) > discordMaxThreadName {
+		name = string(runes[:discordMaxThreadName-3]) + "..."
+	}
+	return name
+}
+
 func truncateDiscordText(text string) string {
 	if utf8.RuneCountInString(text) <= discordMaxLength {
 		return text
@@ -419,6 +490,7 @@ func (a *DiscordAdapter) OpenStream(_ context.Context, cfg channel.ChannelConfig
 		cfg:     cfg,
 		target:  target,
 		reply:   opts.Reply,
+		thread:  opts.Thread,
 		session: session,
 	}, nil
 }
@@ -3,9 +3,12 @@ package discord
 import (
 	"context"
 	"io"
+	"net/http"
 	"strings"
 	"testing"
 
+	"github.com/bwmarrin/discordgo"
+
 	"github.com/memohai/memoh/internal/channel"
 )
 
@@ -60,3 +63,49 @@ func TestDiscordPreparedAttachmentToFile(t *testing.T) {
 		t.Error("discordPreparedAttachmentToFile() expected error for non-upload kind")
 	}
 }
+
+func TestSendDiscordMessageEphemeralGoesToDM(t *testing.T) {
+	var postPath, postBody string
+	session, err := discordgo.New("Bot test")
+	if err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+	session.Client = &http.Client{
+		Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			body, _ := io.ReadAll(req.Body)
+			respBody := `{"id":"msg-2"}`
+			switch {
+			case req.Method == http.MethodGet && strings.HasSuffix(req.URL.Path, "/channels/ch-1/messages/src-1"):
+				respBody = `{"id":"src-1","channel_id":"ch-1","author":{"id":"user-1"}}`
+			case strings.HasSuffix(req.URL.Path, "/users/@me/channels"):
+				respBody = `{"id":"dm-1","type":1}`
+			case req.Method == http.MethodPost:
+				postPath = req.URL.Path
+				postBody = string(body)
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(respBody)),
+				Header:     http.Header{"Content-Type": []string{"application/json"}},
+			}, nil
+		}),
+	}
+
+	err = sendDiscordMessage(context.Background(), session, "ch-1", channel.PreparedOutboundMessage{
+		Target: "ch-1",
+		Message: channel.PreparedMessage{Message: channel.Message{
+			Text:      "Error: not allowed",
+			Ephemeral: true,
+			Reply:     &channel.ReplyRef{MessageID: "src-1"},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if !strings.HasSuffix(postPath, "/channels/dm-1/messages") {
+		t.Fatalf("message posted to %q, want the DM channel", postPath)
+	}
+	if strings.Contains(postBody, "message_reference") {
+		t.Fatalf("DM should not reference the channel message: %s", postBody)
+	}
+}
@@ -19,6 +19,7 @@ type discordOutboundStream struct {
 	cfg        channel.ChannelConfig
 	target     string
 	reply      *channel.ReplyRef
+	thread     *channel.ThreadRef
 	session    *discordgo.Session
 	closed     atomic.Bool
 	mu         sync.Mutex
@@ -103,7 +104,7 @@ func (s *discordOutboundStream) Push(ctx context.Context, event channel.Prepared
 		}
 		return nil
 
-	case channel.StreamEventAgentStart, channel.StreamEventAgentEnd, channel.StreamEventPhaseStart, channel.StreamEventPhaseEnd, channel.StreamEventProcessingStarted, channel.StreamEventProcessingCompleted, channel.StreamEventProcessingFailed, channel.StreamEventToolCallStart, channel.StreamEventToolCallEnd:
+	case channel.StreamEventAgentStart, channel.StreamEventAgentEnd, channel.StreamEventPhaseStart, channel.StreamEventPhaseEnd, channel.StreamEventProcessingStarted, channel.StreamEventProcessingCompleted, channel.StreamEventProcessingFailed, channel.StreamEventToolCallStart, channel.StreamEventToolCallDelta, channel.StreamEventToolCallEnd, channel.StreamEventUnknown:
 		// Status events - no action needed for Discord
 		return nil
 
@@ -132,6 +133,7 @@ func (s *discordOutboundStream) ensureMessage(text string) error {
 	if s.msgID != "" {
 		return nil
 	}
+	s.enterThreadLocked()
 
 	content := truncateDiscordText(text)
 
@@ -185,6 +187,7 @@ func (s *discordOutboundStream) finalizeMessage(text string) error {
 	text = truncateDiscordText(text)
 
 	if s.msgID == "" {
+		s.enterThreadLocked()
 		var msg *discordgo.Message
 		var err error
 		if s.reply != nil && s.reply.MessageID != "" {
@@ -217,6 +220,10 @@ func (s *discordOutboundStream) sendAttachment(ctx context.Context, att channel.
 		Files: []*discordgo.File{file},
 	}
 
+	s.mu.Lock()
+	s.enterThreadLocked()
+	s.mu.Unlock()
+
 	// Add reply reference if this is the first message and we have a reply target
 	if s.reply != nil && s.reply.MessageID != "" {
 		messageSend.Reference = &discordgo.MessageReference{
@@ -228,3 +235,17 @@ func (s *discordOutboundStream) sendAttachment(ctx context.Context, att channel.
 	_, err = s.session.ChannelMessageSendComplex(s.target, messageSend)
 	return err
 }
+
+// enterThreadLocked moves the stream into the thread it replies in before
+// the first message is posted. Messages in the thread drop the reply
+// reference, since the thread already anchors them to the source message.
+func (s *discordOutboundStream) enterThreadLocked() {
+	if s.thread == nil {
+		return
+	}
+	if target, ok := startDiscordThread(s.session, s.target, s.thread.ID); ok {
+		s.target = target
+		s.reply = nil
+	}
+	s.thread = nil
+}
@@ -72,3 +72,59 @@ func TestDiscordOutboundStream_PushErrorEventRedactsSecrets(t *testing.T) {
 		t.Fatalf("expected redaction mask, got %q", content)
 	}
 }
+
+func TestDiscordOutboundStream_ThreadReplyPostsInThread(t *testing.T) {
+	var threadName, postPath, postBody string
+	session, err := discordgo.New("Bot test")
+	if err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+	session.Client = &http.Client{
+		Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			body, _ := io.ReadAll(req.Body)
+			respBody := `{"id":"msg-2","channel_id":"src-1"}`
+			switch {
+			case req.Method == http.MethodGet && strings.HasSuffix(req.URL.Path, "/channels/ch-1/messages/src-1"):
+				respBody = `{"id":"src-1","channel_id":"ch-1","content":"how do I   deploy?"}`
+			case strings.HasSuffix(req.URL.Path, "/channels/ch-1/messages/src-1/threads"):
+				var payload map[string]any
+				_ = json.Unmarshal(body, &payload)
+				threadName, _ = payload["name"].(string)
+				respBody = `{"id":"src-1","type":11}`
+			case req.Method == http.MethodPost:
+				postPath = req.URL.Path
+				postBody = string(body)
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(respBody)),
+				Header:     http.Header{"Content-Type": []string{"application/json"}},
+			}, nil
+		}),
+	}
+
+	stream := &discordOutboundStream{
+		adapter: &DiscordAdapter{},
+		target:  "ch-1",
+		reply:   &channel.ReplyRef{MessageID: "src-1"},
+		thread:  &channel.ThreadRef{ID: "src-1"},
+		session: session,
+	}
+	err = stream.Push(context.Background(), channel.PreparedStreamEvent{
+		Type:   channel.StreamEventStatus,
+		Status: channel.StreamStatusStarted,
+	})
+	if err != nil {
+		t.Fatalf("push status event: %v", err)
+	}
+
+	if threadName != "how do I deploy?" {
+		t.Fatalf("thread name = %q, want source message text", threadName)
+	}
+	if !strings.HasSuffix(postPath, "/channels/src-1/messages") {
+		t.Fatalf("message posted to %q, want the thread channel", postPath)
+	}
+	if strings.Contains(postBody, "message_reference") {
+		t.Fatalf("thread message should not carry a reply reference: %s", postBody)
+	}
+}
@@ -35,6 +35,7 @@ func (*WebAdapter) Descriptor() channel.Descriptor {
 			Attachments:    true,
 			Streaming:      true,
 			BlockStreaming: true,
+			Local:          true,
 		},
 		TargetSpec: channel.TargetSpec{
 			Format: "bot_id",
@@ -177,6 +177,7 @@ func (*TelegramAdapter) Descriptor() channel.Descriptor {
 			Markdown:       true,
 			Reply:          true,
 			Attachments:    true,
+			MaxAttachments: 10,
 			Media:          true,
 			Streaming:      true,
 			BlockStreaming: true,
@@ -609,23 +610,30 @@ func (a *TelegramAdapter) logTelegramInbound(configID string, msg channel.Inboun
 
 // Send delivers an outbound message to Telegram, handling text, attachments, and replies.
 func (a *TelegramAdapter) Send(ctx context.Context, cfg channel.ChannelConfig, msg channel.PreparedOutboundMessage) error {
+	_, err := a.SendWithReceipt(ctx, cfg, msg)
+	return err
+}
+
+// SendWithReceipt is Send that also returns the Telegram message ID of the
+// text message. Attachment-only messages report no ID.
+func (a *TelegramAdapter) SendWithReceipt(ctx context.Context, cfg channel.ChannelConfig, msg channel.PreparedOutboundMessage) (string, error) {
 	telegramCfg, err := parseConfig(cfg.Credentials)
 	if err != nil {
 		if a.logger != nil {
 			a.logger.Error("decode config failed", slog.String("config_id", cfg.ID), slog.Any("error", err))
 		}
-		return err
+		return "", err
 	}
 	to := strings.TrimSpace(msg.Target)
 	if to == "" {
-		return errors.New("telegram target is required")
+		return "", errors.New("telegram target is required")
 	}
 	bot, err := a.getOrCreateBot(telegramCfg, cfg.ID)
 	if err != nil {
-		return err
+		return "", err
 	}
 	if msg.Message.Message.IsEmpty() {
-		return errors.New("message is required")
+		return "", errors.New("message is required")
 	}
 	text := strings.TrimSpace(msg.Message.Message.PlainText())
 	text, parseMode := formatTelegramOutput(text, msg.Message.Message.Format)
@@ -646,15 +654,15 @@ func (a *TelegramAdapter) Send(ctx context.Context, cfg channel.ChannelConfig, m
 				if a.logger != nil {
 					a.logger.Error("send attachment failed", slog.String("config_id", cfg.ID), slog.Any("error", err))
 				}
-				return err
+				return "", err
 			}
 		}
 		if text != "" && !usedCaption {
-			return sendTelegramText(bot, to, text, replyTo, parseMode)
+			return sendTelegramTextReceipt(bot, to, text, replyTo, parseMode)
 		}
-		return nil
+		return "", nil
 	}
-	return sendTelegramText(bot, to, text, replyTo, parseMode)
+	return sendTelegramTextReceipt(bot, to, text, replyTo, parseMode)
 }
 
 // OpenStream opens a Telegram streaming session.
@@ -781,6 +789,15 @@ func sendTelegramText(bot *tgbotapi.BotAPI, target string, text string, replyTo
 	return err
 }
 
+// sendTelegramTextReceipt sends a text message and returns its message ID.
+func sendTelegramTextReceipt(bot *tgbotapi.BotAPI, target string, text string, replyTo int, parseMode string) (string, error) {
+	_, messageID, err := sendTelegramTextReturnMessage(bot, target, text, replyTo, parseMode)
+	if err != nil {
+		return "", err
+	}
+	return strconv.Itoa(messageID), nil
+}
+
 var sendTextForTest func(bot *tgbotapi.BotAPI, target string, text string, replyTo int, parseMode string) (int64, int, error)
 
 // sendTelegramTextReturnMessage sends a text message and returns the chat ID and message ID for later editing.
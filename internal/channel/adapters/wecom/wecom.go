@@ -69,6 +69,10 @@ func (*WeComAdapter) Descriptor() channel.Descriptor {
 				"readTimeoutSeconds":  {Type: channel.FieldNumber, Title: "Read Timeout Seconds"},
 			},
 		},
+		// Each delta pushes a full stream preview; batch them.
+		OutboundPolicy: channel.OutboundPolicy{
+			StreamFlushIntervalMs: 500,
+		},
 		UserConfigSchema: channel.ConfigSchema{
 			Version: 1,
 			Fields: map[string]channel.FieldSchema{
@@ -278,12 +282,14 @@ func (s *wecomOutboundStream) Push(ctx context.Context, event channel.PreparedSt
 		channel.StreamEventPhaseStart,
 		channel.StreamEventPhaseEnd,
 		channel.StreamEventToolCallStart,
+		channel.StreamEventToolCallDelta,
 		channel.StreamEventToolCallEnd,
 		channel.StreamEventAgentStart,
 		channel.StreamEventAgentEnd,
 		channel.StreamEventProcessingStarted,
 		channel.StreamEventProcessingCompleted,
-		channel.StreamEventProcessingFailed:
+		channel.StreamEventProcessingFailed,
+		channel.StreamEventUnknown:
 		return nil
 	case channel.StreamEventDelta:
 		if strings.TrimSpace(event.Delta) == "" || event.Phase == channel.StreamPhaseReasoning {
@@ -2,6 +2,9 @@ package channel
 
 // ChannelCapabilities describes the feature matrix of a channel type.
 // It is used by the outbound layer to validate message content before delivery.
+// Local marks in-process channels that publish to the route hub themselves.
+// MaxAttachments caps attachments per message; zero means no limit.
+// Ephemeral marks channels that can show a message only to the user it answers.
 type ChannelCapabilities struct {
 	Text           bool     `json:"text"`
 	Markdown       bool     `json:"markdown"`
@@ -18,5 +21,8 @@ type ChannelCapabilities struct {
 	Unsend         bool     `json:"unsend"`
 	NativeCommands bool     `json:"native_commands"`
 	BlockStreaming bool     `json:"block_streaming"`
+	Local          bool     `json:"local"`
+	MaxAttachments int      `json:"max_attachments,omitempty"`
+	Ephemeral      bool     `json:"ephemeral"`
 	ChatTypes      []string `json:"chat_types,omitempty"`
 }
@@ -220,6 +220,50 @@ func (m *Manager) EnsureConnection(ctx context.Context, cfg ChannelConfig) error
 	return m.ensureConnection(ctx, cfg)
 }
 
+// RestartConnection stops the running connection for cfg, if any, and
+// connects it again with cfg. The config is left untouched and the status
+// is kept, so the last event time survives the restart. Connections that
+// cannot be stopped return ErrStopNotSupported and keep running. Bots
+// paused for deletion return ErrBotPendingDeletion.
+func (m *Manager) RestartConnection(ctx context.Context, cfg ChannelConfig) error {
+	if cfg.ID == "" {
+		return errors.New("config id is required")
+	}
+	m.mu.Lock()
+	if _, paused := m.pausedBots[strings.TrimSpace(cfg.BotID)]; paused {
+		m.mu.Unlock()
+		return ErrBotPendingDeletion
+	}
+	entry := m.connections[cfg.ID]
+	delete(m.connections, cfg.ID)
+	m.mu.Unlock()
+
+	if entry != nil && entry.connection != nil {
+		if m.logger != nil {
+			m.logger.Info(
+				"adapter restart requested",
+				slog.String("bot_id", cfg.BotID),
+				slog.String("channel", cfg.ChannelType.String()),
+				slog.String("config_id", cfg.ID),
+			)
+		}
+		if err := entry.connection.Stop(ctx); err != nil {
+			if errors.Is(err, ErrStopNotSupported) {
+				m.mu.Lock()
+				if _, exists := m.connections[cfg.ID]; !exists {
+					m.connections[cfg.ID] = entry
+				}
+				m.mu.Unlock()
+				return err
+			}
+			m.markConnectionStatus(cfg, false, err)
+			return err
+		}
+	}
+	m.markConnectionStatus(cfg, false, nil)
+	return m.ensureConnection(ctx, cfg)
+}
+
 // RemoveConnection stops and removes connections matching the given bot and channel type.
 func (m *Manager) RemoveConnection(ctx context.Context, botID string, channelType ChannelType) {
 	botID = strings.TrimSpace(botID)
@@ -377,6 +421,25 @@ func (m *Manager) StopByBot(ctx context.Context, botID string) error {
 	return nil
 }
 
+// PauseBot stops the bot's connections. They stay stopped across refreshes
+// while the bot is pending deletion, and RestartConnection refuses them
+// until ResumeBot.
+func (m *Manager) PauseBot(ctx context.Context, botID string) error {
+	m.mu.Lock()
+	m.pausedBots[strings.TrimSpace(botID)] = struct{}{}
+	m.mu.Unlock()
+	return m.StopByBot(ctx, botID)
+}
+
+// ResumeBot reconnects the bot's channels after it is undeleted.
+func (m *Manager) ResumeBot(ctx context.Context, botID string) error {
+	m.mu.Lock()
+	delete(m.pausedBots, strings.TrimSpace(botID))
+	m.mu.Unlock()
+	m.refresh(ctx)
+	return nil
+}
+
 func (m *Manager) markConnectionStatus(cfg ChannelConfig, running bool, checkErr error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -397,6 +460,7 @@ func (m *Manager) setConnectionStatusLocked(cfg ChannelConfig, running bool, che
 		ChannelType: cfg.ChannelType,
 		Running:     running,
 		UpdatedAt:   time.Now().UTC(),
+		LastEventAt: previous.LastEventAt,
 	}
 	if checkErr != nil {
 		status.LastError = checkErr.Error()
@@ -422,3 +486,15 @@ func (m *Manager) setConnectionStatusLocked(cfg ChannelConfig, running bool, che
 		}
 	}
 }
+
+// markInboundEvent records that the connection of cfg delivered an inbound
+// message.
+func (m *Manager) markInboundEvent(cfg ChannelConfig) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if status, ok := m.connectionMeta[cfg.ID]; ok {
+		now := time.Now().UTC()
+		status.LastEventAt = &now
+		m.connectionMeta[cfg.ID] = status
+	}
+}
@@ -0,0 +1,206 @@
+package channel
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"testing"
+)
+
+type fakeReceiptAdapter struct {
+	fakeAdapter
+	platformMessageID string
+	sendErr           error
+}
+
+func (f *fakeReceiptAdapter) Descriptor() Descriptor {
+	return Descriptor{
+		Type:           f.channelType,
+		DisplayName:    "Fake receipts",
+		Capabilities:   ChannelCapabilities{Text: true},
+		OutboundPolicy: OutboundPolicy{RetryMax: 1, RetryBackoffMs: 1},
+	}
+}
+
+func (f *fakeReceiptAdapter) SendWithReceipt(ctx context.Context, cfg ChannelConfig, msg PreparedOutboundMessage) (string, error) {
+	if f.sendErr != nil {
+		return "", f.sendErr
+	}
+	if err := f.Send(ctx, cfg, msg); err != nil {
+		return "", err
+	}
+	return f.platformMessageID, nil
+}
+
+type fakeDeliveryRecorder struct {
+	mu       sync.Mutex
+	receipts []DeliveryReceipt
+}
+
+func (f *fakeDeliveryRecorder) RecordDelivery(_ context.Context, receipt DeliveryReceipt) error {
+	f.mu.Lock()
+	f.receipts = append(f.receipts, receipt)
+	f.mu.Unlock()
+	return nil
+}
+
+func newReceiptTestManager(adapter Adapter, recorder DeliveryRecorder) *Manager {
+	store := &fakeConfigStore{
+		effectiveConfig: ChannelConfig{ID: "cfg-1", BotID: "bot-1", ChannelType: ChannelType("test")},
+	}
+	manager := NewManager(slog.New(slog.DiscardHandler), NewRegistry(), store, &fakeInboundProcessorIntegration{})
+	manager.RegisterAdapter(adapter)
+	manager.SetDeliveryRecorder(recorder)
+	return manager
+}
+
+func TestManagerSendRecordsDeliveredReceipt(t *testing.T) {
+	t.Parallel()
+
+	adapter := &fakeReceiptAdapter{fakeAdapter: fakeAdapter{channelType: ChannelType("test")}, platformMessageID: "platform-42"}
+	recorder := &fakeDeliveryRecorder{}
+	manager := newReceiptTestManager(adapter, recorder)
+
+	err := manager.Send(context.Background(), "bot-1", ChannelType("test"), SendRequest{
+		Target:           "chat-1",
+		HistoryMessageID: "msg-1",
+		Message:          Message{Text: "hello"},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	if len(recorder.receipts) != 1 {
+		t.Fatalf("expected 1 receipt, got %d", len(recorder.receipts))
+	}
+	got := recorder.receipts[0]
+	if got.Status != DeliveryStatusDelivered || got.PlatformMessageID != "platform-42" {
+		t.Fatalf("unexpected receipt: %+v", got)
+	}
+	if got.BotID != "bot-1" || got.MessageID != "msg-1" || got.Target != "chat-1" || got.ChannelType != ChannelType("test") {
+		t.Fatalf("unexpected receipt routing: %+v", got)
+	}
+	if got.At.IsZero() {
+		t.Fatal("expected receipt timestamp")
+	}
+}
+
+func TestManagerSendRecordsFailedReceipt(t *testing.T) {
+	t.Parallel()
+
+	adapter := &fakeReceiptAdapter{fakeAdapter: fakeAdapter{channelType: ChannelType("test")}, sendErr: errors.New("chat not found")}
+	recorder := &fakeDeliveryRecorder{}
+	manager := newReceiptTestManager(adapter, recorder)
+
+	err := manager.Send(context.Background(), "bot-1", ChannelType("test"), SendRequest{
+		Target:  "chat-1",
+		Message: Message{Text: "hello"},
+	})
+	if err == nil {
+		t.Fatal("expected send error")
+	}
+
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	if len(recorder.receipts) != 1 {
+		t.Fatalf("expected 1 receipt, got %d", len(recorder.receipts))
+	}
+	got := recorder.receipts[0]
+	if got.Status != DeliveryStatusFailed || got.PlatformMessageID != "" || got.Error == "" {
+		t.Fatalf("unexpected receipt: %+v", got)
+	}
+}
+
+func TestManagerSendWithoutReceiptSenderRecordsEmptyPlatformID(t *testing.T) {
+	t.Parallel()
+
+	adapter := &fakeAdapter{channelType: ChannelType("test")}
+	recorder := &fakeDeliveryRecorder{}
+	manager := newReceiptTestManager(adapter, recorder)
+
+	err := manager.Send(context.Background(), "bot-1", ChannelType("test"), SendRequest{
+		Target:  "chat-1",
+		Message: Message{Text: "hello"},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	if len(recorder.receipts) != 1 || recorder.receipts[0].Status != DeliveryStatusDelivered || recorder.receipts[0].PlatformMessageID != "" {
+		t.Fatalf("unexpected receipts: %+v", recorder.receipts)
+	}
+}
+
+type fakeStreamingReceiptAdapter struct {
+	mockAdapter
+	chunkLimit int
+}
+
+func (f *fakeStreamingReceiptAdapter) Descriptor() Descriptor {
+	desc := f.mockAdapter.Descriptor()
+	desc.OutboundPolicy = OutboundPolicy{TextChunkLimit: f.chunkLimit, RetryMax: 1, RetryBackoffMs: 1}
+	return desc
+}
+
+func pushReplyFinal(t *testing.T, manager *Manager, final StreamFinalizePayload) {
+	t.Helper()
+	sender := manager.newReplySender(ChannelConfig{ID: "cfg-1", BotID: "bot-1", ChannelType: ChannelType("test")}, ChannelType("test"))
+	stream, err := sender.OpenStream(context.Background(), "chat-1", StreamOptions{})
+	if err != nil {
+		t.Fatalf("open stream: %v", err)
+	}
+	if err := stream.Push(context.Background(), StreamEvent{Type: StreamEventFinal, Final: &final}); err != nil {
+		t.Fatalf("push final: %v", err)
+	}
+	if err := stream.Close(context.Background()); err != nil {
+		t.Fatalf("close stream: %v", err)
+	}
+}
+
+func TestReplyStreamFinalRecordsLinkedReceipt(t *testing.T) {
+	t.Parallel()
+
+	recorder := &fakeDeliveryRecorder{}
+	manager := newReceiptTestManager(&fakeStreamingReceiptAdapter{chunkLimit: 100}, recorder)
+
+	pushReplyFinal(t, manager, StreamFinalizePayload{Message: Message{Text: "hello"}, HistoryMessageID: "msg-7"})
+
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	if len(recorder.receipts) != 1 {
+		t.Fatalf("expected 1 receipt, got %+v", recorder.receipts)
+	}
+	got := recorder.receipts[0]
+	if got.MessageID != "msg-7" || got.Status != DeliveryStatusDelivered || got.Target != "chat-1" || got.BotID != "bot-1" {
+		t.Fatalf("unexpected receipt: %+v", got)
+	}
+}
+
+func TestReplyStreamChunkedFinalLinksEveryReceipt(t *testing.T) {
+	t.Parallel()
+
+	adapter := &fakeStreamingReceiptAdapter{chunkLimit: 10}
+	recorder := &fakeDeliveryRecorder{}
+	manager := newReceiptTestManager(adapter, recorder)
+
+	pushReplyFinal(t, manager, StreamFinalizePayload{
+		Message:          Message{Text: "first part. second part. third part."},
+		HistoryMessageID: "msg-8",
+	})
+
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	if len(adapter.sentMessages) < 2 || len(recorder.receipts) != len(adapter.sentMessages) {
+		t.Fatalf("expected one receipt per chunk, got %d receipts for %d messages", len(recorder.receipts), len(adapter.sentMessages))
+	}
+	for _, got := range recorder.receipts {
+		if got.MessageID != "msg-8" || got.Status != DeliveryStatusDelivered {
+			t.Fatalf("expected receipt linked to msg-8, got %+v", got)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package channel
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+)
+
+type fakeEphemeralAdapter struct {
+	fakeAdapter
+}
+
+func (f *fakeEphemeralAdapter) Descriptor() Descriptor {
+	return Descriptor{
+		Type:         f.channelType,
+		DisplayName:  "Fake ephemeral",
+		Capabilities: ChannelCapabilities{Text: true, Ephemeral: true},
+	}
+}
+
+func sendEphemeralThrough(t *testing.T, adapter Adapter) {
+	t.Helper()
+	store := &fakeConfigStore{
+		effectiveConfig: ChannelConfig{ID: "cfg-1", BotID: "bot-1", ChannelType: ChannelType("test")},
+	}
+	manager := NewManager(slog.New(slog.DiscardHandler), NewRegistry(), store, &fakeInboundProcessorIntegration{})
+	manager.RegisterAdapter(adapter)
+
+	err := manager.Send(context.Background(), "bot-1", ChannelType("test"), SendRequest{
+		Target:  "chat-1",
+		Message: Message{Text: "Error: not allowed", Ephemeral: true},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
+
+func TestManagerSendKeepsEphemeralWhenSupported(t *testing.T) {
+	t.Parallel()
+
+	adapter := &fakeEphemeralAdapter{fakeAdapter: fakeAdapter{channelType: ChannelType("test")}}
+	sendEphemeralThrough(t, adapter)
+	adapter.mu.Lock()
+	defer adapter.mu.Unlock()
+	if len(adapter.sent) != 1 || !adapter.sent[0].Message.Ephemeral {
+		t.Fatalf("expected an ephemeral message, got %+v", adapter.sent)
+	}
+}
+
+func TestManagerSendDropsEphemeralWhenUnsupported(t *testing.T) {
+	t.Parallel()
+
+	adapter := &fakeAdapter{channelType: ChannelType("test")}
+	sendEphemeralThrough(t, adapter)
+	adapter.mu.Lock()
+	defer adapter.mu.Unlock()
+	if len(adapter.sent) != 1 {
+		t.Fatalf("expected the message to be sent normally, got %+v", adapter.sent)
+	}
+	if adapter.sent[0].Message.Ephemeral || adapter.sent[0].Message.Text != "Error: not allowed" {
+		t.Fatalf("expected a normal message, got %+v", adapter.sent[0].Message)
+	}
+}
+
+func TestStreamFinalDropsEphemeralWhenUnsupported(t *testing.T) {
+	t.Parallel()
+
+	for _, supported := range []bool{true, false} {
+		registry := NewRegistry()
+		if supported {
+			registry.MustRegister(&fakeEphemeralAdapter{fakeAdapter: fakeAdapter{channelType: ChannelType("test")}})
+		} else {
+			registry.MustRegister(&fakeAdapter{channelType: ChannelType("test")})
+		}
+		rec := &recordingStream{}
+		stream := &managerOutboundStream{
+			manager:     &Manager{registry: registry},
+			stream:      rec,
+			channelType: ChannelType("test"),
+		}
+		err := stream.Push(context.Background(), StreamEvent{
+			Type:  StreamEventFinal,
+			Final: &StreamFinalizePayload{Message: Message{Text: "done", Ephemeral: true}},
+		})
+		if err != nil {
+			t.Fatalf("push final: %v", err)
+		}
+		if len(rec.events) != 1 || rec.events[0].Final.Message.Ephemeral != supported {
+			t.Fatalf("supported=%v: unexpected final %+v", supported, rec.events)
+		}
+	}
+}
@@ -98,6 +98,7 @@ func (s *Service) Canonicalize(ctx context.Context, channelIdentityID string) (s
 
 // ResolveByChannelIdentity looks up or creates a channel identity for (channel, channel_subject_id).
 // Optional meta may contain avatar_url which is stored as a dedicated column.
+// The upsert is retried briefly on connection errors.
 func (s *Service) ResolveByChannelIdentity(ctx context.Context, channel, channelSubjectID, displayName string, meta map[string]any) (ChannelIdentity, error) {
 	if s.queries == nil {
 		return ChannelIdentity{}, errors.New("channel identity queries not configured")
@@ -115,13 +116,15 @@ func (s *Service) ResolveByChannelIdentity(ctx context.Context, channel, channel
 		}
 	}
 
-	row, err := s.queries.UpsertChannelIdentityByChannelSubject(ctx, sqlc.UpsertChannelIdentityByChannelSubjectParams{
-		UserID:           pgtype.UUID{},
-		ChannelType:      channel,
-		ChannelSubjectID: channelSubjectID,
-		DisplayName:      toPgText(displayName),
-		AvatarUrl:        toPgText(avatarURL),
-		Metadata:         emptyMetadataBytes(),
+	row, err := db.Retry(ctx, func(ctx context.Context) (sqlc.ChannelIdentity, error) {
+		return s.queries.UpsertChannelIdentityByChannelSubject(ctx, sqlc.UpsertChannelIdentityByChannelSubjectParams{
+			UserID:           pgtype.UUID{},
+			ChannelType:      channel,
+			ChannelSubjectID: channelSubjectID,
+			DisplayName:      toPgText(displayName),
+			AvatarUrl:        toPgText(avatarURL),
+			Metadata:         emptyMetadataBytes(),
+		})
 	})
 	if err != nil {
 		return ChannelIdentity{}, err
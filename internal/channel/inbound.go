@@ -20,6 +20,7 @@ func (m *Manager) HandleInbound(ctx context.Context, cfg ChannelConfig, msg Inbo
 	if m.inboundCtx != nil && m.inboundCtx.Err() != nil {
 		return errors.New("inbound dispatcher stopped")
 	}
+	m.markInboundEvent(cfg)
 	task := inboundTask{
 		cfg: cfg,
 		msg: msg,
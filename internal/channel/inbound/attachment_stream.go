@@ -0,0 +1,160 @@
+package inbound
+
+import (
+	"context"
+	"strings"
+
+	"github.com/memohai/memoh/internal/channel"
+)
+
+// attachmentFallbackStream adapts a reply stream for channels whose
+// descriptor does not declare attachment support. Attachments are never
+// forwarded: those with an HTTP URL are posted as links in the next final
+// message, or in a final message of their own when the reply completes
+// without one; the rest are dropped. It is driven by a single goroutine.
+type attachmentFallbackStream struct {
+	target  channel.OutboundStream
+	pending []channel.Attachment
+}
+
+// wrapAttachmentStream adapts target to the channel's attachment
+// capabilities: links replace attachments when they are unsupported, and
+// final messages are split when they exceed the per-message limit.
+func wrapAttachmentStream(target channel.OutboundStream, caps channel.ChannelCapabilities) channel.OutboundStream {
+	if !caps.Attachments {
+		return &attachmentFallbackStream{target: target}
+	}
+	if caps.MaxAttachments > 0 {
+		return &attachmentLimitStream{target: target, limit: caps.MaxAttachments}
+	}
+	return target
+}
+
+func (s *attachmentFallbackStream) Push(ctx context.Context, event channel.StreamEvent) error {
+	switch event.Type {
+	case channel.StreamEventAttachment:
+		s.pending = append(s.pending, linkableAttachments(event.Attachments)...)
+		return nil
+	case channel.StreamEventFinal:
+		if event.Final != nil {
+			msg := event.Final.Message
+			s.pending = append(s.pending, linkableAttachments(msg.Attachments)...)
+			msg.Attachments = nil
+			msg = appendAttachmentLinks(msg, s.pending)
+			s.pending = nil
+			if msg.IsEmpty() {
+				return nil
+			}
+			event.Final = &channel.StreamFinalizePayload{Message: msg, HistoryMessageID: event.Final.HistoryMessageID}
+		}
+	case channel.StreamEventStatus:
+		if event.Status == channel.StreamStatusCompleted && len(s.pending) > 0 {
+			msg := appendAttachmentLinks(channel.Message{}, s.pending)
+			s.pending = nil
+			if err := s.target.Push(ctx, channel.StreamEvent{
+				Type:  channel.StreamEventFinal,
+				Final: &channel.StreamFinalizePayload{Message: msg},
+			}); err != nil {
+				return err
+			}
+		}
+	}
+	return s.target.Push(ctx, event)
+}
+
+func (s *attachmentFallbackStream) Close(ctx context.Context) error {
+	return s.target.Close(ctx)
+}
+
+// linkableAttachments keeps the attachments that can be shared as a link.
+func linkableAttachments(attachments []channel.Attachment) []channel.Attachment {
+	out := make([]channel.Attachment, 0, len(attachments))
+	for _, att := range attachments {
+		if channel.IsHTTPURL(strings.TrimSpace(att.URL)) {
+			out = append(out, att)
+		}
+	}
+	return out
+}
+
+// appendAttachmentLinks adds one link per attachment to msg, as link parts
+// for rich messages and as text lines otherwise.
+func appendAttachmentLinks(msg channel.Message, attachments []channel.Attachment) channel.Message {
+	if len(attachments) == 0 {
+		return msg
+	}
+	if len(msg.Parts) > 0 {
+		for _, att := range attachments {
+			msg.Parts = append(msg.Parts, channel.MessagePart{
+				Type: channel.MessagePartLink,
+				Text: strings.TrimSpace(att.Name),
+				URL:  strings.TrimSpace(att.URL),
+			})
+		}
+		return msg
+	}
+	lines := make([]string, 0, len(attachments)+1)
+	if text := strings.TrimSpace(msg.Text); text != "" {
+		lines = append(lines, text, "")
+	}
+	for _, att := range attachments {
+		link := strings.TrimSpace(att.URL)
+		if name := strings.TrimSpace(att.Name); name != "" {
+			link = name + ": " + link
+		}
+		lines = append(lines, link)
+	}
+	msg.Text = strings.Join(lines, "\n")
+	return msg
+}
+
+// attachmentLimitStream splits final messages that carry more attachments
+// than the channel accepts per message. The first message keeps the text and
+// the leading attachments; each following message carries the next batch.
+type attachmentLimitStream struct {
+	target channel.OutboundStream
+	limit  int
+}
+
+func (s *attachmentLimitStream) Push(ctx context.Context, event channel.StreamEvent) error {
+	if event.Type != channel.StreamEventFinal || event.Final == nil || len(event.Final.Message.Attachments) <= s.limit {
+		return s.target.Push(ctx, event)
+	}
+	for _, msg := range splitMessageAttachments(event.Final.Message, s.limit) {
+		if err := s.target.Push(ctx, channel.StreamEvent{
+			Type:  channel.StreamEventFinal,
+			Final: &channel.StreamFinalizePayload{Message: msg, HistoryMessageID: event.Final.HistoryMessageID},
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *attachmentLimitStream) Close(ctx context.Context) error {
+	return s.target.Close(ctx)
+}
+
+// splitMessageAttachments splits msg into messages of at most limit
+// attachments. Follow-up messages keep only the reply and thread references
+// and the ephemeral flag.
+func splitMessageAttachments(msg channel.Message, limit int) []channel.Message {
+	attachments := msg.Attachments
+	if limit <= 0 || len(attachments) <= limit {
+		return []channel.Message{msg}
+	}
+	out := make([]channel.Message, 0, (len(attachments)+limit-1)/limit)
+	first := msg
+	first.Attachments = attachments[:limit]
+	out = append(out, first)
+	for start := limit; start < len(attachments); start += limit {
+		end := min(start+limit, len(attachments))
+		out = append(out, channel.Message{
+			Attachments: attachments[start:end],
+			Reply:       msg.Reply,
+			Thread:      msg.Thread,
+			Ephemeral:   msg.Ephemeral,
+		})
+	}
+	return out
+}
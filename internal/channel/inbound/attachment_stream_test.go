@@ -0,0 +1,157 @@
+package inbound
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/memohai/memoh/internal/channel"
+)
+
+func pushAll(t *testing.T, stream channel.OutboundStream, events ...channel.StreamEvent) {
+	t.Helper()
+	for _, event := range events {
+		if err := stream.Push(context.Background(), event); err != nil {
+			t.Fatalf("push %s: %v", event.Type, err)
+		}
+	}
+}
+
+func TestAttachmentFallback_CapableChannelKeepsAttachments(t *testing.T) {
+	t.Parallel()
+
+	sender := &fakeReplySender{}
+	stream := wrapAttachmentStream(&fakeOutboundStream{sender: sender}, channel.ChannelCapabilities{Text: true, Attachments: true})
+	pushAll(t, stream,
+		channel.StreamEvent{Type: channel.StreamEventAttachment, Attachments: []channel.Attachment{{Type: channel.AttachmentImage, URL: "https://example.com/a.png"}}},
+		channel.StreamEvent{Type: channel.StreamEventFinal, Final: &channel.StreamFinalizePayload{Message: channel.Message{Text: "done"}}},
+	)
+	if len(sender.events) != 2 || sender.events[0].Type != channel.StreamEventAttachment {
+		t.Fatalf("expected attachment event to pass through, got %+v", sender.events)
+	}
+	if sender.events[1].Final.Message.Text != "done" {
+		t.Fatalf("expected final text unchanged, got %q", sender.events[1].Final.Message.Text)
+	}
+}
+
+func TestAttachmentFallback_TextOnlyChannelPostsLinks(t *testing.T) {
+	t.Parallel()
+
+	sender := &fakeReplySender{}
+	stream := wrapAttachmentStream(&fakeOutboundStream{sender: sender}, channel.ChannelCapabilities{Text: true})
+	pushAll(t, stream,
+		channel.StreamEvent{Type: channel.StreamEventAttachment, Attachments: []channel.Attachment{
+			{Type: channel.AttachmentImage, URL: "https://example.com/a.png", Name: "chart.png"},
+			{Type: channel.AttachmentFile, URL: "/data/media/report.pdf"},
+		}},
+		channel.StreamEvent{Type: channel.StreamEventFinal, Final: &channel.StreamFinalizePayload{Message: channel.Message{
+			Text:        "Here you go",
+			Attachments: []channel.Attachment{{Type: channel.AttachmentFile, URL: "https://example.com/b.txt"}},
+		}}},
+	)
+	if len(sender.sent) != 1 {
+		t.Fatalf("expected one final message, got %+v", sender.events)
+	}
+	got := sender.sent[0].Message
+	if len(got.Attachments) != 0 {
+		t.Fatalf("expected attachments to be removed, got %+v", got.Attachments)
+	}
+	want := "Here you go\n\nchart.png: https://example.com/a.png\nhttps://example.com/b.txt"
+	if got.Text != want {
+		t.Fatalf("unexpected final text:\n%q\nwant\n%q", got.Text, want)
+	}
+	for _, event := range sender.events {
+		if event.Type == channel.StreamEventAttachment {
+			t.Fatal("expected attachment event to be withheld")
+		}
+	}
+}
+
+func TestAttachmentFallback_TextOnlyChannelFlushesLinksOnCompletion(t *testing.T) {
+	t.Parallel()
+
+	sender := &fakeReplySender{}
+	stream := wrapAttachmentStream(&fakeOutboundStream{sender: sender}, channel.ChannelCapabilities{Text: true})
+	pushAll(t, stream,
+		channel.StreamEvent{Type: channel.StreamEventAttachment, Attachments: []channel.Attachment{{Type: channel.AttachmentImage, URL: "https://example.com/a.png"}}},
+		channel.StreamEvent{Type: channel.StreamEventStatus, Status: channel.StreamStatusCompleted},
+	)
+	if len(sender.events) != 2 || sender.events[1].Type != channel.StreamEventStatus {
+		t.Fatalf("expected a links final before completion, got %+v", sender.events)
+	}
+	if len(sender.sent) != 1 || sender.sent[0].Message.Text != "https://example.com/a.png" {
+		t.Fatalf("unexpected links message: %+v", sender.sent)
+	}
+}
+
+func testAttachments(n int) []channel.Attachment {
+	out := make([]channel.Attachment, 0, n)
+	for i := range n {
+		out = append(out, channel.Attachment{Type: channel.AttachmentImage, URL: fmt.Sprintf("https://example.com/%d.png", i)})
+	}
+	return out
+}
+
+func TestSplitMessageAttachments_CapBoundary(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		count int
+		want  []int
+	}{
+		{count: 0, want: []int{0}},
+		{count: 3, want: []int{3}},
+		{count: 4, want: []int{3, 1}},
+		{count: 6, want: []int{3, 3}},
+		{count: 7, want: []int{3, 3, 1}},
+	}
+	for _, tc := range cases {
+		msg := channel.Message{Text: "album", Attachments: testAttachments(tc.count)}
+		got := splitMessageAttachments(msg, 3)
+		if len(got) != len(tc.want) {
+			t.Fatalf("count %d: expected %d messages, got %d", tc.count, len(tc.want), len(got))
+		}
+		next := 0
+		for i, part := range got {
+			if len(part.Attachments) != tc.want[i] {
+				t.Fatalf("count %d: message %d has %d attachments, want %d", tc.count, i, len(part.Attachments), tc.want[i])
+			}
+			for _, att := range part.Attachments {
+				if want := fmt.Sprintf("https://example.com/%d.png", next); att.URL != want {
+					t.Fatalf("count %d: attachment out of order: got %s, want %s", tc.count, att.URL, want)
+				}
+				next++
+			}
+			if (i == 0) != (part.Text == "album") {
+				t.Fatalf("count %d: message %d has text %q", tc.count, i, part.Text)
+			}
+		}
+	}
+}
+
+func TestAttachmentStream_SplitsFinalOverLimit(t *testing.T) {
+	t.Parallel()
+
+	sender := &fakeReplySender{}
+	stream := wrapAttachmentStream(&fakeOutboundStream{sender: sender}, channel.ChannelCapabilities{Text: true, Attachments: true, MaxAttachments: 2})
+	reply := &channel.ReplyRef{Target: "chat", MessageID: "m1"}
+	pushAll(t, stream,
+		channel.StreamEvent{Type: channel.StreamEventFinal, Final: &channel.StreamFinalizePayload{Message: channel.Message{Text: "two", Attachments: testAttachments(2), Reply: reply}}},
+		channel.StreamEvent{Type: channel.StreamEventFinal, Final: &channel.StreamFinalizePayload{Message: channel.Message{Text: "five", Attachments: testAttachments(5), Reply: reply}}},
+	)
+	if len(sender.sent) != 4 {
+		t.Fatalf("expected 4 messages, got %d", len(sender.sent))
+	}
+	for i, want := range []int{2, 2, 2, 1} {
+		got := sender.sent[i].Message
+		if len(got.Attachments) != want {
+			t.Fatalf("message %d has %d attachments, want %d", i, len(got.Attachments), want)
+		}
+		if got.Reply != reply {
+			t.Fatalf("message %d lost its reply reference", i)
+		}
+	}
+	if sender.sent[1].Message.Text != "five" || sender.sent[2].Message.Text != "" {
+		t.Fatalf("expected text only on the first split message, got %q / %q", sender.sent[1].Message.Text, sender.sent[2].Message.Text)
+	}
+}
@@ -33,9 +33,14 @@ import (
 var base64Std = base64.StdEncoding
 
 const (
-	silentReplyToken        = "NO_REPLY"
-	minDuplicateTextLength  = 10
-	processingStatusTimeout = 60 * time.Second
+	silentReplyToken       = "NO_REPLY"
+	minDuplicateTextLength = 10
+	// spendCapNotice replies to messages a bot cannot answer because it has
+	// reached its monthly spend cap.
+	spendCapNotice = "This bot has reached its monthly usage limit and will reply again next month."
+	// providerQuotaNotice replies to messages a bot cannot answer because its
+	// model provider account is out of quota or credit.
+	providerQuotaNotice = "This bot's model provider has run out of quota or credit. Please ask the bot owner to top up the account or switch to another model."
 )
 
 var whitespacePattern = regexp.MustCompile(`\s+`)
@@ -154,6 +159,14 @@ func (p *ChannelInboundProcessor) SetACLService(service chatACL) {
 	p.acl = service
 }
 
+// SetBlockList configures the per-bot sender block list used during identity resolution.
+func (p *ChannelInboundProcessor) SetBlockList(blockList SenderBlockList) {
+	if p == nil {
+		return
+	}
+	p.identity.SetBlockList(blockList)
+}
+
 // IdentityMiddleware returns the identity resolution middleware.
 func (p *ChannelInboundProcessor) IdentityMiddleware() channel.Middleware {
 	if p == nil || p.identity == nil {
@@ -317,7 +330,11 @@ func (p *ChannelInboundProcessor) HandleInbound(ctx context.Context, cfg channel
 			ThreadID:          extractThreadID(msg),
 		})
 		if err != nil {
-			reply = "Error: " + err.Error()
+			// Command failures only concern the invoking user.
+			return sender.Send(ctx, channel.OutboundMessage{
+				Target:  strings.TrimSpace(msg.ReplyTarget),
+				Message: ephemeralMessage(msg, "Error: "+err.Error()),
+			})
 		}
 		return sender.Send(ctx, channel.OutboundMessage{
 			Target:  strings.TrimSpace(msg.ReplyTarget),
@@ -333,7 +350,7 @@ func (p *ChannelInboundProcessor) HandleInbound(ctx context.Context, cfg channel
 	// Only applies to non-local channels; WebUI always uses the default flow.
 	// Must run after buildInboundQuery so the prefix is stripped from the final text.
 	inboundMode := ModeInject
-	if !isLocalChannelType(msg.Channel) {
+	if !p.isLocalChannel(msg.Channel) {
 		inboundMode, text = DetectMode(text)
 	}
 	threadID := extractThreadID(msg)
@@ -342,7 +359,8 @@ func (p *ChannelInboundProcessor) HandleInbound(ctx context.Context, cfg channel
 	if p.routeResolver == nil {
 		return errors.New("route resolver not configured")
 	}
-	routeMetadata := buildRouteMetadata(msg, identity)
+	senderAttributes := p.senderAttributes(cfg, msg)
+	routeMetadata := buildRouteMetadata(msg, identity, senderAttributes)
 	p.enrichConversationAvatar(ctx, cfg, msg, routeMetadata)
 	resolved, err := p.routeResolver.ResolveConversation(ctx, route.ResolveInput{
 		BotID:             identity.BotID,
@@ -404,7 +422,7 @@ func (p *ChannelInboundProcessor) HandleInbound(ctx context.Context, cfg channel
 	}
 
 	if !aclAllowed {
-		p.persistPassiveMessage(ctx, identity, msg, text, attachments, resolved.RouteID, sessionID, "")
+		p.persistPassiveMessage(ctx, identity, msg, text, attachments, senderAttributes, resolved.RouteID, sessionID, "")
 		if p.logger != nil {
 			p.logger.Info(
 				"inbound denied by acl — event not ingested",
@@ -442,6 +460,20 @@ func (p *ChannelInboundProcessor) HandleInbound(ctx context.Context, cfg channel
 		latestRC = p.pipeline.PushEvent(sessionID, event)
 	}
 
+	// Muted routes keep recording the conversation but never reply.
+	if resolved.Muted {
+		p.persistPassiveMessage(ctx, identity, msg, text, attachments, senderAttributes, resolved.RouteID, sessionID, eventID)
+		if p.logger != nil {
+			p.logger.Info(
+				"inbound not triggering assistant (route muted)",
+				slog.String("channel", msg.Channel.String()),
+				slog.String("bot_id", strings.TrimSpace(identity.BotID)),
+				slog.String("route_id", strings.TrimSpace(resolved.RouteID)),
+			)
+		}
+		return nil
+	}
+
 	// Discuss mode: dispatch to the discuss driver and return.
 	// The discuss driver autonomously decides whether to call the LLM.
 	if sessionType == sessionpkg.TypeDiscuss && p.discussDriver != nil && latestRC != nil {
@@ -454,7 +486,7 @@ func (p *ChannelInboundProcessor) HandleInbound(ctx context.Context, cfg channel
 			ConversationType:  strings.TrimSpace(msg.Conversation.Type),
 			ConversationName:  strings.TrimSpace(msg.Conversation.Name),
 		})
-		p.persistPassiveMessage(ctx, identity, msg, text, attachments, resolved.RouteID, sessionID, eventID)
+		p.persistPassiveMessage(ctx, identity, msg, text, attachments, senderAttributes, resolved.RouteID, sessionID, eventID)
 		return nil
 	}
 
@@ -467,7 +499,7 @@ func (p *ChannelInboundProcessor) HandleInbound(ctx context.Context, cfg channel
 	shouldTrigger := shouldTriggerAssistantResponse(msg) || identity.ForceReply
 
 	if !shouldTrigger {
-		p.persistPassiveMessage(ctx, identity, msg, text, attachments, resolved.RouteID, sessionID, eventID)
+		p.persistPassiveMessage(ctx, identity, msg, text, attachments, senderAttributes, resolved.RouteID, sessionID, eventID)
 		if p.logger != nil {
 			p.logger.Info(
 				"inbound not triggering assistant (group trigger condition not met)",
@@ -489,7 +521,7 @@ func (p *ChannelInboundProcessor) HandleInbound(ctx context.Context, cfg channel
 	// --- Dispatcher-based mode handling (inject / queue) ---
 	// For non-parallel modes, when a route already has an active agent stream,
 	// short-circuit here instead of starting a new stream.
-	if p.dispatcher != nil && !isLocalChannelType(msg.Channel) && inboundMode != ModeParallel {
+	if p.dispatcher != nil && !p.isLocalChannel(msg.Channel) && inboundMode != ModeParallel {
 		if p.dispatcher.IsActive(routeID) {
 			headerifiedText := flow.FormatUserHeader(flow.UserMessageHeaderInput{
 				MessageID:         strings.TrimSpace(msg.Message.ID),
@@ -501,6 +533,7 @@ func (p *ChannelInboundProcessor) HandleInbound(ctx context.Context, cfg channel
 				Target:            strings.TrimSpace(msg.ReplyTarget),
 				AttachmentPaths:   collectAttachmentPaths(attachments),
 				Time:              time.Now().UTC(),
+				SenderAttributes:  senderAttributes,
 			}, text)
 
 			switch inboundMode {
@@ -525,7 +558,7 @@ func (p *ChannelInboundProcessor) HandleInbound(ctx context.Context, cfg channel
 				return nil
 
 			case ModeQueue:
-				p.persistPassiveMessage(ctx, identity, msg, text, attachments, routeID, sessionID, eventID)
+				p.persistPassiveMessage(ctx, identity, msg, text, attachments, senderAttributes, routeID, sessionID, eventID)
 				p.dispatcher.Enqueue(routeID, QueuedTask{
 					Ctx:     ctx,
 					Cfg:     cfg,
@@ -592,9 +625,12 @@ startStream:
 		token = "Bearer " + chatToken
 	}
 
-	var desc channel.Descriptor
+	var (
+		desc    channel.Descriptor
+		hasDesc bool
+	)
 	if p.registry != nil {
-		desc, _ = p.registry.GetDescriptor(msg.Channel) //nolint:errcheck // descriptor lookup is best-effort
+		desc, hasDesc = p.registry.GetDescriptor(msg.Channel)
 	}
 	statusInfo := channel.ProcessingStatusInfo{
 		BotID:             identity.BotID,
@@ -627,12 +663,15 @@ startStream:
 		return err
 	}
 	sourceMessageID := strings.TrimSpace(msg.Message.ID)
-	replyRef := &channel.ReplyRef{Target: target}
-	if sourceMessageID != "" {
-		replyRef.MessageID = sourceMessageID
+	replyMode, err := channel.ReplyModeFromRouting(cfg.Routing)
+	if err != nil && p.logger != nil {
+		p.logger.Warn("invalid reply mode, replying to message",
+			slog.String("channel", msg.Channel.String()), slog.Any("error", err))
 	}
+	replyRef, threadRef := buildReplyRefs(replyMode, desc.Capabilities, target, sourceMessageID, threadID)
 	stream, err := sender.OpenStream(ctx, target, channel.StreamOptions{
 		Reply:           replyRef,
+		Thread:          threadRef,
 		SourceMessageID: sourceMessageID,
 		Metadata: map[string]any{
 			"route_id":          resolved.RouteID,
@@ -647,6 +686,13 @@ startStream:
 		}
 		return err
 	}
+	// Adapt attachments to what the channel can carry (links instead of
+	// files, or several messages under a per-message cap) so they never fail
+	// the reply. The tee below still mirrors the original message to local
+	// subscribers.
+	if hasDesc {
+		stream = wrapAttachmentStream(stream, desc.Capabilities)
+	}
 	streamClosed := false
 	closeStream := func() error {
 		if streamClosed {
@@ -677,11 +723,16 @@ startStream:
 
 	// For non-local channels, wrap the stream so events are mirrored to the
 	// RouteHub (and thus to Web UI and other local subscribers).
-	if p.observer != nil && !isLocalChannelType(msg.Channel) {
+	if p.observer != nil && !p.isLocalChannel(msg.Channel) {
 		stream = channel.NewTeeStream(stream, p.observer, strings.TrimSpace(identity.BotID), msg.Channel)
 		// Broadcast the inbound user message so WebUI can display it.
 		p.broadcastInboundMessage(ctx, strings.TrimSpace(identity.BotID), msg, text, identity, resolvedAttachments)
 	}
+	// Buffer the reply stream so a slow platform does not stall reading the
+	// chat stream; text deltas are merged while the platform catches up, and
+	// held for the channel's flush interval when it sets one.
+	stream = newBufferedStream(ctx, stream, defaultStreamBufferSize,
+		time.Duration(desc.OutboundPolicy.StreamFlushIntervalMs)*time.Millisecond)
 
 	if err := stream.Push(ctx, channel.StreamEvent{
 		Type:   channel.StreamEventStatus,
@@ -708,13 +759,24 @@ startStream:
 		copy(result, outboundAssetRefs)
 		return result
 	}
+	// IDs of the stored assistant messages behind each reply, recorded by
+	// the resolver at persist time so delivery receipts link to them.
+	var (
+		outputIDsMu sync.Mutex
+		outputIDs   []string
+	)
+	outputRecorder := func(ids []string) {
+		outputIDsMu.Lock()
+		outputIDs = ids
+		outputIDsMu.Unlock()
+	}
 
 	// Mark this route as active in the dispatcher so subsequent messages
 	// can be injected or queued. Produces the inject channel for this stream.
 	// Parallel mode (/now) skips the dispatcher entirely — it must not
 	// interfere with the active flag or drain the queue of another stream.
 	var injectCh <-chan conversation.InjectMessage
-	if p.dispatcher != nil && !isLocalChannelType(msg.Channel) && inboundMode != ModeParallel {
+	if p.dispatcher != nil && !p.isLocalChannel(msg.Channel) && inboundMode != ModeParallel {
 		injectCh = p.dispatcher.MarkActive(routeID)
 		defer func() {
 			p.drainQueue(context.WithoutCancel(ctx), routeID)
@@ -735,12 +797,16 @@ startStream:
 		ReplyTarget:             target,
 		ConversationType:        msg.Conversation.Type,
 		ConversationName:        msg.Conversation.Name,
+		ConversationNotes:       resolved.Annotations.Notes,
+		ConversationTags:        resolved.Annotations.Tags,
+		SenderAttributes:        senderAttributes,
 		Query:                   text,
 		CurrentChannel:          msg.Channel.String(),
 		Channels:                []string{msg.Channel.String()},
 		UserMessagePersisted:    false,
 		Attachments:             attachments,
 		OutboundAssetCollector:  assetCollector,
+		AssistantOutputRecorder: outputRecorder,
 		EventID:                 eventID,
 	}
 	if injectCh != nil {
@@ -761,6 +827,7 @@ startStream:
 	defer p.activeStreams.Delete(streamKey)
 
 	chunkCh, streamErrCh := p.runner.StreamChat(streamCtx, chatReq)
+	toolInputs := newToolInputAccumulator()
 
 	var (
 		finalMessages []conversation.ModelMessage
@@ -773,7 +840,7 @@ startStream:
 				chunkCh = nil
 				continue
 			}
-			events, messages, parseErr := mapStreamChunkToChannelEvents(chunk)
+			events, messages, parseErr := mapStreamChunkToChannelEvents(chunk, toolInputs)
 			if parseErr != nil {
 				if p.logger != nil {
 					p.logger.Warn(
@@ -798,6 +865,13 @@ startStream:
 					p.dispatchReactions(ctx, identity.BotID, msg.Channel, target, sourceMessageID, event.Reactions)
 					continue
 				}
+				if event.Type == channel.StreamEventUnknown && p.logger != nil {
+					p.logger.Debug(
+						"unknown stream event type",
+						slog.String("channel", msg.Channel.String()),
+						slog.Any("type", event.Metadata["type"]),
+					)
+				}
 				if event.Type == channel.StreamEventSpeech && len(event.Speeches) > 0 {
 					p.synthesizeAndPushVoice(ctx, strings.TrimSpace(identity.BotID), msg.Channel, event.Speeches, stream, &outboundAssetRefs, &assetMu)
 					continue
@@ -824,6 +898,39 @@ startStream:
 		}
 	}
 
+	if noticeText, ok := chatErrorNotice(streamErr); ok {
+		if p.logger != nil {
+			p.logger.Warn(
+				"chat answered with error notice",
+				slog.String("channel", msg.Channel.String()),
+				slog.String("bot_id", identity.BotID),
+				slog.Any("error", streamErr),
+			)
+		}
+		notice := channel.Message{Text: noticeText}
+		applyReplyRefs(&notice, replyRef, threadRef)
+		if err := stream.Push(ctx, channel.StreamEvent{
+			Type:  channel.StreamEventFinal,
+			Final: &channel.StreamFinalizePayload{Message: notice},
+		}); err != nil {
+			return err
+		}
+		if err := stream.Push(ctx, channel.StreamEvent{
+			Type:   channel.StreamEventStatus,
+			Status: channel.StreamStatusCompleted,
+		}); err != nil {
+			return err
+		}
+		if err := closeStream(); err != nil {
+			return err
+		}
+		if statusNotifier != nil {
+			if notifyErr := p.notifyProcessingCompleted(ctx, statusNotifier, cfg, msg, statusInfo, statusHandle); notifyErr != nil {
+				p.logProcessingStatusError("processing_completed", msg, identity, notifyErr)
+			}
+		}
+		return nil
+	}
 	if streamErr != nil {
 		if p.logger != nil {
 			p.logger.Error(
@@ -871,7 +978,10 @@ startStream:
 	}
 
 	outputs := flow.ExtractAssistantOutputs(finalMessages)
-	for _, output := range outputs {
+	outputIDsMu.Lock()
+	historyMessageIDs := outputIDs
+	outputIDsMu.Unlock()
+	for i, output := range outputs {
 		outMessage := buildChannelMessage(output, desc.Capabilities)
 		if outMessage.IsEmpty() {
 			continue
@@ -883,16 +993,16 @@ startStream:
 		if isMessagingToolDuplicate(plainText, sentTexts) {
 			continue
 		}
-		if outMessage.Reply == nil && sourceMessageID != "" {
-			outMessage.Reply = &channel.ReplyRef{
-				Target:    target,
-				MessageID: sourceMessageID,
-			}
+		applyReplyRefs(&outMessage, replyRef, threadRef)
+		historyMessageID := ""
+		if len(historyMessageIDs) == len(outputs) {
+			historyMessageID = historyMessageIDs[i]
 		}
 		if err := stream.Push(ctx, channel.StreamEvent{
 			Type: channel.StreamEventFinal,
 			Final: &channel.StreamFinalizePayload{
-				Message: outMessage,
+				Message:          outMessage,
+				HistoryMessageID: historyMessageID,
 			},
 		}); err != nil {
 			return err
@@ -1061,6 +1171,7 @@ func (p *ChannelInboundProcessor) persistPassiveMessage(
 	msg channel.InboundMessage,
 	text string,
 	attachments []conversation.ChatAttachment,
+	senderAttributes map[string]string,
 	routeID, sessionID, eventID string,
 ) {
 	if p.message == nil {
@@ -1092,6 +1203,7 @@ func (p *ChannelInboundProcessor) persistPassiveMessage(
 		Target:            strings.TrimSpace(msg.ReplyTarget),
 		AttachmentPaths:   attachmentPaths,
 		Time:              time.Now().UTC(),
+		SenderAttributes:  senderAttributes,
 	}, trimmedText)
 
 	modelMsg := conversation.ModelMessage{Role: "user", Content: conversation.NewTextContent(headerifiedText)}
@@ -1104,8 +1216,9 @@ func (p *ChannelInboundProcessor) persistPassiveMessage(
 	}
 
 	meta := map[string]any{
-		"route_id": strings.TrimSpace(routeID),
-		"platform": msg.Channel.String(),
+		"route_id":                    strings.TrimSpace(routeID),
+		"platform":                    msg.Channel.String(),
+		messagepkg.PassiveMetadataKey: true,
 	}
 
 	var assets []messagepkg.AssetRef
@@ -1242,7 +1355,10 @@ type agentStreamEnvelope struct {
 	Speeches    json.RawMessage `json:"speeches"`
 }
 
-func mapStreamChunkToChannelEvents(chunk conversation.StreamChunk) ([]channel.StreamEvent, []conversation.ModelMessage, error) {
+// mapStreamChunkToChannelEvents converts one agent stream chunk into channel
+// stream events. toolInputs collects streamed tool arguments across chunks;
+// when it is nil, argument deltas are dropped.
+func mapStreamChunkToChannelEvents(chunk conversation.StreamChunk, toolInputs *toolInputAccumulator) ([]channel.StreamEvent, []conversation.ModelMessage, error) {
 	if len(chunk) == 0 {
 		return nil, nil, nil
 	}
@@ -1276,14 +1392,37 @@ func mapStreamChunkToChannelEvents(chunk conversation.StreamChunk) ([]channel.St
 				Phase: channel.StreamPhaseReasoning,
 			},
 		}, finalMessages, nil
+	case "tool_input_delta":
+		callID := strings.TrimSpace(envelope.ToolCallID)
+		if toolInputs == nil || callID == "" {
+			return nil, finalMessages, nil
+		}
+		name := toolInputs.add(callID, strings.TrimSpace(envelope.ToolName), envelope.Delta)
+		return []channel.StreamEvent{
+			{
+				Type:  channel.StreamEventToolCallDelta,
+				Delta: envelope.Delta,
+				ToolCall: &channel.StreamToolCall{
+					Name:   name,
+					CallID: callID,
+				},
+			},
+		}, finalMessages, nil
 	case "tool_call_start":
+		callID := strings.TrimSpace(envelope.ToolCallID)
+		input := envelope.Input
+		if toolInputs != nil {
+			if streamed := toolInputs.take(callID); len(input) == 0 || string(input) == "null" {
+				input = streamed
+			}
+		}
 		return []channel.StreamEvent{
 			{
 				Type: channel.StreamEventToolCallStart,
 				ToolCall: &channel.StreamToolCall{
 					Name:   strings.TrimSpace(envelope.ToolName),
-					CallID: strings.TrimSpace(envelope.ToolCallID),
-					Input:  parseRawJSON(envelope.Input),
+					CallID: callID,
+					Input:  parseRawJSON(input),
 				},
 			},
 		}, finalMessages, nil
@@ -1392,8 +1531,20 @@ func mapStreamChunkToChannelEvents(chunk conversation.StreamChunk) ([]channel.St
 				Error: streamError,
 			},
 		}, finalMessages, nil
-	default:
+	case "tool_call_progress", "agent_abort", "retry", "progress":
 		return nil, finalMessages, nil
+	default:
+		// Pass unrecognized events through so new agent event types stay
+		// visible (e.g. to local subscribers) until they are mapped.
+		return []channel.StreamEvent{
+			{
+				Type: channel.StreamEventUnknown,
+				Metadata: map[string]any{
+					"type":    strings.TrimSpace(envelope.Type),
+					"payload": parseRawJSON(chunk),
+				},
+			},
+		}, finalMessages, nil
 	}
 }
 
@@ -1532,6 +1683,19 @@ func normalizeReplyTarget(registry *channel.Registry, channelType channel.Channe
 	return strings.TrimSpace(target)
 }
 
+// chatErrorNotice returns the reply sent in place of a failed chat for
+// errors the sender should be told about.
+func chatErrorNotice(err error) (string, bool) {
+	switch {
+	case errors.Is(err, conversation.ErrSpendCapExceeded):
+		return spendCapNotice, true
+	case errors.Is(err, conversation.ErrProviderQuotaExhausted):
+		return providerQuotaNotice, true
+	default:
+		return "", false
+	}
+}
+
 func isSilentReplyText(text string) bool {
 	trimmed := strings.TrimSpace(text)
 	if trimmed == "" {
@@ -1640,7 +1804,7 @@ func (p *ChannelInboundProcessor) resolveProcessingStatusNotifier(channelType ch
 	return notifier
 }
 
-func (*ChannelInboundProcessor) notifyProcessingStarted(
+func (p *ChannelInboundProcessor) notifyProcessingStarted(
 	ctx context.Context,
 	notifier channel.ProcessingStatusNotifier,
 	cfg channel.ChannelConfig,
@@ -1650,12 +1814,12 @@ func (*ChannelInboundProcessor) notifyProcessingStarted(
 	if notifier == nil {
 		return channel.ProcessingStatusHandle{}, nil
 	}
-	statusCtx, cancel := context.WithTimeout(ctx, processingStatusTimeout)
+	statusCtx, cancel := context.WithTimeout(ctx, p.processingStatusTimeout(cfg))
 	defer cancel()
 	return notifier.ProcessingStarted(statusCtx, cfg, msg, info)
 }
 
-func (*ChannelInboundProcessor) notifyProcessingCompleted(
+func (p *ChannelInboundProcessor) notifyProcessingCompleted(
 	ctx context.Context,
 	notifier channel.ProcessingStatusNotifier,
 	cfg channel.ChannelConfig,
@@ -1666,12 +1830,12 @@ func (*ChannelInboundProcessor) notifyProcessingCompleted(
 	if notifier == nil {
 		return nil
 	}
-	statusCtx, cancel := context.WithTimeout(ctx, processingStatusTimeout)
+	statusCtx, cancel := context.WithTimeout(ctx, p.processingStatusTimeout(cfg))
 	defer cancel()
 	return notifier.ProcessingCompleted(statusCtx, cfg, msg, info, handle)
 }
 
-func (*ChannelInboundProcessor) notifyProcessingFailed(
+func (p *ChannelInboundProcessor) notifyProcessingFailed(
 	ctx context.Context,
 	notifier channel.ProcessingStatusNotifier,
 	cfg channel.ChannelConfig,
@@ -1683,11 +1847,25 @@ func (*ChannelInboundProcessor) notifyProcessingFailed(
 	if notifier == nil {
 		return nil
 	}
-	statusCtx, cancel := context.WithTimeout(ctx, processingStatusTimeout)
+	statusCtx, cancel := context.WithTimeout(ctx, p.processingStatusTimeout(cfg))
 	defer cancel()
 	return notifier.ProcessingFailed(statusCtx, cfg, msg, info, handle, cause)
 }
 
+// processingStatusTimeout returns the status update timeout set in cfg, or
+// the default when it is unset or invalid.
+func (p *ChannelInboundProcessor) processingStatusTimeout(cfg channel.ChannelConfig) time.Duration {
+	timeout, err := channel.ProcessingStatusTimeoutFromRouting(cfg.Routing)
+	if err != nil {
+		if p != nil && p.logger != nil {
+			p.logger.Warn("invalid processing status timeout, using default",
+				slog.String("channel", cfg.ChannelType.String()), slog.Any("error", err))
+		}
+		return channel.DefaultProcessingStatusTimeout
+	}
+	return timeout
+}
+
 func (p *ChannelInboundProcessor) logProcessingStatusError(
 	stage string,
 	msg channel.InboundMessage,
@@ -1987,11 +2165,14 @@ func extractStorageKey(accessPath string, _ string) string {
 	return accessPath[idx+len(marker):]
 }
 
-// isLocalChannelType returns true for channels that already publish to RouteHub
-// natively (e.g. web). Wrapping these with a tee would cause duplicate events.
-func isLocalChannelType(ct channel.ChannelType) bool {
-	s := strings.ToLower(strings.TrimSpace(string(ct)))
-	return s == "web" || s == "cli"
+// isLocalChannel returns true for channels that already publish to RouteHub
+// natively (e.g. web), as declared by their adapter descriptor. Wrapping these
+// with a tee would cause duplicate events.
+func (p *ChannelInboundProcessor) isLocalChannel(ct channel.ChannelType) bool {
+	if p.registry == nil {
+		return false
+	}
+	return p.registry.IsLocal(ct)
 }
 
 // replayPipelineSession loads persisted events from the DB and replays them
@@ -2246,7 +2427,7 @@ func buildAssetRefs(attachments []channel.Attachment, startOrdinal int) []conver
 		}
 		ref := conversation.OutboundAssetRef{
 			ContentHash: contentHash,
-			Role:        "attachment",
+			Role:        messagepkg.AssetRoleFromMetadata(att.Metadata),
 			Ordinal:     startOrdinal + len(refs),
 			Mime:        strings.TrimSpace(att.Mime),
 			SizeBytes:   att.Size,
@@ -2339,8 +2520,22 @@ func (p *ChannelInboundProcessor) dispatchReactions(
 	}
 }
 
+// senderAttributes returns the sender attributes whitelisted by the channel
+// config. An invalid whitelist falls back to the default attribute.
+func (p *ChannelInboundProcessor) senderAttributes(cfg channel.ChannelConfig, msg channel.InboundMessage) map[string]string {
+	keys, err := channel.SenderAttributesFromRouting(cfg.Routing)
+	if err != nil {
+		if p.logger != nil {
+			p.logger.Warn("invalid sender attributes, using default",
+				slog.String("channel", msg.Channel.String()), slog.Any("error", err))
+		}
+		keys = []string{channel.DefaultSenderAttribute}
+	}
+	return channel.SelectSenderAttributes(msg.Sender.Attributes, keys)
+}
+
 // buildRouteMetadata extracts user/conversation information for route metadata persistence.
-func buildRouteMetadata(msg channel.InboundMessage, identity InboundIdentity) map[string]any {
+func buildRouteMetadata(msg channel.InboundMessage, identity InboundIdentity, senderAttributes map[string]string) map[string]any {
 	m := make(map[string]any)
 
 	if v := strings.TrimSpace(identity.DisplayName); v != "" {
@@ -2356,13 +2551,10 @@ func buildRouteMetadata(msg channel.InboundMessage, identity InboundIdentity) ma
 		m["conversation_name"] = v
 	}
 
-	for k, v := range msg.Sender.Attributes {
-		v = strings.TrimSpace(v)
-		if v == "" {
-			continue
-		}
-		if k == "username" {
-			m["sender_username"] = v
+	// Whitelisted attributes never override the identity fields above.
+	for k, v := range senderAttributes {
+		if _, ok := m["sender_"+k]; !ok {
+			m["sender_"+k] = v
 		}
 	}
 	if mentions, ok := msg.Metadata["mentions"]; ok && mentions != nil {
@@ -2433,6 +2625,16 @@ func isStopCommand(cmdText string) bool {
 
 // handleStopCommand resolves the route for the current conversation and
 // cancels any active agent stream, effectively aborting the generation.
+// ephemeralMessage builds a reply only the sender of msg should see. It
+// replies to msg so adapters can tell whom the message is for.
+func ephemeralMessage(msg channel.InboundMessage, text string) channel.Message {
+	reply := channel.Message{Text: text, Ephemeral: true}
+	if id := strings.TrimSpace(msg.Message.ID); id != "" {
+		reply.Reply = &channel.ReplyRef{Target: strings.TrimSpace(msg.ReplyTarget), MessageID: id}
+	}
+	return reply
+}
+
 func (p *ChannelInboundProcessor) handleStopCommand(
 	ctx context.Context,
 	cfg channel.ChannelConfig,
@@ -2448,12 +2650,12 @@ func (p *ChannelInboundProcessor) handleStopCommand(
 	if p.routeResolver == nil {
 		return sender.Send(ctx, channel.OutboundMessage{
 			Target:  target,
-			Message: channel.Message{Text: "Error: route resolver not configured."},
+			Message: ephemeralMessage(msg, "Error: route resolver not configured."),
 		})
 	}
 
 	threadID := extractThreadID(msg)
-	routeMetadata := buildRouteMetadata(msg, identity)
+	routeMetadata := buildRouteMetadata(msg, identity, p.senderAttributes(cfg, msg))
 	p.enrichConversationAvatar(ctx, cfg, msg, routeMetadata)
 	resolved, err := p.routeResolver.ResolveConversation(ctx, route.ResolveInput{
 		BotID:             identity.BotID,
@@ -2472,7 +2674,7 @@ func (p *ChannelInboundProcessor) handleStopCommand(
 		}
 		return sender.Send(ctx, channel.OutboundMessage{
 			Target:  target,
-			Message: channel.Message{Text: "Error: failed to resolve conversation route."},
+			Message: ephemeralMessage(msg, "Error: failed to resolve conversation route."),
 		})
 	}
 
@@ -2529,7 +2731,7 @@ func isStatusCommand(cmdText string) bool {
 // /new chat → chat, /new discuss → discuss, /new (no arg) → default by context.
 // WebUI (local channel) always defaults to chat.
 // Groups default to discuss, DMs default to chat.
-func resolveNewSessionType(cmdText string, msg channel.InboundMessage) (string, error) {
+func resolveNewSessionType(cmdText string, msg channel.InboundMessage, local bool) (string, error) {
 	extracted := command.ExtractCommandText(cmdText)
 	parsed, _ := command.Parse(extracted)
 
@@ -2538,13 +2740,13 @@ func resolveNewSessionType(cmdText string, msg channel.InboundMessage) (string,
 	case "chat":
 		return sessionpkg.TypeChat, nil
 	case "discuss":
-		if isLocalChannelType(msg.Channel) {
+		if local {
 			return "", errors.New("discuss mode is not supported via WebUI — use a channel adapter (Telegram, Discord, etc.)")
 		}
 		return sessionpkg.TypeDiscuss, nil
 	case "":
 		// Default: local → chat, group → discuss, DM → chat.
-		if isLocalChannelType(msg.Channel) {
+		if local {
 			return sessionpkg.TypeChat, nil
 		}
 		if channel.IsPrivateConversationType(msg.Conversation.Type) {
@@ -2573,29 +2775,29 @@ func (p *ChannelInboundProcessor) handleNewSessionCommand(
 	}
 
 	cmdText := rawTextForCommand(msg, "")
-	sessType, err := resolveNewSessionType(cmdText, msg)
+	sessType, err := resolveNewSessionType(cmdText, msg, p.isLocalChannel(msg.Channel))
 	if err != nil {
 		return sender.Send(ctx, channel.OutboundMessage{
 			Target:  target,
-			Message: channel.Message{Text: "Error: " + err.Error()},
+			Message: ephemeralMessage(msg, "Error: "+err.Error()),
 		})
 	}
 
 	if p.routeResolver == nil {
 		return sender.Send(ctx, channel.OutboundMessage{
 			Target:  target,
-			Message: channel.Message{Text: "Error: route resolver not configured."},
+			Message: ephemeralMessage(msg, "Error: route resolver not configured."),
 		})
 	}
 	if p.sessionEnsurer == nil {
 		return sender.Send(ctx, channel.OutboundMessage{
 			Target:  target,
-			Message: channel.Message{Text: "Error: session service not configured."},
+			Message: ephemeralMessage(msg, "Error: session service not configured."),
 		})
 	}
 
 	threadID := extractThreadID(msg)
-	routeMetadata := buildRouteMetadata(msg, identity)
+	routeMetadata := buildRouteMetadata(msg, identity, p.senderAttributes(cfg, msg))
 	p.enrichConversationAvatar(ctx, cfg, msg, routeMetadata)
 	resolved, err := p.routeResolver.ResolveConversation(ctx, route.ResolveInput{
 		BotID:             identity.BotID,
@@ -2614,7 +2816,7 @@ func (p *ChannelInboundProcessor) handleNewSessionCommand(
 		}
 		return sender.Send(ctx, channel.OutboundMessage{
 			Target:  target,
-			Message: channel.Message{Text: "Error: failed to resolve conversation route."},
+			Message: ephemeralMessage(msg, "Error: failed to resolve conversation route."),
 		})
 	}
 
@@ -2625,7 +2827,7 @@ func (p *ChannelInboundProcessor) handleNewSessionCommand(
 		}
 		return sender.Send(ctx, channel.OutboundMessage{
 			Target:  target,
-			Message: channel.Message{Text: "Error: failed to create new session."},
+			Message: ephemeralMessage(msg, "Error: failed to create new session."),
 		})
 	}
 
@@ -2644,7 +2846,7 @@ func (p *ChannelInboundProcessor) handleNewSessionCommand(
 	}
 	return sender.Send(ctx, channel.OutboundMessage{
 		Target:  target,
-		Message: channel.Message{Text: fmt.Sprintf("New %s conversation started.", modeLabel)},
+		Message: ephemeralMessage(msg, fmt.Sprintf("New %s conversation started.", modeLabel)),
 	})
 }
 
@@ -2662,18 +2864,18 @@ func (p *ChannelInboundProcessor) handleStatusCommand(
 	if p.routeResolver == nil {
 		return sender.Send(ctx, channel.OutboundMessage{
 			Target:  target,
-			Message: channel.Message{Text: "Error: route resolver not configured."},
+			Message: ephemeralMessage(msg, "Error: route resolver not configured."),
 		})
 	}
 	if p.commandHandler == nil {
 		return sender.Send(ctx, channel.OutboundMessage{
 			Target:  target,
-			Message: channel.Message{Text: "Error: command handler not configured."},
+			Message: ephemeralMessage(msg, "Error: command handler not configured."),
 		})
 	}
 
 	threadID := extractThreadID(msg)
-	routeMetadata := buildRouteMetadata(msg, identity)
+	routeMetadata := buildRouteMetadata(msg, identity, p.senderAttributes(cfg, msg))
 	p.enrichConversationAvatar(ctx, cfg, msg, routeMetadata)
 	resolved, err := p.routeResolver.ResolveConversation(ctx, route.ResolveInput{
 		BotID:             identity.BotID,
@@ -2692,7 +2894,7 @@ func (p *ChannelInboundProcessor) handleStatusCommand(
 		}
 		return sender.Send(ctx, channel.OutboundMessage{
 			Target:  target,
-			Message: channel.Message{Text: "Error: failed to resolve conversation route."},
+			Message: ephemeralMessage(msg, "Error: failed to resolve conversation route."),
 		})
 	}
 
@@ -6,12 +6,15 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgtype"
 
@@ -26,6 +29,7 @@ import (
 	messagepkg "github.com/memohai/memoh/internal/message"
 	pipelinepkg "github.com/memohai/memoh/internal/pipeline"
 	"github.com/memohai/memoh/internal/schedule"
+	sessionpkg "github.com/memohai/memoh/internal/session"
 )
 
 type fakeChatGateway struct {
@@ -123,22 +127,33 @@ type fakeProcessingStatusNotifier struct {
 	completedSeen channel.ProcessingStatusHandle
 	failedSeen    channel.ProcessingStatusHandle
 	failedCause   error
+	// timeouts records the time left on each status context.
+	timeouts []time.Duration
 }
 
-func (n *fakeProcessingStatusNotifier) ProcessingStarted(_ context.Context, _ channel.ChannelConfig, _ channel.InboundMessage, info channel.ProcessingStatusInfo) (channel.ProcessingStatusHandle, error) {
+func (n *fakeProcessingStatusNotifier) recordTimeout(ctx context.Context) {
+	if deadline, ok := ctx.Deadline(); ok {
+		n.timeouts = append(n.timeouts, time.Until(deadline))
+	}
+}
+
+func (n *fakeProcessingStatusNotifier) ProcessingStarted(ctx context.Context, _ channel.ChannelConfig, _ channel.InboundMessage, info channel.ProcessingStatusInfo) (channel.ProcessingStatusHandle, error) {
+	n.recordTimeout(ctx)
 	n.events = append(n.events, "started")
 	n.info = append(n.info, info)
 	return n.startedHandle, n.startedErr
 }
 
-func (n *fakeProcessingStatusNotifier) ProcessingCompleted(_ context.Context, _ channel.ChannelConfig, _ channel.InboundMessage, info channel.ProcessingStatusInfo, handle channel.ProcessingStatusHandle) error {
+func (n *fakeProcessingStatusNotifier) ProcessingCompleted(ctx context.Context, _ channel.ChannelConfig, _ channel.InboundMessage, info channel.ProcessingStatusInfo, handle channel.ProcessingStatusHandle) error {
+	n.recordTimeout(ctx)
 	n.events = append(n.events, "completed")
 	n.info = append(n.info, info)
 	n.completedSeen = handle
 	return n.completedErr
 }
 
-func (n *fakeProcessingStatusNotifier) ProcessingFailed(_ context.Context, _ channel.ChannelConfig, _ channel.InboundMessage, info channel.ProcessingStatusInfo, handle channel.ProcessingStatusHandle, cause error) error {
+func (n *fakeProcessingStatusNotifier) ProcessingFailed(ctx context.Context, _ channel.ChannelConfig, _ channel.InboundMessage, info channel.ProcessingStatusInfo, handle channel.ProcessingStatusHandle, cause error) error {
+	n.recordTimeout(ctx)
 	n.events = append(n.events, "failed")
 	n.info = append(n.info, info)
 	n.failedSeen = handle
@@ -176,9 +191,27 @@ func (a *fakeProcessingStatusAdapter) ProcessingFailed(ctx context.Context, cfg
 	return a.notifier.ProcessingFailed(ctx, cfg, msg, info, handle, cause)
 }
 
+type fakeLocalAdapter struct{}
+
+func (*fakeLocalAdapter) Type() channel.ChannelType {
+	return channel.ChannelType("embedded")
+}
+
+func (*fakeLocalAdapter) Descriptor() channel.Descriptor {
+	return channel.Descriptor{
+		Type:       channel.ChannelType("embedded"),
+		Configless: true,
+		Capabilities: channel.ChannelCapabilities{
+			Text:  true,
+			Local: true,
+		},
+	}
+}
+
 type fakeChatService struct {
 	resolveResult route.ResolveConversationResult
 	resolveErr    error
+	resolveIn     route.ResolveInput
 	persisted     []messagepkg.Message
 	persistedIn   []messagepkg.PersistInput
 }
@@ -404,7 +437,8 @@ func (f *fakeAttachmentResolverAdapter) ResolveAttachment(_ context.Context, _ c
 	}, nil
 }
 
-func (f *fakeChatService) ResolveConversation(_ context.Context, _ route.ResolveInput) (route.ResolveConversationResult, error) {
+func (f *fakeChatService) ResolveConversation(_ context.Context, input route.ResolveInput) (route.ResolveConversationResult, error) {
+	f.resolveIn = input
 	if f.resolveErr != nil {
 		return route.ResolveConversationResult{}, f.resolveErr
 	}
@@ -476,6 +510,47 @@ func TestChannelInboundProcessorWithIdentity(t *testing.T) {
 	}
 }
 
+func TestChannelInboundProcessorLinksFinalToPersistedMessage(t *testing.T) {
+	channelIdentitySvc := &fakeChannelIdentityService{channelIdentity: identities.ChannelIdentity{ID: "channelIdentity-1"}}
+	chatSvc := &fakeChatService{resolveResult: route.ResolveConversationResult{ChatID: "chat-1", RouteID: "route-1"}}
+	gateway := &fakeChatGateway{
+		resp: conversation.ChatResponse{
+			Messages: []conversation.ModelMessage{
+				{Role: "assistant", Content: conversation.NewTextContent("AI reply")},
+			},
+		},
+		// The resolver reports the stored message IDs before the final chunk.
+		onChat: func(req conversation.ChatRequest) {
+			req.AssistantOutputRecorder([]string{"msg-42"})
+		},
+	}
+	processor := NewChannelInboundProcessor(slog.Default(), nil, chatSvc, chatSvc, gateway, channelIdentitySvc, &fakePolicyService{}, nil, "", 0)
+	sender := &fakeReplySender{}
+
+	cfg := channel.ChannelConfig{ID: "cfg-1", BotID: "bot-1", ChannelType: channel.ChannelType("feishu")}
+	msg := channel.InboundMessage{
+		BotID:        "bot-1",
+		Channel:      channel.ChannelType("feishu"),
+		Message:      channel.Message{Text: "hello"},
+		ReplyTarget:  "target-id",
+		Sender:       channel.Identity{SubjectID: "ext-1", DisplayName: "User1"},
+		Conversation: channel.Conversation{ID: "chat-1", Type: channel.ConversationTypePrivate},
+	}
+
+	if err := processor.HandleInbound(context.Background(), cfg, msg, sender); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var finals []*channel.StreamFinalizePayload
+	for _, event := range sender.events {
+		if event.Type == channel.StreamEventFinal && event.Final != nil {
+			finals = append(finals, event.Final)
+		}
+	}
+	if len(finals) != 1 || finals[0].HistoryMessageID != "msg-42" {
+		t.Fatalf("expected final linked to msg-42, got %+v", finals)
+	}
+}
+
 func TestChannelInboundProcessorDeniedByACL(t *testing.T) {
 	channelIdentitySvc := &fakeChannelIdentityService{channelIdentity: identities.ChannelIdentity{ID: "channelIdentity-2"}}
 	policySvc := &fakePolicyService{}
@@ -821,6 +896,9 @@ func TestChannelInboundProcessorGroupPassiveSync(t *testing.T) {
 	if chatSvc.persisted[0].Role != "user" {
 		t.Fatalf("passive message role should be user, got %q", chatSvc.persisted[0].Role)
 	}
+	if chatSvc.persisted[0].Metadata[messagepkg.PassiveMetadataKey] != true {
+		t.Fatalf("passive message should be marked for pruning, got metadata %#v", chatSvc.persisted[0].Metadata)
+	}
 }
 
 func TestChannelInboundProcessorGroupMentionTriggersReply(t *testing.T) {
@@ -866,6 +944,124 @@ func TestChannelInboundProcessorGroupMentionTriggersReply(t *testing.T) {
 	if gateway.gotReq.UserMessagePersisted {
 		t.Fatalf("expected UserMessagePersisted=false: user message persistence is deferred to storeRound")
 	}
+	for _, persisted := range chatSvc.persistedIn {
+		if _, ok := persisted.Metadata[messagepkg.PassiveMetadataKey]; ok {
+			t.Fatalf("triggering message must not be marked passive: %#v", persisted.Metadata)
+		}
+	}
+}
+
+func TestChannelInboundProcessorPassesWhitelistedSenderAttributes(t *testing.T) {
+	channelIdentitySvc := &fakeChannelIdentityService{channelIdentity: identities.ChannelIdentity{ID: "channelIdentity-7"}}
+	policySvc := &fakePolicyService{}
+	chatSvc := &fakeChatService{resolveResult: route.ResolveConversationResult{ChatID: "chat-7", RouteID: "route-7"}}
+	gateway := &fakeChatGateway{
+		resp: conversation.ChatResponse{
+			Messages: []conversation.ModelMessage{
+				{Role: "assistant", Content: conversation.NewTextContent("AI reply")},
+			},
+		},
+	}
+	processor := NewChannelInboundProcessor(slog.Default(), nil, chatSvc, chatSvc, gateway, channelIdentitySvc, policySvc, nil, "", 0)
+	sender := &fakeReplySender{}
+
+	cfg := channel.ChannelConfig{
+		ID:      "cfg-1",
+		BotID:   "bot-1",
+		Routing: map[string]any{"sender_attributes": []any{"locale"}},
+	}
+	msg := channel.InboundMessage{
+		BotID:       "bot-1",
+		Channel:     channel.ChannelType("telegram"),
+		Message:     channel.Message{ID: "msg-3", Text: "hello"},
+		ReplyTarget: "123",
+		Sender: channel.Identity{
+			SubjectID: "user-1",
+			Attributes: map[string]string{
+				"username":   "alice",
+				"locale":     "en-GB",
+				"is_premium": "true",
+			},
+		},
+		Conversation: channel.Conversation{ID: "123", Type: "private"},
+	}
+
+	if err := processor.HandleInbound(context.Background(), cfg, msg, sender); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	meta := chatSvc.resolveIn.Metadata
+	if meta["sender_username"] != "alice" || meta["sender_locale"] != "en-GB" {
+		t.Fatalf("expected whitelisted attributes in route metadata, got %#v", meta)
+	}
+	if _, ok := meta["sender_is_premium"]; ok {
+		t.Fatalf("expected non-whitelisted attribute to be dropped, got %#v", meta)
+	}
+	want := map[string]string{"username": "alice", "locale": "en-GB"}
+	if !reflect.DeepEqual(gateway.gotReq.SenderAttributes, want) {
+		t.Fatalf("expected sender attributes %v in chat request, got %v", want, gateway.gotReq.SenderAttributes)
+	}
+}
+
+func TestChannelInboundProcessorMutedRoutePersistsWithoutReply(t *testing.T) {
+	tests := []struct {
+		name      string
+		muted     bool
+		wantReply bool
+	}{
+		{name: "muted", muted: true, wantReply: false},
+		{name: "unmuted", muted: false, wantReply: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			channelIdentitySvc := &fakeChannelIdentityService{channelIdentity: identities.ChannelIdentity{ID: "channelIdentity-mute"}}
+			policySvc := &fakePolicyService{}
+			chatSvc := &fakeChatService{resolveResult: route.ResolveConversationResult{ChatID: "chat-mute", RouteID: "route-mute", Muted: tt.muted}}
+			gateway := &fakeChatGateway{
+				resp: conversation.ChatResponse{
+					Messages: []conversation.ModelMessage{
+						{Role: "assistant", Content: conversation.NewTextContent("AI reply")},
+					},
+				},
+			}
+			processor := NewChannelInboundProcessor(slog.Default(), nil, chatSvc, chatSvc, gateway, channelIdentitySvc, policySvc, nil, "", 0)
+			sender := &fakeReplySender{}
+
+			cfg := channel.ChannelConfig{ID: "cfg-1", BotID: "bot-1"}
+			msg := channel.InboundMessage{
+				BotID:       "bot-1",
+				Channel:     channel.ChannelType("telegram"),
+				Message:     channel.Message{ID: "msg-mute", Text: "hello"},
+				ReplyTarget: "chat-123",
+				Sender:      channel.Identity{SubjectID: "user-1"},
+				Conversation: channel.Conversation{
+					ID:   "conv-mute",
+					Type: channel.ConversationTypePrivate,
+				},
+			}
+
+			if err := processor.HandleInbound(context.Background(), cfg, msg, sender); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !tt.wantReply {
+				if gateway.gotReq.Query != "" {
+					t.Fatal("muted route should not trigger chat call")
+				}
+				if len(sender.sent) != 0 {
+					t.Fatalf("muted route should not send reply: %+v", sender.sent)
+				}
+				if len(chatSvc.persisted) != 1 || chatSvc.persisted[0].Role != "user" {
+					t.Fatalf("muted route should persist the user message, got %+v", chatSvc.persisted)
+				}
+				return
+			}
+			if gateway.gotReq.Query == "" {
+				t.Fatal("unmuted route should trigger chat call")
+			}
+			if len(sender.sent) != 1 {
+				t.Fatalf("expected one outbound reply, got %d", len(sender.sent))
+			}
+		})
+	}
 }
 
 type failingOpenStreamSender struct {
@@ -1491,6 +1687,70 @@ func TestChannelInboundProcessorProcessingStatusFailureLifecycle(t *testing.T) {
 	}
 }
 
+func TestChannelInboundProcessorRepliesWithNoticeWhenSpendCapExceeded(t *testing.T) {
+	notifier := &fakeProcessingStatusNotifier{}
+	registry := channel.NewRegistry()
+	registry.MustRegister(&fakeProcessingStatusAdapter{notifier: notifier})
+	channelIdentitySvc := &fakeChannelIdentityService{channelIdentity: identities.ChannelIdentity{ID: "channelIdentity-cap"}}
+	policySvc := &fakePolicyService{}
+	chatSvc := &fakeChatService{resolveResult: route.ResolveConversationResult{ChatID: "chat-cap", RouteID: "route-cap"}}
+	gateway := &fakeChatGateway{err: fmt.Errorf("%w: used 120 of 100 tokens this month", conversation.ErrSpendCapExceeded)}
+	processor := NewChannelInboundProcessor(slog.Default(), registry, chatSvc, chatSvc, gateway, channelIdentitySvc, policySvc, nil, "", 0)
+	sender := &fakeReplySender{}
+	cfg := channel.ChannelConfig{ID: "cfg-1", BotID: "bot-1", ChannelType: channel.ChannelType("feishu")}
+	msg := channel.InboundMessage{
+		BotID:       "bot-1",
+		Channel:     channel.ChannelType("feishu"),
+		Message:     channel.Message{ID: "om_cap", Text: "hello"},
+		ReplyTarget: "chat_id:oc_cap",
+		Sender:      channel.Identity{SubjectID: "ext-cap"},
+		Conversation: channel.Conversation{
+			ID:   "oc_cap",
+			Type: channel.ConversationTypePrivate,
+		},
+	}
+
+	if err := processor.HandleInbound(context.Background(), cfg, msg, sender); err != nil {
+		t.Fatalf("expected spend cap to be handled, got: %v", err)
+	}
+	if len(sender.sent) != 1 || sender.sent[0].Message.PlainText() != spendCapNotice {
+		t.Fatalf("expected spend cap notice, got: %+v", sender.sent)
+	}
+	if len(notifier.events) != 2 || notifier.events[1] != "completed" {
+		t.Fatalf("expected processing to complete, got: %+v", notifier.events)
+	}
+}
+
+func TestChannelInboundProcessorRepliesWithNoticeWhenProviderQuotaExhausted(t *testing.T) {
+	registry := channel.NewRegistry()
+	registry.MustRegister(&fakeProcessingStatusAdapter{notifier: &fakeProcessingStatusNotifier{}})
+	channelIdentitySvc := &fakeChannelIdentityService{channelIdentity: identities.ChannelIdentity{ID: "channelIdentity-quota"}}
+	policySvc := &fakePolicyService{}
+	chatSvc := &fakeChatService{resolveResult: route.ResolveConversationResult{ChatID: "chat-quota", RouteID: "route-quota"}}
+	gateway := &fakeChatGateway{err: fmt.Errorf("%w: api error 429: You exceeded your current quota", conversation.ErrProviderQuotaExhausted)}
+	processor := NewChannelInboundProcessor(slog.Default(), registry, chatSvc, chatSvc, gateway, channelIdentitySvc, policySvc, nil, "", 0)
+	sender := &fakeReplySender{}
+	cfg := channel.ChannelConfig{ID: "cfg-1", BotID: "bot-1", ChannelType: channel.ChannelType("feishu")}
+	msg := channel.InboundMessage{
+		BotID:       "bot-1",
+		Channel:     channel.ChannelType("feishu"),
+		Message:     channel.Message{ID: "om_quota", Text: "hello"},
+		ReplyTarget: "chat_id:oc_quota",
+		Sender:      channel.Identity{SubjectID: "ext-quota"},
+		Conversation: channel.Conversation{
+			ID:   "oc_quota",
+			Type: channel.ConversationTypePrivate,
+		},
+	}
+
+	if err := processor.HandleInbound(context.Background(), cfg, msg, sender); err != nil {
+		t.Fatalf("expected quota exhaustion to be handled, got: %v", err)
+	}
+	if len(sender.sent) != 1 || sender.sent[0].Message.PlainText() != providerQuotaNotice {
+		t.Fatalf("expected provider quota notice, got: %+v", sender.sent)
+	}
+}
+
 func TestChannelInboundProcessorProcessingStatusErrorsAreBestEffort(t *testing.T) {
 	notifier := &fakeProcessingStatusNotifier{
 		startedErr:   errors.New("start notify failed"),
@@ -1721,13 +1981,18 @@ func TestMapStreamChunkToChannelEvents(t *testing.T) {
 			chunk:         ``,
 			wantNilEvents: true,
 		},
+		{
+			name:          "tool_call_progress ignored",
+			chunk:         `{"type":"tool_call_progress","toolCallId":"c1"}`,
+			wantNilEvents: true,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			events, _, err := mapStreamChunkToChannelEvents(conversation.StreamChunk([]byte(tt.chunk)))
+			events, _, err := mapStreamChunkToChannelEvents(conversation.StreamChunk([]byte(tt.chunk)), nil)
 			if err != nil {
 				t.Fatalf("unexpected error: %v", err)
 			}
@@ -1772,7 +2037,7 @@ func TestMapStreamChunkToChannelEvents_ToolCallFields(t *testing.T) {
 	t.Parallel()
 
 	chunk := `{"type":"tool_call_end","toolName":"calc","toolCallId":"c1","input":{"x":1},"result":{"sum":2}}`
-	events, _, err := mapStreamChunkToChannelEvents(conversation.StreamChunk([]byte(chunk)))
+	events, _, err := mapStreamChunkToChannelEvents(conversation.StreamChunk([]byte(chunk)), nil)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -1792,11 +2057,134 @@ func TestMapStreamChunkToChannelEvents_ToolCallFields(t *testing.T) {
 	}
 }
 
+func TestIsLocalChannel_UsesDescriptorCapability(t *testing.T) {
+	t.Parallel()
+
+	reg := channel.NewRegistry()
+	reg.MustRegister(&fakeLocalAdapter{})
+	reg.MustRegister(&fakeProcessingStatusAdapter{})
+	p := &ChannelInboundProcessor{registry: reg}
+
+	if !p.isLocalChannel(channel.ChannelType("embedded")) {
+		t.Fatal("expected adapter with Local capability to be local")
+	}
+	if p.isLocalChannel(channel.ChannelType("feishu")) {
+		t.Fatal("expected feishu to be non-local")
+	}
+	if p.isLocalChannel(channel.ChannelType("unknown")) {
+		t.Fatal("expected unregistered channel to be non-local")
+	}
+	if (&ChannelInboundProcessor{}).isLocalChannel(channel.ChannelType("embedded")) {
+		t.Fatal("expected processor without registry to treat channels as non-local")
+	}
+}
+
+func TestResolveNewSessionType_LocalChannel(t *testing.T) {
+	t.Parallel()
+
+	msg := channel.InboundMessage{
+		Channel:      channel.ChannelType("embedded"),
+		Conversation: channel.Conversation{Type: channel.ConversationTypeGroup},
+	}
+	got, err := resolveNewSessionType("/new", msg, true)
+	if err != nil || got != sessionpkg.TypeChat {
+		t.Fatalf("expected chat session for local channel, got %q (%v)", got, err)
+	}
+	if _, err := resolveNewSessionType("/new discuss", msg, true); err == nil {
+		t.Fatal("expected discuss to be rejected on local channel")
+	}
+	got, err = resolveNewSessionType("/new", msg, false)
+	if err != nil || got != sessionpkg.TypeDiscuss {
+		t.Fatalf("expected discuss session for group on non-local channel, got %q (%v)", got, err)
+	}
+}
+
+func TestMapStreamChunkToChannelEvents_ToolInputDeltas(t *testing.T) {
+	t.Parallel()
+
+	toolInputs := newToolInputAccumulator()
+	chunks := []string{
+		`{"type":"tool_input_delta","toolName":"weather","toolCallId":"c1"}`,
+		`{"type":"tool_input_delta","toolCallId":"c1","delta":"{\"city\":"}`,
+		`{"type":"tool_input_delta","toolCallId":"c1","delta":"\"Paris\""}`,
+		`{"type":"tool_input_delta","toolCallId":"c1","delta":"}"}`,
+	}
+	var streamed strings.Builder
+	for _, chunk := range chunks {
+		events, _, err := mapStreamChunkToChannelEvents(conversation.StreamChunk([]byte(chunk)), toolInputs)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(events) != 1 || events[0].Type != channel.StreamEventToolCallDelta {
+			t.Fatalf("expected one tool_call_delta event, got %+v", events)
+		}
+		tc := events[0].ToolCall
+		if tc == nil || tc.Name != "weather" || tc.CallID != "c1" || tc.Input != nil {
+			t.Fatalf("unexpected tool call: %+v", tc)
+		}
+		streamed.WriteString(events[0].Delta)
+	}
+	if got := streamed.String(); got != `{"city":"Paris"}` {
+		t.Fatalf("unexpected streamed deltas: %q", got)
+	}
+
+	start := `{"type":"tool_call_start","toolName":"weather","toolCallId":"c1"}`
+	events, _, err := mapStreamChunkToChannelEvents(conversation.StreamChunk([]byte(start)), toolInputs)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events) != 1 || events[0].ToolCall == nil {
+		t.Fatalf("expected one tool_call_start event, got %+v", events)
+	}
+	if got, ok := events[0].ToolCall.Input.(map[string]any); !ok || got["city"] != "Paris" {
+		t.Fatalf("expected streamed arguments on start, got %#v", events[0].ToolCall.Input)
+	}
+	if len(toolInputs.inputs) != 0 {
+		t.Fatalf("expected accumulator to forget finished call, got %d entries", len(toolInputs.inputs))
+	}
+
+	events, _, err = mapStreamChunkToChannelEvents(conversation.StreamChunk([]byte(chunks[1])), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events) != 0 {
+		t.Fatalf("expected deltas to be dropped without accumulator, got %+v", events)
+	}
+}
+
+func TestMapStreamChunkToChannelEvents_UnknownTypePassesThrough(t *testing.T) {
+	t.Parallel()
+
+	chunk := `{"type":"citation_delta","data":{"source":"https://example.com"}}`
+	events, _, err := mapStreamChunkToChannelEvents(conversation.StreamChunk([]byte(chunk)), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(events))
+	}
+	ev := events[0]
+	if ev.Type != channel.StreamEventUnknown {
+		t.Fatalf("expected event type %q, got %q", channel.StreamEventUnknown, ev.Type)
+	}
+	if ev.Metadata["type"] != "citation_delta" {
+		t.Fatalf("expected raw type in metadata, got %#v", ev.Metadata["type"])
+	}
+	payload, ok := ev.Metadata["payload"].(map[string]any)
+	if !ok {
+		t.Fatalf("expected decoded payload, got %#v", ev.Metadata["payload"])
+	}
+	data, ok := payload["data"].(map[string]any)
+	if !ok || data["source"] != "https://example.com" {
+		t.Fatalf("unexpected payload data: %#v", payload["data"])
+	}
+}
+
 func TestMapStreamChunkToChannelEvents_FinalMessages(t *testing.T) {
 	t.Parallel()
 
 	chunk := `{"type":"agent_end","messages":[{"role":"assistant","content":"done"}]}`
-	events, messages, err := mapStreamChunkToChannelEvents(conversation.StreamChunk([]byte(chunk)))
+	events, messages, err := mapStreamChunkToChannelEvents(conversation.StreamChunk([]byte(chunk)), nil)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -2007,3 +2395,171 @@ func TestMapChannelToChatAttachments(t *testing.T) {
 		t.Fatalf("expected non-asset attachment URL, got %q", mapped[1].URL)
 	}
 }
+
+func TestChannelInboundProcessorBlockedSenderDroppedUntilUnblocked(t *testing.T) {
+	channelIdentitySvc := &fakeChannelIdentityService{channelIdentity: identities.ChannelIdentity{ID: "channelIdentity-blocked"}}
+	policySvc := &fakePolicyService{}
+	chatSvc := &fakeChatService{resolveResult: route.ResolveConversationResult{ChatID: "chat-blocked", RouteID: "route-blocked"}}
+	gateway := &fakeChatGateway{
+		resp: conversation.ChatResponse{
+			Messages: []conversation.ModelMessage{
+				{Role: "assistant", Content: conversation.NewTextContent("AI reply")},
+			},
+		},
+	}
+	processor := NewChannelInboundProcessor(slog.Default(), nil, chatSvc, chatSvc, gateway, channelIdentitySvc, policySvc, nil, "", 0)
+	blockList := &fakeSenderBlockList{blocked: map[string]bool{"bot-1:channelIdentity-blocked": true}}
+	processor.SetBlockList(blockList)
+
+	cfg := channel.ChannelConfig{ID: "cfg-1", BotID: "bot-1"}
+	msg := channel.InboundMessage{
+		BotID:       "bot-1",
+		Channel:     channel.ChannelType("telegram"),
+		Message:     channel.Message{ID: "msg-blocked", Text: "hello"},
+		ReplyTarget: "chat-123",
+		Sender:      channel.Identity{SubjectID: "user-1"},
+		Conversation: channel.Conversation{
+			ID:   "conv-blocked",
+			Type: channel.ConversationTypePrivate,
+		},
+	}
+
+	sender := &fakeReplySender{}
+	if err := processor.HandleInbound(context.Background(), cfg, msg, sender); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gateway.gotReq.Query != "" {
+		t.Fatal("blocked sender should not trigger chat call")
+	}
+	if len(sender.sent) != 0 {
+		t.Fatalf("blocked sender without notice should get no reply: %+v", sender.sent)
+	}
+	if len(chatSvc.persisted) != 0 {
+		t.Fatalf("blocked sender message should not be persisted: %+v", chatSvc.persisted)
+	}
+
+	delete(blockList.blocked, "bot-1:channelIdentity-blocked")
+	if err := processor.HandleInbound(context.Background(), cfg, msg, sender); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gateway.gotReq.Query == "" {
+		t.Fatal("unblocked sender should trigger chat call")
+	}
+	if len(sender.sent) != 1 {
+		t.Fatalf("expected one outbound reply after unblock, got %d", len(sender.sent))
+	}
+}
+
+func TestChannelInboundProcessorPassesRouteAnnotationsToChat(t *testing.T) {
+	channelIdentitySvc := &fakeChannelIdentityService{channelIdentity: identities.ChannelIdentity{ID: "channelIdentity-annotated"}}
+	policySvc := &fakePolicyService{}
+	chatSvc := &fakeChatService{resolveResult: route.ResolveConversationResult{
+		ChatID:  "chat-annotated",
+		RouteID: "route-annotated",
+		Annotations: route.Annotations{
+			Notes: "Regular customer, prefers German.",
+			Tags:  []string{"vip", "de"},
+		},
+	}}
+	gateway := &fakeChatGateway{
+		resp: conversation.ChatResponse{
+			Messages: []conversation.ModelMessage{
+				{Role: "assistant", Content: conversation.NewTextContent("AI reply")},
+			},
+		},
+	}
+	processor := NewChannelInboundProcessor(slog.Default(), nil, chatSvc, chatSvc, gateway, channelIdentitySvc, policySvc, nil, "", 0)
+	sender := &fakeReplySender{}
+
+	cfg := channel.ChannelConfig{ID: "cfg-1", BotID: "bot-1"}
+	msg := channel.InboundMessage{
+		BotID:       "bot-1",
+		Channel:     channel.ChannelType("telegram"),
+		Message:     channel.Message{ID: "msg-annotated", Text: "hello"},
+		ReplyTarget: "chat-123",
+		Sender:      channel.Identity{SubjectID: "user-1"},
+		Conversation: channel.Conversation{
+			ID:   "conv-annotated",
+			Type: channel.ConversationTypePrivate,
+		},
+	}
+
+	if err := processor.HandleInbound(context.Background(), cfg, msg, sender); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gateway.gotReq.ConversationNotes != "Regular customer, prefers German." {
+		t.Fatalf("expected route notes in chat request, got %q", gateway.gotReq.ConversationNotes)
+	}
+	if len(gateway.gotReq.ConversationTags) != 2 || gateway.gotReq.ConversationTags[0] != "vip" || gateway.gotReq.ConversationTags[1] != "de" {
+		t.Fatalf("expected route tags in chat request, got %v", gateway.gotReq.ConversationTags)
+	}
+}
+
+func TestBuildAssetRefsTakesRoleFromStreamEvent(t *testing.T) {
+	t.Parallel()
+
+	chunk := `{"type":"attachment_delta","attachments":[` +
+		`{"type":"image","content_hash":"h1","metadata":{"asset_role":"chart"}},` +
+		`{"type":"file","content_hash":"h2"}]}`
+	events, _, err := mapStreamChunkToChannelEvents(conversation.StreamChunk([]byte(chunk)), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events) != 1 || events[0].Type != channel.StreamEventAttachment {
+		t.Fatalf("expected one attachment event, got %+v", events)
+	}
+
+	refs := buildAssetRefs(events[0].Attachments, 0)
+	if len(refs) != 2 {
+		t.Fatalf("expected 2 refs, got %d", len(refs))
+	}
+	if refs[0].Role != "chart" {
+		t.Fatalf("expected role from metadata, got %q", refs[0].Role)
+	}
+	if refs[1].Role != "attachment" {
+		t.Fatalf("expected default role, got %q", refs[1].Role)
+	}
+}
+
+func TestProcessingStatusNotifyUsesConfiguredTimeout(t *testing.T) {
+	t.Parallel()
+
+	processor := &ChannelInboundProcessor{logger: slog.New(slog.DiscardHandler)}
+	ctx := context.Background()
+	msg := channel.InboundMessage{Channel: channel.ChannelType("feishu")}
+	info := channel.ProcessingStatusInfo{}
+
+	cases := []struct {
+		name    string
+		routing map[string]any
+		want    time.Duration
+	}{
+		{name: "configured", routing: map[string]any{channel.ProcessingStatusTimeoutRoutingKey: float64(5)}, want: 5 * time.Second},
+		{name: "default", routing: nil, want: channel.DefaultProcessingStatusTimeout},
+		{name: "invalid", routing: map[string]any{channel.ProcessingStatusTimeoutRoutingKey: "never"}, want: channel.DefaultProcessingStatusTimeout},
+	}
+	for _, tc := range cases {
+		notifier := &fakeProcessingStatusNotifier{}
+		cfg := channel.ChannelConfig{ChannelType: channel.ChannelType("feishu"), Routing: tc.routing}
+
+		handle, err := processor.notifyProcessingStarted(ctx, notifier, cfg, msg, info)
+		if err != nil {
+			t.Fatalf("%s: started: %v", tc.name, err)
+		}
+		if err := processor.notifyProcessingCompleted(ctx, notifier, cfg, msg, info, handle); err != nil {
+			t.Fatalf("%s: completed: %v", tc.name, err)
+		}
+		if err := processor.notifyProcessingFailed(ctx, notifier, cfg, msg, info, handle, errors.New("boom")); err != nil {
+			t.Fatalf("%s: failed: %v", tc.name, err)
+		}
+
+		if len(notifier.timeouts) != 3 {
+			t.Fatalf("%s: status contexts with deadline = %d, want 3", tc.name, len(notifier.timeouts))
+		}
+		for _, got := range notifier.timeouts {
+			if got > tc.want || got < tc.want-time.Second {
+				t.Fatalf("%s: status context timeout = %v, want about %v", tc.name, got, tc.want)
+			}
+		}
+	}
+}
@@ -76,15 +76,23 @@ type BindService interface {
 	Consume(ctx context.Context, code bind.Code, channelIdentityID string) error
 }
 
+// SenderBlockList reports whether a bot has blocked a channel identity.
+// notify is true only for the first message after a block with notice enabled.
+type SenderBlockList interface {
+	CheckBlockedSender(ctx context.Context, botID, channelIdentityID string) (blocked bool, notify bool, err error)
+}
+
 // IdentityResolver implements identity resolution with bind code and bot scope checks.
 type IdentityResolver struct {
 	registry          *channel.Registry
 	channelIdentities ChannelIdentityService
 	policy            PolicyService
 	bind              BindService
+	blockList         SenderBlockList
 	logger            *slog.Logger
 	unboundReply      string
 	bindReply         string
+	blockedReply      string
 }
 
 // NewIdentityResolver creates an IdentityResolver.
@@ -110,9 +118,18 @@ func NewIdentityResolver(
 		logger:            log.With(slog.String("component", "channel_identity")),
 		unboundReply:      unboundReply,
 		bindReply:         "Binding successful! Your identity has been linked.",
+		blockedReply:      "You have been blocked from messaging this bot.",
 	}
 }
 
+// SetBlockList configures the per-bot sender block list.
+func (r *IdentityResolver) SetBlockList(blockList SenderBlockList) {
+	if r == nil {
+		return
+	}
+	r.blockList = blockList
+}
+
 // Middleware returns a channel middleware that resolves identity before processing.
 func (r *IdentityResolver) Middleware() channel.Middleware {
 	return func(next channel.InboundHandler) channel.InboundHandler {
@@ -191,6 +208,22 @@ func (r *IdentityResolver) Resolve(ctx context.Context, cfg channel.ChannelConfi
 		}
 	}
 
+	// Blocked senders are dropped, optionally with a one-time notice.
+	if r.blockList != nil {
+		blocked, notify, err := r.blockList.CheckBlockedSender(ctx, botID, channelIdentityID)
+		if err != nil {
+			return state, err
+		}
+		if blocked {
+			decision := IdentityDecision{Stop: true}
+			if notify {
+				decision.Reply = channel.Message{Text: r.blockedReply}
+			}
+			state.Decision = &decision
+			return state, nil
+		}
+	}
+
 	// Non-owner messages pass identity resolution; downstream ACL decides allow/deny.
 	return state, nil
 }
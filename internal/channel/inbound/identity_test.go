@@ -733,3 +733,55 @@ func TestIdentityResolverBindCodePlatformMismatch(t *testing.T) {
 		t.Fatal("platform mismatch should return stop decision")
 	}
 }
+
+type fakeSenderBlockList struct {
+	blocked  map[string]bool
+	notify   bool
+	notified bool
+}
+
+func (f *fakeSenderBlockList) CheckBlockedSender(_ context.Context, botID, channelIdentityID string) (bool, bool, error) {
+	if !f.blocked[botID+":"+channelIdentityID] {
+		return false, false, nil
+	}
+	if !f.notify || f.notified {
+		return true, false, nil
+	}
+	f.notified = true
+	return true, true, nil
+}
+
+func TestIdentityResolverBlockedSenderNoticeOnce(t *testing.T) {
+	channelIdentitySvc := &fakeChannelIdentityService{channelIdentity: identities.ChannelIdentity{ID: "channelIdentity-blocked"}}
+	resolver := NewIdentityResolver(slog.Default(), nil, channelIdentitySvc, &fakePolicyService{}, nil, "")
+	resolver.SetBlockList(&fakeSenderBlockList{
+		blocked: map[string]bool{"bot-1:channelIdentity-blocked": true},
+		notify:  true,
+	})
+
+	msg := channel.InboundMessage{
+		BotID:       "bot-1",
+		Channel:     channel.ChannelType("telegram"),
+		Message:     channel.Message{Text: "hello"},
+		ReplyTarget: "target-id",
+		Sender:      channel.Identity{SubjectID: "ext-blocked"},
+	}
+	state, err := resolver.Resolve(context.Background(), channel.ChannelConfig{BotID: "bot-1"}, msg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if state.Decision == nil || !state.Decision.Stop {
+		t.Fatal("expected blocked sender to be stopped")
+	}
+	if state.Decision.Reply.PlainText() != "You have been blocked from messaging this bot." {
+		t.Fatalf("expected block notice, got %q", state.Decision.Reply.PlainText())
+	}
+
+	state, err = resolver.Resolve(context.Background(), channel.ChannelConfig{BotID: "bot-1"}, msg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if state.Decision == nil || !state.Decision.Stop || !state.Decision.Reply.IsEmpty() {
+		t.Fatalf("expected silent drop after the notice, got %+v", state.Decision)
+	}
+}
@@ -0,0 +1,43 @@
+package inbound
+
+import (
+	"strings"
+
+	"github.com/memohai/memoh/internal/channel"
+)
+
+// buildReplyRefs returns the reply reference for the reply stream and the
+// thread reference for final messages, following mode. The reply reference is
+// never nil so adapters do not fall back to replying to the source message.
+// Thread mode needs the Threads capability and otherwise replies to the
+// message.
+func buildReplyRefs(mode channel.ReplyMode, caps channel.ChannelCapabilities, target, sourceMessageID, threadID string) (*channel.ReplyRef, *channel.ThreadRef) {
+	reply := &channel.ReplyRef{Target: target}
+	switch mode {
+	case channel.ReplyModeNone:
+		return reply, nil
+	case channel.ReplyModeThread:
+		id := strings.TrimSpace(threadID)
+		if id == "" {
+			id = sourceMessageID
+		}
+		if caps.Threads && id != "" {
+			return reply, &channel.ThreadRef{ID: id}
+		}
+	}
+	reply.MessageID = sourceMessageID
+	return reply, nil
+}
+
+// applyReplyRefs sets the references built by buildReplyRefs on msg unless
+// it already carries its own.
+func applyReplyRefs(msg *channel.Message, reply *channel.ReplyRef, thread *channel.ThreadRef) {
+	if msg.Reply == nil && reply != nil && reply.MessageID != "" {
+		ref := *reply
+		msg.Reply = &ref
+	}
+	if msg.Thread == nil && thread != nil {
+		ref := *thread
+		msg.Thread = &ref
+	}
+}
@@ -0,0 +1,113 @@
+package inbound
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/memohai/memoh/internal/channel"
+	"github.com/memohai/memoh/internal/channel/identities"
+	"github.com/memohai/memoh/internal/channel/route"
+	"github.com/memohai/memoh/internal/conversation"
+)
+
+func TestBuildReplyRefs(t *testing.T) {
+	t.Parallel()
+
+	threaded := channel.ChannelCapabilities{Text: true, Threads: true}
+	tests := []struct {
+		name       string
+		mode       channel.ReplyMode
+		caps       channel.ChannelCapabilities
+		threadID   string
+		wantReply  string
+		wantThread string
+	}{
+		{name: "message", mode: channel.ReplyModeMessage, caps: threaded, wantReply: "m1"},
+		{name: "none", mode: channel.ReplyModeNone, caps: threaded},
+		{name: "thread uses inbound thread", mode: channel.ReplyModeThread, caps: threaded, threadID: "t1", wantThread: "t1"},
+		{name: "thread starts at source message", mode: channel.ReplyModeThread, caps: threaded, wantThread: "m1"},
+		{name: "thread without capability replies", mode: channel.ReplyModeThread, caps: channel.ChannelCapabilities{Text: true}, threadID: "t1", wantReply: "m1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			reply, thread := buildReplyRefs(tt.mode, tt.caps, "target", "m1", tt.threadID)
+			if reply == nil || reply.Target != "target" {
+				t.Fatalf("expected reply ref with target, got %+v", reply)
+			}
+			if reply.MessageID != tt.wantReply {
+				t.Fatalf("expected reply message %q, got %q", tt.wantReply, reply.MessageID)
+			}
+			gotThread := ""
+			if thread != nil {
+				gotThread = thread.ID
+			}
+			if gotThread != tt.wantThread {
+				t.Fatalf("expected thread %q, got %q", tt.wantThread, gotThread)
+			}
+		})
+	}
+}
+
+func TestChannelInboundProcessorReplyMode(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		routing   map[string]any
+		wantReply bool
+	}{
+		{name: "default replies to message", wantReply: true},
+		{name: "message", routing: map[string]any{channel.ReplyModeRoutingKey: "message"}, wantReply: true},
+		{name: "none sends fresh", routing: map[string]any{channel.ReplyModeRoutingKey: "none"}},
+		{name: "thread falls back to message", routing: map[string]any{channel.ReplyModeRoutingKey: "thread"}, wantReply: true},
+		{name: "invalid falls back to message", routing: map[string]any{channel.ReplyModeRoutingKey: "sideways"}, wantReply: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			chatSvc := &fakeChatService{resolveResult: route.ResolveConversationResult{ChatID: "chat-1", RouteID: "route-1"}}
+			gateway := &fakeChatGateway{
+				resp: conversation.ChatResponse{
+					Messages: []conversation.ModelMessage{
+						{Role: "assistant", Content: conversation.NewTextContent("AI reply")},
+					},
+				},
+			}
+			processor := NewChannelInboundProcessor(slog.Default(), nil, chatSvc, chatSvc, gateway,
+				&fakeChannelIdentityService{channelIdentity: identities.ChannelIdentity{ID: "channelIdentity-1"}},
+				&fakePolicyService{}, nil, "", 0)
+			sender := &fakeReplySender{}
+			cfg := channel.ChannelConfig{ID: "cfg-1", BotID: "bot-1", ChannelType: channel.ChannelType("feishu"), Routing: tt.routing}
+			msg := channel.InboundMessage{
+				BotID:        "bot-1",
+				Channel:      channel.ChannelType("feishu"),
+				Message:      channel.Message{ID: "src-1", Text: "hello"},
+				ReplyTarget:  "target-id",
+				Sender:       channel.Identity{SubjectID: "ext-1", DisplayName: "User1"},
+				Conversation: channel.Conversation{ID: "chat-1", Type: channel.ConversationTypePrivate},
+			}
+
+			if err := processor.HandleInbound(context.Background(), cfg, msg, sender); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(sender.sent) != 1 {
+				t.Fatalf("expected one reply, got %+v", sender.sent)
+			}
+			reply := sender.sent[0].Message.Reply
+			if tt.wantReply {
+				if reply == nil || reply.MessageID != "src-1" {
+					t.Fatalf("expected reply to src-1, got %+v", reply)
+				}
+			} else if reply != nil {
+				t.Fatalf("expected a fresh message, got reply %+v", reply)
+			}
+			if sender.sent[0].Message.Thread != nil {
+				t.Fatalf("expected no thread ref, got %+v", sender.sent[0].Message.Thread)
+			}
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package inbound
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/memohai/memoh/internal/channel"
+)
+
+// defaultStreamBufferSize bounds how many events may wait for a slow reply
+// stream before Push blocks. Consecutive text deltas share one slot.
+const defaultStreamBufferSize = 64
+
+var errStreamClosed = errors.New("reply stream closed")
+
+// bufferedStream decouples the chat stream reader from a slow reply stream
+// (for example a platform rate limit). Events are delivered in order by a
+// background goroutine; while it is busy, consecutive text deltas are merged
+// into one event so the backlog stays bounded. When the buffer is full of
+// other events, Push blocks until the downstream catches up.
+//
+// With a positive flush interval, text deltas are also held back so the
+// downstream receives at most one delta per interval; any other event, or
+// Close, flushes a held delta immediately.
+type bufferedStream struct {
+	target        channel.OutboundStream
+	ctx           context.Context
+	size          int
+	flushInterval time.Duration
+
+	mu        sync.Mutex
+	queue     []channel.StreamEvent
+	err       error
+	closed    bool
+	lastDelta time.Time
+
+	ready chan struct{} // signals the sender that events are queued
+	space chan struct{} // signals Push that a slot was freed
+	done  chan struct{} // closed when the sender exits
+}
+
+// newBufferedStream wraps target and starts the background sender. Events are
+// pushed to target with ctx. A zero flushInterval sends deltas as soon as the
+// downstream is free.
+func newBufferedStream(ctx context.Context, target channel.OutboundStream, size int, flushInterval time.Duration) *bufferedStream {
+	if size <= 0 {
+		size = defaultStreamBufferSize
+	}
+	s := &bufferedStream{
+		target:        target,
+		ctx:           ctx,
+		size:          size,
+		flushInterval: flushInterval,
+		ready:         make(chan struct{}, 1),
+		space:         make(chan struct{}, 1),
+		done:          make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+// Push queues event for delivery. It returns the first downstream error, if
+// any, so callers stop producing once the reply stream has failed.
+func (s *bufferedStream) Push(ctx context.Context, event channel.StreamEvent) error {
+	for {
+		s.mu.Lock()
+		if s.err != nil {
+			err := s.err
+			s.mu.Unlock()
+			return err
+		}
+		if s.closed {
+			s.mu.Unlock()
+			return errStreamClosed
+		}
+		if n := len(s.queue); n > 0 && canMergeDelta(s.queue[n-1], event) {
+			s.queue[n-1].Delta += event.Delta
+			s.mu.Unlock()
+			return nil
+		}
+		if len(s.queue) < s.size {
+			s.queue = append(s.queue, event)
+			s.mu.Unlock()
+			notifyStream(s.ready)
+			return nil
+		}
+		s.mu.Unlock()
+		select {
+		case <-s.space:
+		case <-s.done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Close waits for queued events to be delivered, then closes the target.
+func (s *bufferedStream) Close(ctx context.Context) error {
+	s.mu.Lock()
+	s.closed = true
+	s.mu.Unlock()
+	notifyStream(s.ready)
+	select {
+	case <-s.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	closeErr := s.target.Close(ctx)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	return closeErr
+}
+
+func (s *bufferedStream) run() {
+	defer close(s.done)
+	for {
+		s.mu.Lock()
+		if len(s.queue) == 0 {
+			closed := s.closed
+			s.mu.Unlock()
+			if closed {
+				return
+			}
+			<-s.ready
+			continue
+		}
+		event := s.queue[0]
+		if wait := s.deltaHold(event); wait > 0 {
+			s.mu.Unlock()
+			timer := time.NewTimer(wait)
+			select {
+			case <-s.ready:
+			case <-timer.C:
+			}
+			timer.Stop()
+			continue
+		}
+		if event.Type == channel.StreamEventDelta {
+			s.lastDelta = time.Now()
+		}
+		s.queue = s.queue[1:]
+		s.mu.Unlock()
+		notifyStream(s.space)
+
+		if err := s.target.Push(s.ctx, event); err != nil {
+			s.mu.Lock()
+			s.err = err
+			s.queue = nil
+			s.mu.Unlock()
+			return
+		}
+	}
+}
+
+// deltaHold returns how long head, the only queued event, should wait for
+// more deltas before it is sent. It must be called with s.mu held.
+func (s *bufferedStream) deltaHold(head channel.StreamEvent) time.Duration {
+	if s.flushInterval <= 0 || s.closed || len(s.queue) > 1 || head.Type != channel.StreamEventDelta {
+		return 0
+	}
+	return time.Until(s.lastDelta.Add(s.flushInterval))
+}
+
+// canMergeDelta reports whether next can be appended to a queued delta without
+// changing what the downstream renders.
+func canMergeDelta(queued, next channel.StreamEvent) bool {
+	return queued.Type == channel.StreamEventDelta &&
+		next.Type == channel.StreamEventDelta &&
+		queued.Phase == next.Phase &&
+		len(queued.Metadata) == 0 &&
+		len(next.Metadata) == 0
+}
+
+// notifyStream wakes a waiter on ch without blocking.
+func notifyStream(ch chan struct{}) {
+	select {
+	case ch <- struct{}{}:
+	default:
+	}
+}
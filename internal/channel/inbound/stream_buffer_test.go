@@ -0,0 +1,267 @@
+package inbound
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/memohai/memoh/internal/channel"
+)
+
+// slowOutboundStream blocks every Push until release is closed.
+type slowOutboundStream struct {
+	release chan struct{}
+	err     error
+
+	mu     sync.Mutex
+	events []channel.StreamEvent
+	closed bool
+}
+
+func (s *slowOutboundStream) Push(ctx context.Context, event channel.StreamEvent) error {
+	select {
+	case <-s.release:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	if s.err != nil {
+		return s.err
+	}
+	s.mu.Lock()
+	s.events = append(s.events, event)
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *slowOutboundStream) Close(context.Context) error {
+	s.mu.Lock()
+	s.closed = true
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *slowOutboundStream) lastEventIs(eventType channel.StreamEventType) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.events) > 0 && s.events[len(s.events)-1].Type == eventType
+}
+
+func pushWithin(t *testing.T, stream channel.OutboundStream, event channel.StreamEvent) error {
+	t.Helper()
+	errCh := make(chan error, 1)
+	go func() { errCh <- stream.Push(context.Background(), event) }()
+	select {
+	case err := <-errCh:
+		return err
+	case <-time.After(2 * time.Second):
+		t.Fatalf("push blocked on slow downstream: %+v", event)
+		return nil
+	}
+}
+
+func TestBufferedStreamCoalescesDeltasWhileDownstreamIsSlow(t *testing.T) {
+	target := &slowOutboundStream{release: make(chan struct{})}
+	stream := newBufferedStream(context.Background(), target, 4, 0)
+
+	if err := pushWithin(t, stream, channel.StreamEvent{Type: channel.StreamEventStatus, Status: channel.StreamStatusStarted}); err != nil {
+		t.Fatalf("push status: %v", err)
+	}
+	var want strings.Builder
+	for i := 0; i < 500; i++ {
+		want.WriteString("ab")
+		if err := pushWithin(t, stream, channel.StreamEvent{Type: channel.StreamEventDelta, Delta: "ab"}); err != nil {
+			t.Fatalf("push delta %d: %v", i, err)
+		}
+	}
+	close(target.release)
+	if err := stream.Close(context.Background()); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	target.mu.Lock()
+	defer target.mu.Unlock()
+	if !target.closed {
+		t.Fatal("expected target to be closed")
+	}
+	if len(target.events) < 2 || len(target.events) > 3 {
+		t.Fatalf("expected deltas to coalesce into at most 2 events, got %d events", len(target.events))
+	}
+	if target.events[0].Type != channel.StreamEventStatus {
+		t.Fatalf("expected status event first, got %+v", target.events[0])
+	}
+	var got strings.Builder
+	for _, event := range target.events[1:] {
+		if event.Type != channel.StreamEventDelta {
+			t.Fatalf("unexpected event type %q", event.Type)
+		}
+		got.WriteString(event.Delta)
+	}
+	if got.String() != want.String() {
+		t.Fatalf("coalesced text mismatch: got %d bytes, want %d", got.Len(), want.Len())
+	}
+}
+
+func TestBufferedStreamKeepsEventOrderAcrossPhases(t *testing.T) {
+	target := &slowOutboundStream{release: make(chan struct{})}
+	stream := newBufferedStream(context.Background(), target, 8, 0)
+
+	events := []channel.StreamEvent{
+		{Type: channel.StreamEventDelta, Delta: "think ", Phase: channel.StreamPhaseReasoning},
+		{Type: channel.StreamEventDelta, Delta: "more", Phase: channel.StreamPhaseReasoning},
+		{Type: channel.StreamEventDelta, Delta: "Hello ", Phase: channel.StreamPhaseText},
+		{Type: channel.StreamEventToolCallStart},
+		{Type: channel.StreamEventDelta, Delta: "world", Phase: channel.StreamPhaseText},
+	}
+	for _, event := range events {
+		if err := pushWithin(t, stream, event); err != nil {
+			t.Fatalf("push: %v", err)
+		}
+	}
+	close(target.release)
+	if err := stream.Close(context.Background()); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	target.mu.Lock()
+	defer target.mu.Unlock()
+	var got []string
+	for _, event := range target.events {
+		got = append(got, string(event.Type)+":"+event.Delta)
+	}
+	// The first reasoning delta may be taken by the sender before the second
+	// arrives, so both "think more" and "think "/"more" are valid.
+	joined := strings.Join(got, "|")
+	if joined != "delta:think more|delta:Hello |tool_call_start:|delta:world" &&
+		joined != "delta:think |delta:more|delta:Hello |tool_call_start:|delta:world" {
+		t.Fatalf("unexpected event order: %s", joined)
+	}
+}
+
+func TestBufferedStreamBlocksWhenFullWithoutDeadlock(t *testing.T) {
+	target := &slowOutboundStream{release: make(chan struct{})}
+	stream := newBufferedStream(context.Background(), target, 2, 0)
+
+	done := make(chan error, 1)
+	go func() {
+		for i := 0; i < 10; i++ {
+			if err := stream.Push(context.Background(), channel.StreamEvent{Type: channel.StreamEventToolCallStart}); err != nil {
+				done <- err
+				return
+			}
+		}
+		done <- nil
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("expected push to block once the buffer is full")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(target.release)
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("push: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("push still blocked after downstream recovered")
+	}
+	if err := stream.Close(context.Background()); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	target.mu.Lock()
+	defer target.mu.Unlock()
+	if len(target.events) != 10 {
+		t.Fatalf("expected all 10 events delivered, got %d", len(target.events))
+	}
+}
+
+func TestBufferedStreamSurfacesDownstreamError(t *testing.T) {
+	release := make(chan struct{})
+	close(release)
+	target := &slowOutboundStream{release: release, err: errors.New("rate limited")}
+	stream := newBufferedStream(context.Background(), target, 4, 0)
+
+	_ = stream.Push(context.Background(), channel.StreamEvent{Type: channel.StreamEventDelta, Delta: "x"})
+	if err := stream.Close(context.Background()); err == nil || err.Error() != "rate limited" {
+		t.Fatalf("expected downstream error from close, got %v", err)
+	}
+	if err := stream.Push(context.Background(), channel.StreamEvent{Type: channel.StreamEventDelta, Delta: "y"}); err == nil {
+		t.Fatal("expected push after failure to return an error")
+	}
+}
+
+func TestBufferedStreamFlushIntervalCoalescesBurst(t *testing.T) {
+	release := make(chan struct{})
+	close(release)
+	target := &slowOutboundStream{release: release}
+	stream := newBufferedStream(context.Background(), target, 8, time.Hour)
+
+	var want strings.Builder
+	for i := 0; i < 200; i++ {
+		want.WriteString("x")
+		if err := pushWithin(t, stream, channel.StreamEvent{Type: channel.StreamEventDelta, Delta: "x", Phase: channel.StreamPhaseText}); err != nil {
+			t.Fatalf("push delta %d: %v", i, err)
+		}
+	}
+	if err := stream.Close(context.Background()); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	target.mu.Lock()
+	defer target.mu.Unlock()
+	// The first delta goes out at once; the rest of the burst is held for the
+	// interval and flushed as one event on close.
+	if len(target.events) > 2 {
+		t.Fatalf("expected burst to coalesce into at most 2 events, got %d", len(target.events))
+	}
+	var got strings.Builder
+	for _, event := range target.events {
+		got.WriteString(event.Delta)
+	}
+	if got.String() != want.String() {
+		t.Fatalf("coalesced text mismatch: got %q", got.String())
+	}
+}
+
+func TestBufferedStreamFlushIntervalFlushesBeforeOtherEvents(t *testing.T) {
+	release := make(chan struct{})
+	close(release)
+	target := &slowOutboundStream{release: release}
+	stream := newBufferedStream(context.Background(), target, 8, time.Hour)
+
+	for _, delta := range []string{"a", "b", "c"} {
+		if err := pushWithin(t, stream, channel.StreamEvent{Type: channel.StreamEventDelta, Delta: delta}); err != nil {
+			t.Fatalf("push delta: %v", err)
+		}
+	}
+	if err := pushWithin(t, stream, channel.StreamEvent{Type: channel.StreamEventToolCallStart}); err != nil {
+		t.Fatalf("push tool call: %v", err)
+	}
+
+	// The tool call must release the held delta without waiting for the
+	// (hour-long) interval or for Close.
+	deadline := time.Now().Add(2 * time.Second)
+	for !target.lastEventIs(channel.StreamEventToolCallStart) {
+		if time.Now().After(deadline) {
+			t.Fatal("held delta was not flushed by the following event")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if err := stream.Close(context.Background()); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	target.mu.Lock()
+	defer target.mu.Unlock()
+	var text strings.Builder
+	for _, event := range target.events[:len(target.events)-1] {
+		text.WriteString(event.Delta)
+	}
+	if text.String() != "abc" {
+		t.Fatalf("expected deltas delivered before the tool call, got %q", text.String())
+	}
+}
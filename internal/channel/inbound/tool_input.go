@@ -0,0 +1,49 @@
+package inbound
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// toolInputAccumulator rebuilds tool call arguments that the agent streams
+// as tool_input_delta events, keyed by tool call ID. It is used by a single
+// stream and is not safe for concurrent use.
+type toolInputAccumulator struct {
+	names  map[string]string
+	inputs map[string]*strings.Builder
+}
+
+func newToolInputAccumulator() *toolInputAccumulator {
+	return &toolInputAccumulator{
+		names:  map[string]string{},
+		inputs: map[string]*strings.Builder{},
+	}
+}
+
+// add appends delta to the call's arguments and returns the tool name. It
+// does not return the arguments so far: copying them on every delta would
+// make a long argument stream quadratic.
+func (a *toolInputAccumulator) add(callID, toolName, delta string) string {
+	if toolName != "" {
+		a.names[callID] = toolName
+	}
+	buf, ok := a.inputs[callID]
+	if !ok {
+		buf = &strings.Builder{}
+		a.inputs[callID] = buf
+	}
+	buf.WriteString(delta)
+	return a.names[callID]
+}
+
+// take returns the accumulated arguments of a call as JSON and forgets the
+// call. It returns nil when nothing was streamed or the text is not JSON.
+func (a *toolInputAccumulator) take(callID string) json.RawMessage {
+	buf, ok := a.inputs[callID]
+	delete(a.inputs, callID)
+	delete(a.names, callID)
+	if !ok || !json.Valid([]byte(buf.String())) {
+		return nil
+	}
+	return json.RawMessage(buf.String())
+}
@@ -19,12 +19,19 @@ type LifecycleStore interface {
 // ConnectionController controls runtime channel connections.
 type ConnectionController interface {
 	EnsureConnection(ctx context.Context, cfg ChannelConfig) error
+	RestartConnection(ctx context.Context, cfg ChannelConfig) error
 	RemoveConnection(ctx context.Context, botID string, channelType ChannelType)
 }
 
 // ErrEnableChannelFailed indicates that enabling the channel (e.g. EnsureConnection) failed.
 var ErrEnableChannelFailed = errors.New("enable channel failed")
 
+// ErrChannelDisabled indicates that a disabled channel cannot be restarted.
+var ErrChannelDisabled = errors.New("channel is disabled")
+
+// ErrBotPendingDeletion indicates that a bot pending deletion cannot be reconnected.
+var ErrBotPendingDeletion = errors.New("bot is pending deletion")
+
 // Lifecycle coordinates persisted config updates and runtime connection state.
 type Lifecycle struct {
 	store      LifecycleStore
@@ -122,6 +129,28 @@ func (s *Lifecycle) SetBotChannelStatus(ctx context.Context, botID string, chann
 	return updated, nil
 }
 
+// RestartBotChannel cycles the runtime connection of an enabled channel
+// without changing its persisted config.
+func (s *Lifecycle) RestartBotChannel(ctx context.Context, botID string, channelType ChannelType) (ChannelConfig, error) {
+	if s.store == nil {
+		return ChannelConfig{}, errors.New("channel lifecycle store not configured")
+	}
+	if s.controller == nil {
+		return ChannelConfig{}, errors.New("channel connection controller not configured")
+	}
+	cfg, err := s.store.ResolveEffectiveConfig(ctx, botID, channelType)
+	if err != nil {
+		return ChannelConfig{}, err
+	}
+	if cfg.Disabled {
+		return ChannelConfig{}, ErrChannelDisabled
+	}
+	if err := s.controller.RestartConnection(ctx, cfg); err != nil {
+		return ChannelConfig{}, err
+	}
+	return cfg, nil
+}
+
 func (s *Lifecycle) getPreviousConfig(ctx context.Context, botID string, channelType ChannelType) (ChannelConfig, bool, error) {
 	cfg, err := s.store.ResolveEffectiveConfig(ctx, botID, channelType)
 	if err == nil {
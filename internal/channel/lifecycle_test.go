@@ -42,8 +42,9 @@ func (f *fakeLifecycleStore) DeleteConfig(ctx context.Context, botID string, cha
 }
 
 type fakeConnectionController struct {
-	ensureFunc func(ctx context.Context, cfg ChannelConfig) error
-	removeFunc func(ctx context.Context, botID string, channelType ChannelType)
+	ensureFunc  func(ctx context.Context, cfg ChannelConfig) error
+	restartFunc func(ctx context.Context, cfg ChannelConfig) error
+	removeFunc  func(ctx context.Context, botID string, channelType ChannelType)
 }
 
 func (f *fakeConnectionController) EnsureConnection(ctx context.Context, cfg ChannelConfig) error {
@@ -53,6 +54,13 @@ func (f *fakeConnectionController) EnsureConnection(ctx context.Context, cfg Cha
 	return f.ensureFunc(ctx, cfg)
 }
 
+func (f *fakeConnectionController) RestartConnection(ctx context.Context, cfg ChannelConfig) error {
+	if f.restartFunc == nil {
+		return nil
+	}
+	return f.restartFunc(ctx, cfg)
+}
+
 func (f *fakeConnectionController) RemoveConnection(ctx context.Context, botID string, channelType ChannelType) {
 	if f.removeFunc == nil {
 		return
@@ -292,3 +300,65 @@ func TestLifecycleSetBotChannelStatusEnableFailureRollsBack(t *testing.T) {
 		t.Fatalf("expected remove connection to be called on failed enable")
 	}
 }
+
+func TestLifecycleRestartBotChannel(t *testing.T) {
+	t.Parallel()
+
+	var restarted []ChannelConfig
+	store := &fakeLifecycleStore{
+		resolveFunc: func(_ context.Context, botID string, channelType ChannelType) (ChannelConfig, error) {
+			return ChannelConfig{ID: "cfg-1", BotID: botID, ChannelType: channelType}, nil
+		},
+		upsertFunc: func(context.Context, string, ChannelType, UpsertConfigRequest) (ChannelConfig, error) {
+			t.Fatal("restart must not change the config")
+			return ChannelConfig{}, nil
+		},
+	}
+	controller := &fakeConnectionController{
+		restartFunc: func(_ context.Context, cfg ChannelConfig) error {
+			restarted = append(restarted, cfg)
+			return nil
+		},
+		removeFunc: func(context.Context, string, ChannelType) {
+			t.Fatal("restart must not remove the connection")
+		},
+	}
+	service := NewLifecycle(store, controller)
+
+	cfg, err := service.RestartBotChannel(context.Background(), "bot-1", ChannelType("telegram"))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(restarted) != 1 || restarted[0].ID != "cfg-1" || cfg.ID != "cfg-1" {
+		t.Fatalf("expected cfg-1 to be restarted once, got %+v", restarted)
+	}
+}
+
+func TestLifecycleRestartBotChannelRejectsDisabledAndMissing(t *testing.T) {
+	t.Parallel()
+
+	restartCalled := false
+	controller := &fakeConnectionController{
+		restartFunc: func(context.Context, ChannelConfig) error {
+			restartCalled = true
+			return nil
+		},
+	}
+
+	disabled := NewLifecycle(&fakeLifecycleStore{
+		resolveFunc: func(_ context.Context, botID string, channelType ChannelType) (ChannelConfig, error) {
+			return ChannelConfig{ID: "cfg-1", BotID: botID, ChannelType: channelType, Disabled: true}, nil
+		},
+	}, controller)
+	if _, err := disabled.RestartBotChannel(context.Background(), "bot-1", ChannelType("telegram")); !errors.Is(err, ErrChannelDisabled) {
+		t.Fatalf("expected ErrChannelDisabled, got %v", err)
+	}
+
+	missing := NewLifecycle(&fakeLifecycleStore{}, controller)
+	if _, err := missing.RestartBotChannel(context.Background(), "bot-1", ChannelType("telegram")); !errors.Is(err, ErrChannelConfigNotFound) {
+		t.Fatalf("expected ErrChannelConfigNotFound, got %v", err)
+	}
+	if restartCalled {
+		t.Fatal("expected no restart for disabled or missing channels")
+	}
+}
@@ -45,6 +45,11 @@ type ManagerStore interface {
 	ConfigResolver
 }
 
+// DeliveryRecorder persists outbound delivery receipts.
+type DeliveryRecorder interface {
+	RecordDelivery(ctx context.Context, receipt DeliveryReceipt) error
+}
+
 // ConnectionStatus describes runtime status for one configured channel connection.
 type ConnectionStatus struct {
 	ConfigID    string      `json:"config_id"`
@@ -53,6 +58,9 @@ type ConnectionStatus struct {
 	Running     bool        `json:"running"`
 	LastError   string      `json:"last_error,omitempty"`
 	UpdatedAt   time.Time   `json:"updated_at"`
+	// LastEventAt is when the connection last delivered an inbound
+	// message; nil if it has not delivered one yet.
+	LastEventAt *time.Time `json:"last_event_at,omitempty"`
 }
 
 // Manager coordinates channel adapters, connection lifecycle, and message dispatch.
@@ -63,6 +71,8 @@ type Manager struct {
 	service         ManagerStore
 	processor       InboundProcessor
 	attachmentStore OutboundAttachmentStore
+	deliveries      DeliveryRecorder
+	retries         OutboundRetryStore
 	refreshInterval time.Duration
 	logger          *slog.Logger
 	middlewares     []Middleware
@@ -76,6 +86,7 @@ type Manager struct {
 	refreshMu      sync.Mutex
 	connections    map[string]*connectionEntry
 	connectionMeta map[string]ConnectionStatus
+	pausedBots     map[string]struct{}
 }
 
 // ManagerOption configures a Manager during construction.
@@ -126,6 +137,7 @@ func NewManager(log *slog.Logger, registry *Registry, service ManagerStore, proc
 		refreshInterval: 5 * time.Minute,
 		connections:     map[string]*connectionEntry{},
 		connectionMeta:  map[string]ConnectionStatus{},
+		pausedBots:      map[string]struct{}{},
 		logger:          log.With(slog.String("component", "channel")),
 		middlewares:     []Middleware{},
 		inboundQueue:    make(chan inboundTask, 256),
@@ -153,6 +165,18 @@ func (m *Manager) SetAttachmentStore(store OutboundAttachmentStore) {
 	m.attachmentStore = store
 }
 
+// SetDeliveryRecorder wires the store that receives a delivery receipt for
+// every outbound send. Receipts are not recorded when unset.
+func (m *Manager) SetDeliveryRecorder(recorder DeliveryRecorder) {
+	m.deliveries = recorder
+}
+
+// SetOutboundRetryStore enables the persistent retry queue for sends that
+// fail transiently. Failed sends are returned to the caller when unset.
+func (m *Manager) SetOutboundRetryStore(store OutboundRetryStore) {
+	m.retries = store
+}
+
 // RegisterAdapter adds an adapter to the registry and logs the registration.
 func (m *Manager) RegisterAdapter(adapter Adapter) {
 	if adapter == nil {
@@ -210,6 +234,7 @@ func (m *Manager) Start(ctx context.Context) {
 		m.logger.Info("manager start")
 	}
 	m.startInboundWorkers(ctx)
+	m.startOutboundRetries(ctx)
 	go func() {
 		m.refresh(ctx)
 		ticker := time.NewTicker(m.refreshInterval)
@@ -230,41 +255,51 @@ func (m *Manager) Start(ctx context.Context) {
 }
 
 // Send delivers an outbound message to the specified channel, resolving target and config automatically.
+// When a retry store is configured, transient delivery failures are queued for
+// background retries and Send returns an error wrapping ErrOutboundQueued;
+// terminal failures are returned as is.
 func (m *Manager) Send(ctx context.Context, botID string, channelType ChannelType, req SendRequest) error {
+	return m.send(ctx, botID, channelType, req, true)
+}
+
+func (m *Manager) send(ctx context.Context, botID string, channelType ChannelType, req SendRequest, queueOnFailure bool) error {
 	if m.service == nil {
 		return errors.New("channel manager not configured")
 	}
 	sender, ok := m.registry.GetSender(channelType)
 	if !ok {
-		return fmt.Errorf("unsupported channel type: %s", channelType)
+		return Terminal(fmt.Errorf("unsupported channel type: %s", channelType))
 	}
 	config, err := m.service.ResolveEffectiveConfig(ctx, botID, channelType)
 	if err != nil {
+		if errors.Is(err, ErrChannelConfigNotFound) {
+			return Terminal(err)
+		}
 		return err
 	}
 	target := strings.TrimSpace(req.Target)
 	if target == "" {
 		targetChannelIdentityID := strings.TrimSpace(req.ChannelIdentityID)
 		if targetChannelIdentityID == "" {
-			return errors.New("target or channel_identity_id is required")
+			return Terminal(errors.New("target or channel_identity_id is required"))
 		}
 		userCfg, err := m.service.GetChannelIdentityConfig(ctx, targetChannelIdentityID, channelType)
 		if err != nil {
 			if m.logger != nil {
 				m.logger.Warn("channel binding missing", slog.String("channel", channelType.String()), slog.String("channel_identity_id", targetChannelIdentityID))
 			}
-			return errors.New("channel binding required")
+			return Terminal(errors.New("channel binding required"))
 		}
 		target, err = m.registry.ResolveTargetFromUserConfig(channelType, userCfg.Config)
 		if err != nil {
-			return err
+			return Terminal(err)
 		}
 	}
 	if normalized, ok := m.registry.NormalizeTarget(channelType, target); ok {
 		target = normalized
 	}
 	if req.Message.IsEmpty() {
-		return errors.New("message is required")
+		return Terminal(errors.New("message is required"))
 	}
 	if m.logger != nil {
 		m.logger.Info("send outbound", slog.String("channel", channelType.String()), slog.String("bot_id", botID))
@@ -275,13 +310,18 @@ func (m *Manager) Send(ctx context.Context, botID string, channelType ChannelTyp
 		Message: req.Message,
 	}, policy)
 	if err != nil {
-		return err
+		return Terminal(err)
 	}
-	for _, item := range outbound {
-		if err := m.sendWithConfig(ctx, sender, config, item, policy); err != nil {
+	for i, item := range outbound {
+		platformMessageID, err := m.sendWithConfig(ctx, sender, config, item, policy)
+		m.recordDelivery(ctx, config, item.Target, req.HistoryMessageID, platformMessageID, err)
+		if err != nil {
 			if m.logger != nil {
 				m.logger.Error("send outbound failed", slog.String("channel", channelType.String()), slog.String("bot_id", botID), slog.Any("error", err))
 			}
+			if queueOnFailure && m.enqueueOutboundRetries(ctx, botID, channelType, req.HistoryMessageID, outbound[i:], err) {
+				return fmt.Errorf("%w: %w", ErrOutboundQueued, err)
+			}
 			return err
 		}
 	}
@@ -345,15 +385,28 @@ func (m *Manager) ConnectionStatusesByBot(botID string) []ConnectionStatus {
 	if botID == "" {
 		return []ConnectionStatus{}
 	}
+	return m.connectionStatuses(func(status ConnectionStatus) bool { return status.BotID == botID })
+}
+
+// ConnectionStatuses returns observed channel connection statuses for all
+// bots, ordered by bot, channel type and config.
+func (m *Manager) ConnectionStatuses() []ConnectionStatus {
+	return m.connectionStatuses(func(ConnectionStatus) bool { return true })
+}
+
+func (m *Manager) connectionStatuses(keep func(ConnectionStatus) bool) []ConnectionStatus {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	items := make([]ConnectionStatus, 0, len(m.connectionMeta))
 	for _, status := range m.connectionMeta {
-		if status.BotID == botID {
+		if keep(status) {
 			items = append(items, status)
 		}
 	}
 	sort.Slice(items, func(i, j int) bool {
+		if items[i].BotID != items[j].BotID {
+			return items[i].BotID < items[j].BotID
+		}
 		if items[i].ChannelType == items[j].ChannelType {
 			return items[i].ConfigID < items[j].ConfigID
 		}
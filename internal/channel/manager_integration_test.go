@@ -290,6 +290,133 @@ func TestManagerConnectionStatusesByBotTracksConnectFailure(t *testing.T) {
 	}
 }
 
+func TestManagerConnectionStatusesTracksLastInboundEvent(t *testing.T) {
+	t.Parallel()
+
+	log := slog.New(slog.DiscardHandler)
+	reg := NewRegistry()
+	adapter := &fakeAdapter{channelType: ChannelType("test")}
+	manager := NewManager(log, reg, &fakeConfigStore{}, &fakeInboundProcessorIntegration{})
+	manager.RegisterAdapter(adapter)
+
+	cfgs := []ChannelConfig{
+		{ID: "cfg-2", BotID: "bot-2", ChannelType: ChannelType("test"), Credentials: map[string]any{"botToken": "token"}, UpdatedAt: time.Now()},
+		{ID: "cfg-1", BotID: "bot-1", ChannelType: ChannelType("test"), Credentials: map[string]any{"botToken": "token"}, UpdatedAt: time.Now()},
+	}
+	manager.reconcile(context.Background(), cfgs)
+
+	statuses := manager.ConnectionStatuses()
+	if len(statuses) != 2 || statuses[0].BotID != "bot-1" || statuses[1].BotID != "bot-2" {
+		t.Fatalf("expected statuses of both bots ordered by bot, got %+v", statuses)
+	}
+	if statuses[0].LastEventAt != nil {
+		t.Fatal("expected no last event before any inbound message")
+	}
+
+	if err := manager.HandleInbound(context.Background(), cfgs[1], InboundMessage{Channel: ChannelType("test")}); err != nil {
+		t.Fatalf("handle inbound: %v", err)
+	}
+	manager.markConnectionStatus(cfgs[1], true, nil)
+
+	statuses = manager.ConnectionStatusesByBot("bot-1")
+	if len(statuses) != 1 || statuses[0].LastEventAt == nil {
+		t.Fatalf("expected last event to be kept across status updates, got %+v", statuses)
+	}
+	if other := manager.ConnectionStatusesByBot("bot-2"); len(other) != 1 || other[0].LastEventAt != nil {
+		t.Fatalf("expected other bot to have no last event, got %+v", other)
+	}
+	_ = manager.Shutdown(context.Background())
+}
+
+func TestManagerRestartConnectionCyclesAdapter(t *testing.T) {
+	t.Parallel()
+
+	log := slog.New(slog.DiscardHandler)
+	adapter := &fakeAdapter{channelType: ChannelType("test")}
+	manager := NewManager(log, NewRegistry(), &fakeConfigStore{}, &fakeInboundProcessorIntegration{})
+	manager.RegisterAdapter(adapter)
+	defer func() { _ = manager.Shutdown(context.Background()) }()
+
+	cfg := ChannelConfig{
+		ID:          "cfg-1",
+		BotID:       "bot-1",
+		ChannelType: ChannelType("test"),
+		Credentials: map[string]any{"botToken": "token"},
+		UpdatedAt:   time.Now(),
+	}
+	manager.reconcile(context.Background(), []ChannelConfig{cfg})
+	if err := manager.HandleInbound(context.Background(), cfg, InboundMessage{Channel: ChannelType("test")}); err != nil {
+		t.Fatalf("handle inbound: %v", err)
+	}
+
+	if err := manager.RestartConnection(context.Background(), cfg); err != nil {
+		t.Fatalf("restart: %v", err)
+	}
+	adapter.mu.Lock()
+	started, stops := len(adapter.started), adapter.stops
+	adapter.mu.Unlock()
+	if started != 2 || stops != 1 {
+		t.Fatalf("expected stop then reconnect, got %d starts and %d stops", started, stops)
+	}
+	statuses := manager.ConnectionStatusesByBot("bot-1")
+	if len(statuses) != 1 || !statuses[0].Running || statuses[0].LastError != "" {
+		t.Fatalf("expected running status after restart, got %+v", statuses)
+	}
+	if statuses[0].LastEventAt == nil {
+		t.Fatal("expected last event time to survive the restart")
+	}
+
+	adapter.mu.Lock()
+	adapter.connectErr = errors.New("dial failed")
+	adapter.mu.Unlock()
+	if err := manager.RestartConnection(context.Background(), cfg); err == nil {
+		t.Fatal("expected restart to report the reconnect failure")
+	}
+	statuses = manager.ConnectionStatusesByBot("bot-1")
+	if len(statuses) != 1 || statuses[0].Running || statuses[0].LastError == "" {
+		t.Fatalf("expected failed status after failed restart, got %+v", statuses)
+	}
+}
+
+func TestManagerRestartConnectionRejectsPausedBot(t *testing.T) {
+	t.Parallel()
+
+	log := slog.New(slog.DiscardHandler)
+	adapter := &fakeAdapter{channelType: ChannelType("test")}
+	manager := NewManager(log, NewRegistry(), &fakeConfigStore{}, &fakeInboundProcessorIntegration{})
+	manager.RegisterAdapter(adapter)
+	defer func() { _ = manager.Shutdown(context.Background()) }()
+
+	cfg := ChannelConfig{
+		ID:          "cfg-1",
+		BotID:       "bot-1",
+		ChannelType: ChannelType("test"),
+		Credentials: map[string]any{"botToken": "token"},
+		UpdatedAt:   time.Now(),
+	}
+	manager.reconcile(context.Background(), []ChannelConfig{cfg})
+	if err := manager.PauseBot(context.Background(), "bot-1"); err != nil {
+		t.Fatalf("pause: %v", err)
+	}
+
+	if err := manager.RestartConnection(context.Background(), cfg); !errors.Is(err, ErrBotPendingDeletion) {
+		t.Fatalf("expected ErrBotPendingDeletion, got %v", err)
+	}
+	adapter.mu.Lock()
+	started := len(adapter.started)
+	adapter.mu.Unlock()
+	if started != 1 {
+		t.Fatalf("expected paused bot to stay disconnected, got %d starts", started)
+	}
+
+	if err := manager.ResumeBot(context.Background(), "bot-1"); err != nil {
+		t.Fatalf("resume: %v", err)
+	}
+	if err := manager.RestartConnection(context.Background(), cfg); err != nil {
+		t.Fatalf("restart after resume: %v", err)
+	}
+}
+
 func TestManagerEnsureConnectionDetachesRequestContext(t *testing.T) {
 	t.Parallel()
 
@@ -41,6 +41,10 @@ type OutboundPolicy struct {
 	InlineTextWithMedia bool          `json:"inline_text_with_media,omitempty"`
 	RetryMax            int           `json:"retry_max,omitempty"`
 	RetryBackoffMs      int           `json:"retry_backoff_ms,omitempty"`
+	// StreamFlushIntervalMs, when positive, coalesces streamed text deltas so
+	// the channel receives at most one delta per interval. Useful for channels
+	// that update the platform message on every event.
+	StreamFlushIntervalMs int `json:"stream_flush_interval_ms,omitempty"`
 }
 
 // NormalizeOutboundPolicy fills zero-value fields with sensible defaults.
@@ -241,6 +245,7 @@ func buildOutboundMessages(msg OutboundMessage, policy OutboundPolicy) ([]Outbou
 					Actions:     actions,
 					Thread:      base.Thread,
 					Reply:       base.Reply,
+					Ephemeral:   base.Ephemeral,
 					Metadata:    base.Metadata,
 				},
 			}
@@ -346,37 +351,51 @@ func validateMessageCapabilities(registry *Registry, channelType ChannelType, ms
 	return nil
 }
 
-func (m *Manager) sendWithConfig(ctx context.Context, sender Sender, cfg ChannelConfig, msg OutboundMessage, policy OutboundPolicy) error {
+// dropUnsupportedEphemeral clears the ephemeral flag for channels without the
+// Ephemeral capability, so the message is delivered as a normal one.
+func dropUnsupportedEphemeral(registry *Registry, channelType ChannelType, msg Message) Message {
+	if !msg.Ephemeral {
+		return msg
+	}
+	if caps, ok := registry.GetCapabilities(channelType); ok && caps.Ephemeral {
+		return msg
+	}
+	msg.Ephemeral = false
+	return msg
+}
+
+func (m *Manager) sendWithConfig(ctx context.Context, sender Sender, cfg ChannelConfig, msg OutboundMessage, policy OutboundPolicy) (string, error) {
 	if sender == nil {
-		return fmt.Errorf("unsupported channel type: %s", cfg.ChannelType)
+		return "", Terminal(fmt.Errorf("unsupported channel type: %s", cfg.ChannelType))
 	}
 	target := strings.TrimSpace(msg.Target)
 	if target == "" {
-		return errors.New("target is required")
+		return "", Terminal(errors.New("target is required"))
 	}
 	if msg.Message.IsEmpty() {
-		return errors.New("message is required")
+		return "", Terminal(errors.New("message is required"))
 	}
 	normalized := msg
 	attachments, err := normalizeAttachmentRefs(msg.Message.Attachments, cfg.ChannelType)
 	if err != nil {
-		return err
+		return "", Terminal(err)
 	}
 	normalized.Message.Attachments = attachments
+	normalized.Message = dropUnsupportedEphemeral(m.registry, cfg.ChannelType, normalized.Message)
 	if err := validateMessageCapabilities(m.registry, cfg.ChannelType, normalized.Message); err != nil {
-		return err
+		return "", Terminal(err)
 	}
 	prepared, err := PrepareOutboundMessage(ctx, m.attachmentStore, cfg, OutboundMessage{
 		Target:  target,
 		Message: normalized.Message,
 	})
 	if err != nil {
-		return err
+		return "", err
 	}
 	editor, _ := m.registry.GetMessageEditor(cfg.ChannelType)
 	if strings.TrimSpace(normalized.Message.ID) != "" {
 		if editor == nil {
-			return errors.New("channel does not support edit")
+			return "", Terminal(errors.New("channel does not support edit"))
 		}
 		var lastErr error
 		for i := 0; i < policy.RetryMax; i++ {
@@ -389,7 +408,10 @@ func (m *Manager) sendWithConfig(ctx context.Context, sender Sender, cfg Channel
 						slog.String("target", target),
 					)
 				}
-				return nil
+				return strings.TrimSpace(normalized.Message.ID), nil
+			}
+			if IsTerminal(err) {
+				return "", fmt.Errorf("edit outbound failed: %w", err)
 			}
 			lastErr = err
 			if m.logger != nil {
@@ -399,14 +421,21 @@ func (m *Manager) sendWithConfig(ctx context.Context, sender Sender, cfg Channel
 					slog.Any("error", err))
 			}
 			if !sleepWithContext(ctx, time.Duration(i+1)*time.Duration(policy.RetryBackoffMs)*time.Millisecond) {
-				return fmt.Errorf("edit outbound cancelled: %w", ctx.Err())
+				return "", fmt.Errorf("edit outbound cancelled: %w", ctx.Err())
 			}
 		}
-		return fmt.Errorf("edit outbound failed after retries: %w", lastErr)
+		return "", fmt.Errorf("edit outbound failed after retries: %w", lastErr)
 	}
+	receiptSender, _ := sender.(ReceiptSender)
 	var lastErr error
 	for i := 0; i < policy.RetryMax; i++ {
-		err := sender.Send(ctx, cfg, prepared)
+		var platformMessageID string
+		var err error
+		if receiptSender != nil {
+			platformMessageID, err = receiptSender.SendWithReceipt(ctx, cfg, prepared)
+		} else {
+			err = sender.Send(ctx, cfg, prepared)
+		}
 		if err == nil {
 			if m.logger != nil {
 				m.logger.Debug("send outbound success",
@@ -415,7 +444,10 @@ func (m *Manager) sendWithConfig(ctx context.Context, sender Sender, cfg Channel
 					slog.String("target", target),
 				)
 			}
-			return nil
+			return strings.TrimSpace(platformMessageID), nil
+		}
+		if IsTerminal(err) {
+			return "", fmt.Errorf("send outbound failed: %w", err)
 		}
 		lastErr = err
 		if m.logger != nil {
@@ -425,10 +457,10 @@ func (m *Manager) sendWithConfig(ctx context.Context, sender Sender, cfg Channel
 				slog.Any("error", err))
 		}
 		if !sleepWithContext(ctx, time.Duration(i+1)*time.Duration(policy.RetryBackoffMs)*time.Millisecond) {
-			return fmt.Errorf("send outbound cancelled: %w", ctx.Err())
+			return "", fmt.Errorf("send outbound cancelled: %w", ctx.Err())
 		}
 	}
-	return fmt.Errorf("send outbound failed after retries: %w", lastErr)
+	return "", fmt.Errorf("send outbound failed after retries: %w", lastErr)
 }
 
 func normalizeAttachmentRefs(attachments []Attachment, defaultPlatform ChannelType) ([]Attachment, error) {
@@ -481,7 +513,7 @@ func validateStreamEvent(registry *Registry, channelType ChannelType, event Stre
 		if !caps.Streaming && !caps.BlockStreaming {
 			return errors.New("channel does not support streaming")
 		}
-	case StreamEventToolCallStart, StreamEventToolCallEnd:
+	case StreamEventToolCallStart, StreamEventToolCallDelta, StreamEventToolCallEnd:
 		if !caps.Streaming && !caps.BlockStreaming {
 			return errors.New("channel does not support streaming")
 		}
@@ -495,7 +527,7 @@ func validateStreamEvent(registry *Registry, channelType ChannelType, event Stre
 		if _, err := normalizeAttachmentRefs(event.Attachments, channelType); err != nil {
 			return err
 		}
-	case StreamEventAgentStart, StreamEventAgentEnd, StreamEventProcessingStarted, StreamEventProcessingCompleted:
+	case StreamEventAgentStart, StreamEventAgentEnd, StreamEventProcessingStarted, StreamEventProcessingCompleted, StreamEventUnknown:
 		return nil
 	case StreamEventProcessingFailed:
 		if strings.TrimSpace(event.Error) == "" {
@@ -521,6 +553,33 @@ func validateStreamEvent(registry *Registry, channelType ChannelType, event Stre
 	return nil
 }
 
+// recordDelivery stores the receipt for one outbound send. Recording failures
+// are logged and never fail the send itself.
+func (m *Manager) recordDelivery(ctx context.Context, cfg ChannelConfig, target, messageID, platformMessageID string, sendErr error) {
+	if m.deliveries == nil {
+		return
+	}
+	receipt := DeliveryReceipt{
+		BotID:             cfg.BotID,
+		MessageID:         strings.TrimSpace(messageID),
+		ChannelType:       cfg.ChannelType,
+		Target:            strings.TrimSpace(target),
+		PlatformMessageID: platformMessageID,
+		Status:            DeliveryStatusDelivered,
+		At:                time.Now().UTC(),
+	}
+	if sendErr != nil {
+		receipt.Status = DeliveryStatusFailed
+		receipt.Error = sendErr.Error()
+	}
+	if err := m.deliveries.RecordDelivery(context.WithoutCancel(ctx), receipt); err != nil && m.logger != nil {
+		m.logger.Warn("record delivery receipt failed",
+			slog.String("channel", cfg.ChannelType.String()),
+			slog.String("bot_id", cfg.BotID),
+			slog.Any("error", err))
+	}
+}
+
 func (m *Manager) newReplySender(cfg ChannelConfig, channelType ChannelType) StreamReplySender {
 	sender, _ := m.registry.GetSender(channelType)
 	streamSender, _ := m.registry.GetStreamSender(channelType)
@@ -551,7 +610,9 @@ func (s *managerReplySender) Send(ctx context.Context, msg OutboundMessage) erro
 		return err
 	}
 	for _, item := range outbound {
-		if err := s.manager.sendWithConfig(ctx, s.sender, s.config, item, policy); err != nil {
+		platformMessageID, err := s.manager.sendWithConfig(ctx, s.sender, s.config, item, policy)
+		s.manager.recordDelivery(ctx, s.config, item.Target, msg.HistoryMessageID, platformMessageID, err)
+		if err != nil {
 			return err
 		}
 	}
@@ -580,6 +641,7 @@ func (s *managerReplySender) OpenStream(ctx context.Context, target string, opts
 	return &managerOutboundStream{
 		manager:     s.manager,
 		config:      s.config,
+		target:      target,
 		stream:      stream,
 		channelType: s.channelType,
 		policy:      s.manager.resolveOutboundPolicy(s.channelType),
@@ -589,6 +651,7 @@ func (s *managerReplySender) OpenStream(ctx context.Context, target string, opts
 		},
 		reopen: func(ctx context.Context) (PreparedOutboundStream, error) {
 			return s.streamSender.OpenStream(ctx, s.config, target, StreamOptions{
+				Thread:          opts.Thread,
 				SourceMessageID: opts.SourceMessageID,
 				Metadata:        opts.Metadata,
 			})
@@ -604,6 +667,7 @@ func (s *managerReplySender) OpenStream(ctx context.Context, target string, opts
 type managerOutboundStream struct {
 	manager     *Manager
 	config      ChannelConfig
+	target      string
 	stream      PreparedOutboundStream
 	channelType ChannelType
 	policy      OutboundPolicy // cached at open time; immutable after creation
@@ -621,6 +685,11 @@ func (s *managerOutboundStream) Push(ctx context.Context, event StreamEvent) err
 	if err := validateStreamEvent(s.manager.registry, s.channelType, event); err != nil {
 		return err
 	}
+	if event.Type == StreamEventFinal && event.Final != nil && event.Final.Message.Ephemeral {
+		final := *event.Final
+		final.Message = dropUnsupportedEphemeral(s.manager.registry, s.channelType, final.Message)
+		event.Final = &final
+	}
 
 	if event.Type == StreamEventDelta && event.Delta != "" && event.Phase != StreamPhaseReasoning {
 		return s.pushDelta(ctx, event)
@@ -632,6 +701,9 @@ func (s *managerOutboundStream) Push(ctx context.Context, event StreamEvent) err
 		}
 		return s.pushFinalWithChunking(ctx, event)
 	}
+	if event.Type == StreamEventFinal && event.Final != nil {
+		return s.pushFinal(ctx, event, event.Final.HistoryMessageID)
+	}
 	return s.pushPrepared(ctx, event)
 }
 
@@ -734,12 +806,16 @@ func isNaturalBreakPoint(text string) bool {
 // empty-text Final so the adapter finalizes its internal buffer, then
 // delivers any remaining attachments / actions via the non-streaming path.
 func (s *managerOutboundStream) pushFinalAfterSplit(ctx context.Context, event StreamEvent) error {
+	historyMessageID := ""
+	if event.Final != nil {
+		historyMessageID = event.Final.HistoryMessageID
+	}
 	bufferFinal := StreamEvent{
 		Type:     StreamEventFinal,
-		Final:    &StreamFinalizePayload{},
+		Final:    &StreamFinalizePayload{HistoryMessageID: historyMessageID},
 		Metadata: event.Metadata,
 	}
-	if err := s.pushPrepared(ctx, bufferFinal); err != nil {
+	if err := s.pushFinal(ctx, bufferFinal, historyMessageID); err != nil {
 		return err
 	}
 
@@ -754,7 +830,9 @@ func (s *managerOutboundStream) pushFinalAfterSplit(ctx context.Context, event S
 				Attachments: msg.Attachments,
 				Thread:      msg.Thread,
 				Actions:     msg.Actions,
+				Ephemeral:   msg.Ephemeral,
 			},
+			HistoryMessageID: historyMessageID,
 		}); err != nil {
 			return err
 		}
@@ -763,6 +841,7 @@ func (s *managerOutboundStream) pushFinalAfterSplit(ctx context.Context, event S
 }
 
 func (s *managerOutboundStream) pushFinalWithChunking(ctx context.Context, event StreamEvent) error {
+	historyMessageID := event.Final.HistoryMessageID
 	policy := s.policy
 	if policy.TextChunkLimit <= 0 {
 		if s.manager.logger != nil {
@@ -771,7 +850,7 @@ func (s *managerOutboundStream) pushFinalWithChunking(ctx context.Context, event
 				slog.Int("chunk_limit", policy.TextChunkLimit),
 			)
 		}
-		return s.pushPrepared(ctx, event)
+		return s.pushFinal(ctx, event, historyMessageID)
 	}
 	msg := normalizeOutboundMessage(event.Final.Message)
 	text := strings.TrimSpace(msg.PlainText())
@@ -793,7 +872,7 @@ func (s *managerOutboundStream) pushFinalWithChunking(ctx context.Context, event
 				slog.Int("chunk_limit", policy.TextChunkLimit),
 			)
 		}
-		return s.pushPrepared(ctx, event)
+		return s.pushFinal(ctx, event, historyMessageID)
 	}
 
 	chunker := policy.Chunker
@@ -808,7 +887,7 @@ func (s *managerOutboundStream) pushFinalWithChunking(ctx context.Context, event
 				slog.Int("chunks", len(chunks)),
 			)
 		}
-		return s.pushPrepared(ctx, event)
+		return s.pushFinal(ctx, event, historyMessageID)
 	}
 
 	hasAttachments := len(msg.Attachments) > 0
@@ -827,7 +906,7 @@ func (s *managerOutboundStream) pushFinalWithChunking(ctx context.Context, event
 	firstMsg.Actions = nil
 	firstChunkEvent := StreamEvent{
 		Type:     StreamEventFinal,
-		Final:    &StreamFinalizePayload{Message: firstMsg},
+		Final:    &StreamFinalizePayload{Message: firstMsg, HistoryMessageID: historyMessageID},
 		Metadata: event.Metadata,
 	}
 	firstChunkCtx, cancelFirstChunk := context.WithTimeout(ctx, streamFinalFirstChunkTimeout)
@@ -840,9 +919,18 @@ func (s *managerOutboundStream) pushFinalWithChunking(ctx context.Context, event
 				slog.Any("error", err),
 			)
 		}
-		return s.sendChunkedFinal(ctx, msg, chunks, 0, hasAttachments)
+		return s.sendChunkedFinal(ctx, msg, historyMessageID, chunks, 0, hasAttachments)
 	}
-	return s.sendChunkedFinal(ctx, msg, chunks, 1, hasAttachments)
+	s.manager.recordDelivery(ctx, s.config, s.target, historyMessageID, "", nil)
+	return s.sendChunkedFinal(ctx, msg, historyMessageID, chunks, 1, hasAttachments)
+}
+
+// pushFinal pushes a final event to the adapter stream and records the
+// delivery receipt of the message it completes.
+func (s *managerOutboundStream) pushFinal(ctx context.Context, event StreamEvent, historyMessageID string) error {
+	err := s.pushPrepared(ctx, event)
+	s.manager.recordDelivery(ctx, s.config, s.target, historyMessageID, "", err)
+	return err
 }
 
 func (s *managerOutboundStream) pushPrepared(ctx context.Context, event StreamEvent) error {
@@ -856,7 +944,7 @@ func (s *managerOutboundStream) pushPrepared(ctx context.Context, event StreamEv
 	return s.stream.Push(ctx, prepared)
 }
 
-func (s *managerOutboundStream) sendChunkedFinal(ctx context.Context, msg Message, chunks []string, startIndex int, hasAttachments bool) error {
+func (s *managerOutboundStream) sendChunkedFinal(ctx context.Context, msg Message, historyMessageID string, chunks []string, startIndex int, hasAttachments bool) error {
 	if startIndex < 0 {
 		startIndex = 0
 	}
@@ -873,13 +961,15 @@ func (s *managerOutboundStream) sendChunkedFinal(ctx context.Context, msg Messag
 		}
 		if err := s.send(ctx, OutboundMessage{
 			Message: Message{
-				Format:   msg.Format,
-				Text:     chunk,
-				Thread:   msg.Thread,
-				Reply:    msg.Reply,
-				Metadata: msg.Metadata,
-				Actions:  actions,
+				Format:    msg.Format,
+				Text:      chunk,
+				Thread:    msg.Thread,
+				Reply:     msg.Reply,
+				Ephemeral: msg.Ephemeral,
+				Metadata:  msg.Metadata,
+				Actions:   actions,
 			},
+			HistoryMessageID: historyMessageID,
 		}); err != nil {
 			if s.manager.logger != nil {
 				s.manager.logger.Error("stream final overflow chunk send failed",
@@ -899,9 +989,11 @@ func (s *managerOutboundStream) sendChunkedFinal(ctx context.Context, msg Messag
 				Attachments: msg.Attachments,
 				Thread:      msg.Thread,
 				Reply:       msg.Reply,
+				Ephemeral:   msg.Ephemeral,
 				Metadata:    msg.Metadata,
 				Actions:     msg.Actions,
 			},
+			HistoryMessageID: historyMessageID,
 		}); err != nil {
 			if s.manager.logger != nil {
 				s.manager.logger.Error("stream final attachments send failed",
@@ -0,0 +1,186 @@
+package channel
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+)
+
+const (
+	defaultOutboundRetryMaxAttempts = 5
+	outboundRetryBaseBackoff        = 30 * time.Second
+	outboundRetryMaxBackoff         = 10 * time.Minute
+	outboundRetryPollInterval       = 10 * time.Second
+	outboundRetryBatchSize          = 20
+	// outboundRetryClaimTimeout is how long a claimed retry may stay in flight
+	// before a later drain treats it as abandoned and claims it again.
+	outboundRetryClaimTimeout = 5 * time.Minute
+)
+
+// ErrOutboundQueued is returned by Manager.Send when delivery failed and the
+// message was queued for background retries instead. The returned error also
+// wraps the delivery failure.
+var ErrOutboundQueued = errors.New("outbound message queued for retry")
+
+// terminalError marks a delivery failure that retrying cannot fix.
+type terminalError struct {
+	err error
+}
+
+func (e *terminalError) Error() string { return e.err.Error() }
+
+func (e *terminalError) Unwrap() error { return e.err }
+
+// Terminal marks err as a permanent delivery failure (for example an invalid
+// target) so the manager neither retries nor queues it. Adapters may wrap
+// platform errors with it. A nil err stays nil.
+func Terminal(err error) error {
+	if err == nil || IsTerminal(err) {
+		return err
+	}
+	return &terminalError{err: err}
+}
+
+// IsTerminal reports whether err, or any error it wraps, was marked Terminal.
+func IsTerminal(err error) bool {
+	var terminal *terminalError
+	return errors.As(err, &terminal)
+}
+
+// OutboundRetry is a queued outbound send awaiting another delivery attempt.
+type OutboundRetry struct {
+	ID            string
+	BotID         string
+	ChannelType   ChannelType
+	Request       SendRequest
+	Attempts      int
+	MaxAttempts   int
+	NextAttemptAt time.Time
+	LastError     string
+}
+
+// OutboundRetryStore persists the outbound retry queue.
+type OutboundRetryStore interface {
+	EnqueueOutboundRetry(ctx context.Context, retry OutboundRetry) error
+	// ClaimOutboundRetries marks up to limit due retries as in flight and
+	// returns them ordered by next attempt time.
+	ClaimOutboundRetries(ctx context.Context, now time.Time, limit int) ([]OutboundRetry, error)
+	CompleteOutboundRetry(ctx context.Context, id string) error
+	RescheduleOutboundRetry(ctx context.Context, id string, attempts int, nextAttemptAt time.Time, lastError string) error
+	FailOutboundRetry(ctx context.Context, id string, attempts int, lastError string) error
+}
+
+// outboundRetryBackoff returns the delay before the next attempt after the
+// given number of failed attempts, doubling from the base up to the cap.
+func outboundRetryBackoff(attempts int) time.Duration {
+	delay := outboundRetryBaseBackoff
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= outboundRetryMaxBackoff {
+			return outboundRetryMaxBackoff
+		}
+	}
+	return delay
+}
+
+// enqueueOutboundRetries queues the outbound items that were not delivered,
+// keeping their order. It reports whether all of them were queued.
+func (m *Manager) enqueueOutboundRetries(ctx context.Context, botID string, channelType ChannelType, historyMessageID string, items []OutboundMessage, cause error) bool {
+	if m.retries == nil || IsTerminal(cause) {
+		return false
+	}
+	nextAttemptAt := time.Now().UTC().Add(outboundRetryBackoff(1))
+	for _, item := range items {
+		err := m.retries.EnqueueOutboundRetry(context.WithoutCancel(ctx), OutboundRetry{
+			BotID:       botID,
+			ChannelType: channelType,
+			Request: SendRequest{
+				Target:           item.Target,
+				HistoryMessageID: historyMessageID,
+				Message:          item.Message,
+			},
+			Attempts:      1,
+			MaxAttempts:   defaultOutboundRetryMaxAttempts,
+			NextAttemptAt: nextAttemptAt,
+			LastError:     cause.Error(),
+		})
+		if err != nil {
+			if m.logger != nil {
+				m.logger.Error("enqueue outbound retry failed",
+					slog.String("channel", channelType.String()),
+					slog.String("bot_id", botID),
+					slog.Any("error", err))
+			}
+			return false
+		}
+	}
+	if m.logger != nil {
+		m.logger.Warn("send outbound queued for retry",
+			slog.String("channel", channelType.String()),
+			slog.String("bot_id", botID),
+			slog.Int("messages", len(items)),
+			slog.Any("error", cause))
+	}
+	return true
+}
+
+// startOutboundRetries drains the retry queue in the background until ctx is done.
+func (m *Manager) startOutboundRetries(ctx context.Context) {
+	if m.retries == nil {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(outboundRetryPollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				m.drainOutboundRetries(ctx, time.Now().UTC())
+			}
+		}
+	}()
+}
+
+// drainOutboundRetries attempts every retry due at now once. Successful and
+// terminally failed entries leave the queue; the rest are rescheduled.
+func (m *Manager) drainOutboundRetries(ctx context.Context, now time.Time) {
+	if m.retries == nil {
+		return
+	}
+	items, err := m.retries.ClaimOutboundRetries(ctx, now, outboundRetryBatchSize)
+	if err != nil {
+		if m.logger != nil {
+			m.logger.Error("claim outbound retries failed", slog.Any("error", err))
+		}
+		return
+	}
+	for _, item := range items {
+		if ctx.Err() != nil {
+			return
+		}
+		sendErr := m.send(ctx, item.BotID, item.ChannelType, item.Request, false)
+		attempts := item.Attempts + 1
+		var storeErr error
+		switch {
+		case sendErr == nil:
+			storeErr = m.retries.CompleteOutboundRetry(ctx, item.ID)
+		case IsTerminal(sendErr) || attempts >= item.MaxAttempts:
+			if m.logger != nil {
+				m.logger.Error("outbound retry abandoned",
+					slog.String("channel", item.ChannelType.String()),
+					slog.String("bot_id", item.BotID),
+					slog.Int("attempts", attempts),
+					slog.Any("error", sendErr))
+			}
+			storeErr = m.retries.FailOutboundRetry(ctx, item.ID, attempts, sendErr.Error())
+		default:
+			storeErr = m.retries.RescheduleOutboundRetry(ctx, item.ID, attempts, now.Add(outboundRetryBackoff(attempts)), sendErr.Error())
+		}
+		if storeErr != nil && m.logger != nil {
+			m.logger.Error("update outbound retry failed", slog.String("retry_id", item.ID), slog.Any("error", storeErr))
+		}
+	}
+}
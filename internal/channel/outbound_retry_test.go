@@ -0,0 +1,257 @@
+package channel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeOutboundRetryStore struct {
+	mu      sync.Mutex
+	nextID  int
+	pending map[string]OutboundRetry
+	failed  map[string]OutboundRetry
+	done    []string
+}
+
+func newFakeOutboundRetryStore() *fakeOutboundRetryStore {
+	return &fakeOutboundRetryStore{pending: map[string]OutboundRetry{}, failed: map[string]OutboundRetry{}}
+}
+
+func (f *fakeOutboundRetryStore) EnqueueOutboundRetry(_ context.Context, retry OutboundRetry) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.nextID++
+	retry.ID = fmt.Sprintf("retry-%d", f.nextID)
+	f.pending[retry.ID] = retry
+	return nil
+}
+
+func (f *fakeOutboundRetryStore) ClaimOutboundRetries(_ context.Context, now time.Time, limit int) ([]OutboundRetry, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var due []OutboundRetry
+	for _, retry := range f.pending {
+		if !retry.NextAttemptAt.After(now) {
+			due = append(due, retry)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].ID < due[j].ID })
+	if len(due) > limit {
+		due = due[:limit]
+	}
+	return due, nil
+}
+
+func (f *fakeOutboundRetryStore) CompleteOutboundRetry(_ context.Context, id string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.pending, id)
+	f.done = append(f.done, id)
+	return nil
+}
+
+func (f *fakeOutboundRetryStore) RescheduleOutboundRetry(_ context.Context, id string, attempts int, nextAttemptAt time.Time, lastError string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	retry := f.pending[id]
+	retry.Attempts = attempts
+	retry.NextAttemptAt = nextAttemptAt
+	retry.LastError = lastError
+	f.pending[id] = retry
+	return nil
+}
+
+func (f *fakeOutboundRetryStore) FailOutboundRetry(_ context.Context, id string, attempts int, lastError string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	retry := f.pending[id]
+	retry.Attempts = attempts
+	retry.LastError = lastError
+	delete(f.pending, id)
+	f.failed[id] = retry
+	return nil
+}
+
+// flakyAdapter fails the first failures sends with err, then delivers.
+type flakyAdapter struct {
+	fakeAdapter
+	failures int
+	err      error
+	attempts int
+}
+
+func (f *flakyAdapter) Descriptor() Descriptor {
+	return Descriptor{
+		Type:           f.channelType,
+		DisplayName:    "Flaky",
+		Capabilities:   ChannelCapabilities{Text: true},
+		OutboundPolicy: OutboundPolicy{RetryMax: 1, RetryBackoffMs: 1},
+	}
+}
+
+func (f *flakyAdapter) Send(ctx context.Context, cfg ChannelConfig, msg PreparedOutboundMessage) error {
+	f.mu.Lock()
+	f.attempts++
+	fail := f.failures < 0 || f.attempts <= f.failures
+	f.mu.Unlock()
+	if fail {
+		return f.err
+	}
+	return f.fakeAdapter.Send(ctx, cfg, msg)
+}
+
+func newRetryTestManager(adapter Adapter, store OutboundRetryStore) *Manager {
+	configStore := &fakeConfigStore{
+		effectiveConfig: ChannelConfig{ID: "cfg-1", BotID: "bot-1", ChannelType: ChannelType("test")},
+	}
+	manager := NewManager(slog.New(slog.DiscardHandler), NewRegistry(), configStore, &fakeInboundProcessorIntegration{})
+	manager.RegisterAdapter(adapter)
+	manager.SetOutboundRetryStore(store)
+	return manager
+}
+
+func TestManagerSendRetriesTransientFailureUntilDelivered(t *testing.T) {
+	t.Parallel()
+
+	adapter := &flakyAdapter{fakeAdapter: fakeAdapter{channelType: ChannelType("test")}, failures: 2, err: errors.New("rate limited")}
+	store := newFakeOutboundRetryStore()
+	manager := newRetryTestManager(adapter, store)
+
+	err := manager.Send(context.Background(), "bot-1", ChannelType("test"), SendRequest{
+		Target:  "chat-1",
+		Message: Message{Text: "hello"},
+	})
+	if !errors.Is(err, ErrOutboundQueued) {
+		t.Fatalf("expected ErrOutboundQueued, got %v", err)
+	}
+	if len(store.pending) != 1 {
+		t.Fatalf("expected 1 queued retry, got %d", len(store.pending))
+	}
+
+	now := time.Now().UTC()
+	manager.drainOutboundRetries(context.Background(), now)
+	adapter.mu.Lock()
+	early := adapter.attempts
+	adapter.mu.Unlock()
+	if early != 1 {
+		t.Fatalf("expected retry not to be due before its backoff elapses, got %d attempts", early)
+	}
+
+	now = now.Add(outboundRetryBackoff(1))
+	manager.drainOutboundRetries(context.Background(), now)
+	if len(store.pending) != 1 {
+		t.Fatalf("expected retry to be rescheduled, got %d pending", len(store.pending))
+	}
+	for _, retry := range store.pending {
+		if retry.Attempts != 2 || retry.LastError != "send outbound failed after retries: rate limited" {
+			t.Fatalf("unexpected rescheduled retry: %+v", retry)
+		}
+		if !retry.NextAttemptAt.Equal(now.Add(outboundRetryBackoff(2))) {
+			t.Fatalf("expected exponential backoff, got next attempt %v", retry.NextAttemptAt)
+		}
+	}
+
+	manager.drainOutboundRetries(context.Background(), now.Add(outboundRetryBackoff(2)))
+	if len(store.pending) != 0 || len(store.done) != 1 || len(store.failed) != 0 {
+		t.Fatalf("expected retry to complete, pending=%d done=%d failed=%d", len(store.pending), len(store.done), len(store.failed))
+	}
+	adapter.mu.Lock()
+	defer adapter.mu.Unlock()
+	if len(adapter.sent) != 1 || adapter.sent[0].Target != "chat-1" || adapter.sent[0].Message.PlainText() != "hello" {
+		t.Fatalf("unexpected delivered messages: %+v", adapter.sent)
+	}
+}
+
+func TestManagerSendStopsRetryingPermanentFailure(t *testing.T) {
+	t.Parallel()
+
+	adapter := &flakyAdapter{fakeAdapter: fakeAdapter{channelType: ChannelType("test")}, failures: 1, err: errors.New("gateway timeout")}
+	store := newFakeOutboundRetryStore()
+	manager := newRetryTestManager(adapter, store)
+
+	if err := manager.Send(context.Background(), "bot-1", ChannelType("test"), SendRequest{
+		Target:  "chat-1",
+		Message: Message{Text: "hello"},
+	}); !errors.Is(err, ErrOutboundQueued) {
+		t.Fatalf("expected ErrOutboundQueued, got %v", err)
+	}
+
+	adapter.mu.Lock()
+	adapter.failures = -1
+	adapter.err = Terminal(errors.New("chat not found"))
+	adapter.mu.Unlock()
+
+	now := time.Now().UTC().Add(outboundRetryBackoff(1))
+	manager.drainOutboundRetries(context.Background(), now)
+	if len(store.pending) != 0 || len(store.failed) != 1 {
+		t.Fatalf("expected terminal failure to leave the queue, pending=%d failed=%d", len(store.pending), len(store.failed))
+	}
+
+	adapter.mu.Lock()
+	attempts := adapter.attempts
+	adapter.mu.Unlock()
+	manager.drainOutboundRetries(context.Background(), now.Add(outboundRetryMaxBackoff))
+	adapter.mu.Lock()
+	defer adapter.mu.Unlock()
+	if adapter.attempts != attempts {
+		t.Fatalf("expected no further attempts, got %d after %d", adapter.attempts, attempts)
+	}
+}
+
+func TestManagerSendDoesNotQueueInvalidTarget(t *testing.T) {
+	t.Parallel()
+
+	adapter := &flakyAdapter{fakeAdapter: fakeAdapter{channelType: ChannelType("test")}}
+	store := newFakeOutboundRetryStore()
+	manager := newRetryTestManager(adapter, store)
+
+	err := manager.Send(context.Background(), "bot-1", ChannelType("test"), SendRequest{
+		Message: Message{Text: "hello"},
+	})
+	if err == nil || !IsTerminal(err) {
+		t.Fatalf("expected terminal error, got %v", err)
+	}
+	if len(store.pending) != 0 {
+		t.Fatalf("expected nothing queued, got %d", len(store.pending))
+	}
+}
+
+func TestManagerSendGivesUpAfterMaxAttempts(t *testing.T) {
+	t.Parallel()
+
+	adapter := &flakyAdapter{fakeAdapter: fakeAdapter{channelType: ChannelType("test")}, failures: -1, err: errors.New("connection reset")}
+	store := newFakeOutboundRetryStore()
+	manager := newRetryTestManager(adapter, store)
+
+	if err := manager.Send(context.Background(), "bot-1", ChannelType("test"), SendRequest{
+		Target:  "chat-1",
+		Message: Message{Text: "hello"},
+	}); !errors.Is(err, ErrOutboundQueued) {
+		t.Fatalf("expected ErrOutboundQueued, got %v", err)
+	}
+
+	now := time.Now().UTC()
+	for i := 0; i < defaultOutboundRetryMaxAttempts+2; i++ {
+		now = now.Add(outboundRetryMaxBackoff)
+		manager.drainOutboundRetries(context.Background(), now)
+	}
+	if len(store.pending) != 0 || len(store.failed) != 1 {
+		t.Fatalf("expected retry to be abandoned, pending=%d failed=%d", len(store.pending), len(store.failed))
+	}
+	for _, retry := range store.failed {
+		if retry.Attempts != defaultOutboundRetryMaxAttempts {
+			t.Fatalf("expected %d attempts, got %d", defaultOutboundRetryMaxAttempts, retry.Attempts)
+		}
+	}
+	adapter.mu.Lock()
+	defer adapter.mu.Unlock()
+	if adapter.attempts != defaultOutboundRetryMaxAttempts {
+		t.Fatalf("expected %d sends, got %d", defaultOutboundRetryMaxAttempts, adapter.attempts)
+	}
+}
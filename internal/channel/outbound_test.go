@@ -95,7 +95,7 @@ func TestValidateStreamEventInvalidPayload(t *testing.T) {
 		{name: "processing failed missing error", event: StreamEvent{Type: StreamEventProcessingFailed}},
 		{name: "missing final payload", event: StreamEvent{Type: StreamEventFinal}},
 		{name: "missing error payload", event: StreamEvent{Type: StreamEventError}},
-		{name: "unsupported type", event: StreamEvent{Type: StreamEventType("unknown")}},
+		{name: "unsupported type", event: StreamEvent{Type: StreamEventType("bogus")}},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,39 @@
+package channel
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ProcessingStatusTimeoutRoutingKey is the channel config routing key holding
+// how many seconds a processing status update (typing indicator, reaction)
+// may take.
+const ProcessingStatusTimeoutRoutingKey = "processing_status_timeout_seconds"
+
+const (
+	// DefaultProcessingStatusTimeout bounds processing status updates when
+	// the channel config sets no timeout.
+	DefaultProcessingStatusTimeout = 60 * time.Second
+	maxProcessingStatusTimeout     = 10 * time.Minute
+)
+
+// ProcessingStatusTimeoutFromRouting reads the processing status timeout from
+// a channel config's routing map. A missing or empty value yields
+// DefaultProcessingStatusTimeout.
+func ProcessingStatusTimeoutFromRouting(routing map[string]any) (time.Duration, error) {
+	raw := strings.TrimSpace(ReadString(routing, ProcessingStatusTimeoutRoutingKey))
+	if raw == "" {
+		return DefaultProcessingStatusTimeout, nil
+	}
+	seconds, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q: must be a number of seconds", ProcessingStatusTimeoutRoutingKey, raw)
+	}
+	timeout := time.Duration(seconds * float64(time.Second))
+	if timeout <= 0 || timeout > maxProcessingStatusTimeout {
+		return 0, fmt.Errorf("invalid %s %q: must be above 0 and at most %d", ProcessingStatusTimeoutRoutingKey, raw, int(maxProcessingStatusTimeout.Seconds()))
+	}
+	return timeout, nil
+}
@@ -0,0 +1,39 @@
+package channel_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/memohai/memoh/internal/channel"
+)
+
+func TestProcessingStatusTimeoutFromRouting(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		routing map[string]any
+		want    time.Duration
+		wantErr bool
+	}{
+		{routing: nil, want: channel.DefaultProcessingStatusTimeout},
+		{routing: map[string]any{"processing_status_timeout_seconds": ""}, want: channel.DefaultProcessingStatusTimeout},
+		{routing: map[string]any{"processing_status_timeout_seconds": float64(120)}, want: 2 * time.Minute},
+		{routing: map[string]any{"processing_status_timeout_seconds": " 2.5 "}, want: 2500 * time.Millisecond},
+		{routing: map[string]any{"processing_status_timeout_seconds": float64(0)}, wantErr: true},
+		{routing: map[string]any{"processing_status_timeout_seconds": float64(-5)}, wantErr: true},
+		{routing: map[string]any{"processing_status_timeout_seconds": float64(3600)}, wantErr: true},
+		{routing: map[string]any{"processing_status_timeout_seconds": "soon"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := channel.ProcessingStatusTimeoutFromRouting(tt.routing)
+		if tt.wantErr {
+			if err == nil {
+				t.Fatalf("ProcessingStatusTimeoutFromRouting(%v) expected error", tt.routing)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Fatalf("ProcessingStatusTimeoutFromRouting(%v) = (%v, %v), want %v", tt.routing, got, err, tt.want)
+		}
+	}
+}
@@ -185,6 +185,16 @@ func (r *Registry) IsConfigless(channelType ChannelType) bool {
 	return desc.Configless
 }
 
+// IsLocal reports whether the channel type is an in-process channel that
+// publishes to the route hub natively.
+func (r *Registry) IsLocal(channelType ChannelType) bool {
+	desc, ok := r.GetDescriptor(channelType)
+	if !ok {
+		return false
+	}
+	return desc.Capabilities.Local
+}
+
 // --- Sender / Receiver accessors ---
 
 // GetSender returns the Sender for the given channel type, or nil if unsupported.
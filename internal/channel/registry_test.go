@@ -101,3 +101,30 @@ func TestGetAttachmentResolver_Unsupported(t *testing.T) {
 		t.Fatalf("GetAttachmentResolver(test) = (%v, %v), want (nil, false)", resolver, ok)
 	}
 }
+
+type localMockAdapter struct{}
+
+func (*localMockAdapter) Type() channel.ChannelType { return channel.ChannelType("local-test") }
+
+func (*localMockAdapter) Descriptor() channel.Descriptor {
+	return channel.Descriptor{
+		Type:         channel.ChannelType("local-test"),
+		DisplayName:  "LocalTest",
+		Capabilities: channel.ChannelCapabilities{Text: true, Local: true},
+	}
+}
+
+func TestIsLocal(t *testing.T) {
+	t.Parallel()
+	reg := newTestConfigRegistry()
+	reg.MustRegister(&localMockAdapter{})
+	if !reg.IsLocal(channel.ChannelType("local-test")) {
+		t.Fatal("IsLocal(local-test) = false, want true")
+	}
+	if reg.IsLocal(testChannelType) {
+		t.Fatal("IsLocal(test) = true, want false")
+	}
+	if reg.IsLocal(channel.ChannelType("unknown")) {
+		t.Fatal("IsLocal(unknown) = true, want false")
+	}
+}
@@ -0,0 +1,39 @@
+package channel
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ReplyMode controls how a bot's reply refers to the inbound message it answers.
+type ReplyMode string
+
+const (
+	// ReplyModeMessage replies to the source message. It is the default.
+	ReplyModeMessage ReplyMode = "message"
+	// ReplyModeThread posts the reply in the source message's thread on
+	// channels with thread support and replies to the message elsewhere.
+	ReplyModeThread ReplyMode = "thread"
+	// ReplyModeNone sends the reply as a fresh message.
+	ReplyModeNone ReplyMode = "none"
+)
+
+// ReplyModeRoutingKey is the channel config routing key holding the reply mode.
+const ReplyModeRoutingKey = "reply_mode"
+
+// ParseReplyMode validates raw. An empty value yields ReplyModeMessage.
+func ParseReplyMode(raw string) (ReplyMode, error) {
+	switch mode := ReplyMode(strings.ToLower(strings.TrimSpace(raw))); mode {
+	case "":
+		return ReplyModeMessage, nil
+	case ReplyModeMessage, ReplyModeThread, ReplyModeNone:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("invalid reply mode %q: use message, thread or none", raw)
+	}
+}
+
+// ReplyModeFromRouting reads the reply mode from a channel config's routing map.
+func ReplyModeFromRouting(routing map[string]any) (ReplyMode, error) {
+	return ParseReplyMode(ReadString(routing, ReplyModeRoutingKey))
+}
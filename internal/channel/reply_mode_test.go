@@ -0,0 +1,35 @@
+package channel_test
+
+import (
+	"testing"
+
+	"github.com/memohai/memoh/internal/channel"
+)
+
+func TestReplyModeFromRouting(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		routing map[string]any
+		want    channel.ReplyMode
+		wantErr bool
+	}{
+		{routing: nil, want: channel.ReplyModeMessage},
+		{routing: map[string]any{"reply_mode": ""}, want: channel.ReplyModeMessage},
+		{routing: map[string]any{"reply_mode": " Thread "}, want: channel.ReplyModeThread},
+		{routing: map[string]any{"reply_mode": "none"}, want: channel.ReplyModeNone},
+		{routing: map[string]any{"reply_mode": "quote"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := channel.ReplyModeFromRouting(tt.routing)
+		if tt.wantErr {
+			if err == nil {
+				t.Fatalf("ReplyModeFromRouting(%v) expected error", tt.routing)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Fatalf("ReplyModeFromRouting(%v) = (%q, %v), want %q", tt.routing, got, err, tt.want)
+		}
+	}
+}
@@ -165,6 +165,52 @@ func (s *DBService) UpdateMetadata(ctx context.Context, routeID string, metadata
 	})
 }
 
+// SetMuted mutes or unmutes a route. Inbound messages on a muted route are
+// still recorded, but the bot does not reply.
+func (s *DBService) SetMuted(ctx context.Context, routeID string, muted bool) error {
+	pgID, err := dbpkg.ParseUUID(routeID)
+	if err != nil {
+		return err
+	}
+	return s.queries.SetChatRouteMuted(ctx, sqlc.SetChatRouteMutedParams{
+		ID:    pgID,
+		Muted: muted,
+	})
+}
+
+// SetPinned pins or unpins a route. Pinned routes are skipped by retention
+// sweeps, and so is the passive message pruning of their sessions.
+func (s *DBService) SetPinned(ctx context.Context, routeID string, pinned bool) error {
+	pgID, err := dbpkg.ParseUUID(routeID)
+	if err != nil {
+		return err
+	}
+	return s.queries.SetChatRoutePinned(ctx, sqlc.SetChatRoutePinnedParams{
+		ID:     pgID,
+		Pinned: pinned,
+	})
+}
+
+// SetAnnotations replaces the notes and tags of a route.
+func (s *DBService) SetAnnotations(ctx context.Context, routeID string, annotations Annotations) error {
+	pgID, err := dbpkg.ParseUUID(routeID)
+	if err != nil {
+		return err
+	}
+	annotations, err = NormalizeAnnotations(annotations)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(annotations)
+	if err != nil {
+		return fmt.Errorf("marshal route annotations: %w", err)
+	}
+	return s.queries.SetChatRouteAnnotations(ctx, sqlc.SetChatRouteAnnotationsParams{
+		ID:          pgID,
+		Annotations: data,
+	})
+}
+
 // ResolveConversation finds or creates a conversation route for an inbound message.
 func (s *DBService) ResolveConversation(ctx context.Context, input ResolveInput) (ResolveConversationResult, error) {
 	route, err := s.Find(ctx, input.BotID, input.Platform, input.ConversationID, input.ThreadID)
@@ -187,7 +233,7 @@ func (s *DBService) ResolveConversation(ctx context.Context, input ResolveInput)
 		if touchErr := s.queries.TouchChat(ctx, pgConversationID); touchErr != nil && s.logger != nil {
 			s.logger.Warn("touch conversation failed", slog.Any("error", touchErr))
 		}
-		return ResolveConversationResult{ChatID: route.ChatID, RouteID: route.ID, Created: false}, nil
+		return ResolveConversationResult{ChatID: route.ChatID, RouteID: route.ID, Created: false, Muted: route.Muted, Annotations: route.Annotations}, nil
 	}
 
 	if s.conversation == nil {
@@ -230,7 +276,7 @@ func (s *DBService) ResolveConversation(ctx context.Context, input ResolveInput)
 		if dbpkg.IsUniqueViolation(err) {
 			existing, findErr := s.Find(ctx, input.BotID, input.Platform, input.ConversationID, input.ThreadID)
 			if findErr == nil {
-				return ResolveConversationResult{ChatID: existing.ChatID, RouteID: existing.ID, Created: false}, nil
+				return ResolveConversationResult{ChatID: existing.ChatID, RouteID: existing.ID, Created: false, Muted: existing.Muted, Annotations: existing.Annotations}, nil
 			}
 		}
 		return ResolveConversationResult{}, fmt.Errorf("create route: %w", err)
@@ -281,7 +327,7 @@ func toRouteFromCreate(row sqlc.CreateChatRouteRow) Route {
 	return toRouteFields(
 		row.ID, row.ChatID, row.BotID, row.Platform, row.ChannelConfigID,
 		row.ConversationID, row.ThreadID, row.ConversationType, row.ReplyTarget,
-		row.Metadata, row.CreatedAt, row.UpdatedAt,
+		row.Metadata, row.Muted, row.Pinned, row.Annotations, row.CreatedAt, row.UpdatedAt,
 	)
 }
 
@@ -289,7 +335,7 @@ func toRouteFromFind(row sqlc.FindChatRouteRow) Route {
 	return toRouteFields(
 		row.ID, row.ChatID, row.BotID, row.Platform, row.ChannelConfigID,
 		row.ConversationID, row.ThreadID, row.ConversationType, row.ReplyTarget,
-		row.Metadata, row.CreatedAt, row.UpdatedAt,
+		row.Metadata, row.Muted, row.Pinned, row.Annotations, row.CreatedAt, row.UpdatedAt,
 	)
 }
 
@@ -297,7 +343,7 @@ func toRouteFromGet(row sqlc.GetChatRouteByIDRow) Route {
 	return toRouteFields(
 		row.ID, row.ChatID, row.BotID, row.Platform, row.ChannelConfigID,
 		row.ConversationID, row.ThreadID, row.ConversationType, row.ReplyTarget,
-		row.Metadata, row.CreatedAt, row.UpdatedAt,
+		row.Metadata, row.Muted, row.Pinned, row.Annotations, row.CreatedAt, row.UpdatedAt,
 	)
 }
 
@@ -305,11 +351,11 @@ func toRouteFromList(row sqlc.ListChatRoutesRow) Route {
 	return toRouteFields(
 		row.ID, row.ChatID, row.BotID, row.Platform, row.ChannelConfigID,
 		row.ConversationID, row.ThreadID, row.ConversationType, row.ReplyTarget,
-		row.Metadata, row.CreatedAt, row.UpdatedAt,
+		row.Metadata, row.Muted, row.Pinned, row.Annotations, row.CreatedAt, row.UpdatedAt,
 	)
 }
 
-func toRouteFields(id, conversationID, botID pgtype.UUID, platform string, channelConfigID pgtype.UUID, externalConversationID string, threadID, conversationType, replyTarget pgtype.Text, metadata []byte, createdAt, updatedAt pgtype.Timestamptz) Route {
+func toRouteFields(id, conversationID, botID pgtype.UUID, platform string, channelConfigID pgtype.UUID, externalConversationID string, threadID, conversationType, replyTarget pgtype.Text, metadata []byte, muted, pinned bool, annotations []byte, createdAt, updatedAt pgtype.Timestamptz) Route {
 	return Route{
 		ID:               id.String(),
 		ChatID:           conversationID.String(),
@@ -321,6 +367,9 @@ func toRouteFields(id, conversationID, botID pgtype.UUID, platform string, chann
 		ConversationType: dbpkg.TextToString(conversationType),
 		ReplyTarget:      dbpkg.TextToString(replyTarget),
 		Metadata:         parseJSONMap(metadata),
+		Muted:            muted,
+		Pinned:           pinned,
+		Annotations:      parseAnnotations(annotations),
 		CreatedAt:        createdAt.Time,
 		UpdatedAt:        updatedAt.Time,
 	}
@@ -350,6 +399,18 @@ func parseJSONMap(data []byte) map[string]any {
 	return m
 }
 
+// parseAnnotations decodes stored annotations, tolerating malformed JSON.
+func parseAnnotations(data []byte) Annotations {
+	var a Annotations
+	if len(data) > 0 {
+		_ = json.Unmarshal(data, &a)
+	}
+	if a.Tags == nil {
+		a.Tags = []string{}
+	}
+	return a
+}
+
 // metadataChanged returns true when any key in incoming differs from existing.
 func metadataChanged(existing, incoming map[string]any) bool {
 	for k, v := range incoming {
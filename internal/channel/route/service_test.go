@@ -1,9 +1,18 @@
 package route
 
 import (
+	"context"
+	"errors"
+	"log/slog"
+	"reflect"
+	"strings"
 	"testing"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
 	"github.com/memohai/memoh/internal/conversation"
+	"github.com/memohai/memoh/internal/db/sqlc"
 )
 
 func TestDetermineConversationKindTreatsDirectAsDirect(t *testing.T) {
@@ -11,3 +20,93 @@ func TestDetermineConversationKindTreatsDirectAsDirect(t *testing.T) {
 		t.Fatalf("unexpected conversation kind: %q", got)
 	}
 }
+
+// annotationsDB stores the annotations column of a single route row.
+type annotationsDB struct {
+	annotations []byte
+}
+
+func (f *annotationsDB) Exec(_ context.Context, _ string, args ...any) (pgconn.CommandTag, error) {
+	data, ok := args[0].([]byte)
+	if !ok {
+		return pgconn.CommandTag{}, errors.New("unexpected annotations argument")
+	}
+	f.annotations = data
+	return pgconn.CommandTag{}, nil
+}
+
+func (*annotationsDB) Query(context.Context, string, ...any) (pgx.Rows, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (f *annotationsDB) QueryRow(context.Context, string, ...any) pgx.Row {
+	return annotationsRow{annotations: f.annotations}
+}
+
+type annotationsRow struct {
+	annotations []byte
+}
+
+func (r annotationsRow) Scan(dest ...any) error {
+	// Column 13 of GetChatRouteByID is annotations.
+	ptr, ok := dest[13].(*[]byte)
+	if !ok {
+		return errors.New("unexpected annotations destination")
+	}
+	*ptr = r.annotations
+	return nil
+}
+
+func TestDBServiceSetAnnotationsRoundTrip(t *testing.T) {
+	db := &annotationsDB{}
+	svc := NewService(slog.New(slog.DiscardHandler), sqlc.New(db), nil)
+	routeID := "11111111-1111-1111-1111-111111111111"
+
+	rt, err := svc.GetByID(context.Background(), routeID)
+	if err != nil {
+		t.Fatalf("get route: %v", err)
+	}
+	if !rt.Annotations.IsZero() || rt.Annotations.Tags == nil {
+		t.Fatalf("expected empty annotations with non-nil tags, got %+v", rt.Annotations)
+	}
+
+	err = svc.SetAnnotations(context.Background(), routeID, Annotations{
+		Notes: "  Prefers short answers.  ",
+		Tags:  []string{"vip", " VIP ", "", "support"},
+	})
+	if err != nil {
+		t.Fatalf("set annotations: %v", err)
+	}
+	rt, err = svc.GetByID(context.Background(), routeID)
+	if err != nil {
+		t.Fatalf("get route: %v", err)
+	}
+	want := Annotations{Notes: "Prefers short answers.", Tags: []string{"vip", "support"}}
+	if !reflect.DeepEqual(rt.Annotations, want) {
+		t.Fatalf("unexpected annotations: %+v", rt.Annotations)
+	}
+}
+
+func TestNormalizeAnnotationsEnforcesLimits(t *testing.T) {
+	tests := []struct {
+		name string
+		in   Annotations
+	}{
+		{name: "long notes", in: Annotations{Notes: strings.Repeat("a", MaxAnnotationNotesLength+1)}},
+		{name: "long tag", in: Annotations{Tags: []string{strings.Repeat("t", MaxAnnotationTagLength+1)}}},
+		{name: "too many tags", in: Annotations{Tags: func() []string {
+			tags := make([]string, 0, MaxAnnotationTags+1)
+			for i := 0; i <= MaxAnnotationTags; i++ {
+				tags = append(tags, strings.Repeat("t", i+1))
+			}
+			return tags
+		}()}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NormalizeAnnotations(tt.in); !errors.Is(err, ErrInvalidAnnotations) {
+				t.Fatalf("expected ErrInvalidAnnotations, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package route
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// DefaultStaleSweepInterval is how often routes past their TTL are removed.
+const DefaultStaleSweepInterval = time.Hour
+
+// SweepStale removes routes with no activity within ttl: no route update,
+// session update or message since the cutoff. Pinned, muted and annotated
+// routes are kept, as they carry operator decisions that would be lost. A
+// swept route is recreated by the next inbound message. It returns the number
+// of routes removed; a non-positive ttl removes nothing.
+func (s *DBService) SweepStale(ctx context.Context, ttl time.Duration) (int64, error) {
+	if ttl <= 0 {
+		return 0, nil
+	}
+	cutoff := time.Now().Add(-ttl)
+	return s.queries.DeleteStaleChatRoutes(ctx, pgtype.Timestamptz{Time: cutoff, Valid: true})
+}
+
+// StartStaleSweepLoop periodically removes stale routes until ctx is done.
+// It returns immediately when ttl is not positive.
+func (s *DBService) StartStaleSweepLoop(ctx context.Context, ttl, interval time.Duration) {
+	if ttl <= 0 {
+		return
+	}
+	if interval <= 0 {
+		interval = DefaultStaleSweepInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			removed, err := s.SweepStale(ctx, ttl)
+			if err != nil {
+				s.logger.Warn("sweep stale routes failed", slog.Any("error", err))
+				continue
+			}
+			if removed > 0 {
+				s.logger.Info("swept stale routes", slog.Int64("count", removed))
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
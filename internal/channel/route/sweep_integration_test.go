@@ -0,0 +1,164 @@
+package route_test
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/memohai/memoh/internal/channel/route"
+	"github.com/memohai/memoh/internal/db"
+	"github.com/memohai/memoh/internal/db/sqlc"
+)
+
+func setupRouteSweepIntegrationTest(t *testing.T) (*route.DBService, *sqlc.Queries, *pgxpool.Pool) {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("skip integration test: TEST_POSTGRES_DSN is not set")
+	}
+
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		t.Skipf("skip integration test: cannot connect to database: %v", err)
+	}
+	if err := pool.Ping(ctx); err != nil {
+		pool.Close()
+		t.Skipf("skip integration test: database ping failed: %v", err)
+	}
+	t.Cleanup(pool.Close)
+
+	queries := sqlc.New(pool)
+	return route.NewService(slog.New(slog.DiscardHandler), queries, nil), queries, pool
+}
+
+func createRouteSweepBot(ctx context.Context, t *testing.T, queries *sqlc.Queries, pool *pgxpool.Pool) string {
+	t.Helper()
+
+	user, err := queries.CreateUser(ctx, sqlc.CreateUserParams{IsActive: true, Metadata: []byte("{}")})
+	if err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	bot, err := queries.CreateBot(ctx, sqlc.CreateBotParams{
+		OwnerUserID: user.ID,
+		DisplayName: pgtype.Text{String: "route-sweep-test-bot", Valid: true},
+		IsActive:    true,
+		Metadata:    []byte("{}"),
+		Status:      "ready",
+	})
+	if err != nil {
+		t.Fatalf("create bot: %v", err)
+	}
+	t.Cleanup(func() {
+		_, _ = pool.Exec(ctx, "DELETE FROM bots WHERE id = $1", bot.ID)
+		_, _ = pool.Exec(ctx, "DELETE FROM users WHERE id = $1", user.ID)
+	})
+	return bot.ID.String()
+}
+
+func TestSweepStaleRemovesOnlyIdleRoutes(t *testing.T) {
+	svc, queries, pool := setupRouteSweepIntegrationTest(t)
+	ctx := context.Background()
+	botID := createRouteSweepBot(ctx, t, queries, pool)
+
+	createRoute := func(conversationID string) route.Route {
+		t.Helper()
+		rt, err := svc.Create(ctx, route.CreateInput{
+			ChatID:         botID,
+			BotID:          botID,
+			Platform:       "telegram",
+			ConversationID: conversationID,
+		})
+		if err != nil {
+			t.Fatalf("create route %s: %v", conversationID, err)
+		}
+		return rt
+	}
+	stale := createRoute("stale")
+	recent := createRoute("recent")
+	muted := createRoute("muted")
+	pinned := createRoute("pinned")
+	annotated := createRoute("annotated")
+	recentMessage := createRoute("recent-message")
+
+	if err := svc.SetMuted(ctx, muted.ID, true); err != nil {
+		t.Fatalf("mute route: %v", err)
+	}
+	if err := svc.SetPinned(ctx, pinned.ID, true); err != nil {
+		t.Fatalf("pin route: %v", err)
+	}
+	if err := svc.SetAnnotations(ctx, annotated.ID, route.Annotations{Tags: []string{"vip"}}); err != nil {
+		t.Fatalf("annotate route: %v", err)
+	}
+
+	pgBotID, err := db.ParseUUID(botID)
+	if err != nil {
+		t.Fatalf("parse bot id: %v", err)
+	}
+	pgRouteID, err := db.ParseUUID(recentMessage.ID)
+	if err != nil {
+		t.Fatalf("parse route id: %v", err)
+	}
+	session, err := queries.CreateSession(ctx, sqlc.CreateSessionParams{
+		BotID:    pgBotID,
+		RouteID:  pgRouteID,
+		Type:     "chat",
+		Metadata: []byte("{}"),
+	})
+	if err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+	if _, err := queries.CreateMessage(ctx, sqlc.CreateMessageParams{
+		BotID:     pgBotID,
+		SessionID: session.ID,
+		Role:      "user",
+		Content:   []byte(`{"text":"hi"}`),
+		Metadata:  []byte("{}"),
+	}); err != nil {
+		t.Fatalf("create message: %v", err)
+	}
+
+	// Age everything except the recent route and the message.
+	old := time.Now().Add(-60 * 24 * time.Hour)
+	for _, id := range []string{stale.ID, muted.ID, pinned.ID, annotated.ID, recentMessage.ID} {
+		if _, err := pool.Exec(ctx, "UPDATE bot_channel_routes SET updated_at = $2 WHERE id = $1::uuid", id, old); err != nil {
+			t.Fatalf("age route: %v", err)
+		}
+	}
+	if _, err := pool.Exec(ctx, "UPDATE bot_sessions SET updated_at = $2 WHERE id = $1", session.ID, old); err != nil {
+		t.Fatalf("age session: %v", err)
+	}
+
+	removed, err := svc.SweepStale(ctx, 30*24*time.Hour)
+	if err != nil {
+		t.Fatalf("sweep stale routes: %v", err)
+	}
+	if removed < 1 {
+		t.Fatalf("expected the stale route to be removed, removed %d", removed)
+	}
+
+	if _, err := svc.GetByID(ctx, stale.ID); err == nil {
+		t.Fatal("expected stale route to be removed")
+	}
+	for name, rt := range map[string]route.Route{
+		"recent":         recent,
+		"muted":          muted,
+		"pinned":         pinned,
+		"annotated":      annotated,
+		"recent message": recentMessage,
+	} {
+		kept, err := svc.GetByID(ctx, rt.ID)
+		if err != nil {
+			t.Fatalf("expected %s route to be kept: %v", name, err)
+		}
+		if name == "pinned" && !kept.Pinned {
+			t.Fatal("expected pinned route to stay pinned")
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package route
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/memohai/memoh/internal/db/sqlc"
+)
+
+// sweepDB records the cutoff passed to DeleteStaleChatRoutes.
+type sweepDB struct {
+	calls  int
+	cutoff time.Time
+}
+
+func (f *sweepDB) Exec(_ context.Context, _ string, args ...any) (pgconn.CommandTag, error) {
+	f.calls++
+	cutoff, ok := args[0].(pgtype.Timestamptz)
+	if !ok {
+		return pgconn.CommandTag{}, errors.New("unexpected cutoff argument")
+	}
+	f.cutoff = cutoff.Time
+	return pgconn.NewCommandTag("DELETE 2"), nil
+}
+
+func (*sweepDB) Query(context.Context, string, ...any) (pgx.Rows, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (*sweepDB) QueryRow(context.Context, string, ...any) pgx.Row {
+	return nil
+}
+
+func TestSweepStaleUsesTTLCutoff(t *testing.T) {
+	db := &sweepDB{}
+	svc := NewService(slog.New(slog.DiscardHandler), sqlc.New(db), nil)
+
+	removed, err := svc.SweepStale(context.Background(), 0)
+	if err != nil || removed != 0 || db.calls != 0 {
+		t.Fatalf("expected a zero ttl to skip the sweep, removed=%d calls=%d err=%v", removed, db.calls, err)
+	}
+
+	before := time.Now()
+	removed, err = svc.SweepStale(context.Background(), 24*time.Hour)
+	if err != nil {
+		t.Fatalf("sweep stale routes: %v", err)
+	}
+	if removed != 2 {
+		t.Fatalf("expected 2 routes removed, got %d", removed)
+	}
+	if want := before.Add(-24 * time.Hour); db.cutoff.Before(want) || db.cutoff.After(time.Now().Add(-24*time.Hour)) {
+		t.Fatalf("unexpected cutoff %v, want about %v", db.cutoff, want)
+	}
+}
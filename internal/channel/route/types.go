@@ -2,7 +2,11 @@ package route
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
 	"time"
+	"unicode/utf8"
 )
 
 // Route maps external channel conversations to an internal conversation.
@@ -17,15 +21,71 @@ type Route struct {
 	ConversationType string         `json:"conversation_type,omitempty"`
 	ReplyTarget      string         `json:"reply_target,omitempty"`
 	Metadata         map[string]any `json:"metadata,omitempty"`
+	Muted            bool           `json:"muted"`
+	Pinned           bool           `json:"pinned"`
+	Annotations      Annotations    `json:"annotations"`
 	CreatedAt        time.Time      `json:"created_at"`
 	UpdatedAt        time.Time      `json:"updated_at"`
 }
 
 // ResolveConversationResult is returned by ResolveConversation.
 type ResolveConversationResult struct {
-	ChatID  string
-	RouteID string
-	Created bool
+	ChatID      string
+	RouteID     string
+	Created     bool
+	Muted       bool
+	Annotations Annotations
+}
+
+// Limits applied to route annotations.
+const (
+	MaxAnnotationNotesLength = 4000
+	MaxAnnotationTags        = 20
+	MaxAnnotationTagLength   = 64
+)
+
+// Annotations are operator-maintained notes and tags about a conversation,
+// given to the agent so it can personalize replies.
+type Annotations struct {
+	Notes string   `json:"notes"`
+	Tags  []string `json:"tags"`
+}
+
+// ErrInvalidAnnotations is returned when annotations exceed their limits.
+var ErrInvalidAnnotations = errors.New("invalid route annotations")
+
+// NormalizeAnnotations trims notes and tags, drops empty and duplicate tags
+// (case-insensitively, keeping the first spelling) and enforces the limits.
+func NormalizeAnnotations(a Annotations) (Annotations, error) {
+	out := Annotations{Notes: strings.TrimSpace(a.Notes), Tags: []string{}}
+	if utf8.RuneCountInString(out.Notes) > MaxAnnotationNotesLength {
+		return Annotations{}, fmt.Errorf("%w: notes exceed %d characters", ErrInvalidAnnotations, MaxAnnotationNotesLength)
+	}
+	seen := make(map[string]struct{}, len(a.Tags))
+	for _, tag := range a.Tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			continue
+		}
+		if utf8.RuneCountInString(tag) > MaxAnnotationTagLength {
+			return Annotations{}, fmt.Errorf("%w: tag %q exceeds %d characters", ErrInvalidAnnotations, tag, MaxAnnotationTagLength)
+		}
+		key := strings.ToLower(tag)
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		out.Tags = append(out.Tags, tag)
+	}
+	if len(out.Tags) > MaxAnnotationTags {
+		return Annotations{}, fmt.Errorf("%w: more than %d tags", ErrInvalidAnnotations, MaxAnnotationTags)
+	}
+	return out, nil
+}
+
+// IsZero reports whether the annotations carry no information.
+func (a Annotations) IsZero() bool {
+	return strings.TrimSpace(a.Notes) == "" && len(a.Tags) == 0
 }
 
 // CreateInput is the input for creating a route.
@@ -69,4 +129,7 @@ type Service interface {
 	Delete(ctx context.Context, routeID string) error
 	UpdateReplyTarget(ctx context.Context, routeID, replyTarget string) error
 	UpdateMetadata(ctx context.Context, routeID string, metadata map[string]any) error
+	SetMuted(ctx context.Context, routeID string, muted bool) error
+	SetPinned(ctx context.Context, routeID string, pinned bool) error
+	SetAnnotations(ctx context.Context, routeID string, annotations Annotations) error
 }
@@ -0,0 +1,61 @@
+package channel
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// SenderAttributesRoutingKey is the channel config routing key listing the
+// sender attributes passed on to route metadata and the agent.
+const SenderAttributesRoutingKey = "sender_attributes"
+
+// DefaultSenderAttribute is always passed on, whatever the routing lists.
+const DefaultSenderAttribute = "username"
+
+var senderAttributeKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// SenderAttributesFromRouting reads the sender attribute whitelist from a
+// channel config's routing map. Keys are lower-case identifiers; the result
+// always includes DefaultSenderAttribute and is sorted.
+func SenderAttributesFromRouting(routing map[string]any) ([]string, error) {
+	keys := map[string]struct{}{DefaultSenderAttribute: {}}
+	if raw, ok := routing[SenderAttributesRoutingKey]; ok && raw != nil {
+		list, ok := raw.([]any)
+		if !ok {
+			return nil, fmt.Errorf("%s must be a list of attribute names", SenderAttributesRoutingKey)
+		}
+		for _, item := range list {
+			key, _ := item.(string)
+			key = strings.TrimSpace(key)
+			if !senderAttributeKeyPattern.MatchString(key) {
+				return nil, fmt.Errorf("invalid sender attribute %v: use lower-case letters, digits and underscores", item)
+			}
+			keys[key] = struct{}{}
+		}
+	}
+	out := make([]string, 0, len(keys))
+	for key := range keys {
+		out = append(out, key)
+	}
+	sort.Strings(out)
+	return out, nil
+}
+
+// SelectSenderAttributes returns the non-empty sender attributes named in
+// keys, or nil when there are none.
+func SelectSenderAttributes(attributes map[string]string, keys []string) map[string]string {
+	var out map[string]string
+	for _, key := range keys {
+		value := strings.TrimSpace(attributes[key])
+		if value == "" {
+			continue
+		}
+		if out == nil {
+			out = make(map[string]string, len(keys))
+		}
+		out[key] = value
+	}
+	return out
+}
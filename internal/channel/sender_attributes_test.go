@@ -0,0 +1,55 @@
+package channel_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/memohai/memoh/internal/channel"
+)
+
+func TestSenderAttributesFromRouting(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		routing map[string]any
+		want    []string
+		wantErr bool
+	}{
+		{routing: nil, want: []string{"username"}},
+		{routing: map[string]any{"sender_attributes": []any{"locale", "is_premium"}}, want: []string{"is_premium", "locale", "username"}},
+		{routing: map[string]any{"sender_attributes": []any{" avatar_url ", "username"}}, want: []string{"avatar_url", "username"}},
+		{routing: map[string]any{"sender_attributes": "locale"}, wantErr: true},
+		{routing: map[string]any{"sender_attributes": []any{"Locale"}}, wantErr: true},
+		{routing: map[string]any{"sender_attributes": []any{1}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := channel.SenderAttributesFromRouting(tt.routing)
+		if tt.wantErr {
+			if err == nil {
+				t.Fatalf("SenderAttributesFromRouting(%v) expected error", tt.routing)
+			}
+			continue
+		}
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Fatalf("SenderAttributesFromRouting(%v) = (%v, %v), want %v", tt.routing, got, err, tt.want)
+		}
+	}
+}
+
+func TestSelectSenderAttributes(t *testing.T) {
+	t.Parallel()
+
+	got := channel.SelectSenderAttributes(map[string]string{
+		"username":   "alice",
+		"locale":     " en-GB ",
+		"is_bot":     "false",
+		"is_premium": "",
+	}, []string{"is_premium", "locale", "username"})
+	want := map[string]string{"username": "alice", "locale": "en-GB"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("SelectSenderAttributes() = %v, want %v", got, want)
+	}
+	if got := channel.SelectSenderAttributes(map[string]string{"locale": "en"}, []string{"username"}); got != nil {
+		t.Fatalf("expected nil for no matches, got %v", got)
+	}
+}
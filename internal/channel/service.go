@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
+	"sort"
 	"strings"
 	"time"
 
@@ -107,6 +109,43 @@ func (s *Store) UpsertConfig(ctx context.Context, botID string, channelType Chan
 	return normalizeChannelConfigFromRow(row)
 }
 
+// CloneConfig copies a channel configuration to another bot without its
+// credentials or platform identity. The copy is stored disabled; it keeps the
+// routing and can be enabled once credentials are set.
+func (s *Store) CloneConfig(ctx context.Context, botID string, source ChannelConfig) (ChannelConfig, error) {
+	if s.queries == nil {
+		return ChannelConfig{}, errors.New("channel queries not configured")
+	}
+	if source.ChannelType == "" {
+		return ChannelConfig{}, errors.New("channel type is required")
+	}
+	botUUID, err := db.ParseUUID(botID)
+	if err != nil {
+		return ChannelConfig{}, err
+	}
+	routing := source.Routing
+	if routing == nil {
+		routing = map[string]any{}
+	}
+	routingPayload, err := json.Marshal(routing)
+	if err != nil {
+		return ChannelConfig{}, err
+	}
+	row, err := s.queries.UpsertBotChannelConfig(ctx, sqlc.UpsertBotChannelConfigParams{
+		BotID:        botUUID,
+		ChannelType:  source.ChannelType.String(),
+		Credentials:  []byte("{}"),
+		SelfIdentity: []byte("{}"),
+		Routing:      routingPayload,
+		Capabilities: []byte("{}"),
+		Disabled:     true,
+	})
+	if err != nil {
+		return ChannelConfig{}, err
+	}
+	return normalizeChannelConfigFromRow(row)
+}
+
 // DeleteConfig removes a bot's channel configuration.
 func (s *Store) DeleteConfig(ctx context.Context, botID string, channelType ChannelType) error {
 	if s.queries == nil {
@@ -333,6 +372,34 @@ func (s *Store) ResolveChannelIdentityBinding(ctx context.Context, channelType C
 	return "", errors.New("channel user binding not found")
 }
 
+// RecordDelivery persists an outbound delivery receipt. A non-empty MessageID
+// links the receipt to the bot_history_messages row it delivered.
+func (s *Store) RecordDelivery(ctx context.Context, receipt DeliveryReceipt) error {
+	if s.queries == nil {
+		return errors.New("channel queries not configured")
+	}
+	botUUID, err := db.ParseUUID(receipt.BotID)
+	if err != nil {
+		return err
+	}
+	var messageUUID pgtype.UUID
+	if messageID := strings.TrimSpace(receipt.MessageID); messageID != "" {
+		if messageUUID, err = db.ParseUUID(messageID); err != nil {
+			return fmt.Errorf("invalid message id: %w", err)
+		}
+	}
+	_, err = s.queries.CreateDeliveryReceipt(ctx, sqlc.CreateDeliveryReceiptParams{
+		BotID:             botUUID,
+		MessageID:         messageUUID,
+		ChannelType:       receipt.ChannelType.String(),
+		Target:            receipt.Target,
+		PlatformMessageID: receipt.PlatformMessageID,
+		Status:            receipt.Status,
+		Error:             receipt.Error,
+	})
+	return err
+}
+
 func normalizeChannelConfigFromRow(row sqlc.BotChannelConfig) (ChannelConfig, error) {
 	return normalizeChannelConfigFields(
 		row.ID, row.BotID, row.ChannelType,
@@ -411,3 +478,127 @@ func normalizeChannelIdentityBinding(row sqlc.UserChannelBinding) (ChannelIdenti
 		UpdatedAt:         db.TimeFromPg(row.UpdatedAt),
 	}, nil
 }
+
+// EnqueueOutboundRetry persists a failed outbound send for background retries.
+func (s *Store) EnqueueOutboundRetry(ctx context.Context, retry OutboundRetry) error {
+	if s.queries == nil {
+		return errors.New("channel queries not configured")
+	}
+	botUUID, err := db.ParseUUID(retry.BotID)
+	if err != nil {
+		return err
+	}
+	payload, err := json.Marshal(retry.Request)
+	if err != nil {
+		return err
+	}
+	return s.queries.EnqueueChannelOutboundRetry(ctx, sqlc.EnqueueChannelOutboundRetryParams{
+		BotID:         botUUID,
+		ChannelType:   retry.ChannelType.String(),
+		Request:       payload,
+		Attempts:      clampInt32(retry.Attempts),
+		MaxAttempts:   clampInt32(retry.MaxAttempts),
+		NextAttemptAt: pgtype.Timestamptz{Time: retry.NextAttemptAt, Valid: true},
+		LastError:     retry.LastError,
+	})
+}
+
+// ClaimOutboundRetries marks due retries as in flight and returns them in
+// delivery order. Entries left in flight past the claim timeout are reclaimed.
+func (s *Store) ClaimOutboundRetries(ctx context.Context, now time.Time, limit int) ([]OutboundRetry, error) {
+	if s.queries == nil {
+		return nil, errors.New("channel queries not configured")
+	}
+	rows, err := s.queries.ClaimDueChannelOutboundRetries(ctx, sqlc.ClaimDueChannelOutboundRetriesParams{
+		Now:         pgtype.Timestamptz{Time: now, Valid: true},
+		StaleBefore: pgtype.Timestamptz{Time: now.Add(-outboundRetryClaimTimeout), Valid: true},
+		MaxCount:    clampInt32(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		if !rows[i].NextAttemptAt.Time.Equal(rows[j].NextAttemptAt.Time) {
+			return rows[i].NextAttemptAt.Time.Before(rows[j].NextAttemptAt.Time)
+		}
+		return rows[i].CreatedAt.Time.Before(rows[j].CreatedAt.Time)
+	})
+	items := make([]OutboundRetry, 0, len(rows))
+	for _, row := range rows {
+		var req SendRequest
+		if err := json.Unmarshal(row.Request, &req); err != nil {
+			if failErr := s.FailOutboundRetry(ctx, row.ID.String(), int(row.Attempts), "decode request: "+err.Error()); failErr != nil {
+				return nil, failErr
+			}
+			continue
+		}
+		items = append(items, OutboundRetry{
+			ID:            row.ID.String(),
+			BotID:         row.BotID.String(),
+			ChannelType:   ChannelType(row.ChannelType),
+			Request:       req,
+			Attempts:      int(row.Attempts),
+			MaxAttempts:   int(row.MaxAttempts),
+			NextAttemptAt: db.TimeFromPg(row.NextAttemptAt),
+			LastError:     row.LastError,
+		})
+	}
+	return items, nil
+}
+
+// CompleteOutboundRetry removes a delivered retry from the queue.
+func (s *Store) CompleteOutboundRetry(ctx context.Context, id string) error {
+	if s.queries == nil {
+		return errors.New("channel queries not configured")
+	}
+	pgID, err := db.ParseUUID(id)
+	if err != nil {
+		return err
+	}
+	return s.queries.DeleteChannelOutboundRetry(ctx, pgID)
+}
+
+// RescheduleOutboundRetry returns a retry to the queue for a later attempt.
+func (s *Store) RescheduleOutboundRetry(ctx context.Context, id string, attempts int, nextAttemptAt time.Time, lastError string) error {
+	if s.queries == nil {
+		return errors.New("channel queries not configured")
+	}
+	pgID, err := db.ParseUUID(id)
+	if err != nil {
+		return err
+	}
+	return s.queries.RescheduleChannelOutboundRetry(ctx, sqlc.RescheduleChannelOutboundRetryParams{
+		ID:            pgID,
+		Attempts:      clampInt32(attempts),
+		NextAttemptAt: pgtype.Timestamptz{Time: nextAttemptAt, Valid: true},
+		LastError:     lastError,
+	})
+}
+
+// FailOutboundRetry marks a retry as permanently failed. Failed entries are
+// kept for inspection and never retried.
+func (s *Store) FailOutboundRetry(ctx context.Context, id string, attempts int, lastError string) error {
+	if s.queries == nil {
+		return errors.New("channel queries not configured")
+	}
+	pgID, err := db.ParseUUID(id)
+	if err != nil {
+		return err
+	}
+	return s.queries.FailChannelOutboundRetry(ctx, sqlc.FailChannelOutboundRetryParams{
+		ID:        pgID,
+		Attempts:  clampInt32(attempts),
+		LastError: lastError,
+	})
+}
+
+func clampInt32(value int) int32 {
+	switch {
+	case value > math.MaxInt32:
+		return math.MaxInt32
+	case value < 0:
+		return 0
+	default:
+		return int32(value)
+	}
+}
@@ -0,0 +1,93 @@
+package channel
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/memohai/memoh/internal/db/sqlc"
+)
+
+// upsertConfigDB records the arguments of UpsertBotChannelConfig and returns
+// them as the stored row.
+type upsertConfigDB struct {
+	args []any
+}
+
+func (*upsertConfigDB) Exec(context.Context, string, ...any) (pgconn.CommandTag, error) {
+	return pgconn.CommandTag{}, errors.New("not implemented")
+}
+
+func (*upsertConfigDB) Query(context.Context, string, ...any) (pgx.Rows, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (d *upsertConfigDB) QueryRow(_ context.Context, sql string, args ...any) pgx.Row {
+	if !strings.Contains(sql, "name: UpsertBotChannelConfig ") {
+		return upsertConfigRow{err: errors.New("unexpected query")}
+	}
+	d.args = args
+	return upsertConfigRow{args: args}
+}
+
+type upsertConfigRow struct {
+	args []any
+	err  error
+}
+
+func (r upsertConfigRow) Scan(dest ...any) error {
+	if r.err != nil {
+		return r.err
+	}
+	*dest[0].(*pgtype.UUID) = pgtype.UUID{Valid: true}
+	*dest[1].(*pgtype.UUID) = r.args[0].(pgtype.UUID)
+	*dest[2].(*string) = r.args[1].(string)
+	*dest[3].(*[]byte) = r.args[2].([]byte)
+	*dest[4].(*pgtype.Text) = r.args[3].(pgtype.Text)
+	*dest[5].(*[]byte) = r.args[4].([]byte)
+	*dest[6].(*[]byte) = r.args[5].([]byte)
+	*dest[7].(*[]byte) = r.args[6].([]byte)
+	*dest[8].(*bool) = r.args[7].(bool)
+	*dest[9].(*pgtype.Timestamptz) = r.args[8].(pgtype.Timestamptz)
+	return nil
+}
+
+func TestCloneConfigDropsCredentialsAndIdentity(t *testing.T) {
+	t.Parallel()
+
+	db := &upsertConfigDB{}
+	store := NewStore(sqlc.New(db), NewRegistry())
+	source := ChannelConfig{
+		BotID:            "11111111-1111-1111-1111-111111111111",
+		ChannelType:      ChannelType("telegram"),
+		Credentials:      map[string]any{"botToken": "secret-token"},
+		ExternalIdentity: "source-bot",
+		SelfIdentity:     map[string]any{"username": "source_bot"},
+		Routing:          map[string]any{"reply_mode": "mention"},
+	}
+
+	cloned, err := store.CloneConfig(context.Background(), "22222222-2222-2222-2222-222222222222", source)
+	if err != nil {
+		t.Fatalf("clone config: %v", err)
+	}
+	if cloned.BotID != "22222222-2222-2222-2222-222222222222" || cloned.ChannelType != source.ChannelType {
+		t.Fatalf("unexpected clone target: %+v", cloned)
+	}
+	if len(cloned.Credentials) != 0 || cloned.ExternalIdentity != "" || len(cloned.SelfIdentity) != 0 {
+		t.Fatalf("expected credentials and identity to be left out, got %+v", cloned)
+	}
+	if strings.Contains(string(db.args[2].([]byte)), "secret-token") {
+		t.Fatalf("credentials were written for the clone: %s", db.args[2])
+	}
+	if cloned.Routing["reply_mode"] != "mention" {
+		t.Fatalf("expected routing to be copied, got %+v", cloned.Routing)
+	}
+	if !cloned.Disabled {
+		t.Fatal("expected the cloned config to be disabled")
+	}
+}
@@ -106,9 +106,11 @@ func GenerateRoutingKey(platform, botID, conversationID, conversationType, sende
 }
 
 // OutboundMessage pairs a delivery target with the message content.
+// HistoryMessageID optionally links the delivery receipt to a persisted message row.
 type OutboundMessage struct {
-	Target  string  `json:"target"`
-	Message Message `json:"message"`
+	Target           string  `json:"target"`
+	Message          Message `json:"message"`
+	HistoryMessageID string  `json:"history_message_id,omitempty"`
 }
 
 // StreamEventType defines the kind of outbound stream event.
@@ -120,6 +122,7 @@ const (
 	StreamEventFinal               StreamEventType = "final"
 	StreamEventError               StreamEventType = "error"
 	StreamEventToolCallStart       StreamEventType = "tool_call_start"
+	StreamEventToolCallDelta       StreamEventType = "tool_call_delta"
 	StreamEventToolCallEnd         StreamEventType = "tool_call_end"
 	StreamEventPhaseStart          StreamEventType = "phase_start"
 	StreamEventPhaseEnd            StreamEventType = "phase_end"
@@ -131,6 +134,7 @@ const (
 	StreamEventProcessingStarted   StreamEventType = "processing_started"
 	StreamEventProcessingCompleted StreamEventType = "processing_completed"
 	StreamEventProcessingFailed    StreamEventType = "processing_failed"
+	StreamEventUnknown             StreamEventType = "unknown"
 )
 
 // StreamStatus indicates the lifecycle state of a streaming reply.
@@ -143,11 +147,17 @@ const (
 )
 
 // StreamFinalizePayload carries the final reply message emitted by a stream.
+// HistoryMessageID optionally links the delivery receipt to the persisted
+// assistant message the reply was built from.
 type StreamFinalizePayload struct {
-	Message Message `json:"message"`
+	Message          Message `json:"message"`
+	HistoryMessageID string  `json:"history_message_id,omitempty"`
 }
 
 // StreamToolCall carries tool invocation data for tool_call_start / tool_call_end events.
+// In tool_call_delta events Input is empty and the event's Delta holds the
+// next piece of the argument text; the complete arguments arrive decoded on
+// tool_call_start.
 type StreamToolCall struct {
 	Name   string `json:"name"`
 	CallID string `json:"call_id,omitempty"`
@@ -164,6 +174,8 @@ const (
 )
 
 // StreamEvent represents a unified stream event routed through the channel layer.
+// Unknown events carry the original event type and decoded payload in
+// Metadata under "type" and "payload"; adapters may ignore them.
 type StreamEvent struct {
 	Type        StreamEventType        `json:"type"`
 	Status      StreamStatus           `json:"status,omitempty"`
@@ -186,6 +198,7 @@ type SpeechRequest struct {
 // StreamOptions configures how an outbound stream is initialized.
 type StreamOptions struct {
 	Reply           *ReplyRef      `json:"reply,omitempty"`
+	Thread          *ThreadRef     `json:"thread,omitempty"`
 	SourceMessageID string         `json:"source_message_id,omitempty"`
 	Metadata        map[string]any `json:"metadata,omitempty"`
 }
@@ -299,6 +312,9 @@ type ReplyRef struct {
 }
 
 // Message is the unified message structure used across all channels.
+// Ephemeral asks for a message only the user being answered, the sender of
+// the Reply message, can see; the outbound layer clears it on channels
+// without the Ephemeral capability.
 type Message struct {
 	ID          string         `json:"id,omitempty"`
 	Format      MessageFormat  `json:"format,omitempty"`
@@ -308,6 +324,7 @@ type Message struct {
 	Actions     []Action       `json:"actions,omitempty"`
 	Thread      *ThreadRef     `json:"thread,omitempty"`
 	Reply       *ReplyRef      `json:"reply,omitempty"`
+	Ephemeral   bool           `json:"ephemeral,omitempty"`
 	Metadata    map[string]any `json:"metadata,omitempty"`
 }
 
@@ -399,6 +416,8 @@ type ChannelIdentityBinding struct {
 
 // UpsertConfigRequest is the input for creating or updating a channel configuration.
 // Disabled: true to stop the channel, false to enable it. Omitted is treated as false (enabled).
+// Routing may set reply_mode to message (default), thread or none; see ReplyMode.
+// Routing may list sender_attributes to pass on; see SenderAttributesFromRouting.
 type UpsertConfigRequest struct {
 	Credentials      map[string]any `json:"credentials"`
 	ExternalIdentity string         `json:"external_identity,omitempty"`
@@ -419,12 +438,32 @@ type UpdateChannelStatusRequest struct {
 }
 
 // SendRequest is the input for sending an outbound message through a channel.
+// HistoryMessageID optionally links the delivery receipt to a persisted message row.
 type SendRequest struct {
 	Target            string  `json:"target,omitempty"`
 	ChannelIdentityID string  `json:"channel_identity_id,omitempty"`
+	HistoryMessageID  string  `json:"history_message_id,omitempty"`
 	Message           Message `json:"message"`
 }
 
+// Delivery receipt statuses.
+const (
+	DeliveryStatusDelivered = "delivered"
+	DeliveryStatusFailed    = "failed"
+)
+
+// DeliveryReceipt records the outcome of one outbound send as reported by the adapter.
+type DeliveryReceipt struct {
+	BotID             string      `json:"bot_id"`
+	MessageID         string      `json:"message_id,omitempty"`
+	ChannelType       ChannelType `json:"channel_type"`
+	Target            string      `json:"target"`
+	PlatformMessageID string      `json:"platform_message_id,omitempty"`
+	Status            string      `json:"status"`
+	Error             string      `json:"error,omitempty"`
+	At                time.Time   `json:"at"`
+}
+
 // ReactRequest is the input for adding or removing an emoji reaction on a message.
 type ReactRequest struct {
 	Target    string `json:"target"`
@@ -144,6 +144,7 @@ func (s *Service) doCompaction(ctx context.Context, logID pgtype.UUID, sessionUU
 		CodexAccountID: cfg.CodexAccountID,
 		ModelID:        cfg.ModelID,
 		HTTPClient:     cfg.HTTPClient,
+		Headers:        cfg.Headers,
 	})
 
 	result, err := sdk.GenerateTextResult(ctx,
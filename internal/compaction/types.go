@@ -35,6 +35,7 @@ type TriggerConfig struct {
 	APIKey           string //nolint:gosec // runtime credential, not a hardcoded secret
 	CodexAccountID   string
 	BaseURL          string
+	Headers          map[string]string
 	HTTPClient       *http.Client
 	Ratio            int
 	TotalInputTokens int
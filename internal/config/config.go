@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/BurntSushi/toml"
 )
@@ -43,9 +44,12 @@ type Config struct {
 	Postgres       PostgresConfig       `toml:"postgres"`
 	Qdrant         QdrantConfig         `toml:"qdrant"`
 	Sparse         SparseConfig         `toml:"sparse"`
+	Memory         MemoryConfig         `toml:"memory"`
+	Bots           BotsConfig           `toml:"bots"`
 	BrowserGateway BrowserGatewayConfig `toml:"browser_gateway"`
 	Registry       RegistryConfig       `toml:"registry"`
 	Supermarket    SupermarketConfig    `toml:"supermarket"`
+	MediaStorage   MediaStorageConfig   `toml:"media_storage"`
 }
 
 type LogConfig struct {
@@ -55,6 +59,74 @@ type LogConfig struct {
 
 type ServerConfig struct {
 	Addr string `toml:"addr"`
+	// MaxBodyMB caps request bodies. Bulk uploads are exempt. Zero means no
+	// limit.
+	MaxBodyMB int `toml:"max_body_mb"`
+	// RequestTimeoutSeconds bounds how long a request may run. Streaming
+	// and bulk transfer endpoints are exempt. Zero means no timeout.
+	RequestTimeoutSeconds int             `toml:"request_timeout_seconds"`
+	CORS                  CORSConfig      `toml:"cors"`
+	RateLimit             RateLimitConfig `toml:"rate_limit"`
+	// TrustedProxies lists the IPs or CIDRs of reverse proxies whose
+	// X-Forwarded-For header is believed. Empty uses the connection address,
+	// so clients cannot pick their own IP for rate limiting and logs.
+	TrustedProxies []string          `toml:"trusted_proxies"`
+	AccessLog      AccessLogConfig   `toml:"access_log"`
+	Maintenance    MaintenanceConfig `toml:"maintenance"`
+	// DisabledHandlers names handlers whose endpoints are not registered:
+	// "web" and "cli" (local channels), "mcp", "swagger" and "webui".
+	DisabledHandlers []string `toml:"disabled_handlers"`
+}
+
+// AccessLogConfig shapes the per-request access log.
+type AccessLogConfig struct {
+	// Fields picks the logged fields from method, uri, route, status,
+	// latency, request_id, remote_ip, user_agent, bytes_in, bytes_out and
+	// error. Empty logs method, uri, status, latency, request_id and
+	// remote_ip.
+	Fields []string `toml:"fields"`
+	// Headers lists request headers to log. Credentials are redacted.
+	Headers []string `toml:"headers"`
+	// SamplePaths are high-volume paths, such as "/bots/*/messages", of
+	// which only one in SampleEvery successful requests is logged.
+	SamplePaths []string `toml:"sample_paths"`
+	SampleEvery int      `toml:"sample_every"`
+}
+
+// MaintenanceConfig sets the maintenance mode the server starts in. Admins
+// can toggle it at runtime through /maintenance.
+type MaintenanceConfig struct {
+	// Enabled makes mutating endpoints answer 503 while reads continue.
+	Enabled bool `toml:"enabled"`
+	// Message is returned to blocked requests.
+	Message string `toml:"message"`
+}
+
+// RateLimitConfig limits abuse-prone endpoints. Each limit is a request
+// count per minute; zero leaves the endpoint unlimited.
+type RateLimitConfig struct {
+	// LoginPerMinute limits login attempts per client IP.
+	LoginPerMinute int `toml:"login_per_minute"`
+	// SendPerMinute limits message sends per account, or per IP for
+	// unauthenticated requests.
+	SendPerMinute int `toml:"send_per_minute"`
+	// CreatePerMinute limits bot and user creation per account.
+	CreatePerMinute int `toml:"create_per_minute"`
+}
+
+// CORSConfig lets browser clients on other origins call the API. With no
+// allowed origins, CORS stays off and only same-origin clients work.
+type CORSConfig struct {
+	// AllowOrigins lists origins such as "https://app.example.com"; "*"
+	// wildcards are accepted.
+	AllowOrigins []string `toml:"allow_origins"`
+	// AllowMethods defaults to the common REST methods when empty.
+	AllowMethods []string `toml:"allow_methods"`
+	// AllowHeaders defaults to the headers named in the preflight request.
+	AllowHeaders []string `toml:"allow_headers"`
+	// AllowCredentials lets browsers send cookies and auth headers. It is
+	// not honored for a bare "*" origin.
+	AllowCredentials bool `toml:"allow_credentials"`
 }
 
 type AdminConfig struct {
@@ -87,6 +159,32 @@ type WorkspaceConfig struct {
 	CNIBinaryDir string `toml:"cni_bin_dir"`
 	CNIConfigDir string `toml:"cni_conf_dir"`
 	RuntimeDir   string `toml:"runtime_dir"`
+	// AttachmentFallbackPath is the container path template used for
+	// attachments the chat model cannot take natively and that have no
+	// container path yet. Supports {hash} and {ext} placeholders.
+	AttachmentFallbackPath string `toml:"attachment_fallback_path"`
+	// StorageQuotaMB caps the media and files each bot may store under
+	// /data, in megabytes. Zero disables the quota.
+	StorageQuotaMB int64 `toml:"storage_quota_mb"`
+	// VerifyMediaOnRead re-hashes media assets as they are served and fails
+	// reads whose content no longer matches the content hash.
+	VerifyMediaOnRead bool `toml:"verify_media_on_read"`
+	// StrictContainerID makes operations on an existing container fail when
+	// the bot has no known container, instead of guessing its ID.
+	StrictContainerID bool `toml:"strict_container_id"`
+	// VerifyPublicAttachmentURLs checks public attachment URLs with a HEAD
+	// request before passing them to the model. Only public addresses are
+	// contacted.
+	VerifyPublicAttachmentURLs bool `toml:"verify_public_attachment_urls"`
+}
+
+// StorageQuotaBytes returns the per-bot storage quota in bytes, or zero when
+// no quota is set.
+func (c WorkspaceConfig) StorageQuotaBytes() int64 {
+	if c.StorageQuotaMB <= 0 {
+		return 0
+	}
+	return c.StorageQuotaMB * 1024 * 1024
 }
 
 // ImageRef returns the fully qualified image reference for the base image,
@@ -131,6 +229,14 @@ type PostgresConfig struct {
 	Password string `toml:"password" json:"-"`
 	Database string `toml:"database"`
 	SSLMode  string `toml:"sslmode"`
+	// Connection pool tuning. Zero values keep the pgxpool defaults.
+	MaxConns                 int `toml:"max_conns"`
+	MinConns                 int `toml:"min_conns"`
+	MaxConnLifetimeSeconds   int `toml:"max_conn_lifetime_seconds"`
+	HealthCheckPeriodSeconds int `toml:"health_check_period_seconds"`
+	// ReadReplicaDSN optionally points read-heavy listing queries at a
+	// replica. The pool tuning above applies to it as well.
+	ReadReplicaDSN string `toml:"read_replica_dsn" json:"-"`
 }
 
 type QdrantConfig struct {
@@ -143,6 +249,53 @@ type SparseConfig struct {
 	BaseURL string `toml:"base_url"`
 }
 
+// MemoryConfig bounds the background memory stores that follow chat turns.
+type MemoryConfig struct {
+	// StoreWorkers is how many memory stores run at once. Zero uses the
+	// default.
+	StoreWorkers int `toml:"store_workers"`
+	// StoreQueueSize is how many memory stores may wait for a worker before
+	// new ones are dropped. Zero uses the default.
+	StoreQueueSize int `toml:"store_queue_size"`
+}
+
+// BotsConfig controls bot lifecycle behavior.
+type BotsConfig struct {
+	// DeleteGraceMinutes is how long a deleted bot can be undeleted before
+	// its container and data are removed. Zero removes them immediately.
+	DeleteGraceMinutes int `toml:"delete_grace_minutes"`
+	// DefaultChatModel is assigned to new bots as their chat model. It may
+	// be a model UUID or a unique model_id.
+	DefaultChatModel string `toml:"default_chat_model"`
+	// DefaultMemoryProvider is the memory provider UUID assigned to new bots.
+	DefaultMemoryProvider string `toml:"default_memory_provider"`
+	// RouteTTLDays is how long a channel route may go without activity
+	// before it is removed. Zero keeps routes forever.
+	RouteTTLDays int `toml:"route_ttl_days"`
+	// GroupContextMinutes is how many minutes of history group and thread
+	// conversations load. Zero uses a full day, like one-to-one chats.
+	GroupContextMinutes int `toml:"group_context_minutes"`
+	// CompressModelRequests gzips model request bodies. Only enable it when
+	// the providers, or the proxy in front of them, accept gzip bodies.
+	CompressModelRequests bool `toml:"compress_model_requests"`
+}
+
+// DeleteGracePeriod returns the undelete window as a duration.
+func (c BotsConfig) DeleteGracePeriod() time.Duration {
+	if c.DeleteGraceMinutes <= 0 {
+		return 0
+	}
+	return time.Duration(c.DeleteGraceMinutes) * time.Minute
+}
+
+// RouteTTL returns the channel route inactivity limit as a duration.
+func (c BotsConfig) RouteTTL() time.Duration {
+	if c.RouteTTLDays <= 0 {
+		return 0
+	}
+	return time.Duration(c.RouteTTLDays) * 24 * time.Hour
+}
+
 const DefaultProvidersDir = "conf/providers"
 
 type RegistryConfig struct {
@@ -187,6 +340,31 @@ func (c SupermarketConfig) GetBaseURL() string {
 	return DefaultSupermarketBaseURL
 }
 
+const (
+	MediaStorageContainer = "container"
+	MediaStorageS3        = "s3"
+)
+
+// MediaStorageConfig selects where media assets are stored. The default keeps
+// them inside bot containers.
+type MediaStorageConfig struct {
+	Provider string   `toml:"provider"`
+	S3       S3Config `toml:"s3"`
+}
+
+// S3Config configures an S3-compatible object store such as AWS S3 or MinIO.
+type S3Config struct {
+	Endpoint        string `toml:"endpoint"`
+	Region          string `toml:"region"`
+	Bucket          string `toml:"bucket"`
+	AccessKeyID     string `toml:"access_key_id"`
+	SecretAccessKey string `toml:"secret_access_key" json:"-"`
+	Prefix          string `toml:"prefix"`
+	// PathStyle addresses objects as endpoint/bucket/key instead of
+	// bucket.endpoint/key. MinIO usually needs it.
+	PathStyle bool `toml:"path_style"`
+}
+
 func Load(path string) (Config, error) {
 	cfg := Config{
 		Log: LogConfig{
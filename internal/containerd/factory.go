@@ -55,7 +55,12 @@ func ProvideService(ctx context.Context, log *slog.Logger, cfg config.Config, ba
 			return nil, nil, fmt.Errorf("connect containerd: %w", err)
 		}
 		svc := NewDefaultService(log, client, cfg)
-		cleanup := func() { _ = client.Close() }
+		rotateCtx, stopRotation := context.WithCancel(context.Background())
+		go svc.rotateTaskLogs(rotateCtx)
+		cleanup := func() {
+			stopRotation()
+			_ = client.Close()
+		}
 		return svc, cleanup, nil
 	}
 }
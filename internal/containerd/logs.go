@@ -0,0 +1,242 @@
+package containerd
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// TaskLogsOptions controls which part of a task's stdout/stderr is returned.
+type TaskLogsOptions struct {
+	// Tail limits the output to the last N lines; zero returns the whole log.
+	Tail int
+	// Follow keeps the stream open and delivers output as it is written until
+	// the context is cancelled or the reader is closed.
+	Follow bool
+}
+
+const (
+	taskLogsSubdir      = "logs"
+	maxTaskLogSize      = 10 << 20
+	taskLogPollInterval = 250 * time.Millisecond
+	taskLogRotateEvery  = time.Minute
+	tailReadChunk       = 32 << 10
+)
+
+// taskLogPath returns the host file that captures the task IO of a container.
+func taskLogPath(dir, containerID string) (string, error) {
+	if containerID == "" || filepath.Base(containerID) != containerID {
+		return "", ErrInvalidArgument
+	}
+	return filepath.Join(dir, containerID+".log"), nil
+}
+
+// prepareTaskLog makes sure the log directory exists and rotates the previous
+// log aside once it grows past maxTaskLogSize, so the output of the task that
+// last crashed stays readable.
+func prepareTaskLog(dir, containerID string) (string, error) {
+	path, err := taskLogPath(dir, containerID)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return "", err
+	}
+	info, err := os.Stat(path)
+	if err == nil && info.Size() > maxTaskLogSize {
+		if err := os.Rename(path, path+".1"); err != nil { //nolint:gosec // G703: path is derived from the container ID
+			return "", err
+		}
+	}
+	return path, nil
+}
+
+// rotateTaskLogs keeps the logs of running tasks under maxTaskLogSize until
+// ctx is done.
+func (s *DefaultService) rotateTaskLogs(ctx context.Context) {
+	ticker := time.NewTicker(taskLogRotateEvery)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		paths, err := filepath.Glob(filepath.Join(s.logDir, "*.log"))
+		if err != nil {
+			continue
+		}
+		for _, path := range paths {
+			if err := rotateTaskLog(path, maxTaskLogSize); err != nil {
+				s.logger.Warn("task log rotation failed", slog.String("path", path), slog.Any("error", err))
+			}
+		}
+	}
+}
+
+// rotateTaskLog moves the content of a task log to path.1 once it grows past
+// maxSize. The shim keeps the log open in append mode, so it is copied and
+// truncated in place rather than renamed.
+func rotateTaskLog(path string, maxSize int64) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	if info.Size() <= maxSize {
+		return nil
+	}
+	src, err := os.Open(path) //nolint:gosec // G304: path is derived from the container ID
+	if err != nil {
+		return err
+	}
+	defer func() { _ = src.Close() }()
+	tmp := path + ".1.tmp"
+	dst, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o640) //nolint:gosec // G304: path is derived from the container ID
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		_ = dst.Close()
+		_ = os.Remove(tmp)
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, path+".1"); err != nil { //nolint:gosec // G703: path is derived from the container ID
+		return err
+	}
+	return os.Truncate(path, 0)
+}
+
+// openTaskLog opens a captured task log positioned according to opts.
+func openTaskLog(ctx context.Context, path string, opts *TaskLogsOptions) (io.ReadCloser, error) {
+	if opts == nil {
+		opts = &TaskLogsOptions{}
+	}
+	f, err := os.Open(path) //nolint:gosec // G304: path is derived from the container ID
+	if err != nil {
+		return nil, err
+	}
+	if opts.Tail > 0 {
+		offset, err := tailOffset(f, opts.Tail)
+		if err == nil {
+			_, err = f.Seek(offset, io.SeekStart)
+		}
+		if err != nil {
+			_ = f.Close()
+			return nil, err
+		}
+	}
+	if !opts.Follow {
+		return f, nil
+	}
+	return newFollowReader(ctx, f, taskLogPollInterval), nil
+}
+
+// tailOffset returns the offset at which the last n lines of f begin.
+func tailOffset(f *os.File, n int) (int64, error) {
+	info, err := f.Stat()
+	if err != nil {
+		return 0, err
+	}
+	end := info.Size()
+	if end == 0 {
+		return 0, nil
+	}
+	// A trailing newline terminates the last line rather than starting a new one.
+	last := make([]byte, 1)
+	if _, err := f.ReadAt(last, end-1); err != nil {
+		return 0, err
+	}
+	if last[0] == '\n' {
+		end--
+	}
+	buf := make([]byte, tailReadChunk)
+	for pos := end; pos > 0; {
+		size := int64(len(buf))
+		if pos < size {
+			size = pos
+		}
+		pos -= size
+		chunk := buf[:size]
+		if _, err := f.ReadAt(chunk, pos); err != nil {
+			return 0, err
+		}
+		for i := len(chunk) - 1; i >= 0; i-- {
+			if chunk[i] != '\n' {
+				continue
+			}
+			n--
+			if n == 0 {
+				return pos + int64(i) + 1, nil
+			}
+		}
+	}
+	return 0, nil
+}
+
+// followReader reads a growing file, waiting for new data at EOF instead of
+// returning io.EOF. It starts over when the file is truncated by rotation,
+// and ends with io.EOF once the context is done or the reader is closed.
+type followReader struct {
+	ctx      context.Context
+	file     *os.File
+	interval time.Duration
+	closed   chan struct{}
+	once     sync.Once
+}
+
+func newFollowReader(ctx context.Context, f *os.File, interval time.Duration) *followReader {
+	return &followReader{ctx: ctx, file: f, interval: interval, closed: make(chan struct{})}
+}
+
+func (r *followReader) Read(p []byte) (int, error) {
+	for {
+		n, err := r.file.Read(p)
+		if n > 0 {
+			return n, nil
+		}
+		if errors.Is(err, os.ErrClosed) {
+			return 0, io.EOF
+		}
+		if err != nil && !errors.Is(err, io.EOF) {
+			return 0, err
+		}
+		r.rewindIfTruncated()
+		timer := time.NewTimer(r.interval)
+		select {
+		case <-r.ctx.Done():
+			timer.Stop()
+			return 0, io.EOF
+		case <-r.closed:
+			timer.Stop()
+			return 0, io.EOF
+		case <-timer.C:
+		}
+	}
+}
+
+func (r *followReader) rewindIfTruncated() {
+	offset, err := r.file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return
+	}
+	if info, err := r.file.Stat(); err == nil && info.Size() < offset {
+		_, _ = r.file.Seek(0, io.SeekStart)
+	}
+}
+
+func (r *followReader) Close() error {
+	r.once.Do(func() { close(r.closed) })
+	return r.file.Close()
+}
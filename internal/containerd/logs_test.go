@@ -0,0 +1,168 @@
+package containerd
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeTaskLog(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "workspace-bot.log")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write log: %v", err)
+	}
+	return path
+}
+
+func TestOpenTaskLogTail(t *testing.T) {
+	t.Parallel()
+	path := writeTaskLog(t, "one\ntwo\nthree\nfour\n")
+
+	cases := []struct {
+		tail int
+		want string
+	}{
+		{tail: 0, want: "one\ntwo\nthree\nfour\n"},
+		{tail: 2, want: "three\nfour\n"},
+		{tail: 4, want: "one\ntwo\nthree\nfour\n"},
+		{tail: 10, want: "one\ntwo\nthree\nfour\n"},
+	}
+	for _, tc := range cases {
+		r, err := openTaskLog(context.Background(), path, &TaskLogsOptions{Tail: tc.tail})
+		if err != nil {
+			t.Fatalf("tail %d: open: %v", tc.tail, err)
+		}
+		got, err := io.ReadAll(r)
+		_ = r.Close()
+		if err != nil {
+			t.Fatalf("tail %d: read: %v", tc.tail, err)
+		}
+		if string(got) != tc.want {
+			t.Fatalf("tail %d: got %q, want %q", tc.tail, got, tc.want)
+		}
+	}
+}
+
+func TestOpenTaskLogFollow(t *testing.T) {
+	t.Parallel()
+	path := writeTaskLog(t, "old\nlast\n")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	r, err := openTaskLog(ctx, path, &TaskLogsOptions{Tail: 1, Follow: true})
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer func() { _ = r.Close() }()
+
+	buf := make([]byte, 64)
+	n, err := r.Read(buf)
+	if err != nil || string(buf[:n]) != "last\n" {
+		t.Fatalf("expected tail line, got %q (err %v)", buf[:n], err)
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o600) //nolint:gosec // G304: test temp dir
+	if err != nil {
+		t.Fatalf("open for append: %v", err)
+	}
+	if _, err := f.WriteString("new\n"); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+	_ = f.Close()
+
+	n, err = r.Read(buf)
+	if err != nil || string(buf[:n]) != "new\n" {
+		t.Fatalf("expected appended line, got %q (err %v)", buf[:n], err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := r.Read(buf)
+		done <- err
+	}()
+	cancel()
+	select {
+	case err := <-done:
+		if !errors.Is(err, io.EOF) {
+			t.Fatalf("expected EOF after cancel, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("follow reader did not stop after cancel")
+	}
+}
+
+func TestTaskLogPathRejectsTraversal(t *testing.T) {
+	t.Parallel()
+	for _, id := range []string{"", "../etc", "a/b"} {
+		if _, err := taskLogPath("/logs", id); err == nil {
+			t.Fatalf("expected error for container ID %q", id)
+		}
+	}
+}
+
+func TestRotateTaskLogCopiesAndTruncates(t *testing.T) {
+	t.Parallel()
+	path := writeTaskLog(t, "one\ntwo\n")
+
+	if err := rotateTaskLog(path, 100); err != nil {
+		t.Fatalf("rotate below limit: %v", err)
+	}
+	if _, err := os.Stat(path + ".1"); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected no rotation below the limit, got %v", err)
+	}
+
+	// Keep the log open for appending, as the shim does.
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o600) //nolint:gosec // G304: test temp dir
+	if err != nil {
+		t.Fatalf("open for append: %v", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	if err := rotateTaskLog(path, 4); err != nil {
+		t.Fatalf("rotate: %v", err)
+	}
+	rotated, err := os.ReadFile(path + ".1") //nolint:gosec // G304: test temp dir
+	if err != nil || string(rotated) != "one\ntwo\n" {
+		t.Fatalf("expected rotated content, got %q (err %v)", rotated, err)
+	}
+	if _, err := f.WriteString("three\n"); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+	current, err := os.ReadFile(path) //nolint:gosec // G304: test temp dir
+	if err != nil || string(current) != "three\n" {
+		t.Fatalf("expected writes to continue at the start of the log, got %q (err %v)", current, err)
+	}
+}
+
+func TestOpenTaskLogFollowAcrossRotation(t *testing.T) {
+	t.Parallel()
+	path := writeTaskLog(t, "old\n")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	r, err := openTaskLog(ctx, path, &TaskLogsOptions{Follow: true})
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer func() { _ = r.Close() }()
+	buf := make([]byte, 64)
+	if n, err := r.Read(buf); err != nil || string(buf[:n]) != "old\n" {
+		t.Fatalf("expected existing line, got %q (err %v)", buf[:n], err)
+	}
+
+	if err := rotateTaskLog(path, 1); err != nil {
+		t.Fatalf("rotate: %v", err)
+	}
+	// Shorter than what was read, so the reader can tell the log restarted.
+	if err := os.WriteFile(path, []byte("n\n"), 0o600); err != nil {
+		t.Fatalf("write after rotation: %v", err)
+	}
+	if n, err := r.Read(buf); err != nil || string(buf[:n]) != "n\n" {
+		t.Fatalf("expected line written after rotation, got %q (err %v)", buf[:n], err)
+	}
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"log/slog"
+	"path/filepath"
 	"runtime"
 	"strings"
 	"syscall"
@@ -109,11 +111,16 @@ type Service interface {
 	PrepareSnapshot(ctx context.Context, snapshotter, key, parent string) error
 	CreateContainerFromSnapshot(ctx context.Context, req CreateContainerRequest) (ContainerInfo, error)
 	SnapshotMounts(ctx context.Context, snapshotter, key string) ([]MountInfo, error)
+
+	// TaskLogs streams the stdout/stderr captured from a container's task.
+	// Returns an error wrapping os.ErrNotExist when no output was captured yet.
+	TaskLogs(ctx context.Context, containerID string, opts *TaskLogsOptions) (io.ReadCloser, error)
 }
 
 type DefaultService struct {
 	client    *containerd.Client
 	namespace string
+	logDir    string
 	logger    *slog.Logger
 }
 
@@ -122,9 +129,18 @@ func NewDefaultService(log *slog.Logger, client *containerd.Client, cfg config.C
 	if namespace == "" {
 		namespace = DefaultNamespace
 	}
+	dataRoot := cfg.Workspace.DataRoot
+	if dataRoot == "" {
+		dataRoot = config.DefaultDataRoot
+	}
+	// The shim writes task logs itself, so the path must be absolute.
+	if abs, err := filepath.Abs(dataRoot); err == nil {
+		dataRoot = abs
+	}
 	return &DefaultService{
 		client:    client,
 		namespace: namespace,
+		logDir:    filepath.Join(dataRoot, taskLogsSubdir),
 		logger:    log.With(slog.String("service", "containerd")),
 	}
 }
@@ -478,13 +494,29 @@ func (s *DefaultService) StartContainer(ctx context.Context, containerID string,
 		return err
 	}
 
-	task, err := container.NewTask(ctx, cio.NullIO)
+	ioCreator := cio.NullIO
+	if logPath, err := prepareTaskLog(s.logDir, containerID); err != nil {
+		s.logger.Warn("task log capture disabled",
+			slog.String("container_id", containerID), slog.Any("error", err))
+	} else {
+		ioCreator = cio.LogFile(logPath)
+	}
+
+	task, err := container.NewTask(ctx, ioCreator)
 	if err != nil {
 		return err
 	}
 	return task.Start(ctx)
 }
 
+func (s *DefaultService) TaskLogs(ctx context.Context, containerID string, opts *TaskLogsOptions) (io.ReadCloser, error) {
+	path, err := taskLogPath(s.logDir, containerID)
+	if err != nil {
+		return nil, err
+	}
+	return openTaskLog(ctx, path, opts)
+}
+
 func (s *DefaultService) getTask(ctx context.Context, containerID string) (containerd.Task, context.Context, error) {
 	if containerID == "" {
 		return nil, nil, ErrInvalidArgument
@@ -3,9 +3,11 @@ package containerd
 import (
 	"context"
 	"fmt"
+	"io"
 	"log/slog"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
 
@@ -357,6 +359,27 @@ func (s *AppleService) ListTasks(ctx context.Context, opts *ListTasksOptions) ([
 	return out, nil
 }
 
+func (s *AppleService) TaskLogs(ctx context.Context, containerID string, opts *TaskLogsOptions) (io.ReadCloser, error) {
+	if containerID == "" {
+		return nil, ErrInvalidArgument
+	}
+	if err := s.ensureHealthy(ctx); err != nil {
+		return nil, err
+	}
+	ctr, err := s.client.LoadContainer(ctx, containerID)
+	if err != nil {
+		return nil, err
+	}
+	var logOpts []acgo.LogsOpt
+	if opts != nil && opts.Tail > 0 {
+		logOpts = append(logOpts, acgo.WithLogsTail(strconv.Itoa(opts.Tail)))
+	}
+	if opts != nil && opts.Follow {
+		logOpts = append(logOpts, acgo.WithLogsFollow())
+	}
+	return ctr.Logs(ctx, logOpts...)
+}
+
 // ---------------------------------------------------------------------------
 // Network (no-op — Apple Container handles networking natively)
 // ---------------------------------------------------------------------------
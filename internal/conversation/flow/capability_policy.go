@@ -26,6 +26,9 @@ type gatewayAttachment struct {
 
 	// FallbackPath is an internal helper only used by server-side routing.
 	FallbackPath string `json:"-"`
+	// SourceURL is an attachment URL not handed to the model; the content is
+	// fetched server-side if the attachment falls back to a file.
+	SourceURL string `json:"-"`
 }
 
 // capabilityRouteResult holds the outcome of splitting attachments by model capability.
@@ -35,6 +38,15 @@ type capabilityRouteResult struct {
 	// Fallback are attachments whose modality is unsupported; they are converted
 	// to container file path references for the LLM to access via tools.
 	Fallback []gatewayAttachment
+	// Order lists where each input attachment was routed, in input order, so
+	// the partitions can be merged back without reordering.
+	Order []attachmentRoute
+}
+
+// attachmentRoute locates one input attachment in Native or Fallback.
+type attachmentRoute struct {
+	Native bool
+	Index  int
 }
 
 // routeAttachmentsByCapability splits attachments based on model compatibilities.
@@ -52,13 +64,16 @@ func routeAttachmentsByCapability(compatibilities []string, attachments []gatewa
 	result := capabilityRouteResult{
 		Native:   make([]gatewayAttachment, 0, len(attachments)),
 		Fallback: make([]gatewayAttachment, 0),
+		Order:    make([]attachmentRoute, 0, len(attachments)),
 	}
 	for _, att := range attachments {
 		att.Type = strings.ToLower(strings.TrimSpace(att.Type))
 		att.Transport = strings.ToLower(strings.TrimSpace(att.Transport))
 		if att.Type == "image" && hasVision && isGatewayNativeAttachment(att) {
+			result.Order = append(result.Order, attachmentRoute{Native: true, Index: len(result.Native)})
 			result.Native = append(result.Native, att)
 		} else {
+			result.Order = append(result.Order, attachmentRoute{Index: len(result.Fallback)})
 			result.Fallback = append(result.Fallback, att)
 		}
 	}
@@ -17,6 +17,7 @@ func TestRouteAttachmentsByCapability_VisionSupported(t *testing.T) {
 	assert.Len(t, result.Fallback, 1)
 	assert.Equal(t, "image", result.Native[0].Type)
 	assert.Equal(t, "audio", result.Fallback[0].Type)
+	assert.Equal(t, []attachmentRoute{{Native: true, Index: 0}, {Native: false, Index: 0}}, result.Order)
 }
 
 func TestRouteAttachmentsByCapability_NoVision(t *testing.T) {
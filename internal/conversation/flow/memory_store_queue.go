@@ -0,0 +1,86 @@
+package flow
+
+import (
+	"context"
+	"sync"
+)
+
+const (
+	// DefaultMemoryStoreWorkers is how many memory stores run at once when
+	// no limit is configured.
+	DefaultMemoryStoreWorkers = 4
+	// DefaultMemoryStoreQueueSize is how many memory stores may wait for a
+	// worker when no queue size is configured.
+	DefaultMemoryStoreQueueSize = 64
+)
+
+// memoryStoreQueue runs memory stores on a fixed set of workers. Stores are
+// best-effort: when the queue is full new ones are dropped rather than
+// blocking the chat turn that produced them.
+type memoryStoreQueue struct {
+	jobs   chan func()
+	wg     sync.WaitGroup
+	mu     sync.RWMutex
+	closed bool
+}
+
+func newMemoryStoreQueue(workers, queueSize int) *memoryStoreQueue {
+	if workers <= 0 {
+		workers = DefaultMemoryStoreWorkers
+	}
+	if queueSize <= 0 {
+		queueSize = DefaultMemoryStoreQueueSize
+	}
+	q := &memoryStoreQueue{jobs: make(chan func(), queueSize)}
+	q.wg.Add(workers)
+	for range workers {
+		go q.work()
+	}
+	return q
+}
+
+func (q *memoryStoreQueue) work() {
+	defer q.wg.Done()
+	for job := range q.jobs {
+		job()
+	}
+}
+
+// enqueue schedules job and reports whether it was accepted. Jobs are
+// rejected once the queue is full or closed.
+func (q *memoryStoreQueue) enqueue(job func()) bool {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	if q.closed {
+		return false
+	}
+	select {
+	case q.jobs <- job:
+		return true
+	default:
+		return false
+	}
+}
+
+// close stops accepting jobs and waits for the queued ones to finish, or
+// until ctx is done.
+func (q *memoryStoreQueue) close(ctx context.Context) error {
+	q.mu.Lock()
+	if !q.closed {
+		q.closed = true
+		close(q.jobs)
+	}
+	q.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		q.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,125 @@
+package flow
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMemoryStoreQueueBoundsConcurrencyUnderBurst(t *testing.T) {
+	t.Parallel()
+
+	const workers = 3
+	q := newMemoryStoreQueue(workers, 50)
+
+	var running, peak, done atomic.Int32
+	release := make(chan struct{})
+	accepted := 0
+	for range 50 {
+		ok := q.enqueue(func() {
+			n := running.Add(1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			<-release
+			running.Add(-1)
+			done.Add(1)
+		})
+		if ok {
+			accepted++
+		}
+	}
+	if accepted != 50 {
+		t.Fatalf("accepted = %d, want 50", accepted)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for running.Load() < workers && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+
+	if err := q.close(context.Background()); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if got := peak.Load(); got != workers {
+		t.Fatalf("peak concurrency = %d, want %d", got, workers)
+	}
+	if got := done.Load(); got != 50 {
+		t.Fatalf("completed = %d, want 50", got)
+	}
+}
+
+func TestMemoryStoreQueueDropsWhenFull(t *testing.T) {
+	t.Parallel()
+
+	q := newMemoryStoreQueue(1, 2)
+	release := make(chan struct{})
+	started := make(chan struct{})
+	var once sync.Once
+	block := func() {
+		once.Do(func() { close(started) })
+		<-release
+	}
+
+	if !q.enqueue(block) {
+		t.Fatal("first job rejected")
+	}
+	<-started
+	if !q.enqueue(block) || !q.enqueue(block) {
+		t.Fatal("queued jobs rejected before the queue was full")
+	}
+	if q.enqueue(block) {
+		t.Fatal("expected job to be dropped when the queue is full")
+	}
+
+	close(release)
+	if err := q.close(context.Background()); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+}
+
+func TestMemoryStoreQueueCloseFlushesAndRejectsNewJobs(t *testing.T) {
+	t.Parallel()
+
+	q := newMemoryStoreQueue(2, 10)
+	var done atomic.Int32
+	for range 10 {
+		q.enqueue(func() {
+			time.Sleep(time.Millisecond)
+			done.Add(1)
+		})
+	}
+	if err := q.close(context.Background()); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if got := done.Load(); got != 10 {
+		t.Fatalf("completed = %d, want 10", got)
+	}
+	if q.enqueue(func() {}) {
+		t.Fatal("expected enqueue after close to be rejected")
+	}
+	if err := q.close(context.Background()); err != nil {
+		t.Fatalf("second close: %v", err)
+	}
+}
+
+func TestMemoryStoreQueueCloseHonorsContext(t *testing.T) {
+	t.Parallel()
+
+	q := newMemoryStoreQueue(1, 1)
+	release := make(chan struct{})
+	defer close(release)
+	q.enqueue(func() { <-release })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := q.close(ctx); err == nil {
+		t.Fatal("expected close to stop waiting when ctx is done")
+	}
+}
@@ -0,0 +1,17 @@
+package flow
+
+import (
+	"fmt"
+
+	agentpkg "github.com/memohai/memoh/internal/agent"
+	"github.com/memohai/memoh/internal/conversation"
+)
+
+// wrapProviderError wraps model provider errors that callers handle
+// specifically in their typed conversation error.
+func wrapProviderError(err error) error {
+	if agentpkg.IsQuotaExhaustedError(err) {
+		return fmt.Errorf("%w: %w", conversation.ErrProviderQuotaExhausted, err)
+	}
+	return err
+}
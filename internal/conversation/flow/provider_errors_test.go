@@ -0,0 +1,21 @@
+package flow
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/memohai/memoh/internal/conversation"
+)
+
+func TestWrapProviderError(t *testing.T) {
+	quota := fmt.Errorf("generate: %w", errors.New("api error 402: Insufficient credits"))
+	if err := wrapProviderError(quota); !errors.Is(err, conversation.ErrProviderQuotaExhausted) {
+		t.Fatalf("expected quota error to be typed, got: %v", err)
+	}
+
+	other := errors.New("api error 500: internal server error")
+	if err := wrapProviderError(other); !errors.Is(err, other) || errors.Is(err, conversation.ErrProviderQuotaExhausted) {
+		t.Fatalf("expected other errors unchanged, got: %v", err)
+	}
+}
@@ -0,0 +1,75 @@
+package flow
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+)
+
+// errNonPublicAddress is returned when a public URL check would dial an
+// address that is not publicly routable.
+var errNonPublicAddress = errors.New("address is not public")
+
+// nonPublicPrefixes are the ranges netip has no predicate for that must not
+// be reached from a public URL check: "this network", carrier-grade NAT, IETF
+// protocol assignments and benchmarking.
+var nonPublicPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+}
+
+// NewPublicURLCheckClient returns the HTTP client used to verify public
+// attachment URLs. It only connects to publicly routable addresses: the
+// check runs on the resolved address of every connection, so DNS rebinding
+// cannot reach loopback, private, link-local or cloud metadata addresses.
+// Redirects are not followed and proxies are not used.
+func NewPublicURLCheckClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: publicURLCheckTimeout,
+		Control: rejectNonPublicAddress,
+	}
+	return &http.Client{
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: publicURLCheckTimeout,
+		},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// rejectNonPublicAddress is a net.Dialer Control hook that refuses to
+// connect to addresses that are not publicly routable.
+func rejectNonPublicAddress(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return err
+	}
+	if !isPublicAddr(addr) {
+		return fmt.Errorf("%w: %s", errNonPublicAddress, addr)
+	}
+	return nil
+}
+
+func isPublicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsValid() || !addr.IsGlobalUnicast() || addr.IsPrivate() {
+		return false
+	}
+	for _, prefix := range nonPublicPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
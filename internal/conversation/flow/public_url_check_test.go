@@ -0,0 +1,68 @@
+package flow
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+)
+
+func TestIsPublicAddr(t *testing.T) {
+	tests := []struct {
+		addr string
+		want bool
+	}{
+		{"93.184.216.34", true},
+		{"2606:2800:220:1:248:1893:25c8:1946", true},
+		{"127.0.0.1", false},
+		{"::1", false},
+		{"10.0.0.5", false},
+		{"172.16.3.4", false},
+		{"192.168.1.1", false},
+		{"169.254.169.254", false},
+		{"fe80::1", false},
+		{"fd00:ec2::254", false},
+		{"100.100.100.200", false},
+		{"0.0.0.0", false},
+		{"::ffff:127.0.0.1", false},
+		{"224.0.0.1", false},
+	}
+	for _, tt := range tests {
+		if got := isPublicAddr(netip.MustParseAddr(tt.addr)); got != tt.want {
+			t.Errorf("isPublicAddr(%s) = %v, want %v", tt.addr, got, tt.want)
+		}
+	}
+}
+
+func TestPublicURLCheckClientRefusesLoopback(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+	}))
+	defer server.Close()
+
+	resolver := &Resolver{}
+	resolver.SetPublicURLCheckClient(NewPublicURLCheckClient())
+	err := resolver.verifyPublicURL(context.Background(), server.URL+"/demo.png", "image")
+	if !errors.Is(err, errNonPublicAddress) {
+		t.Fatalf("expected non-public address error, got %v", err)
+	}
+}
+
+func TestPublicURLCheckClientDoesNotFollowRedirects(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+	}))
+	defer target.Close()
+	redirect := httptest.NewServer(http.RedirectHandler(target.URL+"/demo.png", http.StatusFound))
+	defer redirect.Close()
+
+	client := NewPublicURLCheckClient()
+	client.Transport = http.DefaultTransport // allow dialing the loopback test server
+	resolver := &Resolver{}
+	resolver.SetPublicURLCheckClient(client)
+	if err := resolver.verifyPublicURL(context.Background(), redirect.URL+"/demo.png", "image"); err == nil {
+		t.Fatal("expected a redirect to fail verification")
+	}
+}
@@ -0,0 +1,63 @@
+package flow
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// gzipRequestTransport compresses request bodies with gzip before sending
+// them. Requests that already carry a Content-Encoding are passed through.
+type gzipRequestTransport struct {
+	base http.RoundTripper
+}
+
+func (t *gzipRequestTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if req.Body == nil || req.Body == http.NoBody || req.Header.Get("Content-Encoding") != "" {
+		return base.RoundTrip(req)
+	}
+
+	var compressed bytes.Buffer
+	zw := gzip.NewWriter(&compressed)
+	_, copyErr := io.Copy(zw, req.Body)
+	_ = req.Body.Close()
+	if copyErr != nil {
+		return nil, fmt.Errorf("compress request body: %w", copyErr)
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("compress request body: %w", err)
+	}
+
+	body := compressed.Bytes()
+	out := req.Clone(req.Context())
+	out.Body = io.NopCloser(bytes.NewReader(body))
+	out.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+	out.ContentLength = int64(len(body))
+	out.Header.Set("Content-Encoding", "gzip")
+	return base.RoundTrip(out)
+}
+
+// SetRequestCompression toggles gzip compression of model request bodies,
+// which keeps large payloads (inlined attachments, long history) small on the
+// wire. Only enable it when the provider or proxy in front of it accepts
+// Content-Encoding: gzip request bodies.
+func (r *Resolver) SetRequestCompression(enabled bool) {
+	if r.streamHTTPClient == nil {
+		return
+	}
+	current, compressed := r.streamHTTPClient.Transport.(*gzipRequestTransport)
+	switch {
+	case enabled && !compressed:
+		r.streamHTTPClient.Transport = &gzipRequestTransport{base: r.streamHTTPClient.Transport}
+	case !enabled && compressed:
+		r.streamHTTPClient.Transport = current.base
+	}
+}
@@ -0,0 +1,94 @@
+package flow
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSetRequestCompression(t *testing.T) {
+	t.Parallel()
+
+	const payload = `{"messages":[{"role":"user","content":"hello"}]}`
+	tests := []struct {
+		name     string
+		enabled  bool
+		wantGzip bool
+	}{
+		{name: "enabled", enabled: true, wantGzip: true},
+		{name: "disabled", enabled: false, wantGzip: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var gotEncoding string
+			var gotBody []byte
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotEncoding = r.Header.Get("Content-Encoding")
+				gotBody, _ = io.ReadAll(r.Body)
+				w.WriteHeader(http.StatusNoContent)
+			}))
+			defer server.Close()
+
+			resolver := &Resolver{streamHTTPClient: server.Client()}
+			resolver.SetRequestCompression(tt.enabled)
+
+			req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, server.URL, bytes.NewBufferString(payload))
+			if err != nil {
+				t.Fatalf("new request: %v", err)
+			}
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := resolver.streamHTTPClient.Do(req) //nolint:gosec // G704: httptest server URL
+			if err != nil {
+				t.Fatalf("post: %v", err)
+			}
+			_ = resp.Body.Close()
+
+			if !tt.wantGzip {
+				if gotEncoding != "" {
+					t.Fatalf("expected no content encoding, got %q", gotEncoding)
+				}
+				if string(gotBody) != payload {
+					t.Fatalf("expected plain body, got %q", gotBody)
+				}
+				return
+			}
+			if gotEncoding != "gzip" {
+				t.Fatalf("expected gzip content encoding, got %q", gotEncoding)
+			}
+			zr, err := gzip.NewReader(bytes.NewReader(gotBody))
+			if err != nil {
+				t.Fatalf("body is not gzip encoded: %v", err)
+			}
+			decoded, err := io.ReadAll(zr)
+			if err != nil {
+				t.Fatalf("decompress body: %v", err)
+			}
+			if string(decoded) != payload {
+				t.Fatalf("unexpected decompressed body: %q", decoded)
+			}
+		})
+	}
+}
+
+func TestSetRequestCompressionCanBeDisabledAgain(t *testing.T) {
+	t.Parallel()
+
+	base := &http.Transport{}
+	resolver := &Resolver{streamHTTPClient: &http.Client{Transport: base}}
+	resolver.SetRequestCompression(true)
+	resolver.SetRequestCompression(true)
+	wrapped, ok := resolver.streamHTTPClient.Transport.(*gzipRequestTransport)
+	if !ok || wrapped.base != base {
+		t.Fatalf("expected a single gzip wrapper around the base transport, got %T", resolver.streamHTTPClient.Transport)
+	}
+	resolver.SetRequestCompression(false)
+	if resolver.streamHTTPClient.Transport != base {
+		t.Fatalf("expected base transport to be restored, got %T", resolver.streamHTTPClient.Transport)
+	}
+}
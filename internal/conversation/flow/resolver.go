@@ -22,6 +22,8 @@ import (
 	"github.com/memohai/memoh/internal/accounts"
 	agentpkg "github.com/memohai/memoh/internal/agent"
 	"github.com/memohai/memoh/internal/agent/background"
+	"github.com/memohai/memoh/internal/bots"
+	"github.com/memohai/memoh/internal/channel"
 	"github.com/memohai/memoh/internal/compaction"
 	"github.com/memohai/memoh/internal/conversation"
 	"github.com/memohai/memoh/internal/db/sqlc"
@@ -39,12 +41,24 @@ const (
 	defaultMaxContextMinutes = 24 * 60
 )
 
+// maxContextMinutes returns the history window for a conversation type.
+// Group and thread conversations use the configured group window.
+func (r *Resolver) maxContextMinutes(conversationType string) int {
+	switch channel.NormalizeConversationType(conversationType) {
+	case channel.ConversationTypeGroup, channel.ConversationTypeThread:
+		return coalescePositiveInt(r.groupContextMins, defaultMaxContextMinutes)
+	}
+	return defaultMaxContextMinutes
+}
+
 // SkillEntry represents a skill loaded from the container.
 type SkillEntry struct {
 	Name        string
 	Description string
 	Content     string
 	Metadata    map[string]any
+	// Error is set when the skill failed validation on load.
+	Error string
 }
 
 // SkillLoader loads skills for a given bot from its container.
@@ -57,6 +71,11 @@ type ConversationSettingsReader interface {
 	GetSettings(ctx context.Context, conversationID string) (conversation.Settings, error)
 }
 
+// attachmentWriter writes attachment bytes into a bot container.
+type attachmentWriter interface {
+	WriteContainerFile(ctx context.Context, botID, containerPath string, reader io.Reader) error
+}
+
 // gatewayAssetLoader resolves content_hash references to binary payloads for gateway dispatch.
 type gatewayAssetLoader interface {
 	OpenForGateway(ctx context.Context, botID, contentHash string) (reader io.ReadCloser, mime string, err error)
@@ -78,10 +97,16 @@ type Resolver struct {
 	eventPublisher    messageevent.Publisher
 	skillLoader       SkillLoader
 	assetLoader       gatewayAssetLoader
+	attachmentWriter  attachmentWriter
+	attachmentPath    string
+	publicURLClient   *http.Client
 	pipeline          *pipelinepkg.Pipeline
 	streamHTTPClient  *http.Client
 	bgManager         *background.Manager
+	memoryStores      *memoryStoreQueue
+	groupContextMins  int
 	outboundFn        func(ctx context.Context, botID, channelType, target, text string) error
+	monthlyUsage      func(ctx context.Context, botID string, since time.Time) (spendUsage, error)
 	bgNotifDeferred   sync.Map // key: "botID:sessionID" → wake arrived while a session turn was active
 	sessionTurnMu     sync.Mutex
 	sessionTurnRefs   map[string]int // key: "botID:sessionID" → active turn refcount
@@ -138,6 +163,7 @@ func NewResolver(
 		accountService:   accountService,
 		streamHTTPClient: streamHTTPClient,
 		sessionTurnRefs:  make(map[string]int),
+		memoryStores:     newMemoryStoreQueue(DefaultMemoryStoreWorkers, DefaultMemoryStoreQueueSize),
 		timeout:          timeout,
 		clockLocation:    clockLocation,
 		logger:           log.With(slog.String("service", "conversation_resolver")),
@@ -149,6 +175,33 @@ func (r *Resolver) SetMemoryRegistry(registry *memprovider.Registry) {
 	r.memoryRegistry = registry
 }
 
+// SetMemoryStoreLimits bounds the background memory stores that follow each
+// stored round: at most workers run at once and at most queueSize wait, with
+// further stores dropped. Non-positive values use the defaults. It must be
+// called before the resolver handles chats.
+func (r *Resolver) SetMemoryStoreLimits(workers, queueSize int) {
+	old := r.memoryStores
+	r.memoryStores = newMemoryStoreQueue(workers, queueSize)
+	if old != nil {
+		_ = old.close(context.Background())
+	}
+}
+
+// SetGroupContextMinutes sets how many minutes of history group and thread
+// conversations load. Non-positive values use defaultMaxContextMinutes.
+func (r *Resolver) SetGroupContextMinutes(minutes int) {
+	r.groupContextMins = minutes
+}
+
+// CloseMemoryStores stops accepting memory stores and waits for the queued
+// ones to finish, or until ctx is done.
+func (r *Resolver) CloseMemoryStores(ctx context.Context) error {
+	if r.memoryStores == nil {
+		return nil
+	}
+	return r.memoryStores.close(ctx)
+}
+
 // SetSkillLoader sets the skill loader used to populate usable skills in gateway requests.
 func (r *Resolver) SetSkillLoader(sl SkillLoader) {
 	r.skillLoader = sl
@@ -160,6 +213,23 @@ func (r *Resolver) SetGatewayAssetLoader(loader gatewayAssetLoader) {
 	r.assetLoader = loader
 }
 
+// SetAttachmentFallback configures where attachments the chat model cannot
+// take natively are written when they have no container path yet, so the
+// agent can still read them with the file tool. An empty template uses
+// defaultAttachmentFallbackPath.
+func (r *Resolver) SetAttachmentFallback(writer attachmentWriter, pathTemplate string) {
+	r.attachmentWriter = writer
+	r.attachmentPath = strings.TrimSpace(pathTemplate)
+}
+
+// SetPublicURLCheckClient enables verifying public attachment URLs with a
+// HEAD request before passing them to the model. URLs that fail the check are
+// downgraded to file references. A nil client disables the check. The URLs are
+// user supplied, so production callers pass NewPublicURLCheckClient.
+func (r *Resolver) SetPublicURLCheckClient(client *http.Client) {
+	r.publicURLClient = client
+}
+
 // SetCompactionService configures the compaction service for context compaction.
 func (r *Resolver) SetCompactionService(s *compaction.Service) {
 	r.compactionService = s
@@ -234,7 +304,9 @@ type resolvedContext struct {
 	provider        sqlc.Provider
 	query           string // headerified query
 	injectedRecords *[]conversation.InjectedMessageRecord
-	estimatedTokens int // estimated input token count for compaction
+	estimatedTokens int    // estimated input token count for compaction
+	maxToolRounds   int    // per-turn tool call cap from bot features; 0 = none
+	modelVariant    string // model experiment variant that served the turn, if any
 }
 
 func (r *Resolver) resolve(ctx context.Context, req conversation.ChatRequest) (resolvedContext, error) {
@@ -248,7 +320,14 @@ func (r *Resolver) resolve(ctx context.Context, req conversation.ChatRequest) (r
 		return resolvedContext{}, errors.New("chat id is required")
 	}
 
-	runCfg, chatModel, provider, err := r.buildBaseRunConfig(ctx, baseRunConfigParams{
+	botSettings, _ := r.loadBotSettings(ctx, req.BotID)
+	if !req.DryRun {
+		if err := r.checkSpendCap(ctx, req.BotID, botSettings); err != nil {
+			return resolvedContext{}, err
+		}
+	}
+
+	runCfg, chatModel, provider, modelVariant, err := r.buildBaseRunConfig(ctx, baseRunConfigParams{
 		BotID:             req.BotID,
 		ChatID:            req.ChatID,
 		SessionID:         req.SessionID,
@@ -257,6 +336,8 @@ func (r *Resolver) resolve(ctx context.Context, req conversation.ChatRequest) (r
 		CurrentPlatform:   req.CurrentChannel,
 		ReplyTarget:       req.ReplyTarget,
 		ConversationType:  req.ConversationType,
+		ConversationNotes: req.ConversationNotes,
+		ConversationTags:  req.ConversationTags,
 		SessionToken:      req.ChatToken,
 		Model:             req.Model,
 		Provider:          req.Provider,
@@ -269,12 +350,7 @@ func (r *Resolver) resolve(ctx context.Context, req conversation.ChatRequest) (r
 		)
 		return resolvedContext{}, err
 	}
-	memoryMsg := r.loadMemoryContextMessage(ctx, req)
 	reqMessages := pruneMessagesForGateway(nonNilModelMessages(req.Messages))
-	if memoryMsg != nil {
-		pruned, _ := pruneMessageForGateway(*memoryMsg)
-		memoryMsg = &pruned
-	}
 
 	// When the DCP pipeline has data for this session, build context from
 	// the rendered event stream (RC) + bot turn responses (TR) instead of
@@ -287,18 +363,19 @@ func (r *Resolver) resolve(ctx context.Context, req conversation.ChatRequest) (r
 		}
 	}
 
-	botSettings, _ := r.loadBotSettings(ctx, req.BotID)
-	contextTokenBudget := 0
-	if botSettings.ContextTokenBudget > 0 {
-		contextTokenBudget = botSettings.ContextTokenBudget
-	}
+	features := r.loadBotFeatures(ctx, req.BotID)
+	// The system prompt (SOUL.md, skills, tool instructions) shares the
+	// context window with history, so only the remainder of the configured
+	// budget is available for trimming.
+	contextTokenBudget := historyBudget(botSettings.ContextTokenBudget, botSettings.SystemPromptReserve)
+	trimTokens, trimMessagesCap := historyTrimLimits(contextTokenBudget, features.HistoryTrim)
 
 	var messages []conversation.ModelMessage
 	var estimatedTokens int
 	if usePipeline {
 		messages = r.buildMessagesFromPipeline(ctx, req, contextTokenBudget)
 	} else if r.conversationSvc != nil {
-		loaded, loadErr := r.loadMessages(ctx, req.ChatID, req.SessionID, defaultMaxContextMinutes)
+		loaded, loadErr := r.loadMessages(ctx, req.ChatID, req.SessionID, r.maxContextMinutes(req.ConversationType))
 		if loadErr != nil {
 			r.logger.Error("resolve: loadMessages failed",
 				slog.String("bot_id", req.BotID),
@@ -308,10 +385,13 @@ func (r *Resolver) resolve(ctx context.Context, req conversation.ChatRequest) (r
 		}
 		loaded = pruneHistoryForGateway(loaded)
 		loaded = dedupePersistedCurrentUserMessage(loaded, req)
+		if features.StripHistoryHeaders {
+			loaded = stripHistoryUserHeaders(loaded)
+		}
 		loaded = r.replaceCompactedMessages(ctx, loaded)
-		messages, estimatedTokens = trimMessagesByTokens(r.logger, loaded, contextTokenBudget)
+		messages, estimatedTokens = trimHistory(r.logger, loaded, trimTokens, trimMessagesCap, features.HistorySystemMessages)
 		// When context reaches 70% of the contextTokenBudget (the user-configured
-		// budget cap), run synchronous compaction before sending the request.
+		// budget cap minus the system prompt reserve), run synchronous compaction before sending the request.
 		// contextTokenBudget is the authoritative limit for how much context
 		// the user wants to send to the LLM. We compact at 70% to keep the
 		// context healthy and avoid edge-case timeouts.
@@ -319,7 +399,7 @@ func (r *Resolver) resolve(ctx context.Context, req conversation.ChatRequest) (r
 		if contextTokenBudget > 0 {
 			compactionThreshold = contextTokenBudget * 70 / 100
 		}
-		if compactionThreshold > 0 && estimatedTokens >= compactionThreshold {
+		if compactionThreshold > 0 && estimatedTokens >= compactionThreshold && !req.DryRun {
 			r.logger.Warn("resolve: context reached compaction threshold, running synchronous compaction",
 				slog.String("bot_id", req.BotID),
 				slog.Int("estimated_tokens", estimatedTokens),
@@ -328,7 +408,7 @@ func (r *Resolver) resolve(ctx context.Context, req conversation.ChatRequest) (r
 			)
 			r.runCompactionSync(ctx, req, estimatedTokens)
 			// Reload messages after compaction.
-			loaded, loadErr = r.loadMessages(ctx, req.ChatID, req.SessionID, defaultMaxContextMinutes)
+			loaded, loadErr = r.loadMessages(ctx, req.ChatID, req.SessionID, r.maxContextMinutes(req.ConversationType))
 			if loadErr != nil {
 				r.logger.Error("resolve: reload messages after compaction failed",
 					slog.String("bot_id", req.BotID),
@@ -338,8 +418,11 @@ func (r *Resolver) resolve(ctx context.Context, req conversation.ChatRequest) (r
 			}
 			loaded = pruneHistoryForGateway(loaded)
 			loaded = dedupePersistedCurrentUserMessage(loaded, req)
+			if features.StripHistoryHeaders {
+				loaded = stripHistoryUserHeaders(loaded)
+			}
 			loaded = r.replaceCompactedMessages(ctx, loaded)
-			messages, estimatedTokens = trimMessagesByTokens(r.logger, loaded, contextTokenBudget)
+			messages, estimatedTokens = trimHistory(r.logger, loaded, trimTokens, trimMessagesCap, features.HistorySystemMessages)
 			// Remove tool messages from the recent context — they are large
 			// and unnecessary when we already have a summary. Keep only
 			// user/assistant conversation turns.
@@ -347,8 +430,12 @@ func (r *Resolver) resolve(ctx context.Context, req conversation.ChatRequest) (r
 		}
 		_ = estimatedTokens
 	}
-	if memoryMsg != nil {
-		messages = append(messages, *memoryMsg)
+	// Priming gives the first turn of a session the bot's background
+	// memories, before search has any conversation to match against.
+	primeMemory := features.MemoryPriming && r.claimMemoryPriming(ctx, req.SessionID)
+	if memoryMsg := r.loadMemoryContextMessage(ctx, req, primeMemory); memoryMsg != nil {
+		pruned, _ := pruneMessageForGateway(*memoryMsg)
+		messages = append(messages, pruned)
 	}
 	if !usePipeline {
 		messages = append(messages, reqMessages...)
@@ -379,6 +466,7 @@ func (r *Resolver) resolve(ctx context.Context, req conversation.ChatRequest) (r
 		AttachmentPaths:   extractAttachmentPaths(mergedAttachments),
 		Time:              time.Now().In(tz),
 		Timezone:          runCfg.Identity.Timezone,
+		SenderAttributes:  req.SenderAttributes,
 	}, req.Query)
 	runCfg.Messages = modelMessagesToSDKMessages(nonNilModelMessages(messages))
 	// When using the pipeline the user message is already in the RC;
@@ -429,6 +517,8 @@ func (r *Resolver) resolve(ctx context.Context, req conversation.ChatRequest) (r
 		query:           headerifiedQuery,
 		injectedRecords: injectedRecords,
 		estimatedTokens: estimatedTokens,
+		maxToolRounds:   features.MaxToolRounds,
+		modelVariant:    modelVariant,
 	}, nil
 }
 
@@ -445,6 +535,7 @@ func (r *Resolver) Chat(ctx context.Context, req conversation.ChatRequest) (conv
 		req.RawQuery = strings.TrimSpace(req.Query)
 	}
 	req.Query = rc.query
+	req.ModelVariant = rc.modelVariant
 
 	go r.maybeGenerateSessionTitle(context.WithoutCancel(ctx), req, req.Query)
 
@@ -453,7 +544,7 @@ func (r *Resolver) Chat(ctx context.Context, req conversation.ChatRequest) (conv
 
 	result, err := r.agent.Generate(ctx, cfg)
 	if err != nil {
-		return conversation.ChatResponse{}, err
+		return conversation.ChatResponse{}, wrapProviderError(err)
 	}
 
 	outputMessages := sdkMessagesToModelMessages(result.Messages)
@@ -484,6 +575,8 @@ type baseRunConfigParams struct {
 	CurrentPlatform   string
 	ReplyTarget       string
 	ConversationType  string
+	ConversationNotes string
+	ConversationTags  []string
 	SessionToken      string //nolint:gosec // session credential material, not a hardcoded secret
 	SessionType       string
 	Model             string
@@ -493,13 +586,14 @@ type baseRunConfigParams struct {
 
 // buildBaseRunConfig creates a RunConfig with model, credentials, skills,
 // identity and system prompt — everything except Messages/Query/InlineImages.
-// Both resolve() and ResolveRunConfig() delegate to this shared builder.
-func (r *Resolver) buildBaseRunConfig(ctx context.Context, p baseRunConfigParams) (agentpkg.RunConfig, models.GetResponse, sqlc.Provider, error) {
+// Both resolve() and ResolveRunConfig() delegate to this shared builder. The
+// returned string is the model experiment variant that was selected, if any.
+func (r *Resolver) buildBaseRunConfig(ctx context.Context, p baseRunConfigParams) (agentpkg.RunConfig, models.GetResponse, sqlc.Provider, string, error) {
 	botSettings, err := r.loadBotSettings(ctx, p.BotID)
 	if err != nil {
-		return agentpkg.RunConfig{}, models.GetResponse{}, sqlc.Provider{}, err
+		return agentpkg.RunConfig{}, models.GetResponse{}, sqlc.Provider{}, "", err
 	}
-	loopDetectionEnabled := r.loadBotLoopDetectionEnabled(ctx, p.BotID)
+	features := r.loadBotFeatures(ctx, p.BotID)
 	userTimezoneName, userClockLocation := r.resolveTimezone(ctx, p.BotID, p.UserID)
 
 	chatID := p.ChatID
@@ -509,9 +603,9 @@ func (r *Resolver) buildBaseRunConfig(ctx context.Context, p baseRunConfigParams
 
 	req := buildModelSelectionRequest(p, chatID)
 
-	chatModel, provider, err := r.selectChatModel(ctx, req, botSettings, conversation.Settings{})
+	chatModel, provider, modelVariant, err := r.selectChatModel(ctx, req, botSettings, conversation.Settings{}, features.ModelExperiment)
 	if err != nil {
-		return agentpkg.RunConfig{}, models.GetResponse{}, sqlc.Provider{}, err
+		return agentpkg.RunConfig{}, models.GetResponse{}, sqlc.Provider{}, "", err
 	}
 
 	reasoningEffort := p.ReasoningEffort
@@ -527,7 +621,7 @@ func (r *Resolver) buildBaseRunConfig(ctx context.Context, p baseRunConfigParams
 	authCtx := oauthctx.WithUserID(ctx, p.UserID)
 	creds, err := authResolver.ResolveModelCredentials(authCtx, provider)
 	if err != nil {
-		return agentpkg.RunConfig{}, models.GetResponse{}, sqlc.Provider{}, fmt.Errorf("resolve provider credentials: %w", err)
+		return agentpkg.RunConfig{}, models.GetResponse{}, sqlc.Provider{}, "", fmt.Errorf("resolve provider credentials: %w", err)
 	}
 
 	sdkModel := models.NewSDKChatModel(models.SDKModelConfig{
@@ -538,6 +632,7 @@ func (r *Resolver) buildBaseRunConfig(ctx context.Context, p baseRunConfigParams
 		BaseURL:         providers.ProviderConfigString(provider, "base_url"),
 		HTTPClient:      r.streamHTTPClient,
 		ReasoningConfig: reasoningConfig,
+		Headers:         providers.ProviderConfigHeaders(provider),
 	})
 
 	var agentSkills []agentpkg.SkillEntry
@@ -547,6 +642,11 @@ func (r *Resolver) buildBaseRunConfig(ctx context.Context, p baseRunConfigParams
 			r.logger.Warn("failed to load skills", slog.String("bot_id", p.BotID), slog.Any("error", skillErr))
 		} else {
 			for _, e := range entries {
+				if e.Error != "" {
+					r.logger.Warn("skipping invalid skill",
+						slog.String("bot_id", p.BotID), slog.String("skill", e.Name), slog.String("error", e.Error))
+					continue
+				}
 				if skill, ok := normalizeGatewaySkill(e); ok {
 					agentSkills = append(agentSkills, skill)
 				}
@@ -571,16 +671,18 @@ func (r *Resolver) buildBaseRunConfig(ctx context.Context, p baseRunConfigParams
 			CurrentPlatform:   p.CurrentPlatform,
 			ReplyTarget:       strings.TrimSpace(p.ReplyTarget),
 			ConversationType:  strings.TrimSpace(p.ConversationType),
+			ConversationNotes: strings.TrimSpace(p.ConversationNotes),
+			ConversationTags:  p.ConversationTags,
 			Timezone:          userTimezoneName,
 			TimezoneLocation:  userClockLocation,
 			SessionToken:      p.SessionToken,
 		},
 		Skills:            agentSkills,
-		LoopDetection:     agentpkg.LoopDetectionConfig{Enabled: loopDetectionEnabled},
+		LoopDetection:     loopDetectionConfig(features),
 		BackgroundManager: r.bgManager,
 	}
 
-	return cfg, chatModel, provider, nil
+	return cfg, chatModel, provider, modelVariant, nil
 }
 
 func buildModelSelectionRequest(p baseRunConfigParams, chatID string) conversation.ChatRequest {
@@ -603,7 +705,7 @@ func (r *Resolver) ResolveRunConfig(ctx context.Context, botID, sessionID, chann
 		return pipelinepkg.ResolveRunConfigResult{}, errors.New("bot id is required")
 	}
 
-	cfg, chatModel, _, err := r.buildBaseRunConfig(ctx, baseRunConfigParams{
+	cfg, chatModel, _, _, err := r.buildBaseRunConfig(ctx, baseRunConfigParams{
 		BotID:             botID,
 		SessionID:         sessionID,
 		ChannelIdentityID: channelIdentityID,
@@ -648,6 +750,8 @@ func (r *Resolver) prepareRunConfig(ctx context.Context, cfg agentpkg.RunConfig)
 		Now:                now,
 		Timezone:           cfg.Identity.Timezone,
 		SupportsImageInput: supportsImageInput,
+		ConversationNotes:  cfg.Identity.ConversationNotes,
+		ConversationTags:   cfg.Identity.ConversationTags,
 	})
 
 	if cfg.Query != "" {
@@ -686,9 +790,21 @@ func (r *Resolver) prepareRunConfig(ctx context.Context, cfg agentpkg.RunConfig)
 	return cfg
 }
 
+// loopDetectionConfig maps the bot's loop detection feature flags to the
+// agent configuration.
+func loopDetectionConfig(features bots.Features) agentpkg.LoopDetectionConfig {
+	return agentpkg.LoopDetectionConfig{
+		Enabled:         features.LoopDetection.Enabled,
+		WindowSize:      features.LoopDetection.WindowSize,
+		RepeatThreshold: features.LoopDetection.RepeatThreshold,
+	}
+}
+
+// normalizeGatewaySkill converts a loaded skill for the agent. Skills that
+// failed validation are rejected rather than passed on in degraded form.
 func normalizeGatewaySkill(entry SkillEntry) (agentpkg.SkillEntry, bool) {
 	name := strings.TrimSpace(entry.Name)
-	if name == "" {
+	if name == "" || entry.Error != "" {
 		return agentpkg.SkillEntry{}, false
 	}
 	description := strings.TrimSpace(entry.Description)
@@ -1,26 +1,52 @@
 package flow
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
+	"path"
 	"strings"
+	"time"
 
 	sdk "github.com/memohai/twilight-ai/sdk"
 
 	attachmentpkg "github.com/memohai/memoh/internal/attachment"
 	"github.com/memohai/memoh/internal/conversation"
+	"github.com/memohai/memoh/internal/media"
 	"github.com/memohai/memoh/internal/models"
 )
 
 const (
 	gatewayInlineAttachmentMaxBytes int64 = 20 * 1024 * 1024
+	// defaultAttachmentFallbackPath is where attachments without a container
+	// path are written when no template is configured.
+	defaultAttachmentFallbackPath  = "/data/attachments/{hash}{ext}"
+	maxFallbackAttachmentExtLength = 10
+	// publicURLCheckTimeout bounds the HEAD request verifying a public
+	// attachment URL before it is handed to the model.
+	publicURLCheckTimeout = 5 * time.Second
+	// publicURLFetchTimeout bounds downloading an attachment URL that could
+	// not be passed to the model directly.
+	publicURLFetchTimeout = 30 * time.Second
 )
 
+// gatewayAttachmentMetadataKeys lists the attachment metadata passed through
+// to the gateway. Everything else (storage keys, platform identifiers) stays
+// server-side.
+var gatewayAttachmentMetadataKeys = []string{
+	"page_count",
+	"duration_ms",
+	"width",
+	"height",
+}
+
 // routeAndMergeAttachments applies CapabilityFallbackPolicy to split
 // request attachments by model input modalities, then merges the results
 // into a single []any for the gateway request.
@@ -33,34 +59,130 @@ func (r *Resolver) routeAndMergeAttachments(ctx context.Context, model models.Ge
 	for i := range routed.Fallback {
 		fallbackPath := strings.TrimSpace(routed.Fallback[i].FallbackPath)
 		if fallbackPath == "" {
-			if r != nil && r.logger != nil {
-				r.logger.Warn(
-					"drop attachment without fallback path",
-					slog.String("type", strings.TrimSpace(routed.Fallback[i].Type)),
-					slog.String("transport", strings.TrimSpace(routed.Fallback[i].Transport)),
-					slog.String("content_hash", strings.TrimSpace(routed.Fallback[i].ContentHash)),
-					slog.Bool("has_payload", strings.TrimSpace(routed.Fallback[i].Payload) != ""),
-				)
+			ingestedPath, err := r.ingestFallbackAttachment(ctx, req, routed.Fallback[i])
+			if err != nil {
+				if r != nil && r.logger != nil {
+					r.logger.Warn(
+						"drop attachment without fallback path",
+						slog.String("type", strings.TrimSpace(routed.Fallback[i].Type)),
+						slog.String("transport", strings.TrimSpace(routed.Fallback[i].Transport)),
+						slog.String("content_hash", strings.TrimSpace(routed.Fallback[i].ContentHash)),
+						slog.Bool("has_payload", strings.TrimSpace(routed.Fallback[i].Payload) != ""),
+						slog.Any("error", err),
+					)
+				}
+				routed.Fallback[i] = gatewayAttachment{}
+				continue
 			}
-			routed.Fallback[i] = gatewayAttachment{}
-			continue
+			fallbackPath = ingestedPath
 		}
 		routed.Fallback[i].Type = "file"
 		routed.Fallback[i].Transport = gatewayTransportToolFileRef
 		routed.Fallback[i].Payload = fallbackPath
 	}
-	merged := make([]any, 0, len(routed.Native)+len(routed.Fallback))
-	merged = append(merged, attachmentsToAny(routed.Native)...)
-	for _, fb := range routed.Fallback {
+	// Merge in the original request order so position-sensitive prompts
+	// ("compare the first and second file") keep referring to the right item.
+	ordered := make([]gatewayAttachment, 0, len(routed.Order))
+	for _, route := range routed.Order {
+		if route.Native {
+			ordered = append(ordered, routed.Native[route.Index])
+			continue
+		}
+		fb := routed.Fallback[route.Index]
 		if fb.Type == "" || strings.TrimSpace(fb.Transport) == "" || strings.TrimSpace(fb.Payload) == "" {
 			continue
 		}
-		merged = append(merged, fb)
+		ordered = append(ordered, fb)
 	}
-	if len(merged) == 0 {
-		return []any{}
+	return attachmentsToAny(ordered)
+}
+
+// ingestFallbackAttachment writes the content of an attachment that has no
+// container path into the bot container at a path synthesized from the
+// configured template, and returns that path. Dry runs only synthesize it.
+func (r *Resolver) ingestFallbackAttachment(ctx context.Context, req conversation.ChatRequest, item gatewayAttachment) (string, error) {
+	if r == nil || r.attachmentWriter == nil {
+		return "", errors.New("attachment writer not configured")
+	}
+	botID := strings.TrimSpace(req.BotID)
+	data, err := r.readFallbackAttachment(ctx, botID, item)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	containerPath := expandAttachmentFallbackPath(r.attachmentPath, hex.EncodeToString(sum[:]), fallbackAttachmentExt(item))
+	if req.DryRun {
+		return containerPath, nil
+	}
+	if err := r.attachmentWriter.WriteContainerFile(ctx, botID, containerPath, bytes.NewReader(data)); err != nil {
+		return "", fmt.Errorf("write attachment: %w", err)
+	}
+	return containerPath, nil
+}
+
+// readFallbackAttachment returns the bytes of an inline attachment payload, of
+// its persisted media asset or, with a public URL check client configured, of
+// its source URL.
+func (r *Resolver) readFallbackAttachment(ctx context.Context, botID string, item gatewayAttachment) ([]byte, error) {
+	var reader io.Reader
+	switch {
+	case item.Transport == gatewayTransportInlineDataURL && strings.TrimSpace(item.Payload) != "":
+		decoded, err := attachmentpkg.DecodeBase64(item.Payload, gatewayInlineAttachmentMaxBytes)
+		if err != nil {
+			return nil, err
+		}
+		reader = decoded
+	case strings.TrimSpace(item.ContentHash) != "" && r.assetLoader != nil:
+		asset, _, err := r.assetLoader.OpenForGateway(ctx, botID, strings.TrimSpace(item.ContentHash))
+		if err != nil {
+			return nil, fmt.Errorf("open asset: %w", err)
+		}
+		defer func() {
+			_ = asset.Close()
+		}()
+		reader = io.LimitReader(asset, gatewayInlineAttachmentMaxBytes+1)
+	case strings.TrimSpace(item.SourceURL) != "" && r.publicURLClient != nil:
+		body, err := r.fetchSourceURL(ctx, strings.TrimSpace(item.SourceURL))
+		if err != nil {
+			return nil, fmt.Errorf("fetch attachment url: %w", err)
+		}
+		defer func() {
+			_ = body.Close()
+		}()
+		reader = io.LimitReader(body, gatewayInlineAttachmentMaxBytes+1)
+	default:
+		return nil, errors.New("attachment has no content to ingest")
+	}
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("read attachment: %w", err)
+	}
+	if int64(len(data)) > gatewayInlineAttachmentMaxBytes {
+		return nil, fmt.Errorf("attachment too large to ingest: %d > %d", len(data), gatewayInlineAttachmentMaxBytes)
+	}
+	if len(data) == 0 {
+		return nil, errors.New("attachment is empty")
+	}
+	return data, nil
+}
+
+// fallbackAttachmentExt prefers the extension of the original file name and
+// falls back to the one derived from the MIME type.
+func fallbackAttachmentExt(item gatewayAttachment) string {
+	ext := strings.ToLower(path.Ext(strings.TrimSpace(item.Name)))
+	if len(ext) > 1 && len(ext) <= maxFallbackAttachmentExtLength && !strings.ContainsAny(ext, " /\\") {
+		return ext
 	}
-	return merged
+	return media.ExtensionFromMime(item.Mime)
+}
+
+// expandAttachmentFallbackPath fills the {hash} and {ext} placeholders of a
+// fallback path template.
+func expandAttachmentFallbackPath(template, hash, ext string) string {
+	if template == "" {
+		template = defaultAttachmentFallbackPath
+	}
+	return strings.NewReplacer("{hash}", hash, "{ext}", ext).Replace(template)
 }
 
 func (r *Resolver) prepareGatewayAttachments(ctx context.Context, req conversation.ChatRequest) []gatewayAttachment {
@@ -73,6 +195,7 @@ func (r *Resolver) prepareGatewayAttachments(ctx context.Context, req conversati
 		payload := strings.TrimSpace(raw.Base64)
 		transport := ""
 		fallbackPath := strings.TrimSpace(raw.Path)
+		sourceURL := ""
 		if payload != "" {
 			transport = gatewayTransportInlineDataURL
 		} else {
@@ -89,13 +212,28 @@ func (r *Resolver) prepareGatewayAttachments(ctx context.Context, req conversati
 				// by inlineImageAttachmentAssetIfNeeded below — prefer that path
 				// so we never expose ephemeral or credentialed platform URLs
 				// directly to the model.
+				if err := r.verifyPublicURL(ctx, rawURL, attachmentType); err != nil {
+					if r.logger != nil {
+						r.logger.Warn(
+							"public attachment url failed verification, using file reference",
+							slog.String("url", rawURL),
+							slog.Any("error", err),
+						)
+					}
+					sourceURL = rawURL
+					break
+				}
 				payload = rawURL
 				transport = gatewayTransportPublicURL
 			case rawURL != "" && fallbackPath == "":
-				// URL is either a persisted local path (contentHash set) or an
-				// unresolvable reference; store it as fallbackPath so the agent
-				// can access it via the file tool if needed.
-				fallbackPath = rawURL
+				// URL is either a persisted local path (contentHash set) or a
+				// reference the model cannot fetch itself. Only a path can be
+				// handed to the file tool; other URLs are fetched on fallback.
+				if strings.HasPrefix(rawURL, "/") {
+					fallbackPath = rawURL
+				} else {
+					sourceURL = rawURL
+				}
 			}
 		}
 		item := gatewayAttachment{
@@ -106,8 +244,9 @@ func (r *Resolver) prepareGatewayAttachments(ctx context.Context, req conversati
 			Name:         strings.TrimSpace(raw.Name),
 			Transport:    transport,
 			Payload:      payload,
-			Metadata:     raw.Metadata,
+			Metadata:     gatewayAttachmentMetadata(raw.Metadata),
 			FallbackPath: fallbackPath,
+			SourceURL:    sourceURL,
 		}
 		item = normalizeGatewayAttachmentPayload(item)
 		item = r.inlineImageAttachmentAssetIfNeeded(ctx, strings.TrimSpace(req.BotID), item)
@@ -116,6 +255,85 @@ func (r *Resolver) prepareGatewayAttachments(ctx context.Context, req conversati
 	return prepared
 }
 
+// gatewayAttachmentMetadata returns the whitelisted subset of attachment
+// metadata, or nil when none of the keys are present.
+func gatewayAttachmentMetadata(metadata map[string]any) map[string]any {
+	var out map[string]any
+	for _, key := range gatewayAttachmentMetadataKeys {
+		value, ok := metadata[key]
+		if !ok || value == nil {
+			continue
+		}
+		if out == nil {
+			out = make(map[string]any, len(gatewayAttachmentMetadataKeys))
+		}
+		out[key] = value
+	}
+	return out
+}
+
+// verifyPublicURL checks with a HEAD request that a public attachment URL is
+// reachable and, for images, serves image content. It is a no-op unless a
+// check client is configured.
+func (r *Resolver) verifyPublicURL(ctx context.Context, rawURL, attachmentType string) error {
+	if r == nil || r.publicURLClient == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, publicURLCheckTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, rawURL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := r.publicURLClient.Do(req) //nolint:gosec // G704: the check client only dials public addresses, see NewPublicURLCheckClient
+	if err != nil {
+		return err
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	contentType := strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Type")))
+	if attachmentType == "image" && contentType != "" && !strings.HasPrefix(contentType, "image/") {
+		return fmt.Errorf("unexpected content type %q for image", contentType)
+	}
+	return nil
+}
+
+// fetchSourceURL downloads an attachment URL with the public URL check client,
+// so it is subject to the same address restrictions as the HEAD check.
+func (r *Resolver) fetchSourceURL(ctx context.Context, rawURL string) (io.ReadCloser, error) {
+	ctx, cancel := context.WithTimeout(ctx, publicURLFetchTimeout)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp, err := r.publicURLClient.Do(req) //nolint:gosec // G704: client only dials public addresses
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		_ = resp.Body.Close()
+		cancel()
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return cancelOnClose{ReadCloser: resp.Body, cancel: cancel}, nil
+}
+
+// cancelOnClose releases a request context once its body is closed.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c cancelOnClose) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
+}
+
 func normalizeGatewayAttachmentPayload(item gatewayAttachment) gatewayAttachment {
 	if item.Transport != gatewayTransportInlineDataURL {
 		return item
@@ -130,6 +130,7 @@ func (r *Resolver) buildCompactionConfig(ctx context.Context, req conversation.C
 		APIKey:           creds.APIKey,
 		CodexAccountID:   creds.CodexAccountID,
 		BaseURL:          providers.ProviderConfigString(compactProvider, "base_url"),
+		Headers:          providers.ProviderConfigHeaders(compactProvider),
 		Ratio:            ratio,
 		TotalInputTokens: inputTokens,
 		HTTPClient:       r.streamHTTPClient,
@@ -0,0 +1,35 @@
+package flow
+
+import "testing"
+
+func TestMaxContextMinutesByConversationType(t *testing.T) {
+	tests := []struct {
+		conversationType string
+		groupMinutes     int
+		want             int
+	}{
+		{conversationType: "private", want: 24 * 60},
+		{conversationType: "direct", groupMinutes: 60, want: 24 * 60},
+		{conversationType: "", want: 24 * 60},
+		{conversationType: "group", want: 24 * 60},
+		{conversationType: "group", groupMinutes: 6 * 60, want: 6 * 60},
+		{conversationType: "supergroup", groupMinutes: 6 * 60, want: 6 * 60},
+		{conversationType: "thread", groupMinutes: 90, want: 90},
+	}
+	for _, tt := range tests {
+		r := &Resolver{}
+		r.SetGroupContextMinutes(tt.groupMinutes)
+		if got := r.maxContextMinutes(tt.conversationType); got != tt.want {
+			t.Errorf("maxContextMinutes(%q) with group window %d = %d, want %d", tt.conversationType, tt.groupMinutes, got, tt.want)
+		}
+	}
+}
+
+func TestCoalescePositiveInt(t *testing.T) {
+	if got := coalescePositiveInt(0, -5, 30, 60); got != 30 {
+		t.Fatalf("expected first positive value, got %d", got)
+	}
+	if got := coalescePositiveInt(0, -1); got != 0 {
+		t.Fatalf("expected 0 when no value is positive, got %d", got)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/memohai/memoh/internal/bots"
 	"github.com/memohai/memoh/internal/conversation"
 	"github.com/memohai/memoh/internal/db"
 	messagepkg "github.com/memohai/memoh/internal/message"
@@ -49,6 +50,11 @@ func (r *Resolver) loadMessages(ctx context.Context, chatID string, sessionID st
 		} else {
 			mm.Role = m.Role
 		}
+		// A fully redacted message only carries the redaction marker; keep it
+		// only where dropping it would orphan tool messages.
+		if messagepkg.FullyRedacted(m.Metadata) && !hasToolParts(mm) {
+			continue
+		}
 		var inputTokens *int
 		var outputTokens *int
 		if len(m.Usage) > 0 {
@@ -72,6 +78,19 @@ func (r *Resolver) loadMessages(ctx context.Context, chatID string, sessionID st
 	return result, nil
 }
 
+// hasToolParts reports whether msg issues or answers tool calls.
+func hasToolParts(msg conversation.ModelMessage) bool {
+	if msg.Role == "tool" || len(msg.ToolCalls) > 0 {
+		return true
+	}
+	for _, part := range msg.ContentParts() {
+		if part.Type == "tool-call" || part.Type == "tool-result" {
+			return true
+		}
+	}
+	return false
+}
+
 func dedupePersistedCurrentUserMessage(messages []messageWithUsage, req conversation.ChatRequest) []messageWithUsage {
 	if !req.UserMessagePersisted || len(messages) == 0 {
 		return messages
@@ -108,6 +127,62 @@ func dedupePersistedCurrentUserMessage(messages []messageWithUsage, req conversa
 	return messages
 }
 
+// stripHistoryUserHeaders removes the XML message header from historical user
+// messages so replayed history carries only the raw text. Like gateway
+// pruning, it drops the input token usage of rewritten and later messages.
+func stripHistoryUserHeaders(messages []messageWithUsage) []messageWithUsage {
+	staleUsage := false
+	for i := range messages {
+		if msg, _, ok := stripUserHeader(messages[i].Message); ok {
+			messages[i].Message = msg
+			staleUsage = true
+		}
+		if staleUsage {
+			messages[i].UsageInputTokens = nil
+		}
+	}
+	return messages
+}
+
+// stripUserHeader splits the XML message header off a user message. Both
+// plain-string content and the first text part of multi-part content are
+// handled. ok is false when msg is not a headerified user message.
+func stripUserHeader(msg conversation.ModelMessage) (conversation.ModelMessage, UserMessageMeta, bool) {
+	if !strings.EqualFold(strings.TrimSpace(msg.Role), "user") || len(msg.Content) == 0 {
+		return msg, UserMessageMeta{}, false
+	}
+	var text string
+	if err := json.Unmarshal(msg.Content, &text); err == nil {
+		meta, query, ok := parseUserHeader(text)
+		if !ok {
+			return msg, UserMessageMeta{}, false
+		}
+		msg.Content = conversation.NewTextContent(query)
+		return msg, meta, true
+	}
+	var parts []map[string]any
+	if err := json.Unmarshal(msg.Content, &parts); err != nil {
+		return msg, UserMessageMeta{}, false
+	}
+	for i, part := range parts {
+		if readAnyString(part["type"]) != "text" {
+			continue
+		}
+		meta, query, ok := parseUserHeader(readAnyString(part["text"]))
+		if !ok {
+			return msg, UserMessageMeta{}, false
+		}
+		parts[i]["text"] = query
+		content, err := json.Marshal(parts)
+		if err != nil {
+			return msg, UserMessageMeta{}, false
+		}
+		msg.Content = content
+		return msg, meta, true
+	}
+	return msg, UserMessageMeta{}, false
+}
+
 func estimateMessageTokens(msg conversation.ModelMessage) int {
 	text := msg.TextContent()
 	if len(text) == 0 {
@@ -117,8 +192,100 @@ func estimateMessageTokens(msg conversation.ModelMessage) int {
 	return len(text) / 4
 }
 
+const (
+	// defaultSystemPromptReserve is the number of context tokens kept free for
+	// the system prompt when a bot does not configure its own reserve.
+	defaultSystemPromptReserve = 4096
+	// minHistoryBudgetRatio keeps at least this percentage of the context
+	// budget for history when a bot configures its own reserve, so an
+	// oversized reserve cannot starve the context.
+	minHistoryBudgetRatio = 25
+)
+
+// historyBudget returns the token budget left for conversation history after
+// reserving room for the system prompt. A zero context budget means history
+// is not trimmed by tokens. A non-positive reserve falls back to
+// defaultSystemPromptReserve.
+func historyBudget(contextTokenBudget, systemPromptReserve int) int {
+	if contextTokenBudget <= 0 {
+		return 0
+	}
+	floor := 1
+	if systemPromptReserve > 0 {
+		floor = max(contextTokenBudget*minHistoryBudgetRatio/100, 1)
+	} else {
+		systemPromptReserve = defaultSystemPromptReserve
+	}
+	return max(contextTokenBudget-systemPromptReserve, floor)
+}
+
 func trimMessagesByTokens(log *slog.Logger, messages []messageWithUsage, maxTokens int) ([]conversation.ModelMessage, int) {
-	if maxTokens == 0 || len(messages) == 0 {
+	return trimMessages(log, messages, maxTokens, 0)
+}
+
+// trimHistory trims replayed history like trimMessages, after taking stored
+// system and developer messages out of it as systemMode says. A pinned
+// message goes first in the result and its cost comes out of the token
+// budget, so trimming can neither drop it nor keep a stale one instead.
+func trimHistory(log *slog.Logger, messages []messageWithUsage, maxTokens, maxMessages int, systemMode string) ([]conversation.ModelMessage, int) {
+	messages, pinned := pinSystemMessages(messages, systemMode)
+	if pinned == nil {
+		return trimMessages(log, messages, maxTokens, maxMessages)
+	}
+	pinnedTokens := estimateMessageTokens(*pinned)
+	if maxTokens > 0 {
+		maxTokens = max(maxTokens-pinnedTokens, 1)
+	}
+	if maxMessages > 0 {
+		maxMessages = max(maxMessages-1, 1)
+	}
+	trimmed, totalTokens := trimMessages(log, messages, maxTokens, maxMessages)
+	return append([]conversation.ModelMessage{*pinned}, trimmed...), totalTokens + pinnedTokens
+}
+
+// pinSystemMessages removes stored system and developer messages from
+// history and returns the most recent one to pin, or nil when there is none
+// or systemMode is bots.HistorySystemExclude.
+func pinSystemMessages(messages []messageWithUsage, systemMode string) ([]messageWithUsage, *conversation.ModelMessage) {
+	var pinned *conversation.ModelMessage
+	rest := make([]messageWithUsage, 0, len(messages))
+	for _, m := range messages {
+		switch strings.ToLower(strings.TrimSpace(m.Message.Role)) {
+		case "system", "developer":
+			msg := m.Message
+			pinned = &msg
+		default:
+			rest = append(rest, m)
+		}
+	}
+	if systemMode == bots.HistorySystemExclude {
+		return rest, nil
+	}
+	return rest, pinned
+}
+
+// historyTrimLimits returns the token budget and message cap that a bot's
+// history trim strategy applies to its context budget.
+func historyTrimLimits(contextTokenBudget int, trim bots.HistoryTrimFeature) (maxTokens, maxMessages int) {
+	switch trim.Strategy {
+	case bots.HistoryTrimCount:
+		return 0, trim.MaxMessages
+	case bots.HistoryTrimCombined:
+		return contextTokenBudget, trim.MaxMessages
+	default:
+		return contextTokenBudget, 0
+	}
+}
+
+// trimMessages drops the oldest history until it fits both the token budget
+// and the message cap. A zero limit is not applied. It returns the kept
+// messages and their estimated token count.
+func trimMessages(log *slog.Logger, messages []messageWithUsage, maxTokens, maxMessages int) ([]conversation.ModelMessage, int) {
+	countCutoff := 0
+	if maxMessages > 0 && len(messages) > maxMessages {
+		countCutoff = len(messages) - maxMessages
+	}
+	if (maxTokens == 0 && countCutoff == 0) || len(messages) == 0 {
 		result := make([]conversation.ModelMessage, len(messages))
 		for i, m := range messages {
 			result[i] = m.Message
@@ -127,7 +294,7 @@ func trimMessagesByTokens(log *slog.Logger, messages []messageWithUsage, maxToke
 		for _, m := range messages {
 			totalTokens += estimateMessageTokens(m.Message)
 		}
-		return result, totalTokens
+		return pairToolMessages(result), totalTokens
 	}
 
 	// Scan from newest to oldest, accumulating per-message estimated context
@@ -136,13 +303,14 @@ func trimMessagesByTokens(log *slog.Logger, messages []messageWithUsage, maxToke
 	// based estimate for all messages since this measures context window impact.
 	totalTokens := 0
 	cutoff := 0
-	for i := len(messages) - 1; i >= 0; i-- {
+	for i := len(messages) - 1; i >= countCutoff; i-- {
 		totalTokens += estimateMessageTokens(messages[i].Message)
-		if totalTokens > maxTokens {
+		if maxTokens > 0 && totalTokens > maxTokens {
 			cutoff = i + 1
 			break
 		}
 	}
+	cutoff = max(cutoff, countCutoff)
 
 	// Keep provider-valid message order: a "tool" message must follow a preceding
 	// assistant tool call. When history is head-trimmed, a leading tool message
@@ -156,11 +324,11 @@ func trimMessagesByTokens(log *slog.Logger, messages []messageWithUsage, maxToke
 			slog.Int("total_messages", len(messages)),
 			slog.Int("estimated_tokens", totalTokens),
 			slog.Int("max_tokens", maxTokens),
+			slog.Int("max_messages", maxMessages),
 			slog.Int("cutoff_index", cutoff),
 			slog.Int("kept_messages", len(messages)-cutoff),
 		)
 	}
-
 	result := make([]conversation.ModelMessage, 0, len(messages)-cutoff)
 	if cutoff > 0 {
 		// Add a truncation notice at the beginning so the LLM knows earlier
@@ -175,10 +343,139 @@ func trimMessagesByTokens(log *slog.Logger, messages []messageWithUsage, maxToke
 			),
 		})
 	}
+	kept := make([]conversation.ModelMessage, 0, len(messages)-cutoff)
 	for _, m := range messages[cutoff:] {
-		result = append(result, m.Message)
+		kept = append(kept, m.Message)
+	}
+	return append(result, pairToolMessages(kept)...), totalTokens
+}
+
+// pairToolMessages drops tool calls that no later tool message answers and
+// tool results that answer no earlier call. Providers reject either, and
+// both can appear at the edges of a trimmed window or after an interrupted
+// round. Assistant messages left without content are dropped; calls and
+// results without an ID are kept as they are.
+func pairToolMessages(messages []conversation.ModelMessage) []conversation.ModelMessage {
+	issued := map[string]bool{}
+	answered := map[string]bool{}
+	for _, msg := range messages {
+		switch strings.ToLower(strings.TrimSpace(msg.Role)) {
+		case "assistant":
+			for _, id := range toolCallIDs(msg) {
+				issued[id] = true
+			}
+		case "tool":
+			for _, id := range toolResultIDs(msg) {
+				if issued[id] {
+					answered[id] = true
+				}
+			}
+		}
+	}
+
+	result := make([]conversation.ModelMessage, 0, len(messages))
+	for _, msg := range messages {
+		switch strings.ToLower(strings.TrimSpace(msg.Role)) {
+		case "assistant":
+			filtered, changed := filterToolParts(msg, "tool-call", answered)
+			if !changed {
+				result = append(result, msg)
+				continue
+			}
+			calls := make([]conversation.ToolCall, 0, len(filtered.ToolCalls))
+			for _, tc := range filtered.ToolCalls {
+				if id := strings.TrimSpace(tc.ID); id == "" || answered[id] {
+					calls = append(calls, tc)
+				}
+			}
+			filtered.ToolCalls = calls
+			if filtered.HasContent() {
+				result = append(result, filtered)
+			}
+		case "tool":
+			filtered, changed := filterToolParts(msg, "tool-result", answered)
+			if !changed {
+				result = append(result, msg)
+				continue
+			}
+			if len(toolResultIDs(filtered)) > 0 {
+				result = append(result, filtered)
+			}
+		default:
+			result = append(result, msg)
+		}
+	}
+	return result
+}
+
+// toolCallIDs returns the IDs of the tool calls an assistant message issues.
+func toolCallIDs(msg conversation.ModelMessage) []string {
+	var ids []string
+	for _, part := range toolContentParts(msg.Content) {
+		if id := strings.TrimSpace(part.ToolCallID); part.Type == "tool-call" && id != "" {
+			ids = append(ids, id)
+		}
+	}
+	for _, tc := range msg.ToolCalls {
+		if id := strings.TrimSpace(tc.ID); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// toolResultIDs returns the IDs of the tool calls a tool message answers.
+func toolResultIDs(msg conversation.ModelMessage) []string {
+	var ids []string
+	for _, part := range toolContentParts(msg.Content) {
+		if id := strings.TrimSpace(part.ToolCallID); part.Type == "tool-result" && id != "" {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		if id := strings.TrimSpace(msg.ToolCallID); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// filterToolParts removes content parts of partType whose tool call ID is not
+// in keep, along with a legacy tool_call_id or tool_calls entry that is not
+// in keep. It reports whether anything was removed.
+func filterToolParts(msg conversation.ModelMessage, partType string, keep map[string]bool) (conversation.ModelMessage, bool) {
+	changed := false
+	var raw []json.RawMessage
+	if len(msg.Content) > 0 && json.Unmarshal(msg.Content, &raw) == nil {
+		kept := make([]json.RawMessage, 0, len(raw))
+		for _, item := range raw {
+			var part toolContentPart
+			if json.Unmarshal(item, &part) == nil && part.Type == partType {
+				if id := strings.TrimSpace(part.ToolCallID); id != "" && !keep[id] {
+					changed = true
+					continue
+				}
+			}
+			kept = append(kept, item)
+		}
+		if changed {
+			content, err := json.Marshal(kept)
+			if err != nil {
+				return msg, false
+			}
+			msg.Content = content
+		}
+	}
+	if id := strings.TrimSpace(msg.ToolCallID); partType == "tool-result" && id != "" && !keep[id] {
+		msg.ToolCallID = ""
+		changed = true
+	}
+	for _, tc := range msg.ToolCalls {
+		if id := strings.TrimSpace(tc.ID); partType == "tool-call" && id != "" && !keep[id] {
+			changed = true
+		}
 	}
-	return result, totalTokens
+	return msg, changed
 }
 
 func (r *Resolver) replaceCompactedMessages(ctx context.Context, messages []messageWithUsage) []messageWithUsage {
@@ -0,0 +1,71 @@
+package flow
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	messagepkg "github.com/memohai/memoh/internal/message"
+)
+
+// fakeHistoryMessageService returns fixed session history. Other Service
+// methods are not used by loadMessages.
+type fakeHistoryMessageService struct {
+	messagepkg.Service
+	messages []messagepkg.Message
+}
+
+func (f *fakeHistoryMessageService) ListActiveSinceBySession(context.Context, string, time.Time) ([]messagepkg.Message, error) {
+	return f.messages, nil
+}
+
+func TestLoadMessagesExcludesRedactedContent(t *testing.T) {
+	t.Parallel()
+
+	fullyRedacted := map[string]any{messagepkg.RedactedMetadataKey: map[string]any{"full": true}}
+	service := &fakeHistoryMessageService{messages: []messagepkg.Message{
+		{Role: "user", Content: json.RawMessage(`{"role":"user","content":"hello"}`)},
+		{
+			Role:     "user",
+			Content:  json.RawMessage(`{"role":"user","content":"[redacted]"}`),
+			Metadata: fullyRedacted,
+		},
+		{
+			Role: "assistant",
+			Content: json.RawMessage(`{"role":"assistant","content":[{"type":"text","text":"[redacted]"},` +
+				`{"type":"tool-call","toolCallId":"call-1","toolName":"lookup","input":{}}]}`),
+			Metadata: fullyRedacted,
+		},
+		{
+			Role:    "tool",
+			Content: json.RawMessage(`{"role":"tool","content":[{"type":"tool-result","toolCallId":"call-1","toolName":"lookup","output":{"type":"text","value":"ok"}}]}`),
+		},
+		{
+			Role:     "user",
+			Content:  json.RawMessage(`{"role":"user","content":"call me at [redacted]"}`),
+			Metadata: map[string]any{messagepkg.RedactedMetadataKey: map[string]any{"full": false}},
+		},
+	}}
+	resolver := &Resolver{messageService: service, logger: slog.New(slog.DiscardHandler)}
+
+	loaded, err := resolver.loadMessages(context.Background(), "bot-1", "session-1", 60)
+	if err != nil {
+		t.Fatalf("loadMessages: %v", err)
+	}
+	roles := make([]string, 0, len(loaded))
+	for _, item := range loaded {
+		roles = append(roles, item.Message.Role)
+	}
+	if got := strings.Join(roles, ","); got != "user,assistant,tool,user" {
+		t.Fatalf("roles = %s, want user,assistant,tool,user", got)
+	}
+	if text := loaded[0].Message.TextContent(); text != "hello" {
+		t.Fatalf("first message = %q, want hello", text)
+	}
+	if text := loaded[3].Message.TextContent(); text != "call me at [redacted]" {
+		t.Fatalf("partly redacted message = %q", text)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/memohai/memoh/internal/conversation"
+	"github.com/memohai/memoh/internal/db/sqlc"
 )
 
 // resolveDisplayName returns the best available display name for the request identity:
@@ -13,51 +14,42 @@ func (r *Resolver) resolveDisplayName(ctx context.Context, req conversation.Chat
 	if name := strings.TrimSpace(req.DisplayName); name != "" {
 		return name
 	}
-	if r.queries == nil {
-		return "User"
-	}
-	channelIdentityID := strings.TrimSpace(req.SourceChannelIdentityID)
-	if channelIdentityID == "" {
-		return "User"
-	}
-	pgID, err := parseResolverUUID(channelIdentityID)
-	if err != nil {
+	ci, ok := r.lookupChannelIdentity(ctx, req.SourceChannelIdentityID)
+	if !ok {
 		return "User"
 	}
-	ci, err := r.queries.GetChannelIdentityByID(ctx, pgID)
-	if err == nil && ci.DisplayName.Valid {
+	if ci.DisplayName.Valid {
 		if name := strings.TrimSpace(ci.DisplayName.String); name != "" {
 			return name
 		}
 	}
-	linkedUserID := r.linkedUserIDFromChannelIdentity(ctx, channelIdentityID)
-	if linkedUserID == "" {
-		return "User"
-	}
-	userPgID, err := parseResolverUUID(linkedUserID)
-	if err != nil {
-		return "User"
-	}
-	u, err := r.queries.GetUserByID(ctx, userPgID)
-	if err != nil || !u.DisplayName.Valid {
-		return "User"
-	}
-	if name := strings.TrimSpace(u.DisplayName.String); name != "" {
-		return name
+	if ci.UserDisplayName.Valid {
+		if name := strings.TrimSpace(ci.UserDisplayName.String); name != "" {
+			return name
+		}
 	}
 	return "User"
 }
 
-func (r *Resolver) isExistingChannelIdentityID(ctx context.Context, id string) bool {
+// lookupChannelIdentity loads a channel identity together with its linked
+// user in one query. ok is false when the identity does not exist.
+func (r *Resolver) lookupChannelIdentity(ctx context.Context, id string) (sqlc.GetChannelIdentityWithUserRow, bool) {
 	if r.queries == nil {
-		return false
+		return sqlc.GetChannelIdentityWithUserRow{}, false
+	}
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return sqlc.GetChannelIdentityWithUserRow{}, false
 	}
 	pgID, err := parseResolverUUID(id)
 	if err != nil {
-		return false
+		return sqlc.GetChannelIdentityWithUserRow{}, false
 	}
-	_, err = r.queries.GetChannelIdentityByID(ctx, pgID)
-	return err == nil
+	row, err := r.queries.GetChannelIdentityWithUser(ctx, pgID)
+	if err != nil {
+		return sqlc.GetChannelIdentityWithUserRow{}, false
+	}
+	return row, true
 }
 
 func (r *Resolver) isExistingUserID(ctx context.Context, id string) bool {
@@ -71,18 +63,3 @@ func (r *Resolver) isExistingUserID(ctx context.Context, id string) bool {
 	_, err = r.queries.GetUserByID(ctx, pgID)
 	return err == nil
 }
-
-func (r *Resolver) linkedUserIDFromChannelIdentity(ctx context.Context, channelIdentityID string) string {
-	if r.queries == nil {
-		return ""
-	}
-	pgID, err := parseResolverUUID(channelIdentityID)
-	if err != nil {
-		return ""
-	}
-	row, err := r.queries.GetChannelIdentityByID(ctx, pgID)
-	if err != nil || !row.UserID.Valid {
-		return ""
-	}
-	return row.UserID.String()
-}
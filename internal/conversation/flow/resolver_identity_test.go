@@ -0,0 +1,127 @@
+package flow
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/memohai/memoh/internal/conversation"
+	"github.com/memohai/memoh/internal/db"
+	"github.com/memohai/memoh/internal/db/sqlc"
+)
+
+const (
+	testIdentityID = "11111111-1111-1111-1111-111111111111"
+	testUserID     = "22222222-2222-2222-2222-222222222222"
+)
+
+// identityDB serves GetChannelIdentityWithUser from a single identity row
+// and counts the queries issued.
+type identityDB struct {
+	identity *sqlc.GetChannelIdentityWithUserRow
+	queries  int
+}
+
+func (*identityDB) Exec(context.Context, string, ...any) (pgconn.CommandTag, error) {
+	return pgconn.CommandTag{}, errors.New("not implemented")
+}
+
+func (*identityDB) Query(context.Context, string, ...any) (pgx.Rows, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (f *identityDB) QueryRow(_ context.Context, query string, _ ...any) pgx.Row {
+	f.queries++
+	if !strings.Contains(query, "GetChannelIdentityWithUser") || f.identity == nil {
+		return identityRow{err: pgx.ErrNoRows}
+	}
+	return identityRow{row: *f.identity}
+}
+
+type identityRow struct {
+	row sqlc.GetChannelIdentityWithUserRow
+	err error
+}
+
+func (r identityRow) Scan(dest ...any) error {
+	if r.err != nil {
+		return r.err
+	}
+	*dest[0].(*pgtype.UUID) = r.row.ID
+	*dest[1].(*pgtype.Text) = r.row.DisplayName
+	*dest[2].(*pgtype.UUID) = r.row.LinkedUserID
+	*dest[3].(*pgtype.Text) = r.row.UserDisplayName
+	return nil
+}
+
+func linkedIdentity(displayName, userDisplayName string) *sqlc.GetChannelIdentityWithUserRow {
+	return &sqlc.GetChannelIdentityWithUserRow{
+		ID:              db.ParseUUIDOrEmpty(testIdentityID),
+		DisplayName:     pgtype.Text{String: displayName, Valid: displayName != ""},
+		LinkedUserID:    db.ParseUUIDOrEmpty(testUserID),
+		UserDisplayName: pgtype.Text{String: userDisplayName, Valid: userDisplayName != ""},
+	}
+}
+
+func TestResolveDisplayName_SingleQuery(t *testing.T) {
+	tests := []struct {
+		name     string
+		identity *sqlc.GetChannelIdentityWithUserRow
+		want     string
+	}{
+		{name: "identity name", identity: linkedIdentity("Alice", "Alice Account"), want: "Alice"},
+		{name: "linked user name", identity: linkedIdentity("", "Alice Account"), want: "Alice Account"},
+		{name: "no names", identity: linkedIdentity("", ""), want: "User"},
+		{name: "missing identity", identity: nil, want: "User"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &identityDB{identity: tt.identity}
+			resolver := &Resolver{queries: sqlc.New(fake)}
+			got := resolver.resolveDisplayName(context.Background(), conversation.ChatRequest{
+				SourceChannelIdentityID: testIdentityID,
+			})
+			if got != tt.want {
+				t.Fatalf("resolveDisplayName() = %q, want %q", got, tt.want)
+			}
+			if fake.queries != 1 {
+				t.Fatalf("expected 1 query, got %d", fake.queries)
+			}
+		})
+	}
+}
+
+func TestResolvePersistSenderIDs_UsesLinkedUserFromIdentityQuery(t *testing.T) {
+	fake := &identityDB{identity: linkedIdentity("Alice", "")}
+	resolver := &Resolver{queries: sqlc.New(fake)}
+
+	identityID, userID := resolver.resolvePersistSenderIDs(context.Background(), conversation.ChatRequest{
+		SourceChannelIdentityID: testIdentityID,
+	})
+	if identityID != testIdentityID {
+		t.Fatalf("expected channel identity %q, got %q", testIdentityID, identityID)
+	}
+	if userID != testUserID {
+		t.Fatalf("expected linked user %q, got %q", testUserID, userID)
+	}
+	if fake.queries != 1 {
+		t.Fatalf("expected 1 query, got %d", fake.queries)
+	}
+}
+
+func TestResolvePersistSenderIDs_MissingIdentity(t *testing.T) {
+	fake := &identityDB{}
+	resolver := &Resolver{queries: sqlc.New(fake)}
+
+	identityID, userID := resolver.resolvePersistSenderIDs(context.Background(), conversation.ChatRequest{
+		SourceChannelIdentityID: testIdentityID,
+	})
+	if identityID != "" || userID != "" {
+		t.Fatalf("expected no sender ids, got (%q, %q)", identityID, userID)
+	}
+}
@@ -3,6 +3,7 @@ package flow
 import (
 	"context"
 	"log/slog"
+	"sort"
 	"strings"
 
 	"github.com/memohai/memoh/internal/conversation"
@@ -32,11 +33,58 @@ func (r *Resolver) resolveMemoryProvider(ctx context.Context, botID string) memp
 	return p
 }
 
-func (r *Resolver) loadMemoryContextMessage(ctx context.Context, req conversation.ChatRequest) *conversation.ModelMessage {
+// Memory priming pulls the bot's most recently updated memories into the
+// first turn of a conversation, when there is no history for search to build on.
+const (
+	memoryPrimingLimit        = 10
+	memoryPrimingSnippetChars = 300
+	// memoryPrimingFetchLimit leaves room for memories that are skipped
+	// because search already returned them.
+	memoryPrimingFetchLimit = 2 * memoryPrimingLimit
+	// memoryPrimedKey marks in session metadata that the session's first
+	// turn has been primed.
+	memoryPrimedKey = "memory_primed"
+)
+
+// claimMemoryPriming reports whether the session has not been primed yet and
+// marks it primed, so only its first turn gets priming.
+func (r *Resolver) claimMemoryPriming(ctx context.Context, sessionID string) bool {
+	sessionID = strings.TrimSpace(sessionID)
+	if sessionID == "" || r.sessionService == nil {
+		return false
+	}
+	sess, err := r.sessionService.Get(ctx, sessionID)
+	if err != nil {
+		r.logger.Warn("memory priming: failed to get session", slog.String("session_id", sessionID), slog.Any("error", err))
+		return false
+	}
+	if primed, _ := sess.Metadata[memoryPrimedKey].(bool); primed {
+		return false
+	}
+	metadata := make(map[string]any, len(sess.Metadata)+1)
+	for k, v := range sess.Metadata {
+		metadata[k] = v
+	}
+	metadata[memoryPrimedKey] = true
+	if _, err := r.sessionService.UpdateMetadata(ctx, sessionID, metadata); err != nil {
+		r.logger.Warn("memory priming: failed to mark session", slog.String("session_id", sessionID), slog.Any("error", err))
+		return false
+	}
+	return true
+}
+
+// loadMemoryContextMessage builds the memory context message for a turn.
+// When prime is set, bot-level memories are added next to the search results.
+func (r *Resolver) loadMemoryContextMessage(ctx context.Context, req conversation.ChatRequest, prime bool) *conversation.ModelMessage {
 	p := r.resolveMemoryProvider(ctx, req.BotID)
 	if p == nil {
 		return nil
 	}
+	return r.buildMemoryContextMessage(ctx, p, req, prime)
+}
+
+func (r *Resolver) buildMemoryContextMessage(ctx context.Context, p memprovider.Provider, req conversation.ChatRequest, prime bool) *conversation.ModelMessage {
+	contextText := ""
 	result, err := p.OnBeforeChat(ctx, memprovider.BeforeChatRequest{
 		Query:  req.Query,
 		BotID:  req.BotID,
@@ -44,14 +92,86 @@ func (r *Resolver) loadMemoryContextMessage(ctx context.Context, req conversatio
 	})
 	if err != nil {
 		r.logger.Warn("memory provider OnBeforeChat failed", slog.Any("error", err))
-		return nil
+	} else if result != nil {
+		contextText = strings.TrimSpace(result.ContextText)
 	}
-	if result == nil || strings.TrimSpace(result.ContextText) == "" {
+	if prime {
+		if primed := r.loadPrimingMemories(ctx, p, req.BotID, contextText); primed != "" {
+			if contextText != "" {
+				contextText += "\n"
+			}
+			contextText += primed
+		}
+	}
+	if contextText == "" {
 		return nil
 	}
 	return &conversation.ModelMessage{
 		Role:    "user",
-		Content: conversation.NewTextContent(result.ContextText),
+		Content: conversation.NewTextContent(contextText),
+	}
+}
+
+// loadPrimingMemories formats the bot's most recently updated memories,
+// skipping any already present in the search context.
+func (r *Resolver) loadPrimingMemories(ctx context.Context, p memprovider.Provider, botID, searchContext string) string {
+	botID = strings.TrimSpace(botID)
+	if botID == "" {
+		return ""
+	}
+	resp, err := p.GetAll(ctx, memprovider.GetAllRequest{
+		BotID: botID,
+		Limit: memoryPrimingFetchLimit,
+		Filters: map[string]any{
+			"scopeId": botID,
+			"bot_id":  botID,
+		},
+		NoStats: true,
+	})
+	if err != nil {
+		r.logger.Warn("memory priming GetAll failed", slog.String("bot_id", botID), slog.Any("error", err))
+		return ""
+	}
+	items := memprovider.DeduplicateItems(resp.Results)
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].UpdatedAt > items[j].UpdatedAt
+	})
+
+	var sb strings.Builder
+	count := 0
+	for _, item := range items {
+		if count >= memoryPrimingLimit {
+			break
+		}
+		text := strings.TrimSpace(item.Memory)
+		if text == "" || strings.Contains(searchContext, text) {
+			continue
+		}
+		if count == 0 {
+			sb.WriteString("<memory-context>\nBackground memory about this bot (use when helpful):\n")
+		}
+		sb.WriteString("- ")
+		sb.WriteString(memprovider.TruncateSnippet(text, memoryPrimingSnippetChars))
+		sb.WriteString("\n")
+		count++
+	}
+	if count == 0 {
+		return ""
+	}
+	sb.WriteString("</memory-context>")
+	return sb.String()
+}
+
+// storeMemoryAsync hands the round to the memory store queue, dropping it
+// when the queue is full so that a burst of turns cannot pile up unbounded
+// memory writes.
+func (r *Resolver) storeMemoryAsync(ctx context.Context, req conversation.ChatRequest, messages []conversation.ModelMessage) {
+	if r.memoryStores == nil {
+		go r.storeMemory(ctx, req, messages)
+		return
+	}
+	if !r.memoryStores.enqueue(func() { r.storeMemory(ctx, req, messages) }) {
+		r.logger.Warn("memory store queue full, dropping memory store", slog.String("bot_id", req.BotID))
 	}
 }
 
@@ -2,10 +2,14 @@ package flow
 
 import (
 	"context"
+	"errors"
 	"log/slog"
+	"strings"
 	"testing"
 
 	"github.com/memohai/memoh/internal/conversation"
+	memprovider "github.com/memohai/memoh/internal/memory/adapters"
+	"github.com/memohai/memoh/internal/session"
 )
 
 func TestLoadMemoryContextMessage_NoProvider(t *testing.T) {
@@ -16,8 +20,140 @@ func TestLoadMemoryContextMessage_NoProvider(t *testing.T) {
 		Query:  "hello",
 		BotID:  "bot-1",
 		ChatID: "chat-1",
-	})
+	}, false)
 	if msg != nil {
 		t.Fatalf("expected nil message when no memory provider is configured")
 	}
 }
+
+type fakePrimingMemoryProvider struct {
+	memprovider.Provider
+	searchText  string
+	items       []memprovider.MemoryItem
+	getAllCalls int
+	getAllLimit int
+}
+
+func (p *fakePrimingMemoryProvider) OnBeforeChat(context.Context, memprovider.BeforeChatRequest) (*memprovider.BeforeChatResult, error) {
+	if p.searchText == "" {
+		return nil, nil
+	}
+	return &memprovider.BeforeChatResult{ContextText: p.searchText}, nil
+}
+
+func (p *fakePrimingMemoryProvider) GetAll(_ context.Context, req memprovider.GetAllRequest) (memprovider.SearchResponse, error) {
+	p.getAllCalls++
+	p.getAllLimit = req.Limit
+	return memprovider.SearchResponse{Results: p.items}, nil
+}
+
+func TestBuildMemoryContextMessage_PrimesFirstTurn(t *testing.T) {
+	provider := &fakePrimingMemoryProvider{
+		searchText: "<memory-context>\nRelevant memory context (use when helpful):\n- User likes tea\n</memory-context>",
+		items: []memprovider.MemoryItem{
+			{ID: "1", Memory: "User likes tea", UpdatedAt: "2026-01-03T00:00:00Z"},
+			{ID: "2", Memory: "User lives in Lisbon", UpdatedAt: "2026-01-01T00:00:00Z"},
+			{ID: "3", Memory: "User is a nurse", UpdatedAt: "2026-01-02T00:00:00Z"},
+		},
+	}
+	resolver := &Resolver{logger: slog.Default()}
+	msg := resolver.buildMemoryContextMessage(context.Background(), provider, conversation.ChatRequest{
+		Query: "hi",
+		BotID: "bot-1",
+	}, true)
+	if msg == nil {
+		t.Fatal("expected memory context message")
+	}
+	text := msg.TextContent()
+	if !strings.Contains(text, "User likes tea") {
+		t.Fatalf("expected search context to be kept, got: %s", text)
+	}
+	if strings.Count(text, "User likes tea") != 1 {
+		t.Fatalf("expected primed memories to skip search results, got: %s", text)
+	}
+	nurse := strings.Index(text, "User is a nurse")
+	lisbon := strings.Index(text, "User lives in Lisbon")
+	if nurse < 0 || lisbon < 0 || nurse > lisbon {
+		t.Fatalf("expected primed memories newest first, got: %s", text)
+	}
+	if provider.getAllLimit != memoryPrimingFetchLimit {
+		t.Fatalf("expected priming lookup limited to %d, got %d", memoryPrimingFetchLimit, provider.getAllLimit)
+	}
+}
+
+func TestBuildMemoryContextMessage_SearchOnlyAfterFirstTurn(t *testing.T) {
+	provider := &fakePrimingMemoryProvider{
+		searchText: "<memory-context>\n- User likes tea\n</memory-context>",
+		items:      []memprovider.MemoryItem{{ID: "2", Memory: "User lives in Lisbon"}},
+	}
+	resolver := &Resolver{logger: slog.Default()}
+	msg := resolver.buildMemoryContextMessage(context.Background(), provider, conversation.ChatRequest{
+		Query: "hi",
+		BotID: "bot-1",
+	}, false)
+	if msg == nil {
+		t.Fatal("expected memory context message")
+	}
+	if provider.getAllCalls != 0 {
+		t.Fatalf("expected no priming lookup, got %d GetAll calls", provider.getAllCalls)
+	}
+	if strings.Contains(msg.TextContent(), "Lisbon") {
+		t.Fatalf("expected search results only, got: %s", msg.TextContent())
+	}
+}
+
+func TestBuildMemoryContextMessage_PrimesWithoutSearchResults(t *testing.T) {
+	provider := &fakePrimingMemoryProvider{
+		items: []memprovider.MemoryItem{{ID: "2", Memory: "User lives in Lisbon"}},
+	}
+	resolver := &Resolver{logger: slog.Default()}
+	msg := resolver.buildMemoryContextMessage(context.Background(), provider, conversation.ChatRequest{
+		Query: "hi",
+		BotID: "bot-1",
+	}, true)
+	if msg == nil || !strings.Contains(msg.TextContent(), "User lives in Lisbon") {
+		t.Fatalf("expected primed memory context, got: %#v", msg)
+	}
+}
+
+// metadataSessionService keeps session metadata in memory.
+type metadataSessionService struct {
+	metadata map[string]map[string]any
+}
+
+func (s *metadataSessionService) Get(_ context.Context, sessionID string) (session.Session, error) {
+	return session.Session{ID: sessionID, Metadata: s.metadata[sessionID]}, nil
+}
+
+func (*metadataSessionService) UpdateTitle(context.Context, string, string) (session.Session, error) {
+	return session.Session{}, errors.New("unexpected UpdateTitle call")
+}
+
+func (s *metadataSessionService) UpdateMetadata(_ context.Context, sessionID string, metadata map[string]any) (session.Session, error) {
+	s.metadata[sessionID] = metadata
+	return session.Session{ID: sessionID, Metadata: metadata}, nil
+}
+
+func TestClaimMemoryPrimingOncePerSession(t *testing.T) {
+	sessions := &metadataSessionService{metadata: map[string]map[string]any{
+		"s1": {"source": "web"},
+	}}
+	resolver := &Resolver{logger: slog.Default(), sessionService: sessions}
+	ctx := context.Background()
+
+	if !resolver.claimMemoryPriming(ctx, "s1") {
+		t.Fatal("expected the first turn of a session to be primed")
+	}
+	if resolver.claimMemoryPriming(ctx, "s1") {
+		t.Fatal("expected later turns of the session not to be primed")
+	}
+	if sessions.metadata["s1"]["source"] != "web" {
+		t.Fatalf("expected existing session metadata to be kept, got %v", sessions.metadata["s1"])
+	}
+	if !resolver.claimMemoryPriming(ctx, "s2") {
+		t.Fatal("expected a new session to be primed")
+	}
+	if resolver.claimMemoryPriming(ctx, "") {
+		t.Fatal("expected no priming without a session")
+	}
+}
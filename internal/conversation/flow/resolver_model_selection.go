@@ -4,10 +4,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
+	"math/rand/v2"
 	"strings"
 
 	"github.com/jackc/pgx/v5"
 
+	"github.com/memohai/memoh/internal/bots"
 	"github.com/memohai/memoh/internal/conversation"
 	"github.com/memohai/memoh/internal/db"
 	"github.com/memohai/memoh/internal/db/sqlc"
@@ -15,44 +18,77 @@ import (
 	"github.com/memohai/memoh/internal/settings"
 )
 
-func (r *Resolver) selectChatModel(ctx context.Context, req conversation.ChatRequest, botSettings settings.Settings, cs conversation.Settings) (models.GetResponse, sqlc.Provider, error) {
+// selectChatModel resolves the chat model for a turn. The returned variant
+// names the model experiment variant that served the turn, or is empty when
+// the experiment was not used.
+func (r *Resolver) selectChatModel(ctx context.Context, req conversation.ChatRequest, botSettings settings.Settings, cs conversation.Settings, experiment []bots.ModelVariant) (models.GetResponse, sqlc.Provider, string, error) {
 	if r.modelsService == nil {
-		return models.GetResponse{}, sqlc.Provider{}, errors.New("models service not configured")
+		return models.GetResponse{}, sqlc.Provider{}, "", errors.New("models service not configured")
 	}
-	modelID := strings.TrimSpace(req.Model)
-	providerFilter := strings.TrimSpace(req.Provider)
-
-	// Priority: request model > chat settings > bot settings.
-	if modelID == "" && providerFilter == "" {
-		if value := strings.TrimSpace(cs.ModelID); value != "" {
-			modelID = value
-		} else if value := strings.TrimSpace(botSettings.ChatModelID); value != "" {
-			modelID = value
-		}
+	roll := 0
+	if total := modelVariantWeightTotal(experiment); total > 0 {
+		roll = rand.IntN(total) //nolint:gosec // G404: variant assignment does not need crypto/rand
 	}
+	modelID, variant := chooseChatModelID(req, botSettings, cs, experiment, roll)
+	providerFilter := strings.TrimSpace(req.Provider)
 
 	if modelID == "" {
-		return models.GetResponse{}, sqlc.Provider{}, errors.New("chat model not configured: specify model in request or bot settings")
+		return models.GetResponse{}, sqlc.Provider{}, "", errors.New("chat model not configured: specify model in request or bot settings")
 	}
 
 	if providerFilter == "" {
-		return r.fetchChatModel(ctx, modelID)
+		model, prov, err := r.fetchChatModel(ctx, modelID)
+		if err == nil {
+			return model, prov, variant, nil
+		}
+		// A variant whose model is gone must not fail the turn; the bot's
+		// own chat model serves it instead.
+		fallbackID := strings.TrimSpace(botSettings.ChatModelID)
+		if variant == "" || fallbackID == "" {
+			return models.GetResponse{}, sqlc.Provider{}, "", err
+		}
+		r.logger.Warn("model experiment variant unavailable, using bot chat model",
+			slog.String("bot_id", req.BotID),
+			slog.String("variant", variant),
+			slog.Any("error", err),
+		)
+		model, prov, err = r.fetchChatModel(ctx, fallbackID)
+		return model, prov, "", err
 	}
 
 	candidates, err := r.listCandidates(ctx, providerFilter)
 	if err != nil {
-		return models.GetResponse{}, sqlc.Provider{}, err
+		return models.GetResponse{}, sqlc.Provider{}, "", err
 	}
 	for _, m := range candidates {
 		if matchesModelReference(m, modelID) {
 			prov, err := models.FetchProviderByID(ctx, r.queries, m.ProviderID)
 			if err != nil {
-				return models.GetResponse{}, sqlc.Provider{}, err
+				return models.GetResponse{}, sqlc.Provider{}, "", err
 			}
-			return m, prov, nil
+			return m, prov, "", nil
 		}
 	}
-	return models.GetResponse{}, sqlc.Provider{}, fmt.Errorf("chat model %q not found for provider %q", modelID, providerFilter)
+	return models.GetResponse{}, sqlc.Provider{}, "", fmt.Errorf("chat model %q not found for provider %q", modelID, providerFilter)
+}
+
+// chooseChatModelID picks the model reference for a turn. Priority: request
+// model > chat settings > model experiment > bot settings. The variant is set
+// only when the experiment made the choice; roll selects it and must be in
+// [0, modelVariantWeightTotal(experiment)).
+func chooseChatModelID(req conversation.ChatRequest, botSettings settings.Settings, cs conversation.Settings, experiment []bots.ModelVariant, roll int) (string, string) {
+	modelID := strings.TrimSpace(req.Model)
+	if modelID != "" || strings.TrimSpace(req.Provider) != "" {
+		return modelID, ""
+	}
+	if value := strings.TrimSpace(cs.ModelID); value != "" {
+		return value, ""
+	}
+	if len(experiment) > 0 {
+		variant := pickModelVariant(experiment, roll).Model
+		return variant, variant
+	}
+	return strings.TrimSpace(botSettings.ChatModelID), ""
 }
 
 func (r *Resolver) fetchChatModel(ctx context.Context, modelID string) (models.GetResponse, sqlc.Provider, error) {
@@ -90,6 +126,26 @@ resolved:
 	return model, prov, nil
 }
 
+// pickModelVariant returns the variant whose weight range contains roll,
+// where roll is in [0, modelVariantWeightTotal(variants)).
+func pickModelVariant(variants []bots.ModelVariant, roll int) bots.ModelVariant {
+	for _, v := range variants {
+		if roll < v.Weight {
+			return v
+		}
+		roll -= v.Weight
+	}
+	return variants[len(variants)-1]
+}
+
+func modelVariantWeightTotal(variants []bots.ModelVariant) int {
+	total := 0
+	for _, v := range variants {
+		total += v.Weight
+	}
+	return total
+}
+
 func matchesModelReference(model models.GetResponse, modelRef string) bool {
 	ref := strings.TrimSpace(modelRef)
 	if ref == "" {
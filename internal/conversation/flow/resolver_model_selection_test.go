@@ -1,9 +1,14 @@
 package flow
 
 import (
+	"math"
+	"math/rand/v2"
 	"testing"
 
+	"github.com/memohai/memoh/internal/bots"
+	"github.com/memohai/memoh/internal/conversation"
 	"github.com/memohai/memoh/internal/models"
+	"github.com/memohai/memoh/internal/settings"
 )
 
 func TestMatchesModelReference_ModelID(t *testing.T) {
@@ -88,3 +93,65 @@ func TestBuildModelSelectionRequest_PreservesOverrides(t *testing.T) {
 		t.Fatalf("unexpected provider override: %q", req.Provider)
 	}
 }
+
+func TestPickModelVariantFollowsWeights(t *testing.T) {
+	variants := []bots.ModelVariant{
+		{Model: "model-a", Weight: 70},
+		{Model: "model-b", Weight: 30},
+	}
+	rng := rand.New(rand.NewPCG(1, 2)) //nolint:gosec // G404: seeded for a reproducible distribution
+	total := modelVariantWeightTotal(variants)
+	const turns = 20000
+	counts := map[string]int{}
+	for range turns {
+		counts[pickModelVariant(variants, rng.IntN(total)).Model]++
+	}
+
+	for _, v := range variants {
+		share := float64(counts[v.Model]) / turns
+		want := float64(v.Weight) / float64(total)
+		if math.Abs(share-want) > 0.02 {
+			t.Fatalf("%s served %.3f of turns, want about %.2f", v.Model, share, want)
+		}
+	}
+}
+
+func TestPickModelVariantBoundaries(t *testing.T) {
+	variants := []bots.ModelVariant{
+		{Model: "model-a", Weight: 1},
+		{Model: "model-b", Weight: 3},
+	}
+	want := []string{"model-a", "model-b", "model-b", "model-b"}
+	for roll, model := range want {
+		if got := pickModelVariant(variants, roll).Model; got != model {
+			t.Fatalf("roll %d picked %q, want %q", roll, got, model)
+		}
+	}
+}
+
+func TestChooseChatModelID(t *testing.T) {
+	experiment := []bots.ModelVariant{
+		{Model: "model-a", Weight: 50},
+		{Model: "model-b", Weight: 50},
+	}
+	bot := settings.Settings{ChatModelID: "model-bot"}
+	cases := []struct {
+		name        string
+		req         conversation.ChatRequest
+		cs          conversation.Settings
+		experiment  []bots.ModelVariant
+		wantModel   string
+		wantVariant string
+	}{
+		{name: "experiment draws a variant", experiment: experiment, wantModel: "model-b", wantVariant: "model-b"},
+		{name: "request model wins", req: conversation.ChatRequest{Model: "model-b"}, experiment: experiment, wantModel: "model-b"},
+		{name: "chat model matching a variant is not attributed", cs: conversation.Settings{ModelID: "model-b"}, experiment: experiment, wantModel: "model-b"},
+		{name: "no experiment uses bot model", wantModel: "model-bot"},
+	}
+	for _, tc := range cases {
+		model, variant := chooseChatModelID(tc.req, bot, tc.cs, tc.experiment, 60)
+		if model != tc.wantModel || variant != tc.wantVariant {
+			t.Fatalf("%s: got (%q, %q), want (%q, %q)", tc.name, model, variant, tc.wantModel, tc.wantVariant)
+		}
+	}
+}
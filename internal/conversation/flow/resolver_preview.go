@@ -0,0 +1,127 @@
+package flow
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	sdk "github.com/memohai/twilight-ai/sdk"
+
+	agentpkg "github.com/memohai/memoh/internal/agent"
+	"github.com/memohai/memoh/internal/conversation"
+)
+
+// redactedValue replaces credential material in request previews.
+const redactedValue = "[REDACTED]"
+
+// RequestPreview is the agent request assembled for a chat turn, with
+// credentials removed. It mirrors what would be sent to the model.
+type RequestPreview struct {
+	BotID           string                       `json:"bot_id"`
+	ChatID          string                       `json:"chat_id"`
+	SessionID       string                       `json:"session_id,omitempty"`
+	SessionType     string                       `json:"session_type,omitempty"`
+	Model           RequestPreviewModel          `json:"model"`
+	ReasoningEffort string                       `json:"reasoning_effort,omitempty"`
+	System          string                       `json:"system"`
+	Messages        []sdk.Message                `json:"messages"`
+	Skills          []RequestPreviewSkill        `json:"skills"`
+	Identity        RequestPreviewIdentity       `json:"identity"`
+	LoopDetection   agentpkg.LoopDetectionConfig `json:"loop_detection"`
+	EstimatedTokens int                          `json:"estimated_tokens"`
+}
+
+// RequestPreviewModel describes the selected chat model without provider credentials.
+type RequestPreviewModel struct {
+	ID                 string `json:"id"`
+	ModelID            string `json:"model_id"`
+	ProviderID         string `json:"provider_id,omitempty"`
+	ProviderName       string `json:"provider_name,omitempty"`
+	ClientType         string `json:"client_type,omitempty"`
+	SupportsImageInput bool   `json:"supports_image_input"`
+	SupportsToolCall   bool   `json:"supports_tool_call"`
+}
+
+// RequestPreviewSkill is a skill included in the system prompt.
+type RequestPreviewSkill struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// RequestPreviewIdentity is the routing identity passed to the agent.
+type RequestPreviewIdentity struct {
+	ChannelIdentityID string `json:"channel_identity_id,omitempty"`
+	CurrentPlatform   string `json:"current_platform,omitempty"`
+	ReplyTarget       string `json:"reply_target,omitempty"`
+	ConversationType  string `json:"conversation_type,omitempty"`
+	Timezone          string `json:"timezone,omitempty"`
+	SessionToken      string `json:"session_token,omitempty"` //nolint:gosec // G117: only ever holds the redacted placeholder
+}
+
+// PreviewRequest resolves req like a chat turn and returns the assembled
+// agent request without calling the model. Synchronous compaction is skipped
+// and nothing is persisted.
+func (r *Resolver) PreviewRequest(ctx context.Context, req conversation.ChatRequest) (RequestPreview, error) {
+	req.DryRun = true
+	req.InjectCh = nil
+	rc, err := r.resolve(ctx, req)
+	if err != nil {
+		return RequestPreview{}, err
+	}
+	return r.previewResolved(ctx, rc), nil
+}
+
+// previewResolved builds the final run config the same way Chat and
+// StreamChat do and converts it into a redacted preview.
+func (r *Resolver) previewResolved(ctx context.Context, rc resolvedContext) RequestPreview {
+	cfg := r.prepareRunConfig(ctx, rc.runConfig)
+
+	providerID := ""
+	if rc.provider.ID.Valid {
+		providerID = uuid.UUID(rc.provider.ID.Bytes).String()
+	}
+	skills := make([]RequestPreviewSkill, 0, len(cfg.Skills))
+	for _, skill := range cfg.Skills {
+		skills = append(skills, RequestPreviewSkill{Name: skill.Name, Description: skill.Description})
+	}
+	sessionToken := ""
+	if cfg.Identity.SessionToken != "" {
+		sessionToken = redactedValue
+	}
+
+	return RequestPreview{
+		BotID:       cfg.Identity.BotID,
+		ChatID:      cfg.Identity.ChatID,
+		SessionID:   cfg.Identity.SessionID,
+		SessionType: cfg.SessionType,
+		Model: RequestPreviewModel{
+			ID:                 rc.model.ID,
+			ModelID:            rc.model.ModelID,
+			ProviderID:         providerID,
+			ProviderName:       rc.provider.Name,
+			ClientType:         rc.provider.ClientType,
+			SupportsImageInput: cfg.SupportsImageInput,
+			SupportsToolCall:   cfg.SupportsToolCall,
+		},
+		ReasoningEffort: cfg.ReasoningEffort,
+		System:          cfg.System,
+		Messages:        nonNilSDKMessages(cfg.Messages),
+		Skills:          skills,
+		Identity: RequestPreviewIdentity{
+			ChannelIdentityID: cfg.Identity.ChannelIdentityID,
+			CurrentPlatform:   cfg.Identity.CurrentPlatform,
+			ReplyTarget:       cfg.Identity.ReplyTarget,
+			ConversationType:  cfg.Identity.ConversationType,
+			Timezone:          cfg.Identity.Timezone,
+			SessionToken:      sessionToken,
+		},
+		LoopDetection:   cfg.LoopDetection,
+		EstimatedTokens: rc.estimatedTokens,
+	}
+}
+
+func nonNilSDKMessages(messages []sdk.Message) []sdk.Message {
+	if messages == nil {
+		return []sdk.Message{}
+	}
+	return messages
+}
@@ -0,0 +1,103 @@
+package flow
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	sdk "github.com/memohai/twilight-ai/sdk"
+
+	agentpkg "github.com/memohai/memoh/internal/agent"
+	"github.com/memohai/memoh/internal/db/sqlc"
+	"github.com/memohai/memoh/internal/models"
+)
+
+func TestPreviewResolvedRedactsSecretsWithoutCallingModel(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	const apiKey = "sk-preview-provider-secret" //nolint:gosec // G101: test fixture
+	const sessionToken = "preview-session-secret"
+	rc := resolvedContext{
+		runConfig: agentpkg.RunConfig{
+			Model: models.NewSDKChatModel(models.SDKModelConfig{
+				ModelID:    "gpt-test",
+				ClientType: string(models.ClientTypeOpenAICompletions),
+				APIKey:     apiKey,
+				BaseURL:    server.URL,
+				HTTPClient: server.Client(),
+			}),
+			Messages:         []sdk.Message{sdk.UserMessage("earlier turn")},
+			Query:            "what changed?",
+			SupportsToolCall: true,
+			Identity: agentpkg.SessionContext{
+				BotID:        "bot-1",
+				ChatID:       "bot-1",
+				SessionToken: sessionToken,
+			},
+			Skills: []agentpkg.SkillEntry{{Name: "deploy", Description: "Deploy the app", Content: "steps"}},
+		},
+		model:           models.GetResponse{ID: "model-uuid", ModelID: "gpt-test"},
+		provider:        sqlc.Provider{Name: "openai", ClientType: string(models.ClientTypeOpenAICompletions), Config: []byte(`{"api_key":"` + apiKey + `"}`)},
+		estimatedTokens: 42,
+	}
+
+	preview := (&Resolver{}).previewResolved(context.Background(), rc)
+
+	if got := calls.Load(); got != 0 {
+		t.Fatalf("expected no model calls, got %d", got)
+	}
+	if preview.Identity.SessionToken != redactedValue {
+		t.Fatalf("expected session token to be redacted, got %q", preview.Identity.SessionToken)
+	}
+	payload, err := json.Marshal(preview)
+	if err != nil {
+		t.Fatalf("marshal preview: %v", err)
+	}
+	for _, secret := range []string{apiKey, sessionToken} {
+		if strings.Contains(string(payload), secret) {
+			t.Fatalf("preview leaks secret %q: %s", secret, payload)
+		}
+	}
+
+	if preview.System == "" {
+		t.Fatal("expected system prompt to be assembled")
+	}
+	if !strings.Contains(preview.System, "deploy") {
+		t.Fatalf("expected skill in system prompt, got %q", preview.System)
+	}
+	if len(preview.Messages) != 2 {
+		t.Fatalf("expected history plus query, got %d messages", len(preview.Messages))
+	}
+	if preview.Model.ModelID != "gpt-test" || preview.Model.ProviderName != "openai" {
+		t.Fatalf("unexpected model preview: %+v", preview.Model)
+	}
+	if len(preview.Skills) != 1 || preview.Skills[0].Name != "deploy" {
+		t.Fatalf("unexpected skills: %+v", preview.Skills)
+	}
+	if preview.EstimatedTokens != 42 {
+		t.Fatalf("expected estimated tokens 42, got %d", preview.EstimatedTokens)
+	}
+}
+
+func TestPreviewResolvedOmitsEmptySessionToken(t *testing.T) {
+	t.Parallel()
+
+	preview := (&Resolver{}).previewResolved(context.Background(), resolvedContext{})
+	if preview.Identity.SessionToken != "" {
+		t.Fatalf("expected empty session token, got %q", preview.Identity.SessionToken)
+	}
+	if preview.Messages == nil || preview.Skills == nil {
+		t.Fatal("expected non-nil messages and skills")
+	}
+}
@@ -0,0 +1,106 @@
+package flow
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/memohai/memoh/internal/conversation"
+)
+
+func reasoningRound() []conversation.ModelMessage {
+	return []conversation.ModelMessage{
+		{Role: "user", Content: conversation.NewTextContent("what is 6 x 7?")},
+		{Role: "assistant", Content: json.RawMessage(`[
+			{"type":"reasoning","text":"Six sevens."},
+			{"type":"reasoning","text":"That is 42."},
+			{"type":"text","text":"42"}
+		]`)},
+	}
+}
+
+func TestPersistMessagesStoresReasoningWhenEnabled(t *testing.T) {
+	svc := &fakeToolCallMessageService{}
+	resolver := &Resolver{messageService: svc, logger: slog.New(slog.DiscardHandler)}
+	req := conversation.ChatRequest{BotID: "bot-1", Query: "what is 6 x 7?", CurrentChannel: "telegram"}
+
+	resolver.persistMessages(context.Background(), req, reasoningRound(), "", nil, storeOptions{persistReasoning: true})
+
+	if len(svc.persisted) != 2 {
+		t.Fatalf("expected 2 persisted messages, got %d", len(svc.persisted))
+	}
+	if _, ok := svc.persisted[0].Metadata["reasoning"]; ok {
+		t.Fatalf("expected no reasoning on the user message, got %v", svc.persisted[0].Metadata)
+	}
+	assistant := svc.persisted[1].Metadata
+	if got := assistant["reasoning"]; got != "Six sevens.\n\nThat is 42." {
+		t.Fatalf("unexpected reasoning trace: %v", got)
+	}
+	if assistant["platform"] != "telegram" {
+		t.Fatalf("expected route metadata to be kept, got %v", assistant)
+	}
+}
+
+func TestPersistMessagesOmitsReasoningWhenDisabled(t *testing.T) {
+	svc := &fakeToolCallMessageService{}
+	resolver := &Resolver{messageService: svc, logger: slog.New(slog.DiscardHandler)}
+	req := conversation.ChatRequest{BotID: "bot-1", Query: "what is 6 x 7?"}
+
+	resolver.storeMessages(context.Background(), req, reasoningRound(), "", nil)
+
+	if len(svc.persisted) != 2 {
+		t.Fatalf("expected 2 persisted messages, got %d", len(svc.persisted))
+	}
+	for _, input := range svc.persisted {
+		if _, ok := input.Metadata["reasoning"]; ok {
+			t.Fatalf("expected reasoning to be omitted, got %v", input.Metadata)
+		}
+	}
+	if !strings.Contains(string(svc.persisted[1].Content), "Six sevens.") {
+		t.Fatalf("expected reasoning parts kept in stored content, got %s", svc.persisted[1].Content)
+	}
+}
+
+func TestPersistMessagesKeepsUserHeaderWithReasoning(t *testing.T) {
+	svc := &fakeToolCallMessageService{}
+	resolver := &Resolver{messageService: svc, logger: slog.New(slog.DiscardHandler)}
+	req := conversation.ChatRequest{BotID: "bot-1", Query: "what is 6 x 7?"}
+	round := reasoningRound()
+	round[0].Content = conversation.NewTextContent(FormatUserHeader(UserMessageHeaderInput{
+		MessageID:   "m-1",
+		DisplayName: "Alice",
+		Channel:     "telegram",
+	}, "what is 6 x 7?"))
+
+	resolver.persistMessages(context.Background(), req, round, "", nil, storeOptions{persistReasoning: true, stripUserHeaders: true})
+
+	if len(svc.persisted) != 2 {
+		t.Fatalf("expected 2 persisted messages, got %d", len(svc.persisted))
+	}
+	if _, ok := svc.persisted[0].Metadata["user_header"]; !ok {
+		t.Fatalf("expected user header metadata on the user message, got %v", svc.persisted[0].Metadata)
+	}
+	if got := svc.persisted[1].Metadata["reasoning"]; got != "Six sevens.\n\nThat is 42." {
+		t.Fatalf("unexpected reasoning trace: %v", got)
+	}
+	if !strings.Contains(string(svc.persisted[1].Content), "Six sevens.") {
+		t.Fatalf("expected reasoning kept in content when enabled, got %s", svc.persisted[1].Content)
+	}
+}
+
+func TestWithReasoningTraceDoesNotMutateSharedMetadata(t *testing.T) {
+	meta := map[string]any{"route_id": "route-1"}
+	out := withReasoningTrace(meta, reasoningRound()[1])
+	if _, ok := meta["reasoning"]; ok {
+		t.Fatal("expected shared metadata to be left untouched")
+	}
+	if out["route_id"] != "route-1" || out["reasoning"] == nil {
+		t.Fatalf("unexpected metadata: %v", out)
+	}
+	plain := conversation.ModelMessage{Role: "assistant", Content: conversation.NewTextContent("hi")}
+	if got := withReasoningTrace(meta, plain); len(got) != 1 {
+		t.Fatalf("expected metadata unchanged without reasoning, got %v", got)
+	}
+}
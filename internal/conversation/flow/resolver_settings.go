@@ -2,10 +2,10 @@ package flow
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"log/slog"
 
+	"github.com/memohai/memoh/internal/bots"
 	"github.com/memohai/memoh/internal/db"
 	"github.com/memohai/memoh/internal/settings"
 )
@@ -17,44 +17,23 @@ func (r *Resolver) loadBotSettings(ctx context.Context, botID string) (settings.
 	return r.settingsService.GetBot(ctx, botID)
 }
 
-func (r *Resolver) loadBotLoopDetectionEnabled(ctx context.Context, botID string) bool {
+// loadBotFeatures reads the feature flags from the bot metadata, falling back
+// to defaults when the bot cannot be loaded.
+func (r *Resolver) loadBotFeatures(ctx context.Context, botID string) bots.Features {
 	if r.queries == nil {
-		return false
+		return bots.DefaultFeatures()
 	}
 	botUUID, err := db.ParseUUID(botID)
 	if err != nil {
-		return false
+		return bots.DefaultFeatures()
 	}
 	row, err := r.queries.GetBotByID(ctx, botUUID)
 	if err != nil {
-		r.logger.Debug("failed to load bot metadata for loop detection",
+		r.logger.Debug("failed to load bot metadata for feature flags",
 			slog.String("bot_id", botID),
 			slog.Any("error", err),
 		)
-		return false
+		return bots.DefaultFeatures()
 	}
-	return parseLoopDetectionEnabledFromMetadata(row.Metadata)
-}
-
-func parseLoopDetectionEnabledFromMetadata(payload []byte) bool {
-	if len(payload) == 0 {
-		return false
-	}
-	var metadata map[string]any
-	if err := json.Unmarshal(payload, &metadata); err != nil || metadata == nil {
-		return false
-	}
-	features, ok := metadata["features"].(map[string]any)
-	if !ok {
-		return false
-	}
-	loopDetection, ok := features["loop_detection"].(map[string]any)
-	if !ok {
-		return false
-	}
-	enabled, ok := loopDetection["enabled"].(bool)
-	if !ok {
-		return false
-	}
-	return enabled
+	return bots.ParseFeatures(row.Metadata)
 }
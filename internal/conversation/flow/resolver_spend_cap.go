@@ -0,0 +1,90 @@
+package flow
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/memohai/memoh/internal/conversation"
+	"github.com/memohai/memoh/internal/db"
+	"github.com/memohai/memoh/internal/db/sqlc"
+	"github.com/memohai/memoh/internal/settings"
+)
+
+// spendUsage is a bot's token usage and estimated cost over a period.
+type spendUsage struct {
+	Tokens  int64
+	CostUSD float64
+}
+
+// checkSpendCap returns an error wrapping conversation.ErrSpendCapExceeded
+// when the bot's usage this calendar month (UTC) has reached one of its caps.
+// Failing to read usage does not block the chat.
+func (r *Resolver) checkSpendCap(ctx context.Context, botID string, botSettings settings.Settings) error {
+	if botSettings.MonthlyTokenCap <= 0 && botSettings.MonthlyCostCapUSD <= 0 {
+		return nil
+	}
+	load := r.monthlyUsage
+	if load == nil {
+		load = r.loadSpendUsage
+	}
+	usage, err := load(ctx, botID, monthStart(time.Now()))
+	if err != nil {
+		r.logger.Warn("load monthly usage for spend cap failed", slog.String("bot_id", botID), slog.Any("error", err))
+		return nil
+	}
+	if botSettings.MonthlyTokenCap > 0 && usage.Tokens >= botSettings.MonthlyTokenCap {
+		return fmt.Errorf("%w: used %d of %d tokens this month", conversation.ErrSpendCapExceeded, usage.Tokens, botSettings.MonthlyTokenCap)
+	}
+	if botSettings.MonthlyCostCapUSD > 0 && usage.CostUSD >= botSettings.MonthlyCostCapUSD {
+		return fmt.Errorf("%w: spent an estimated $%.2f of $%.2f this month", conversation.ErrSpendCapExceeded, usage.CostUSD, botSettings.MonthlyCostCapUSD)
+	}
+	return nil
+}
+
+// loadSpendUsage sums the bot's stored usage since the given time, pricing
+// it with the configured model prices.
+func (r *Resolver) loadSpendUsage(ctx context.Context, botID string, since time.Time) (spendUsage, error) {
+	if r.queries == nil {
+		return spendUsage{}, nil
+	}
+	pgBotID, err := db.ParseUUID(botID)
+	if err != nil {
+		return spendUsage{}, err
+	}
+	rows, err := r.queries.GetTokenUsageByModel(ctx, sqlc.GetTokenUsageByModelParams{
+		BotID:    pgBotID,
+		FromTime: pgtype.Timestamptz{Time: since, Valid: true},
+		ToTime:   pgtype.Timestamptz{Time: time.Now().Add(time.Minute), Valid: true},
+	})
+	if err != nil {
+		return spendUsage{}, err
+	}
+	var usage spendUsage
+	for _, row := range rows {
+		usage.Tokens += row.InputTokens + row.OutputTokens
+	}
+	if r.modelsService == nil {
+		return usage, nil
+	}
+	pricing, err := r.modelsService.Pricing(ctx)
+	if err != nil {
+		r.logger.Warn("load model pricing for spend cap failed", slog.Any("error", err))
+		return usage, nil
+	}
+	for _, row := range rows {
+		if row.ModelID.Valid {
+			usage.CostUSD += pricing[row.ModelID.String()].Cost(row.InputTokens, row.OutputTokens)
+		}
+	}
+	return usage, nil
+}
+
+// monthStart returns the start of t's calendar month in UTC.
+func monthStart(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
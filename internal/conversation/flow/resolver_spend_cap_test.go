@@ -0,0 +1,77 @@
+package flow
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/memohai/memoh/internal/conversation"
+	"github.com/memohai/memoh/internal/settings"
+)
+
+func spendCapResolver(usage spendUsage, err error) (*Resolver, *time.Time) {
+	var since time.Time
+	r := &Resolver{logger: slog.New(slog.DiscardHandler)}
+	r.monthlyUsage = func(_ context.Context, _ string, from time.Time) (spendUsage, error) {
+		since = from
+		return usage, err
+	}
+	return r, &since
+}
+
+func TestCheckSpendCapTripsPastTokenCap(t *testing.T) {
+	r, since := spendCapResolver(spendUsage{Tokens: 120_000}, nil)
+
+	err := r.checkSpendCap(context.Background(), "bot-1", settings.Settings{MonthlyTokenCap: 100_000})
+	if !errors.Is(err, conversation.ErrSpendCapExceeded) {
+		t.Fatalf("expected ErrSpendCapExceeded, got %v", err)
+	}
+	if want := monthStart(time.Now()); !since.Equal(want) {
+		t.Fatalf("expected usage counted since %s, got %s", want, *since)
+	}
+}
+
+func TestCheckSpendCapTripsPastCostCap(t *testing.T) {
+	r, _ := spendCapResolver(spendUsage{Tokens: 10, CostUSD: 25.5}, nil)
+
+	err := r.checkSpendCap(context.Background(), "bot-1", settings.Settings{MonthlyTokenCap: 100_000, MonthlyCostCapUSD: 25})
+	if !errors.Is(err, conversation.ErrSpendCapExceeded) {
+		t.Fatalf("expected ErrSpendCapExceeded, got %v", err)
+	}
+}
+
+func TestCheckSpendCapPassesUnderCap(t *testing.T) {
+	r, _ := spendCapResolver(spendUsage{Tokens: 99_999, CostUSD: 24.99}, nil)
+
+	if err := r.checkSpendCap(context.Background(), "bot-1", settings.Settings{MonthlyTokenCap: 100_000, MonthlyCostCapUSD: 25}); err != nil {
+		t.Fatalf("expected under-cap usage to pass, got %v", err)
+	}
+}
+
+func TestCheckSpendCapSkipsUsageWithoutCap(t *testing.T) {
+	r, since := spendCapResolver(spendUsage{Tokens: 1 << 40}, nil)
+
+	if err := r.checkSpendCap(context.Background(), "bot-1", settings.Settings{}); err != nil {
+		t.Fatalf("expected no cap to pass, got %v", err)
+	}
+	if !since.IsZero() {
+		t.Fatal("expected usage not to be loaded without a cap")
+	}
+}
+
+func TestCheckSpendCapPassesWhenUsageUnavailable(t *testing.T) {
+	r, _ := spendCapResolver(spendUsage{}, errors.New("db down"))
+
+	if err := r.checkSpendCap(context.Background(), "bot-1", settings.Settings{MonthlyTokenCap: 1}); err != nil {
+		t.Fatalf("expected usage errors not to block chat, got %v", err)
+	}
+}
+
+func TestMonthStart(t *testing.T) {
+	at := time.Date(2026, 3, 31, 23, 30, 0, 0, time.FixedZone("UTC-5", -5*3600))
+	if got, want := monthStart(at), time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Fatalf("expected %s, got %s", want, got)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"log/slog"
 	"strings"
+	"time"
 
 	sdk "github.com/memohai/twilight-ai/sdk"
 
@@ -13,6 +14,12 @@ import (
 )
 
 func (r *Resolver) storeRound(ctx context.Context, req conversation.ChatRequest, messages []conversation.ModelMessage, modelID string) error {
+	return r.storeTimedRound(ctx, req, messages, modelID, nil)
+}
+
+// storeTimedRound is storeRound with tool call durations, keyed by tool call
+// ID, measured while the round streamed.
+func (r *Resolver) storeTimedRound(ctx context.Context, req conversation.ChatRequest, messages []conversation.ModelMessage, modelID string, toolDurations map[string]time.Duration) error {
 	fullRound := make([]conversation.ModelMessage, 0, len(messages))
 
 	// When the user message was already persisted by a channel adapter, skip
@@ -46,8 +53,8 @@ func (r *Resolver) storeRound(ctx context.Context, req conversation.ChatRequest,
 		return nil
 	}
 
-	r.storeMessages(ctx, req, filtered, modelID)
-	go r.storeMemory(context.WithoutCancel(ctx), req, filtered)
+	r.storeMessages(ctx, req, filtered, modelID, toolDurations)
+	r.storeMemoryAsync(context.WithoutCancel(ctx), req, filtered)
 
 	return nil
 }
@@ -84,7 +91,7 @@ func (r *Resolver) StoreRound(ctx context.Context, botID, sessionID, channelIden
 	return r.storeRound(ctx, req, modelMessages, modelID)
 }
 
-func (r *Resolver) storeMessages(ctx context.Context, req conversation.ChatRequest, messages []conversation.ModelMessage, modelID string) {
+func (r *Resolver) storeMessages(ctx context.Context, req conversation.ChatRequest, messages []conversation.ModelMessage, modelID string, toolDurations map[string]time.Duration) {
 	if r.messageService == nil {
 		return
 	}
@@ -92,11 +99,26 @@ func (r *Resolver) storeMessages(ctx context.Context, req conversation.ChatReque
 		return
 	}
 
-	// Check bot setting for full tool result persistence.
-	pruneToolResults := true
+	// Check bot settings for full tool result and reasoning persistence.
+	opts := storeOptions{pruneToolResults: true}
 	if botSettings, err := r.loadBotSettings(ctx, req.BotID); err == nil {
-		pruneToolResults = !botSettings.PersistFullToolResults
+		opts.pruneToolResults = !botSettings.PersistFullToolResults
+		opts.persistReasoning = botSettings.PersistReasoning
 	}
+	opts.stripUserHeaders = r.loadBotFeatures(ctx, req.BotID).StripHistoryHeaders
+	r.persistMessages(ctx, req, messages, modelID, toolDurations, opts)
+}
+
+// storeOptions holds the per-bot settings that shape how a round is stored.
+type storeOptions struct {
+	pruneToolResults bool
+	persistReasoning bool
+	// stripUserHeaders stores user messages without their XML message header,
+	// keeping the header fields in metadata under "user_header" instead.
+	stripUserHeaders bool
+}
+
+func (r *Resolver) persistMessages(ctx context.Context, req conversation.ChatRequest, messages []conversation.ModelMessage, modelID string, toolDurations map[string]time.Duration, opts storeOptions) {
 	meta := buildRouteMetadata(req)
 	senderChannelIdentityID, senderUserID := r.resolvePersistSenderIDs(ctx, req)
 
@@ -115,17 +137,33 @@ func (r *Resolver) storeMessages(ctx context.Context, req conversation.ChatReque
 		outboundAssets = outboundAssetRefsToMessageRefs(req.OutboundAssetCollector())
 	}
 
+	stored := make([]conversation.ModelMessage, len(messages))
+	messageIDs := make([]string, len(messages))
+	inputs := make([]messagepkg.PersistInput, 0, len(messages))
+	indexes := make([]int, 0, len(messages))
 	for i, msg := range messages {
 		msg = normalizeUserMessageContent(msg)
+		messageMeta := meta
+		if opts.stripUserHeaders {
+			if stripped, header, ok := stripUserHeader(msg); ok {
+				msg = stripped
+				messageMeta = withMetadata(meta, "user_header", header.ToMap())
+			}
+		}
 
 		// Prune tool results at store time to reduce DB bloat.
 		// This prevents ~10KB+ tool outputs from being stored verbatim.
-		if pruneToolResults {
+		if opts.pruneToolResults {
 			if pruned, changed := pruneMessageForGateway(msg); changed {
 				msg = pruned
 			}
 		}
 
+		// The reasoning trace is only copied into metadata when the bot opts in.
+		if opts.persistReasoning && msg.Role == "assistant" {
+			messageMeta = withReasoningTrace(messageMeta, msg)
+		}
+
 		content, err := json.Marshal(msg)
 		if err != nil {
 			r.logger.Warn("storeMessages: marshal failed", slog.Any("error", err))
@@ -157,7 +195,10 @@ func (r *Resolver) storeMessages(ctx context.Context, req conversation.ChatReque
 		if i == lastAssistantIdx && len(outboundAssets) > 0 {
 			assets = append(assets, outboundAssets...)
 		}
-		if _, err := r.messageService.Persist(ctx, messagepkg.PersistInput{
+		if req.ModelVariant != "" && msg.Role == "assistant" {
+			messageMeta = withMetadata(messageMeta, "model_variant", req.ModelVariant)
+		}
+		inputs = append(inputs, messagepkg.PersistInput{
 			BotID:                   req.BotID,
 			SessionID:               req.SessionID,
 			SenderChannelIdentityID: messageSenderChannelIdentityID,
@@ -166,16 +207,120 @@ func (r *Resolver) storeMessages(ctx context.Context, req conversation.ChatReque
 			SourceReplyToMessageID:  sourceReplyToMessageID,
 			Role:                    msg.Role,
 			Content:                 content,
-			Metadata:                meta,
+			Metadata:                messageMeta,
 			Usage:                   msg.Usage,
 			Assets:                  assets,
 			ModelID:                 modelID,
 			EventID:                 messageEventID,
 			DisplayText:             displayText,
-		}); err != nil {
-			r.logger.Warn("persist message failed", slog.Any("error", err))
+		})
+		indexes = append(indexes, i)
+		stored[i] = msg
+	}
+	if len(inputs) == 0 {
+		return
+	}
+
+	persisted, err := r.messageService.PersistBatch(ctx, inputs)
+	for j := range persisted {
+		messageIDs[indexes[j]] = persisted[j].ID
+	}
+	if err != nil {
+		// Retry what the batch did not store message by message so one bad
+		// row does not drop the rest of the round.
+		r.logger.Warn("persist message batch failed, retrying individually", slog.Int("remaining", len(inputs)-len(persisted)), slog.Any("error", err))
+		for j := len(persisted); j < len(inputs); j++ {
+			i := indexes[j]
+			msg, persistErr := r.messageService.Persist(ctx, inputs[j])
+			if persistErr != nil {
+				r.logger.Warn("persist message failed", slog.Any("error", persistErr))
+				stored[i] = conversation.ModelMessage{}
+				continue
+			}
+			messageIDs[i] = msg.ID
+		}
+	}
+	r.storeToolCalls(ctx, req, stored, messageIDs, toolDurations)
+	if req.AssistantOutputRecorder != nil {
+		req.AssistantOutputRecorder(assistantOutputIDs(messages, messageIDs))
+	}
+}
+
+// assistantOutputIDs returns the IDs of the assistant messages that
+// ExtractAssistantOutputs turns into replies, in the same order.
+func assistantOutputIDs(messages []conversation.ModelMessage, messageIDs []string) []string {
+	ids := make([]string, 0, len(messages))
+	for i, msg := range messages {
+		if len(ExtractAssistantOutputs([]conversation.ModelMessage{msg})) == 0 {
+			continue
+		}
+		ids = append(ids, messageIDs[i])
+	}
+	return ids
+}
+
+// storeToolCalls records the tool calls of a stored round as structured rows
+// linked to the assistant messages that issued them. Arguments and results are
+// taken from the stored messages, so they honor tool result pruning.
+func (r *Resolver) storeToolCalls(ctx context.Context, req conversation.ChatRequest, messages []conversation.ModelMessage, messageIDs []string, toolDurations map[string]time.Duration) {
+	calls := extractToolCalls(messages)
+	if len(calls) == 0 {
+		return
+	}
+	inputs := make([]messagepkg.ToolCallInput, 0, len(calls))
+	for _, call := range calls {
+		messageID := messageIDs[call.MessageIndex]
+		if messageID == "" {
+			continue
+		}
+		inputs = append(inputs, messagepkg.ToolCallInput{
+			BotID:      req.BotID,
+			SessionID:  req.SessionID,
+			MessageID:  messageID,
+			ToolCallID: call.ToolCallID,
+			ToolName:   call.ToolName,
+			Arguments:  call.Arguments,
+			Result:     call.Result,
+			IsError:    call.IsError,
+			Duration:   toolDurations[call.ToolCallID],
+		})
+	}
+	if len(inputs) == 0 {
+		return
+	}
+	if err := r.messageService.RecordToolCalls(ctx, inputs); err != nil {
+		r.logger.Warn("record tool calls failed", slog.String("bot_id", req.BotID), slog.Any("error", err))
+	}
+}
+
+// withReasoningTrace returns a copy of meta with the reasoning text of msg
+// stored under "reasoning". meta is returned unchanged when msg carries no
+// reasoning.
+func withReasoningTrace(meta map[string]any, msg conversation.ModelMessage) map[string]any {
+	texts := make([]string, 0, 1)
+	for _, part := range msg.ContentParts() {
+		if !strings.EqualFold(strings.TrimSpace(part.Type), "reasoning") {
+			continue
 		}
+		if text := strings.TrimSpace(part.Text); text != "" {
+			texts = append(texts, text)
+		}
+	}
+	if len(texts) == 0 {
+		return meta
+	}
+	return withMetadata(meta, "reasoning", strings.Join(texts, "\n\n"))
+}
+
+// withMetadata returns a copy of meta with key set to value, leaving the
+// route metadata shared by the round untouched.
+func withMetadata(meta map[string]any, key string, value any) map[string]any {
+	out := make(map[string]any, len(meta)+1)
+	for k, v := range meta {
+		out[k] = v
 	}
+	out[key] = value
+	return out
 }
 
 // outboundAssetRefsToMessageRefs converts outbound asset refs from the streaming
@@ -192,7 +337,7 @@ func outboundAssetRefsToMessageRefs(refs []conversation.OutboundAssetRef) []mess
 		}
 		role := ref.Role
 		if strings.TrimSpace(role) == "" {
-			role = "attachment"
+			role = messagepkg.AssetRoleFromMetadata(ref.Metadata)
 		}
 		result = append(result, messagepkg.AssetRef{
 			ContentHash: contentHash,
@@ -258,7 +403,8 @@ func (r *Resolver) resolvePersistSenderIDs(ctx context.Context, req conversation
 	userID := strings.TrimSpace(req.UserID)
 
 	senderChannelIdentityID := ""
-	if r.isExistingChannelIdentityID(ctx, channelIdentityID) {
+	identity, identityFound := r.lookupChannelIdentity(ctx, channelIdentityID)
+	if identityFound {
 		senderChannelIdentityID = channelIdentityID
 	}
 
@@ -266,10 +412,8 @@ func (r *Resolver) resolvePersistSenderIDs(ctx context.Context, req conversation
 	if r.isExistingUserID(ctx, userID) {
 		senderUserID = userID
 	}
-	if senderUserID == "" && senderChannelIdentityID != "" {
-		if linked := r.linkedUserIDFromChannelIdentity(ctx, senderChannelIdentityID); linked != "" {
-			senderUserID = linked
-		}
+	if senderUserID == "" && identityFound && identity.LinkedUserID.Valid {
+		senderUserID = identity.LinkedUserID.String()
 	}
 	return senderChannelIdentityID, senderUserID
 }
@@ -0,0 +1,85 @@
+package flow
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/memohai/memoh/internal/conversation"
+)
+
+func TestStoreMessagesPersistsRoundInOneBatch(t *testing.T) {
+	svc := &fakeToolCallMessageService{}
+	resolver := &Resolver{messageService: svc, logger: slog.New(slog.DiscardHandler)}
+	req := conversation.ChatRequest{BotID: "bot-1", SessionID: "session-1", Query: "find the weather"}
+
+	round := toolCallRound()
+	round[1].Usage = json.RawMessage(`{"inputTokens":10,"outputTokens":4}`)
+	round[3].Usage = json.RawMessage(`{"inputTokens":20,"outputTokens":6}`)
+	resolver.storeMessages(context.Background(), req, round, "model-1", nil)
+
+	if len(svc.batches) != 1 || svc.batches[0] != 4 {
+		t.Fatalf("expected one batch of 4 messages, got %v", svc.batches)
+	}
+	wantRoles := []string{"user", "assistant", "tool", "assistant"}
+	for i, input := range svc.persisted {
+		if input.Role != wantRoles[i] {
+			t.Fatalf("message %d: expected role %q, got %q", i, wantRoles[i], input.Role)
+		}
+		if string(input.Usage) != string(round[i].Usage) {
+			t.Fatalf("message %d: expected usage %s, got %s", i, round[i].Usage, input.Usage)
+		}
+		if input.ModelID != "model-1" {
+			t.Fatalf("message %d: expected model-1, got %q", i, input.ModelID)
+		}
+	}
+	for _, call := range svc.toolCalls {
+		if call.MessageID != "msg-2" {
+			t.Fatalf("expected tool call linked to msg-2, got %+v", call)
+		}
+	}
+}
+
+func TestStoreMessagesRetriesRemainderWhenBatchFails(t *testing.T) {
+	svc := &fakeToolCallMessageService{batchErr: errors.New("db down"), batchStored: 1}
+	resolver := &Resolver{messageService: svc, logger: slog.New(slog.DiscardHandler)}
+	req := conversation.ChatRequest{BotID: "bot-1", SessionID: "session-1", Query: "find the weather"}
+
+	resolver.storeMessages(context.Background(), req, toolCallRound(), "", nil)
+
+	if len(svc.persisted) != 4 {
+		t.Fatalf("expected each message stored once, got %d", len(svc.persisted))
+	}
+	wantRoles := []string{"user", "assistant", "tool", "assistant"}
+	for i, input := range svc.persisted {
+		if input.Role != wantRoles[i] {
+			t.Fatalf("message %d: expected role %q, got %q", i, wantRoles[i], input.Role)
+		}
+	}
+	if len(svc.toolCalls) != 2 || svc.toolCalls[0].MessageID != "msg-2" {
+		t.Fatalf("expected tool calls linked to the retried assistant message, got %+v", svc.toolCalls)
+	}
+}
+
+func TestStoreMessagesRecordsModelVariant(t *testing.T) {
+	svc := &fakeToolCallMessageService{}
+	resolver := &Resolver{messageService: svc, logger: slog.New(slog.DiscardHandler)}
+	req := conversation.ChatRequest{BotID: "bot-1", SessionID: "session-1", Query: "find the weather", ModelVariant: "gpt-4o"}
+
+	resolver.storeMessages(context.Background(), req, toolCallRound(), "model-1", nil)
+
+	for i, input := range svc.persisted {
+		got, ok := input.Metadata["model_variant"]
+		if input.Role != "assistant" {
+			if ok {
+				t.Fatalf("message %d (%s): unexpected model_variant %v", i, input.Role, got)
+			}
+			continue
+		}
+		if got != "gpt-4o" {
+			t.Fatalf("message %d: model_variant = %v, want gpt-4o", i, got)
+		}
+	}
+}
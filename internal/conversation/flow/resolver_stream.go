@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log/slog"
 	"strings"
+	"time"
 
 	sdk "github.com/memohai/twilight-ai/sdk"
 
@@ -41,21 +42,39 @@ func (r *Resolver) StreamChat(ctx context.Context, req conversation.ChatRequest)
 			streamReq.RawQuery = strings.TrimSpace(streamReq.Query)
 		}
 		streamReq.Query = rc.query
+		streamReq.ModelVariant = rc.modelVariant
 
 		go r.maybeGenerateSessionTitle(context.WithoutCancel(ctx), streamReq, streamReq.Query)
 
 		cfg := rc.runConfig
 		cfg = r.prepareRunConfig(ctx, cfg)
 
+		runCtx, runCancel := context.WithCancel(ctx)
+		defer runCancel()
+
 		// Wrap with idle timeout: if no events arrive within the adaptive timeout, cancel the stream.
-		idleCtx, idleCancel := withIdleTimeout(ctx)
+		idleCtx, idleCancel := withIdleTimeout(runCtx)
 		defer idleCancel.Stop()
 
 		eventCh := r.agent.Stream(idleCtx, cfg)
 		stored := false
 		var toolCallCount int
+		toolTimer := newToolCallTimer()
+		toolRounds := &toolRoundLimiter{max: rc.maxToolRounds}
+		limitHit := false
+		quotaError := ""
 		for event := range eventCh {
 			idleCancel.Reset() // each event resets the idle timer
+			toolTimer.Observe(event)
+			if toolRounds.Observe(event) {
+				limitHit = true
+				runCancel()
+				// Drain until the agent sees the cancel and closes the
+				// channel, so it is not left blocked on a send.
+				for range eventCh {
+				}
+				break
+			}
 
 			// Track tool calls for adaptive idle timeout and progress events
 			if event.Type == agentpkg.EventToolCallStart {
@@ -70,6 +89,12 @@ func (r *Resolver) StreamChat(ctx context.Context, req conversation.ChatRequest)
 					slog.String("model_id", rc.model.ID),
 					slog.String("error", event.Error),
 				)
+				// Reported as a typed error once the stream ends so the
+				// caller can reply with a specific message.
+				if event.ErrorCode == agentpkg.ErrorCodeQuotaExhausted {
+					quotaError = event.Error
+					continue
+				}
 			}
 
 			data, err := json.Marshal(event)
@@ -77,7 +102,7 @@ func (r *Resolver) StreamChat(ctx context.Context, req conversation.ChatRequest)
 				continue
 			}
 			if !stored && event.IsTerminal() && len(event.Messages) > 0 {
-				if _, storeErr := r.tryStoreStream(ctx, streamReq, data, rc.model.ID, rc); storeErr != nil {
+				if _, storeErr := r.tryStoreStream(ctx, streamReq, data, rc.model.ID, rc, toolTimer.Durations()); storeErr != nil {
 					r.logger.Error("stream persist failed", slog.Any("error", storeErr))
 				} else {
 					stored = true
@@ -94,10 +119,31 @@ func (r *Resolver) StreamChat(ctx context.Context, req conversation.ChatRequest)
 		// storing results, persist a synthetic message so the user can see
 		// what happened and ask the bot to continue.
 		if !stored {
-			r.persistPartialResult(ctx, streamReq, rc, toolCallCount, idleCancel.DidFire())
+			r.persistPartialResult(ctx, streamReq, rc, toolCallCount, interruptReason(idleCancel.DidFire(), limitHit))
 		}
 
-		if idleCancel.DidFire() {
+		if quotaError != "" {
+			errCh <- fmt.Errorf("%w: %s", conversation.ErrProviderQuotaExhausted, quotaError)
+			return
+		}
+
+		if limitHit {
+			r.logger.Warn("agent stream aborted: tool call limit reached",
+				slog.String("bot_id", streamReq.BotID),
+				slog.String("chat_id", streamReq.ChatID),
+				slog.Int("max_tool_rounds", rc.maxToolRounds),
+			)
+			limitEvent := agentpkg.StreamEvent{
+				Type:  agentpkg.EventError,
+				Error: toolRounds.AbortMessage(),
+			}
+			if data, err := json.Marshal(limitEvent); err == nil {
+				select {
+				case chunkCh <- conversation.StreamChunk(data):
+				case <-ctx.Done():
+				}
+			}
+		} else if idleCancel.DidFire() {
 			r.logger.Warn("agent stream aborted: idle timeout (no events from provider)",
 				slog.String("bot_id", streamReq.BotID),
 				slog.String("chat_id", streamReq.ChatID),
@@ -143,6 +189,7 @@ func (r *Resolver) StreamChatWS(
 		req.RawQuery = strings.TrimSpace(req.Query)
 	}
 	req.Query = rc.query
+	req.ModelVariant = rc.modelVariant
 
 	go r.maybeGenerateSessionTitle(context.WithoutCancel(ctx), req, req.Query)
 
@@ -168,8 +215,17 @@ func (r *Resolver) StreamChatWS(
 	modelID := rc.model.ID
 	stored := false
 	var toolCallCount int
+	toolTimer := newToolCallTimer()
+	toolRounds := &toolRoundLimiter{max: rc.maxToolRounds}
+	limitHit := false
 	for event := range agentEventCh {
 		idleCancel.Reset() // each event resets the idle timer
+		toolTimer.Observe(event)
+		if toolRounds.Observe(event) {
+			limitHit = true
+			cancel()
+			break
+		}
 
 		// Track tool calls for adaptive idle timeout
 		if event.Type == agentpkg.EventToolCallStart {
@@ -192,7 +248,7 @@ func (r *Resolver) StreamChatWS(
 		}
 
 		if !stored && event.IsTerminal() && len(event.Messages) > 0 {
-			if _, storeErr := r.tryStoreStream(ctx, req, data, modelID, rc); storeErr != nil {
+			if _, storeErr := r.tryStoreStream(ctx, req, data, modelID, rc, toolTimer.Durations()); storeErr != nil {
 				r.logger.Error("ws persist failed", slog.Any("error", storeErr))
 			} else {
 				stored = true
@@ -208,10 +264,26 @@ func (r *Resolver) StreamChatWS(
 
 	// Intermediate persistence on abort/error
 	if !stored {
-		r.persistPartialResult(ctx, req, rc, toolCallCount, idleCancel.DidFire())
+		r.persistPartialResult(ctx, req, rc, toolCallCount, interruptReason(idleCancel.DidFire(), limitHit))
 	}
 
-	if idleCancel.DidFire() {
+	if limitHit {
+		r.logger.Warn("agent ws stream aborted: tool call limit reached",
+			slog.String("bot_id", req.BotID),
+			slog.String("chat_id", req.ChatID),
+			slog.Int("max_tool_rounds", rc.maxToolRounds),
+		)
+		limitEvent := agentpkg.StreamEvent{
+			Type:  agentpkg.EventError,
+			Error: toolRounds.AbortMessage(),
+		}
+		if data, err := json.Marshal(limitEvent); err == nil {
+			select {
+			case eventCh <- json.RawMessage(data):
+			case <-ctx.Done():
+			}
+		}
+	} else if idleCancel.DidFire() {
 		r.logger.Warn("agent ws stream aborted: idle timeout (no events from provider)",
 			slog.String("bot_id", req.BotID),
 			slog.String("chat_id", req.ChatID),
@@ -234,7 +306,7 @@ func (r *Resolver) StreamChatWS(
 	return nil
 }
 
-func (r *Resolver) tryStoreStream(ctx context.Context, req conversation.ChatRequest, data []byte, modelID string, rc resolvedContext) (bool, error) {
+func (r *Resolver) tryStoreStream(ctx context.Context, req conversation.ChatRequest, data []byte, modelID string, rc resolvedContext, toolDurations map[string]time.Duration) (bool, error) {
 	var envelope struct {
 		Type     string          `json:"type"`
 		Messages json.RawMessage `json:"messages"`
@@ -258,7 +330,7 @@ func (r *Resolver) tryStoreStream(ctx context.Context, req conversation.ChatRequ
 		roundMessages = interleaveInjectedMessages(roundMessages, *rc.injectedRecords)
 	}
 
-	if err := r.storeRound(ctx, req, roundMessages, modelID); err != nil {
+	if err := r.storeTimedRound(ctx, req, roundMessages, modelID, toolDurations); err != nil {
 		return false, err
 	}
 
@@ -269,15 +341,23 @@ func (r *Resolver) tryStoreStream(ctx context.Context, req conversation.ChatRequ
 	return true, nil
 }
 
+// interruptReason describes why a stream ended before storing its result.
+func interruptReason(wasIdleTimeout, toolLimitHit bool) string {
+	switch {
+	case toolLimitHit:
+		return "tool call limit reached"
+	case wasIdleTimeout:
+		return "provider idle timeout"
+	default:
+		return "provider error"
+	}
+}
+
 // persistPartialResult stores a synthetic assistant message when the agent
 // stream was interrupted (error, abort, idle timeout) after completing tool
 // calls but before producing a final response. This preserves intermediate
 // progress so the user can see what was accomplished and ask the bot to continue.
-func (r *Resolver) persistPartialResult(ctx context.Context, req conversation.ChatRequest, rc resolvedContext, toolCallCount int, wasIdleTimeout bool) {
-	reason := "provider error"
-	if wasIdleTimeout {
-		reason = "provider idle timeout"
-	}
+func (r *Resolver) persistPartialResult(ctx context.Context, req conversation.ChatRequest, rc resolvedContext, toolCallCount int, reason string) {
 	syntheticMsg := fmt.Sprintf("[Agent interrupted after %d tool calls: %s. Partial results saved — ask the bot to continue.]", toolCallCount, reason)
 
 	roundMessages := prependUserMessage(req.Query, []conversation.ModelMessage{
@@ -3,10 +3,15 @@ package flow
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"io"
 	"log/slog"
+	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 
@@ -114,6 +119,106 @@ func TestPrepareGatewayAttachments_PublicURLFromURLFieldIsNativePublic(t *testin
 	}
 }
 
+func TestPrepareGatewayAttachments_PassesWhitelistedMetadata(t *testing.T) {
+	resolver := &Resolver{logger: slog.Default()}
+	req := conversation.ChatRequest{
+		Attachments: []conversation.ChatAttachment{
+			{
+				Type: "file",
+				URL:  "https://example.com/report.pdf",
+				Metadata: map[string]any{
+					"page_count":  12,
+					"width":       800,
+					"storage_key": "bots/bot-1/report.pdf",
+					"platform_id": "abc",
+				},
+			},
+			{
+				Type:     "audio",
+				URL:      "https://example.com/voice.ogg",
+				Metadata: map[string]any{"storage_key": "bots/bot-1/voice.ogg"},
+			},
+		},
+	}
+
+	prepared := resolver.prepareGatewayAttachments(context.Background(), req)
+	if len(prepared) != 2 {
+		t.Fatalf("expected 2 attachments, got %d", len(prepared))
+	}
+	meta := prepared[0].Metadata
+	if len(meta) != 2 || meta["page_count"] != 12 || meta["width"] != 800 {
+		t.Fatalf("expected only whitelisted metadata, got %#v", meta)
+	}
+	if prepared[1].Metadata != nil {
+		t.Fatalf("expected metadata to be dropped, got %#v", prepared[1].Metadata)
+	}
+
+	raw, err := json.Marshal(prepared[0])
+	if err != nil {
+		t.Fatalf("marshal attachment: %v", err)
+	}
+	if strings.Contains(string(raw), "storage_key") || !strings.Contains(string(raw), `"page_count":12`) {
+		t.Fatalf("unexpected serialized metadata: %s", raw)
+	}
+}
+
+func TestPrepareGatewayAttachments_VerifiesPublicURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodHead {
+			t.Errorf("expected HEAD request, got %s", r.Method)
+		}
+		switch r.URL.Path {
+		case "/demo.png":
+			w.Header().Set("Content-Type", "image/png")
+		case "/page.png":
+			w.Header().Set("Content-Type", "text/html")
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	unreachable := httptest.NewServer(http.NotFoundHandler())
+	unreachableURL := unreachable.URL + "/demo.png"
+	unreachable.Close()
+
+	tests := []struct {
+		name       string
+		url        string
+		wantNative bool
+	}{
+		{name: "reachable image", url: server.URL + "/demo.png", wantNative: true},
+		{name: "not found", url: server.URL + "/missing.png"},
+		{name: "wrong content type", url: server.URL + "/page.png"},
+		{name: "unreachable host", url: unreachableURL},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolver := &Resolver{logger: slog.Default()}
+			resolver.SetPublicURLCheckClient(server.Client())
+			req := conversation.ChatRequest{
+				Attachments: []conversation.ChatAttachment{{Type: "image", URL: tt.url}},
+			}
+
+			prepared := resolver.prepareGatewayAttachments(context.Background(), req)
+			if len(prepared) != 1 {
+				t.Fatalf("expected 1 attachment, got %d", len(prepared))
+			}
+			if tt.wantNative {
+				if prepared[0].Transport != gatewayTransportPublicURL || prepared[0].Payload != tt.url {
+					t.Fatalf("expected public url transport, got %+v", prepared[0])
+				}
+				return
+			}
+			if prepared[0].Transport != "" || prepared[0].Payload != "" {
+				t.Fatalf("expected no native transport, got %+v", prepared[0])
+			}
+			if prepared[0].FallbackPath != "" || prepared[0].SourceURL != tt.url {
+				t.Fatalf("expected source url %q and no fallback path, got %+v", tt.url, prepared[0])
+			}
+		})
+	}
+}
+
 func TestRouteAndMergeAttachments_ImagePathOnlyFallsBackToFile(t *testing.T) {
 	resolver := &Resolver{logger: slog.Default()}
 	model := models.GetResponse{
@@ -213,6 +318,186 @@ func TestRouteAndMergeAttachments_DropsUnsupportedInlineWithoutFallbackPath(t *t
 	}
 }
 
+func TestRouteAndMergeAttachments_PreservesRequestOrder(t *testing.T) {
+	resolver := &Resolver{logger: slog.Default()}
+	model := models.GetResponse{
+		Model: models.Model{
+			Config: models.ModelConfig{
+				Compatibilities: []string{models.CompatVision},
+			},
+		},
+	}
+	req := conversation.ChatRequest{
+		Attachments: []conversation.ChatAttachment{
+			{Type: "file", Path: "/data/media/doc/first.pdf"},
+			{Type: "image", URL: "data:image/png;base64,AAAA"},
+			{Type: "video", Base64: "AAAA"},
+			{Type: "audio", Path: "/data/media/audio/third.wav"},
+			{Type: "image", URL: "https://example.com/fourth.png"},
+		},
+	}
+
+	merged := resolver.routeAndMergeAttachments(context.Background(), model, req)
+	// The video has no fallback path and no writer, so it is dropped in place.
+	want := []string{
+		"/data/media/doc/first.pdf",
+		"data:image/png;base64,AAAA",
+		"/data/media/audio/third.wav",
+		"https://example.com/fourth.png",
+	}
+	if len(merged) != len(want) {
+		t.Fatalf("expected %d attachments, got %d", len(want), len(merged))
+	}
+	for i, payload := range want {
+		item, ok := merged[i].(gatewayAttachment)
+		if !ok {
+			t.Fatalf("attachment %d: expected gatewayAttachment type", i)
+		}
+		if item.Payload != payload {
+			t.Fatalf("attachment %d: expected payload %q, got %q", i, payload, item.Payload)
+		}
+	}
+}
+
+type fakeAttachmentWriter struct {
+	err   error
+	botID string
+	path  string
+	data  []byte
+}
+
+func (f *fakeAttachmentWriter) WriteContainerFile(_ context.Context, botID, containerPath string, reader io.Reader) error {
+	if f.err != nil {
+		return f.err
+	}
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return err
+	}
+	f.botID, f.path, f.data = botID, containerPath, data
+	return nil
+}
+
+func TestRouteAndMergeAttachments_IngestsUnsupportedInlineIntoTemplatePath(t *testing.T) {
+	writer := &fakeAttachmentWriter{}
+	resolver := &Resolver{logger: slog.Default()}
+	resolver.SetAttachmentFallback(writer, "/data/uploads/{hash}{ext}")
+	model := models.GetResponse{Model: models.Model{Config: models.ModelConfig{Compatibilities: []string{}}}}
+	payload := []byte("video-bytes")
+	req := conversation.ChatRequest{
+		BotID: "bot-1",
+		Attachments: []conversation.ChatAttachment{
+			{
+				Type:   "video",
+				Name:   "clip.MP4",
+				Mime:   "video/mp4",
+				Base64: base64.StdEncoding.EncodeToString(payload),
+			},
+		},
+	}
+
+	merged := resolver.routeAndMergeAttachments(context.Background(), model, req)
+	if len(merged) != 1 {
+		t.Fatalf("expected ingested attachment to be kept, got %d", len(merged))
+	}
+	item, ok := merged[0].(gatewayAttachment)
+	if !ok {
+		t.Fatalf("expected gatewayAttachment type")
+	}
+	sum := sha256.Sum256(payload)
+	wantPath := "/data/uploads/" + hex.EncodeToString(sum[:]) + ".mp4"
+	if item.Type != "file" || item.Transport != gatewayTransportToolFileRef || item.Payload != wantPath {
+		t.Fatalf("expected file ref to %q, got %+v", wantPath, item)
+	}
+	if writer.botID != "bot-1" || writer.path != wantPath {
+		t.Fatalf("expected write to %q for bot-1, got %q for %q", wantPath, writer.path, writer.botID)
+	}
+	if !bytes.Equal(writer.data, payload) {
+		t.Fatalf("unexpected ingested bytes: %q", writer.data)
+	}
+}
+
+func TestRouteAndMergeAttachments_IngestsPersistedAssetWithDefaultTemplate(t *testing.T) {
+	writer := &fakeAttachmentWriter{}
+	resolver := &Resolver{
+		logger: slog.Default(),
+		assetLoader: &fakeGatewayAssetLoader{
+			openFn: func(_ context.Context, _, _ string) (io.ReadCloser, string, error) {
+				return io.NopCloser(strings.NewReader("%PDF-1.7")), "application/pdf", nil
+			},
+		},
+	}
+	resolver.SetAttachmentFallback(writer, "")
+	model := models.GetResponse{Model: models.Model{Config: models.ModelConfig{Compatibilities: []string{}}}}
+	req := conversation.ChatRequest{
+		BotID: "bot-1",
+		Attachments: []conversation.ChatAttachment{
+			{Type: "file", Mime: "application/pdf", ContentHash: "asset-3"},
+		},
+	}
+
+	merged := resolver.routeAndMergeAttachments(context.Background(), model, req)
+	if len(merged) != 1 {
+		t.Fatalf("expected ingested attachment to be kept, got %d", len(merged))
+	}
+	sum := sha256.Sum256([]byte("%PDF-1.7"))
+	wantPath := "/data/attachments/" + hex.EncodeToString(sum[:]) + ".pdf"
+	if item := merged[0].(gatewayAttachment); item.Payload != wantPath {
+		t.Fatalf("expected payload %q, got %q", wantPath, item.Payload)
+	}
+	if writer.path != wantPath {
+		t.Fatalf("expected write to %q, got %q", wantPath, writer.path)
+	}
+}
+
+func TestRouteAndMergeAttachments_IngestsUnverifiedURLFromSource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte("<html></html>"))
+	}))
+	defer server.Close()
+	writer := &fakeAttachmentWriter{}
+	resolver := &Resolver{logger: slog.Default()}
+	resolver.SetAttachmentFallback(writer, "/data/uploads/{hash}{ext}")
+	resolver.SetPublicURLCheckClient(server.Client())
+	model := models.GetResponse{Model: models.Model{Config: models.ModelConfig{Compatibilities: []string{models.CompatVision}}}}
+	req := conversation.ChatRequest{
+		BotID:       "bot-1",
+		Attachments: []conversation.ChatAttachment{{Type: "image", Name: "page.png", URL: server.URL + "/page.png"}},
+	}
+
+	merged := resolver.routeAndMergeAttachments(context.Background(), model, req)
+	if len(merged) != 1 {
+		t.Fatalf("expected ingested attachment to be kept, got %d", len(merged))
+	}
+	sum := sha256.Sum256([]byte("<html></html>"))
+	wantPath := "/data/uploads/" + hex.EncodeToString(sum[:]) + ".png"
+	if item := merged[0].(gatewayAttachment); item.Transport != gatewayTransportToolFileRef || item.Payload != wantPath {
+		t.Fatalf("expected file ref to %q, got %+v", wantPath, item)
+	}
+	if writer.path != wantPath || string(writer.data) != "<html></html>" {
+		t.Fatalf("expected fetched content at %q, got %q at %q", wantPath, writer.data, writer.path)
+	}
+}
+
+func TestRouteAndMergeAttachments_DropsAttachmentWhenIngestFails(t *testing.T) {
+	writer := &fakeAttachmentWriter{err: errors.New("container unavailable")}
+	resolver := &Resolver{logger: slog.Default()}
+	resolver.SetAttachmentFallback(writer, "/data/uploads/{hash}{ext}")
+	model := models.GetResponse{Model: models.Model{Config: models.ModelConfig{Compatibilities: []string{}}}}
+	req := conversation.ChatRequest{
+		BotID: "bot-1",
+		Attachments: []conversation.ChatAttachment{
+			{Type: "video", Base64: "AAAA"},
+		},
+	}
+
+	merged := resolver.routeAndMergeAttachments(context.Background(), model, req)
+	if len(merged) != 0 {
+		t.Fatalf("expected attachment to be dropped when ingest fails, got %d", len(merged))
+	}
+}
+
 func TestEncodeReaderAsDataURL_DetectsImageMime(t *testing.T) {
 	jpegBytes := []byte{
 		0xFF, 0xD8, 0xFF, 0xE0, 0x00, 0x10, 0x4A, 0x46,
@@ -266,6 +551,24 @@ func TestOutboundAssetRefsToMessageRefs(t *testing.T) {
 	}
 }
 
+func TestOutboundAssetRefsToMessageRefs_RoleFromMetadata(t *testing.T) {
+	t.Parallel()
+	refs := []conversation.OutboundAssetRef{
+		{ContentHash: "a1", Metadata: map[string]any{"asset_role": "chart"}},
+		{ContentHash: "a2", Role: "voice", Metadata: map[string]any{"asset_role": "chart"}},
+	}
+	result := outboundAssetRefsToMessageRefs(refs)
+	if len(result) != 2 {
+		t.Fatalf("expected 2 refs, got %d", len(result))
+	}
+	if result[0].Role != "chart" {
+		t.Fatalf("expected role from metadata, got %q", result[0].Role)
+	}
+	if result[1].Role != "voice" {
+		t.Fatalf("expected explicit role to win, got %q", result[1].Role)
+	}
+}
+
 func TestOutboundAssetRefsToMessageRefs_Empty(t *testing.T) {
 	t.Parallel()
 	result := outboundAssetRefsToMessageRefs(nil)
@@ -380,3 +683,39 @@ func TestNormalizeImagePartsToDataURL_LeavesStringImageUntouched(t *testing.T) {
 		t.Fatalf("expected content unchanged, got %s", string(normalized.Content))
 	}
 }
+
+func TestSanitizeMessagesFillsEmptyToolResults(t *testing.T) {
+	t.Parallel()
+
+	cleaned := sanitizeMessages([]conversation.ModelMessage{
+		{Role: "tool", ToolCallID: "call-1"},
+		{Role: "tool", ToolCallID: "call-2", Content: conversation.NewTextContent("  ")},
+		{Role: "tool", ToolCallID: "call-3", Content: conversation.NewTextContent("42")},
+		{Role: "tool", Content: json.RawMessage(`[` +
+			`{"type":"tool-result","toolCallId":"call-4","toolName":"lookup"},` +
+			`{"type":"tool-result","toolCallId":"call-5","toolName":"lookup","output":{"type":"text","value":""}},` +
+			`{"type":"tool-result","toolCallId":"call-6","toolName":"lookup","output":{"type":"text","value":"ok"}}]`)},
+	})
+	if len(cleaned) != 4 {
+		t.Fatalf("expected 4 messages, got %d", len(cleaned))
+	}
+	for i, want := range []string{emptyToolResultPlaceholder, emptyToolResultPlaceholder, "42"} {
+		if got := cleaned[i].TextContent(); got != want {
+			t.Fatalf("message %d content = %q, want %q", i, got, want)
+		}
+	}
+
+	var parts []struct {
+		Output struct {
+			Value string `json:"value"`
+		} `json:"output"`
+	}
+	if err := json.Unmarshal(cleaned[3].Content, &parts); err != nil {
+		t.Fatalf("unmarshal tool results: %v", err)
+	}
+	for i, want := range []string{emptyToolResultPlaceholder, emptyToolResultPlaceholder, "ok"} {
+		if parts[i].Output.Value != want {
+			t.Fatalf("tool result %d output = %q, want %q", i, parts[i].Output.Value, want)
+		}
+	}
+}
@@ -27,6 +27,7 @@ const (
 type SessionService interface {
 	Get(ctx context.Context, sessionID string) (session.Session, error)
 	UpdateTitle(ctx context.Context, sessionID, title string) (session.Session, error)
+	UpdateMetadata(ctx context.Context, sessionID string, metadata map[string]any) (session.Session, error)
 }
 
 // SetSessionService configures the session service used for auto title generation.
@@ -120,6 +121,7 @@ func (r *Resolver) generateTitle(ctx context.Context, userID string, model model
 		APIKey:         creds.APIKey,
 		CodexAccountID: creds.CodexAccountID,
 		BaseURL:        providers.ProviderConfigString(provider, "base_url"),
+		Headers:        providers.ProviderConfigHeaders(provider),
 	}
 	sdkModel := models.NewSDKChatModel(modelCfg)
 
@@ -0,0 +1,175 @@
+package flow
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	agentpkg "github.com/memohai/memoh/internal/agent"
+	"github.com/memohai/memoh/internal/conversation"
+)
+
+// extractedToolCall is a tool call found in a round, paired with its result
+// when the round contains one. MessageIndex points at the assistant message
+// that issued the call.
+type extractedToolCall struct {
+	MessageIndex int
+	ToolCallID   string
+	ToolName     string
+	Arguments    json.RawMessage
+	Result       json.RawMessage
+	IsError      bool
+}
+
+// toolContentPart holds the fields of SDK tool-call and tool-result parts.
+// Legacy tool results carry their payload in "output" instead of "result".
+type toolContentPart struct {
+	Type       string          `json:"type"`
+	ToolCallID string          `json:"toolCallId"`
+	ToolName   string          `json:"toolName"`
+	Input      json.RawMessage `json:"input"`
+	Result     json.RawMessage `json:"result"`
+	Output     json.RawMessage `json:"output"`
+	IsError    bool            `json:"isError"`
+}
+
+// extractToolCalls collects the tool calls issued by assistant messages and
+// attaches the results returned by later tool messages, matched by tool call
+// ID. Calls are returned in the order they were issued.
+func extractToolCalls(messages []conversation.ModelMessage) []extractedToolCall {
+	var calls []extractedToolCall
+	byID := map[string]int{}
+	addCall := func(call extractedToolCall) {
+		if strings.TrimSpace(call.ToolName) == "" {
+			return
+		}
+		if call.ToolCallID != "" {
+			if _, ok := byID[call.ToolCallID]; ok {
+				return
+			}
+			byID[call.ToolCallID] = len(calls)
+		}
+		calls = append(calls, call)
+	}
+	setResult := func(toolCallID string, result json.RawMessage, isError bool) {
+		idx, ok := byID[toolCallID]
+		if !ok || len(result) == 0 {
+			return
+		}
+		calls[idx].Result = result
+		calls[idx].IsError = isError
+	}
+
+	for i, msg := range messages {
+		switch strings.ToLower(strings.TrimSpace(msg.Role)) {
+		case "assistant":
+			for _, part := range toolContentParts(msg.Content) {
+				if part.Type != "tool-call" {
+					continue
+				}
+				addCall(extractedToolCall{
+					MessageIndex: i,
+					ToolCallID:   strings.TrimSpace(part.ToolCallID),
+					ToolName:     strings.TrimSpace(part.ToolName),
+					Arguments:    part.Input,
+				})
+			}
+			for _, tc := range msg.ToolCalls {
+				var args json.RawMessage
+				if tc.Function.Arguments != "" {
+					args = json.RawMessage(tc.Function.Arguments)
+				}
+				addCall(extractedToolCall{
+					MessageIndex: i,
+					ToolCallID:   strings.TrimSpace(tc.ID),
+					ToolName:     strings.TrimSpace(tc.Function.Name),
+					Arguments:    args,
+				})
+			}
+		case "tool":
+			parts := toolContentParts(msg.Content)
+			found := false
+			for _, part := range parts {
+				if part.Type != "tool-result" {
+					continue
+				}
+				found = true
+				result := part.Result
+				if len(result) == 0 {
+					result = part.Output
+				}
+				setResult(strings.TrimSpace(part.ToolCallID), result, part.IsError)
+			}
+			if !found && msg.ToolCallID != "" {
+				setResult(strings.TrimSpace(msg.ToolCallID), msg.Content, false)
+			}
+		}
+	}
+	return calls
+}
+
+// toolContentParts decodes array-shaped message content. String content and
+// malformed parts yield nothing.
+func toolContentParts(content json.RawMessage) []toolContentPart {
+	if len(content) == 0 {
+		return nil
+	}
+	var raw []json.RawMessage
+	if err := json.Unmarshal(content, &raw); err != nil {
+		return nil
+	}
+	parts := make([]toolContentPart, 0, len(raw))
+	for _, item := range raw {
+		var part toolContentPart
+		if err := json.Unmarshal(item, &part); err != nil {
+			continue
+		}
+		parts = append(parts, part)
+	}
+	return parts
+}
+
+// toolCallTimer measures tool call durations from stream start/end events.
+// It is owned by the goroutine consuming the stream.
+type toolCallTimer struct {
+	now       func() time.Time
+	started   map[string]time.Time
+	durations map[string]time.Duration
+}
+
+func newToolCallTimer() *toolCallTimer {
+	return &toolCallTimer{
+		now:       time.Now,
+		started:   map[string]time.Time{},
+		durations: map[string]time.Duration{},
+	}
+}
+
+// Observe records the start or end of a tool call. Other events are ignored.
+func (t *toolCallTimer) Observe(event agentpkg.StreamEvent) {
+	id := strings.TrimSpace(event.ToolCallID)
+	if t == nil || id == "" {
+		return
+	}
+	switch event.Type {
+	case agentpkg.EventToolCallStart:
+		t.started[id] = t.now()
+	case agentpkg.EventToolCallEnd:
+		if start, ok := t.started[id]; ok {
+			t.durations[id] = t.now().Sub(start)
+			delete(t.started, id)
+		}
+	}
+}
+
+// Durations returns the measured durations keyed by tool call ID.
+func (t *toolCallTimer) Durations() map[string]time.Duration {
+	if t == nil {
+		return nil
+	}
+	out := make(map[string]time.Duration, len(t.durations))
+	for id, d := range t.durations {
+		out[id] = d
+	}
+	return out
+}
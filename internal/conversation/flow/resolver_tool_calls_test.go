@@ -0,0 +1,178 @@
+package flow
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"testing"
+	"time"
+
+	agentpkg "github.com/memohai/memoh/internal/agent"
+	"github.com/memohai/memoh/internal/conversation"
+	messagepkg "github.com/memohai/memoh/internal/message"
+)
+
+// fakeToolCallMessageService records persisted messages and tool calls. Other
+// Service methods are not used by storeMessages.
+type fakeToolCallMessageService struct {
+	messagepkg.Service
+	persisted []messagepkg.PersistInput
+	toolCalls []messagepkg.ToolCallInput
+	batches   []int
+	// batchErr makes PersistBatch fail after storing batchStored inputs.
+	batchErr    error
+	batchStored int
+}
+
+func (f *fakeToolCallMessageService) Persist(_ context.Context, input messagepkg.PersistInput) (messagepkg.Message, error) {
+	f.persisted = append(f.persisted, input)
+	return messagepkg.Message{ID: fmt.Sprintf("msg-%d", len(f.persisted)), Role: input.Role}, nil
+}
+
+func (f *fakeToolCallMessageService) PersistBatch(ctx context.Context, inputs []messagepkg.PersistInput) ([]messagepkg.Message, error) {
+	f.batches = append(f.batches, len(inputs))
+	if f.batchErr != nil {
+		inputs = inputs[:f.batchStored]
+	}
+	results := make([]messagepkg.Message, 0, len(inputs))
+	for _, input := range inputs {
+		msg, _ := f.Persist(ctx, input)
+		results = append(results, msg)
+	}
+	return results, f.batchErr
+}
+
+func (f *fakeToolCallMessageService) RecordToolCalls(_ context.Context, calls []messagepkg.ToolCallInput) error {
+	f.toolCalls = append(f.toolCalls, calls...)
+	return nil
+}
+
+func toolCallRound() []conversation.ModelMessage {
+	return []conversation.ModelMessage{
+		{Role: "user", Content: conversation.NewTextContent("find the weather")},
+		{Role: "assistant", Content: json.RawMessage(`[
+			{"type":"text","text":"Searching."},
+			{"type":"tool-call","toolCallId":"call-1","toolName":"web_search","input":{"query":"weather"}},
+			{"type":"tool-call","toolCallId":"call-2","toolName":"read","input":{"path":"/tmp/x"}}
+		]`)},
+		{Role: "tool", Content: json.RawMessage(`[
+			{"type":"tool-result","toolCallId":"call-1","toolName":"web_search","result":{"hits":3}},
+			{"type":"tool-result","toolCallId":"call-2","toolName":"read","output":{"type":"text","value":"missing"},"isError":true}
+		]`)},
+		{Role: "assistant", Content: conversation.NewTextContent("It is sunny.")},
+	}
+}
+
+func TestExtractToolCallsPairsResults(t *testing.T) {
+	calls := extractToolCalls(toolCallRound())
+	if len(calls) != 2 {
+		t.Fatalf("expected 2 tool calls, got %d: %+v", len(calls), calls)
+	}
+	first := calls[0]
+	if first.MessageIndex != 1 || first.ToolCallID != "call-1" || first.ToolName != "web_search" {
+		t.Fatalf("unexpected first call: %+v", first)
+	}
+	if string(first.Arguments) != `{"query":"weather"}` || string(first.Result) != `{"hits":3}` || first.IsError {
+		t.Fatalf("unexpected first call payloads: args=%s result=%s error=%v", first.Arguments, first.Result, first.IsError)
+	}
+	second := calls[1]
+	if second.ToolName != "read" || !second.IsError || string(second.Result) != `{"type":"text","value":"missing"}` {
+		t.Fatalf("expected legacy output to be used as result, got %+v", second)
+	}
+}
+
+func TestExtractToolCallsFromOpenAIStyleToolCalls(t *testing.T) {
+	calls := extractToolCalls([]conversation.ModelMessage{
+		{Role: "assistant", ToolCalls: []conversation.ToolCall{{
+			ID:       "call-9",
+			Type:     "function",
+			Function: conversation.ToolCallFunction{Name: "exec", Arguments: `{"cmd":"ls"}`},
+		}}},
+		{Role: "tool", ToolCallID: "call-9", Content: conversation.NewTextContent("a.txt")},
+	})
+	if len(calls) != 1 {
+		t.Fatalf("expected 1 tool call, got %d", len(calls))
+	}
+	if calls[0].ToolName != "exec" || string(calls[0].Arguments) != `{"cmd":"ls"}` || string(calls[0].Result) != `"a.txt"` {
+		t.Fatalf("unexpected call: %+v", calls[0])
+	}
+}
+
+func TestStoreMessagesRecordsToolCalls(t *testing.T) {
+	svc := &fakeToolCallMessageService{}
+	resolver := &Resolver{messageService: svc, logger: slog.New(slog.DiscardHandler)}
+	req := conversation.ChatRequest{BotID: "bot-1", SessionID: "session-1", Query: "find the weather"}
+
+	resolver.storeMessages(context.Background(), req, toolCallRound(), "model-1", map[string]time.Duration{
+		"call-1": 1500 * time.Millisecond,
+	})
+
+	if len(svc.persisted) != 4 {
+		t.Fatalf("expected 4 persisted messages, got %d", len(svc.persisted))
+	}
+	if len(svc.toolCalls) != 2 {
+		t.Fatalf("expected 2 recorded tool calls, got %d", len(svc.toolCalls))
+	}
+	for _, call := range svc.toolCalls {
+		if call.MessageID != "msg-2" || call.BotID != "bot-1" || call.SessionID != "session-1" {
+			t.Fatalf("expected call linked to the issuing assistant message, got %+v", call)
+		}
+	}
+	if got := svc.toolCalls[0]; got.ToolName != "web_search" || got.Duration != 1500*time.Millisecond || string(got.Result) != `{"hits":3}` {
+		t.Fatalf("unexpected web_search call: %+v", got)
+	}
+	if got := svc.toolCalls[1]; got.ToolName != "read" || got.Duration != 0 || !got.IsError {
+		t.Fatalf("unexpected read call: %+v", got)
+	}
+}
+
+func TestStoreMessagesWithoutToolCallsRecordsNothing(t *testing.T) {
+	svc := &fakeToolCallMessageService{}
+	resolver := &Resolver{messageService: svc, logger: slog.New(slog.DiscardHandler)}
+	req := conversation.ChatRequest{BotID: "bot-1", Query: "hi"}
+
+	resolver.storeMessages(context.Background(), req, []conversation.ModelMessage{
+		{Role: "user", Content: conversation.NewTextContent("hi")},
+		{Role: "assistant", Content: conversation.NewTextContent("hello")},
+	}, "", nil)
+
+	if len(svc.toolCalls) != 0 {
+		t.Fatalf("expected no tool calls, got %+v", svc.toolCalls)
+	}
+}
+
+func TestToolCallTimerMeasuresStartToEnd(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	timer := newToolCallTimer()
+	timer.now = func() time.Time { return now }
+
+	timer.Observe(agentpkg.StreamEvent{Type: agentpkg.EventToolCallStart, ToolCallID: "call-1"})
+	now = now.Add(2 * time.Second)
+	timer.Observe(agentpkg.StreamEvent{Type: agentpkg.EventToolCallEnd, ToolCallID: "call-1"})
+	timer.Observe(agentpkg.StreamEvent{Type: agentpkg.EventToolCallEnd, ToolCallID: "call-unknown"})
+
+	durations := timer.Durations()
+	if len(durations) != 1 || durations["call-1"] != 2*time.Second {
+		t.Fatalf("unexpected durations: %v", durations)
+	}
+}
+
+func TestStoreMessagesReportsAssistantOutputIDs(t *testing.T) {
+	svc := &fakeToolCallMessageService{}
+	resolver := &Resolver{messageService: svc, logger: slog.New(slog.DiscardHandler)}
+	var got []string
+	req := conversation.ChatRequest{
+		BotID:                   "bot-1",
+		SessionID:               "session-1",
+		Query:                   "find the weather",
+		AssistantOutputRecorder: func(ids []string) { got = ids },
+	}
+
+	resolver.storeMessages(context.Background(), req, toolCallRound(), "model-1", nil)
+
+	// The tool-calling assistant message is not a reply; only the final one is.
+	if len(got) != 1 || got[0] != "msg-4" {
+		t.Fatalf("expected output IDs [msg-4], got %v", got)
+	}
+}
@@ -12,6 +12,7 @@ import (
 	sdk "github.com/memohai/twilight-ai/sdk"
 
 	agentpkg "github.com/memohai/memoh/internal/agent"
+	"github.com/memohai/memoh/internal/channel"
 	"github.com/memohai/memoh/internal/channel/route"
 	"github.com/memohai/memoh/internal/conversation"
 	"github.com/memohai/memoh/internal/heartbeat"
@@ -51,6 +52,7 @@ func (r *Resolver) TriggerSchedule(ctx context.Context, botID string, payload sc
 	if err != nil {
 		return schedule.TriggerResult{}, err
 	}
+	req.ModelVariant = rc.modelVariant
 
 	cfg := rc.runConfig
 	cfg.SessionType = "schedule"
@@ -109,6 +111,7 @@ func (r *Resolver) TriggerHeartbeat(ctx context.Context, botID string, payload h
 	if err != nil {
 		return heartbeat.TriggerResult{}, err
 	}
+	req.ModelVariant = rc.modelVariant
 
 	cfg := rc.runConfig
 	cfg.SessionType = "heartbeat"
@@ -136,13 +139,17 @@ func (r *Resolver) TriggerHeartbeat(ctx context.Context, botID string, payload h
 		return heartbeat.TriggerResult{}, err
 	}
 
+	outputMessages := sdkMessagesToModelMessages(result.Messages)
 	status := "alert"
 	text := strings.TrimSpace(result.Text)
-	if isHeartbeatOK(text) {
+	reply := finalAssistantReplyText(outputMessages)
+	if isHeartbeatOK(text) || isHeartbeatOK(reply) {
 		status = "ok"
 	}
+	if text == "" {
+		text = reply
+	}
 
-	outputMessages := sdkMessagesToModelMessages(result.Messages)
 	roundMessages := prependUserMessage(heartbeatPrompt, outputMessages)
 	_ = r.storeRound(ctx, req, roundMessages, rc.model.ID)
 
@@ -162,6 +169,53 @@ func isHeartbeatOK(text string) bool {
 	return strings.HasPrefix(t, "HEARTBEAT_OK") || strings.HasSuffix(t, "HEARTBEAT_OK") || t == "HEARTBEAT_OK"
 }
 
+// heartbeatContentPart covers the text-bearing part shapes providers return
+// for assistant content, including reasoning parts that must be skipped.
+type heartbeatContentPart struct {
+	Type    string `json:"type"`
+	Text    string `json:"text"`
+	Thought bool   `json:"thought"`
+}
+
+// finalAssistantReplyText returns the visible text of the last assistant
+// message that has any. Reasoning models may leave the generated text empty
+// and put the reply in message content next to reasoning parts (OpenAI
+// "reasoning", Anthropic "thinking", Google parts flagged "thought").
+func finalAssistantReplyText(messages []conversation.ModelMessage) string {
+	for i := len(messages) - 1; i >= 0; i-- {
+		msg := messages[i]
+		if msg.Role != "assistant" || len(msg.Content) == 0 {
+			continue
+		}
+		var plain string
+		if err := json.Unmarshal(msg.Content, &plain); err == nil {
+			if text := strings.TrimSpace(plain); text != "" {
+				return text
+			}
+			continue
+		}
+		var parts []heartbeatContentPart
+		if err := json.Unmarshal(msg.Content, &parts); err != nil {
+			continue
+		}
+		texts := make([]string, 0, len(parts))
+		for _, part := range parts {
+			switch part.Type {
+			case "reasoning", "thinking", "redacted_thinking":
+				continue
+			}
+			if part.Thought || strings.TrimSpace(part.Text) == "" {
+				continue
+			}
+			texts = append(texts, strings.TrimSpace(part.Text))
+		}
+		if len(texts) > 0 {
+			return strings.Join(texts, "\n")
+		}
+	}
+	return ""
+}
+
 type backgroundDeliveryContext struct {
 	routeID     string
 	channelType string
@@ -295,6 +349,7 @@ func (r *Resolver) deliverBackgroundNotifications(ctx context.Context, botID, se
 	if err != nil {
 		return fmt.Errorf("resolve background delivery: %w", err)
 	}
+	req.ModelVariant = rc.modelVariant
 
 	cfg := rc.runConfig
 	// Inject drained notifications so the first LLM call sees them.
@@ -327,7 +382,16 @@ func (r *Resolver) deliverBackgroundNotifications(ctx context.Context, botID, se
 	// Auto-deliver the agent's text response to the user through the normal
 	// outbound path, not through a special "send" tool call.
 	if text := strings.TrimSpace(result.Text); text != "" && r.outboundFn != nil {
-		if err := r.outboundFn(ctx, botID, delivery.channelType, delivery.replyTarget, text); err != nil {
+		err := r.outboundFn(ctx, botID, delivery.channelType, delivery.replyTarget, text)
+		switch {
+		case errors.Is(err, channel.ErrOutboundQueued):
+			r.logger.Info("background notification: outbound delivery queued for retry",
+				slog.String("bot_id", botID),
+				slog.String("platform", delivery.channelType),
+				slog.String("reply_target", delivery.replyTarget),
+				slog.Any("error", err),
+			)
+		case err != nil:
 			r.logger.Warn("background notification: outbound delivery failed",
 				slog.String("bot_id", botID),
 				slog.String("platform", delivery.channelType),
@@ -9,6 +9,7 @@ import (
 
 	"github.com/memohai/memoh/internal/agent/background"
 	"github.com/memohai/memoh/internal/channel/route"
+	"github.com/memohai/memoh/internal/conversation"
 	"github.com/memohai/memoh/internal/session"
 )
 
@@ -27,6 +28,10 @@ func (*fakeBackgroundSessionService) UpdateTitle(context.Context, string, string
 	return session.Session{}, errors.New("unexpected UpdateTitle call")
 }
 
+func (*fakeBackgroundSessionService) UpdateMetadata(context.Context, string, map[string]any) (session.Session, error) {
+	return session.Session{}, errors.New("unexpected UpdateMetadata call")
+}
+
 type fakeBackgroundRouteService struct {
 	getByIDFn func(ctx context.Context, routeID string) (route.Route, error)
 }
@@ -231,3 +236,62 @@ func TestSessionTurnExit_TriggersPendingBackgroundNotifications(t *testing.T) {
 		time.Sleep(10 * time.Millisecond)
 	}
 }
+
+func TestFinalAssistantReplyTextSkipsProviderReasoning(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name:    "openai reasoning part",
+			content: `[{"type":"reasoning","text":"checking tasks"},{"type":"text","text":"HEARTBEAT_OK"}]`,
+			want:    "HEARTBEAT_OK",
+		},
+		{
+			name:    "anthropic thinking block",
+			content: `[{"type":"thinking","thinking":"nothing due","signature":"sig"},{"type":"redacted_thinking","data":"x"},{"type":"text","text":"HEARTBEAT_OK"}]`,
+			want:    "HEARTBEAT_OK",
+		},
+		{
+			name:    "google thought part",
+			content: `[{"text":"HEARTBEAT_OK seems right","thought":true},{"text":"Disk is almost full."}]`,
+			want:    "Disk is almost full.",
+		},
+		{
+			name:    "plain string",
+			content: `"  HEARTBEAT_OK  "`,
+			want:    "HEARTBEAT_OK",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			messages := []conversation.ModelMessage{
+				{Role: "user", Content: []byte(`"heartbeat"`)},
+				{Role: "assistant", Content: []byte(tc.content)},
+				{Role: "tool", Content: []byte(`"ok"`)},
+			}
+			if got := finalAssistantReplyText(messages); got != tc.want {
+				t.Fatalf("finalAssistantReplyText = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestFinalAssistantReplyTextUsesLastNonEmptyAssistantMessage(t *testing.T) {
+	t.Parallel()
+
+	messages := []conversation.ModelMessage{
+		{Role: "assistant", Content: []byte(`[{"type":"text","text":"HEARTBEAT_OK"}]`)},
+		{Role: "assistant", Content: []byte(`[{"type":"reasoning","text":"done"}]`)},
+	}
+	if got := finalAssistantReplyText(messages); got != "HEARTBEAT_OK" {
+		t.Fatalf("finalAssistantReplyText = %q, want HEARTBEAT_OK", got)
+	}
+	if got := finalAssistantReplyText(nil); got != "" {
+		t.Fatalf("finalAssistantReplyText(nil) = %q, want empty", got)
+	}
+}
@@ -1,8 +1,12 @@
 package flow
 
 import (
+	"encoding/json"
+	"fmt"
+	"strings"
 	"testing"
 
+	"github.com/memohai/memoh/internal/bots"
 	"github.com/memohai/memoh/internal/conversation"
 )
 
@@ -170,3 +174,279 @@ func TestTrimMessagesByTokens_EstimatesFallback(t *testing.T) {
 		t.Fatalf("expected [system notice, assistant message], got %d messages: %+v", len(trimmed), trimmed)
 	}
 }
+
+func TestHistoryBudget(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		budget  int
+		reserve int
+		want    int
+	}{
+		{name: "no budget disables trimming", budget: 0, reserve: 8000, want: 0},
+		{name: "default reserve", budget: 32000, reserve: 0, want: 32000 - defaultSystemPromptReserve},
+		{name: "negative reserve uses default", budget: 32000, reserve: -1, want: 32000 - defaultSystemPromptReserve},
+		{name: "small configured reserve", budget: 32000, reserve: 1000, want: 31000},
+		{name: "large configured reserve", budget: 32000, reserve: 20000, want: 12000},
+		{name: "reserve clamped to history floor", budget: 32000, reserve: 30000, want: 8000},
+		{name: "configured reserve exceeding budget", budget: 2000, reserve: 4096, want: 500},
+		{name: "default reserve is not floored", budget: 5000, reserve: 0, want: 5000 - defaultSystemPromptReserve},
+		{name: "default reserve exceeding budget", budget: 2000, reserve: 0, want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := historyBudget(tt.budget, tt.reserve); got != tt.want {
+				t.Fatalf("historyBudget(%d, %d) = %d, want %d", tt.budget, tt.reserve, got, tt.want)
+			}
+		})
+	}
+}
+
+// usagelessHistory returns n alternating user/assistant messages without
+// usage data.
+func usagelessHistory(n int) []messageWithUsage {
+	messages := make([]messageWithUsage, 0, n)
+	for i := range n {
+		role := "user"
+		if i%2 == 1 {
+			role = "assistant"
+		}
+		messages = append(messages, messageWithUsage{
+			Message: conversation.ModelMessage{Role: role, Content: conversation.NewTextContent(fmt.Sprintf("message %d", i))},
+		})
+	}
+	return messages
+}
+
+func TestTrimMessages_CountCapAppliesWithoutTokenBudget(t *testing.T) {
+	t.Parallel()
+
+	maxTokens, maxMessages := historyTrimLimits(0, bots.HistoryTrimFeature{Strategy: bots.HistoryTrimCount, MaxMessages: 4})
+	trimmed, _ := trimMessages(nil, usagelessHistory(10), maxTokens, maxMessages)
+
+	if len(trimmed) != 5 || trimmed[0].Role != "system" {
+		t.Fatalf("expected system notice plus 4 messages, got %d: %+v", len(trimmed), trimmed)
+	}
+	if got := trimmed[1].TextContent(); got != "message 6" {
+		t.Fatalf("expected oldest kept message to be message 6, got %q", got)
+	}
+}
+
+func TestTrimMessages_CountCapSkipsOrphanTool(t *testing.T) {
+	t.Parallel()
+
+	messages := usagelessHistory(3)
+	messages = append(messages,
+		messageWithUsage{Message: conversation.ModelMessage{Role: "tool", ToolCallID: "call-1", Content: conversation.NewTextContent("result")}},
+		messageWithUsage{Message: conversation.ModelMessage{Role: "assistant", Content: conversation.NewTextContent("done")}},
+	)
+	trimmed, _ := trimMessages(nil, messages, 0, 2)
+	if len(trimmed) != 2 || trimmed[0].Role != "system" || trimmed[1].TextContent() != "done" {
+		t.Fatalf("expected the leading tool message to be dropped, got %+v", trimmed)
+	}
+}
+
+func TestTrimMessages_CombinedAppliesTheTighterLimit(t *testing.T) {
+	t.Parallel()
+
+	trim := bots.HistoryTrimFeature{Strategy: bots.HistoryTrimCombined, MaxMessages: 6}
+
+	// A generous token budget leaves the count cap in charge.
+	maxTokens, maxMessages := historyTrimLimits(100000, trim)
+	trimmed, _ := trimMessages(nil, usagelessHistory(10), maxTokens, maxMessages)
+	if len(trimmed) != 7 {
+		t.Fatalf("expected system notice plus 6 messages, got %d", len(trimmed))
+	}
+
+	// A tight token budget cuts deeper than the count cap: each message is
+	// about 2 estimated tokens.
+	maxTokens, maxMessages = historyTrimLimits(5, trim)
+	trimmed, _ = trimMessages(nil, usagelessHistory(10), maxTokens, maxMessages)
+	if len(trimmed) != 3 {
+		t.Fatalf("expected system notice plus 2 messages, got %d: %+v", len(trimmed), trimmed)
+	}
+}
+
+func TestTrimMessages_TokenStrategyIgnoresMessageCap(t *testing.T) {
+	t.Parallel()
+
+	maxTokens, maxMessages := historyTrimLimits(0, bots.HistoryTrimFeature{MaxMessages: 4})
+	trimmed, _ := trimMessages(nil, usagelessHistory(10), maxTokens, maxMessages)
+	if len(trimmed) != 10 {
+		t.Fatalf("expected untrimmed history, got %d messages", len(trimmed))
+	}
+}
+
+func TestTrimMessages_DropsToolCallWhoseResultIsMissing(t *testing.T) {
+	t.Parallel()
+
+	messages := []messageWithUsage{
+		{Message: conversation.ModelMessage{Role: "user", Content: conversation.NewTextContent("first")}},
+		{Message: conversation.ModelMessage{
+			Role:    "assistant",
+			Content: json.RawMessage(`[{"type":"tool-call","toolCallId":"call-1","toolName":"lookup","input":{}}]`),
+		}},
+		{Message: conversation.ModelMessage{
+			Role:    "tool",
+			Content: json.RawMessage(`[{"type":"tool-result","toolCallId":"call-1","toolName":"lookup","output":{"type":"text","value":"ok"}}]`),
+		}},
+		{Message: conversation.ModelMessage{Role: "user", Content: conversation.NewTextContent("second")}},
+		{Message: conversation.ModelMessage{
+			Role: "assistant",
+			Content: json.RawMessage(`[{"type":"text","text":"checking"},` +
+				`{"type":"tool-call","toolCallId":"call-2","toolName":"lookup","input":{}}]`),
+		}},
+		{Message: conversation.ModelMessage{
+			Role: "assistant",
+			ToolCalls: []conversation.ToolCall{{
+				ID:       "call-3",
+				Type:     "function",
+				Function: conversation.ToolCallFunction{Name: "calc", Arguments: `{}`},
+			}},
+		}},
+	}
+
+	// The cap cuts between call-1 and its result, so the result is skipped as
+	// a leading orphan; call-2 and call-3 were never answered.
+	trimmed, _ := trimMessages(nil, messages, 0, 4)
+	var roles []string
+	for _, m := range trimmed {
+		roles = append(roles, m.Role)
+	}
+	if got := strings.Join(roles, ","); got != "system,user,assistant" {
+		t.Fatalf("roles = %s, want system,user,assistant", got)
+	}
+	last := trimmed[len(trimmed)-1]
+	if strings.Contains(string(last.Content), "call-2") {
+		t.Fatalf("expected unanswered tool call to be removed, got %s", last.Content)
+	}
+	if last.TextContent() != "checking" {
+		t.Fatalf("expected assistant text to survive, got %q", last.TextContent())
+	}
+}
+
+func TestTrimMessages_DropsToolResultWhoseCallWasTrimmed(t *testing.T) {
+	t.Parallel()
+
+	messages := []messageWithUsage{
+		{Message: conversation.ModelMessage{
+			Role: "assistant",
+			ToolCalls: []conversation.ToolCall{{
+				ID:       "call-0",
+				Type:     "function",
+				Function: conversation.ToolCallFunction{Name: "calc", Arguments: `{}`},
+			}},
+		}},
+		{Message: conversation.ModelMessage{Role: "user", Content: conversation.NewTextContent("go on")}},
+		{Message: conversation.ModelMessage{
+			Role:    "assistant",
+			Content: json.RawMessage(`[{"type":"tool-call","toolCallId":"call-1","toolName":"lookup","input":{}}]`),
+		}},
+		{Message: conversation.ModelMessage{
+			Role: "tool",
+			Content: json.RawMessage(`[{"type":"tool-result","toolCallId":"call-0","toolName":"calc","output":{"type":"text","value":"1"}},` +
+				`{"type":"tool-result","toolCallId":"call-1","toolName":"lookup","output":{"type":"text","value":"ok"}}]`),
+		}},
+		{Message: conversation.ModelMessage{Role: "tool", ToolCallID: "call-0", Content: conversation.NewTextContent("1")}},
+		{Message: conversation.ModelMessage{Role: "assistant", Content: conversation.NewTextContent("done")}},
+	}
+
+	trimmed, _ := trimMessages(nil, messages, 0, 5)
+	var roles []string
+	for _, m := range trimmed {
+		roles = append(roles, m.Role)
+	}
+	if got := strings.Join(roles, ","); got != "system,user,assistant,tool,assistant" {
+		t.Fatalf("roles = %s, want system,user,assistant,tool,assistant", got)
+	}
+	result := string(trimmed[3].Content)
+	if strings.Contains(result, "call-0") || !strings.Contains(result, "call-1") {
+		t.Fatalf("expected only the call-1 result to remain, got %s", result)
+	}
+}
+
+func TestTrimMessages_KeepsPairedToolMessagesUntouched(t *testing.T) {
+	t.Parallel()
+
+	messages := []messageWithUsage{
+		{Message: conversation.ModelMessage{Role: "user", Content: conversation.NewTextContent("hi")}},
+		{Message: conversation.ModelMessage{
+			Role: "assistant",
+			ToolCalls: []conversation.ToolCall{{
+				ID:       "call-1",
+				Type:     "function",
+				Function: conversation.ToolCallFunction{Name: "calc", Arguments: `{}`},
+			}},
+		}},
+		{Message: conversation.ModelMessage{Role: "tool", ToolCallID: "call-1", Content: conversation.NewTextContent("2")}},
+		{Message: conversation.ModelMessage{Role: "assistant", Content: conversation.NewTextContent("done")}},
+	}
+
+	trimmed, _ := trimMessages(nil, messages, 0, 0)
+	if len(trimmed) != len(messages) {
+		t.Fatalf("kept %d messages, want %d", len(trimmed), len(messages))
+	}
+	if len(trimmed[1].ToolCalls) != 1 || trimmed[2].ToolCallID != "call-1" {
+		t.Fatalf("expected tool call pair to be kept, got %+v / %+v", trimmed[1], trimmed[2])
+	}
+}
+
+func systemPinHistory() []messageWithUsage {
+	return []messageWithUsage{
+		{Message: conversation.ModelMessage{Role: "system", Content: conversation.NewTextContent("old instructions")}},
+		{Message: conversation.ModelMessage{Role: "user", Content: conversation.NewTextContent("one")}},
+		{Message: conversation.ModelMessage{Role: "assistant", Content: conversation.NewTextContent("two")}},
+		{Message: conversation.ModelMessage{Role: "developer", Content: conversation.NewTextContent("new instructions")}},
+		{Message: conversation.ModelMessage{Role: "user", Content: conversation.NewTextContent("three")}},
+		{Message: conversation.ModelMessage{Role: "assistant", Content: conversation.NewTextContent("four")}},
+	}
+}
+
+func TestTrimHistory_PinsMostRecentSystemMessage(t *testing.T) {
+	t.Parallel()
+
+	trimmed, _ := trimHistory(nil, systemPinHistory(), 0, 3, "")
+	var texts []string
+	for _, m := range trimmed {
+		texts = append(texts, m.Role+":"+m.TextContent())
+	}
+	got := strings.Join(texts, "|")
+	if !strings.HasPrefix(got, "developer:new instructions|system:") {
+		t.Fatalf("expected the newest instruction message to be pinned ahead of the trim notice, got %s", got)
+	}
+	if !strings.HasSuffix(got, "|user:three|assistant:four") {
+		t.Fatalf("expected the newest turns to be kept, got %s", got)
+	}
+	if strings.Contains(got, "old instructions") {
+		t.Fatalf("expected stale system message to be excluded, got %s", got)
+	}
+}
+
+func TestTrimHistory_PinnedMessageSurvivesUntrimmedHistory(t *testing.T) {
+	t.Parallel()
+
+	trimmed, _ := trimHistory(nil, systemPinHistory(), 0, 0, bots.HistorySystemPin)
+	var roles []string
+	for _, m := range trimmed {
+		roles = append(roles, m.Role)
+	}
+	if got := strings.Join(roles, ","); got != "developer,user,assistant,user,assistant" {
+		t.Fatalf("roles = %s, want developer,user,assistant,user,assistant", got)
+	}
+}
+
+func TestTrimHistory_ExcludesSystemMessages(t *testing.T) {
+	t.Parallel()
+
+	trimmed, _ := trimHistory(nil, systemPinHistory(), 0, 0, bots.HistorySystemExclude)
+	for _, m := range trimmed {
+		if m.Role == "system" || m.Role == "developer" {
+			t.Fatalf("expected no stored system messages, got %s: %q", m.Role, m.TextContent())
+		}
+	}
+	if len(trimmed) != 4 {
+		t.Fatalf("kept %d messages, want 4", len(trimmed))
+	}
+}
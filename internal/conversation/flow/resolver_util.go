@@ -27,11 +27,77 @@ func sanitizeMessages(messages []conversation.ModelMessage) []conversation.Model
 		if !msg.HasContent() && strings.TrimSpace(msg.ToolCallID) == "" {
 			continue
 		}
-		cleaned = append(cleaned, msg)
+		cleaned = append(cleaned, fillEmptyToolResult(msg))
 	}
 	return cleaned
 }
 
+// emptyToolResultPlaceholder stands in for a tool result with no output.
+// Some providers reject tool messages with empty content.
+const emptyToolResultPlaceholder = "{}"
+
+// fillEmptyToolResult gives a tool message with empty content, or
+// tool-result parts without output, the placeholder result so the call stays
+// answered.
+func fillEmptyToolResult(msg conversation.ModelMessage) conversation.ModelMessage {
+	if !strings.EqualFold(strings.TrimSpace(msg.Role), "tool") {
+		return msg
+	}
+	var parts []map[string]json.RawMessage
+	if err := json.Unmarshal(msg.Content, &parts); err == nil && len(parts) > 0 {
+		changed := false
+		for _, part := range parts {
+			var partType string
+			_ = json.Unmarshal(part["type"], &partType)
+			if partType != "tool-result" || !emptyToolOutput(part["output"]) || !emptyJSON(part["result"]) {
+				continue
+			}
+			output, err := json.Marshal(map[string]string{"type": "text", "value": emptyToolResultPlaceholder})
+			if err != nil {
+				return msg
+			}
+			part["output"] = output
+			changed = true
+		}
+		if changed {
+			if content, err := json.Marshal(parts); err == nil {
+				msg.Content = content
+			}
+		}
+		return msg
+	}
+	if strings.TrimSpace(msg.ToolCallID) != "" && strings.TrimSpace(msg.TextContent()) == "" && len(msg.ContentParts()) == 0 {
+		msg.Content = conversation.NewTextContent(emptyToolResultPlaceholder)
+	}
+	return msg
+}
+
+// emptyToolOutput reports whether a tool-result output is missing or is a
+// text output with no text.
+func emptyToolOutput(raw json.RawMessage) bool {
+	if emptyJSON(raw) {
+		return true
+	}
+	var output struct {
+		Type  string          `json:"type"`
+		Value json.RawMessage `json:"value"`
+	}
+	if err := json.Unmarshal(raw, &output); err != nil {
+		return false
+	}
+	return output.Type == "text" && emptyJSON(output.Value)
+}
+
+// emptyJSON reports whether raw is missing, null, an empty string, an empty
+// object or an empty array.
+func emptyJSON(raw json.RawMessage) bool {
+	switch strings.TrimSpace(string(raw)) {
+	case "", "null", `""`, "{}", "[]":
+		return true
+	}
+	return false
+}
+
 func normalizeImagePartsToDataURL(msg conversation.ModelMessage) (conversation.ModelMessage, bool) {
 	if len(msg.Content) == 0 {
 		return msg, false
@@ -166,3 +232,13 @@ func parseResolverUUID(id string) (pgtype.UUID, error) {
 	}
 	return db.ParseUUID(id)
 }
+
+// coalescePositiveInt returns the first positive value, or 0 if none is.
+func coalescePositiveInt(values ...int) int {
+	for _, v := range values {
+		if v > 0 {
+			return v
+		}
+	}
+	return 0
+}
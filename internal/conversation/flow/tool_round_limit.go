@@ -0,0 +1,28 @@
+package flow
+
+import (
+	"fmt"
+
+	agentpkg "github.com/memohai/memoh/internal/agent"
+)
+
+// toolRoundLimiter enforces a bot's cap on tool calls per turn by counting
+// tool_call_end events. A zero max disables the cap.
+type toolRoundLimiter struct {
+	max    int
+	rounds int
+}
+
+// Observe counts event and reports whether the turn has exceeded the cap.
+func (l *toolRoundLimiter) Observe(event agentpkg.StreamEvent) bool {
+	if l == nil || l.max <= 0 || event.Type != agentpkg.EventToolCallEnd {
+		return false
+	}
+	l.rounds++
+	return l.rounds > l.max
+}
+
+// AbortMessage explains why the turn was stopped.
+func (l *toolRoundLimiter) AbortMessage() string {
+	return fmt.Sprintf("tool call limit reached (%d per turn), stream aborted", l.max)
+}
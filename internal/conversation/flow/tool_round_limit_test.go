@@ -0,0 +1,56 @@
+package flow
+
+import (
+	"strings"
+	"testing"
+
+	agentpkg "github.com/memohai/memoh/internal/agent"
+)
+
+func toolRoundEvents(rounds int) []agentpkg.StreamEvent {
+	events := make([]agentpkg.StreamEvent, 0, rounds*3)
+	for i := 0; i < rounds; i++ {
+		events = append(events,
+			agentpkg.StreamEvent{Type: agentpkg.EventToolCallStart, ToolName: "exec"},
+			agentpkg.StreamEvent{Type: agentpkg.EventToolCallEnd, ToolName: "exec"},
+			agentpkg.StreamEvent{Type: agentpkg.EventTextDelta, Delta: "."},
+		)
+	}
+	return events
+}
+
+func TestToolRoundLimiterAbortsAfterCap(t *testing.T) {
+	limiter := &toolRoundLimiter{max: 3}
+	completed := 0
+	aborted := false
+	for _, event := range toolRoundEvents(5) {
+		if limiter.Observe(event) {
+			aborted = true
+			break
+		}
+		if event.Type == agentpkg.EventToolCallEnd {
+			completed++
+		}
+	}
+	if !aborted {
+		t.Fatal("expected the stream to be aborted")
+	}
+	if completed != 3 {
+		t.Fatalf("expected abort on the round after the cap, completed %d rounds", completed)
+	}
+	if msg := limiter.AbortMessage(); !strings.Contains(msg, "tool call limit reached") || !strings.Contains(msg, "3") {
+		t.Fatalf("unexpected abort message %q", msg)
+	}
+	if got := interruptReason(false, true); got != "tool call limit reached" {
+		t.Fatalf("unexpected interrupt reason %q", got)
+	}
+}
+
+func TestToolRoundLimiterWithoutCapNeverAborts(t *testing.T) {
+	limiter := &toolRoundLimiter{}
+	for _, event := range toolRoundEvents(200) {
+		if limiter.Observe(event) {
+			t.Fatal("expected no abort without a cap")
+		}
+	}
+}
@@ -1,6 +1,8 @@
 package flow
 
 import (
+	"encoding/xml"
+	"sort"
 	"strings"
 	"time"
 )
@@ -18,6 +20,9 @@ type UserMessageMeta struct {
 	Time              string   `json:"time"`
 	Timezone          string   `json:"timezone,omitempty"`
 	AttachmentPaths   []string `json:"attachments"`
+	// SenderAttributes are the platform sender attributes whitelisted by the
+	// channel config, written as sender-<key> tag attributes.
+	SenderAttributes map[string]string `json:"sender-attributes,omitempty"`
 }
 
 // UserMessageHeaderInput is the unified input for building user message headers.
@@ -34,6 +39,7 @@ type UserMessageHeaderInput struct {
 	AttachmentPaths   []string
 	Time              time.Time
 	Timezone          string
+	SenderAttributes  map[string]string
 }
 
 // BuildUserMessageMetaFromInput constructs metadata from one cohesive input.
@@ -53,6 +59,7 @@ func BuildUserMessageMetaFromInput(input UserMessageHeaderInput) UserMessageMeta
 		Time:              time.Now().UTC().Format(time.RFC3339),
 		Timezone:          strings.TrimSpace(input.Timezone),
 		AttachmentPaths:   attachmentPaths,
+		SenderAttributes:  input.SenderAttributes,
 	}
 	if !input.Time.IsZero() {
 		meta.Time = input.Time.Format(time.RFC3339)
@@ -104,6 +111,9 @@ func (m UserMessageMeta) ToMap() map[string]any {
 	if strings.TrimSpace(m.Timezone) != "" {
 		result["timezone"] = m.Timezone
 	}
+	if len(m.SenderAttributes) > 0 {
+		result["sender-attributes"] = m.SenderAttributes
+	}
 	return result
 }
 
@@ -126,6 +136,7 @@ func FormatUserHeaderFromMeta(meta UserMessageMeta, query string) string {
 		writeXMLAttr(&sb, "id", meta.MessageID)
 	}
 	writeXMLAttr(&sb, "sender", meta.DisplayName)
+	writeSenderAttributes(&sb, meta.SenderAttributes)
 	writeXMLAttr(&sb, "t", meta.Time)
 	writeXMLAttr(&sb, "channel", meta.Channel)
 	if meta.ConversationName != "" {
@@ -139,12 +150,13 @@ func FormatUserHeaderFromMeta(meta UserMessageMeta, query string) string {
 	}
 	sb.WriteString(">\n")
 
-	if len(meta.AttachmentPaths) > 0 {
-		for _, p := range meta.AttachmentPaths {
-			sb.WriteString("<attachment path=\"")
-			sb.WriteString(escapeXMLAttr(p))
-			sb.WriteString("\"/>\n")
+	for _, p := range meta.AttachmentPaths {
+		if strings.TrimSpace(p) == "" {
+			continue
 		}
+		sb.WriteString("<attachment")
+		writeXMLAttr(&sb, "path", p)
+		sb.WriteString("/>\n")
 	}
 
 	sb.WriteString(query)
@@ -152,15 +164,112 @@ func FormatUserHeaderFromMeta(meta UserMessageMeta, query string) string {
 	return sb.String()
 }
 
-// escapeXMLAttr escapes a string for use inside an XML attribute value.
+// parseUserHeader reverses FormatUserHeaderFromMeta: it splits a headerified
+// query into the metadata carried by the <message> tag and the raw query. The
+// channel identity ID and timezone are not part of the tag and come back empty.
+// ok is false when text is not a complete message tag.
+func parseUserHeader(text string) (meta UserMessageMeta, query string, ok bool) {
+	const closing = "\n</message>"
+	if !strings.HasPrefix(text, "<message ") || !strings.HasSuffix(text, closing) {
+		return UserMessageMeta{}, "", false
+	}
+	// Attribute values are escaped, so the first '>' ends the opening tag.
+	end := strings.IndexByte(text, '>')
+	if end < 0 || !strings.HasPrefix(text[end+1:], "\n") || !strings.HasSuffix(text[end+2:], closing) {
+		return UserMessageMeta{}, "", false
+	}
+	var tag struct {
+		ID           string     `xml:"id,attr"`
+		Sender       string     `xml:"sender,attr"`
+		Time         string     `xml:"t,attr"`
+		Channel      string     `xml:"channel,attr"`
+		Conversation string     `xml:"conversation,attr"`
+		Type         string     `xml:"type,attr"`
+		Target       string     `xml:"target,attr"`
+		Other        []xml.Attr `xml:",any,attr"`
+	}
+	if err := xml.Unmarshal([]byte(text[:end+1]+"</message>"), &tag); err != nil {
+		return UserMessageMeta{}, "", false
+	}
+	meta = UserMessageMeta{
+		MessageID:        tag.ID,
+		DisplayName:      tag.Sender,
+		Channel:          tag.Channel,
+		ConversationType: tag.Type,
+		ConversationName: tag.Conversation,
+		Target:           tag.Target,
+		Time:             tag.Time,
+		AttachmentPaths:  []string{},
+		SenderAttributes: parseSenderAttributes(tag.Other),
+	}
+
+	rest := strings.TrimSuffix(text[end+2:], closing)
+	for strings.HasPrefix(rest, "<attachment ") {
+		lineEnd := strings.Index(rest, "/>\n")
+		if lineEnd < 0 {
+			break
+		}
+		var attachment struct {
+			Path string `xml:"path,attr"`
+		}
+		if err := xml.Unmarshal([]byte(rest[:lineEnd+2]), &attachment); err != nil {
+			return UserMessageMeta{}, "", false
+		}
+		meta.AttachmentPaths = append(meta.AttachmentPaths, attachment.Path)
+		rest = rest[lineEnd+3:]
+	}
+	return meta, rest, true
+}
+
+// xmlAttrReplacer escapes markup characters and encodes line breaks and tabs
+// as character references, since XML parsers normalize literal whitespace in
+// attribute values to spaces.
+var xmlAttrReplacer = strings.NewReplacer(
+	"&", "&amp;",
+	"<", "&lt;",
+	">", "&gt;",
+	"\"", "&quot;",
+	"\n", "&#10;",
+	"\r", "&#13;",
+	"\t", "&#9;",
+)
+
+// escapeXMLAttr escapes a string for use inside a double-quoted XML attribute
+// value so that it round-trips unchanged.
 func escapeXMLAttr(s string) string {
-	r := strings.NewReplacer(
-		"&", "&amp;",
-		"<", "&lt;",
-		">", "&gt;",
-		"\"", "&quot;",
-	)
-	return r.Replace(s)
+	return xmlAttrReplacer.Replace(s)
+}
+
+// senderAttrPrefix prefixes the tag attribute of each sender attribute.
+// Underscores in attribute keys become hyphens.
+const senderAttrPrefix = "sender-"
+
+func writeSenderAttributes(sb *strings.Builder, attributes map[string]string) {
+	keys := make([]string, 0, len(attributes))
+	for key, value := range attributes {
+		if strings.TrimSpace(key) != "" && value != "" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		writeXMLAttr(sb, senderAttrPrefix+strings.ReplaceAll(key, "_", "-"), attributes[key])
+	}
+}
+
+func parseSenderAttributes(attrs []xml.Attr) map[string]string {
+	var out map[string]string
+	for _, attr := range attrs {
+		name := attr.Name.Local
+		if attr.Name.Space != "" || !strings.HasPrefix(name, senderAttrPrefix) || len(name) == len(senderAttrPrefix) {
+			continue
+		}
+		if out == nil {
+			out = map[string]string{}
+		}
+		out[strings.ReplaceAll(strings.TrimPrefix(name, senderAttrPrefix), "-", "_")] = attr.Value
+	}
+	return out
 }
 
 func writeXMLAttr(sb *strings.Builder, key, value string) {
@@ -1,9 +1,16 @@
 package flow
 
 import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"log/slog"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/memohai/memoh/internal/conversation"
 )
 
 func TestFormatUserHeaderIncludesAttachments(t *testing.T) {
@@ -43,3 +50,193 @@ func TestFormatUserHeaderWithoutAttachmentsUsesEmptyList(t *testing.T) {
 		t.Fatalf("expected no attachment tag in header: %s", header)
 	}
 }
+
+type parsedUserHeader struct {
+	XMLName      xml.Name `xml:"message"`
+	Sender       string   `xml:"sender,attr"`
+	Conversation string   `xml:"conversation,attr"`
+	Attachments  []struct {
+		Path string `xml:"path,attr"`
+	} `xml:"attachment"`
+}
+
+func TestFormatUserHeaderEscapesSpecialCharacters(t *testing.T) {
+	t.Parallel()
+
+	paths := []string{
+		"/data/media/report: Q1 #2.pdf",
+		`/data/media/say "hi" & <bye>.txt`,
+		"/data/media/it's.png",
+	}
+	header := FormatUserHeader(UserMessageHeaderInput{
+		ChannelIdentityID: "cid_1",
+		DisplayName:       `Bob "The Builder" O'Neil <admin>`,
+		Channel:           "telegram",
+		ConversationType:  "group",
+		ConversationName:  "Line one\nLine two\tTabbed",
+		AttachmentPaths:   append(paths, "  "),
+		Time:              time.Date(2026, 4, 6, 10, 0, 0, 0, time.UTC),
+	}, "hello")
+
+	var parsed parsedUserHeader
+	if err := xml.Unmarshal([]byte(header), &parsed); err != nil {
+		t.Fatalf("header is not well-formed XML: %v\n%s", err, header)
+	}
+	if parsed.Sender != `Bob "The Builder" O'Neil <admin>` {
+		t.Fatalf("sender did not round-trip: %q", parsed.Sender)
+	}
+	if parsed.Conversation != "Line one\nLine two\tTabbed" {
+		t.Fatalf("multi-line conversation did not round-trip: %q", parsed.Conversation)
+	}
+	if len(parsed.Attachments) != len(paths) {
+		t.Fatalf("expected %d attachments (blank skipped), got %d", len(paths), len(parsed.Attachments))
+	}
+	for i, want := range paths {
+		if parsed.Attachments[i].Path != want {
+			t.Fatalf("attachment %d: got %q, want %q", i, parsed.Attachments[i].Path, want)
+		}
+	}
+	if strings.Count(header, "\n") != len(paths)+2 {
+		t.Fatalf("expected attribute values to stay on one line: %s", header)
+	}
+}
+
+func TestParseUserHeaderRoundTrips(t *testing.T) {
+	t.Parallel()
+
+	meta := BuildUserMessageMetaFromInput(UserMessageHeaderInput{
+		MessageID:        "msg_1",
+		DisplayName:      `Bob "B" <admin>`,
+		Channel:          "telegram",
+		ConversationType: "group",
+		ConversationName: "Team\nChat",
+		Target:           "chat-1",
+		AttachmentPaths:  []string{"/data/a b.png", "/data/<c>.txt"},
+		Time:             time.Date(2026, 4, 6, 10, 0, 0, 0, time.UTC),
+		SenderAttributes: map[string]string{"locale": "en-GB", "is_premium": "true"},
+	})
+	for _, query := range []string{"hello", "multi\nline <b>query</b>", ""} {
+		header := FormatUserHeaderFromMeta(meta, query)
+		parsed, got, ok := parseUserHeader(header)
+		if !ok {
+			t.Fatalf("expected header to parse: %s", header)
+		}
+		if got != query {
+			t.Fatalf("query did not round-trip: got %q, want %q", got, query)
+		}
+		if !reflect.DeepEqual(parsed, meta) {
+			t.Fatalf("meta did not round-trip:\n got %+v\nwant %+v", parsed, meta)
+		}
+	}
+}
+
+func TestFormatUserHeaderIncludesSenderAttributes(t *testing.T) {
+	t.Parallel()
+
+	header := FormatUserHeader(UserMessageHeaderInput{
+		DisplayName:      "Alice",
+		Channel:          "telegram",
+		Time:             time.Date(2026, 4, 6, 10, 0, 0, 0, time.UTC),
+		SenderAttributes: map[string]string{"username": "alice", "is_premium": "true"},
+	}, "hi")
+	want := `<message sender="Alice" sender-is-premium="true" sender-username="alice" t=`
+	if !strings.HasPrefix(header, want) {
+		t.Fatalf("expected sender attributes after sender, got: %s", header)
+	}
+}
+
+func TestParseUserHeaderRejectsPlainText(t *testing.T) {
+	t.Parallel()
+
+	for _, text := range []string{"hello", "<message> not a header", "<message sender=\"a\">\nunterminated"} {
+		if _, _, ok := parseUserHeader(text); ok {
+			t.Fatalf("expected %q not to parse as a header", text)
+		}
+	}
+}
+
+func TestStripHistoryUserHeadersKeepsCurrentQueryHeader(t *testing.T) {
+	t.Parallel()
+
+	input := UserMessageHeaderInput{
+		MessageID:        "msg_1",
+		DisplayName:      "Alice",
+		Channel:          "telegram",
+		ConversationType: "group",
+		Time:             time.Date(2026, 4, 6, 10, 0, 0, 0, time.UTC),
+	}
+	tokens := 100
+	history := []messageWithUsage{
+		{Message: conversation.ModelMessage{Role: "user", Content: conversation.NewTextContent(FormatUserHeader(input, "first"))}, UsageInputTokens: &tokens},
+		{Message: conversation.ModelMessage{Role: "assistant", Content: conversation.NewTextContent("<message> is a tag")}, UsageInputTokens: &tokens},
+		{Message: conversation.ModelMessage{Role: "user", Content: json.RawMessage(`[{"type":"text","text":` + mustJSON(t, FormatUserHeader(input, "second")) + `},{"type":"image","image":"data:image/png;base64,AA=="}]`)}},
+	}
+
+	got := stripHistoryUserHeaders(history)
+
+	if text := got[0].Message.TextContent(); text != "first" {
+		t.Fatalf("expected header stripped from history, got %q", text)
+	}
+	if got[0].UsageInputTokens != nil || got[1].UsageInputTokens != nil {
+		t.Fatal("expected usage after a rewritten message to be cleared")
+	}
+	if text := got[1].Message.TextContent(); text != "<message> is a tag" {
+		t.Fatalf("expected assistant message untouched, got %q", text)
+	}
+	parts := got[2].Message.ContentParts()
+	if len(parts) != 2 || parts[0].Text != "second" || parts[1].Type != "image" {
+		t.Fatalf("expected multi-part header stripped with image kept, got %s", got[2].Message.Content)
+	}
+
+	current := FormatUserHeader(input, "third")
+	if _, query, ok := parseUserHeader(current); !ok || query != "third" {
+		t.Fatalf("expected current query to keep its header: %s", current)
+	}
+}
+
+func TestPersistMessagesStoresUserHeaderSeparately(t *testing.T) {
+	t.Parallel()
+
+	svc := &fakeToolCallMessageService{}
+	resolver := &Resolver{messageService: svc, logger: slog.New(slog.DiscardHandler)}
+	headerified := FormatUserHeader(UserMessageHeaderInput{
+		MessageID:        "msg_1",
+		DisplayName:      "Alice",
+		Channel:          "telegram",
+		ConversationType: "private",
+		Time:             time.Date(2026, 4, 6, 10, 0, 0, 0, time.UTC),
+	}, "hello")
+	round := []conversation.ModelMessage{
+		{Role: "user", Content: conversation.NewTextContent(headerified)},
+		{Role: "assistant", Content: conversation.NewTextContent("hi Alice")},
+	}
+
+	resolver.persistMessages(context.Background(), conversation.ChatRequest{BotID: "bot-1", Query: "hello"}, round, "", nil, storeOptions{stripUserHeaders: true})
+
+	if len(svc.persisted) != 2 {
+		t.Fatalf("expected 2 persisted messages, got %d", len(svc.persisted))
+	}
+	var stored conversation.ModelMessage
+	if err := json.Unmarshal(svc.persisted[0].Content, &stored); err != nil {
+		t.Fatalf("unmarshal stored user message: %v", err)
+	}
+	if text := stored.TextContent(); text != "hello" {
+		t.Fatalf("expected stored user message without header, got %q", text)
+	}
+	header, ok := svc.persisted[0].Metadata["user_header"].(map[string]any)
+	if !ok || header["display-name"] != "Alice" || header["message-id"] != "msg_1" {
+		t.Fatalf("expected header fields in metadata, got %v", svc.persisted[0].Metadata)
+	}
+	if _, ok := svc.persisted[1].Metadata["user_header"]; ok {
+		t.Fatal("expected no header metadata on the assistant message")
+	}
+}
+
+func mustJSON(t *testing.T, v any) string {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	return string(data)
+}
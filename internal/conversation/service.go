@@ -22,6 +22,12 @@ var (
 	ErrNotParticipant   = errors.New("not a participant")
 	ErrPermissionDenied = errors.New("permission denied")
 	ErrModelIDAmbiguous = errors.New("model_id is ambiguous across providers")
+	// ErrSpendCapExceeded is returned when a bot has reached its monthly
+	// token or cost cap.
+	ErrSpendCapExceeded = errors.New("bot monthly spend cap exceeded")
+	// ErrProviderQuotaExhausted is returned when the model provider rejects
+	// a request because the account is out of quota or credit.
+	ErrProviderQuotaExhausted = errors.New("model provider quota exhausted")
 )
 
 // Service manages conversation lifecycle, participants, and settings.
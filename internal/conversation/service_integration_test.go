@@ -48,10 +48,12 @@ func setupChatPresenceIntegrationTest(t *testing.T) chatPresenceFixture {
 
 	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
 	queries := sqlc.New(pool)
+	messageSvc := message.NewService(logger, queries)
+	messageSvc.SetDB(pool)
 
 	return chatPresenceFixture{
 		chatSvc:            conversation.NewService(logger, queries),
-		messageSvc:         message.NewService(logger, queries),
+		messageSvc:         messageSvc,
 		channelIdentitySvc: identities.NewService(logger, queries),
 		queries:            queries,
 		cleanup:            func() { pool.Close() },
@@ -240,3 +242,55 @@ func TestObservedAccessReadableButNotParticipant(t *testing.T) {
 		t.Fatal("expected observed list entry with channel_identity_observed access mode")
 	}
 }
+
+func TestPersistBatchKeepsOrderAndUsage(t *testing.T) {
+	fixture := setupChatPresenceIntegrationTest(t)
+	defer fixture.cleanup()
+	ctx := context.Background()
+
+	ownerUserID, err := createUserForChatPresence(ctx, fixture.queries)
+	if err != nil {
+		t.Fatalf("create owner user failed: %v", err)
+	}
+	botID, err := createBotForChatPresence(ctx, fixture.queries, ownerUserID)
+	if err != nil {
+		t.Fatalf("create bot failed: %v", err)
+	}
+
+	inputs := []message.PersistInput{
+		{BotID: botID, Role: "user", Content: []byte(`{"content":"q"}`)},
+		{BotID: botID, Role: "assistant", Content: []byte(`{"content":"call"}`), Usage: []byte(`{"inputTokens":10}`)},
+		{BotID: botID, Role: "tool", Content: []byte(`{"content":"result"}`)},
+		{BotID: botID, Role: "assistant", Content: []byte(`{"content":"answer"}`), Usage: []byte(`{"inputTokens":20}`)},
+	}
+	persisted, err := fixture.messageSvc.PersistBatch(ctx, inputs)
+	if err != nil {
+		t.Fatalf("persist batch failed: %v", err)
+	}
+	if len(persisted) != len(inputs) {
+		t.Fatalf("expected %d persisted messages, got %d", len(inputs), len(persisted))
+	}
+
+	listed, err := fixture.messageSvc.List(ctx, botID)
+	if err != nil {
+		t.Fatalf("list messages failed: %v", err)
+	}
+	if len(listed) != len(inputs) {
+		t.Fatalf("expected %d listed messages, got %d", len(inputs), len(listed))
+	}
+	for i, msg := range listed {
+		if msg.ID != persisted[i].ID || msg.Role != inputs[i].Role {
+			t.Fatalf("message %d: expected %s (%s), got %s (%s)", i, persisted[i].ID, inputs[i].Role, msg.ID, msg.Role)
+		}
+		if len(inputs[i].Usage) == 0 {
+			continue
+		}
+		var want, got struct {
+			InputTokens int `json:"inputTokens"`
+		}
+		_ = json.Unmarshal(inputs[i].Usage, &want)
+		if err := json.Unmarshal(msg.Usage, &got); err != nil || got != want {
+			t.Fatalf("message %d: expected usage %s, got %s", i, inputs[i].Usage, msg.Usage)
+		}
+	}
+}
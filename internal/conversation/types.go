@@ -238,11 +238,33 @@ type ChatRequest struct {
 	UserMessagePersisted    bool   `json:"-"`
 	EventID                 string `json:"-"`
 	RawQuery                string `json:"-"`
+	// DryRun resolves the request without side effects such as synchronous
+	// compaction. Used when previewing the assembled agent request.
+	DryRun bool `json:"-"`
+
+	// ConversationNotes and ConversationTags are the operator annotations of
+	// the route, surfaced to the agent for personalization.
+	ConversationNotes string   `json:"-"`
+	ConversationTags  []string `json:"-"`
+
+	// ModelVariant is the model experiment variant that served the turn. Set
+	// by the resolver and recorded on the stored assistant messages.
+	ModelVariant string `json:"-"`
+
+	// SenderAttributes are the platform sender attributes the channel config
+	// passes on to the agent, keyed by attribute name.
+	SenderAttributes map[string]string `json:"-"`
 
 	// OutboundAssetCollector returns asset refs accumulated during outbound streaming.
 	// Set by the inbound channel processor; called by the resolver at persist time.
 	OutboundAssetCollector func() []OutboundAssetRef `json:"-"`
 
+	// AssistantOutputRecorder receives the IDs of the stored assistant
+	// messages that carry visible output, in order. Set by the inbound
+	// channel processor to link delivery receipts; called by the resolver at
+	// persist time. IDs of messages that failed to persist are empty.
+	AssistantOutputRecorder func(messageIDs []string) `json:"-"`
+
 	// InjectCh receives user messages to inject into the active agent stream
 	// between tool rounds via the PrepareStep hook. Nil means no injection.
 	InjectCh <-chan InjectMessage `json:"-"`
@@ -2,12 +2,85 @@ package db
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	"github.com/memohai/memoh/internal/config"
 )
 
+// maxPoolConns bounds the configured pool size; PostgreSQL itself rarely
+// allows more connections than this.
+const maxPoolConns = 10000
+
 func Open(ctx context.Context, cfg config.PostgresConfig) (*pgxpool.Pool, error) {
-	return pgxpool.New(ctx, DSN(cfg))
+	poolCfg, err := PoolConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return pgxpool.NewWithConfig(ctx, poolCfg)
+}
+
+// OpenReadReplica opens the read replica pool. It returns nil without error
+// when no replica is configured.
+func OpenReadReplica(ctx context.Context, cfg config.PostgresConfig) (*pgxpool.Pool, error) {
+	if strings.TrimSpace(cfg.ReadReplicaDSN) == "" {
+		return nil, nil
+	}
+	poolCfg, err := poolConfig(strings.TrimSpace(cfg.ReadReplicaDSN), cfg)
+	if err != nil {
+		return nil, fmt.Errorf("read replica: %w", err)
+	}
+	return pgxpool.NewWithConfig(ctx, poolCfg)
+}
+
+// PoolConfig builds the pgxpool config for cfg, applying the pool tuning
+// values that are set. Out of range values are rejected.
+func PoolConfig(cfg config.PostgresConfig) (*pgxpool.Config, error) {
+	return poolConfig(DSN(cfg), cfg)
+}
+
+func poolConfig(dsn string, cfg config.PostgresConfig) (*pgxpool.Config, error) {
+	if err := validatePoolTuning(cfg); err != nil {
+		return nil, err
+	}
+	poolCfg, err := pgxpool.ParseConfig(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("parse postgres config: %w", err)
+	}
+	if cfg.MaxConns > 0 {
+		poolCfg.MaxConns = int32(cfg.MaxConns) //nolint:gosec // G115: bounded by validatePoolTuning
+	}
+	if cfg.MinConns > 0 {
+		poolCfg.MinConns = int32(cfg.MinConns) //nolint:gosec // G115: bounded by validatePoolTuning
+	}
+	if cfg.MaxConnLifetimeSeconds > 0 {
+		poolCfg.MaxConnLifetime = time.Duration(cfg.MaxConnLifetimeSeconds) * time.Second
+	}
+	if cfg.HealthCheckPeriodSeconds > 0 {
+		poolCfg.HealthCheckPeriod = time.Duration(cfg.HealthCheckPeriodSeconds) * time.Second
+	}
+	if poolCfg.MinConns > poolCfg.MaxConns {
+		return nil, fmt.Errorf("postgres min_conns (%d) exceeds max_conns (%d)", poolCfg.MinConns, poolCfg.MaxConns)
+	}
+	return poolCfg, nil
+}
+
+func validatePoolTuning(cfg config.PostgresConfig) error {
+	if cfg.MaxConns < 0 || cfg.MaxConns > maxPoolConns {
+		return fmt.Errorf("postgres max_conns must be between 0 and %d", maxPoolConns)
+	}
+	if cfg.MinConns < 0 || cfg.MinConns > maxPoolConns {
+		return fmt.Errorf("postgres min_conns must be between 0 and %d", maxPoolConns)
+	}
+	if cfg.MaxConnLifetimeSeconds < 0 {
+		return errors.New("postgres max_conn_lifetime_seconds must not be negative")
+	}
+	if cfg.HealthCheckPeriodSeconds < 0 {
+		return errors.New("postgres health_check_period_seconds must not be negative")
+	}
+	return nil
 }
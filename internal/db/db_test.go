@@ -0,0 +1,77 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/memohai/memoh/internal/config"
+)
+
+func testPostgresConfig() config.PostgresConfig {
+	return config.PostgresConfig{
+		Host:     "localhost",
+		Port:     5432,
+		User:     "memoh",
+		Password: "secret",
+		Database: "memoh",
+		SSLMode:  "disable",
+	}
+}
+
+func TestPoolConfigAppliesTuning(t *testing.T) {
+	cfg := testPostgresConfig()
+	cfg.MaxConns = 40
+	cfg.MinConns = 5
+	cfg.MaxConnLifetimeSeconds = 1800
+	cfg.HealthCheckPeriodSeconds = 15
+
+	poolCfg, err := PoolConfig(cfg)
+	if err != nil {
+		t.Fatalf("PoolConfig: %v", err)
+	}
+	if poolCfg.MaxConns != 40 || poolCfg.MinConns != 5 {
+		t.Fatalf("expected 5..40 connections, got %d..%d", poolCfg.MinConns, poolCfg.MaxConns)
+	}
+	if poolCfg.MaxConnLifetime != 30*time.Minute {
+		t.Fatalf("expected 30m lifetime, got %s", poolCfg.MaxConnLifetime)
+	}
+	if poolCfg.HealthCheckPeriod != 15*time.Second {
+		t.Fatalf("expected 15s health check period, got %s", poolCfg.HealthCheckPeriod)
+	}
+	if poolCfg.ConnConfig.Host != "localhost" || poolCfg.ConnConfig.Database != "memoh" {
+		t.Fatalf("expected connection settings from DSN, got host=%q database=%q", poolCfg.ConnConfig.Host, poolCfg.ConnConfig.Database)
+	}
+}
+
+func TestPoolConfigKeepsDefaultsWhenUnset(t *testing.T) {
+	defaults, err := PoolConfig(testPostgresConfig())
+	if err != nil {
+		t.Fatalf("PoolConfig: %v", err)
+	}
+	if defaults.MaxConns <= 0 || defaults.MaxConnLifetime <= 0 || defaults.HealthCheckPeriod <= 0 {
+		t.Fatalf("expected pgxpool defaults, got max=%d lifetime=%s health=%s", defaults.MaxConns, defaults.MaxConnLifetime, defaults.HealthCheckPeriod)
+	}
+}
+
+func TestPoolConfigRejectsInvalidTuning(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*config.PostgresConfig)
+	}{
+		{name: "negative max conns", modify: func(c *config.PostgresConfig) { c.MaxConns = -1 }},
+		{name: "max conns too large", modify: func(c *config.PostgresConfig) { c.MaxConns = maxPoolConns + 1 }},
+		{name: "negative min conns", modify: func(c *config.PostgresConfig) { c.MinConns = -1 }},
+		{name: "min above max", modify: func(c *config.PostgresConfig) { c.MaxConns = 4; c.MinConns = 8 }},
+		{name: "negative lifetime", modify: func(c *config.PostgresConfig) { c.MaxConnLifetimeSeconds = -1 }},
+		{name: "negative health check", modify: func(c *config.PostgresConfig) { c.HealthCheckPeriodSeconds = -1 }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testPostgresConfig()
+			tt.modify(&cfg)
+			if _, err := PoolConfig(cfg); err == nil {
+				t.Fatal("expected invalid pool tuning to be rejected")
+			}
+		})
+	}
+}
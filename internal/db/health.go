@@ -0,0 +1,104 @@
+package db
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultHealthCheckInterval is how often the database is pinged.
+	DefaultHealthCheckInterval = 5 * time.Second
+	healthPingTimeout          = 3 * time.Second
+)
+
+// Pinger checks that the database answers. *pgxpool.Pool implements it.
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// HealthStatus is the last observed database state.
+type HealthStatus struct {
+	Healthy   bool
+	Error     string
+	CheckedAt time.Time
+}
+
+// HealthMonitor pings the database periodically so the health endpoint can
+// report it down while Postgres is unreachable. The pool reconnects on its
+// own; the monitor only observes. It starts out healthy, as the pool was
+// reachable when it was opened.
+type HealthMonitor struct {
+	pinger Pinger
+	logger *slog.Logger
+	now    func() time.Time
+
+	mu     sync.RWMutex
+	status HealthStatus
+}
+
+// NewHealthMonitor creates a HealthMonitor for pinger.
+func NewHealthMonitor(log *slog.Logger, pinger Pinger) *HealthMonitor {
+	return &HealthMonitor{
+		pinger: pinger,
+		logger: log.With(slog.String("component", "db_health")),
+		now:    time.Now,
+		status: HealthStatus{Healthy: true},
+	}
+}
+
+// Status returns the result of the last check.
+func (m *HealthMonitor) Status() HealthStatus {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.status
+}
+
+// Healthy reports whether the last check reached the database.
+func (m *HealthMonitor) Healthy() bool {
+	return m.Status().Healthy
+}
+
+// Check pings the database and records the result, logging when the
+// database goes down or comes back.
+func (m *HealthMonitor) Check(ctx context.Context) error {
+	pingCtx, cancel := context.WithTimeout(ctx, healthPingTimeout)
+	err := m.pinger.Ping(pingCtx)
+	cancel()
+
+	status := HealthStatus{Healthy: err == nil, CheckedAt: m.now()}
+	if err != nil {
+		status.Error = err.Error()
+	}
+	m.mu.Lock()
+	wasHealthy := m.status.Healthy
+	m.status = status
+	m.mu.Unlock()
+
+	switch {
+	case wasHealthy && !status.Healthy:
+		m.logger.Error("database unreachable", slog.Any("error", err))
+	case !wasHealthy && status.Healthy:
+		m.logger.Info("database reachable again")
+	}
+	return err
+}
+
+// StartLoop checks the database every interval until ctx is done.
+func (m *HealthMonitor) StartLoop(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultHealthCheckInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			_ = m.Check(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
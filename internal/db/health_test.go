@@ -0,0 +1,47 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+)
+
+// flakyPinger fails while down is set.
+type flakyPinger struct {
+	down bool
+}
+
+func (p *flakyPinger) Ping(context.Context) error {
+	if p.down {
+		return errors.New("connection refused")
+	}
+	return nil
+}
+
+func TestHealthMonitorTracksOutageAndRecovery(t *testing.T) {
+	pinger := &flakyPinger{}
+	monitor := NewHealthMonitor(slog.New(slog.DiscardHandler), pinger)
+	ctx := context.Background()
+
+	if !monitor.Healthy() {
+		t.Fatal("expected monitor to start healthy")
+	}
+
+	pinger.down = true
+	if err := monitor.Check(ctx); err == nil {
+		t.Fatal("expected check to fail while the database is down")
+	}
+	status := monitor.Status()
+	if status.Healthy || status.Error != "connection refused" || status.CheckedAt.IsZero() {
+		t.Fatalf("status while down = %+v", status)
+	}
+
+	pinger.down = false
+	if err := monitor.Check(ctx); err != nil {
+		t.Fatalf("check after recovery: %v", err)
+	}
+	if status := monitor.Status(); !status.Healthy || status.Error != "" {
+		t.Fatalf("status after recovery = %+v", status)
+	}
+}
@@ -0,0 +1,84 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// retryPolicy bounds how often and how long Retry waits for the database
+// to come back.
+type retryPolicy struct {
+	attempts    int
+	baseBackoff time.Duration
+	maxBackoff  time.Duration
+}
+
+// defaultRetryPolicy retries for up to about 1.5 seconds, long enough for
+// the pool to reconnect after a brief Postgres restart or failover without
+// holding a request for long.
+var defaultRetryPolicy = retryPolicy{
+	attempts:    4,
+	baseBackoff: 100 * time.Millisecond,
+	maxBackoff:  time.Second,
+}
+
+// Server error codes sent when Postgres terminates or refuses a session;
+// the statement did not run and can be sent again.
+var connectionErrorCodes = []string{
+	"57P01", // admin_shutdown
+	"57P02", // crash_shutdown
+	"57P03", // cannot_connect_now
+}
+
+// IsConnectionError reports whether err means the database could not be
+// reached and the statement was not executed, so it is safe to retry: a
+// failed connect, a connection lost before the query was sent, or a server
+// shutting down or still starting up.
+func IsConnectionError(err error) bool {
+	if err == nil {
+		return false
+	}
+	var connectErr *pgconn.ConnectError
+	if errors.As(err, &connectErr) {
+		return true
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		for _, code := range connectionErrorCodes {
+			if pgErr.Code == code {
+				return true
+			}
+		}
+		return false
+	}
+	return pgconn.SafeToRetry(err)
+}
+
+// Retry runs op and runs it again with bounded exponential backoff while it
+// fails with a connection error, so key paths ride out a Postgres restart.
+// Other errors, and the last connection error once attempts run out, are
+// returned as is. op must be safe to repeat after a connection error.
+func Retry[T any](ctx context.Context, op func(context.Context) (T, error)) (T, error) {
+	return retry(ctx, defaultRetryPolicy, op)
+}
+
+func retry[T any](ctx context.Context, policy retryPolicy, op func(context.Context) (T, error)) (T, error) {
+	backoff := policy.baseBackoff
+	for attempt := 1; ; attempt++ {
+		result, err := op(ctx)
+		if err == nil || attempt >= policy.attempts || !IsConnectionError(err) {
+			return result, err
+		}
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return result, err
+		case <-timer.C:
+		}
+		backoff = min(backoff*2, policy.maxBackoff)
+	}
+}
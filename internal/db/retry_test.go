@@ -0,0 +1,100 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+var testRetryPolicy = retryPolicy{attempts: 3, baseBackoff: time.Millisecond, maxBackoff: 2 * time.Millisecond}
+
+func TestIsConnectionError(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "nil", err: nil, want: false},
+		{name: "connect", err: fmt.Errorf("acquire: %w", &pgconn.ConnectError{}), want: true},
+		{name: "admin shutdown", err: &pgconn.PgError{Code: "57P01"}, want: true},
+		{name: "starting up", err: fmt.Errorf("query: %w", &pgconn.PgError{Code: "57P03"}), want: true},
+		{name: "unique violation", err: &pgconn.PgError{Code: "23505"}, want: false},
+		{name: "plain", err: errors.New("boom"), want: false},
+	}
+	for _, tc := range cases {
+		if got := IsConnectionError(tc.err); got != tc.want {
+			t.Fatalf("%s: IsConnectionError = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestRetryRecoversFromConnectionErrors(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	got, err := retry(context.Background(), testRetryPolicy, func(context.Context) (string, error) {
+		calls++
+		if calls < 3 {
+			return "", &pgconn.PgError{Code: "57P01"}
+		}
+		return "stored", nil
+	})
+	if err != nil {
+		t.Fatalf("retry: %v", err)
+	}
+	if got != "stored" || calls != 3 {
+		t.Fatalf("got %q after %d calls, want stored after 3", got, calls)
+	}
+}
+
+func TestRetryGivesUpAfterAttempts(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	_, err := retry(context.Background(), testRetryPolicy, func(context.Context) (int, error) {
+		calls++
+		return 0, &pgconn.PgError{Code: "57P03"}
+	})
+	if !IsConnectionError(err) {
+		t.Fatalf("expected the last connection error, got %v", err)
+	}
+	if calls != testRetryPolicy.attempts {
+		t.Fatalf("calls = %d, want %d", calls, testRetryPolicy.attempts)
+	}
+}
+
+func TestRetryReturnsOtherErrorsImmediately(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	want := &pgconn.PgError{Code: "23505"}
+	_, err := retry(context.Background(), testRetryPolicy, func(context.Context) (int, error) {
+		calls++
+		return 0, want
+	})
+	if !errors.Is(err, want) || calls != 1 {
+		t.Fatalf("got %v after %d calls, want the error after 1 call", err, calls)
+	}
+}
+
+func TestRetryStopsWhenContextDone(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	policy := retryPolicy{attempts: 5, baseBackoff: time.Hour, maxBackoff: time.Hour}
+	_, err := retry(ctx, policy, func(context.Context) (int, error) {
+		calls++
+		cancel()
+		return 0, &pgconn.PgError{Code: "57P01"}
+	})
+	if !IsConnectionError(err) || calls != 1 {
+		t.Fatalf("got %v after %d calls, want the connection error after 1 call", err, calls)
+	}
+}
@@ -0,0 +1,79 @@
+package db
+
+import (
+	"context"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/memohai/memoh/internal/db/sqlc"
+)
+
+// ReplicaReadQueries are the sqlc queries served by the read replica. Only
+// paging through message history is routed: it dominates read load and
+// tolerates replica lag. Queries that read back rows written moments
+// earlier, such as the latest messages or history for the next turn, stay
+// on the primary.
+var ReplicaReadQueries = []string{
+	"ListMessages",
+	"ListMessagesBySession",
+	"ListMessagesBefore",
+	"ListMessagesBeforeBySession",
+}
+
+// QueryRouter is a sqlc.DBTX that sends the named read-only queries to a
+// replica and every other statement to the primary. Queries are identified
+// by the "-- name:" header sqlc puts at the start of each statement.
+type QueryRouter struct {
+	primary sqlc.DBTX
+	replica sqlc.DBTX
+	reads   map[string]struct{}
+}
+
+// NewQueryRouter returns a router over primary and replica. It returns
+// primary itself when replica is nil.
+func NewQueryRouter(primary, replica sqlc.DBTX, readQueries ...string) sqlc.DBTX {
+	if replica == nil {
+		return primary
+	}
+	reads := make(map[string]struct{}, len(readQueries))
+	for _, name := range readQueries {
+		reads[name] = struct{}{}
+	}
+	return &QueryRouter{primary: primary, replica: replica, reads: reads}
+}
+
+func (r *QueryRouter) Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	return r.primary.Exec(ctx, sql, args...)
+}
+
+func (r *QueryRouter) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	return r.route(sql).Query(ctx, sql, args...)
+}
+
+func (r *QueryRouter) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	return r.route(sql).QueryRow(ctx, sql, args...)
+}
+
+func (r *QueryRouter) route(sql string) sqlc.DBTX {
+	if _, ok := r.reads[queryName(sql)]; ok {
+		return r.replica
+	}
+	return r.primary
+}
+
+// queryName returns the name from a sqlc "-- name: Name :kind" header, or
+// "" when sql has none.
+func queryName(sql string) string {
+	header, _, _ := strings.Cut(sql, "\n")
+	rest, ok := strings.CutPrefix(strings.TrimSpace(header), "-- name:")
+	if !ok {
+		return ""
+	}
+	fields := strings.Fields(rest)
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[0]
+}
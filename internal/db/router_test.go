@@ -0,0 +1,90 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/memohai/memoh/internal/db/sqlc"
+)
+
+var errRecorded = errors.New("recorded")
+
+// recordingDB is a sqlc.DBTX that records the queries it receives and fails
+// every call.
+type recordingDB struct {
+	names []string
+}
+
+func (d *recordingDB) Exec(_ context.Context, sql string, _ ...interface{}) (pgconn.CommandTag, error) {
+	d.names = append(d.names, queryName(sql))
+	return pgconn.CommandTag{}, errRecorded
+}
+
+func (d *recordingDB) Query(_ context.Context, sql string, _ ...interface{}) (pgx.Rows, error) {
+	d.names = append(d.names, queryName(sql))
+	return nil, errRecorded
+}
+
+func (d *recordingDB) QueryRow(_ context.Context, sql string, _ ...interface{}) pgx.Row {
+	d.names = append(d.names, queryName(sql))
+	return errRow{}
+}
+
+type errRow struct{}
+
+func (errRow) Scan(...any) error { return errRecorded }
+
+func TestQueryRouterSendsReadsToReplica(t *testing.T) {
+	primary, replica := &recordingDB{}, &recordingDB{}
+	queries := sqlc.New(NewQueryRouter(primary, replica, ReplicaReadQueries...))
+	ctx := context.Background()
+
+	_, _ = queries.ListMessages(ctx, pgtype.UUID{})
+	_, _ = queries.ListMessagesBeforeBySession(ctx, sqlc.ListMessagesBeforeBySessionParams{})
+	_, _ = queries.CreateMessage(ctx, sqlc.CreateMessageParams{})
+	_ = queries.DeleteMessagesByBot(ctx, pgtype.UUID{})
+
+	if want := []string{"ListMessages", "ListMessagesBeforeBySession"}; !slices.Equal(replica.names, want) {
+		t.Fatalf("replica got %v, want %v", replica.names, want)
+	}
+	if want := []string{"CreateMessage", "DeleteMessagesByBot"}; !slices.Equal(primary.names, want) {
+		t.Fatalf("primary got %v, want %v", primary.names, want)
+	}
+}
+
+func TestQueryRouterExecAlwaysUsesPrimary(t *testing.T) {
+	primary, replica := &recordingDB{}, &recordingDB{}
+	router := NewQueryRouter(primary, replica, "ListMessages")
+
+	_, _ = router.Exec(context.Background(), "-- name: ListMessages :many\nSELECT 1")
+	if len(replica.names) != 0 || len(primary.names) != 1 {
+		t.Fatalf("expected exec on primary only, primary=%v replica=%v", primary.names, replica.names)
+	}
+}
+
+func TestNewQueryRouterWithoutReplica(t *testing.T) {
+	primary := &recordingDB{}
+	if got := NewQueryRouter(primary, nil, ReplicaReadQueries...); got != sqlc.DBTX(primary) {
+		t.Fatalf("expected primary to be returned, got %T", got)
+	}
+}
+
+func TestQueryName(t *testing.T) {
+	tests := map[string]string{
+		"-- name: ListMessages :many\nSELECT 1": "ListMessages",
+		"  -- name:   GetBot :one\nSELECT 1":    "GetBot",
+		"SELECT 1":                              "",
+		"-- name:\nSELECT 1":                    "",
+	}
+	for sql, want := range tests {
+		if got := queryName(sql); got != want {
+			t.Errorf("queryName(%q) = %q, want %q", sql, got, want)
+		}
+	}
+}
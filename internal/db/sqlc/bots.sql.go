@@ -12,19 +12,21 @@ import (
 )
 
 const createBot = `-- name: CreateBot :one
-INSERT INTO bots (owner_user_id, display_name, avatar_url, timezone, is_active, metadata, status)
-VALUES ($1, $2, $3, $4, $5, $6, $7)
+INSERT INTO bots (owner_user_id, display_name, avatar_url, timezone, is_active, metadata, status, chat_model_id, memory_provider_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8::uuid, $9::uuid)
 RETURNING id, owner_user_id, display_name, avatar_url, timezone, is_active, status, language, reasoning_enabled, reasoning_effort, chat_model_id, search_provider_id, memory_provider_id, heartbeat_enabled, heartbeat_interval, heartbeat_prompt, metadata, created_at, updated_at
 `
 
 type CreateBotParams struct {
-	OwnerUserID pgtype.UUID `json:"owner_user_id"`
-	DisplayName pgtype.Text `json:"display_name"`
-	AvatarUrl   pgtype.Text `json:"avatar_url"`
-	Timezone    pgtype.Text `json:"timezone"`
-	IsActive    bool        `json:"is_active"`
-	Metadata    []byte      `json:"metadata"`
-	Status      string      `json:"status"`
+	OwnerUserID      pgtype.UUID `json:"owner_user_id"`
+	DisplayName      pgtype.Text `json:"display_name"`
+	AvatarUrl        pgtype.Text `json:"avatar_url"`
+	Timezone         pgtype.Text `json:"timezone"`
+	IsActive         bool        `json:"is_active"`
+	Metadata         []byte      `json:"metadata"`
+	Status           string      `json:"status"`
+	ChatModelID      pgtype.UUID `json:"chat_model_id"`
+	MemoryProviderID pgtype.UUID `json:"memory_provider_id"`
 }
 
 type CreateBotRow struct {
@@ -58,6 +60,8 @@ func (q *Queries) CreateBot(ctx context.Context, arg CreateBotParams) (CreateBot
 		arg.IsActive,
 		arg.Metadata,
 		arg.Status,
+		arg.ChatModelID,
+		arg.MemoryProviderID,
 	)
 	var i CreateBotRow
 	err := row.Scan(
@@ -156,6 +160,59 @@ func (q *Queries) GetBotByID(ctx context.Context, id pgtype.UUID) (GetBotByIDRow
 	return i, err
 }
 
+const getBotDeleteState = `-- name: GetBotDeleteState :one
+SELECT status, delete_requested_at
+FROM bots
+WHERE id = $1
+`
+
+type GetBotDeleteStateRow struct {
+	Status            string             `json:"status"`
+	DeleteRequestedAt pgtype.Timestamptz `json:"delete_requested_at"`
+}
+
+func (q *Queries) GetBotDeleteState(ctx context.Context, id pgtype.UUID) (GetBotDeleteStateRow, error) {
+	row := q.db.QueryRow(ctx, getBotDeleteState, id)
+	var i GetBotDeleteStateRow
+	err := row.Scan(&i.Status, &i.DeleteRequestedAt)
+	return i, err
+}
+
+const listBotActivityByOwner = `-- name: ListBotActivityByOwner :many
+SELECT
+  b.id,
+  (SELECT max(m.created_at) FROM bot_history_messages m WHERE m.bot_id = b.id)::timestamptz AS last_message_at,
+  (SELECT max(l.started_at) FROM schedule_logs l WHERE l.bot_id = b.id)::timestamptz AS last_schedule_run_at
+FROM bots b
+WHERE b.owner_user_id = $1
+`
+
+type ListBotActivityByOwnerRow struct {
+	ID                pgtype.UUID        `json:"id"`
+	LastMessageAt     pgtype.Timestamptz `json:"last_message_at"`
+	LastScheduleRunAt pgtype.Timestamptz `json:"last_schedule_run_at"`
+}
+
+func (q *Queries) ListBotActivityByOwner(ctx context.Context, ownerUserID pgtype.UUID) ([]ListBotActivityByOwnerRow, error) {
+	rows, err := q.db.Query(ctx, listBotActivityByOwner, ownerUserID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListBotActivityByOwnerRow
+	for rows.Next() {
+		var i ListBotActivityByOwnerRow
+		if err := rows.Scan(&i.ID, &i.LastMessageAt, &i.LastScheduleRunAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listBotsByOwner = `-- name: ListBotsByOwner :many
 SELECT id, owner_user_id, display_name, avatar_url, timezone, is_active, status, language, reasoning_enabled, reasoning_effort, chat_model_id, search_provider_id, memory_provider_id, heartbeat_enabled, heartbeat_interval, heartbeat_prompt, metadata, created_at, updated_at
 FROM bots
@@ -225,6 +282,35 @@ func (q *Queries) ListBotsByOwner(ctx context.Context, ownerUserID pgtype.UUID)
 	return items, nil
 }
 
+const listBotsPendingPurge = `-- name: ListBotsPendingPurge :many
+SELECT id
+FROM bots
+WHERE status = 'deleting'
+  AND delete_requested_at IS NOT NULL
+  AND delete_requested_at <= $1::timestamptz
+ORDER BY delete_requested_at ASC
+`
+
+func (q *Queries) ListBotsPendingPurge(ctx context.Context, cutoff pgtype.Timestamptz) ([]pgtype.UUID, error) {
+	rows, err := q.db.Query(ctx, listBotsPendingPurge, cutoff)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []pgtype.UUID
+	for rows.Next() {
+		var id pgtype.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listHeartbeatEnabledBots = `-- name: ListHeartbeatEnabledBots :many
 SELECT id, owner_user_id, heartbeat_enabled, heartbeat_interval, heartbeat_prompt
 FROM bots
@@ -265,6 +351,42 @@ func (q *Queries) ListHeartbeatEnabledBots(ctx context.Context) ([]ListHeartbeat
 	return items, nil
 }
 
+const markBotDeleting = `-- name: MarkBotDeleting :exec
+UPDATE bots
+SET status = 'deleting',
+    delete_requested_at = now(),
+    updated_at = now()
+WHERE id = $1
+`
+
+func (q *Queries) MarkBotDeleting(ctx context.Context, id pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, markBotDeleting, id)
+	return err
+}
+
+const restoreDeletingBot = `-- name: RestoreDeletingBot :execrows
+UPDATE bots
+SET status = 'ready',
+    delete_requested_at = NULL,
+    updated_at = now()
+WHERE id = $1
+  AND status = 'deleting'
+  AND delete_requested_at > $2::timestamptz
+`
+
+type RestoreDeletingBotParams struct {
+	ID     pgtype.UUID        `json:"id"`
+	Cutoff pgtype.Timestamptz `json:"cutoff"`
+}
+
+func (q *Queries) RestoreDeletingBot(ctx context.Context, arg RestoreDeletingBotParams) (int64, error) {
+	result, err := q.db.Exec(ctx, restoreDeletingBot, arg.ID, arg.Cutoff)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const updateBotOwner = `-- name: UpdateBotOwner :one
 UPDATE bots
 SET owner_user_id = $2,
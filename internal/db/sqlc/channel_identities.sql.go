@@ -125,6 +125,36 @@ func (q *Queries) GetChannelIdentityByIDForUpdate(ctx context.Context, id pgtype
 	return i, err
 }
 
+const getChannelIdentityWithUser = `-- name: GetChannelIdentityWithUser :one
+SELECT
+  ci.id,
+  ci.display_name,
+  u.id AS linked_user_id,
+  u.display_name AS user_display_name
+FROM channel_identities ci
+LEFT JOIN users u ON u.id = ci.user_id
+WHERE ci.id = $1
+`
+
+type GetChannelIdentityWithUserRow struct {
+	ID              pgtype.UUID `json:"id"`
+	DisplayName     pgtype.Text `json:"display_name"`
+	LinkedUserID    pgtype.UUID `json:"linked_user_id"`
+	UserDisplayName pgtype.Text `json:"user_display_name"`
+}
+
+func (q *Queries) GetChannelIdentityWithUser(ctx context.Context, id pgtype.UUID) (GetChannelIdentityWithUserRow, error) {
+	row := q.db.QueryRow(ctx, getChannelIdentityWithUser, id)
+	var i GetChannelIdentityWithUserRow
+	err := row.Scan(
+		&i.ID,
+		&i.DisplayName,
+		&i.LinkedUserID,
+		&i.UserDisplayName,
+	)
+	return i, err
+}
+
 const listChannelIdentitiesByUserID = `-- name: ListChannelIdentitiesByUserID :many
 SELECT id, user_id, channel_type, channel_subject_id, display_name, avatar_url, metadata, created_at, updated_at
 FROM channel_identities
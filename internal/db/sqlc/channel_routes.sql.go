@@ -37,6 +37,9 @@ RETURNING
   default_reply_target AS reply_target,
   active_session_id,
   metadata,
+  muted,
+  pinned,
+  annotations,
   created_at,
   updated_at
 `
@@ -65,6 +68,9 @@ type CreateChatRouteRow struct {
 	ReplyTarget      pgtype.Text        `json:"reply_target"`
 	ActiveSessionID  pgtype.UUID        `json:"active_session_id"`
 	Metadata         []byte             `json:"metadata"`
+	Muted            bool               `json:"muted"`
+	Pinned           bool               `json:"pinned"`
+	Annotations      []byte             `json:"annotations"`
 	CreatedAt        pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt        pgtype.Timestamptz `json:"updated_at"`
 }
@@ -94,6 +100,9 @@ func (q *Queries) CreateChatRoute(ctx context.Context, arg CreateChatRouteParams
 		&i.ReplyTarget,
 		&i.ActiveSessionID,
 		&i.Metadata,
+		&i.Muted,
+		&i.Pinned,
+		&i.Annotations,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 	)
@@ -110,6 +119,40 @@ func (q *Queries) DeleteChatRoute(ctx context.Context, id pgtype.UUID) error {
 	return err
 }
 
+const deleteStaleChatRoutes = `-- name: DeleteStaleChatRoutes :execrows
+DELETE FROM bot_channel_routes r
+WHERE r.updated_at < $1::timestamptz
+  AND r.pinned = false
+  AND r.muted = false
+  AND COALESCE(r.annotations->>'notes', '') = ''
+  AND COALESCE(NULLIF(r.annotations->'tags', 'null'::jsonb), '[]'::jsonb) = '[]'::jsonb
+  AND NOT EXISTS (
+    SELECT 1
+    FROM bot_sessions s
+    WHERE s.route_id = r.id
+      AND s.deleted_at IS NULL
+      AND s.updated_at >= $1::timestamptz
+  )
+  AND NOT EXISTS (
+    SELECT 1
+    FROM bot_sessions s
+    JOIN bot_history_messages m ON m.session_id = s.id
+    WHERE s.route_id = r.id
+      AND m.created_at >= $1::timestamptz
+  )
+`
+
+// Removes routes with no activity since the cutoff. Pinned and muted routes
+// and routes carrying operator notes or tags are kept. Sessions of a removed
+// route keep their history; their route_id is cleared by the foreign key.
+func (q *Queries) DeleteStaleChatRoutes(ctx context.Context, cutoff pgtype.Timestamptz) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteStaleChatRoutes, cutoff)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const findChatRoute = `-- name: FindChatRoute :one
 SELECT
   id,
@@ -123,6 +166,9 @@ SELECT
   default_reply_target AS reply_target,
   active_session_id,
   metadata,
+  muted,
+  pinned,
+  annotations,
   created_at,
   updated_at
 FROM bot_channel_routes
@@ -152,6 +198,9 @@ type FindChatRouteRow struct {
 	ReplyTarget      pgtype.Text        `json:"reply_target"`
 	ActiveSessionID  pgtype.UUID        `json:"active_session_id"`
 	Metadata         []byte             `json:"metadata"`
+	Muted            bool               `json:"muted"`
+	Pinned           bool               `json:"pinned"`
+	Annotations      []byte             `json:"annotations"`
 	CreatedAt        pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt        pgtype.Timestamptz `json:"updated_at"`
 }
@@ -176,6 +225,9 @@ func (q *Queries) FindChatRoute(ctx context.Context, arg FindChatRouteParams) (F
 		&i.ReplyTarget,
 		&i.ActiveSessionID,
 		&i.Metadata,
+		&i.Muted,
+		&i.Pinned,
+		&i.Annotations,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 	)
@@ -195,6 +247,9 @@ SELECT
   default_reply_target AS reply_target,
   active_session_id,
   metadata,
+  muted,
+  pinned,
+  annotations,
   created_at,
   updated_at
 FROM bot_channel_routes
@@ -213,6 +268,9 @@ type GetChatRouteByIDRow struct {
 	ReplyTarget      pgtype.Text        `json:"reply_target"`
 	ActiveSessionID  pgtype.UUID        `json:"active_session_id"`
 	Metadata         []byte             `json:"metadata"`
+	Muted            bool               `json:"muted"`
+	Pinned           bool               `json:"pinned"`
+	Annotations      []byte             `json:"annotations"`
 	CreatedAt        pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt        pgtype.Timestamptz `json:"updated_at"`
 }
@@ -232,6 +290,9 @@ func (q *Queries) GetChatRouteByID(ctx context.Context, id pgtype.UUID) (GetChat
 		&i.ReplyTarget,
 		&i.ActiveSessionID,
 		&i.Metadata,
+		&i.Muted,
+		&i.Pinned,
+		&i.Annotations,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 	)
@@ -251,6 +312,9 @@ SELECT
   default_reply_target AS reply_target,
   active_session_id,
   metadata,
+  muted,
+  pinned,
+  annotations,
   created_at,
   updated_at
 FROM bot_channel_routes
@@ -270,6 +334,9 @@ type ListChatRoutesRow struct {
 	ReplyTarget      pgtype.Text        `json:"reply_target"`
 	ActiveSessionID  pgtype.UUID        `json:"active_session_id"`
 	Metadata         []byte             `json:"metadata"`
+	Muted            bool               `json:"muted"`
+	Pinned           bool               `json:"pinned"`
+	Annotations      []byte             `json:"annotations"`
 	CreatedAt        pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt        pgtype.Timestamptz `json:"updated_at"`
 }
@@ -295,6 +362,9 @@ func (q *Queries) ListChatRoutes(ctx context.Context, chatID pgtype.UUID) ([]Lis
 			&i.ReplyTarget,
 			&i.ActiveSessionID,
 			&i.Metadata,
+			&i.Muted,
+			&i.Pinned,
+			&i.Annotations,
 			&i.CreatedAt,
 			&i.UpdatedAt,
 		); err != nil {
@@ -308,6 +378,54 @@ func (q *Queries) ListChatRoutes(ctx context.Context, chatID pgtype.UUID) ([]Lis
 	return items, nil
 }
 
+const setChatRouteAnnotations = `-- name: SetChatRouteAnnotations :exec
+UPDATE bot_channel_routes
+SET annotations = $1, updated_at = now()
+WHERE id = $2
+`
+
+type SetChatRouteAnnotationsParams struct {
+	Annotations []byte      `json:"annotations"`
+	ID          pgtype.UUID `json:"id"`
+}
+
+func (q *Queries) SetChatRouteAnnotations(ctx context.Context, arg SetChatRouteAnnotationsParams) error {
+	_, err := q.db.Exec(ctx, setChatRouteAnnotations, arg.Annotations, arg.ID)
+	return err
+}
+
+const setChatRouteMuted = `-- name: SetChatRouteMuted :exec
+UPDATE bot_channel_routes
+SET muted = $1, updated_at = now()
+WHERE id = $2
+`
+
+type SetChatRouteMutedParams struct {
+	Muted bool        `json:"muted"`
+	ID    pgtype.UUID `json:"id"`
+}
+
+func (q *Queries) SetChatRouteMuted(ctx context.Context, arg SetChatRouteMutedParams) error {
+	_, err := q.db.Exec(ctx, setChatRouteMuted, arg.Muted, arg.ID)
+	return err
+}
+
+const setChatRoutePinned = `-- name: SetChatRoutePinned :exec
+UPDATE bot_channel_routes
+SET pinned = $1, updated_at = now()
+WHERE id = $2
+`
+
+type SetChatRoutePinnedParams struct {
+	Pinned bool        `json:"pinned"`
+	ID     pgtype.UUID `json:"id"`
+}
+
+func (q *Queries) SetChatRoutePinned(ctx context.Context, arg SetChatRoutePinnedParams) error {
+	_, err := q.db.Exec(ctx, setChatRoutePinned, arg.Pinned, arg.ID)
+	return err
+}
+
 const setRouteActiveSession = `-- name: SetRouteActiveSession :exec
 UPDATE bot_channel_routes
 SET active_session_id = $1::uuid, updated_at = now()
@@ -102,6 +102,30 @@ func (q *Queries) GetMemoryProviderByID(ctx context.Context, id pgtype.UUID) (Me
 	return i, err
 }
 
+const listBotIDsByMemoryProvider = `-- name: ListBotIDsByMemoryProvider :many
+SELECT id FROM bots WHERE memory_provider_id = $1 ORDER BY created_at ASC
+`
+
+func (q *Queries) ListBotIDsByMemoryProvider(ctx context.Context, memoryProviderID pgtype.UUID) ([]pgtype.UUID, error) {
+	rows, err := q.db.Query(ctx, listBotIDsByMemoryProvider, memoryProviderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []pgtype.UUID
+	for rows.Next() {
+		var id pgtype.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listMemoryProviders = `-- name: ListMemoryProviders :many
 SELECT id, name, provider, config, is_default, created_at, updated_at FROM memory_providers ORDER BY created_at ASC
 `
@@ -25,7 +25,8 @@ INSERT INTO bot_history_messages (
   usage,
   model_id,
   event_id,
-  display_text
+  display_text,
+  created_at
 )
 VALUES (
   $1,
@@ -40,8 +41,12 @@ VALUES (
   $10,
   $11::uuid,
   $12::uuid,
-  $13::text
+  $13::text,
+  -- clock_timestamp keeps messages written in one transaction in order;
+  -- now() would give them all the transaction start time.
+  clock_timestamp()
 )
+ON CONFLICT DO NOTHING
 RETURNING
   id,
   bot_id,
@@ -148,6 +153,150 @@ func (q *Queries) DeleteMessagesBySession(ctx context.Context, sessionID pgtype.
 	return err
 }
 
+const getMessageByExternalID = `-- name: GetMessageByExternalID :one
+SELECT
+  id,
+  bot_id,
+  session_id,
+  sender_channel_identity_id,
+  sender_account_user_id AS sender_user_id,
+  source_message_id AS external_message_id,
+  source_reply_to_message_id,
+  role,
+  content,
+  metadata,
+  usage,
+  event_id,
+  display_text,
+  created_at
+FROM bot_history_messages
+WHERE bot_id = $1
+  AND session_id = $2
+  AND COALESCE(metadata->>'platform', '') = $3::text
+  AND source_message_id = $4::text
+  AND role = $5
+`
+
+type GetMessageByExternalIDParams struct {
+	BotID             pgtype.UUID `json:"bot_id"`
+	SessionID         pgtype.UUID `json:"session_id"`
+	Platform          string      `json:"platform"`
+	ExternalMessageID string      `json:"external_message_id"`
+	Role              string      `json:"role"`
+}
+
+type GetMessageByExternalIDRow struct {
+	ID                      pgtype.UUID        `json:"id"`
+	BotID                   pgtype.UUID        `json:"bot_id"`
+	SessionID               pgtype.UUID        `json:"session_id"`
+	SenderChannelIdentityID pgtype.UUID        `json:"sender_channel_identity_id"`
+	SenderUserID            pgtype.UUID        `json:"sender_user_id"`
+	ExternalMessageID       pgtype.Text        `json:"external_message_id"`
+	SourceReplyToMessageID  pgtype.Text        `json:"source_reply_to_message_id"`
+	Role                    string             `json:"role"`
+	Content                 []byte             `json:"content"`
+	Metadata                []byte             `json:"metadata"`
+	Usage                   []byte             `json:"usage"`
+	EventID                 pgtype.UUID        `json:"event_id"`
+	DisplayText             pgtype.Text        `json:"display_text"`
+	CreatedAt               pgtype.Timestamptz `json:"created_at"`
+}
+
+// Returns the message already stored for an external platform message in a
+// session, which CreateMessage skips as a duplicate.
+func (q *Queries) GetMessageByExternalID(ctx context.Context, arg GetMessageByExternalIDParams) (GetMessageByExternalIDRow, error) {
+	row := q.db.QueryRow(ctx, getMessageByExternalID,
+		arg.BotID,
+		arg.SessionID,
+		arg.Platform,
+		arg.ExternalMessageID,
+		arg.Role,
+	)
+	var i GetMessageByExternalIDRow
+	err := row.Scan(
+		&i.ID,
+		&i.BotID,
+		&i.SessionID,
+		&i.SenderChannelIdentityID,
+		&i.SenderUserID,
+		&i.ExternalMessageID,
+		&i.SourceReplyToMessageID,
+		&i.Role,
+		&i.Content,
+		&i.Metadata,
+		&i.Usage,
+		&i.EventID,
+		&i.DisplayText,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getMessageByID = `-- name: GetMessageByID :one
+SELECT
+  id,
+  bot_id,
+  session_id,
+  sender_channel_identity_id,
+  sender_account_user_id AS sender_user_id,
+  source_message_id AS external_message_id,
+  source_reply_to_message_id,
+  role,
+  content,
+  metadata,
+  usage,
+  event_id,
+  display_text,
+  created_at
+FROM bot_history_messages
+WHERE id = $1
+  AND bot_id = $2
+`
+
+type GetMessageByIDParams struct {
+	ID    pgtype.UUID `json:"id"`
+	BotID pgtype.UUID `json:"bot_id"`
+}
+
+type GetMessageByIDRow struct {
+	ID                      pgtype.UUID        `json:"id"`
+	BotID                   pgtype.UUID        `json:"bot_id"`
+	SessionID               pgtype.UUID        `json:"session_id"`
+	SenderChannelIdentityID pgtype.UUID        `json:"sender_channel_identity_id"`
+	SenderUserID            pgtype.UUID        `json:"sender_user_id"`
+	ExternalMessageID       pgtype.Text        `json:"external_message_id"`
+	SourceReplyToMessageID  pgtype.Text        `json:"source_reply_to_message_id"`
+	Role                    string             `json:"role"`
+	Content                 []byte             `json:"content"`
+	Metadata                []byte             `json:"metadata"`
+	Usage                   []byte             `json:"usage"`
+	EventID                 pgtype.UUID        `json:"event_id"`
+	DisplayText             pgtype.Text        `json:"display_text"`
+	CreatedAt               pgtype.Timestamptz `json:"created_at"`
+}
+
+func (q *Queries) GetMessageByID(ctx context.Context, arg GetMessageByIDParams) (GetMessageByIDRow, error) {
+	row := q.db.QueryRow(ctx, getMessageByID, arg.ID, arg.BotID)
+	var i GetMessageByIDRow
+	err := row.Scan(
+		&i.ID,
+		&i.BotID,
+		&i.SessionID,
+		&i.SenderChannelIdentityID,
+		&i.SenderUserID,
+		&i.ExternalMessageID,
+		&i.SourceReplyToMessageID,
+		&i.Role,
+		&i.Content,
+		&i.Metadata,
+		&i.Usage,
+		&i.EventID,
+		&i.DisplayText,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
 const listActiveMessagesSince = `-- name: ListActiveMessagesSince :many
 SELECT
   m.id,
@@ -336,6 +485,37 @@ func (q *Queries) ListActiveMessagesSinceBySession(ctx context.Context, arg List
 	return items, nil
 }
 
+const listBotsWithPassiveMessageLimit = `-- name: ListBotsWithPassiveMessageLimit :many
+SELECT id, metadata
+FROM bots
+WHERE metadata->'features' ? 'passive_message_limit'
+`
+
+type ListBotsWithPassiveMessageLimitRow struct {
+	ID       pgtype.UUID `json:"id"`
+	Metadata []byte      `json:"metadata"`
+}
+
+func (q *Queries) ListBotsWithPassiveMessageLimit(ctx context.Context) ([]ListBotsWithPassiveMessageLimitRow, error) {
+	rows, err := q.db.Query(ctx, listBotsWithPassiveMessageLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListBotsWithPassiveMessageLimitRow
+	for rows.Next() {
+		var i ListBotsWithPassiveMessageLimitRow
+		if err := rows.Scan(&i.ID, &i.Metadata); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listMessages = `-- name: ListMessages :many
 SELECT
   m.id,
@@ -1286,6 +1466,120 @@ func (q *Queries) MarkMessagesCompacted(ctx context.Context, arg MarkMessagesCom
 	return err
 }
 
+const prunePassiveMessages = `-- name: PrunePassiveMessages :execrows
+DELETE FROM bot_history_messages
+WHERE id IN (
+  SELECT ranked.id
+  FROM (
+    SELECT
+      m.id,
+      ROW_NUMBER() OVER (PARTITION BY m.session_id ORDER BY m.created_at DESC, m.id DESC) AS position
+    FROM bot_history_messages m
+    WHERE m.bot_id = $1
+      AND m.metadata->>'passive' = 'true'
+      AND NOT EXISTS (
+        SELECT 1
+        FROM bot_sessions s
+        JOIN bot_channel_routes r ON r.id = s.route_id
+        WHERE s.id = m.session_id
+          AND r.pinned
+      )
+  ) ranked
+  WHERE ranked.position > $2::bigint
+)
+`
+
+type PrunePassiveMessagesParams struct {
+	BotID pgtype.UUID `json:"bot_id"`
+	Keep  int64       `json:"keep"`
+}
+
+func (q *Queries) PrunePassiveMessages(ctx context.Context, arg PrunePassiveMessagesParams) (int64, error) {
+	result, err := q.db.Exec(ctx, prunePassiveMessages, arg.BotID, arg.Keep)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const redactMessage = `-- name: RedactMessage :one
+UPDATE bot_history_messages
+SET
+  content = $1,
+  display_text = $2::text,
+  metadata = metadata || $3::jsonb
+WHERE id = $4
+  AND bot_id = $5
+RETURNING
+  id,
+  bot_id,
+  session_id,
+  sender_channel_identity_id,
+  sender_account_user_id AS sender_user_id,
+  source_message_id AS external_message_id,
+  source_reply_to_message_id,
+  role,
+  content,
+  metadata,
+  usage,
+  event_id,
+  display_text,
+  created_at
+`
+
+type RedactMessageParams struct {
+	Content     []byte      `json:"content"`
+	DisplayText pgtype.Text `json:"display_text"`
+	Metadata    []byte      `json:"metadata"`
+	ID          pgtype.UUID `json:"id"`
+	BotID       pgtype.UUID `json:"bot_id"`
+}
+
+type RedactMessageRow struct {
+	ID                      pgtype.UUID        `json:"id"`
+	BotID                   pgtype.UUID        `json:"bot_id"`
+	SessionID               pgtype.UUID        `json:"session_id"`
+	SenderChannelIdentityID pgtype.UUID        `json:"sender_channel_identity_id"`
+	SenderUserID            pgtype.UUID        `json:"sender_user_id"`
+	ExternalMessageID       pgtype.Text        `json:"external_message_id"`
+	SourceReplyToMessageID  pgtype.Text        `json:"source_reply_to_message_id"`
+	Role                    string             `json:"role"`
+	Content                 []byte             `json:"content"`
+	Metadata                []byte             `json:"metadata"`
+	Usage                   []byte             `json:"usage"`
+	EventID                 pgtype.UUID        `json:"event_id"`
+	DisplayText             pgtype.Text        `json:"display_text"`
+	CreatedAt               pgtype.Timestamptz `json:"created_at"`
+}
+
+func (q *Queries) RedactMessage(ctx context.Context, arg RedactMessageParams) (RedactMessageRow, error) {
+	row := q.db.QueryRow(ctx, redactMessage,
+		arg.Content,
+		arg.DisplayText,
+		arg.Metadata,
+		arg.ID,
+		arg.BotID,
+	)
+	var i RedactMessageRow
+	err := row.Scan(
+		&i.ID,
+		&i.BotID,
+		&i.SessionID,
+		&i.SenderChannelIdentityID,
+		&i.SenderUserID,
+		&i.ExternalMessageID,
+		&i.SourceReplyToMessageID,
+		&i.Role,
+		&i.Content,
+		&i.Metadata,
+		&i.Usage,
+		&i.EventID,
+		&i.DisplayText,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
 const searchMessages = `-- name: SearchMessages :many
 SELECT
   m.id,
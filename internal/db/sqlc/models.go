@@ -9,36 +9,44 @@ import (
 )
 
 type Bot struct {
-	ID                  pgtype.UUID        `json:"id"`
-	OwnerUserID         pgtype.UUID        `json:"owner_user_id"`
-	DisplayName         pgtype.Text        `json:"display_name"`
-	AvatarUrl           pgtype.Text        `json:"avatar_url"`
-	Timezone            pgtype.Text        `json:"timezone"`
-	IsActive            bool               `json:"is_active"`
-	Status              string             `json:"status"`
-	Language            string             `json:"language"`
-	ReasoningEnabled    bool               `json:"reasoning_enabled"`
-	ReasoningEffort     string             `json:"reasoning_effort"`
-	ChatModelID         pgtype.UUID        `json:"chat_model_id"`
-	SearchProviderID    pgtype.UUID        `json:"search_provider_id"`
-	MemoryProviderID    pgtype.UUID        `json:"memory_provider_id"`
-	HeartbeatEnabled    bool               `json:"heartbeat_enabled"`
-	HeartbeatInterval   int32              `json:"heartbeat_interval"`
-	HeartbeatPrompt     string             `json:"heartbeat_prompt"`
-	HeartbeatModelID    pgtype.UUID        `json:"heartbeat_model_id"`
-	CompactionEnabled   bool               `json:"compaction_enabled"`
-	CompactionThreshold int32              `json:"compaction_threshold"`
-	CompactionRatio     int32              `json:"compaction_ratio"`
-	CompactionModelID   pgtype.UUID        `json:"compaction_model_id"`
-	TitleModelID        pgtype.UUID        `json:"title_model_id"`
-	ImageModelID        pgtype.UUID        `json:"image_model_id"`
-	DiscussProbeModelID pgtype.UUID        `json:"discuss_probe_model_id"`
-	TtsModelID          pgtype.UUID        `json:"tts_model_id"`
-	BrowserContextID    pgtype.UUID        `json:"browser_context_id"`
-	Metadata            []byte             `json:"metadata"`
-	CreatedAt           pgtype.Timestamptz `json:"created_at"`
-	UpdatedAt           pgtype.Timestamptz `json:"updated_at"`
-	AclDefaultEffect    string             `json:"acl_default_effect"`
+	ID                        pgtype.UUID        `json:"id"`
+	OwnerUserID               pgtype.UUID        `json:"owner_user_id"`
+	DisplayName               pgtype.Text        `json:"display_name"`
+	AvatarUrl                 pgtype.Text        `json:"avatar_url"`
+	Timezone                  pgtype.Text        `json:"timezone"`
+	IsActive                  bool               `json:"is_active"`
+	Status                    string             `json:"status"`
+	Language                  string             `json:"language"`
+	ReasoningEnabled          bool               `json:"reasoning_enabled"`
+	ReasoningEffort           string             `json:"reasoning_effort"`
+	ChatModelID               pgtype.UUID        `json:"chat_model_id"`
+	SearchProviderID          pgtype.UUID        `json:"search_provider_id"`
+	MemoryProviderID          pgtype.UUID        `json:"memory_provider_id"`
+	HeartbeatEnabled          bool               `json:"heartbeat_enabled"`
+	HeartbeatInterval         int32              `json:"heartbeat_interval"`
+	HeartbeatPrompt           string             `json:"heartbeat_prompt"`
+	HeartbeatModelID          pgtype.UUID        `json:"heartbeat_model_id"`
+	CompactionEnabled         bool               `json:"compaction_enabled"`
+	CompactionThreshold       int32              `json:"compaction_threshold"`
+	CompactionRatio           int32              `json:"compaction_ratio"`
+	CompactionModelID         pgtype.UUID        `json:"compaction_model_id"`
+	TitleModelID              pgtype.UUID        `json:"title_model_id"`
+	ImageModelID              pgtype.UUID        `json:"image_model_id"`
+	DiscussProbeModelID       pgtype.UUID        `json:"discuss_probe_model_id"`
+	TtsModelID                pgtype.UUID        `json:"tts_model_id"`
+	BrowserContextID          pgtype.UUID        `json:"browser_context_id"`
+	ContextTokenBudget        pgtype.Int4        `json:"context_token_budget"`
+	SystemPromptReserve       pgtype.Int4        `json:"system_prompt_reserve"`
+	PersistFullToolResults    bool               `json:"persist_full_tool_results"`
+	PersistReasoning          bool               `json:"persist_reasoning"`
+	MonthlyTokenCap           int64              `json:"monthly_token_cap"`
+	MonthlyCostCapUsd         float64            `json:"monthly_cost_cap_usd"`
+	DeleteRequestedAt         pgtype.Timestamptz `json:"delete_requested_at"`
+	SearchFallbackProviderIds []pgtype.UUID      `json:"search_fallback_provider_ids"`
+	Metadata                  []byte             `json:"metadata"`
+	CreatedAt                 pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt                 pgtype.Timestamptz `json:"updated_at"`
+	AclDefaultEffect          string             `json:"acl_default_effect"`
 }
 
 type BotAclRule struct {
@@ -61,6 +69,16 @@ type BotAclRule struct {
 	SubjectChannelType     pgtype.Text        `json:"subject_channel_type"`
 }
 
+type BotBlockedSender struct {
+	BotID             pgtype.UUID        `json:"bot_id"`
+	ChannelIdentityID pgtype.UUID        `json:"channel_identity_id"`
+	Reason            string             `json:"reason"`
+	Notify            bool               `json:"notify"`
+	NotifiedAt        pgtype.Timestamptz `json:"notified_at"`
+	CreatedByUserID   pgtype.UUID        `json:"created_by_user_id"`
+	CreatedAt         pgtype.Timestamptz `json:"created_at"`
+}
+
 type BotChannelConfig struct {
 	ID               pgtype.UUID        `json:"id"`
 	BotID            pgtype.UUID        `json:"bot_id"`
@@ -76,6 +94,20 @@ type BotChannelConfig struct {
 	UpdatedAt        pgtype.Timestamptz `json:"updated_at"`
 }
 
+type BotChannelOutboundRetry struct {
+	ID            pgtype.UUID        `json:"id"`
+	BotID         pgtype.UUID        `json:"bot_id"`
+	ChannelType   string             `json:"channel_type"`
+	Request       []byte             `json:"request"`
+	Status        string             `json:"status"`
+	Attempts      int32              `json:"attempts"`
+	MaxAttempts   int32              `json:"max_attempts"`
+	NextAttemptAt pgtype.Timestamptz `json:"next_attempt_at"`
+	LastError     string             `json:"last_error"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt     pgtype.Timestamptz `json:"updated_at"`
+}
+
 type BotChannelRoute struct {
 	ID                     pgtype.UUID        `json:"id"`
 	BotID                  pgtype.UUID        `json:"bot_id"`
@@ -87,6 +119,9 @@ type BotChannelRoute struct {
 	DefaultReplyTarget     pgtype.Text        `json:"default_reply_target"`
 	ActiveSessionID        pgtype.UUID        `json:"active_session_id"`
 	Metadata               []byte             `json:"metadata"`
+	Muted                  bool               `json:"muted"`
+	Pinned                 bool               `json:"pinned"`
+	Annotations            []byte             `json:"annotations"`
 	CreatedAt              pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt              pgtype.Timestamptz `json:"updated_at"`
 }
@@ -161,6 +196,32 @@ type BotHistoryMessageCompact struct {
 	CompletedAt  pgtype.Timestamptz `json:"completed_at"`
 }
 
+type BotMessageDeliveryReceipt struct {
+	ID                pgtype.UUID        `json:"id"`
+	BotID             pgtype.UUID        `json:"bot_id"`
+	MessageID         pgtype.UUID        `json:"message_id"`
+	ChannelType       string             `json:"channel_type"`
+	Target            string             `json:"target"`
+	PlatformMessageID string             `json:"platform_message_id"`
+	Status            string             `json:"status"`
+	Error             string             `json:"error"`
+	CreatedAt         pgtype.Timestamptz `json:"created_at"`
+}
+
+type BotMessageToolCall struct {
+	ID         pgtype.UUID        `json:"id"`
+	BotID      pgtype.UUID        `json:"bot_id"`
+	SessionID  pgtype.UUID        `json:"session_id"`
+	MessageID  pgtype.UUID        `json:"message_id"`
+	ToolCallID string             `json:"tool_call_id"`
+	ToolName   string             `json:"tool_name"`
+	Arguments  []byte             `json:"arguments"`
+	Result     []byte             `json:"result"`
+	IsError    bool               `json:"is_error"`
+	DurationMs pgtype.Int8        `json:"duration_ms"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+}
+
 type BotSession struct {
 	ID              pgtype.UUID        `json:"id"`
 	BotID           pgtype.UUID        `json:"bot_id"`
@@ -413,17 +474,20 @@ type ProviderOauthToken struct {
 }
 
 type Schedule struct {
-	ID           pgtype.UUID        `json:"id"`
-	Name         string             `json:"name"`
-	Description  string             `json:"description"`
-	Pattern      string             `json:"pattern"`
-	MaxCalls     pgtype.Int4        `json:"max_calls"`
-	CurrentCalls int32              `json:"current_calls"`
-	CreatedAt    pgtype.Timestamptz `json:"created_at"`
-	UpdatedAt    pgtype.Timestamptz `json:"updated_at"`
-	Enabled      bool               `json:"enabled"`
-	Command      string             `json:"command"`
-	BotID        pgtype.UUID        `json:"bot_id"`
+	ID                pgtype.UUID        `json:"id"`
+	Name              string             `json:"name"`
+	Description       string             `json:"description"`
+	Pattern           string             `json:"pattern"`
+	MaxCalls          pgtype.Int4        `json:"max_calls"`
+	CurrentCalls      int32              `json:"current_calls"`
+	CreatedAt         pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt         pgtype.Timestamptz `json:"updated_at"`
+	Enabled           bool               `json:"enabled"`
+	Command           string             `json:"command"`
+	BotID             pgtype.UUID        `json:"bot_id"`
+	MaxRuntimeSeconds pgtype.Int4        `json:"max_runtime_seconds"`
+	Condition         string             `json:"condition"`
+	SkippedCalls      int32              `json:"skipped_calls"`
 }
 
 type ScheduleLog struct {
@@ -470,6 +534,18 @@ type StorageProvider struct {
 	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
 }
 
+type Task struct {
+	ID        string             `json:"id"`
+	BotID     string             `json:"bot_id"`
+	Name      string             `json:"name"`
+	Command   string             `json:"command"`
+	Status    string             `json:"status"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+	ExecID    pgtype.Text        `json:"exec_id"`
+	Pid       pgtype.Int4        `json:"pid"`
+}
+
 type TtsModel struct {
 	ID            pgtype.UUID        `json:"id"`
 	ModelID       string             `json:"model_id"`
@@ -11,20 +11,46 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const countBotUserMessagesSince = `-- name: CountBotUserMessagesSince :one
+SELECT count(*)
+FROM bot_history_messages m
+LEFT JOIN bot_sessions s ON s.id = m.session_id
+WHERE m.bot_id = $1
+  AND m.role = 'user'
+  AND m.created_at > $2
+  AND (s.type IS NULL OR s.type IN ('chat', 'discuss'))
+`
+
+type CountBotUserMessagesSinceParams struct {
+	BotID pgtype.UUID        `json:"bot_id"`
+	Since pgtype.Timestamptz `json:"since"`
+}
+
+// Counts user messages in conversation sessions, leaving out the messages
+// written by schedule and heartbeat runs themselves.
+func (q *Queries) CountBotUserMessagesSince(ctx context.Context, arg CountBotUserMessagesSinceParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countBotUserMessagesSince, arg.BotID, arg.Since)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createSchedule = `-- name: CreateSchedule :one
-INSERT INTO schedule (name, description, pattern, max_calls, enabled, command, bot_id)
-VALUES ($1, $2, $3, $4, $5, $6, $7)
-RETURNING id, name, description, pattern, max_calls, current_calls, created_at, updated_at, enabled, command, bot_id
+INSERT INTO schedule (name, description, pattern, max_calls, enabled, command, bot_id, max_runtime_seconds, condition)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING id, name, description, pattern, max_calls, current_calls, created_at, updated_at, enabled, command, bot_id, max_runtime_seconds, condition, skipped_calls
 `
 
 type CreateScheduleParams struct {
-	Name        string      `json:"name"`
-	Description string      `json:"description"`
-	Pattern     string      `json:"pattern"`
-	MaxCalls    pgtype.Int4 `json:"max_calls"`
-	Enabled     bool        `json:"enabled"`
-	Command     string      `json:"command"`
-	BotID       pgtype.UUID `json:"bot_id"`
+	Name              string      `json:"name"`
+	Description       string      `json:"description"`
+	Pattern           string      `json:"pattern"`
+	MaxCalls          pgtype.Int4 `json:"max_calls"`
+	Enabled           bool        `json:"enabled"`
+	Command           string      `json:"command"`
+	BotID             pgtype.UUID `json:"bot_id"`
+	MaxRuntimeSeconds pgtype.Int4 `json:"max_runtime_seconds"`
+	Condition         string      `json:"condition"`
 }
 
 func (q *Queries) CreateSchedule(ctx context.Context, arg CreateScheduleParams) (Schedule, error) {
@@ -36,6 +62,8 @@ func (q *Queries) CreateSchedule(ctx context.Context, arg CreateScheduleParams)
 		arg.Enabled,
 		arg.Command,
 		arg.BotID,
+		arg.MaxRuntimeSeconds,
+		arg.Condition,
 	)
 	var i Schedule
 	err := row.Scan(
@@ -50,6 +78,9 @@ func (q *Queries) CreateSchedule(ctx context.Context, arg CreateScheduleParams)
 		&i.Enabled,
 		&i.Command,
 		&i.BotID,
+		&i.MaxRuntimeSeconds,
+		&i.Condition,
+		&i.SkippedCalls,
 	)
 	return i, err
 }
@@ -65,7 +96,7 @@ func (q *Queries) DeleteSchedule(ctx context.Context, id pgtype.UUID) error {
 }
 
 const getScheduleByID = `-- name: GetScheduleByID :one
-SELECT id, name, description, pattern, max_calls, current_calls, created_at, updated_at, enabled, command, bot_id
+SELECT id, name, description, pattern, max_calls, current_calls, created_at, updated_at, enabled, command, bot_id, max_runtime_seconds, condition, skipped_calls
 FROM schedule
 WHERE id = $1
 `
@@ -85,6 +116,9 @@ func (q *Queries) GetScheduleByID(ctx context.Context, id pgtype.UUID) (Schedule
 		&i.Enabled,
 		&i.Command,
 		&i.BotID,
+		&i.MaxRuntimeSeconds,
+		&i.Condition,
+		&i.SkippedCalls,
 	)
 	return i, err
 }
@@ -98,7 +132,7 @@ SET current_calls = current_calls + 1,
     END,
     updated_at = now()
 WHERE id = $1
-RETURNING id, name, description, pattern, max_calls, current_calls, created_at, updated_at, enabled, command, bot_id
+RETURNING id, name, description, pattern, max_calls, current_calls, created_at, updated_at, enabled, command, bot_id, max_runtime_seconds, condition, skipped_calls
 `
 
 func (q *Queries) IncrementScheduleCalls(ctx context.Context, id pgtype.UUID) (Schedule, error) {
@@ -116,14 +150,30 @@ func (q *Queries) IncrementScheduleCalls(ctx context.Context, id pgtype.UUID) (S
 		&i.Enabled,
 		&i.Command,
 		&i.BotID,
+		&i.MaxRuntimeSeconds,
+		&i.Condition,
+		&i.SkippedCalls,
 	)
 	return i, err
 }
 
+const incrementScheduleSkips = `-- name: IncrementScheduleSkips :exec
+UPDATE schedule
+SET skipped_calls = skipped_calls + 1,
+    updated_at = now()
+WHERE id = $1
+`
+
+func (q *Queries) IncrementScheduleSkips(ctx context.Context, id pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, incrementScheduleSkips, id)
+	return err
+}
+
 const listEnabledSchedules = `-- name: ListEnabledSchedules :many
-SELECT id, name, description, pattern, max_calls, current_calls, created_at, updated_at, enabled, command, bot_id
+SELECT id, name, description, pattern, max_calls, current_calls, created_at, updated_at, enabled, command, bot_id, max_runtime_seconds, condition, skipped_calls
 FROM schedule
 WHERE enabled = true
+  AND bot_id NOT IN (SELECT id FROM bots WHERE status = 'deleting')
 ORDER BY created_at DESC
 `
 
@@ -148,6 +198,9 @@ func (q *Queries) ListEnabledSchedules(ctx context.Context) ([]Schedule, error)
 			&i.Enabled,
 			&i.Command,
 			&i.BotID,
+			&i.MaxRuntimeSeconds,
+			&i.Condition,
+			&i.SkippedCalls,
 		); err != nil {
 			return nil, err
 		}
@@ -160,7 +213,7 @@ func (q *Queries) ListEnabledSchedules(ctx context.Context) ([]Schedule, error)
 }
 
 const listSchedulesByBot = `-- name: ListSchedulesByBot :many
-SELECT id, name, description, pattern, max_calls, current_calls, created_at, updated_at, enabled, command, bot_id
+SELECT id, name, description, pattern, max_calls, current_calls, created_at, updated_at, enabled, command, bot_id, max_runtime_seconds, condition, skipped_calls
 FROM schedule
 WHERE bot_id = $1
 ORDER BY created_at DESC
@@ -187,6 +240,9 @@ func (q *Queries) ListSchedulesByBot(ctx context.Context, botID pgtype.UUID) ([]
 			&i.Enabled,
 			&i.Command,
 			&i.BotID,
+			&i.MaxRuntimeSeconds,
+			&i.Condition,
+			&i.SkippedCalls,
 		); err != nil {
 			return nil, err
 		}
@@ -206,19 +262,23 @@ SET name = $2,
     max_calls = $5,
     enabled = $6,
     command = $7,
+    max_runtime_seconds = $8,
+    condition = $9,
     updated_at = now()
 WHERE id = $1
-RETURNING id, name, description, pattern, max_calls, current_calls, created_at, updated_at, enabled, command, bot_id
+RETURNING id, name, description, pattern, max_calls, current_calls, created_at, updated_at, enabled, command, bot_id, max_runtime_seconds, condition, skipped_calls
 `
 
 type UpdateScheduleParams struct {
-	ID          pgtype.UUID `json:"id"`
-	Name        string      `json:"name"`
-	Description string      `json:"description"`
-	Pattern     string      `json:"pattern"`
-	MaxCalls    pgtype.Int4 `json:"max_calls"`
-	Enabled     bool        `json:"enabled"`
-	Command     string      `json:"command"`
+	ID                pgtype.UUID `json:"id"`
+	Name              string      `json:"name"`
+	Description       string      `json:"description"`
+	Pattern           string      `json:"pattern"`
+	MaxCalls          pgtype.Int4 `json:"max_calls"`
+	Enabled           bool        `json:"enabled"`
+	Command           string      `json:"command"`
+	MaxRuntimeSeconds pgtype.Int4 `json:"max_runtime_seconds"`
+	Condition         string      `json:"condition"`
 }
 
 func (q *Queries) UpdateSchedule(ctx context.Context, arg UpdateScheduleParams) (Schedule, error) {
@@ -230,6 +290,8 @@ func (q *Queries) UpdateSchedule(ctx context.Context, arg UpdateScheduleParams)
 		arg.MaxCalls,
 		arg.Enabled,
 		arg.Command,
+		arg.MaxRuntimeSeconds,
+		arg.Condition,
 	)
 	var i Schedule
 	err := row.Scan(
@@ -244,6 +306,9 @@ func (q *Queries) UpdateSchedule(ctx context.Context, arg UpdateScheduleParams)
 		&i.Enabled,
 		&i.Command,
 		&i.BotID,
+		&i.MaxRuntimeSeconds,
+		&i.Condition,
+		&i.SkippedCalls,
 	)
 	return i, err
 }
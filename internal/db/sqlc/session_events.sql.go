@@ -62,6 +62,23 @@ func (q *Queries) CreateSessionEvent(ctx context.Context, arg CreateSessionEvent
 	return id, err
 }
 
+const getSessionEventData = `-- name: GetSessionEventData :one
+SELECT session_id, event_data FROM bot_session_events
+WHERE id = $1
+`
+
+type GetSessionEventDataRow struct {
+	SessionID pgtype.UUID `json:"session_id"`
+	EventData []byte      `json:"event_data"`
+}
+
+func (q *Queries) GetSessionEventData(ctx context.Context, id pgtype.UUID) (GetSessionEventDataRow, error) {
+	row := q.db.QueryRow(ctx, getSessionEventData, id)
+	var i GetSessionEventDataRow
+	err := row.Scan(&i.SessionID, &i.EventData)
+	return i, err
+}
+
 const listSessionEventsBySession = `-- name: ListSessionEventsBySession :many
 SELECT id, bot_id, session_id, event_kind, event_data, external_message_id, sender_channel_identity_id, received_at_ms, created_at FROM bot_session_events
 WHERE session_id = $1
@@ -138,3 +155,19 @@ func (q *Queries) ListSessionEventsBySessionAfter(ctx context.Context, arg ListS
 	}
 	return items, nil
 }
+
+const updateSessionEventData = `-- name: UpdateSessionEventData :exec
+UPDATE bot_session_events
+SET event_data = $2
+WHERE id = $1
+`
+
+type UpdateSessionEventDataParams struct {
+	ID        pgtype.UUID `json:"id"`
+	EventData []byte      `json:"event_data"`
+}
+
+func (q *Queries) UpdateSessionEventData(ctx context.Context, arg UpdateSessionEventDataParams) error {
+	_, err := q.db.Exec(ctx, updateSessionEventData, arg.ID, arg.EventData)
+	return err
+}
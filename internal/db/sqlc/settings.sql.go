@@ -28,11 +28,16 @@ SET language = 'auto',
     title_model_id = NULL,
     image_model_id = NULL,
     search_provider_id = NULL,
+    search_fallback_provider_ids = '{}',
     memory_provider_id = NULL,
     tts_model_id = NULL,
     browser_context_id = NULL,
     context_token_budget = NULL,
+    system_prompt_reserve = NULL,
     persist_full_tool_results = false,
+    persist_reasoning = false,
+    monthly_token_cap = 0,
+    monthly_cost_cap_usd = 0,
     updated_at = now()
 WHERE id = $1
 `
@@ -65,7 +70,12 @@ SELECT
   tts_models.id AS tts_model_id,
   browser_contexts.id AS browser_context_id,
   bots.context_token_budget,
-  bots.persist_full_tool_results
+  bots.system_prompt_reserve,
+  bots.persist_full_tool_results,
+  bots.persist_reasoning,
+  bots.monthly_token_cap,
+  bots.monthly_cost_cap_usd,
+  bots.search_fallback_provider_ids
 FROM bots
 LEFT JOIN models AS chat_models ON chat_models.id = bots.chat_model_id
 LEFT JOIN models AS heartbeat_models ON heartbeat_models.id = bots.heartbeat_model_id
@@ -80,28 +90,33 @@ WHERE bots.id = $1
 `
 
 type GetSettingsByBotIDRow struct {
-	BotID                  pgtype.UUID `json:"bot_id"`
-	Language               string      `json:"language"`
-	ReasoningEnabled       bool        `json:"reasoning_enabled"`
-	ReasoningEffort        string      `json:"reasoning_effort"`
-	HeartbeatEnabled       bool        `json:"heartbeat_enabled"`
-	HeartbeatInterval      int32       `json:"heartbeat_interval"`
-	HeartbeatPrompt        string      `json:"heartbeat_prompt"`
-	CompactionEnabled      bool        `json:"compaction_enabled"`
-	CompactionThreshold    int32       `json:"compaction_threshold"`
-	CompactionRatio        int32       `json:"compaction_ratio"`
-	Timezone               pgtype.Text `json:"timezone"`
-	ChatModelID            pgtype.UUID `json:"chat_model_id"`
-	HeartbeatModelID       pgtype.UUID `json:"heartbeat_model_id"`
-	CompactionModelID      pgtype.UUID `json:"compaction_model_id"`
-	TitleModelID           pgtype.UUID `json:"title_model_id"`
-	SearchProviderID       pgtype.UUID `json:"search_provider_id"`
-	MemoryProviderID       pgtype.UUID `json:"memory_provider_id"`
-	ImageModelID           pgtype.UUID `json:"image_model_id"`
-	TtsModelID             pgtype.UUID `json:"tts_model_id"`
-	BrowserContextID       pgtype.UUID `json:"browser_context_id"`
-	ContextTokenBudget     pgtype.Int4 `json:"context_token_budget"`
-	PersistFullToolResults bool        `json:"persist_full_tool_results"`
+	BotID                     pgtype.UUID   `json:"bot_id"`
+	Language                  string        `json:"language"`
+	ReasoningEnabled          bool          `json:"reasoning_enabled"`
+	ReasoningEffort           string        `json:"reasoning_effort"`
+	HeartbeatEnabled          bool          `json:"heartbeat_enabled"`
+	HeartbeatInterval         int32         `json:"heartbeat_interval"`
+	HeartbeatPrompt           string        `json:"heartbeat_prompt"`
+	CompactionEnabled         bool          `json:"compaction_enabled"`
+	CompactionThreshold       int32         `json:"compaction_threshold"`
+	CompactionRatio           int32         `json:"compaction_ratio"`
+	Timezone                  pgtype.Text   `json:"timezone"`
+	ChatModelID               pgtype.UUID   `json:"chat_model_id"`
+	HeartbeatModelID          pgtype.UUID   `json:"heartbeat_model_id"`
+	CompactionModelID         pgtype.UUID   `json:"compaction_model_id"`
+	TitleModelID              pgtype.UUID   `json:"title_model_id"`
+	SearchProviderID          pgtype.UUID   `json:"search_provider_id"`
+	MemoryProviderID          pgtype.UUID   `json:"memory_provider_id"`
+	ImageModelID              pgtype.UUID   `json:"image_model_id"`
+	TtsModelID                pgtype.UUID   `json:"tts_model_id"`
+	BrowserContextID          pgtype.UUID   `json:"browser_context_id"`
+	ContextTokenBudget        pgtype.Int4   `json:"context_token_budget"`
+	SystemPromptReserve       pgtype.Int4   `json:"system_prompt_reserve"`
+	PersistFullToolResults    bool          `json:"persist_full_tool_results"`
+	PersistReasoning          bool          `json:"persist_reasoning"`
+	MonthlyTokenCap           int64         `json:"monthly_token_cap"`
+	MonthlyCostCapUsd         float64       `json:"monthly_cost_cap_usd"`
+	SearchFallbackProviderIds []pgtype.UUID `json:"search_fallback_provider_ids"`
 }
 
 func (q *Queries) GetSettingsByBotID(ctx context.Context, id pgtype.UUID) (GetSettingsByBotIDRow, error) {
@@ -129,7 +144,12 @@ func (q *Queries) GetSettingsByBotID(ctx context.Context, id pgtype.UUID) (GetSe
 		&i.TtsModelID,
 		&i.BrowserContextID,
 		&i.ContextTokenBudget,
+		&i.SystemPromptReserve,
 		&i.PersistFullToolResults,
+		&i.PersistReasoning,
+		&i.MonthlyTokenCap,
+		&i.MonthlyCostCapUsd,
+		&i.SearchFallbackProviderIds,
 	)
 	return i, err
 }
@@ -152,15 +172,20 @@ WITH updated AS (
       compaction_model_id = COALESCE($13::uuid, bots.compaction_model_id),
       title_model_id = COALESCE($14::uuid, bots.title_model_id),
       search_provider_id = COALESCE($15::uuid, bots.search_provider_id),
-      memory_provider_id = COALESCE($16::uuid, bots.memory_provider_id),
-      image_model_id = COALESCE($17::uuid, bots.image_model_id),
-      tts_model_id = COALESCE($18::uuid, bots.tts_model_id),
-      browser_context_id = COALESCE($19::uuid, bots.browser_context_id),
-      context_token_budget = COALESCE($20, bots.context_token_budget),
-      persist_full_tool_results = $21,
+      search_fallback_provider_ids = COALESCE($16::uuid[], bots.search_fallback_provider_ids),
+      memory_provider_id = COALESCE($17::uuid, bots.memory_provider_id),
+      image_model_id = COALESCE($18::uuid, bots.image_model_id),
+      tts_model_id = COALESCE($19::uuid, bots.tts_model_id),
+      browser_context_id = COALESCE($20::uuid, bots.browser_context_id),
+      context_token_budget = COALESCE($21, bots.context_token_budget),
+      system_prompt_reserve = COALESCE($22, bots.system_prompt_reserve),
+      persist_full_tool_results = $23,
+      persist_reasoning = $24,
+      monthly_token_cap = $25,
+      monthly_cost_cap_usd = $26,
       updated_at = now()
-  WHERE bots.id = $22
-  RETURNING bots.id, bots.language, bots.reasoning_enabled, bots.reasoning_effort, bots.heartbeat_enabled, bots.heartbeat_interval, bots.heartbeat_prompt, bots.compaction_enabled, bots.compaction_threshold, bots.compaction_ratio, bots.timezone, bots.chat_model_id, bots.heartbeat_model_id, bots.compaction_model_id, bots.title_model_id, bots.image_model_id, bots.search_provider_id, bots.memory_provider_id, bots.tts_model_id, bots.browser_context_id, bots.context_token_budget, bots.persist_full_tool_results
+  WHERE bots.id = $27
+  RETURNING bots.id, bots.language, bots.reasoning_enabled, bots.reasoning_effort, bots.heartbeat_enabled, bots.heartbeat_interval, bots.heartbeat_prompt, bots.compaction_enabled, bots.compaction_threshold, bots.compaction_ratio, bots.timezone, bots.chat_model_id, bots.heartbeat_model_id, bots.compaction_model_id, bots.title_model_id, bots.image_model_id, bots.search_provider_id, bots.memory_provider_id, bots.tts_model_id, bots.browser_context_id, bots.context_token_budget, bots.system_prompt_reserve, bots.persist_full_tool_results, bots.persist_reasoning, bots.monthly_token_cap, bots.monthly_cost_cap_usd, bots.search_fallback_provider_ids
 )
 SELECT
   updated.id AS bot_id,
@@ -184,7 +209,12 @@ SELECT
   tts_models.id AS tts_model_id,
   browser_contexts.id AS browser_context_id,
   updated.context_token_budget,
-  updated.persist_full_tool_results
+  updated.system_prompt_reserve,
+  updated.persist_full_tool_results,
+  updated.persist_reasoning,
+  updated.monthly_token_cap,
+  updated.monthly_cost_cap_usd,
+  updated.search_fallback_provider_ids
 FROM updated
 LEFT JOIN models AS chat_models ON chat_models.id = updated.chat_model_id
 LEFT JOIN models AS heartbeat_models ON heartbeat_models.id = updated.heartbeat_model_id
@@ -198,53 +228,63 @@ LEFT JOIN browser_contexts ON browser_contexts.id = updated.browser_context_id
 `
 
 type UpsertBotSettingsParams struct {
-	Language               string      `json:"language"`
-	ReasoningEnabled       bool        `json:"reasoning_enabled"`
-	ReasoningEffort        string      `json:"reasoning_effort"`
-	HeartbeatEnabled       bool        `json:"heartbeat_enabled"`
-	HeartbeatInterval      int32       `json:"heartbeat_interval"`
-	HeartbeatPrompt        string      `json:"heartbeat_prompt"`
-	CompactionEnabled      bool        `json:"compaction_enabled"`
-	CompactionThreshold    int32       `json:"compaction_threshold"`
-	CompactionRatio        int32       `json:"compaction_ratio"`
-	Timezone               pgtype.Text `json:"timezone"`
-	ChatModelID            pgtype.UUID `json:"chat_model_id"`
-	HeartbeatModelID       pgtype.UUID `json:"heartbeat_model_id"`
-	CompactionModelID      pgtype.UUID `json:"compaction_model_id"`
-	TitleModelID           pgtype.UUID `json:"title_model_id"`
-	SearchProviderID       pgtype.UUID `json:"search_provider_id"`
-	MemoryProviderID       pgtype.UUID `json:"memory_provider_id"`
-	ImageModelID           pgtype.UUID `json:"image_model_id"`
-	TtsModelID             pgtype.UUID `json:"tts_model_id"`
-	BrowserContextID       pgtype.UUID `json:"browser_context_id"`
-	ContextTokenBudget     pgtype.Int4 `json:"context_token_budget"`
-	PersistFullToolResults bool        `json:"persist_full_tool_results"`
-	ID                     pgtype.UUID `json:"id"`
+	Language                  string        `json:"language"`
+	ReasoningEnabled          bool          `json:"reasoning_enabled"`
+	ReasoningEffort           string        `json:"reasoning_effort"`
+	HeartbeatEnabled          bool          `json:"heartbeat_enabled"`
+	HeartbeatInterval         int32         `json:"heartbeat_interval"`
+	HeartbeatPrompt           string        `json:"heartbeat_prompt"`
+	CompactionEnabled         bool          `json:"compaction_enabled"`
+	CompactionThreshold       int32         `json:"compaction_threshold"`
+	CompactionRatio           int32         `json:"compaction_ratio"`
+	Timezone                  pgtype.Text   `json:"timezone"`
+	ChatModelID               pgtype.UUID   `json:"chat_model_id"`
+	HeartbeatModelID          pgtype.UUID   `json:"heartbeat_model_id"`
+	CompactionModelID         pgtype.UUID   `json:"compaction_model_id"`
+	TitleModelID              pgtype.UUID   `json:"title_model_id"`
+	SearchProviderID          pgtype.UUID   `json:"search_provider_id"`
+	SearchFallbackProviderIds []pgtype.UUID `json:"search_fallback_provider_ids"`
+	MemoryProviderID          pgtype.UUID   `json:"memory_provider_id"`
+	ImageModelID              pgtype.UUID   `json:"image_model_id"`
+	TtsModelID                pgtype.UUID   `json:"tts_model_id"`
+	BrowserContextID          pgtype.UUID   `json:"browser_context_id"`
+	ContextTokenBudget        pgtype.Int4   `json:"context_token_budget"`
+	SystemPromptReserve       pgtype.Int4   `json:"system_prompt_reserve"`
+	PersistFullToolResults    bool          `json:"persist_full_tool_results"`
+	PersistReasoning          bool          `json:"persist_reasoning"`
+	MonthlyTokenCap           int64         `json:"monthly_token_cap"`
+	MonthlyCostCapUsd         float64       `json:"monthly_cost_cap_usd"`
+	ID                        pgtype.UUID   `json:"id"`
 }
 
 type UpsertBotSettingsRow struct {
-	BotID                  pgtype.UUID `json:"bot_id"`
-	Language               string      `json:"language"`
-	ReasoningEnabled       bool        `json:"reasoning_enabled"`
-	ReasoningEffort        string      `json:"reasoning_effort"`
-	HeartbeatEnabled       bool        `json:"heartbeat_enabled"`
-	HeartbeatInterval      int32       `json:"heartbeat_interval"`
-	HeartbeatPrompt        string      `json:"heartbeat_prompt"`
-	CompactionEnabled      bool        `json:"compaction_enabled"`
-	CompactionThreshold    int32       `json:"compaction_threshold"`
-	CompactionRatio        int32       `json:"compaction_ratio"`
-	Timezone               pgtype.Text `json:"timezone"`
-	ChatModelID            pgtype.UUID `json:"chat_model_id"`
-	HeartbeatModelID       pgtype.UUID `json:"heartbeat_model_id"`
-	CompactionModelID      pgtype.UUID `json:"compaction_model_id"`
-	TitleModelID           pgtype.UUID `json:"title_model_id"`
-	SearchProviderID       pgtype.UUID `json:"search_provider_id"`
-	MemoryProviderID       pgtype.UUID `json:"memory_provider_id"`
-	ImageModelID           pgtype.UUID `json:"image_model_id"`
-	TtsModelID             pgtype.UUID `json:"tts_model_id"`
-	BrowserContextID       pgtype.UUID `json:"browser_context_id"`
-	ContextTokenBudget     pgtype.Int4 `json:"context_token_budget"`
-	PersistFullToolResults bool        `json:"persist_full_tool_results"`
+	BotID                     pgtype.UUID   `json:"bot_id"`
+	Language                  string        `json:"language"`
+	ReasoningEnabled          bool          `json:"reasoning_enabled"`
+	ReasoningEffort           string        `json:"reasoning_effort"`
+	HeartbeatEnabled          bool          `json:"heartbeat_enabled"`
+	HeartbeatInterval         int32         `json:"heartbeat_interval"`
+	HeartbeatPrompt           string        `json:"heartbeat_prompt"`
+	CompactionEnabled         bool          `json:"compaction_enabled"`
+	CompactionThreshold       int32         `json:"compaction_threshold"`
+	CompactionRatio           int32         `json:"compaction_ratio"`
+	Timezone                  pgtype.Text   `json:"timezone"`
+	ChatModelID               pgtype.UUID   `json:"chat_model_id"`
+	HeartbeatModelID          pgtype.UUID   `json:"heartbeat_model_id"`
+	CompactionModelID         pgtype.UUID   `json:"compaction_model_id"`
+	TitleModelID              pgtype.UUID   `json:"title_model_id"`
+	SearchProviderID          pgtype.UUID   `json:"search_provider_id"`
+	MemoryProviderID          pgtype.UUID   `json:"memory_provider_id"`
+	ImageModelID              pgtype.UUID   `json:"image_model_id"`
+	TtsModelID                pgtype.UUID   `json:"tts_model_id"`
+	BrowserContextID          pgtype.UUID   `json:"browser_context_id"`
+	ContextTokenBudget        pgtype.Int4   `json:"context_token_budget"`
+	SystemPromptReserve       pgtype.Int4   `json:"system_prompt_reserve"`
+	PersistFullToolResults    bool          `json:"persist_full_tool_results"`
+	PersistReasoning          bool          `json:"persist_reasoning"`
+	MonthlyTokenCap           int64         `json:"monthly_token_cap"`
+	MonthlyCostCapUsd         float64       `json:"monthly_cost_cap_usd"`
+	SearchFallbackProviderIds []pgtype.UUID `json:"search_fallback_provider_ids"`
 }
 
 func (q *Queries) UpsertBotSettings(ctx context.Context, arg UpsertBotSettingsParams) (UpsertBotSettingsRow, error) {
@@ -264,12 +304,17 @@ func (q *Queries) UpsertBotSettings(ctx context.Context, arg UpsertBotSettingsPa
 		arg.CompactionModelID,
 		arg.TitleModelID,
 		arg.SearchProviderID,
+		arg.SearchFallbackProviderIds,
 		arg.MemoryProviderID,
 		arg.ImageModelID,
 		arg.TtsModelID,
 		arg.BrowserContextID,
 		arg.ContextTokenBudget,
+		arg.SystemPromptReserve,
 		arg.PersistFullToolResults,
+		arg.PersistReasoning,
+		arg.MonthlyTokenCap,
+		arg.MonthlyCostCapUsd,
 		arg.ID,
 	)
 	var i UpsertBotSettingsRow
@@ -295,7 +340,12 @@ func (q *Queries) UpsertBotSettings(ctx context.Context, arg UpsertBotSettingsPa
 		&i.TtsModelID,
 		&i.BrowserContextID,
 		&i.ContextTokenBudget,
+		&i.SystemPromptReserve,
 		&i.PersistFullToolResults,
+		&i.PersistReasoning,
+		&i.MonthlyTokenCap,
+		&i.MonthlyCostCapUsd,
+		&i.SearchFallbackProviderIds,
 	)
 	return i, err
 }
@@ -145,3 +145,104 @@ func (q *Queries) GetTokenUsageByModel(ctx context.Context, arg GetTokenUsageByM
 	}
 	return items, nil
 }
+
+const getTokenUsageReport = `-- name: GetTokenUsageReport :many
+SELECT
+  m.bot_id,
+  COALESCE(b.display_name, '')::text AS bot_name,
+  attr.user_id,
+  COALESCE(u.display_name, u.username, '')::text AS user_name,
+  date_trunc('day', m.created_at)::date AS day,
+  m.model_id,
+  COALESCE(mo.model_id, 'unknown') AS model_slug,
+  COALESCE(SUM((m.usage->>'inputTokens')::bigint), 0)::bigint AS input_tokens,
+  COALESCE(SUM((m.usage->>'outputTokens')::bigint), 0)::bigint AS output_tokens,
+  COALESCE(SUM((m.usage->'inputTokenDetails'->>'cacheReadTokens')::bigint), 0)::bigint AS cache_read_tokens,
+  COALESCE(SUM((m.usage->'inputTokenDetails'->>'cacheWriteTokens')::bigint), 0)::bigint AS cache_write_tokens,
+  COALESCE(SUM((m.usage->'outputTokenDetails'->>'reasoningTokens')::bigint), 0)::bigint AS reasoning_tokens
+FROM bot_history_messages m
+JOIN bots b ON b.id = m.bot_id
+LEFT JOIN LATERAL (
+  SELECT um.sender_account_user_id AS user_id
+  FROM bot_history_messages um
+  WHERE um.bot_id = m.bot_id
+    AND um.session_id IS NOT DISTINCT FROM m.session_id
+    AND um.role = 'user'
+    AND um.sender_account_user_id IS NOT NULL
+    AND um.created_at <= m.created_at
+  ORDER BY um.created_at DESC
+  LIMIT 1
+) attr ON true
+LEFT JOIN users u ON u.id = attr.user_id
+LEFT JOIN models mo ON mo.id = m.model_id
+WHERE m.usage IS NOT NULL
+  AND m.created_at >= $1
+  AND m.created_at < $2
+  AND ($3::uuid IS NULL OR m.bot_id = $3::uuid)
+  AND ($4::uuid IS NULL OR attr.user_id = $4::uuid)
+GROUP BY m.bot_id, b.display_name, attr.user_id, u.display_name, u.username, day, m.model_id, mo.model_id
+ORDER BY day, m.bot_id, attr.user_id, model_slug
+`
+
+type GetTokenUsageReportParams struct {
+	FromTime pgtype.Timestamptz `json:"from_time"`
+	ToTime   pgtype.Timestamptz `json:"to_time"`
+	BotID    pgtype.UUID        `json:"bot_id"`
+	UserID   pgtype.UUID        `json:"user_id"`
+}
+
+type GetTokenUsageReportRow struct {
+	BotID            pgtype.UUID `json:"bot_id"`
+	BotName          string      `json:"bot_name"`
+	UserID           pgtype.UUID `json:"user_id"`
+	UserName         string      `json:"user_name"`
+	Day              pgtype.Date `json:"day"`
+	ModelID          pgtype.UUID `json:"model_id"`
+	ModelSlug        string      `json:"model_slug"`
+	InputTokens      int64       `json:"input_tokens"`
+	OutputTokens     int64       `json:"output_tokens"`
+	CacheReadTokens  int64       `json:"cache_read_tokens"`
+	CacheWriteTokens int64       `json:"cache_write_tokens"`
+	ReasoningTokens  int64       `json:"reasoning_tokens"`
+}
+
+// Usage is attributed to the sender of the latest user message in the same
+// session at or before the usage-bearing message. Rows without one (e.g.
+// heartbeats and schedules) have a NULL user_id.
+func (q *Queries) GetTokenUsageReport(ctx context.Context, arg GetTokenUsageReportParams) ([]GetTokenUsageReportRow, error) {
+	rows, err := q.db.Query(ctx, getTokenUsageReport,
+		arg.FromTime,
+		arg.ToTime,
+		arg.BotID,
+		arg.UserID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetTokenUsageReportRow
+	for rows.Next() {
+		var i GetTokenUsageReportRow
+		if err := rows.Scan(
+			&i.BotID,
+			&i.BotName,
+			&i.UserID,
+			&i.UserName,
+			&i.Day,
+			&i.ModelID,
+			&i.ModelSlug,
+			&i.InputTokens,
+			&i.OutputTokens,
+			&i.CacheReadTokens,
+			&i.CacheWriteTokens,
+			&i.ReasoningTokens,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -43,6 +43,9 @@ func (h *ACLHandler) Register(e *echo.Echo) {
 	group.GET("/channel-identities", h.SearchChannelIdentities)
 	group.GET("/channel-identities/:channel_identity_id/conversations", h.ListObservedConversations)
 	group.GET("/channel-types/:channel_type/conversations", h.ListObservedConversationsByChannelType)
+	group.GET("/blocked-senders", h.ListBlockedSenders)
+	group.POST("/blocked-senders", h.BlockSender)
+	group.DELETE("/blocked-senders/:channel_identity_id", h.UnblockSender)
 }
 
 // ListRules godoc
@@ -317,6 +320,88 @@ func (h *ACLHandler) ListObservedConversationsByChannelType(c echo.Context) erro
 	return c.JSON(http.StatusOK, acl.ObservedConversationCandidateListResponse{Items: items})
 }
 
+// ListBlockedSenders godoc
+// @Summary List blocked senders
+// @Description List the channel identities whose messages the bot drops
+// @Tags bots
+// @Param bot_id path string true "Bot ID"
+// @Success 200 {object} acl.BlockedSenderListResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /bots/{bot_id}/acl/blocked-senders [get].
+func (h *ACLHandler) ListBlockedSenders(c echo.Context) error {
+	botID, _, err := h.requireManageAccess(c)
+	if err != nil {
+		return err
+	}
+	items, err := h.service.ListBlockedSenders(c.Request().Context(), botID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, acl.BlockedSenderListResponse{Items: items})
+}
+
+// BlockSender godoc
+// @Summary Block a sender
+// @Description Drop all inbound messages from a channel identity, optionally telling them once
+// @Tags bots
+// @Param bot_id path string true "Bot ID"
+// @Param payload body acl.BlockSenderRequest true "Block payload"
+// @Success 200 {object} acl.BlockedSender
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /bots/{bot_id}/acl/blocked-senders [post].
+func (h *ACLHandler) BlockSender(c echo.Context) error {
+	botID, actorID, err := h.requireManageAccess(c)
+	if err != nil {
+		return err
+	}
+	var req acl.BlockSenderRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	item, err := h.service.BlockSender(c.Request().Context(), botID, actorID, req)
+	if err != nil {
+		if errors.Is(err, acl.ErrInvalidBlockedSender) {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, item)
+}
+
+// UnblockSender godoc
+// @Summary Unblock a sender
+// @Description Remove a channel identity from the bot's block list
+// @Tags bots
+// @Param bot_id path string true "Bot ID"
+// @Param channel_identity_id path string true "Channel identity ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /bots/{bot_id}/acl/blocked-senders/{channel_identity_id} [delete].
+func (h *ACLHandler) UnblockSender(c echo.Context) error {
+	botID, _, err := h.requireManageAccess(c)
+	if err != nil {
+		return err
+	}
+	channelIdentityID := strings.TrimSpace(c.Param("channel_identity_id"))
+	if channelIdentityID == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "channel_identity_id is required")
+	}
+	if err := h.service.UnblockSender(c.Request().Context(), botID, channelIdentityID); err != nil {
+		if errors.Is(err, acl.ErrBlockedSenderNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, err.Error())
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
 func (h *ACLHandler) requireManageAccess(c echo.Context) (string, string, error) {
 	actorID, err := RequireChannelIdentityID(c)
 	if err != nil {
@@ -0,0 +1,608 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"gopkg.in/yaml.v3"
+
+	"github.com/memohai/memoh/internal/accounts"
+	"github.com/memohai/memoh/internal/bots"
+	"github.com/memohai/memoh/internal/schedule"
+	"github.com/memohai/memoh/internal/settings"
+)
+
+// botBundleVersion is the bundle format written by export and accepted by
+// import.
+const botBundleVersion = 1
+
+// bundleRedactedValue replaces secret values in exported bundles.
+const bundleRedactedValue = "[redacted]"
+
+// Bundle import conflict policies. A conflict is an item the target bot
+// already has with different content.
+const (
+	bundleConflictSkip      = "skip"
+	bundleConflictOverwrite = "overwrite"
+	bundleConflictFail      = "fail"
+)
+
+var (
+	errInvalidBundle  = errors.New("invalid bundle")
+	errBundleConflict = errors.New("bundle conflicts with bot configuration")
+)
+
+// bundleSecretKeys are key suffixes whose string values are redacted on
+// export, matched case-insensitively with separators removed.
+var bundleSecretKeys = []string{"token", "secret", "password", "passwd", "apikey", "accesskey", "privatekey", "credential", "credentials"}
+
+// BotBundle is a portable copy of a bot's configuration. Secret values are
+// replaced by "[redacted]"; on import they keep the target bot's value.
+type BotBundle struct {
+	Version   int                `json:"version"`
+	Metadata  map[string]any     `json:"metadata,omitempty"`
+	Settings  *settings.Settings `json:"settings,omitempty"`
+	Skills    []BundleSkill      `json:"skills,omitempty"`
+	Schedules []BundleSchedule   `json:"schedules,omitempty"`
+}
+
+// BundleSkill is a skill's SKILL.md file.
+type BundleSkill struct {
+	Name string `json:"name"`
+	Raw  string `json:"raw"`
+}
+
+// BundleSchedule is a schedule without its runtime state. Schedules are
+// matched by name on import.
+type BundleSchedule struct {
+	Name              string `json:"name"`
+	Description       string `json:"description"`
+	Pattern           string `json:"pattern"`
+	MaxCalls          *int   `json:"max_calls,omitempty"`
+	Command           string `json:"command"`
+	Enabled           bool   `json:"enabled"`
+	MaxRuntimeSeconds *int   `json:"max_runtime_seconds,omitempty"`
+	Condition         string `json:"condition,omitempty"`
+}
+
+// BotBundleImportResponse lists the items an import applied and skipped,
+// and the items that conflicted. Items are named "settings",
+// "metadata.<key>", "skill.<name>" and "schedule.<name>".
+type BotBundleImportResponse struct {
+	Applied   []string `json:"applied"`
+	Skipped   []string `json:"skipped"`
+	Conflicts []string `json:"conflicts"`
+}
+
+// bundleBotStore reads and updates bot metadata.
+type bundleBotStore interface {
+	Get(ctx context.Context, botID string) (bots.Bot, error)
+	Update(ctx context.Context, botID string, req bots.UpdateBotRequest) (bots.Bot, error)
+}
+
+// bundleSettingsStore reads and writes bot settings.
+type bundleSettingsStore interface {
+	GetBot(ctx context.Context, botID string) (settings.Settings, error)
+	UpsertBot(ctx context.Context, botID string, req settings.UpsertRequest) (settings.Settings, error)
+}
+
+// bundleScheduleStore lists and writes bot schedules.
+type bundleScheduleStore interface {
+	List(ctx context.Context, botID string) ([]schedule.Schedule, error)
+	Create(ctx context.Context, botID string, req schedule.CreateRequest) (schedule.Schedule, error)
+	Update(ctx context.Context, id string, req schedule.UpdateRequest) (schedule.Schedule, error)
+}
+
+// bundleSkillStore reads and writes the skills in a bot's workspace.
+type bundleSkillStore interface {
+	LoadSkills(ctx context.Context, botID string) ([]SkillItem, error)
+	WriteSkills(ctx context.Context, botID string, raws []string) error
+}
+
+// BotBundleHandler exports and imports bot configuration bundles.
+type BotBundleHandler struct {
+	bots           bundleBotStore
+	settings       bundleSettingsStore
+	schedules      bundleScheduleStore
+	skills         bundleSkillStore
+	botService     *bots.Service
+	accountService *accounts.Service
+	logger         *slog.Logger
+}
+
+// NewBotBundleHandler creates a BotBundleHandler.
+func NewBotBundleHandler(log *slog.Logger, botService *bots.Service, accountService *accounts.Service, settingsService *settings.Service, scheduleService *schedule.Service, containerdHandler *ContainerdHandler) *BotBundleHandler {
+	h := &BotBundleHandler{
+		botService:     botService,
+		accountService: accountService,
+		logger:         log.With(slog.String("handler", "bot_bundle")),
+	}
+	if botService != nil {
+		h.bots = botService
+	}
+	if settingsService != nil {
+		h.settings = settingsService
+	}
+	if scheduleService != nil {
+		h.schedules = scheduleService
+	}
+	if containerdHandler != nil {
+		h.skills = containerdHandler
+	}
+	return h
+}
+
+func (h *BotBundleHandler) Register(e *echo.Echo) {
+	group := e.Group("/bots/:bot_id/bundle")
+	group.GET("", h.Export)
+	group.POST("", h.Import)
+}
+
+// Export godoc
+// @Summary Export bot bundle
+// @Description Export the bot's metadata, settings, skills and schedules as a portable JSON bundle. Secret values are redacted.
+// @Tags bots
+// @Param bot_id path string true "Bot ID"
+// @Success 200 {object} BotBundle
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /bots/{bot_id}/bundle [get].
+func (h *BotBundleHandler) Export(c echo.Context) error {
+	botID, err := h.requireBotAccess(c)
+	if err != nil {
+		return err
+	}
+	bundle, err := h.exportBundle(c.Request().Context(), botID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, bundle)
+}
+
+// Import godoc
+// @Summary Import bot bundle
+// @Description Apply an exported bundle to the bot. Settings are always applied. Metadata keys, skills and schedules the bot already has with different content are conflicts, handled by on_conflict: skip keeps the bot's version, overwrite replaces it and fail rejects the import.
+// @Tags bots
+// @Param bot_id path string true "Bot ID"
+// @Param on_conflict query string false "Conflict policy: skip, overwrite or fail" default(skip)
+// @Param payload body BotBundle true "Bundle"
+// @Success 200 {object} BotBundleImportResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /bots/{bot_id}/bundle [post].
+func (h *BotBundleHandler) Import(c echo.Context) error {
+	botID, err := h.requireBotAccess(c)
+	if err != nil {
+		return err
+	}
+	policy := strings.ToLower(strings.TrimSpace(c.QueryParam("on_conflict")))
+	switch policy {
+	case "":
+		policy = bundleConflictSkip
+	case bundleConflictSkip, bundleConflictOverwrite, bundleConflictFail:
+	default:
+		return echo.NewHTTPError(http.StatusBadRequest, "on_conflict must be skip, overwrite or fail")
+	}
+	var bundle BotBundle
+	if err := c.Bind(&bundle); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	resp, err := h.importBundle(c.Request().Context(), botID, bundle, policy)
+	if err != nil {
+		switch {
+		case errors.Is(err, errInvalidBundle):
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		case errors.Is(err, errBundleConflict):
+			return echo.NewHTTPError(http.StatusConflict, fmt.Sprintf("%v: %s", err, strings.Join(resp.Conflicts, ", ")))
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h *BotBundleHandler) exportBundle(ctx context.Context, botID string) (BotBundle, error) {
+	if h.bots == nil || h.settings == nil || h.schedules == nil || h.skills == nil {
+		return BotBundle{}, errors.New("bot bundle services not configured")
+	}
+	bot, err := h.bots.Get(ctx, botID)
+	if err != nil {
+		return BotBundle{}, err
+	}
+	botSettings, err := h.settings.GetBot(ctx, botID)
+	if err != nil {
+		return BotBundle{}, err
+	}
+	skills, err := h.skills.LoadSkills(ctx, botID)
+	if err != nil {
+		return BotBundle{}, err
+	}
+	schedules, err := h.schedules.List(ctx, botID)
+	if err != nil {
+		return BotBundle{}, err
+	}
+
+	metadata, _ := redactBundleSecrets(bot.Metadata)
+	bundle := BotBundle{
+		Version:  botBundleVersion,
+		Metadata: metadata,
+		Settings: &botSettings,
+	}
+	for _, skill := range skills {
+		bundle.Skills = append(bundle.Skills, BundleSkill{Name: skill.Name, Raw: redactSkillSecrets(skill.Raw)})
+	}
+	sort.Slice(bundle.Skills, func(i, j int) bool { return bundle.Skills[i].Name < bundle.Skills[j].Name })
+	for _, item := range schedules {
+		bundle.Schedules = append(bundle.Schedules, bundleScheduleFrom(item))
+	}
+	sort.Slice(bundle.Schedules, func(i, j int) bool { return bundle.Schedules[i].Name < bundle.Schedules[j].Name })
+	return bundle, nil
+}
+
+// importBundle applies bundle to the bot. With the fail policy nothing is
+// applied when there are conflicts; the returned response still lists them.
+func (h *BotBundleHandler) importBundle(ctx context.Context, botID string, bundle BotBundle, policy string) (BotBundleImportResponse, error) {
+	resp := BotBundleImportResponse{Applied: []string{}, Skipped: []string{}, Conflicts: []string{}}
+	if bundle.Version != botBundleVersion {
+		return resp, fmt.Errorf("%w: unsupported version %d", errInvalidBundle, bundle.Version)
+	}
+	if h.bots == nil || h.settings == nil || h.schedules == nil || h.skills == nil {
+		return resp, errors.New("bot bundle services not configured")
+	}
+
+	bot, err := h.bots.Get(ctx, botID)
+	if err != nil {
+		return resp, err
+	}
+	existingSkills := map[string]string{}
+	if len(bundle.Skills) > 0 {
+		skills, err := h.skills.LoadSkills(ctx, botID)
+		if err != nil {
+			return resp, err
+		}
+		for _, skill := range skills {
+			existingSkills[skill.Name] = skill.Raw
+		}
+	}
+	existingSchedules := map[string]schedule.Schedule{}
+	if len(bundle.Schedules) > 0 {
+		schedules, err := h.schedules.List(ctx, botID)
+		if err != nil {
+			return resp, err
+		}
+		for _, item := range schedules {
+			existingSchedules[item.Name] = item
+		}
+	}
+
+	// Plan every item before writing anything, so that invalid bundles and
+	// the fail policy leave the bot untouched.
+	var conflicts []string
+	conflicting := func(item string) bool {
+		conflicts = append(conflicts, item)
+		return policy == bundleConflictOverwrite
+	}
+
+	metadata := restoreBundleSecrets(bundle.Metadata, bot.Metadata)
+	var metadataKeys []string
+	for key := range metadata {
+		metadataKeys = append(metadataKeys, key)
+	}
+	sort.Strings(metadataKeys)
+	mergedMetadata := map[string]any{}
+	for key, value := range bot.Metadata {
+		mergedMetadata[key] = value
+	}
+	var appliedMetadata, skippedMetadata []string
+	for _, key := range metadataKeys {
+		current, exists := bot.Metadata[key]
+		if exists && reflect.DeepEqual(current, metadata[key]) {
+			continue
+		}
+		item := "metadata." + key
+		if exists && !conflicting(item) {
+			skippedMetadata = append(skippedMetadata, item)
+			continue
+		}
+		mergedMetadata[key] = metadata[key]
+		appliedMetadata = append(appliedMetadata, item)
+	}
+
+	var skillRaws, appliedSkills, skippedSkills []string
+	for _, skill := range bundle.Skills {
+		existing, exists := existingSkills[parseSkillFile(skill.Raw, skill.Name).Name]
+		raw := restoreSkillSecrets(skill.Raw, existing)
+		if err := validateSkillFile(raw); err != nil {
+			return resp, fmt.Errorf("%w: skill %q: %w", errInvalidBundle, skill.Name, err)
+		}
+		name := parseSkillFile(raw, skill.Name).Name
+		if exists && sameSkillFile(existing, raw) {
+			continue
+		}
+		item := "skill." + name
+		if exists && !conflicting(item) {
+			skippedSkills = append(skippedSkills, item)
+			continue
+		}
+		skillRaws = append(skillRaws, raw)
+		appliedSkills = append(appliedSkills, item)
+	}
+
+	type scheduleWrite struct {
+		existingID string
+		schedule   BundleSchedule
+	}
+	var scheduleWrites []scheduleWrite
+	var skippedSchedules []string
+	for _, item := range bundle.Schedules {
+		if strings.TrimSpace(item.Name) == "" {
+			return resp, fmt.Errorf("%w: schedule name is required", errInvalidBundle)
+		}
+		existing, exists := existingSchedules[item.Name]
+		if exists && reflect.DeepEqual(bundleScheduleFrom(existing), item) {
+			continue
+		}
+		name := "schedule." + item.Name
+		if exists && !conflicting(name) {
+			skippedSchedules = append(skippedSchedules, name)
+			continue
+		}
+		scheduleWrites = append(scheduleWrites, scheduleWrite{existingID: existing.ID, schedule: item})
+	}
+
+	resp.Conflicts = append(resp.Conflicts, conflicts...)
+	if policy == bundleConflictFail && len(conflicts) > 0 {
+		return resp, errBundleConflict
+	}
+
+	if bundle.Settings != nil {
+		if _, err := h.settings.UpsertBot(ctx, botID, bundle.Settings.CloneRequest()); err != nil {
+			return resp, err
+		}
+		resp.Applied = append(resp.Applied, "settings")
+	}
+	if len(appliedMetadata) > 0 {
+		if _, err := h.bots.Update(ctx, botID, bots.UpdateBotRequest{Metadata: mergedMetadata}); err != nil {
+			return resp, err
+		}
+		resp.Applied = append(resp.Applied, appliedMetadata...)
+	}
+	resp.Skipped = append(resp.Skipped, skippedMetadata...)
+	if len(skillRaws) > 0 {
+		if err := h.skills.WriteSkills(ctx, botID, skillRaws); err != nil {
+			return resp, err
+		}
+		resp.Applied = append(resp.Applied, appliedSkills...)
+	}
+	resp.Skipped = append(resp.Skipped, skippedSkills...)
+	for _, write := range scheduleWrites {
+		item := write.schedule
+		var err error
+		if write.existingID == "" {
+			enabled := item.Enabled
+			_, err = h.schedules.Create(ctx, botID, schedule.CreateRequest{
+				Name:              item.Name,
+				Description:       item.Description,
+				Pattern:           item.Pattern,
+				MaxCalls:          schedule.NullableInt{Value: item.MaxCalls, Set: true},
+				Command:           item.Command,
+				Enabled:           &enabled,
+				MaxRuntimeSeconds: schedule.NullableInt{Value: item.MaxRuntimeSeconds, Set: true},
+				Condition:         item.Condition,
+			})
+		} else {
+			_, err = h.schedules.Update(ctx, write.existingID, schedule.UpdateRequest{
+				Name:              &item.Name,
+				Description:       &item.Description,
+				Pattern:           &item.Pattern,
+				MaxCalls:          schedule.NullableInt{Value: item.MaxCalls, Set: true},
+				Command:           &item.Command,
+				Enabled:           &item.Enabled,
+				MaxRuntimeSeconds: schedule.NullableInt{Value: item.MaxRuntimeSeconds, Set: true},
+				Condition:         &item.Condition,
+			})
+		}
+		if err != nil {
+			return resp, fmt.Errorf("schedule %q: %w", item.Name, err)
+		}
+		resp.Applied = append(resp.Applied, "schedule."+item.Name)
+	}
+	resp.Skipped = append(resp.Skipped, skippedSchedules...)
+	return resp, nil
+}
+
+func bundleScheduleFrom(item schedule.Schedule) BundleSchedule {
+	return BundleSchedule{
+		Name:              item.Name,
+		Description:       item.Description,
+		Pattern:           item.Pattern,
+		MaxCalls:          item.MaxCalls,
+		Command:           item.Command,
+		Enabled:           item.Enabled,
+		MaxRuntimeSeconds: item.MaxRuntimeSeconds,
+		Condition:         item.Condition,
+	}
+}
+
+// isBundleSecretKey reports whether values stored under key are secrets.
+func isBundleSecretKey(key string) bool {
+	normalized := strings.ToLower(strings.NewReplacer("_", "", "-", "", ".", "", " ", "").Replace(key))
+	for _, suffix := range bundleSecretKeys {
+		if strings.HasSuffix(normalized, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactBundleSecrets returns a copy of values with the non-empty strings
+// under secret keys replaced by bundleRedactedValue, and whether anything
+// was redacted.
+func redactBundleSecrets(values map[string]any) (map[string]any, bool) {
+	if values == nil {
+		return nil, false
+	}
+	redacted := false
+	out := make(map[string]any, len(values))
+	for key, value := range values {
+		if text, ok := value.(string); ok && text != "" && isBundleSecretKey(key) {
+			out[key] = bundleRedactedValue
+			redacted = true
+			continue
+		}
+		var changed bool
+		out[key], changed = redactBundleValue(value)
+		redacted = redacted || changed
+	}
+	return out, redacted
+}
+
+func redactBundleValue(value any) (any, bool) {
+	switch v := value.(type) {
+	case map[string]any:
+		return redactBundleSecrets(v)
+	case []any:
+		redacted := false
+		out := make([]any, len(v))
+		for i, item := range v {
+			var changed bool
+			out[i], changed = redactBundleValue(item)
+			redacted = redacted || changed
+		}
+		return out, redacted
+	default:
+		return value, false
+	}
+}
+
+// restoreBundleSecrets replaces redacted values in incoming with the values
+// existing holds at the same place. Redacted values existing does not have
+// are left out.
+func restoreBundleSecrets(incoming, existing map[string]any) map[string]any {
+	if incoming == nil {
+		return nil
+	}
+	out := make(map[string]any, len(incoming))
+	for key, value := range incoming {
+		if restored, ok := restoreBundleValue(value, existing[key]); ok {
+			out[key] = restored
+		}
+	}
+	return out
+}
+
+// restoreBundleValue restores redacted values in value from previous. It
+// reports false for a redacted value previous cannot restore.
+func restoreBundleValue(value, previous any) (any, bool) {
+	switch v := value.(type) {
+	case string:
+		if v == bundleRedactedValue {
+			p, ok := previous.(string)
+			return p, ok && p != bundleRedactedValue
+		}
+	case map[string]any:
+		p, _ := previous.(map[string]any)
+		return restoreBundleSecrets(v, p), true
+	case []any:
+		p, _ := previous.([]any)
+		out := make([]any, 0, len(v))
+		for i, item := range v {
+			var prev any
+			if i < len(p) {
+				prev = p[i]
+			}
+			if restored, ok := restoreBundleValue(item, prev); ok {
+				out = append(out, restored)
+			}
+		}
+		return out, true
+	}
+	return value, true
+}
+
+// redactSkillSecrets redacts secrets in the frontmatter of a SKILL.md file.
+// Files without secrets are returned unchanged.
+func redactSkillSecrets(raw string) string {
+	frontmatter, body, ok := skillFrontmatterMap(raw)
+	if !ok {
+		return raw
+	}
+	redacted, changed := redactBundleSecrets(frontmatter)
+	if !changed {
+		return raw
+	}
+	return renderSkillFile(redacted, body, raw)
+}
+
+// restoreSkillSecrets restores redacted frontmatter values of a SKILL.md
+// file from the existing file of the same skill.
+func restoreSkillSecrets(raw, existing string) string {
+	if !strings.Contains(raw, bundleRedactedValue) {
+		return raw
+	}
+	frontmatter, body, ok := skillFrontmatterMap(raw)
+	if !ok {
+		return raw
+	}
+	previous, _, _ := skillFrontmatterMap(existing)
+	return renderSkillFile(restoreBundleSecrets(frontmatter, previous), body, raw)
+}
+
+// sameSkillFile reports whether two SKILL.md files have the same body and
+// frontmatter, ignoring how the frontmatter is formatted.
+func sameSkillFile(a, b string) bool {
+	if strings.TrimSpace(a) == strings.TrimSpace(b) {
+		return true
+	}
+	frontmatterA, bodyA, okA := skillFrontmatterMap(a)
+	frontmatterB, bodyB, okB := skillFrontmatterMap(b)
+	return okA && okB && strings.TrimSpace(bodyA) == strings.TrimSpace(bodyB) && reflect.DeepEqual(frontmatterA, frontmatterB)
+}
+
+func skillFrontmatterMap(raw string) (map[string]any, string, bool) {
+	frontmatterRaw, body, ok := splitSkillFrontmatter(strings.TrimSpace(raw))
+	if !ok {
+		return nil, "", false
+	}
+	var frontmatter map[string]any
+	if err := yaml.Unmarshal([]byte(frontmatterRaw), &frontmatter); err != nil || frontmatter == nil {
+		return nil, "", false
+	}
+	return frontmatter, body, true
+}
+
+// renderSkillFile writes frontmatter and body back into a SKILL.md file,
+// returning fallback when the frontmatter cannot be encoded.
+func renderSkillFile(frontmatter map[string]any, body, fallback string) string {
+	encoded, err := yaml.Marshal(frontmatter)
+	if err != nil {
+		return fallback
+	}
+	return "---\n" + string(encoded) + "---\n\n" + body
+}
+
+func (h *BotBundleHandler) requireBotAccess(c echo.Context) (string, error) {
+	channelIdentityID, err := RequireChannelIdentityID(c)
+	if err != nil {
+		return "", err
+	}
+	botID := strings.TrimSpace(c.Param("bot_id"))
+	if botID == "" {
+		return "", echo.NewHTTPError(http.StatusBadRequest, "bot id is required")
+	}
+	if _, err := AuthorizeBotAccess(c.Request().Context(), h.botService, h.accountService, channelIdentityID, botID); err != nil {
+		return "", err
+	}
+	return botID, nil
+}
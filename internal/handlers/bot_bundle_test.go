@@ -0,0 +1,353 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/memohai/memoh/internal/bots"
+	"github.com/memohai/memoh/internal/schedule"
+	"github.com/memohai/memoh/internal/settings"
+)
+
+type fakeBundleBots struct {
+	bot bots.Bot
+}
+
+func (f *fakeBundleBots) Get(context.Context, string) (bots.Bot, error) {
+	return f.bot, nil
+}
+
+func (f *fakeBundleBots) Update(_ context.Context, _ string, req bots.UpdateBotRequest) (bots.Bot, error) {
+	f.bot.Metadata = req.Metadata
+	return f.bot, nil
+}
+
+type fakeBundleSettings struct {
+	settings settings.Settings
+	upserts  int
+}
+
+func (f *fakeBundleSettings) GetBot(context.Context, string) (settings.Settings, error) {
+	return f.settings, nil
+}
+
+func (f *fakeBundleSettings) UpsertBot(_ context.Context, _ string, req settings.UpsertRequest) (settings.Settings, error) {
+	f.upserts++
+	f.settings.ChatModelID = req.ChatModelID
+	f.settings.Language = req.Language
+	f.settings.HeartbeatEnabled = *req.HeartbeatEnabled
+	return f.settings, nil
+}
+
+type fakeBundleSchedules struct {
+	items []schedule.Schedule
+}
+
+func (f *fakeBundleSchedules) List(context.Context, string) ([]schedule.Schedule, error) {
+	return f.items, nil
+}
+
+func (f *fakeBundleSchedules) Create(_ context.Context, botID string, req schedule.CreateRequest) (schedule.Schedule, error) {
+	item := schedule.Schedule{
+		ID:                req.Name + "-id",
+		BotID:             botID,
+		Name:              req.Name,
+		Description:       req.Description,
+		Pattern:           req.Pattern,
+		MaxCalls:          req.MaxCalls.Value,
+		Command:           req.Command,
+		Enabled:           *req.Enabled,
+		MaxRuntimeSeconds: req.MaxRuntimeSeconds.Value,
+		Condition:         req.Condition,
+	}
+	f.items = append(f.items, item)
+	return item, nil
+}
+
+func (f *fakeBundleSchedules) Update(_ context.Context, id string, req schedule.UpdateRequest) (schedule.Schedule, error) {
+	for i, item := range f.items {
+		if item.ID == id {
+			f.items[i].Command = *req.Command
+			f.items[i].Pattern = *req.Pattern
+			return f.items[i], nil
+		}
+	}
+	return schedule.Schedule{}, errors.New("schedule not found")
+}
+
+type fakeBundleSkills struct {
+	raws map[string]string
+}
+
+func (f *fakeBundleSkills) LoadSkills(context.Context, string) ([]SkillItem, error) {
+	var items []SkillItem
+	for name, raw := range f.raws {
+		items = append(items, SkillItem{Name: name, Raw: raw})
+	}
+	return items, nil
+}
+
+func (f *fakeBundleSkills) WriteSkills(_ context.Context, _ string, raws []string) error {
+	for _, raw := range raws {
+		f.raws[parseSkillFile(raw, "").Name] = raw
+	}
+	return nil
+}
+
+type bundleFixture struct {
+	bots      *fakeBundleBots
+	settings  *fakeBundleSettings
+	schedules *fakeBundleSchedules
+	skills    *fakeBundleSkills
+	handler   *BotBundleHandler
+}
+
+func newBundleFixture(metadata map[string]any, skills map[string]string, schedules []schedule.Schedule) *bundleFixture {
+	f := &bundleFixture{
+		bots:      &fakeBundleBots{bot: bots.Bot{ID: "bot-1", Metadata: metadata}},
+		settings:  &fakeBundleSettings{settings: settings.Settings{Language: "auto"}},
+		schedules: &fakeBundleSchedules{items: schedules},
+		skills:    &fakeBundleSkills{raws: skills},
+	}
+	f.handler = NewBotBundleHandler(slog.New(slog.DiscardHandler), nil, nil, nil, nil, nil)
+	f.handler.bots = f.bots
+	f.handler.settings = f.settings
+	f.handler.schedules = f.schedules
+	f.handler.skills = f.skills
+	return f
+}
+
+const weatherSkill = "---\nname: weather\ndescription: Check the weather\nmetadata:\n  api_key: sk-live-123\n  units: metric\n---\n\nLook up the forecast."
+
+func sourceBundleFixture() *bundleFixture {
+	maxCalls := 3
+	f := newBundleFixture(
+		map[string]any{
+			"features": map[string]any{"max_tool_rounds": float64(8)},
+			"webhook":  map[string]any{"url": "https://example.com/hook", "signing_secret": "whsec-1"},
+		},
+		map[string]string{"weather": weatherSkill},
+		[]schedule.Schedule{{
+			ID:       "sched-1",
+			Name:     "digest",
+			Pattern:  "0 9 * * *",
+			Command:  "Send the daily digest",
+			Enabled:  true,
+			MaxCalls: &maxCalls,
+		}},
+	)
+	f.settings.settings = settings.Settings{ChatModelID: "model-1", Language: "en", HeartbeatEnabled: true}
+	return f
+}
+
+func TestBotBundleExportRedactsSecrets(t *testing.T) {
+	source := sourceBundleFixture()
+
+	bundle, err := source.handler.exportBundle(context.Background(), "bot-1")
+	if err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	payload, err := json.Marshal(bundle)
+	if err != nil {
+		t.Fatalf("marshal bundle: %v", err)
+	}
+	for _, secret := range []string{"sk-live-123", "whsec-1"} {
+		if strings.Contains(string(payload), secret) {
+			t.Fatalf("bundle leaks secret %q: %s", secret, payload)
+		}
+	}
+	webhook := bundle.Metadata["webhook"].(map[string]any)
+	if webhook["signing_secret"] != bundleRedactedValue || webhook["url"] != "https://example.com/hook" {
+		t.Fatalf("unexpected webhook metadata: %+v", webhook)
+	}
+	if !strings.Contains(bundle.Skills[0].Raw, "units: metric") || !strings.Contains(bundle.Skills[0].Raw, "Look up the forecast.") {
+		t.Fatalf("skill lost non-secret content: %s", bundle.Skills[0].Raw)
+	}
+	if source.bots.bot.Metadata["webhook"].(map[string]any)["signing_secret"] != "whsec-1" {
+		t.Fatal("export modified the source bot metadata")
+	}
+}
+
+func TestBotBundleRoundTrip(t *testing.T) {
+	source := sourceBundleFixture()
+	bundle, err := source.handler.exportBundle(context.Background(), "bot-1")
+	if err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	payload, err := json.Marshal(bundle)
+	if err != nil {
+		t.Fatalf("marshal bundle: %v", err)
+	}
+	var decoded BotBundle
+	if err := json.Unmarshal(payload, &decoded); err != nil {
+		t.Fatalf("unmarshal bundle: %v", err)
+	}
+
+	target := newBundleFixture(map[string]any{}, map[string]string{}, nil)
+	resp, err := target.handler.importBundle(context.Background(), "bot-2", decoded, bundleConflictSkip)
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	want := []string{"settings", "metadata.features", "metadata.webhook", "skill.weather", "schedule.digest"}
+	if !reflect.DeepEqual(resp.Applied, want) {
+		t.Fatalf("applied = %v, want %v", resp.Applied, want)
+	}
+	if target.settings.settings.ChatModelID != "model-1" || !target.settings.settings.HeartbeatEnabled {
+		t.Fatalf("settings not applied: %+v", target.settings.settings)
+	}
+	if got := bots.FeaturesFromMetadata(target.bots.bot.Metadata).MaxToolRounds; got != 8 {
+		t.Fatalf("max_tool_rounds = %d, want 8", got)
+	}
+	if _, ok := target.bots.bot.Metadata["webhook"].(map[string]any)["signing_secret"]; ok {
+		t.Fatal("a redacted secret the target never had must not be imported")
+	}
+	if len(target.schedules.items) != 1 || *target.schedules.items[0].MaxCalls != 3 {
+		t.Fatalf("schedule not imported: %+v", target.schedules.items)
+	}
+
+	// Exporting the target again yields the same skills and schedules.
+	again, err := target.handler.exportBundle(context.Background(), "bot-2")
+	if err != nil {
+		t.Fatalf("second export: %v", err)
+	}
+	if !reflect.DeepEqual(again.Schedules, bundle.Schedules) {
+		t.Fatalf("schedules changed in round trip: %+v vs %+v", again.Schedules, bundle.Schedules)
+	}
+	if parseSkillFile(again.Skills[0].Raw, "").Metadata["units"] != "metric" {
+		t.Fatalf("skill changed in round trip: %s", again.Skills[0].Raw)
+	}
+}
+
+func TestBotBundleImportKeepsTargetSecrets(t *testing.T) {
+	source := sourceBundleFixture()
+	bundle, err := source.handler.exportBundle(context.Background(), "bot-1")
+	if err != nil {
+		t.Fatalf("export: %v", err)
+	}
+
+	// Re-importing into the source restores every redacted value, so nothing
+	// differs and nothing conflicts.
+	resp, err := source.handler.importBundle(context.Background(), "bot-1", bundle, bundleConflictFail)
+	if err != nil {
+		t.Fatalf("import: %v (conflicts %v)", err, resp.Conflicts)
+	}
+	if !reflect.DeepEqual(resp.Applied, []string{"settings"}) || len(resp.Conflicts) != 0 {
+		t.Fatalf("unexpected import result: %+v", resp)
+	}
+	if source.skills.raws["weather"] != weatherSkill {
+		t.Fatalf("skill rewritten: %s", source.skills.raws["weather"])
+	}
+	if source.bots.bot.Metadata["webhook"].(map[string]any)["signing_secret"] != "whsec-1" {
+		t.Fatal("target secret was lost")
+	}
+}
+
+func TestBotBundleRoundTripRestoresSecretsInArrays(t *testing.T) {
+	source := newBundleFixture(map[string]any{
+		"webhooks": []any{
+			map[string]any{"url": "https://example.com/a", "signing_secret": "whsec-a"},
+			map[string]any{"url": "https://example.com/b", "signing_secret": "whsec-b"},
+		},
+	}, map[string]string{}, nil)
+
+	bundle, err := source.handler.exportBundle(context.Background(), "bot-1")
+	if err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	payload, err := json.Marshal(bundle)
+	if err != nil {
+		t.Fatalf("marshal bundle: %v", err)
+	}
+	if strings.Contains(string(payload), "whsec-") {
+		t.Fatalf("bundle leaks a secret inside an array: %s", payload)
+	}
+	var decoded BotBundle
+	if err := json.Unmarshal(payload, &decoded); err != nil {
+		t.Fatalf("unmarshal bundle: %v", err)
+	}
+
+	resp, err := source.handler.importBundle(context.Background(), "bot-1", decoded, bundleConflictFail)
+	if err != nil {
+		t.Fatalf("import: %v (conflicts %v)", err, resp.Conflicts)
+	}
+	webhooks := source.bots.bot.Metadata["webhooks"].([]any)
+	for i, want := range []string{"whsec-a", "whsec-b"} {
+		if got := webhooks[i].(map[string]any)["signing_secret"]; got != want {
+			t.Fatalf("webhooks[%d].signing_secret = %v, want %s", i, got, want)
+		}
+	}
+}
+
+func TestBotBundleImportConflictPolicies(t *testing.T) {
+	source := sourceBundleFixture()
+	bundle, err := source.handler.exportBundle(context.Background(), "bot-1")
+	if err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	newTarget := func() *bundleFixture {
+		return newBundleFixture(
+			map[string]any{"features": map[string]any{"max_tool_rounds": float64(2)}},
+			map[string]string{"weather": "---\nname: weather\ndescription: Old weather\n---\n\nOld body."},
+			[]schedule.Schedule{{ID: "sched-9", Name: "digest", Pattern: "0 8 * * *", Command: "Old digest", Enabled: true}},
+		)
+	}
+	conflicts := []string{"metadata.features", "skill.weather", "schedule.digest"}
+
+	failing := newTarget()
+	resp, err := failing.handler.importBundle(context.Background(), "bot-2", bundle, bundleConflictFail)
+	if !errors.Is(err, errBundleConflict) {
+		t.Fatalf("expected conflict error, got %v", err)
+	}
+	if !reflect.DeepEqual(resp.Conflicts, conflicts) {
+		t.Fatalf("conflicts = %v, want %v", resp.Conflicts, conflicts)
+	}
+	if failing.settings.upserts != 0 || failing.schedules.items[0].Command != "Old digest" {
+		t.Fatal("fail policy must not change the bot")
+	}
+
+	skipping := newTarget()
+	resp, err = skipping.handler.importBundle(context.Background(), "bot-2", bundle, bundleConflictSkip)
+	if err != nil {
+		t.Fatalf("skip import: %v", err)
+	}
+	if !reflect.DeepEqual(resp.Skipped, conflicts) {
+		t.Fatalf("skipped = %v, want %v", resp.Skipped, conflicts)
+	}
+	if got := bots.FeaturesFromMetadata(skipping.bots.bot.Metadata).MaxToolRounds; got != 2 {
+		t.Fatalf("skip policy overwrote features, max_tool_rounds = %d", got)
+	}
+	if !strings.Contains(skipping.skills.raws["weather"], "Old body.") || skipping.schedules.items[0].Command != "Old digest" {
+		t.Fatal("skip policy overwrote existing items")
+	}
+
+	overwriting := newTarget()
+	resp, err = overwriting.handler.importBundle(context.Background(), "bot-2", bundle, bundleConflictOverwrite)
+	if err != nil {
+		t.Fatalf("overwrite import: %v", err)
+	}
+	if len(resp.Skipped) != 0 {
+		t.Fatalf("skipped = %v, want none", resp.Skipped)
+	}
+	if got := bots.FeaturesFromMetadata(overwriting.bots.bot.Metadata).MaxToolRounds; got != 8 {
+		t.Fatalf("max_tool_rounds = %d, want 8", got)
+	}
+	if overwriting.schedules.items[0].Command != "Send the daily digest" || len(overwriting.schedules.items) != 1 {
+		t.Fatalf("schedule not overwritten: %+v", overwriting.schedules.items)
+	}
+	if strings.Contains(overwriting.skills.raws["weather"], bundleRedactedValue) {
+		t.Fatalf("redacted value written into skill: %s", overwriting.skills.raws["weather"])
+	}
+}
+
+func TestBotBundleImportRejectsUnknownVersion(t *testing.T) {
+	target := newBundleFixture(map[string]any{}, map[string]string{}, nil)
+	_, err := target.handler.importBundle(context.Background(), "bot-2", BotBundle{Version: 99}, bundleConflictSkip)
+	if !errors.Is(err, errInvalidBundle) {
+		t.Fatalf("expected invalid bundle error, got %v", err)
+	}
+}
@@ -0,0 +1,243 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/labstack/echo/v4"
+
+	"github.com/memohai/memoh/internal/accounts"
+	"github.com/memohai/memoh/internal/bots"
+	"github.com/memohai/memoh/internal/channel"
+	memprovider "github.com/memohai/memoh/internal/memory/adapters"
+	"github.com/memohai/memoh/internal/settings"
+)
+
+type BotCloneHandler struct {
+	botService      *bots.Service
+	accountService  *accounts.Service
+	settingsService *settings.Service
+	channelStore    *channel.Store
+	registry        *channel.Registry
+	skills          bundleSkillStore
+	memoryRegistry  *memprovider.Registry
+	logger          *slog.Logger
+}
+
+func NewBotCloneHandler(log *slog.Logger, botService *bots.Service, accountService *accounts.Service, settingsService *settings.Service, channelStore *channel.Store, registry *channel.Registry, containerdHandler *ContainerdHandler, memoryRegistry *memprovider.Registry) *BotCloneHandler {
+	h := &BotCloneHandler{
+		botService:      botService,
+		accountService:  accountService,
+		settingsService: settingsService,
+		channelStore:    channelStore,
+		registry:        registry,
+		memoryRegistry:  memoryRegistry,
+		logger:          log.With(slog.String("handler", "bot_clone")),
+	}
+	if containerdHandler != nil {
+		h.skills = containerdHandler
+	}
+	return h
+}
+
+func (h *BotCloneHandler) Register(e *echo.Echo) {
+	e.POST("/bots/:id/clone", h.Clone)
+}
+
+// Clone godoc
+// @Summary Clone bot
+// @Description Create a bot with the profile, metadata, settings, channel configs, skills and shared memories of an existing bot (owner/admin only). The clone is owned by the source bot's owner. Channel configs are copied disabled and without credentials. If any part cannot be copied the clone is deleted again.
+// @Tags bots
+// @Param id path string true "Source bot ID"
+// @Param payload body bots.CloneBotRequest false "Clone options"
+// @Success 201 {object} bots.Bot
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /bots/{id}/clone [post].
+func (h *BotCloneHandler) Clone(c echo.Context) error {
+	channelIdentityID, err := RequireChannelIdentityID(c)
+	if err != nil {
+		return err
+	}
+	sourceID := strings.TrimSpace(c.Param("id"))
+	if sourceID == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "bot id is required")
+	}
+	ctx := c.Request().Context()
+	if _, err := AuthorizeBotAccess(ctx, h.botService, h.accountService, channelIdentityID, sourceID); err != nil {
+		return err
+	}
+	var req bots.CloneBotRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	// Read everything that is copied before creating the clone, so a source
+	// that cannot be read does not leave a half-cloned bot behind.
+	var sourceSettings settings.Settings
+	if req.CopySettings() {
+		if h.settingsService == nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "settings service not configured")
+		}
+		if sourceSettings, err = h.settingsService.GetBot(ctx, sourceID); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		}
+	}
+	var sourceChannels []channel.ChannelConfig
+	if req.CopyChannels() {
+		if sourceChannels, err = h.listChannelConfigs(ctx, sourceID); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		}
+	}
+	var sourceSkills []string
+	if req.CopySkills() {
+		if sourceSkills, err = h.loadSkills(ctx, sourceID); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		}
+	}
+	var sourceMemories []memprovider.MemoryItem
+	if req.CopyMemories() {
+		if sourceMemories, err = h.listMemories(ctx, sourceID); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		}
+	}
+
+	clone, err := h.botService.Clone(ctx, sourceID, req)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return echo.NewHTTPError(http.StatusNotFound, "bot not found")
+		}
+		if errors.Is(err, bots.ErrOwnerUserNotFound) {
+			return echo.NewHTTPError(http.StatusBadRequest, "owner user not found")
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	// The clone is only useful as a whole, so any copy failure deletes it.
+	fail := func(what string, err error) error {
+		h.logger.Error("clone bot failed", slog.String("bot_id", clone.ID), slog.String("source_bot_id", sourceID), slog.String("step", what), slog.Any("error", err))
+		if discardErr := h.botService.Discard(ctx, clone.ID); discardErr != nil {
+			h.logger.Error("discard failed bot clone", slog.String("bot_id", clone.ID), slog.Any("error", discardErr))
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("copying %s failed: %v", what, err))
+	}
+	if req.CopySettings() {
+		if _, err := h.settingsService.UpsertBot(ctx, clone.ID, sourceSettings.CloneRequest()); err != nil {
+			return fail("settings", err)
+		}
+	}
+	for _, cfg := range sourceChannels {
+		if _, err := h.channelStore.CloneConfig(ctx, clone.ID, cfg); err != nil {
+			return fail(cfg.ChannelType.String()+" channel", err)
+		}
+	}
+	if len(sourceSkills) == 0 && len(sourceMemories) == 0 {
+		return c.JSON(http.StatusCreated, clone)
+	}
+
+	// Skills and memories are written to the clone's workspace, which its
+	// create lifecycle sets up in the background.
+	if clone, err = h.botService.WaitReady(ctx, clone.ID); err != nil {
+		return fail("workspace", err)
+	}
+	if len(sourceSkills) > 0 {
+		if err := h.skills.WriteSkills(ctx, clone.ID, sourceSkills); err != nil {
+			return fail("skills", err)
+		}
+	}
+	if len(sourceMemories) > 0 {
+		if err := h.writeMemories(ctx, clone.ID, sourceMemories); err != nil {
+			return fail("memories", err)
+		}
+	}
+	return c.JSON(http.StatusCreated, clone)
+}
+
+// loadSkills returns the raw skill files of a bot.
+func (h *BotCloneHandler) loadSkills(ctx context.Context, botID string) ([]string, error) {
+	if h.skills == nil {
+		return nil, errors.New("skill store not configured")
+	}
+	items, err := h.skills.LoadSkills(ctx, botID)
+	if err != nil {
+		return nil, err
+	}
+	raws := make([]string, 0, len(items))
+	for _, item := range items {
+		raws = append(raws, item.Raw)
+	}
+	return raws, nil
+}
+
+// listMemories returns the memories in a bot's shared namespace. A bot
+// without a memory provider has nothing to copy.
+func (h *BotCloneHandler) listMemories(ctx context.Context, botID string) ([]memprovider.MemoryItem, error) {
+	provider := resolveMemoryProvider(ctx, h.logger, h.memoryRegistry, h.settingsService, botID)
+	if provider == nil {
+		return nil, nil
+	}
+	resp, err := provider.GetAll(ctx, memprovider.GetAllRequest{
+		Filters: buildNamespaceFilters(sharedMemoryNamespace, botID, nil),
+		NoStats: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Results, nil
+}
+
+// writeMemories adds items to a bot's shared namespace with the provider its
+// (already copied) settings select.
+func (h *BotCloneHandler) writeMemories(ctx context.Context, botID string, items []memprovider.MemoryItem) error {
+	provider := resolveMemoryProvider(ctx, h.logger, h.memoryRegistry, h.settingsService, botID)
+	if provider == nil {
+		return errors.New("memory provider not configured")
+	}
+	filters := buildNamespaceFilters(sharedMemoryNamespace, botID, nil)
+	for _, item := range items {
+		metadata := make(map[string]any, len(item.Metadata))
+		for k, v := range item.Metadata {
+			if k != "namespace" && k != "scopeId" {
+				metadata[k] = v
+			}
+		}
+		if _, err := provider.Add(ctx, memprovider.AddRequest{
+			Message:  item.Memory,
+			BotID:    botID,
+			Metadata: metadata,
+			Filters:  filters,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// listChannelConfigs returns the stored channel configs of a bot. Configless
+// channel types have nothing to copy and are skipped.
+func (h *BotCloneHandler) listChannelConfigs(ctx context.Context, botID string) ([]channel.ChannelConfig, error) {
+	if h.channelStore == nil || h.registry == nil {
+		return nil, errors.New("channel store not configured")
+	}
+	var configs []channel.ChannelConfig
+	for _, channelType := range h.registry.Types() {
+		if h.registry.IsConfigless(channelType) {
+			continue
+		}
+		cfg, err := h.channelStore.ResolveEffectiveConfig(ctx, botID, channelType)
+		if errors.Is(err, channel.ErrChannelConfigNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		configs = append(configs, cfg)
+	}
+	return configs, nil
+}
@@ -0,0 +1,233 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"golang.org/x/time/rate"
+
+	"github.com/memohai/memoh/internal/accounts"
+	"github.com/memohai/memoh/internal/bots"
+	"github.com/memohai/memoh/internal/channel"
+	"github.com/memohai/memoh/internal/channel/route"
+)
+
+// broadcastInterval is the minimum time between two broadcasts from the same bot.
+const broadcastInterval = time.Minute
+
+// Broadcast result statuses.
+const (
+	broadcastStatusSent    = "sent"
+	broadcastStatusQueued  = "queued"
+	broadcastStatusFailed  = "failed"
+	broadcastStatusSkipped = "skipped"
+)
+
+// broadcastRouteLister lists the conversation routes of a bot.
+type broadcastRouteLister interface {
+	List(ctx context.Context, chatID string) ([]route.Route, error)
+}
+
+// broadcastSender delivers one outbound message through a channel.
+type broadcastSender interface {
+	Send(ctx context.Context, botID string, channelType channel.ChannelType, req channel.SendRequest) error
+}
+
+// BroadcastHandler sends one announcement to every conversation a bot has on a platform.
+type BroadcastHandler struct {
+	routes         broadcastRouteLister
+	sender         broadcastSender
+	registry       *channel.Registry
+	botService     *bots.Service
+	accountService *accounts.Service
+	logger         *slog.Logger
+
+	limitersMu sync.Mutex
+	limiters   map[string]*rate.Limiter
+}
+
+// BroadcastRequest is the announcement to send.
+// ConversationType optionally restricts the broadcast to "private", "group", etc.
+type BroadcastRequest struct {
+	Message          channel.Message `json:"message"`
+	ConversationType string          `json:"conversation_type,omitempty"`
+}
+
+// BroadcastResult is the outcome for one route.
+type BroadcastResult struct {
+	RouteID        string `json:"route_id"`
+	ConversationID string `json:"conversation_id"`
+	Target         string `json:"target,omitempty"`
+	Status         string `json:"status"`
+	Error          string `json:"error,omitempty"`
+}
+
+// BroadcastResponse summarizes a broadcast.
+type BroadcastResponse struct {
+	Total   int               `json:"total"`
+	Sent    int               `json:"sent"`
+	Queued  int               `json:"queued"`
+	Failed  int               `json:"failed"`
+	Skipped int               `json:"skipped"`
+	Results []BroadcastResult `json:"results"`
+}
+
+// NewBroadcastHandler creates a BroadcastHandler.
+func NewBroadcastHandler(log *slog.Logger, routeService route.Service, channelManager *channel.Manager, registry *channel.Registry, botService *bots.Service, accountService *accounts.Service) *BroadcastHandler {
+	h := &BroadcastHandler{
+		registry:       registry,
+		botService:     botService,
+		accountService: accountService,
+		logger:         log.With(slog.String("handler", "broadcast")),
+		limiters:       map[string]*rate.Limiter{},
+	}
+	if routeService != nil {
+		h.routes = routeService
+	}
+	if channelManager != nil {
+		h.sender = channelManager
+	}
+	return h
+}
+
+func (h *BroadcastHandler) Register(e *echo.Echo) {
+	e.POST("/bots/:id/channel/:platform/broadcast", h.Broadcast)
+}
+
+// Broadcast godoc
+// @Summary Broadcast a message to all conversations of a bot on a platform
+// @Description Sends the message to every route the bot has on the platform and reports per-route success or failure. Limited to one broadcast per bot per minute.
+// @Tags bots
+// @Accept json
+// @Produce json
+// @Param id path string true "Bot ID"
+// @Param platform path string true "Channel platform"
+// @Param payload body BroadcastRequest true "Broadcast payload"
+// @Success 200 {object} BroadcastResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /bots/{id}/channel/{platform}/broadcast [post].
+func (h *BroadcastHandler) Broadcast(c echo.Context) error {
+	channelIdentityID, err := RequireChannelIdentityID(c)
+	if err != nil {
+		return err
+	}
+	botID := strings.TrimSpace(c.Param("id"))
+	if botID == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "bot id is required")
+	}
+	if _, err := AuthorizeBotAccess(c.Request().Context(), h.botService, h.accountService, channelIdentityID, botID); err != nil {
+		return err
+	}
+	if h.routes == nil || h.sender == nil || h.registry == nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "services not configured")
+	}
+	channelType, err := h.registry.ParseChannelType(c.Param("platform"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	var req BroadcastRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if req.Message.IsEmpty() {
+		return echo.NewHTTPError(http.StatusBadRequest, "message is required")
+	}
+	if !h.allow(botID) {
+		return echo.NewHTTPError(http.StatusTooManyRequests, "broadcast rate limit exceeded")
+	}
+
+	resp, err := h.broadcast(c.Request().Context(), botID, channelType, req)
+	if err != nil {
+		h.logger.Error("broadcast failed", slog.String("bot_id", botID), slog.Any("error", err))
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// allow reports whether botID may broadcast now.
+func (h *BroadcastHandler) allow(botID string) bool {
+	h.limitersMu.Lock()
+	defer h.limitersMu.Unlock()
+	limiter, ok := h.limiters[botID]
+	if !ok {
+		limiter = rate.NewLimiter(rate.Every(broadcastInterval), 1)
+		h.limiters[botID] = limiter
+	}
+	return limiter.Allow()
+}
+
+// broadcast sends req.Message to each route of botID on channelType. Muted
+// routes, routes without a reply target, and routes sharing a target with an
+// earlier route are skipped.
+func (h *BroadcastHandler) broadcast(ctx context.Context, botID string, channelType channel.ChannelType, req BroadcastRequest) (BroadcastResponse, error) {
+	routes, err := h.routes.List(ctx, botID)
+	if err != nil {
+		return BroadcastResponse{}, err
+	}
+	conversationType := strings.TrimSpace(req.ConversationType)
+	resp := BroadcastResponse{Results: []BroadcastResult{}}
+	seen := map[string]struct{}{}
+	for _, rt := range routes {
+		if !strings.EqualFold(strings.TrimSpace(rt.Platform), channelType.String()) {
+			continue
+		}
+		if conversationType != "" && !strings.EqualFold(strings.TrimSpace(rt.ConversationType), conversationType) {
+			continue
+		}
+		result := BroadcastResult{
+			RouteID:        rt.ID,
+			ConversationID: rt.ConversationID,
+			Target:         strings.TrimSpace(rt.ReplyTarget),
+		}
+		_, duplicate := seen[result.Target]
+		switch {
+		case rt.Muted:
+			result.Status = broadcastStatusSkipped
+			result.Error = "route is muted"
+		case result.Target == "":
+			result.Status = broadcastStatusSkipped
+			result.Error = "reply target missing in route"
+		case duplicate:
+			result.Status = broadcastStatusSkipped
+			result.Error = "target already sent"
+		default:
+			seen[result.Target] = struct{}{}
+			err := h.sender.Send(ctx, botID, channelType, channel.SendRequest{
+				Target:  result.Target,
+				Message: req.Message,
+			})
+			switch {
+			case errors.Is(err, channel.ErrOutboundQueued):
+				result.Status = broadcastStatusQueued
+				result.Error = err.Error()
+			case err != nil:
+				result.Status = broadcastStatusFailed
+				result.Error = err.Error()
+			default:
+				result.Status = broadcastStatusSent
+			}
+		}
+		switch result.Status {
+		case broadcastStatusSent:
+			resp.Sent++
+		case broadcastStatusQueued:
+			resp.Queued++
+		case broadcastStatusFailed:
+			resp.Failed++
+		default:
+			resp.Skipped++
+		}
+		resp.Results = append(resp.Results, result)
+	}
+	resp.Total = len(resp.Results)
+	return resp, nil
+}
@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"testing"
+
+	"github.com/memohai/memoh/internal/channel"
+	"github.com/memohai/memoh/internal/channel/route"
+)
+
+type fakeBroadcastRoutes struct {
+	routes []route.Route
+}
+
+func (f *fakeBroadcastRoutes) List(_ context.Context, _ string) ([]route.Route, error) {
+	return f.routes, nil
+}
+
+type fakeBroadcastSender struct {
+	mu      sync.Mutex
+	failFor map[string]error
+	sent    []string
+}
+
+func (f *fakeBroadcastSender) Send(_ context.Context, _ string, _ channel.ChannelType, req channel.SendRequest) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.failFor[req.Target]; err != nil {
+		return err
+	}
+	f.sent = append(f.sent, req.Target)
+	return nil
+}
+
+func newTestBroadcastHandler(routes []route.Route, sender *fakeBroadcastSender) *BroadcastHandler {
+	h := NewBroadcastHandler(slog.New(slog.DiscardHandler), nil, nil, channel.NewRegistry(), nil, nil)
+	h.routes = &fakeBroadcastRoutes{routes: routes}
+	h.sender = sender
+	return h
+}
+
+func TestBroadcastFansOutAndReportsPartialFailures(t *testing.T) {
+	t.Parallel()
+
+	routes := []route.Route{
+		{ID: "r1", Platform: "telegram", ConversationID: "c1", ConversationType: "private", ReplyTarget: "100"},
+		{ID: "r2", Platform: "telegram", ConversationID: "c2", ConversationType: "group", ReplyTarget: "200"},
+		{ID: "r3", Platform: "discord", ConversationID: "c3", ReplyTarget: "300"},
+		{ID: "r4", Platform: "telegram", ConversationID: "c4", ReplyTarget: ""},
+		{ID: "r5", Platform: "telegram", ConversationID: "c2", ThreadID: "t1", ReplyTarget: "200"},
+		{ID: "r6", Platform: "telegram", ConversationID: "c6", ReplyTarget: "600"},
+		{ID: "r7", Platform: "telegram", ConversationID: "c7", ReplyTarget: "700"},
+	}
+	sender := &fakeBroadcastSender{failFor: map[string]error{
+		"600": errors.New("bot was blocked by the user"),
+		"700": fmt.Errorf("%w: telegram is unavailable", channel.ErrOutboundQueued),
+	}}
+	h := newTestBroadcastHandler(routes, sender)
+
+	resp, err := h.broadcast(context.Background(), "bot-1", channel.ChannelType("telegram"), BroadcastRequest{
+		Message: channel.Message{Text: "maintenance tonight"},
+	})
+	if err != nil {
+		t.Fatalf("broadcast: %v", err)
+	}
+
+	if resp.Total != 6 || resp.Sent != 2 || resp.Queued != 1 || resp.Failed != 1 || resp.Skipped != 2 {
+		t.Fatalf("unexpected summary: %+v", resp)
+	}
+	want := map[string]string{
+		"r1": broadcastStatusSent,
+		"r2": broadcastStatusSent,
+		"r4": broadcastStatusSkipped,
+		"r5": broadcastStatusSkipped,
+		"r6": broadcastStatusFailed,
+		"r7": broadcastStatusQueued,
+	}
+	for _, result := range resp.Results {
+		if want[result.RouteID] != result.Status {
+			t.Fatalf("route %s: expected %q, got %q (%s)", result.RouteID, want[result.RouteID], result.Status, result.Error)
+		}
+		if result.Status == broadcastStatusFailed && result.Error != "bot was blocked by the user" {
+			t.Fatalf("expected failure reason, got %q", result.Error)
+		}
+	}
+	if len(sender.sent) != 2 || sender.sent[0] != "100" || sender.sent[1] != "200" {
+		t.Fatalf("unexpected deliveries: %v", sender.sent)
+	}
+}
+
+func TestBroadcastFiltersByConversationType(t *testing.T) {
+	t.Parallel()
+
+	routes := []route.Route{
+		{ID: "r1", Platform: "telegram", ConversationType: "private", ReplyTarget: "100"},
+		{ID: "r2", Platform: "telegram", ConversationType: "group", ReplyTarget: "200"},
+	}
+	sender := &fakeBroadcastSender{}
+	h := newTestBroadcastHandler(routes, sender)
+
+	resp, err := h.broadcast(context.Background(), "bot-1", channel.ChannelType("telegram"), BroadcastRequest{
+		Message:          channel.Message{Text: "hi"},
+		ConversationType: "group",
+	})
+	if err != nil {
+		t.Fatalf("broadcast: %v", err)
+	}
+	if resp.Total != 1 || len(sender.sent) != 1 || sender.sent[0] != "200" {
+		t.Fatalf("expected only the group route, got %+v sent=%v", resp, sender.sent)
+	}
+}
+
+func TestBroadcastSkipsMutedRoutes(t *testing.T) {
+	t.Parallel()
+
+	routes := []route.Route{
+		{ID: "r1", Platform: "telegram", ReplyTarget: "100"},
+		{ID: "r2", Platform: "telegram", ReplyTarget: "200", Muted: true},
+	}
+	sender := &fakeBroadcastSender{}
+	h := newTestBroadcastHandler(routes, sender)
+
+	resp, err := h.broadcast(context.Background(), "bot-1", channel.ChannelType("telegram"), BroadcastRequest{
+		Message: channel.Message{Text: "hi"},
+	})
+	if err != nil {
+		t.Fatalf("broadcast: %v", err)
+	}
+	if resp.Total != 2 || resp.Sent != 1 || resp.Skipped != 1 {
+		t.Fatalf("unexpected summary: %+v", resp)
+	}
+	if got := resp.Results[1]; got.RouteID != "r2" || got.Status != broadcastStatusSkipped {
+		t.Fatalf("expected muted route to be skipped, got %+v", got)
+	}
+	if len(sender.sent) != 1 || sender.sent[0] != "100" {
+		t.Fatalf("unexpected deliveries: %v", sender.sent)
+	}
+}
+
+func TestBroadcastRateLimitIsPerBot(t *testing.T) {
+	t.Parallel()
+
+	h := newTestBroadcastHandler(nil, &fakeBroadcastSender{})
+	if !h.allow("bot-1") {
+		t.Fatal("expected first broadcast to be allowed")
+	}
+	if h.allow("bot-1") {
+		t.Fatal("expected second broadcast from the same bot to be limited")
+	}
+	if !h.allow("bot-2") {
+		t.Fatal("expected another bot to be unaffected")
+	}
+}
@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/memohai/memoh/internal/accounts"
+	"github.com/memohai/memoh/internal/channel"
+)
+
+// Channel connection states.
+const (
+	ChannelConnectionConnected    = "connected"
+	ChannelConnectionError        = "error"
+	ChannelConnectionDisconnected = "disconnected"
+)
+
+// channelConnectionLister reads runtime channel connection statuses. It is
+// the data the channel health checker reports per bot.
+type channelConnectionLister interface {
+	ConnectionStatuses() []channel.ConnectionStatus
+}
+
+// ChannelConnectionsHandler lets admins inspect the live channel
+// connections of all bots.
+type ChannelConnectionsHandler struct {
+	connections    channelConnectionLister
+	accountService *accounts.Service
+	logger         *slog.Logger
+}
+
+// ChannelConnection is the runtime state of one channel config.
+type ChannelConnection struct {
+	ConfigID    string              `json:"config_id"`
+	BotID       string              `json:"bot_id"`
+	ChannelType channel.ChannelType `json:"channel_type"`
+	// State is "connected", "error" or "disconnected".
+	State       string     `json:"state"`
+	LastError   string     `json:"last_error,omitempty"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	LastEventAt *time.Time `json:"last_event_at,omitempty"`
+}
+
+// ChannelConnectionListResponse lists channel connections with a count per
+// state.
+type ChannelConnectionListResponse struct {
+	Items  []ChannelConnection `json:"items"`
+	Counts map[string]int      `json:"counts"`
+}
+
+// NewChannelConnectionsHandler creates a ChannelConnectionsHandler.
+func NewChannelConnectionsHandler(log *slog.Logger, manager *channel.Manager, accountService *accounts.Service) *ChannelConnectionsHandler {
+	return &ChannelConnectionsHandler{
+		connections:    manager,
+		accountService: accountService,
+		logger:         log.With(slog.String("handler", "channel_connections")),
+	}
+}
+
+func (h *ChannelConnectionsHandler) Register(e *echo.Echo) {
+	e.GET("/channel-connections", h.ListConnections)
+}
+
+// ListConnections godoc
+// @Summary List active channel connections (admin only)
+// @Description Lists the channel configs the server currently runs, with their connection state, last error and the time of their last inbound event.
+// @Tags channel
+// @Produce json
+// @Param bot_id query string false "Only connections of this bot"
+// @Param channel_type query string false "Only connections of this channel type"
+// @Param state query string false "Only connections in this state: connected, error or disconnected"
+// @Success 200 {object} ChannelConnectionListResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /channel-connections [get].
+func (h *ChannelConnectionsHandler) ListConnections(c echo.Context) error {
+	if err := RequireAdmin(c, h.accountService); err != nil {
+		return err
+	}
+	state := strings.ToLower(strings.TrimSpace(c.QueryParam("state")))
+	switch state {
+	case "", ChannelConnectionConnected, ChannelConnectionError, ChannelConnectionDisconnected:
+	default:
+		return echo.NewHTTPError(http.StatusBadRequest, "state must be connected, error or disconnected")
+	}
+	return c.JSON(http.StatusOK, h.list(
+		strings.TrimSpace(c.QueryParam("bot_id")),
+		channel.ChannelType(strings.TrimSpace(c.QueryParam("channel_type"))),
+		state,
+	))
+}
+
+// list returns the connections matching the non-empty filters.
+func (h *ChannelConnectionsHandler) list(botID string, channelType channel.ChannelType, state string) ChannelConnectionListResponse {
+	resp := ChannelConnectionListResponse{
+		Items: []ChannelConnection{},
+		Counts: map[string]int{
+			ChannelConnectionConnected:    0,
+			ChannelConnectionError:        0,
+			ChannelConnectionDisconnected: 0,
+		},
+	}
+	if h.connections == nil {
+		return resp
+	}
+	for _, status := range h.connections.ConnectionStatuses() {
+		if botID != "" && status.BotID != botID {
+			continue
+		}
+		if channelType != "" && status.ChannelType != channelType {
+			continue
+		}
+		item := toChannelConnection(status)
+		if state != "" && item.State != state {
+			continue
+		}
+		resp.Items = append(resp.Items, item)
+		resp.Counts[item.State]++
+	}
+	return resp
+}
+
+// toChannelConnection derives the connection state the way the channel
+// health checker does: running is connected, stopped with an error is an
+// error, and anything else is disconnected.
+func toChannelConnection(status channel.ConnectionStatus) ChannelConnection {
+	item := ChannelConnection{
+		ConfigID:    status.ConfigID,
+		BotID:       status.BotID,
+		ChannelType: status.ChannelType,
+		State:       ChannelConnectionDisconnected,
+		LastError:   strings.TrimSpace(status.LastError),
+		UpdatedAt:   status.UpdatedAt,
+		LastEventAt: status.LastEventAt,
+	}
+	switch {
+	case status.Running:
+		item.State = ChannelConnectionConnected
+	case item.LastError != "":
+		item.State = ChannelConnectionError
+	}
+	return item
+}
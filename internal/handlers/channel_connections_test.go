@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"log/slog"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/memohai/memoh/internal/channel"
+)
+
+type fakeConnectionLister struct {
+	items []channel.ConnectionStatus
+}
+
+func (f *fakeConnectionLister) ConnectionStatuses() []channel.ConnectionStatus {
+	return f.items
+}
+
+func TestChannelConnectionsListMixedStates(t *testing.T) {
+	lastEvent := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	h := NewChannelConnectionsHandler(slog.New(slog.DiscardHandler), nil, nil)
+	h.connections = &fakeConnectionLister{items: []channel.ConnectionStatus{
+		{ConfigID: "cfg-1", BotID: "bot-1", ChannelType: "telegram", Running: true, LastEventAt: &lastEvent},
+		{ConfigID: "cfg-2", BotID: "bot-1", ChannelType: "discord", LastError: " invalid token "},
+		{ConfigID: "cfg-3", BotID: "bot-2", ChannelType: "telegram"},
+	}}
+
+	resp := h.list("", "", "")
+	if len(resp.Items) != 3 {
+		t.Fatalf("items = %d, want 3", len(resp.Items))
+	}
+	want := map[string]string{
+		"cfg-1": ChannelConnectionConnected,
+		"cfg-2": ChannelConnectionError,
+		"cfg-3": ChannelConnectionDisconnected,
+	}
+	for _, item := range resp.Items {
+		if item.State != want[item.ConfigID] {
+			t.Fatalf("%s state = %q, want %q", item.ConfigID, item.State, want[item.ConfigID])
+		}
+	}
+	if got := resp.Items[0].LastEventAt; got == nil || !got.Equal(lastEvent) {
+		t.Fatalf("last event at = %v, want %v", got, lastEvent)
+	}
+	if resp.Items[1].LastError != "invalid token" || resp.Items[2].LastEventAt != nil {
+		t.Fatalf("unexpected items: %+v", resp.Items)
+	}
+	for state, count := range map[string]int{
+		ChannelConnectionConnected:    1,
+		ChannelConnectionError:        1,
+		ChannelConnectionDisconnected: 1,
+	} {
+		if resp.Counts[state] != count {
+			t.Fatalf("count[%s] = %d, want %d", state, resp.Counts[state], count)
+		}
+	}
+}
+
+func TestChannelConnectionsListFilters(t *testing.T) {
+	h := NewChannelConnectionsHandler(slog.New(slog.DiscardHandler), nil, nil)
+	h.connections = &fakeConnectionLister{items: []channel.ConnectionStatus{
+		{ConfigID: "cfg-1", BotID: "bot-1", ChannelType: "telegram", Running: true},
+		{ConfigID: "cfg-2", BotID: "bot-1", ChannelType: "discord", LastError: "invalid token"},
+		{ConfigID: "cfg-3", BotID: "bot-2", ChannelType: "telegram", LastError: "timeout"},
+	}}
+
+	cases := []struct {
+		botID       string
+		channelType channel.ChannelType
+		state       string
+		want        []string
+	}{
+		{botID: "bot-1", want: []string{"cfg-1", "cfg-2"}},
+		{channelType: "telegram", want: []string{"cfg-1", "cfg-3"}},
+		{state: ChannelConnectionError, want: []string{"cfg-2", "cfg-3"}},
+		{botID: "bot-2", state: ChannelConnectionConnected, want: []string{}},
+	}
+	for _, tc := range cases {
+		resp := h.list(tc.botID, tc.channelType, tc.state)
+		got := make([]string, 0, len(resp.Items))
+		for _, item := range resp.Items {
+			got = append(got, item.ConfigID)
+		}
+		if !slices.Equal(got, tc.want) {
+			t.Fatalf("filter %+v got %v, want %v", tc, got, tc.want)
+		}
+	}
+}
@@ -39,6 +39,7 @@ type ContainerdHandler struct {
 	botService       *bots.Service
 	accountService   *accounts.Service
 	policyService    *policy.Service
+	skills           *skillsCache
 }
 
 type ContainerGPURequest struct {
@@ -155,6 +156,7 @@ func NewContainerdHandler(log *slog.Logger, manager *workspace.Manager, cfg conf
 		botService:       botService,
 		accountService:   accountService,
 		policyService:    policyService,
+		skills:           newSkillsCache(skillsCacheTTL),
 	}
 	return h
 }
@@ -166,6 +168,7 @@ func (h *ContainerdHandler) Register(e *echo.Echo) {
 	group.DELETE("", h.DeleteContainer)
 	group.POST("/start", h.StartContainer)
 	group.POST("/stop", h.StopContainer)
+	group.GET("/logs", h.StreamContainerLogs)
 	group.POST("/snapshots", h.CreateSnapshot)
 	group.GET("/snapshots", h.ListSnapshots)
 	group.POST("/snapshots/rollback", h.RollbackSnapshot)
@@ -175,6 +178,7 @@ func (h *ContainerdHandler) Register(e *echo.Echo) {
 	group.GET("/skills", h.ListSkills)
 	group.POST("/skills", h.UpsertSkills)
 	group.DELETE("/skills", h.DeleteSkills)
+	group.POST("/skills/reload", h.ReloadSkills)
 	// Terminal routes
 	group.GET("/terminal", h.GetTerminalInfo)
 	group.GET("/terminal/ws", h.HandleTerminalWS)
@@ -672,6 +676,7 @@ func (h *ContainerdHandler) RollbackSnapshot(c echo.Context) error {
 
 // ExportContainerData godoc
 // @Summary Export container /data as a tar.gz archive
+// @Description Streams the archive. If the bot has no container, its preserved data archive is streamed instead.
 // @Tags containerd
 // @Param bot_id path string true "Bot ID"
 // @Produce application/gzip
@@ -702,10 +707,13 @@ func (h *ContainerdHandler) ExportContainerData(c echo.Context) error {
 
 // ImportContainerData godoc
 // @Summary Import a tar.gz archive into container /data
+// @Description Accepts either a multipart upload or a raw application/gzip body, which is streamed without buffering.
+// @Description If the bot has no container yet, the archive is staged and restored when the container is created.
 // @Tags containerd
 // @Param bot_id path string true "Bot ID"
 // @Accept multipart/form-data
-// @Param file formData file true "tar.gz archive"
+// @Accept application/gzip
+// @Param file formData file false "tar.gz archive"
 // @Success 200 {object} object
 // @Failure 400 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
@@ -719,20 +727,37 @@ func (h *ContainerdHandler) ImportContainerData(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusInternalServerError, "manager not configured")
 	}
 
-	file, err := c.FormFile("file")
-	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, "file is required")
-	}
-	src, err := file.Open()
+	src, err := importDataSource(c)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, "failed to open uploaded file")
+		return err
 	}
 	defer func() { _ = src.Close() }()
 
-	if err := h.manager.ImportData(c.Request().Context(), botID, src); err != nil {
+	staged, err := h.manager.ImportOrStageData(c.Request().Context(), botID, src)
+	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
 	}
-	return c.JSON(http.StatusOK, map[string]bool{"imported": true})
+	return c.JSON(http.StatusOK, map[string]bool{"imported": !staged, "staged": staged})
+}
+
+// importDataSource returns the archive stream of an import request. Multipart
+// uploads use the "file" field; any other body is streamed as-is.
+func importDataSource(c echo.Context) (io.ReadCloser, error) {
+	if !strings.HasPrefix(c.Request().Header.Get(echo.HeaderContentType), echo.MIMEMultipartForm) {
+		if c.Request().Body == nil || c.Request().ContentLength == 0 {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, "archive body is required")
+		}
+		return c.Request().Body, nil
+	}
+	file, err := c.FormFile("file")
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "file is required")
+	}
+	src, err := file.Open()
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "failed to open uploaded file")
+	}
+	return src, nil
 }
 
 // RestorePreservedData godoc
@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	ctr "github.com/memohai/memoh/internal/containerd"
+	"github.com/memohai/memoh/internal/workspace"
+)
+
+const (
+	defaultContainerLogLines = 200
+	maxContainerLogLines     = 5000
+	// maxContainerLogLineSize bounds a single log line; a longer line ends the stream.
+	maxContainerLogLineSize = 64 << 10
+)
+
+type containerLogEvent struct {
+	Type string `json:"type"`
+	Line string `json:"line"`
+}
+
+// StreamContainerLogs godoc
+// @Summary Stream container stdout/stderr logs for bot
+// @Description Streams the last `lines` log lines as SSE `log` events and, when follow is enabled, keeps streaming new output.
+// @Tags containerd
+// @Param bot_id path string true "Bot ID"
+// @Param lines query int false "Number of trailing lines to return (default 200, max 5000)"
+// @Param follow query bool false "Keep streaming new output (default true)"
+// @Produce text/event-stream
+// @Success 200 {string} string "SSE stream of log lines"
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Failure 501 {object} ErrorResponse "Log streaming not supported on this backend"
+// @Router /bots/{bot_id}/container/logs [get].
+func (h *ContainerdHandler) StreamContainerLogs(c echo.Context) error {
+	botID, err := h.requireBotAccess(c)
+	if err != nil {
+		return err
+	}
+	if h.manager == nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "manager not configured")
+	}
+	opts, err := parseContainerLogOptions(c)
+	if err != nil {
+		return err
+	}
+
+	reader, err := h.manager.ContainerLogs(c.Request().Context(), botID, opts)
+	if err != nil {
+		switch {
+		case errors.Is(err, workspace.ErrContainerNotFound):
+			return echo.NewHTTPError(http.StatusNotFound, "container not found for bot")
+		case errors.Is(err, os.ErrNotExist):
+			return echo.NewHTTPError(http.StatusNotFound, "no logs recorded for container")
+		case errors.Is(err, ctr.ErrNotSupported):
+			return echo.NewHTTPError(http.StatusNotImplemented, "log streaming not supported on this backend")
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	defer func() { _ = reader.Close() }()
+
+	flusher, ok := c.Response().Writer.(http.Flusher)
+	if !ok {
+		return echo.NewHTTPError(http.StatusInternalServerError, "streaming not supported")
+	}
+	c.Response().Header().Set(echo.HeaderContentType, "text/event-stream")
+	c.Response().Header().Set(echo.HeaderCacheControl, "no-cache")
+	c.Response().Header().Set(echo.HeaderConnection, "keep-alive")
+	c.Response().WriteHeader(http.StatusOK)
+
+	if err := streamLogLines(bufio.NewWriter(c.Response().Writer), flusher, reader); err != nil {
+		h.logger.Debug("container log stream ended",
+			slog.String("bot_id", botID), slog.Any("error", err))
+	}
+	return nil
+}
+
+// parseContainerLogOptions reads the lines and follow query parameters.
+func parseContainerLogOptions(c echo.Context) (*ctr.TaskLogsOptions, error) {
+	opts := &ctr.TaskLogsOptions{Tail: defaultContainerLogLines, Follow: true}
+	if raw := strings.TrimSpace(c.QueryParam("lines")); raw != "" {
+		lines, err := strconv.Atoi(raw)
+		if err != nil || lines <= 0 {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, "lines must be a positive integer")
+		}
+		opts.Tail = min(lines, maxContainerLogLines)
+	}
+	if raw := strings.TrimSpace(c.QueryParam("follow")); raw != "" {
+		follow, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, "follow must be a boolean")
+		}
+		opts.Follow = follow
+	}
+	return opts, nil
+}
+
+// streamLogLines forwards each line of r as an SSE log event until r is
+// exhausted or the client goes away.
+func streamLogLines(writer *bufio.Writer, flusher http.Flusher, r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 4096), maxContainerLogLineSize)
+	for scanner.Scan() {
+		data, err := json.Marshal(containerLogEvent{Type: "log", Line: strings.TrimRight(scanner.Text(), "\r")})
+		if err != nil {
+			return err
+		}
+		if err := writeSSEData(writer, flusher, string(data)); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
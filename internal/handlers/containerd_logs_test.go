@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+// fakeTaskLog yields log lines the way a running task writes them: one write
+// at a time, then closes its output.
+func fakeTaskLog(lines ...string) io.Reader {
+	pr, pw := io.Pipe()
+	go func() {
+		for _, line := range lines {
+			if _, err := io.WriteString(pw, line+"\n"); err != nil {
+				return
+			}
+		}
+		_ = pw.Close()
+	}()
+	return pr
+}
+
+func TestStreamLogLinesEmitsSSEEvents(t *testing.T) {
+	t.Parallel()
+	rec := httptest.NewRecorder()
+	writer := bufio.NewWriter(rec)
+
+	err := streamLogLines(writer, rec, fakeTaskLog("server started", "listening on :8080\r", `quote "x"`))
+	if err != nil {
+		t.Fatalf("streamLogLines: %v", err)
+	}
+
+	var got []string
+	for _, block := range strings.Split(strings.TrimSpace(rec.Body.String()), "\n\n") {
+		payload, ok := strings.CutPrefix(block, "data: ")
+		if !ok {
+			t.Fatalf("unexpected SSE block %q", block)
+		}
+		var event containerLogEvent
+		if err := json.Unmarshal([]byte(payload), &event); err != nil {
+			t.Fatalf("decode event: %v", err)
+		}
+		if event.Type != "log" {
+			t.Fatalf("expected log event, got %q", event.Type)
+		}
+		got = append(got, event.Line)
+	}
+	want := []string{"server started", "listening on :8080", `quote "x"`}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Fatalf("got lines %q, want %q", got, want)
+	}
+}
+
+func TestParseContainerLogOptions(t *testing.T) {
+	t.Parallel()
+	e := echo.New()
+	parse := func(query string) (int, bool, error) {
+		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/logs?"+query, nil)
+		opts, err := parseContainerLogOptions(e.NewContext(req, httptest.NewRecorder()))
+		if err != nil {
+			return 0, false, err
+		}
+		return opts.Tail, opts.Follow, nil
+	}
+
+	if tail, follow, err := parse(""); err != nil || tail != defaultContainerLogLines || !follow {
+		t.Fatalf("defaults: tail=%d follow=%v err=%v", tail, follow, err)
+	}
+	if tail, follow, err := parse("lines=50&follow=false"); err != nil || tail != 50 || follow {
+		t.Fatalf("explicit: tail=%d follow=%v err=%v", tail, follow, err)
+	}
+	if tail, _, err := parse("lines=999999"); err != nil || tail != maxContainerLogLines {
+		t.Fatalf("capped: tail=%d err=%v", tail, err)
+	}
+	for _, query := range []string{"lines=abc", "lines=0", "follow=maybe"} {
+		if _, _, err := parse(query); err == nil {
+			t.Fatalf("expected error for %q", query)
+		}
+	}
+}
@@ -36,6 +36,10 @@ func NewEmbeddedWebHandler(log *slog.Logger) (*EmbeddedWebHandler, error) {
 	return &EmbeddedWebHandler{log: log, webFS: webFS}, nil
 }
 
+func (*EmbeddedWebHandler) HandlerName() string {
+	return "webui"
+}
+
 func (h *EmbeddedWebHandler) Register(e *echo.Echo) {
 	e.GET("/assets/*", h.serveAsset)
 	for route, meta := range embeddedStaticRoutes {
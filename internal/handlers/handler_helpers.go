@@ -49,6 +49,25 @@ func AuthorizeBotAccess(ctx context.Context, botService *bots.Service, accountSe
 	return bot, nil
 }
 
+// RequireAdmin validates that the caller is an authenticated admin.
+func RequireAdmin(c echo.Context, accountService *accounts.Service) error {
+	channelIdentityID, err := RequireChannelIdentityID(c)
+	if err != nil {
+		return err
+	}
+	if accountService == nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "account service not configured")
+	}
+	isAdmin, err := accountService.IsAdmin(c.Request().Context(), channelIdentityID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	if !isAdmin {
+		return echo.NewHTTPError(http.StatusForbidden, "admin role required")
+	}
+	return nil
+}
+
 // parseOffsetLimit extracts limit and offset query parameters with defaults.
 func parseOffsetLimit(c echo.Context) (limit, offset int) {
 	limit = 50
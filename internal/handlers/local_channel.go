@@ -86,6 +86,11 @@ func (h *LocalChannelHandler) SetTtsService(synth localTtsSynthesizer, resolver
 	h.ttsModelResolver = resolver
 }
 
+// HandlerName returns the channel type, so each local channel can be disabled.
+func (h *LocalChannelHandler) HandlerName() string {
+	return h.channelType.String()
+}
+
 // Register registers the local channel routes.
 func (h *LocalChannelHandler) Register(e *echo.Echo) {
 	prefix := fmt.Sprintf("/bots/:bot_id/%s", h.channelType.String())
@@ -868,7 +873,7 @@ func extractAssetRefsFromProcessedEvent(event json.RawMessage) []messagepkg.Asse
 		}
 		ref := messagepkg.AssetRef{
 			ContentHash: ch,
-			Role:        "attachment",
+			Role:        messagepkg.AssetRoleFromMetadata(att.Metadata),
 			Ordinal:     i,
 			Name:        name,
 			Mime:        strings.TrimSpace(att.Mime),
@@ -73,3 +73,21 @@ func TestFormatLocalStreamEvent_EncodesToolCallAsToolCallObject(t *testing.T) {
 		t.Fatalf("unexpected camelCase toolName in payload")
 	}
 }
+
+func TestExtractAssetRefsFromProcessedEventTakesRoleFromMetadata(t *testing.T) {
+	t.Parallel()
+
+	event := json.RawMessage(`{"type":"attachment_delta","attachments":[` +
+		`{"content_hash":"h1","metadata":{"asset_role":"generated_image"}},` +
+		`{"content_hash":"h2"}]}`)
+	refs := extractAssetRefsFromProcessedEvent(event)
+	if len(refs) != 2 {
+		t.Fatalf("expected 2 refs, got %d", len(refs))
+	}
+	if refs[0].Role != "generated_image" {
+		t.Fatalf("expected role from metadata, got %q", refs[0].Role)
+	}
+	if refs[1].Role != "attachment" {
+		t.Fatalf("expected default role, got %q", refs[1].Role)
+	}
+}
@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/memohai/memoh/internal/accounts"
+	"github.com/memohai/memoh/internal/server"
+)
+
+// MaintenanceHandler lets admins view and toggle maintenance mode.
+type MaintenanceHandler struct {
+	maintenance    *server.Maintenance
+	accountService *accounts.Service
+	logger         *slog.Logger
+}
+
+// SetMaintenanceRequest turns maintenance mode on or off.
+type SetMaintenanceRequest struct {
+	Enabled bool   `json:"enabled"`
+	Message string `json:"message,omitempty"`
+}
+
+// NewMaintenanceHandler creates a MaintenanceHandler.
+func NewMaintenanceHandler(log *slog.Logger, maintenance *server.Maintenance, accountService *accounts.Service) *MaintenanceHandler {
+	return &MaintenanceHandler{
+		maintenance:    maintenance,
+		accountService: accountService,
+		logger:         log.With(slog.String("handler", "maintenance")),
+	}
+}
+
+func (h *MaintenanceHandler) Register(e *echo.Echo) {
+	e.GET("/maintenance", h.GetMaintenance)
+	e.PUT("/maintenance", h.SetMaintenance)
+}
+
+// GetMaintenance godoc
+// @Summary Get maintenance mode
+// @Description Reports whether the server is in maintenance mode, in which mutating endpoints answer 503.
+// @Tags system
+// @Produce json
+// @Success 200 {object} server.MaintenanceStatus
+// @Failure 400 {object} ErrorResponse
+// @Router /maintenance [get].
+func (h *MaintenanceHandler) GetMaintenance(c echo.Context) error {
+	if _, err := RequireChannelIdentityID(c); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, h.maintenance.Status())
+}
+
+// SetMaintenance godoc
+// @Summary Set maintenance mode (admin only)
+// @Description Turns maintenance mode on or off. While on, mutating endpoints answer 503 with the message and reads continue. The state resets to the configured mode on restart.
+// @Tags system
+// @Accept json
+// @Produce json
+// @Param payload body SetMaintenanceRequest true "Maintenance mode"
+// @Success 200 {object} server.MaintenanceStatus
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /maintenance [put].
+func (h *MaintenanceHandler) SetMaintenance(c echo.Context) error {
+	if err := RequireAdmin(c, h.accountService); err != nil {
+		return err
+	}
+	var req SetMaintenanceRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	status := h.maintenance.Set(req.Enabled, req.Message)
+	h.logger.Info("maintenance mode changed", slog.Bool("enabled", status.Enabled), slog.String("message", status.Message))
+	return c.JSON(http.StatusOK, status)
+}
@@ -34,6 +34,10 @@ func NewMCPHandler(log *slog.Logger, service *mcp.ConnectionService, botService
 	}
 }
 
+func (*MCPHandler) HandlerName() string {
+	return "mcp"
+}
+
 func (h *MCPHandler) Register(e *echo.Echo) {
 	group := e.Group("/bots/:bot_id/mcp")
 	group.GET("", h.List)
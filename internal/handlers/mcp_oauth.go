@@ -34,6 +34,12 @@ func NewMCPOAuthHandler(log *slog.Logger, oauthService *mcp.OAuthService, connSe
 	}
 }
 
+// HandlerName shares the MCP handler's name, so disabling "mcp" removes
+// both.
+func (*MCPOAuthHandler) HandlerName() string {
+	return "mcp"
+}
+
 func (h *MCPOAuthHandler) Register(e *echo.Echo) {
 	group := e.Group("/bots/:bot_id/mcp/:id/oauth")
 	group.POST("/discover", h.Discover)
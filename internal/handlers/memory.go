@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
@@ -96,23 +97,29 @@ func (h *MemoryHandler) SetSettingsService(svc *settings.Service) {
 
 // resolveProvider returns the memory provider for a bot, or nil if not configured.
 func (h *MemoryHandler) resolveProvider(ctx context.Context, botID string) memprovider.Provider {
-	if h.memoryRegistry == nil {
+	return resolveMemoryProvider(ctx, h.logger, h.memoryRegistry, h.settingsService, botID)
+}
+
+// resolveMemoryProvider returns the memory provider configured for a bot,
+// falling back to the built-in default provider.
+func resolveMemoryProvider(ctx context.Context, logger *slog.Logger, registry *memprovider.Registry, settingsService *settings.Service, botID string) memprovider.Provider {
+	if registry == nil {
 		return nil
 	}
-	if h.settingsService != nil {
-		botSettings, err := h.settingsService.GetBot(ctx, botID)
+	if settingsService != nil {
+		botSettings, err := settingsService.GetBot(ctx, botID)
 		if err == nil {
 			providerID := strings.TrimSpace(botSettings.MemoryProviderID)
 			if providerID != "" {
-				p, getErr := h.memoryRegistry.Get(providerID)
+				p, getErr := registry.Get(providerID)
 				if getErr == nil {
 					return p
 				}
-				h.logger.Warn("memory provider lookup failed", slog.String("provider_id", providerID), slog.Any("error", getErr))
+				logger.Warn("memory provider lookup failed", slog.String("provider_id", providerID), slog.Any("error", getErr))
 			}
 		}
 	}
-	p, err := h.memoryRegistry.Get(defaultBuiltinProviderID)
+	p, err := registry.Get(defaultBuiltinProviderID)
 	if err != nil {
 		return nil
 	}
@@ -138,6 +145,7 @@ func (h *MemoryHandler) Register(e *echo.Echo) {
 	chatGroup.GET("/status", h.ChatStatus)
 	chatGroup.GET("", h.ChatGetAll)
 	chatGroup.GET("/usage", h.ChatUsage)
+	chatGroup.GET("/namespaces", h.ChatNamespaces)
 	chatGroup.DELETE("", h.ChatDelete)
 	chatGroup.DELETE("/:memory_id", h.ChatDeleteOne)
 }
@@ -162,7 +170,9 @@ func (h *MemoryHandler) checkService(ctx context.Context, botID string) (memprov
 // @Success 200 {object} adapters.SearchResponse
 // @Failure 400 {object} ErrorResponse
 // @Failure 403 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
+// @Failure 502 {object} ErrorResponse
 // @Failure 503 {object} ErrorResponse
 // @Router /bots/{bot_id}/memory [post].
 func (h *MemoryHandler) ChatAdd(c echo.Context) error {
@@ -208,7 +218,7 @@ func (h *MemoryHandler) ChatAdd(c echo.Context) error {
 	}
 	resp, err := provider.Add(c.Request().Context(), req)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		return memoryHTTPError(err)
 	}
 	return c.JSON(http.StatusOK, resp)
 }
@@ -349,7 +359,7 @@ func (h *MemoryHandler) ChatDelete(c echo.Context) error {
 	if len(payload.MemoryIDs) > 0 {
 		resp, delErr := provider.DeleteBatch(c.Request().Context(), payload.MemoryIDs)
 		if delErr != nil {
-			return echo.NewHTTPError(http.StatusInternalServerError, delErr.Error())
+			return memoryHTTPError(delErr)
 		}
 		return c.JSON(http.StatusOK, resp)
 	}
@@ -379,6 +389,7 @@ func (h *MemoryHandler) ChatDelete(c echo.Context) error {
 // @Success 200 {object} adapters.DeleteResponse
 // @Failure 400 {object} ErrorResponse
 // @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Failure 503 {object} ErrorResponse
 // @Router /bots/{bot_id}/memory/{id} [delete].
@@ -398,7 +409,7 @@ func (h *MemoryHandler) ChatDeleteOne(c echo.Context) error {
 	}
 	resp, err := provider.Delete(c.Request().Context(), memoryID)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		return memoryHTTPError(err)
 	}
 	return c.JSON(http.StatusOK, resp)
 }
@@ -459,7 +470,7 @@ func (h *MemoryHandler) ChatCompact(c echo.Context) error {
 	filters := buildNamespaceFilters(scope.Namespace, scope.ScopeID, nil)
 	result, err := provider.Compact(c.Request().Context(), filters, ratio, decayDays)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		return memoryHTTPError(err)
 	}
 	return c.JSON(http.StatusOK, result)
 }
@@ -509,6 +520,34 @@ func (h *MemoryHandler) ChatUsage(c echo.Context) error {
 	return c.JSON(http.StatusOK, totalUsage)
 }
 
+// ChatNamespaces godoc
+// @Summary List memory namespaces
+// @Description List the memory namespaces of a bot together with their item counts
+// @Tags memory
+// @Produce json
+// @Param bot_id path string true "Bot ID"
+// @Success 200 {array} adapters.NamespaceStats
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Failure 503 {object} ErrorResponse
+// @Router /bots/{bot_id}/memory/namespaces [get].
+func (h *MemoryHandler) ChatNamespaces(c echo.Context) error {
+	botID, err := h.requireBotAccess(c)
+	if err != nil {
+		return err
+	}
+	provider, checkErr := h.checkService(c.Request().Context(), botID)
+	if checkErr != nil {
+		return checkErr
+	}
+	namespaces, err := memprovider.ListNamespaces(c.Request().Context(), provider, botID)
+	if err != nil {
+		return memoryHTTPError(err)
+	}
+	return c.JSON(http.StatusOK, namespaces)
+}
+
 // ChatRebuild godoc
 // @Summary Rebuild memories from filesystem
 // @Description Read memory files from the container filesystem (source of truth) and restore missing entries to memory storage
@@ -537,14 +576,14 @@ func (h *MemoryHandler) ChatRebuild(c echo.Context) error {
 	}
 	status, err := syncProvider.Status(c.Request().Context(), botID)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		return memoryHTTPError(err)
 	}
 	if !status.CanManualSync {
 		return echo.NewHTTPError(http.StatusConflict, "manual sync is not available for the selected memory provider")
 	}
 	result, err := syncProvider.Rebuild(c.Request().Context(), botID)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		return memoryHTTPError(err)
 	}
 	return c.JSON(http.StatusOK, result)
 }
@@ -577,13 +616,33 @@ func (h *MemoryHandler) ChatStatus(c echo.Context) error {
 	}
 	status, err := syncProvider.Status(c.Request().Context(), botID)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		return memoryHTTPError(err)
 	}
 	return c.JSON(http.StatusOK, status)
 }
 
 // --- helpers ---
 
+// memoryHTTPError maps memory provider domain errors to HTTP status codes.
+func memoryHTTPError(err error) *echo.HTTPError {
+	var httpErr *echo.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr
+	}
+	switch {
+	case errors.Is(err, memprovider.ErrMemoryNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case errors.Is(err, memprovider.ErrDimensionMismatch):
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	case errors.Is(err, memprovider.ErrEmbedderUnavailable):
+		return echo.NewHTTPError(http.StatusBadGateway, err.Error())
+	case errors.Is(err, memprovider.ErrStoreUnreachable):
+		return echo.NewHTTPError(http.StatusServiceUnavailable, err.Error())
+	default:
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+}
+
 // resolveEnabledScopes returns bot-shared namespace scope.
 func (*MemoryHandler) resolveEnabledScopes(botID string) ([]namespaceScope, error) {
 	botID = strings.TrimSpace(botID)
@@ -808,6 +867,7 @@ func (r *fileMemoryRuntime) Update(ctx context.Context, req memprovider.UpdateRe
 	existing.Memory = text
 	existing.Hash = runtimeHash(text)
 	existing.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+	existing.Metadata = memprovider.MergeMetadata(existing.Metadata, req.Metadata)
 	itemsToPersist := []storefs.MemoryItem{runtimeToStoreItem(*existing)}
 	if err := r.store.PersistMemories(ctx, botID, itemsToPersist, nil); err != nil {
 		return memprovider.MemoryItem{}, err
@@ -1,24 +1,28 @@
 package handlers
 
 import (
+	"errors"
 	"log/slog"
 	"net/http"
 	"strings"
 
 	"github.com/labstack/echo/v4"
 
+	"github.com/memohai/memoh/internal/accounts"
 	memprovider "github.com/memohai/memoh/internal/memory/adapters"
 )
 
 type MemoryProvidersHandler struct {
-	service *memprovider.Service
-	logger  *slog.Logger
+	service        *memprovider.Service
+	accountService *accounts.Service
+	logger         *slog.Logger
 }
 
-func NewMemoryProvidersHandler(log *slog.Logger, service *memprovider.Service) *MemoryProvidersHandler {
+func NewMemoryProvidersHandler(log *slog.Logger, service *memprovider.Service, accountService *accounts.Service) *MemoryProvidersHandler {
 	return &MemoryProvidersHandler{
-		service: service,
-		logger:  log.With(slog.String("handler", "memory_providers")),
+		service:        service,
+		accountService: accountService,
+		logger:         log.With(slog.String("handler", "memory_providers")),
 	}
 }
 
@@ -29,6 +33,8 @@ func (h *MemoryProvidersHandler) Register(e *echo.Echo) {
 	group.GET("", h.List)
 	group.GET("/:id", h.Get)
 	group.GET("/:id/status", h.Status)
+	group.POST("/:id/reindex", h.Reindex)
+	group.GET("/:id/reindex", h.ReindexStatus)
 	group.PUT("/:id", h.Update)
 	group.DELETE("/:id", h.Delete)
 }
@@ -133,6 +139,62 @@ func (h *MemoryProvidersHandler) Status(c echo.Context) error {
 	return c.JSON(http.StatusOK, resp)
 }
 
+// Reindex godoc
+// @Summary Reindex memory provider (admin only)
+// @Description Start a background reindex that re-embeds every memory of the bots bound to this provider and rebuilds the BM25 index. Only one reindex per provider may run at a time.
+// @Tags memory-providers
+// @Produce json
+// @Param id path string true "Provider ID"
+// @Success 202 {object} adapters.ReindexProgress
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /memory-providers/{id}/reindex [post].
+func (h *MemoryProvidersHandler) Reindex(c echo.Context) error {
+	if err := RequireAdmin(c, h.accountService); err != nil {
+		return err
+	}
+	id := strings.TrimSpace(c.Param("id"))
+	if id == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "id is required")
+	}
+	progress, err := h.service.Reindex(c.Request().Context(), id)
+	if err != nil {
+		if errors.Is(err, memprovider.ErrReindexInProgress) {
+			return echo.NewHTTPError(http.StatusConflict, err.Error())
+		}
+		return memoryHTTPError(err)
+	}
+	return c.JSON(http.StatusAccepted, progress)
+}
+
+// ReindexStatus godoc
+// @Summary Get memory reindex progress (admin only)
+// @Description Get the progress of the latest reindex run for a memory provider
+// @Tags memory-providers
+// @Produce json
+// @Param id path string true "Provider ID"
+// @Success 200 {object} adapters.ReindexProgress
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /memory-providers/{id}/reindex [get].
+func (h *MemoryProvidersHandler) ReindexStatus(c echo.Context) error {
+	if err := RequireAdmin(c, h.accountService); err != nil {
+		return err
+	}
+	id := strings.TrimSpace(c.Param("id"))
+	if id == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "id is required")
+	}
+	progress, ok := h.service.ReindexProgress(id)
+	if !ok {
+		return echo.NewHTTPError(http.StatusNotFound, "no reindex has been started for this provider")
+	}
+	return c.JSON(http.StatusOK, progress)
+}
+
 // Update godoc
 // @Summary Update a memory provider
 // @Description Update memory provider by ID
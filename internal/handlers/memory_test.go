@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+
+	memprovider "github.com/memohai/memoh/internal/memory/adapters"
+)
+
+func TestMemoryHTTPError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantMsg    string
+	}{
+		{
+			name:       "not found",
+			err:        fmt.Errorf("dense runtime: %w", memprovider.ErrMemoryNotFound),
+			wantStatus: http.StatusNotFound,
+			wantMsg:    "dense runtime: memory not found",
+		},
+		{
+			name:       "dimension mismatch",
+			err:        fmt.Errorf("dense embed query: %w: expected 1536, got 768", memprovider.ErrDimensionMismatch),
+			wantStatus: http.StatusConflict,
+			wantMsg:    "dense embed query: embedding dimension mismatch: expected 1536, got 768",
+		},
+		{
+			name:       "embedder unavailable",
+			err:        fmt.Errorf("sparse encode query: %w: %w", memprovider.ErrEmbedderUnavailable, errors.New("connection refused")),
+			wantStatus: http.StatusBadGateway,
+			wantMsg:    "sparse encode query: embedder unavailable: connection refused",
+		},
+		{
+			name:       "store unreachable",
+			err:        fmt.Errorf("%w: %w", memprovider.ErrStoreUnreachable, errors.New("qdrant: deadline exceeded")),
+			wantStatus: http.StatusServiceUnavailable,
+			wantMsg:    "memory store unreachable: qdrant: deadline exceeded",
+		},
+		{
+			name:       "http error passthrough",
+			err:        echo.NewHTTPError(http.StatusBadRequest, "memory_id is required"),
+			wantStatus: http.StatusBadRequest,
+			wantMsg:    "memory_id is required",
+		},
+		{
+			name:       "unknown",
+			err:        errors.New("boom"),
+			wantStatus: http.StatusInternalServerError,
+			wantMsg:    "boom",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := memoryHTTPError(tt.err)
+			if got.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", got.Code, tt.wantStatus)
+			}
+			if msg, _ := got.Message.(string); msg != tt.wantMsg {
+				t.Fatalf("message = %q, want %q", got.Message, tt.wantMsg)
+			}
+		})
+	}
+}
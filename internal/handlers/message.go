@@ -19,8 +19,11 @@ import (
 	"github.com/memohai/memoh/internal/bots"
 	"github.com/memohai/memoh/internal/conversation"
 	"github.com/memohai/memoh/internal/media"
+	memprovider "github.com/memohai/memoh/internal/memory/adapters"
 	messagepkg "github.com/memohai/memoh/internal/message"
 	messageevent "github.com/memohai/memoh/internal/message/event"
+	"github.com/memohai/memoh/internal/settings"
+	"github.com/memohai/memoh/internal/storage"
 )
 
 // MessageHandler handles bot-scoped messaging endpoints.
@@ -31,9 +34,18 @@ type MessageHandler struct {
 	mediaService        *media.Service
 	botService          *bots.Service
 	accountService      *accounts.Service
+	sessionDropper      sessionDropper
+	memoryRegistry      *memprovider.Registry
+	settingsService     *settings.Service
 	logger              *slog.Logger
 }
 
+// sessionDropper discards the in-memory context of a session so it is
+// rebuilt from storage on the next turn.
+type sessionDropper interface {
+	DropSession(sessionID string)
+}
+
 // NewMessageHandler creates a MessageHandler.
 func NewMessageHandler(log *slog.Logger, conversationService conversation.Accessor, messageService messagepkg.Service, botService *bots.Service, accountService *accounts.Service, eventSubscribers ...messageevent.Subscriber) *MessageHandler {
 	var messageEvents messageevent.Subscriber
@@ -55,6 +67,19 @@ func (h *MessageHandler) SetMediaService(svc *media.Service) {
 	h.mediaService = svc
 }
 
+// SetSessionDropper sets the pipeline whose session context is discarded
+// after a redaction.
+func (h *MessageHandler) SetSessionDropper(d sessionDropper) {
+	h.sessionDropper = d
+}
+
+// SetMemoryProviders sets the memory providers that phrase redactions are
+// applied to.
+func (h *MessageHandler) SetMemoryProviders(registry *memprovider.Registry, settingsService *settings.Service) {
+	h.memoryRegistry = registry
+	h.settingsService = settingsService
+}
+
 // Register registers all conversation routes.
 func (h *MessageHandler) Register(e *echo.Echo) {
 	// Bot-scoped message container (single shared history per bot).
@@ -62,7 +87,11 @@ func (h *MessageHandler) Register(e *echo.Echo) {
 	botGroup.GET("/messages", h.ListMessages)
 	botGroup.GET("/messages/events", h.StreamMessageEvents)
 	botGroup.DELETE("/messages", h.DeleteMessages)
+	botGroup.POST("/messages/:message_id/redact", h.RedactMessage)
+	botGroup.GET("/tool-calls", h.ListToolCalls)
 	botGroup.GET("/media/:content_hash", h.ServeMedia)
+	botGroup.GET("/media/:content_hash/signed-url", h.SignedMediaURL)
+	botGroup.GET("/media-integrity", h.ScanMediaIntegrity)
 }
 
 // --- Messages ---
@@ -181,6 +210,59 @@ func (h *MessageHandler) ListMessages(c echo.Context) error {
 	return c.JSON(http.StatusOK, map[string]any{"items": messages})
 }
 
+// ListToolCalls godoc
+// @Summary List bot tool calls
+// @Description Lists the tool calls the bot made, newest first, with arguments, results and durations.
+// @Tags messages
+// @Produce json
+// @Param bot_id path string true "Bot ID"
+// @Param tool_name query string false "Tool name"
+// @Param session_id query string false "Session ID"
+// @Param before query string false "Before"
+// @Param limit query int false "Limit"
+// @Success 200 {object} map[string][]messagepkg.ToolCall
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /bots/{bot_id}/tool-calls [get].
+func (h *MessageHandler) ListToolCalls(c echo.Context) error {
+	channelIdentityID, err := h.requireChannelIdentityID(c)
+	if err != nil {
+		return err
+	}
+	botID := strings.TrimSpace(c.Param("bot_id"))
+	if botID == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "bot id is required")
+	}
+	if _, err := h.authorizeBotAccess(c.Request().Context(), channelIdentityID, botID); err != nil {
+		return err
+	}
+	if err := h.requireReadable(c.Request().Context(), botID, channelIdentityID); err != nil {
+		return err
+	}
+	if h.messageService == nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "message service not configured")
+	}
+
+	filter := messagepkg.ToolCallFilter{
+		ToolName:  strings.TrimSpace(c.QueryParam("tool_name")),
+		SessionID: strings.TrimSpace(c.QueryParam("session_id")),
+	}
+	if s := strings.TrimSpace(c.QueryParam("limit")); s != "" {
+		if n, err := strconv.ParseInt(s, 10, 32); err == nil && n > 0 {
+			filter.Limit = int32(n)
+		}
+	}
+	if before, ok := parseBeforeParam(c.QueryParam("before")); ok {
+		filter.Before = before
+	}
+	calls, err := h.messageService.ListToolCalls(c.Request().Context(), botID, filter)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, map[string]any{"items": calls})
+}
+
 // fillAssetMimeFromStorage fills mime, storage_key, size_bytes from storage (soft link: DB only has content_hash).
 func (h *MessageHandler) fillAssetMimeFromStorage(ctx context.Context, botID string, messages []messagepkg.Message) {
 	if h.mediaService == nil {
@@ -386,6 +468,97 @@ func (h *MessageHandler) DeleteMessages(c echo.Context) error {
 	return c.NoContent(http.StatusNoContent)
 }
 
+// RedactMessageRequest is the body of a message redaction.
+type RedactMessageRequest struct {
+	// Phrases are replaced wherever they occur in the message; when empty the
+	// whole message content is redacted.
+	Phrases []string `json:"phrases,omitempty"`
+	Reason  string   `json:"reason,omitempty"`
+}
+
+// RedactMessage godoc
+// @Summary Redact a bot history message
+// @Description Replace phrases, or the whole content, of a stored message with a redaction marker while keeping the message. The redaction also covers the message's metadata, tool calls and session event, and phrases are removed from the bot's memories. Fully redacted messages are left out of the agent's context.
+// @Tags messages
+// @Accept json
+// @Produce json
+// @Param bot_id path string true "Bot ID"
+// @Param message_id path string true "Message ID"
+// @Param payload body RedactMessageRequest true "Redaction"
+// @Success 200 {object} messagepkg.Message
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /bots/{bot_id}/messages/{message_id}/redact [post].
+func (h *MessageHandler) RedactMessage(c echo.Context) error {
+	channelIdentityID, err := h.requireChannelIdentityID(c)
+	if err != nil {
+		return err
+	}
+	botID := strings.TrimSpace(c.Param("bot_id"))
+	if botID == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "bot id is required")
+	}
+	messageID := strings.TrimSpace(c.Param("message_id"))
+	if messageID == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "message id is required")
+	}
+	if _, err := h.authorizeBotManage(c.Request().Context(), channelIdentityID, botID); err != nil {
+		return err
+	}
+	if h.messageService == nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "message service not configured")
+	}
+	var req RedactMessageRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	msg, err := h.messageService.Redact(c.Request().Context(), botID, messageID, messagepkg.RedactInput{
+		Phrases:    req.Phrases,
+		RedactedBy: channelIdentityID,
+		Reason:     req.Reason,
+	})
+	if err != nil {
+		if errors.Is(err, messagepkg.ErrMessageNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, err.Error())
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	if h.sessionDropper != nil && msg.SessionID != "" {
+		h.sessionDropper.DropSession(msg.SessionID)
+	}
+	h.redactMemories(c.Request().Context(), botID, req.Phrases)
+	return c.JSON(http.StatusOK, msg)
+}
+
+// redactMemories replaces phrases in the bot's stored memories. Memories are
+// not linked to the messages they were extracted from, so whole-message
+// redactions leave them alone.
+func (h *MessageHandler) redactMemories(ctx context.Context, botID string, phrases []string) {
+	if len(phrases) == 0 {
+		return
+	}
+	provider := resolveMemoryProvider(ctx, h.logger, h.memoryRegistry, h.settingsService, botID)
+	if provider == nil {
+		return
+	}
+	memories, err := provider.GetAll(ctx, memprovider.GetAllRequest{BotID: botID, NoStats: true})
+	if err != nil {
+		h.logger.Warn("load memories for redaction failed", slog.String("bot_id", botID), slog.Any("error", err))
+		return
+	}
+	for _, item := range memories.Results {
+		redacted := messagepkg.RedactText(item.Memory, phrases)
+		if redacted == item.Memory {
+			continue
+		}
+		if _, err := provider.Update(ctx, memprovider.UpdateRequest{MemoryID: item.ID, Memory: redacted}); err != nil {
+			h.logger.Warn("redact memory failed", slog.String("bot_id", botID), slog.String("memory_id", item.ID), slog.Any("error", err))
+		}
+	}
+}
+
 // --- helpers ---
 
 func (*MessageHandler) requireChannelIdentityID(c echo.Context) (string, error) {
@@ -424,12 +597,10 @@ func (h *MessageHandler) requireReadable(ctx context.Context, conversationID, ch
 	return nil
 }
 
-// ServeMedia streams a media asset by bot_id + content_hash with read-access authorization.
+// ServeMedia streams a media asset by bot_id + content_hash. Requests carrying
+// a signature from SignedMediaURL are authorized by it; all others need
+// read access to the bot.
 func (h *MessageHandler) ServeMedia(c echo.Context) error {
-	channelIdentityID, err := h.requireChannelIdentityID(c)
-	if err != nil {
-		return err
-	}
 	botID := strings.TrimSpace(c.Param("bot_id"))
 	if botID == "" {
 		return echo.NewHTTPError(http.StatusBadRequest, "bot id is required")
@@ -438,15 +609,29 @@ func (h *MessageHandler) ServeMedia(c echo.Context) error {
 	if contentHash == "" {
 		return echo.NewHTTPError(http.StatusBadRequest, "content hash is required")
 	}
+	if h.mediaService == nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "media service not configured")
+	}
+	if signature := c.QueryParam("signature"); signature != "" {
+		if err := h.mediaService.VerifySignature(botID, contentHash, c.QueryParam("expires"), signature); err != nil {
+			return echo.NewHTTPError(http.StatusForbidden, err.Error())
+		}
+		return h.streamMedia(c, botID, contentHash)
+	}
+	channelIdentityID, err := h.requireChannelIdentityID(c)
+	if err != nil {
+		return err
+	}
 	if _, err := h.authorizeBotAccess(c.Request().Context(), channelIdentityID, botID); err != nil {
 		return err
 	}
 	if err := h.requireReadable(c.Request().Context(), botID, channelIdentityID); err != nil {
 		return err
 	}
-	if h.mediaService == nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, "media service not configured")
-	}
+	return h.streamMedia(c, botID, contentHash)
+}
+
+func (h *MessageHandler) streamMedia(c echo.Context, botID, contentHash string) error {
 	reader, asset, err := h.mediaService.Open(c.Request().Context(), botID, contentHash)
 	if err != nil {
 		if errors.Is(err, media.ErrAssetNotFound) {
@@ -467,3 +652,99 @@ func (h *MessageHandler) ServeMedia(c echo.Context) error {
 	}
 	return nil
 }
+
+// SignedMediaURL godoc
+// @Summary Create a signed, expiring media URL
+// @Description Returns a URL for the media asset that can be fetched without other credentials until it expires.
+// @Tags messages
+// @Produce json
+// @Param bot_id path string true "Bot ID"
+// @Param content_hash path string true "Content hash"
+// @Param ttl_seconds query int false "Validity in seconds (default 900, max 86400)"
+// @Success 200 {object} media.SignedURL
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /bots/{bot_id}/media/{content_hash}/signed-url [get].
+func (h *MessageHandler) SignedMediaURL(c echo.Context) error {
+	channelIdentityID, err := h.requireChannelIdentityID(c)
+	if err != nil {
+		return err
+	}
+	botID := strings.TrimSpace(c.Param("bot_id"))
+	if botID == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "bot id is required")
+	}
+	contentHash := strings.TrimSpace(c.Param("content_hash"))
+	if contentHash == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "content hash is required")
+	}
+	var ttl time.Duration
+	if raw := strings.TrimSpace(c.QueryParam("ttl_seconds")); raw != "" {
+		seconds, err := strconv.Atoi(raw)
+		if err != nil || seconds <= 0 {
+			return echo.NewHTTPError(http.StatusBadRequest, "ttl_seconds must be a positive integer")
+		}
+		ttl = time.Duration(seconds) * time.Second
+	}
+	if _, err := h.authorizeBotAccess(c.Request().Context(), channelIdentityID, botID); err != nil {
+		return err
+	}
+	if err := h.requireReadable(c.Request().Context(), botID, channelIdentityID); err != nil {
+		return err
+	}
+	if h.mediaService == nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "media service not configured")
+	}
+	signed, err := h.mediaService.SignedURL(c.Request().Context(), botID, contentHash, ttl)
+	if err != nil {
+		if errors.Is(err, media.ErrAssetNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, "asset not found")
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, signed)
+}
+
+// MediaIntegrityResponse lists a bot's corrupted media assets.
+type MediaIntegrityResponse struct {
+	Items []storage.IntegrityError `json:"items"`
+}
+
+// ScanMediaIntegrity godoc
+// @Summary Scan a bot's media for corruption (admin only)
+// @Description Re-hashes every content-addressed media asset of the bot and reports those whose bytes no longer match their content hash.
+// @Tags messages
+// @Produce json
+// @Param bot_id path string true "Bot ID"
+// @Success 200 {object} MediaIntegrityResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Failure 501 {object} ErrorResponse
+// @Router /bots/{bot_id}/media-integrity [get].
+func (h *MessageHandler) ScanMediaIntegrity(c echo.Context) error {
+	if err := RequireAdmin(c, h.accountService); err != nil {
+		return err
+	}
+	botID := strings.TrimSpace(c.Param("bot_id"))
+	if botID == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "bot id is required")
+	}
+	if h.mediaService == nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "media service not configured")
+	}
+	items, err := h.mediaService.ScanIntegrity(c.Request().Context(), botID)
+	if err != nil {
+		if errors.Is(err, storage.ErrIntegrityScanNotSupported) {
+			return echo.NewHTTPError(http.StatusNotImplemented, err.Error())
+		}
+		h.logger.Warn("media integrity scan failed", slog.String("bot_id", botID), slog.Any("error", err))
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	if len(items) > 0 {
+		h.logger.Warn("corrupted media assets found", slog.String("bot_id", botID), slog.Int("count", len(items)))
+	}
+	return c.JSON(http.StatusOK, MediaIntegrityResponse{Items: items})
+}
@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/memohai/memoh/internal/media"
+)
+
+type memMediaProvider struct {
+	objects map[string][]byte
+}
+
+func (m *memMediaProvider) Put(_ context.Context, key string, reader io.Reader) error {
+	data, err := io.ReadAll(reader)
+	m.objects[key] = data
+	return err
+}
+
+func (m *memMediaProvider) Open(_ context.Context, key string) (io.ReadCloser, error) {
+	data, ok := m.objects[key]
+	if !ok {
+		return nil, os.ErrNotExist
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (m *memMediaProvider) Delete(_ context.Context, key string) error {
+	delete(m.objects, key)
+	return nil
+}
+
+func (*memMediaProvider) AccessPath(key string) string { return key }
+
+func serveSignedMedia(t *testing.T, h *MessageHandler, target string) (*httptest.ResponseRecorder, error) {
+	t.Helper()
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	parts := strings.Split(strings.SplitN(target, "?", 2)[0], "/")
+	c.SetPath("/bots/:bot_id/media/:content_hash")
+	c.SetParamNames("bot_id", "content_hash")
+	c.SetParamValues(parts[2], parts[4])
+	return rec, h.ServeMedia(c)
+}
+
+func TestServeMediaWithSignedURL(t *testing.T) {
+	t.Parallel()
+	svc := media.NewService(slog.New(slog.DiscardHandler), &memMediaProvider{objects: map[string][]byte{}})
+	svc.SetURLSigningKey("test-secret")
+	asset, err := svc.Ingest(context.Background(), media.IngestInput{BotID: "bot-1", Mime: "image/png", Reader: strings.NewReader("png bytes")})
+	if err != nil {
+		t.Fatalf("Ingest failed: %v", err)
+	}
+	h := NewMessageHandler(slog.New(slog.DiscardHandler), nil, nil, nil, nil)
+	h.SetMediaService(svc)
+
+	signed, err := svc.SignedURL(context.Background(), "bot-1", asset.ContentHash, time.Minute)
+	if err != nil {
+		t.Fatalf("SignedURL failed: %v", err)
+	}
+	rec, err := serveSignedMedia(t, h, signed.URL)
+	if err != nil {
+		t.Fatalf("ServeMedia failed: %v", err)
+	}
+	if rec.Code != http.StatusOK || rec.Body.String() != "png bytes" || rec.Header().Get("Content-Type") != "image/png" {
+		t.Fatalf("unexpected response %d %q %q", rec.Code, rec.Header().Get("Content-Type"), rec.Body.String())
+	}
+
+	tampered := strings.Replace(signed.URL, "expires=", "expires=1", 1)
+	_, err = serveSignedMedia(t, h, tampered)
+	var httpErr *echo.HTTPError
+	if !errors.As(err, &httpErr) || httpErr.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 for tampered url, got %v", err)
+	}
+}
@@ -7,11 +7,14 @@ import (
 	"github.com/labstack/echo/v4"
 
 	"github.com/memohai/memoh/internal/boot"
+	"github.com/memohai/memoh/internal/db"
 	"github.com/memohai/memoh/internal/version"
 )
 
 type PingResponse struct {
+	// Status is "ok", or "degraded" while the database is unreachable.
 	Status            string `json:"status"`
+	Database          string `json:"database"`
 	ContainerBackend  string `json:"container_backend"`
 	SnapshotSupported bool   `json:"snapshot_supported"`
 	Version           string `json:"version"`
@@ -19,14 +22,16 @@ type PingResponse struct {
 }
 
 type PingHandler struct {
-	logger  *slog.Logger
-	runtime *boot.RuntimeConfig
+	logger   *slog.Logger
+	runtime  *boot.RuntimeConfig
+	dbHealth *db.HealthMonitor
 }
 
-func NewPingHandler(log *slog.Logger, rc *boot.RuntimeConfig) *PingHandler {
+func NewPingHandler(log *slog.Logger, rc *boot.RuntimeConfig, dbHealth *db.HealthMonitor) *PingHandler {
 	return &PingHandler{
-		logger:  log.With(slog.String("handler", "ping")),
-		runtime: rc,
+		logger:   log.With(slog.String("handler", "ping")),
+		runtime:  rc,
+		dbHealth: dbHealth,
 	}
 }
 
@@ -41,8 +46,13 @@ func (h *PingHandler) Register(e *echo.Echo) {
 // @Success 200 {object} PingResponse
 // @Router /ping [get].
 func (h *PingHandler) Ping(c echo.Context) error {
+	status, database := "ok", "ok"
+	if !h.databaseHealthy() {
+		status, database = "degraded", "down"
+	}
 	return c.JSON(http.StatusOK, PingResponse{
-		Status:            "ok",
+		Status:            status,
+		Database:          database,
 		ContainerBackend:  h.runtime.ContainerBackend,
 		SnapshotSupported: h.runtime.ContainerBackend != "apple",
 		Version:           version.Version,
@@ -50,6 +60,14 @@ func (h *PingHandler) Ping(c echo.Context) error {
 	})
 }
 
-func (*PingHandler) PingHead(c echo.Context) error {
+// PingHead answers 503 while the database is unreachable.
+func (h *PingHandler) PingHead(c echo.Context) error {
+	if !h.databaseHealthy() {
+		return c.NoContent(http.StatusServiceUnavailable)
+	}
 	return c.NoContent(http.StatusOK)
 }
+
+func (h *PingHandler) databaseHealthy() bool {
+	return h.dbHealth == nil || h.dbHealth.Healthy()
+}
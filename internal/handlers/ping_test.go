@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/memohai/memoh/internal/boot"
+	"github.com/memohai/memoh/internal/db"
+)
+
+type switchPinger struct {
+	err error
+}
+
+func (p *switchPinger) Ping(context.Context) error { return p.err }
+
+func TestPingReportsDatabaseOutage(t *testing.T) {
+	pinger := &switchPinger{}
+	monitor := db.NewHealthMonitor(slog.New(slog.DiscardHandler), pinger)
+	e := echo.New()
+	NewPingHandler(slog.New(slog.DiscardHandler), &boot.RuntimeConfig{}, monitor).Register(e)
+
+	serve := func(method, path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(method, path, nil))
+		return rec
+	}
+	ping := func() PingResponse {
+		t.Helper()
+		var resp PingResponse
+		if err := json.Unmarshal(serve(http.MethodGet, "/ping").Body.Bytes(), &resp); err != nil {
+			t.Fatalf("decode ping: %v", err)
+		}
+		return resp
+	}
+
+	pinger.err = errors.New("connection refused")
+	_ = monitor.Check(context.Background())
+	if rec := serve(http.MethodHead, "/health"); rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("health while down = %d, want %d", rec.Code, http.StatusServiceUnavailable)
+	}
+	if resp := ping(); resp.Status != "degraded" || resp.Database != "down" {
+		t.Fatalf("ping while down = %+v", resp)
+	}
+
+	pinger.err = nil
+	_ = monitor.Check(context.Background())
+	if rec := serve(http.MethodHead, "/health"); rec.Code != http.StatusOK {
+		t.Fatalf("health after recovery = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if resp := ping(); resp.Status != "ok" || resp.Database != "ok" {
+		t.Fatalf("ping after recovery = %+v", resp)
+	}
+}
@@ -67,6 +67,9 @@ func (h *ProvidersHandler) Create(c echo.Context) error {
 
 	resp, err := h.service.Create(c.Request().Context(), req)
 	if err != nil {
+		if errors.Is(err, providers.ErrInvalidConfig) {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
 		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
 	}
 
@@ -207,6 +210,9 @@ func (h *ProvidersHandler) Update(c echo.Context) error {
 
 	resp, err := h.service.Update(c.Request().Context(), id, req)
 	if err != nil {
+		if errors.Is(err, providers.ErrInvalidConfig) {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
 		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
 	}
 
@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/memohai/memoh/internal/accounts"
+	"github.com/memohai/memoh/internal/channel"
+	"github.com/memohai/memoh/internal/conversation"
+	"github.com/memohai/memoh/internal/conversation/flow"
+)
+
+// requestPreviewer assembles an agent request without calling the model.
+type requestPreviewer interface {
+	PreviewRequest(ctx context.Context, req conversation.ChatRequest) (flow.RequestPreview, error)
+}
+
+// RequestPreviewHandler serves the admin-only request preview used to debug
+// how a turn is assembled.
+type RequestPreviewHandler struct {
+	previewer      requestPreviewer
+	accountService *accounts.Service
+	logger         *slog.Logger
+}
+
+// RequestPreviewRequest is the chat turn to preview.
+type RequestPreviewRequest struct {
+	Query            string `json:"query"`
+	SessionID        string `json:"session_id,omitempty"`
+	Model            string `json:"model,omitempty"`
+	Provider         string `json:"provider,omitempty"`
+	ReasoningEffort  string `json:"reasoning_effort,omitempty"`
+	CurrentChannel   string `json:"current_channel,omitempty"`
+	ConversationType string `json:"conversation_type,omitempty"`
+}
+
+// NewRequestPreviewHandler creates a RequestPreviewHandler.
+func NewRequestPreviewHandler(log *slog.Logger, resolver *flow.Resolver, accountService *accounts.Service) *RequestPreviewHandler {
+	return &RequestPreviewHandler{
+		previewer:      resolver,
+		accountService: accountService,
+		logger:         log.With(slog.String("handler", "request_preview")),
+	}
+}
+
+func (h *RequestPreviewHandler) Register(e *echo.Echo) {
+	e.POST("/bots/:bot_id/debug/request-preview", h.PreviewRequest)
+}
+
+// PreviewRequest godoc
+// @Summary Preview the assembled agent request for a chat turn
+// @Description Resolves a chat turn (history, trimming, skills, attachments and system prompt) and returns the request that would be sent to the model, without calling it. Credentials are redacted. Admin only.
+// @Tags debug
+// @Accept json
+// @Produce json
+// @Param bot_id path string true "Bot ID"
+// @Param payload body RequestPreviewRequest true "Chat turn to preview"
+// @Success 200 {object} flow.RequestPreview
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /bots/{bot_id}/debug/request-preview [post].
+func (h *RequestPreviewHandler) PreviewRequest(c echo.Context) error {
+	if err := RequireAdmin(c, h.accountService); err != nil {
+		return err
+	}
+	channelIdentityID, err := RequireChannelIdentityID(c)
+	if err != nil {
+		return err
+	}
+	botID := strings.TrimSpace(c.Param("bot_id"))
+	if botID == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "bot id is required")
+	}
+	var body RequestPreviewRequest
+	if err := c.Bind(&body); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if strings.TrimSpace(body.Query) == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "query is required")
+	}
+	if h.previewer == nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "resolver not configured")
+	}
+
+	preview, err := h.previewer.PreviewRequest(c.Request().Context(), previewChatRequest(botID, channelIdentityID, body))
+	if err != nil {
+		h.logger.Warn("request preview failed", slog.String("bot_id", botID), slog.Any("error", err))
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, preview)
+}
+
+// previewChatRequest builds the chat request a local channel turn from the
+// caller would produce.
+func previewChatRequest(botID, channelIdentityID string, body RequestPreviewRequest) conversation.ChatRequest {
+	currentChannel := strings.TrimSpace(body.CurrentChannel)
+	conversationType := strings.TrimSpace(body.ConversationType)
+	if conversationType == "" {
+		conversationType = channel.ConversationTypePrivate
+	}
+	req := conversation.ChatRequest{
+		BotID:                   botID,
+		ChatID:                  botID,
+		SessionID:               strings.TrimSpace(body.SessionID),
+		UserID:                  channelIdentityID,
+		SourceChannelIdentityID: channelIdentityID,
+		ConversationType:        conversationType,
+		Query:                   body.Query,
+		Model:                   strings.TrimSpace(body.Model),
+		Provider:                strings.TrimSpace(body.Provider),
+		ReasoningEffort:         strings.TrimSpace(body.ReasoningEffort),
+		CurrentChannel:          currentChannel,
+	}
+	if currentChannel != "" {
+		req.Channels = []string{currentChannel}
+	}
+	return req
+}
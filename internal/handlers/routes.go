@@ -0,0 +1,283 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/labstack/echo/v4"
+
+	"github.com/memohai/memoh/internal/accounts"
+	"github.com/memohai/memoh/internal/bots"
+	"github.com/memohai/memoh/internal/channel/route"
+)
+
+// RouteHandler manages the channel conversation routes of a bot.
+type RouteHandler struct {
+	routes         route.Service
+	botService     *bots.Service
+	accountService *accounts.Service
+	logger         *slog.Logger
+}
+
+// RouteListResponse wraps the routes of a bot.
+type RouteListResponse struct {
+	Items []route.Route `json:"items"`
+}
+
+// NewRouteHandler creates a RouteHandler.
+func NewRouteHandler(log *slog.Logger, routeService route.Service, botService *bots.Service, accountService *accounts.Service) *RouteHandler {
+	return &RouteHandler{
+		routes:         routeService,
+		botService:     botService,
+		accountService: accountService,
+		logger:         log.With(slog.String("handler", "routes")),
+	}
+}
+
+func (h *RouteHandler) Register(e *echo.Echo) {
+	group := e.Group("/bots/:bot_id/routes")
+	group.GET("", h.ListRoutes)
+	group.POST("/:route_id/mute", h.MuteRoute)
+	group.POST("/:route_id/unmute", h.UnmuteRoute)
+	group.POST("/:route_id/pin", h.PinRoute)
+	group.POST("/:route_id/unpin", h.UnpinRoute)
+	group.GET("/:route_id/annotations", h.GetRouteAnnotations)
+	group.PUT("/:route_id/annotations", h.SetRouteAnnotations)
+}
+
+// ListRoutes godoc
+// @Summary List channel routes of a bot
+// @Description Lists the external conversations (routes) the bot participates in.
+// @Tags routes
+// @Produce json
+// @Param bot_id path string true "Bot ID"
+// @Success 200 {object} RouteListResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /bots/{bot_id}/routes [get].
+func (h *RouteHandler) ListRoutes(c echo.Context) error {
+	botID, err := h.authorize(c)
+	if err != nil {
+		return err
+	}
+	items, err := h.routes.List(c.Request().Context(), botID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	if items == nil {
+		items = []route.Route{}
+	}
+	return c.JSON(http.StatusOK, RouteListResponse{Items: items})
+}
+
+// MuteRoute godoc
+// @Summary Mute a channel route
+// @Description Stops the bot from replying in the conversation. Messages are still recorded.
+// @Tags routes
+// @Produce json
+// @Param bot_id path string true "Bot ID"
+// @Param route_id path string true "Route ID"
+// @Success 200 {object} route.Route
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /bots/{bot_id}/routes/{route_id}/mute [post].
+func (h *RouteHandler) MuteRoute(c echo.Context) error {
+	return h.setMuted(c, true)
+}
+
+// UnmuteRoute godoc
+// @Summary Unmute a channel route
+// @Description Lets the bot reply in the conversation again.
+// @Tags routes
+// @Produce json
+// @Param bot_id path string true "Bot ID"
+// @Param route_id path string true "Route ID"
+// @Success 200 {object} route.Route
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /bots/{bot_id}/routes/{route_id}/unmute [post].
+func (h *RouteHandler) UnmuteRoute(c echo.Context) error {
+	return h.setMuted(c, false)
+}
+
+func (h *RouteHandler) setMuted(c echo.Context, muted bool) error {
+	botID, err := h.authorize(c)
+	if err != nil {
+		return err
+	}
+	ctx := c.Request().Context()
+	rt, err := h.botRoute(ctx, botID, c.Param("route_id"))
+	if err != nil {
+		return err
+	}
+	if err := h.routes.SetMuted(ctx, rt.ID, muted); err != nil {
+		h.logger.Error("set route muted failed", slog.String("route_id", rt.ID), slog.Any("error", err))
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	rt.Muted = muted
+	return c.JSON(http.StatusOK, rt)
+}
+
+// PinRoute godoc
+// @Summary Pin a channel route
+// @Description Keeps the conversation and its history out of retention sweeps and pruning.
+// @Tags routes
+// @Produce json
+// @Param bot_id path string true "Bot ID"
+// @Param route_id path string true "Route ID"
+// @Success 200 {object} route.Route
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /bots/{bot_id}/routes/{route_id}/pin [post].
+func (h *RouteHandler) PinRoute(c echo.Context) error {
+	return h.setPinned(c, true)
+}
+
+// UnpinRoute godoc
+// @Summary Unpin a channel route
+// @Description Makes the conversation subject to retention sweeps and pruning again.
+// @Tags routes
+// @Produce json
+// @Param bot_id path string true "Bot ID"
+// @Param route_id path string true "Route ID"
+// @Success 200 {object} route.Route
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /bots/{bot_id}/routes/{route_id}/unpin [post].
+func (h *RouteHandler) UnpinRoute(c echo.Context) error {
+	return h.setPinned(c, false)
+}
+
+func (h *RouteHandler) setPinned(c echo.Context, pinned bool) error {
+	botID, err := h.authorize(c)
+	if err != nil {
+		return err
+	}
+	ctx := c.Request().Context()
+	rt, err := h.botRoute(ctx, botID, c.Param("route_id"))
+	if err != nil {
+		return err
+	}
+	if err := h.routes.SetPinned(ctx, rt.ID, pinned); err != nil {
+		h.logger.Error("set route pinned failed", slog.String("route_id", rt.ID), slog.Any("error", err))
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	rt.Pinned = pinned
+	return c.JSON(http.StatusOK, rt)
+}
+
+// GetRouteAnnotations godoc
+// @Summary Get channel route annotations
+// @Description Returns the notes and tags kept about the conversation.
+// @Tags routes
+// @Produce json
+// @Param bot_id path string true "Bot ID"
+// @Param route_id path string true "Route ID"
+// @Success 200 {object} route.Annotations
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /bots/{bot_id}/routes/{route_id}/annotations [get].
+func (h *RouteHandler) GetRouteAnnotations(c echo.Context) error {
+	botID, err := h.authorize(c)
+	if err != nil {
+		return err
+	}
+	rt, err := h.botRoute(c.Request().Context(), botID, c.Param("route_id"))
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, rt.Annotations)
+}
+
+// SetRouteAnnotations godoc
+// @Summary Set channel route annotations
+// @Description Replaces the notes and tags kept about the conversation. They are given to the agent so it can personalize replies.
+// @Tags routes
+// @Accept json
+// @Produce json
+// @Param bot_id path string true "Bot ID"
+// @Param route_id path string true "Route ID"
+// @Param payload body route.Annotations true "Annotations"
+// @Success 200 {object} route.Annotations
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /bots/{bot_id}/routes/{route_id}/annotations [put].
+func (h *RouteHandler) SetRouteAnnotations(c echo.Context) error {
+	botID, err := h.authorize(c)
+	if err != nil {
+		return err
+	}
+	var req route.Annotations
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	annotations, err := route.NormalizeAnnotations(req)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	ctx := c.Request().Context()
+	rt, err := h.botRoute(ctx, botID, c.Param("route_id"))
+	if err != nil {
+		return err
+	}
+	if err := h.routes.SetAnnotations(ctx, rt.ID, annotations); err != nil {
+		h.logger.Error("set route annotations failed", slog.String("route_id", rt.ID), slog.Any("error", err))
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, annotations)
+}
+
+// authorize checks bot access and returns the bot ID.
+func (h *RouteHandler) authorize(c echo.Context) (string, error) {
+	channelIdentityID, err := RequireChannelIdentityID(c)
+	if err != nil {
+		return "", err
+	}
+	botID := strings.TrimSpace(c.Param("bot_id"))
+	if botID == "" {
+		return "", echo.NewHTTPError(http.StatusBadRequest, "bot id is required")
+	}
+	if _, err := AuthorizeBotAccess(c.Request().Context(), h.botService, h.accountService, channelIdentityID, botID); err != nil {
+		return "", err
+	}
+	if h.routes == nil {
+		return "", echo.NewHTTPError(http.StatusInternalServerError, "route service not configured")
+	}
+	return botID, nil
+}
+
+// botRoute loads a route and ensures it belongs to botID.
+func (h *RouteHandler) botRoute(ctx context.Context, botID, routeID string) (route.Route, error) {
+	routeID = strings.TrimSpace(routeID)
+	if routeID == "" {
+		return route.Route{}, echo.NewHTTPError(http.StatusBadRequest, "route id is required")
+	}
+	rt, err := h.routes.GetByID(ctx, routeID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return route.Route{}, echo.NewHTTPError(http.StatusNotFound, "route not found")
+		}
+		return route.Route{}, echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if rt.BotID != botID {
+		return route.Route{}, echo.NewHTTPError(http.StatusNotFound, "route not found")
+	}
+	return rt, nil
+}
@@ -22,6 +22,9 @@ type SkillItem struct {
 	Content     string         `json:"content"`
 	Metadata    map[string]any `json:"metadata,omitempty"`
 	Raw         string         `json:"raw"`
+	// Error describes why the skill failed validation. Invalid skills are
+	// listed so they can be fixed but are not offered to the agent.
+	Error string `json:"error,omitempty"`
 }
 
 type SkillsResponse struct {
@@ -54,7 +57,32 @@ func (h *ContainerdHandler) ListSkills(c echo.Context) error {
 	if err != nil {
 		return err
 	}
-	skills, err := h.loadSkillsFromContainer(c.Request().Context(), botID)
+	skills, err := h.skills.reload(c.Request().Context(), botID, h.loadSkillsFromContainer)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	for i := range skills {
+		skills[i].Raw = skills[i].Content
+	}
+	return c.JSON(http.StatusOK, SkillsResponse{Skills: skills})
+}
+
+// ReloadSkills godoc
+// @Summary Reload skills from data directory
+// @Description Discards the cached skills of the bot and reads them again from the container, so edits made directly to skill files take effect on the next turn.
+// @Tags containerd
+// @Param bot_id path string true "Bot ID"
+// @Success 200 {object} SkillsResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /bots/{bot_id}/container/skills/reload [post].
+func (h *ContainerdHandler) ReloadSkills(c echo.Context) error {
+	botID, err := h.requireBotAccess(c)
+	if err != nil {
+		return err
+	}
+	skills, err := h.skills.reload(c.Request().Context(), botID, h.loadSkillsFromContainer)
 	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
 	}
@@ -87,28 +115,37 @@ func (h *ContainerdHandler) UpsertSkills(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, "skills is required")
 	}
 
-	ctx := c.Request().Context()
+	for _, raw := range req.Skills {
+		if err := validateSkillFile(raw); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid skill: "+err.Error())
+		}
+	}
+	if err := h.WriteSkills(c.Request().Context(), botID, req.Skills); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, skillsOpResponse{OK: true})
+}
+
+// WriteSkills writes SKILL.md files into the bot's skills directory, each
+// under the directory named after the skill. Callers validate the files.
+func (h *ContainerdHandler) WriteSkills(ctx context.Context, botID string, raws []string) error {
 	client, err := h.getGRPCClient(ctx, botID)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("container not reachable: %v", err))
+		return fmt.Errorf("container not reachable: %w", err)
 	}
-
-	for _, raw := range req.Skills {
+	defer h.skills.invalidate(botID)
+	for _, raw := range raws {
 		parsed := parseSkillFile(raw, "")
-		if !isValidSkillName(parsed.Name) {
-			return echo.NewHTTPError(http.StatusBadRequest, "skill must have a valid name in YAML frontmatter")
-		}
 		dirPath := path.Join(skillsDirPath, parsed.Name)
 		if err := client.Mkdir(ctx, dirPath); err != nil {
-			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("mkdir failed: %v", err))
+			return fmt.Errorf("mkdir failed: %w", err)
 		}
 		filePath := path.Join(dirPath, "SKILL.md")
 		if err := client.WriteFile(ctx, filePath, []byte(raw)); err != nil {
-			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("write failed: %v", err))
+			return fmt.Errorf("write failed: %w", err)
 		}
 	}
-
-	return c.JSON(http.StatusOK, skillsOpResponse{OK: true})
+	return nil
 }
 
 // DeleteSkills godoc
@@ -147,13 +184,15 @@ func (h *ContainerdHandler) DeleteSkills(c echo.Context) error {
 		}
 		_ = client.DeleteFile(ctx, path.Join(skillsDirPath, skillName), true)
 	}
+	h.skills.invalidate(botID)
 
 	return c.JSON(http.StatusOK, skillsOpResponse{OK: true})
 }
 
-// LoadSkills loads all skills from the container for the given bot.
+// LoadSkills loads all skills for the given bot, reusing recently loaded
+// skills until they expire or are reloaded.
 func (h *ContainerdHandler) LoadSkills(ctx context.Context, botID string) ([]SkillItem, error) {
-	return h.loadSkillsFromContainer(ctx, botID)
+	return h.skills.get(ctx, botID, h.loadSkillsFromContainer)
 }
 
 func (h *ContainerdHandler) loadSkillsFromContainer(ctx context.Context, botID string) ([]SkillItem, error) {
@@ -161,7 +200,10 @@ func (h *ContainerdHandler) loadSkillsFromContainer(ctx context.Context, botID s
 	if err != nil {
 		return nil, err
 	}
+	return loadSkillsWithClient(ctx, client)
+}
 
+func loadSkillsWithClient(ctx context.Context, client *bridge.Client) ([]SkillItem, error) {
 	entries, err := client.ListDirAll(ctx, skillsDirPath, false)
 	if err != nil {
 		return []SkillItem{}, nil
@@ -205,13 +247,17 @@ func readContainerSkillFile(ctx context.Context, client *bridge.Client, filePath
 }
 
 func skillItemFromParsed(parsed parsedSkill, raw string) SkillItem {
-	return SkillItem{
+	item := SkillItem{
 		Name:        parsed.Name,
 		Description: parsed.Description,
 		Content:     parsed.Content,
 		Metadata:    parsed.Metadata,
 		Raw:         raw,
 	}
+	if err := validateSkillFile(raw); err != nil {
+		item.Error = err.Error()
+	}
+	return item
 }
 
 // --- parsing logic (unchanged) ---
@@ -230,25 +276,10 @@ func parseSkillFile(raw string, fallbackName string) parsedSkill {
 		Name:    strings.TrimSpace(fallbackName),
 		Content: trimmed,
 	}
-	if !strings.HasPrefix(trimmed, "---") {
-		return normalizeParsedSkill(result)
-	}
-
-	rest := trimmed[3:]
-	rest = strings.TrimLeft(rest, " \t")
-	if len(rest) > 0 && rest[0] == '\n' {
-		rest = rest[1:]
-	} else if len(rest) > 1 && rest[0] == '\r' && rest[1] == '\n' {
-		rest = rest[2:]
-	}
-	closingIdx := strings.Index(rest, "\n---")
-	if closingIdx < 0 {
+	frontmatterRaw, body, ok := splitSkillFrontmatter(trimmed)
+	if !ok {
 		return normalizeParsedSkill(result)
 	}
-
-	frontmatterRaw := rest[:closingIdx]
-	body := rest[closingIdx+4:]
-	body = strings.TrimLeft(body, "\r\n")
 	result.Content = body
 
 	var fm struct {
@@ -269,6 +300,27 @@ func parseSkillFile(raw string, fallbackName string) parsedSkill {
 	return normalizeParsedSkill(result)
 }
 
+// splitSkillFrontmatter separates the "---" delimited YAML frontmatter of a
+// trimmed SKILL.md from its body. ok is false when there is no frontmatter.
+func splitSkillFrontmatter(trimmed string) (frontmatter, body string, ok bool) {
+	if !strings.HasPrefix(trimmed, "---") {
+		return "", "", false
+	}
+
+	rest := trimmed[3:]
+	rest = strings.TrimLeft(rest, " \t")
+	if len(rest) > 0 && rest[0] == '\n' {
+		rest = rest[1:]
+	} else if len(rest) > 1 && rest[0] == '\r' && rest[1] == '\n' {
+		rest = rest[2:]
+	}
+	closingIdx := strings.Index(rest, "\n---")
+	if closingIdx < 0 {
+		return "", "", false
+	}
+	return rest[:closingIdx], strings.TrimLeft(rest[closingIdx+4:], "\r\n"), true
+}
+
 func normalizeParsedSkill(skill parsedSkill) parsedSkill {
 	if strings.TrimSpace(skill.Name) == "" {
 		skill.Name = "default"
@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// skillsCacheTTL bounds how long loaded skills are reused across chat turns
+// before the container is read again. Skill edits made through the API and
+// explicit reloads invalidate the cache immediately.
+const skillsCacheTTL = 30 * time.Second
+
+type skillsLoader func(ctx context.Context, botID string) ([]SkillItem, error)
+
+type skillsCacheEntry struct {
+	skills   []SkillItem
+	loadedAt time.Time
+}
+
+// skillsCache keeps the most recently loaded skills of each bot. A nil cache
+// always loads from the container.
+type skillsCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	now     func() time.Time
+	entries map[string]skillsCacheEntry
+}
+
+func newSkillsCache(ttl time.Duration) *skillsCache {
+	return &skillsCache{
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[string]skillsCacheEntry),
+	}
+}
+
+// get returns the cached skills of a bot, loading them when missing or stale.
+func (c *skillsCache) get(ctx context.Context, botID string, load skillsLoader) ([]SkillItem, error) {
+	if c == nil {
+		return load(ctx, botID)
+	}
+	c.mu.Lock()
+	entry, ok := c.entries[botID]
+	c.mu.Unlock()
+	if ok && c.now().Sub(entry.loadedAt) < c.ttl {
+		return cloneSkillItems(entry.skills), nil
+	}
+	return c.reload(ctx, botID, load)
+}
+
+// reload discards the cached skills of a bot and loads them again.
+func (c *skillsCache) reload(ctx context.Context, botID string, load skillsLoader) ([]SkillItem, error) {
+	c.invalidate(botID)
+	skills, err := load(ctx, botID)
+	if err != nil {
+		return nil, err
+	}
+	if c != nil {
+		c.mu.Lock()
+		c.entries[botID] = skillsCacheEntry{skills: cloneSkillItems(skills), loadedAt: c.now()}
+		c.mu.Unlock()
+	}
+	return skills, nil
+}
+
+func (c *skillsCache) invalidate(botID string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	delete(c.entries, botID)
+	c.mu.Unlock()
+}
+
+// cloneSkillItems copies the slice so callers may mutate items without
+// touching the cached entry.
+func cloneSkillItems(items []SkillItem) []SkillItem {
+	if items == nil {
+		return nil
+	}
+	out := make([]SkillItem, len(items))
+	copy(out, items)
+	return out
+}
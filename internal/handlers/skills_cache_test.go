@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"context"
+	"net"
+	"path"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/memohai/memoh/internal/workspace/bridge"
+	pb "github.com/memohai/memoh/internal/workspace/bridgepb"
+)
+
+// skillsTestServer serves a mutable set of files under the skills directory.
+type skillsTestServer struct {
+	pb.UnimplementedContainerServiceServer
+	mu    sync.Mutex
+	files map[string]string
+}
+
+func (s *skillsTestServer) setFile(name, content string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.files[name] = content
+}
+
+func (s *skillsTestServer) ListDir(_ context.Context, req *pb.ListDirRequest) (*pb.ListDirResponse, error) {
+	if req.GetPath() != skillsDirPath {
+		return nil, status.Errorf(codes.NotFound, "no such directory: %s", req.GetPath())
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	dirs := make([]string, 0, len(s.files))
+	for name := range s.files {
+		dirs = append(dirs, name)
+	}
+	sort.Strings(dirs)
+	resp := &pb.ListDirResponse{}
+	for _, name := range dirs {
+		resp.Entries = append(resp.Entries, &pb.FileEntry{Path: name, IsDir: true})
+	}
+	return resp, nil
+}
+
+func (s *skillsTestServer) ReadFile(_ context.Context, req *pb.ReadFileRequest) (*pb.ReadFileResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for name, content := range s.files {
+		if req.GetPath() == path.Join(skillsDirPath, name, "SKILL.md") {
+			return &pb.ReadFileResponse{Content: content}, nil
+		}
+	}
+	return nil, status.Errorf(codes.NotFound, "no such file: %s", req.GetPath())
+}
+
+func newSkillsTestClient(t *testing.T, srv *skillsTestServer) *bridge.Client {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	grpcSrv := grpc.NewServer()
+	pb.RegisterContainerServiceServer(grpcSrv, srv)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = grpcSrv.Serve(lis)
+	}()
+	t.Cleanup(func() {
+		grpcSrv.Stop()
+		<-done
+	})
+
+	conn, err := grpc.NewClient("passthrough://bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("grpc.NewClient: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return bridge.NewClientFromConn(conn)
+}
+
+func TestSkillsCacheReloadPicksUpChangedContent(t *testing.T) {
+	t.Parallel()
+	srv := &skillsTestServer{files: map[string]string{
+		"weather": "---\nname: weather\ndescription: Check the weather\n---\n\nUse the forecast API.",
+	}}
+	client := newSkillsTestClient(t, srv)
+	loads := 0
+	load := func(ctx context.Context, _ string) ([]SkillItem, error) {
+		loads++
+		return loadSkillsWithClient(ctx, client)
+	}
+	cache := newSkillsCache(time.Hour)
+	ctx := context.Background()
+
+	skills, err := cache.get(ctx, "bot-1", load)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if len(skills) != 1 || skills[0].Content != "Use the forecast API." {
+		t.Fatalf("unexpected initial skills: %+v", skills)
+	}
+
+	srv.setFile("weather", "---\nname: weather\ndescription: Check the weather\n---\n\nUse the radar API.")
+	srv.setFile("notes", "---\nname: notes\ndescription: Take notes\n---\n\nWrite to /data/notes.")
+
+	skills, err = cache.get(ctx, "bot-1", load)
+	if err != nil {
+		t.Fatalf("cached get: %v", err)
+	}
+	if loads != 1 || skills[0].Content != "Use the forecast API." {
+		t.Fatalf("expected cached skills before reload, loads=%d skills=%+v", loads, skills)
+	}
+
+	skills, err = cache.reload(ctx, "bot-1", load)
+	if err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	if len(skills) != 2 {
+		t.Fatalf("expected 2 skills after reload, got %+v", skills)
+	}
+	byName := map[string]SkillItem{}
+	for _, skill := range skills {
+		byName[skill.Name] = skill
+	}
+	if byName["weather"].Content != "Use the radar API." {
+		t.Fatalf("expected reloaded weather content, got %q", byName["weather"].Content)
+	}
+	if byName["notes"].Description != "Take notes" {
+		t.Fatalf("expected new notes skill, got %+v", byName["notes"])
+	}
+
+	// Subsequent turns see the reloaded skills without hitting the container.
+	skills, err = cache.get(ctx, "bot-1", load)
+	if err != nil {
+		t.Fatalf("get after reload: %v", err)
+	}
+	if loads != 2 || len(skills) != 2 {
+		t.Fatalf("expected reloaded skills from cache, loads=%d skills=%d", loads, len(skills))
+	}
+}
+
+func TestSkillsCacheExpiresAndInvalidates(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache := newSkillsCache(time.Minute)
+	cache.now = func() time.Time { return now }
+	loads := 0
+	load := func(context.Context, string) ([]SkillItem, error) {
+		loads++
+		return []SkillItem{{Name: "demo"}}, nil
+	}
+	ctx := context.Background()
+
+	_, _ = cache.get(ctx, "bot-1", load)
+	_, _ = cache.get(ctx, "bot-1", load)
+	if loads != 1 {
+		t.Fatalf("expected cache hit, got %d loads", loads)
+	}
+	now = now.Add(time.Minute)
+	_, _ = cache.get(ctx, "bot-1", load)
+	if loads != 2 {
+		t.Fatalf("expected reload after TTL, got %d loads", loads)
+	}
+	cache.invalidate("bot-1")
+	_, _ = cache.get(ctx, "bot-1", load)
+	if loads != 3 {
+		t.Fatalf("expected reload after invalidate, got %d loads", loads)
+	}
+
+	var nilCache *skillsCache
+	if _, err := nilCache.get(ctx, "bot-1", load); err != nil || loads != 4 {
+		t.Fatalf("expected nil cache to load directly, loads=%d err=%v", loads, err)
+	}
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// maxSkillFileSize bounds a SKILL.md so a single skill cannot crowd out
+	// the rest of the system prompt.
+	maxSkillFileSize          = 64 << 10
+	maxSkillNameLength        = 64
+	maxSkillDescriptionLength = 1024
+)
+
+// validateSkillFile checks a raw SKILL.md against the skill format: a size
+// limit, YAML frontmatter with a name and description, an optional metadata
+// mapping and a non-empty body. Skills that fail validation are reported
+// instead of being passed to the agent with fallback values.
+func validateSkillFile(raw string) error {
+	if len(raw) > maxSkillFileSize {
+		return fmt.Errorf("skill file is %d bytes, exceeding the %d byte limit", len(raw), maxSkillFileSize)
+	}
+	frontmatterRaw, body, ok := splitSkillFrontmatter(strings.TrimSpace(raw))
+	if !ok {
+		return errors.New("missing YAML frontmatter delimited by ---")
+	}
+
+	var fm struct {
+		Name        any `yaml:"name"`
+		Description any `yaml:"description"`
+		Metadata    any `yaml:"metadata"`
+	}
+	if err := yaml.Unmarshal([]byte(frontmatterRaw), &fm); err != nil {
+		return fmt.Errorf("invalid YAML frontmatter: %w", err)
+	}
+
+	name, ok := fm.Name.(string)
+	name = strings.TrimSpace(name)
+	switch {
+	case !ok || name == "":
+		return errors.New("frontmatter name is required")
+	case len(name) > maxSkillNameLength:
+		return fmt.Errorf("frontmatter name exceeds %d characters", maxSkillNameLength)
+	case !isValidSkillName(name):
+		return fmt.Errorf("frontmatter name %q is not a valid skill name", name)
+	}
+
+	description, ok := fm.Description.(string)
+	description = strings.TrimSpace(description)
+	switch {
+	case !ok || description == "":
+		return errors.New("frontmatter description is required")
+	case len(description) > maxSkillDescriptionLength:
+		return fmt.Errorf("frontmatter description exceeds %d characters", maxSkillDescriptionLength)
+	}
+
+	if fm.Metadata != nil {
+		if _, ok := fm.Metadata.(map[string]any); !ok {
+			return errors.New("frontmatter metadata must be a mapping")
+		}
+	}
+
+	if strings.TrimSpace(body) == "" {
+		return errors.New("skill body is empty")
+	}
+	return nil
+}
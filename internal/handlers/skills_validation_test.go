@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestValidateSkillFile(t *testing.T) {
+	t.Parallel()
+	valid := "---\nname: weather\ndescription: Check the weather\nmetadata:\n  version: \"1\"\n---\n\nUse the forecast API."
+
+	cases := []struct {
+		name    string
+		raw     string
+		wantErr string
+	}{
+		{name: "valid", raw: valid},
+		{name: "oversized", raw: valid + strings.Repeat("x", maxSkillFileSize), wantErr: "byte limit"},
+		{name: "no frontmatter", raw: "# Weather\n\nUse the forecast API.", wantErr: "missing YAML frontmatter"},
+		{name: "unterminated frontmatter", raw: "---\nname: weather\ndescription: x\n", wantErr: "missing YAML frontmatter"},
+		{name: "malformed yaml", raw: "---\nname: [weather\ndescription: x\n---\nbody", wantErr: "invalid YAML frontmatter"},
+		{name: "missing name", raw: "---\ndescription: Check the weather\n---\nbody", wantErr: "name is required"},
+		{name: "non-string name", raw: "---\nname: [a, b]\ndescription: x\n---\nbody", wantErr: "name is required"},
+		{name: "invalid name", raw: "---\nname: ../etc\ndescription: x\n---\nbody", wantErr: "not a valid skill name"},
+		{name: "missing description", raw: "---\nname: weather\n---\nbody", wantErr: "description is required"},
+		{name: "long description", raw: "---\nname: weather\ndescription: " + strings.Repeat("d", maxSkillDescriptionLength+1) + "\n---\nbody", wantErr: "description exceeds"},
+		{name: "metadata list", raw: "---\nname: weather\ndescription: x\nmetadata:\n  - a\n---\nbody", wantErr: "metadata must be a mapping"},
+		{name: "empty body", raw: "---\nname: weather\ndescription: x\n---\n", wantErr: "body is empty"},
+	}
+	for _, tc := range cases {
+		err := validateSkillFile(tc.raw)
+		if tc.wantErr == "" {
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %v", tc.name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+			t.Fatalf("%s: expected error containing %q, got %v", tc.name, tc.wantErr, err)
+		}
+	}
+}
+
+func TestLoadSkillsReportsInvalidSkills(t *testing.T) {
+	t.Parallel()
+	srv := &skillsTestServer{files: map[string]string{
+		"weather": "---\nname: weather\ndescription: Check the weather\n---\n\nUse the forecast API.",
+		"huge":    "---\nname: huge\ndescription: Too big\n---\n\n" + strings.Repeat("x", maxSkillFileSize),
+		"broken":  "---\nname: broken\nmetadata: nope\n---\n\nBody",
+	}}
+	skills, err := loadSkillsWithClient(context.Background(), newSkillsTestClient(t, srv))
+	if err != nil {
+		t.Fatalf("loadSkillsWithClient: %v", err)
+	}
+	if len(skills) != 3 {
+		t.Fatalf("expected invalid skills to be listed, got %d", len(skills))
+	}
+	errs := map[string]string{}
+	for _, skill := range skills {
+		errs[skill.Name] = skill.Error
+	}
+	if errs["weather"] != "" {
+		t.Fatalf("expected weather to be valid, got %q", errs["weather"])
+	}
+	if !strings.Contains(errs["huge"], "byte limit") {
+		t.Fatalf("expected size error for huge, got %q", errs["huge"])
+	}
+	if !strings.Contains(errs["broken"], "description is required") {
+		t.Fatalf("expected validation error for broken, got %q", errs["broken"])
+	}
+}
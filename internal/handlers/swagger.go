@@ -28,6 +28,10 @@ func NewSwaggerHandler(log *slog.Logger) *SwaggerHandler {
 	return &SwaggerHandler{logger: log.With(slog.String("handler", "swagger"))}
 }
 
+func (*SwaggerHandler) HandlerName() string {
+	return "swagger"
+}
+
 func (h *SwaggerHandler) Register(e *echo.Echo) {
 	e.GET("api/swagger.json", h.Spec)
 	e.GET("api/docs", h.UI)
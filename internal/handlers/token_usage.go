@@ -14,26 +14,30 @@ import (
 	"github.com/memohai/memoh/internal/bots"
 	"github.com/memohai/memoh/internal/db"
 	"github.com/memohai/memoh/internal/db/sqlc"
+	"github.com/memohai/memoh/internal/models"
 )
 
 type TokenUsageHandler struct {
 	queries        *sqlc.Queries
 	botService     *bots.Service
 	accountService *accounts.Service
+	modelsService  *models.Service
 	logger         *slog.Logger
 }
 
-func NewTokenUsageHandler(log *slog.Logger, queries *sqlc.Queries, botService *bots.Service, accountService *accounts.Service) *TokenUsageHandler {
+func NewTokenUsageHandler(log *slog.Logger, queries *sqlc.Queries, botService *bots.Service, accountService *accounts.Service, modelsService *models.Service) *TokenUsageHandler {
 	return &TokenUsageHandler{
 		queries:        queries,
 		botService:     botService,
 		accountService: accountService,
+		modelsService:  modelsService,
 		logger:         log.With(slog.String("handler", "token_usage")),
 	}
 }
 
 func (h *TokenUsageHandler) Register(e *echo.Echo) {
 	e.GET("/bots/:bot_id/token-usage", h.GetTokenUsage)
+	e.GET("/token-usage/report", h.GetTokenUsageReport)
 }
 
 // DailyTokenUsage represents aggregated token usage for a single day.
@@ -47,13 +51,15 @@ type DailyTokenUsage struct {
 }
 
 // ModelTokenUsage represents aggregated token usage for a single model.
+// EstimatedCostUSD is zero for models without configured pricing.
 type ModelTokenUsage struct {
-	ModelID      string `json:"model_id"`
-	ModelSlug    string `json:"model_slug"`
-	ModelName    string `json:"model_name"`
-	ProviderName string `json:"provider_name"`
-	InputTokens  int64  `json:"input_tokens"`
-	OutputTokens int64  `json:"output_tokens"`
+	ModelID          string  `json:"model_id"`
+	ModelSlug        string  `json:"model_slug"`
+	ModelName        string  `json:"model_name"`
+	ProviderName     string  `json:"provider_name"`
+	InputTokens      int64   `json:"input_tokens"`
+	OutputTokens     int64   `json:"output_tokens"`
+	EstimatedCostUSD float64 `json:"estimated_cost_usd"`
 }
 
 // TokenUsageResponse is the response body for GET /bots/:bot_id/token-usage.
@@ -90,21 +96,9 @@ func (h *TokenUsageHandler) GetTokenUsage(c echo.Context) error {
 		return err
 	}
 
-	fromStr := strings.TrimSpace(c.QueryParam("from"))
-	toStr := strings.TrimSpace(c.QueryParam("to"))
-	if fromStr == "" || toStr == "" {
-		return echo.NewHTTPError(http.StatusBadRequest, "from and to query parameters are required (YYYY-MM-DD)")
-	}
-	fromDate, err := time.Parse("2006-01-02", fromStr)
+	fromTS, toTS, err := parseUsageDateRange(c)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, "invalid from date format, expected YYYY-MM-DD")
-	}
-	toDate, err := time.Parse("2006-01-02", toStr)
-	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, "invalid to date format, expected YYYY-MM-DD")
-	}
-	if !toDate.After(fromDate) {
-		return echo.NewHTTPError(http.StatusBadRequest, "to must be after from")
+		return err
 	}
 
 	pgBotID, err := db.ParseUUID(botID)
@@ -120,9 +114,6 @@ func (h *TokenUsageHandler) GetTokenUsage(c echo.Context) error {
 		}
 	}
 
-	fromTS := pgtype.Timestamptz{Time: fromDate, Valid: true}
-	toTS := pgtype.Timestamptz{Time: toDate, Valid: true}
-
 	ctx := c.Request().Context()
 
 	chat, heartbeat, schedule, err := h.fetchUsageByDay(ctx, pgBotID, fromTS, toTS, pgModelID)
@@ -187,21 +178,60 @@ func (h *TokenUsageHandler) fetchUsageByModel(ctx context.Context, botID pgtype.
 	if err != nil {
 		return nil, err
 	}
+	pricing := h.modelPricing(ctx)
 
 	result := make([]ModelTokenUsage, 0, len(rows))
 	for _, r := range rows {
+		modelID := formatOptionalUUID(r.ModelID)
 		result = append(result, ModelTokenUsage{
-			ModelID:      formatOptionalUUID(r.ModelID),
-			ModelSlug:    r.ModelSlug,
-			ModelName:    r.ModelName,
-			ProviderName: r.ProviderName,
-			InputTokens:  r.InputTokens,
-			OutputTokens: r.OutputTokens,
+			ModelID:          modelID,
+			ModelSlug:        r.ModelSlug,
+			ModelName:        r.ModelName,
+			ProviderName:     r.ProviderName,
+			InputTokens:      r.InputTokens,
+			OutputTokens:     r.OutputTokens,
+			EstimatedCostUSD: pricing[modelID].Cost(r.InputTokens, r.OutputTokens),
 		})
 	}
 	return result, nil
 }
 
+// modelPricing returns the configured model prices. Cost estimation is best
+// effort: when pricing cannot be loaded, costs are reported as zero.
+func (h *TokenUsageHandler) modelPricing(ctx context.Context) map[string]models.ModelPricing {
+	if h.modelsService == nil {
+		return nil
+	}
+	pricing, err := h.modelsService.Pricing(ctx)
+	if err != nil {
+		h.logger.Warn("load model pricing failed", slog.Any("error", err))
+		return nil
+	}
+	return pricing
+}
+
+// parseUsageDateRange reads the required from/to (YYYY-MM-DD, to exclusive)
+// query parameters.
+func parseUsageDateRange(c echo.Context) (from, to pgtype.Timestamptz, err error) {
+	fromStr := strings.TrimSpace(c.QueryParam("from"))
+	toStr := strings.TrimSpace(c.QueryParam("to"))
+	if fromStr == "" || toStr == "" {
+		return from, to, echo.NewHTTPError(http.StatusBadRequest, "from and to query parameters are required (YYYY-MM-DD)")
+	}
+	fromDate, err := time.Parse("2006-01-02", fromStr)
+	if err != nil {
+		return from, to, echo.NewHTTPError(http.StatusBadRequest, "invalid from date format, expected YYYY-MM-DD")
+	}
+	toDate, err := time.Parse("2006-01-02", toStr)
+	if err != nil {
+		return from, to, echo.NewHTTPError(http.StatusBadRequest, "invalid to date format, expected YYYY-MM-DD")
+	}
+	if !toDate.After(fromDate) {
+		return from, to, echo.NewHTTPError(http.StatusBadRequest, "to must be after from")
+	}
+	return pgtype.Timestamptz{Time: fromDate, Valid: true}, pgtype.Timestamptz{Time: toDate, Valid: true}, nil
+}
+
 func formatPgDate(d pgtype.Date) string {
 	if !d.Valid {
 		return ""
@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/memohai/memoh/internal/db"
+	"github.com/memohai/memoh/internal/db/sqlc"
+	"github.com/memohai/memoh/internal/models"
+)
+
+// TokenUsageTotals holds summed token counts and their estimated cost.
+// Usage of unpriced models adds no cost.
+type TokenUsageTotals struct {
+	InputTokens      int64   `json:"input_tokens"`
+	OutputTokens     int64   `json:"output_tokens"`
+	CacheReadTokens  int64   `json:"cache_read_tokens"`
+	CacheWriteTokens int64   `json:"cache_write_tokens"`
+	ReasoningTokens  int64   `json:"reasoning_tokens"`
+	EstimatedCostUSD float64 `json:"estimated_cost_usd"`
+}
+
+func (t *TokenUsageTotals) add(o TokenUsageTotals) {
+	t.InputTokens += o.InputTokens
+	t.OutputTokens += o.OutputTokens
+	t.CacheReadTokens += o.CacheReadTokens
+	t.CacheWriteTokens += o.CacheWriteTokens
+	t.ReasoningTokens += o.ReasoningTokens
+	t.EstimatedCostUSD += o.EstimatedCostUSD
+}
+
+// TokenUsageReportModel is one model's share of a report entry.
+type TokenUsageReportModel struct {
+	ModelID   string `json:"model_id"`
+	ModelSlug string `json:"model_slug"`
+	TokenUsageTotals
+}
+
+// TokenUsageReportEntry aggregates the usage of one bot for one user on one
+// day. UserID is empty for usage not triggered by a user, such as heartbeats
+// and schedules.
+type TokenUsageReportEntry struct {
+	Day      string `json:"day"`
+	BotID    string `json:"bot_id"`
+	BotName  string `json:"bot_name"`
+	UserID   string `json:"user_id"`
+	UserName string `json:"user_name"`
+	TokenUsageTotals
+	Models []TokenUsageReportModel `json:"models"`
+}
+
+// TokenUsageReportResponse is the response body for GET /token-usage/report.
+type TokenUsageReportResponse struct {
+	Items []TokenUsageReportEntry `json:"items"`
+	Total TokenUsageTotals        `json:"total"`
+}
+
+// GetTokenUsageReport godoc
+// @Summary Get token usage report
+// @Description Aggregate stored token usage and estimated cost by bot, user, and day with a per-model breakdown. Usage is attributed to the user whose message started the turn (admin only)
+// @Tags token-usage
+// @Param from query string true "Start date (YYYY-MM-DD)"
+// @Param to query string true "End date exclusive (YYYY-MM-DD)"
+// @Param bot_id query string false "Optional bot UUID to filter by"
+// @Param user_id query string false "Optional user UUID to filter by"
+// @Success 200 {object} TokenUsageReportResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /token-usage/report [get].
+func (h *TokenUsageHandler) GetTokenUsageReport(c echo.Context) error {
+	if err := RequireAdmin(c, h.accountService); err != nil {
+		return err
+	}
+	fromTS, toTS, err := parseUsageDateRange(c)
+	if err != nil {
+		return err
+	}
+	params := sqlc.GetTokenUsageReportParams{FromTime: fromTS, ToTime: toTS}
+	if botID := strings.TrimSpace(c.QueryParam("bot_id")); botID != "" {
+		if params.BotID, err = db.ParseUUID(botID); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid bot_id")
+		}
+	}
+	if userID := strings.TrimSpace(c.QueryParam("user_id")); userID != "" {
+		if params.UserID, err = db.ParseUUID(userID); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid user_id")
+		}
+	}
+
+	rows, err := h.queries.GetTokenUsageReport(c.Request().Context(), params)
+	if err != nil {
+		h.logger.Error("fetch token usage report failed", slog.Any("error", err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to fetch token usage report")
+	}
+	return c.JSON(http.StatusOK, aggregateTokenUsageReport(rows, h.modelPricing(c.Request().Context())))
+}
+
+// aggregateTokenUsageReport folds per-model rows into one entry per bot, user
+// and day, keeping the order in which the entries first appear. Costs are
+// estimated from pricing, keyed by model UUID.
+func aggregateTokenUsageReport(rows []sqlc.GetTokenUsageReportRow, pricing map[string]models.ModelPricing) TokenUsageReportResponse {
+	resp := TokenUsageReportResponse{Items: make([]TokenUsageReportEntry, 0)}
+	index := make(map[[3]string]int)
+	for _, r := range rows {
+		day := formatPgDate(r.Day)
+		botID := formatOptionalUUID(r.BotID)
+		userID := formatOptionalUUID(r.UserID)
+		key := [3]string{day, botID, userID}
+		i, ok := index[key]
+		if !ok {
+			i = len(resp.Items)
+			index[key] = i
+			resp.Items = append(resp.Items, TokenUsageReportEntry{
+				Day:      day,
+				BotID:    botID,
+				BotName:  r.BotName,
+				UserID:   userID,
+				UserName: r.UserName,
+				Models:   make([]TokenUsageReportModel, 0, 1),
+			})
+		}
+		modelID := formatOptionalUUID(r.ModelID)
+		totals := TokenUsageTotals{
+			InputTokens:      r.InputTokens,
+			OutputTokens:     r.OutputTokens,
+			CacheReadTokens:  r.CacheReadTokens,
+			CacheWriteTokens: r.CacheWriteTokens,
+			ReasoningTokens:  r.ReasoningTokens,
+			EstimatedCostUSD: pricing[modelID].Cost(r.InputTokens, r.OutputTokens),
+		}
+		entry := &resp.Items[i]
+		entry.TokenUsageTotals.add(totals)
+		entry.Models = append(entry.Models, TokenUsageReportModel{
+			ModelID:          modelID,
+			ModelSlug:        r.ModelSlug,
+			TokenUsageTotals: totals,
+		})
+		resp.Total.add(totals)
+	}
+	return resp
+}
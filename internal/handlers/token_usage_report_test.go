@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/memohai/memoh/internal/db/sqlc"
+	"github.com/memohai/memoh/internal/models"
+)
+
+func reportUUID(b byte) pgtype.UUID {
+	return pgtype.UUID{Bytes: [16]byte{b}, Valid: true}
+}
+
+func reportDay(day string) pgtype.Date {
+	t, _ := time.Parse("2006-01-02", day)
+	return pgtype.Date{Time: t, Valid: true}
+}
+
+func TestAggregateTokenUsageReport(t *testing.T) {
+	bot, alice := reportUUID(1), reportUUID(2)
+	gpt, claude := reportUUID(3), reportUUID(4)
+	rows := []sqlc.GetTokenUsageReportRow{
+		{BotID: bot, BotName: "helper", UserID: alice, UserName: "alice", Day: reportDay("2026-03-01"), ModelID: gpt, ModelSlug: "gpt", InputTokens: 100, OutputTokens: 10, CacheReadTokens: 40},
+		{BotID: bot, BotName: "helper", UserID: alice, UserName: "alice", Day: reportDay("2026-03-01"), ModelID: claude, ModelSlug: "claude", InputTokens: 200, OutputTokens: 20, ReasoningTokens: 5},
+		{BotID: bot, BotName: "helper", Day: reportDay("2026-03-01"), ModelID: gpt, ModelSlug: "gpt", InputTokens: 7, OutputTokens: 1},
+		{BotID: bot, BotName: "helper", UserID: alice, UserName: "alice", Day: reportDay("2026-03-02"), ModelSlug: "unknown", InputTokens: 50, OutputTokens: 5, CacheWriteTokens: 3},
+	}
+
+	pricing := map[string]models.ModelPricing{gpt.String(): {InputPerMillion: 2, OutputPerMillion: 10}}
+
+	resp := aggregateTokenUsageReport(rows, pricing)
+
+	if len(resp.Items) != 3 {
+		t.Fatalf("expected 3 entries, got %d: %+v", len(resp.Items), resp.Items)
+	}
+	first := resp.Items[0]
+	if first.Day != "2026-03-01" || first.UserID != alice.String() || first.UserName != "alice" || first.BotName != "helper" {
+		t.Fatalf("unexpected first entry: %+v", first)
+	}
+	if first.InputTokens != 300 || first.OutputTokens != 30 || first.CacheReadTokens != 40 || first.ReasoningTokens != 5 {
+		t.Fatalf("unexpected first entry totals: %+v", first.TokenUsageTotals)
+	}
+	if len(first.Models) != 2 || first.Models[0].ModelSlug != "gpt" || first.Models[1].ModelID != claude.String() || first.Models[1].InputTokens != 200 {
+		t.Fatalf("unexpected model breakdown: %+v", first.Models)
+	}
+	if unattributed := resp.Items[1]; unattributed.UserID != "" || unattributed.InputTokens != 7 || len(unattributed.Models) != 1 {
+		t.Fatalf("expected unattributed usage in its own entry, got %+v", unattributed)
+	}
+	if second := resp.Items[2]; second.Day != "2026-03-02" || second.CacheWriteTokens != 3 || second.Models[0].ModelID != "" {
+		t.Fatalf("unexpected second day entry: %+v", second)
+	}
+	if cost := first.Models[0].EstimatedCostUSD; math.Abs(cost-0.0003) > 1e-12 {
+		t.Fatalf("expected gpt cost 0.0003, got %v", cost)
+	}
+	if cost := first.Models[1].EstimatedCostUSD; cost != 0 {
+		t.Fatalf("expected unpriced model to cost nothing, got %v", cost)
+	}
+	if cost := resp.Total.EstimatedCostUSD; math.Abs(cost-0.000324) > 1e-12 {
+		t.Fatalf("expected total cost 0.000324, got %v", cost)
+	}
+	resp.Total.EstimatedCostUSD = 0
+	want := TokenUsageTotals{InputTokens: 357, OutputTokens: 36, CacheReadTokens: 40, CacheWriteTokens: 3, ReasoningTokens: 5}
+	if resp.Total != want {
+		t.Fatalf("expected total %+v, got %+v", want, resp.Total)
+	}
+}
+
+func TestAggregateTokenUsageReportEmpty(t *testing.T) {
+	resp := aggregateTokenUsageReport(nil, nil)
+	if resp.Items == nil || len(resp.Items) != 0 || resp.Total != (TokenUsageTotals{}) {
+		t.Fatalf("expected empty report, got %+v", resp)
+	}
+}
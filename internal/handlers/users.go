@@ -75,9 +75,11 @@ func (h *UsersHandler) Register(e *echo.Echo) {
 	botGroup.PUT("/:id", h.UpdateBot)
 	botGroup.PUT("/:id/owner", h.TransferBotOwner)
 	botGroup.DELETE("/:id", h.DeleteBot)
+	botGroup.POST("/:id/undelete", h.UndeleteBot)
 	botGroup.GET("/:id/channel/:platform", h.GetBotChannelConfig)
 	botGroup.PUT("/:id/channel/:platform", h.UpsertBotChannelConfig)
 	botGroup.PATCH("/:id/channel/:platform/status", h.UpdateBotChannelStatus)
+	botGroup.POST("/:id/channel/:platform/restart", h.RestartBotChannel)
 	botGroup.DELETE("/:id/channel/:platform", h.DeleteBotChannelConfig)
 	botGroup.POST("/:id/channel/:platform/send", h.SendBotMessage)
 	botGroup.POST("/:id/channel/:platform/send_chat", h.SendBotMessageSession)
@@ -449,9 +451,10 @@ func (h *UsersHandler) CreateBot(c echo.Context) error {
 
 // ListBots godoc
 // @Summary List bots
-// @Description List bots accessible to current user (admin can specify owner_id)
+// @Description List bots accessible to current user (admin can specify owner_id), with last message and schedule run times
 // @Tags bots
 // @Param owner_id query string false "Owner user ID (admin only)"
+// @Param sort query string false "Sort order: created (default) or activity"
 // @Success 200 {object} bots.ListBotsResponse
 // @Failure 400 {object} ErrorResponse
 // @Failure 403 {object} ErrorResponse
@@ -462,6 +465,11 @@ func (h *UsersHandler) ListBots(c echo.Context) error {
 	if err != nil {
 		return err
 	}
+	sortBy := strings.TrimSpace(c.QueryParam("sort"))
+	if sortBy != "" && sortBy != bots.ListSortCreated && sortBy != bots.ListSortActivity {
+		return echo.NewHTTPError(http.StatusBadRequest, "sort must be created or activity")
+	}
+	var items []bots.Bot
 	ownerID := strings.TrimSpace(c.QueryParam("owner_id"))
 	if ownerID != "" {
 		isAdmin, err := h.service.IsAdmin(c.Request().Context(), channelIdentityID)
@@ -471,15 +479,18 @@ func (h *UsersHandler) ListBots(c echo.Context) error {
 		if !isAdmin {
 			return echo.NewHTTPError(http.StatusForbidden, "admin role required for owner filter")
 		}
-		items, err := h.botService.ListByOwner(c.Request().Context(), ownerID)
+		items, err = h.botService.ListByOwner(c.Request().Context(), ownerID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		}
+	} else {
+		items, err = h.botService.ListAccessible(c.Request().Context(), channelIdentityID)
 		if err != nil {
 			return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
 		}
-		return c.JSON(http.StatusOK, bots.ListBotsResponse{Items: items})
 	}
-	items, err := h.botService.ListAccessible(c.Request().Context(), channelIdentityID)
-	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	if sortBy == bots.ListSortActivity {
+		bots.SortByActivity(items)
 	}
 	return c.JSON(http.StatusOK, bots.ListBotsResponse{Items: items})
 }
@@ -659,6 +670,46 @@ func (h *UsersHandler) DeleteBot(c echo.Context) error {
 	})
 }
 
+// UndeleteBot godoc
+// @Summary Undelete bot
+// @Description Restore a deleted bot before its delete grace period ends (owner/admin only)
+// @Tags bots
+// @Param id path string true "Bot ID"
+// @Success 200 {object} bots.Bot
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 410 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /bots/{id}/undelete [post].
+func (h *UsersHandler) UndeleteBot(c echo.Context) error {
+	channelIdentityID, err := h.requireChannelIdentityID(c)
+	if err != nil {
+		return err
+	}
+	botID := strings.TrimSpace(c.Param("id"))
+	if botID == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "bot id is required")
+	}
+	if _, err := h.authorizeBotAccess(c.Request().Context(), channelIdentityID, botID); err != nil {
+		return err
+	}
+	resp, err := h.botService.Undelete(c.Request().Context(), botID)
+	if err != nil {
+		switch {
+		case errors.Is(err, bots.ErrBotNotFound):
+			return echo.NewHTTPError(http.StatusNotFound, "bot not found")
+		case errors.Is(err, bots.ErrBotNotDeleted):
+			return echo.NewHTTPError(http.StatusConflict, err.Error())
+		case errors.Is(err, bots.ErrUndeleteExpired):
+			return echo.NewHTTPError(http.StatusGone, err.Error())
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
 // GetBotChannelConfig godoc
 // @Summary Get bot channel config
 // @Description Get bot channel configuration
@@ -736,6 +787,15 @@ func (h *UsersHandler) UpsertBotChannelConfig(c echo.Context) error {
 	if req.Credentials == nil {
 		req.Credentials = map[string]any{}
 	}
+	if _, err := channel.ReplyModeFromRouting(req.Routing); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if _, err := channel.SenderAttributesFromRouting(req.Routing); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if _, err := channel.ProcessingStatusTimeoutFromRouting(req.Routing); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
 	if h.channelLifecycle == nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, "channel lifecycle not configured")
 	}
@@ -800,6 +860,56 @@ func (h *UsersHandler) UpdateBotChannelStatus(c echo.Context) error {
 	return c.JSON(http.StatusOK, resp)
 }
 
+// RestartBotChannel godoc
+// @Summary Restart bot channel connection
+// @Description Stop and reconnect the bot's channel adapter without changing its config
+// @Tags bots
+// @Param id path string true "Bot ID"
+// @Param platform path string true "Channel platform"
+// @Success 200 {object} channel.ChannelConfig
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /bots/{id}/channel/{platform}/restart [post].
+func (h *UsersHandler) RestartBotChannel(c echo.Context) error {
+	channelIdentityID, err := h.requireChannelIdentityID(c)
+	if err != nil {
+		return err
+	}
+	botID := strings.TrimSpace(c.Param("id"))
+	if botID == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "bot id is required")
+	}
+	bot, err := h.authorizeBotAccess(c.Request().Context(), channelIdentityID, botID)
+	if err != nil {
+		return err
+	}
+	if bot.Status == bots.BotStatusDeleting {
+		return echo.NewHTTPError(http.StatusConflict, channel.ErrBotPendingDeletion.Error())
+	}
+	channelType, err := h.registry.ParseChannelType(c.Param("platform"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if h.channelLifecycle == nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "channel lifecycle not configured")
+	}
+	resp, err := h.channelLifecycle.RestartBotChannel(c.Request().Context(), botID, channelType)
+	if err != nil {
+		switch {
+		case errors.Is(err, channel.ErrChannelConfigNotFound):
+			return echo.NewHTTPError(http.StatusNotFound, err.Error())
+		case errors.Is(err, channel.ErrChannelDisabled), errors.Is(err, channel.ErrStopNotSupported), errors.Is(err, channel.ErrBotPendingDeletion):
+			return echo.NewHTTPError(http.StatusConflict, err.Error())
+		}
+		h.logger.Error("restart bot channel failed", slog.String("bot_id", botID), slog.String("channel", channelType.String()), slog.Any("error", err))
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
 // DeleteBotChannelConfig godoc
 // @Summary Delete bot channel config
 // @Description Remove bot channel configuration
@@ -844,6 +954,7 @@ func (h *UsersHandler) DeleteBotChannelConfig(c echo.Context) error {
 // @Param platform path string true "Channel platform"
 // @Param payload body channel.SendRequest true "Send payload"
 // @Success 200 {object} map[string]string
+// @Success 202 {object} map[string]string
 // @Failure 400 {object} ErrorResponse
 // @Failure 403 {object} ErrorResponse
 // @Failure 404 {object} ErrorResponse
@@ -876,6 +987,9 @@ func (h *UsersHandler) SendBotMessage(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, "message is required")
 	}
 	if err := h.channelManager.Send(c.Request().Context(), botID, channelType, req); err != nil {
+		if errors.Is(err, channel.ErrOutboundQueued) {
+			return c.JSON(http.StatusAccepted, map[string]string{"status": "queued"})
+		}
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 	return c.JSON(http.StatusOK, map[string]string{"status": "ok"})
@@ -889,6 +1003,7 @@ func (h *UsersHandler) SendBotMessage(c echo.Context) error {
 // @Param platform path string true "Channel platform"
 // @Param payload body channel.SendRequest true "Send payload"
 // @Success 200 {object} map[string]string
+// @Success 202 {object} map[string]string
 // @Failure 400 {object} ErrorResponse
 // @Failure 401 {object} ErrorResponse
 // @Failure 403 {object} ErrorResponse
@@ -932,6 +1047,9 @@ func (h *UsersHandler) SendBotMessageSession(c echo.Context) error {
 		Target:  route.ReplyTarget,
 		Message: req.Message,
 	}); err != nil {
+		if errors.Is(err, channel.ErrOutboundQueued) {
+			return c.JSON(http.StatusAccepted, map[string]string{"status": "queued"})
+		}
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 	return c.JSON(http.StatusOK, map[string]string{"status": "ok"})
@@ -0,0 +1,80 @@
+package containerchecker
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/memohai/memoh/internal/healthcheck"
+	"github.com/memohai/memoh/internal/workspace"
+)
+
+const (
+	checkTypeContainerRestart = "container.restart"
+	titleKeyContainerRestart  = "bots.checks.titles.containerRestart"
+)
+
+// RestartObserver reads the restart history of bot containers.
+type RestartObserver interface {
+	RestartStatus(botID string) (workspace.RestartStatus, bool)
+}
+
+// Checker evaluates container restart and crash-loop health checks.
+type Checker struct {
+	logger   *slog.Logger
+	observer RestartObserver
+}
+
+// NewChecker creates a container restart health checker.
+func NewChecker(log *slog.Logger, observer RestartObserver) *Checker {
+	if log == nil {
+		log = slog.Default()
+	}
+	return &Checker{
+		logger:   log.With(slog.String("checker", "healthcheck_container")),
+		observer: observer,
+	}
+}
+
+// ListChecks reports the restart count and last error of a bot container.
+// Bots whose container has not been restarted produce no checks.
+func (c *Checker) ListChecks(ctx context.Context, botID string) []healthcheck.CheckResult {
+	if err := ctx.Err(); err != nil {
+		return []healthcheck.CheckResult{}
+	}
+	botID = strings.TrimSpace(botID)
+	if botID == "" || c.observer == nil {
+		return []healthcheck.CheckResult{}
+	}
+	status, ok := c.observer.RestartStatus(botID)
+	if !ok {
+		return []healthcheck.CheckResult{}
+	}
+
+	item := healthcheck.CheckResult{
+		ID:       checkTypeContainerRestart,
+		Type:     checkTypeContainerRestart,
+		TitleKey: titleKeyContainerRestart,
+		Status:   healthcheck.StatusOK,
+		Summary:  fmt.Sprintf("Container restarted %d time(s).", status.RestartCount),
+		Detail:   strings.TrimSpace(status.LastError),
+		Metadata: map[string]any{
+			"restart_count": status.RestartCount,
+			"crash_looping": status.CrashLooping,
+		},
+	}
+	if strings.TrimSpace(status.LastError) != "" {
+		item.Metadata["last_error"] = strings.TrimSpace(status.LastError)
+	}
+	if !status.LastRestartAt.IsZero() {
+		item.Metadata["last_restart_at"] = status.LastRestartAt.UTC().Format(time.RFC3339)
+	}
+	if status.CrashLooping {
+		item.Status = healthcheck.StatusError
+		item.Summary = fmt.Sprintf("Container is crash looping after %d restarts; restarts paused.", status.RestartCount)
+		item.Metadata["backoff_until"] = status.BackoffUntil.UTC().Format(time.RFC3339)
+	}
+	return []healthcheck.CheckResult{item}
+}
@@ -0,0 +1,66 @@
+package containerchecker
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/memohai/memoh/internal/workspace"
+)
+
+type fakeRestartObserver struct {
+	status workspace.RestartStatus
+	ok     bool
+}
+
+func (f *fakeRestartObserver) RestartStatus(_ string) (workspace.RestartStatus, bool) {
+	return f.status, f.ok
+}
+
+func TestCheckerListChecksCrashLoop(t *testing.T) {
+	t.Parallel()
+	backoffUntil := time.Date(2026, 1, 1, 0, 5, 0, 0, time.UTC)
+	checker := NewChecker(slog.New(slog.DiscardHandler), &fakeRestartObserver{
+		ok: true,
+		status: workspace.RestartStatus{
+			RestartCount:  5,
+			LastError:     "task stopped with exit code 137",
+			LastRestartAt: backoffUntil.Add(-30 * time.Second),
+			BackoffUntil:  backoffUntil,
+			CrashLooping:  true,
+		},
+	})
+
+	items := checker.ListChecks(context.Background(), "bot-1")
+	if len(items) != 1 {
+		t.Fatalf("expected 1 check, got %d", len(items))
+	}
+	item := items[0]
+	if item.Status != "error" {
+		t.Fatalf("expected error status, got %s", item.Status)
+	}
+	if item.Metadata["restart_count"] != 5 {
+		t.Fatalf("expected restart_count 5, got %v", item.Metadata["restart_count"])
+	}
+	if item.Metadata["last_error"] != "task stopped with exit code 137" {
+		t.Fatalf("unexpected last_error: %v", item.Metadata["last_error"])
+	}
+	if item.Metadata["backoff_until"] != "2026-01-01T00:05:00Z" {
+		t.Fatalf("unexpected backoff_until: %v", item.Metadata["backoff_until"])
+	}
+}
+
+func TestCheckerListChecksHealthyAndUnknown(t *testing.T) {
+	t.Parallel()
+	checker := NewChecker(nil, &fakeRestartObserver{ok: true, status: workspace.RestartStatus{RestartCount: 1}})
+	items := checker.ListChecks(context.Background(), "bot-1")
+	if len(items) != 1 || items[0].Status != "ok" {
+		t.Fatalf("expected single ok check, got %+v", items)
+	}
+
+	none := NewChecker(nil, &fakeRestartObserver{})
+	if items := none.ListChecks(context.Background(), "bot-1"); len(items) != 0 {
+		t.Fatalf("expected no checks without restart history, got %+v", items)
+	}
+}
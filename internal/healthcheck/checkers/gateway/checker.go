@@ -0,0 +1,101 @@
+package gatewaychecker
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/memohai/memoh/internal/healthcheck"
+)
+
+const (
+	checkTypeGatewayHealth = "gateway.health"
+	titleKeyGatewayHealth  = "bots.checks.titles.gatewayHealth"
+
+	// probeTimeout bounds one health probe so a hung gateway cannot stall the
+	// bot checks endpoint.
+	probeTimeout = 3 * time.Second
+)
+
+// Checker probes the browser gateway's /health endpoint. The gateway is
+// shared by all bots, so every bot reports the same result.
+type Checker struct {
+	logger     *slog.Logger
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewChecker creates a gateway health checker for the gateway at baseURL. A
+// nil client uses http.DefaultClient.
+func NewChecker(log *slog.Logger, baseURL string, client *http.Client) *Checker {
+	if log == nil {
+		log = slog.Default()
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &Checker{
+		logger:     log.With(slog.String("checker", "healthcheck_gateway")),
+		baseURL:    strings.TrimRight(strings.TrimSpace(baseURL), "/"),
+		httpClient: client,
+	}
+}
+
+// ListChecks reports whether the gateway answers its health endpoint.
+func (c *Checker) ListChecks(ctx context.Context, botID string) []healthcheck.CheckResult {
+	if err := ctx.Err(); err != nil {
+		return []healthcheck.CheckResult{}
+	}
+	if strings.TrimSpace(botID) == "" || c.baseURL == "" {
+		return []healthcheck.CheckResult{}
+	}
+
+	item := healthcheck.CheckResult{
+		ID:       checkTypeGatewayHealth,
+		Type:     checkTypeGatewayHealth,
+		TitleKey: titleKeyGatewayHealth,
+		Subtitle: c.baseURL,
+		Status:   healthcheck.StatusOK,
+		Summary:  "Gateway is reachable.",
+		Metadata: map[string]any{
+			"base_url": c.baseURL,
+		},
+	}
+	started := time.Now()
+	err := c.probe(ctx)
+	item.Metadata["latency_ms"] = time.Since(started).Milliseconds()
+	if err != nil {
+		c.logger.Warn("gateway health probe failed",
+			slog.String("bot_id", botID),
+			slog.String("base_url", c.baseURL),
+			slog.Any("error", err),
+		)
+		item.Status = healthcheck.StatusError
+		item.Summary = "Gateway is unreachable."
+		item.Detail = err.Error()
+	}
+	return []healthcheck.CheckResult{item}
+}
+
+func (c *Checker) probe(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, probeTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/health", nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.httpClient.Do(req) //nolint:gosec // base URL comes from server config
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("health endpoint returned %s", resp.Status)
+	}
+	return nil
+}
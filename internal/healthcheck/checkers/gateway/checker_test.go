@@ -0,0 +1,76 @@
+package gatewaychecker
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newFakeGateway(t *testing.T, status int) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/health" {
+			http.NotFound(w, r)
+			return
+		}
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(`{"status":"ok"}`))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestCheckerListChecksHealthy(t *testing.T) {
+	t.Parallel()
+	srv := newFakeGateway(t, http.StatusOK)
+	checker := NewChecker(slog.New(slog.DiscardHandler), srv.URL+"/", srv.Client())
+
+	items := checker.ListChecks(context.Background(), "bot-1")
+	if len(items) != 1 {
+		t.Fatalf("expected 1 check, got %d", len(items))
+	}
+	if items[0].Status != "ok" || items[0].Type != checkTypeGatewayHealth {
+		t.Fatalf("expected ok gateway check, got %+v", items[0])
+	}
+	if items[0].Metadata["base_url"] != srv.URL {
+		t.Fatalf("unexpected base_url: %v", items[0].Metadata["base_url"])
+	}
+}
+
+func TestCheckerListChecksUnhealthy(t *testing.T) {
+	t.Parallel()
+	srv := newFakeGateway(t, http.StatusServiceUnavailable)
+	checker := NewChecker(slog.New(slog.DiscardHandler), srv.URL, srv.Client())
+
+	items := checker.ListChecks(context.Background(), "bot-1")
+	if len(items) != 1 || items[0].Status != "error" {
+		t.Fatalf("expected single error check, got %+v", items)
+	}
+	if !strings.Contains(items[0].Detail, "503") {
+		t.Fatalf("expected status code in detail, got %q", items[0].Detail)
+	}
+}
+
+func TestCheckerListChecksUnreachable(t *testing.T) {
+	t.Parallel()
+	srv := httptest.NewServer(http.NotFoundHandler())
+	url := srv.URL
+	srv.Close()
+	checker := NewChecker(slog.New(slog.DiscardHandler), url, nil)
+
+	items := checker.ListChecks(context.Background(), "bot-1")
+	if len(items) != 1 || items[0].Status != "error" || items[0].Detail == "" {
+		t.Fatalf("expected error check with detail, got %+v", items)
+	}
+}
+
+func TestCheckerListChecksSkipsWithoutGateway(t *testing.T) {
+	t.Parallel()
+	checker := NewChecker(nil, "", nil)
+	if items := checker.ListChecks(context.Background(), "bot-1"); len(items) != 0 {
+		t.Fatalf("expected no checks without a gateway URL, got %+v", items)
+	}
+}
@@ -0,0 +1,97 @@
+package schedulechecker
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/memohai/memoh/internal/healthcheck"
+	"github.com/memohai/memoh/internal/schedule"
+)
+
+const (
+	checkTypeScheduleFailures = "schedule.failures"
+	titleKeyScheduleFailures  = "bots.checks.titles.scheduleFailures"
+)
+
+// ScheduleReader reads bot schedules and their execution history.
+type ScheduleReader interface {
+	List(ctx context.Context, botID string) ([]schedule.Schedule, error)
+	ListLogsBySchedule(ctx context.Context, scheduleID string, limit, offset int) ([]schedule.Log, int64, error)
+}
+
+// Checker reports enabled schedules whose recent runs keep failing.
+type Checker struct {
+	logger    *slog.Logger
+	reader    ScheduleReader
+	threshold int
+}
+
+// NewChecker creates a schedule failure health checker. A schedule is reported
+// once its last threshold runs have all failed; a non-positive threshold uses
+// schedule.DefaultFailureAlertThreshold.
+func NewChecker(log *slog.Logger, reader ScheduleReader, threshold int) *Checker {
+	if log == nil {
+		log = slog.Default()
+	}
+	if threshold <= 0 {
+		threshold = schedule.DefaultFailureAlertThreshold
+	}
+	return &Checker{
+		logger:    log.With(slog.String("checker", "healthcheck_schedule")),
+		reader:    reader,
+		threshold: threshold,
+	}
+}
+
+// ListChecks returns one error check per failing schedule. Healthy schedules
+// and bots without schedules produce no checks.
+func (c *Checker) ListChecks(ctx context.Context, botID string) []healthcheck.CheckResult {
+	if err := ctx.Err(); err != nil {
+		return []healthcheck.CheckResult{}
+	}
+	botID = strings.TrimSpace(botID)
+	if botID == "" || c.reader == nil {
+		return []healthcheck.CheckResult{}
+	}
+	schedules, err := c.reader.List(ctx, botID)
+	if err != nil {
+		c.logger.Warn("list schedules for healthcheck failed", slog.String("bot_id", botID), slog.Any("error", err))
+		return []healthcheck.CheckResult{}
+	}
+
+	items := make([]healthcheck.CheckResult, 0)
+	for _, sched := range schedules {
+		if !sched.Enabled {
+			continue
+		}
+		// Fetch a little more than the threshold so in-progress runs at the
+		// head of the history do not hide a failure streak.
+		logs, _, err := c.reader.ListLogsBySchedule(ctx, sched.ID, c.threshold+2, 0)
+		if err != nil {
+			c.logger.Warn("list schedule logs for healthcheck failed", slog.String("schedule_id", sched.ID), slog.Any("error", err))
+			continue
+		}
+		streak := schedule.ConsecutiveFailures(logs)
+		if streak.Count < c.threshold {
+			continue
+		}
+		items = append(items, healthcheck.CheckResult{
+			ID:       checkTypeScheduleFailures + "." + sched.ID,
+			Type:     checkTypeScheduleFailures,
+			TitleKey: titleKeyScheduleFailures,
+			Subtitle: sched.Name,
+			Status:   healthcheck.StatusError,
+			Summary:  fmt.Sprintf("Last %d scheduled runs failed.", streak.Count),
+			Detail:   strings.TrimSpace(streak.LastError),
+			Metadata: map[string]any{
+				"schedule_id":          sched.ID,
+				"consecutive_failures": streak.Count,
+				"last_run_at":          streak.LastRunAt.UTC().Format(time.RFC3339),
+			},
+		})
+	}
+	return items
+}
@@ -0,0 +1,92 @@
+package schedulechecker
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/memohai/memoh/internal/schedule"
+)
+
+type fakeScheduleReader struct {
+	schedules []schedule.Schedule
+	logs      map[string][]schedule.Log
+}
+
+func (f *fakeScheduleReader) List(_ context.Context, _ string) ([]schedule.Schedule, error) {
+	return f.schedules, nil
+}
+
+func (f *fakeScheduleReader) ListLogsBySchedule(_ context.Context, scheduleID string, limit, _ int) ([]schedule.Log, int64, error) {
+	logs := f.logs[scheduleID]
+	total := int64(len(logs))
+	if len(logs) > limit {
+		logs = logs[:limit]
+	}
+	return logs, total, nil
+}
+
+func runLog(status, errMsg string, at time.Time) schedule.Log {
+	completed := at.Add(time.Second)
+	return schedule.Log{Status: status, ErrorMessage: errMsg, StartedAt: at, CompletedAt: &completed}
+}
+
+func TestCheckerReportsRepeatedFailures(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	reader := &fakeScheduleReader{
+		schedules: []schedule.Schedule{
+			{ID: "failing", Name: "daily report", Enabled: true},
+			{ID: "recovered", Name: "hourly sync", Enabled: true},
+			{ID: "disabled", Name: "old job", Enabled: false},
+		},
+		logs: map[string][]schedule.Log{
+			"failing": {
+				runLog("error", "model timeout", now),
+				runLog("error", "model timeout", now.Add(-time.Hour)),
+				runLog("error", "rate limited", now.Add(-2*time.Hour)),
+				runLog("ok", "", now.Add(-3*time.Hour)),
+			},
+			"recovered": {
+				runLog("ok", "", now),
+				runLog("error", "boom", now.Add(-time.Hour)),
+				runLog("error", "boom", now.Add(-2*time.Hour)),
+				runLog("error", "boom", now.Add(-3*time.Hour)),
+			},
+			"disabled": {
+				runLog("error", "boom", now),
+				runLog("error", "boom", now.Add(-time.Hour)),
+				runLog("error", "boom", now.Add(-2*time.Hour)),
+			},
+		},
+	}
+	checker := NewChecker(slog.New(slog.DiscardHandler), reader, 3)
+
+	items := checker.ListChecks(context.Background(), "bot-1")
+	if len(items) != 1 {
+		t.Fatalf("expected 1 failing schedule, got %+v", items)
+	}
+	item := items[0]
+	if item.Status != "error" || item.ID != "schedule.failures.failing" || item.Subtitle != "daily report" {
+		t.Fatalf("unexpected check: %+v", item)
+	}
+	if item.Detail != "model timeout" || item.Metadata["consecutive_failures"] != 3 {
+		t.Fatalf("unexpected failure details: %+v", item)
+	}
+}
+
+func TestCheckerIgnoresFailuresBelowThreshold(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	reader := &fakeScheduleReader{
+		schedules: []schedule.Schedule{{ID: "s1", Enabled: true}},
+		logs: map[string][]schedule.Log{
+			"s1": {runLog("error", "boom", now), runLog("error", "boom", now.Add(-time.Hour))},
+		},
+	}
+	checker := NewChecker(nil, reader, 0)
+	if items := checker.ListChecks(context.Background(), "bot-1"); len(items) != 0 {
+		t.Fatalf("expected no checks below the default threshold, got %+v", items)
+	}
+}
@@ -0,0 +1,100 @@
+package skillchecker
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/memohai/memoh/internal/conversation/flow"
+	"github.com/memohai/memoh/internal/healthcheck"
+)
+
+const (
+	checkTypeSkillValidation = "skill.validation"
+	titleKeySkillValidation  = "bots.checks.titles.skillValidation"
+)
+
+// SkillLoader loads the skills of a bot along with their validation errors.
+type SkillLoader interface {
+	LoadSkills(ctx context.Context, botID string) ([]flow.SkillEntry, error)
+}
+
+// Checker reports skills that failed validation on load.
+type Checker struct {
+	logger *slog.Logger
+	loader SkillLoader
+}
+
+// NewChecker creates a skill validation health checker.
+func NewChecker(log *slog.Logger, loader SkillLoader) *Checker {
+	if log == nil {
+		log = slog.Default()
+	}
+	return &Checker{
+		logger: log.With(slog.String("checker", "healthcheck_skill")),
+		loader: loader,
+	}
+}
+
+// ListChecks returns one warning per invalid skill, or a single OK check when
+// every loaded skill is valid. Bots without skills, or whose skills cannot be
+// read because the container is unavailable, produce no checks.
+func (c *Checker) ListChecks(ctx context.Context, botID string) []healthcheck.CheckResult {
+	botID = strings.TrimSpace(botID)
+	if botID == "" || c.loader == nil {
+		return []healthcheck.CheckResult{}
+	}
+	skills, err := c.loader.LoadSkills(ctx, botID)
+	if err != nil {
+		c.logger.Debug("skill healthcheck load failed",
+			slog.String("bot_id", botID), slog.Any("error", err))
+		return []healthcheck.CheckResult{}
+	}
+	if len(skills) == 0 {
+		return []healthcheck.CheckResult{}
+	}
+
+	results := make([]healthcheck.CheckResult, 0)
+	for idx, skill := range skills {
+		if strings.TrimSpace(skill.Error) == "" {
+			continue
+		}
+		name := strings.TrimSpace(skill.Name)
+		results = append(results, healthcheck.CheckResult{
+			ID:       buildCheckID(name, idx),
+			Type:     checkTypeSkillValidation,
+			TitleKey: titleKeySkillValidation,
+			Subtitle: name,
+			Status:   healthcheck.StatusWarn,
+			Summary:  fmt.Sprintf("Skill %q is invalid and was not loaded.", name),
+			Detail:   strings.TrimSpace(skill.Error),
+			Metadata: map[string]any{
+				"name": name,
+			},
+		})
+	}
+	if len(results) > 0 {
+		return results
+	}
+	return []healthcheck.CheckResult{
+		{
+			ID:       checkTypeSkillValidation,
+			Type:     checkTypeSkillValidation,
+			TitleKey: titleKeySkillValidation,
+			Status:   healthcheck.StatusOK,
+			Summary:  fmt.Sprintf("All %d skills are valid.", len(skills)),
+			Metadata: map[string]any{
+				"skill_count": len(skills),
+			},
+		},
+	}
+}
+
+func buildCheckID(name string, idx int) string {
+	if name != "" {
+		return checkTypeSkillValidation + "." + name + "." + strconv.Itoa(idx+1)
+	}
+	return checkTypeSkillValidation + ".unknown_" + strconv.Itoa(idx+1)
+}
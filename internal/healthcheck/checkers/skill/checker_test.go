@@ -0,0 +1,64 @@
+package skillchecker
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/memohai/memoh/internal/conversation/flow"
+)
+
+type fakeSkillLoader struct {
+	items []flow.SkillEntry
+	err   error
+}
+
+func (f *fakeSkillLoader) LoadSkills(_ context.Context, _ string) ([]flow.SkillEntry, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.items, nil
+}
+
+func newTestLogger() *slog.Logger {
+	return slog.New(slog.DiscardHandler)
+}
+
+func TestCheckerReportsInvalidSkills(t *testing.T) {
+	t.Parallel()
+	checker := NewChecker(newTestLogger(), &fakeSkillLoader{items: []flow.SkillEntry{
+		{Name: "weather", Description: "Check the weather", Content: "Use the API."},
+		{Name: "huge", Error: "skill file is 70000 bytes, exceeding the 65536 byte limit"},
+		{Name: "broken", Error: "frontmatter description is required"},
+	}})
+
+	items := checker.ListChecks(context.Background(), "bot-1")
+	if len(items) != 2 {
+		t.Fatalf("expected 2 checks, got %d", len(items))
+	}
+	if items[0].Status != "warn" || items[0].Subtitle != "huge" {
+		t.Fatalf("unexpected first check: %+v", items[0])
+	}
+	if items[1].Detail != "frontmatter description is required" {
+		t.Fatalf("expected validation error as detail, got %q", items[1].Detail)
+	}
+	if items[0].ID == items[1].ID {
+		t.Fatalf("expected unique check IDs, got %q", items[0].ID)
+	}
+}
+
+func TestCheckerAllValidAndUnavailable(t *testing.T) {
+	t.Parallel()
+	valid := NewChecker(newTestLogger(), &fakeSkillLoader{items: []flow.SkillEntry{{Name: "weather"}}})
+	items := valid.ListChecks(context.Background(), "bot-1")
+	if len(items) != 1 || items[0].Status != "ok" {
+		t.Fatalf("expected single ok check, got %+v", items)
+	}
+
+	for _, loader := range []*fakeSkillLoader{{}, {err: errors.New("container not reachable")}} {
+		if items := NewChecker(newTestLogger(), loader).ListChecks(context.Background(), "bot-1"); len(items) != 0 {
+			t.Fatalf("expected no checks, got %+v", items)
+		}
+	}
+}
@@ -106,6 +106,17 @@ func (s *Service) Stop(botID string) {
 	s.removeJob(botID)
 }
 
+// PauseBot stops the bot's heartbeat while it is pending deletion.
+func (s *Service) PauseBot(_ context.Context, botID string) error {
+	s.Stop(botID)
+	return nil
+}
+
+// ResumeBot reschedules the bot's heartbeat after it is undeleted.
+func (s *Service) ResumeBot(ctx context.Context, botID string) error {
+	return s.Reschedule(ctx, botID)
+}
+
 func (s *Service) runHeartbeat(ctx context.Context, cfg Config) {
 	if s.triggerer == nil {
 		s.logger.Error("heartbeat triggerer not configured")
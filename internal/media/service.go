@@ -11,6 +11,7 @@ import (
 	"os"
 	"path"
 	"strings"
+	"time"
 
 	"github.com/memohai/memoh/internal/storage"
 )
@@ -18,8 +19,11 @@ import (
 // Service provides content-addressed media asset persistence.
 // All metadata is derived from the filesystem — no database, no sidecar files.
 type Service struct {
-	provider storage.Provider
-	logger   *slog.Logger
+	provider   storage.Provider
+	logger     *slog.Logger
+	signingKey []byte
+	urlBase    string
+	now        func() time.Time
 }
 
 // NewService creates a media service with the given storage provider.
@@ -30,6 +34,7 @@ func NewService(log *slog.Logger, provider storage.Provider) *Service {
 	return &Service{
 		provider: provider,
 		logger:   log.With(slog.String("service", "media")),
+		now:      time.Now,
 	}
 }
 
@@ -60,7 +65,7 @@ func (s *Service) Ingest(ctx context.Context, input IngestInput) (Asset, error)
 	}()
 
 	mime := coalesce(input.Mime, "application/octet-stream")
-	ext := extensionFromMime(mime)
+	ext := ExtensionFromMime(mime)
 	if ext == ".bin" && input.OriginalExt != "" {
 		ext = input.OriginalExt
 	}
@@ -166,6 +171,32 @@ func (s *Service) IngestContainerFile(ctx context.Context, botID, containerPath
 	return s.Ingest(ctx, IngestInput{BotID: botID, Mime: mime, Reader: f, OriginalExt: ext})
 }
 
+// WriteContainerFile writes raw bytes to an arbitrary path in a bot's /data/
+// directory. The provider must implement ContainerFileWriter.
+func (s *Service) WriteContainerFile(ctx context.Context, botID, containerPath string, reader io.Reader) error {
+	if s.provider == nil {
+		return ErrProviderUnavailable
+	}
+	writer, ok := s.provider.(storage.ContainerFileWriter)
+	if !ok {
+		return storage.ErrContainerFileNotSupported
+	}
+	return writer.WriteContainerFile(ctx, botID, containerPath, reader)
+}
+
+// ScanIntegrity re-hashes a bot's stored assets and returns those whose
+// content no longer matches their content hash.
+func (s *Service) ScanIntegrity(ctx context.Context, botID string) ([]storage.IntegrityError, error) {
+	if s.provider == nil {
+		return nil, ErrProviderUnavailable
+	}
+	scanner, ok := s.provider.(storage.IntegrityScanner)
+	if !ok {
+		return nil, storage.ErrIntegrityScanNotSupported
+	}
+	return scanner.ScanIntegrity(ctx, botID)
+}
+
 // resolveByContentHash scans hash-prefix directory by extension to find the file.
 // It first tries known extensions (fast path), then falls back to a directory
 // listing if the provider supports it, so arbitrary file types are found.
@@ -279,7 +310,9 @@ func mimeFromExtension(ext string) string {
 	return "application/octet-stream"
 }
 
-func extensionFromMime(mime string) string {
+// ExtensionFromMime returns the file extension used for a MIME type when
+// storing assets, or ".bin" when the type is unknown.
+func ExtensionFromMime(mime string) string {
 	if ext, ok := mimeToExt[strings.ToLower(strings.TrimSpace(mime))]; ok {
 		return ext
 	}
@@ -0,0 +1,112 @@
+package media
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultSignedURLTTL is used when SignedURL is called without a TTL.
+	DefaultSignedURLTTL = 15 * time.Minute
+	// MaxSignedURLTTL caps how long a signed URL may stay valid.
+	MaxSignedURLTTL = 24 * time.Hour
+)
+
+var (
+	// ErrURLSigningDisabled indicates no URL signing key is configured.
+	ErrURLSigningDisabled = errors.New("media url signing not configured")
+	// ErrSignatureInvalid indicates a signed URL was tampered with or malformed.
+	ErrSignatureInvalid = errors.New("invalid media url signature")
+	// ErrSignatureExpired indicates a signed URL is past its expiry.
+	ErrSignatureExpired = errors.New("media url signature expired")
+)
+
+// SignedURL is a time-limited URL for one media asset.
+type SignedURL struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// SetURLSigningKey enables SignedURL. The key is derived from secret so it
+// differs from other uses of the same secret.
+func (s *Service) SetURLSigningKey(secret string) {
+	if strings.TrimSpace(secret) == "" {
+		s.signingKey = nil
+		return
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("memoh-media-url"))
+	s.signingKey = mac.Sum(nil)
+}
+
+// SetURLBasePath sets the path prefix the API is served under (e.g. "/api"),
+// so signed URLs point at the media endpoint as clients reach it.
+func (s *Service) SetURLBasePath(base string) {
+	s.urlBase = strings.TrimRight(strings.TrimSpace(base), "/")
+}
+
+// SignedURL returns a URL to the media endpoint that grants access to the
+// asset until ttl elapses, without other credentials. A non-positive ttl uses
+// DefaultSignedURLTTL; longer ttls are capped at MaxSignedURLTTL.
+func (s *Service) SignedURL(ctx context.Context, botID, contentHash string, ttl time.Duration) (SignedURL, error) {
+	if len(s.signingKey) == 0 {
+		return SignedURL{}, ErrURLSigningDisabled
+	}
+	if s.provider == nil {
+		return SignedURL{}, ErrProviderUnavailable
+	}
+	if _, err := s.resolveByContentHash(ctx, botID, contentHash); err != nil {
+		return SignedURL{}, err
+	}
+	if ttl <= 0 {
+		ttl = DefaultSignedURLTTL
+	}
+	ttl = min(ttl, MaxSignedURLTTL)
+	expiresAt := s.now().Add(ttl).Truncate(time.Second)
+	expires := strconv.FormatInt(expiresAt.Unix(), 10)
+
+	query := url.Values{}
+	query.Set("expires", expires)
+	query.Set("signature", s.sign(botID, contentHash, expires))
+	return SignedURL{
+		URL:       s.urlBase + "/bots/" + url.PathEscape(botID) + "/media/" + url.PathEscape(contentHash) + "?" + query.Encode(),
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// VerifySignature checks the expires and signature query values of a signed
+// media URL for the given asset.
+func (s *Service) VerifySignature(botID, contentHash, expires, signature string) error {
+	if len(s.signingKey) == 0 {
+		return ErrURLSigningDisabled
+	}
+	expiresUnix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return ErrSignatureInvalid
+	}
+	got, err := hex.DecodeString(signature)
+	if err != nil {
+		return ErrSignatureInvalid
+	}
+	want, _ := hex.DecodeString(s.sign(botID, contentHash, expires))
+	if !hmac.Equal(got, want) {
+		return ErrSignatureInvalid
+	}
+	if !s.now().Before(time.Unix(expiresUnix, 0)) {
+		return ErrSignatureExpired
+	}
+	return nil
+}
+
+func (s *Service) sign(botID, contentHash, expires string) string {
+	mac := hmac.New(sha256.New, s.signingKey)
+	mac.Write([]byte(botID + "\n" + contentHash + "\n" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,184 @@
+package media
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memProvider is an in-memory storage.Provider.
+type memProvider struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func (m *memProvider) Put(_ context.Context, key string, reader io.Reader) error {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.objects[key] = data
+	return nil
+}
+
+func (m *memProvider) Open(_ context.Context, key string) (io.ReadCloser, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	data, ok := m.objects[key]
+	if !ok {
+		return nil, os.ErrNotExist
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (m *memProvider) Delete(_ context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.objects, key)
+	return nil
+}
+
+func (*memProvider) AccessPath(key string) string {
+	return "/data/media/" + key
+}
+
+func newSignedURLTestService(t *testing.T) (*Service, Asset) {
+	t.Helper()
+	svc := NewService(slog.New(slog.DiscardHandler), &memProvider{objects: map[string][]byte{}})
+	svc.SetURLSigningKey("test-jwt-secret")
+	asset, err := svc.Ingest(context.Background(), IngestInput{BotID: "bot-1", Mime: "image/png", Reader: strings.NewReader("png")})
+	if err != nil {
+		t.Fatalf("Ingest failed: %v", err)
+	}
+	return svc, asset
+}
+
+func signedQuery(t *testing.T, signed SignedURL) url.Values {
+	t.Helper()
+	u, err := url.Parse(signed.URL)
+	if err != nil {
+		t.Fatalf("parse signed url: %v", err)
+	}
+	return u.Query()
+}
+
+func TestSignedURLVerifies(t *testing.T) {
+	t.Parallel()
+	svc, asset := newSignedURLTestService(t)
+
+	signed, err := svc.SignedURL(context.Background(), "bot-1", asset.ContentHash, time.Minute)
+	if err != nil {
+		t.Fatalf("SignedURL failed: %v", err)
+	}
+	if !strings.HasPrefix(signed.URL, "/bots/bot-1/media/"+asset.ContentHash+"?") {
+		t.Fatalf("unexpected url %q", signed.URL)
+	}
+	q := signedQuery(t, signed)
+	if err := svc.VerifySignature("bot-1", asset.ContentHash, q.Get("expires"), q.Get("signature")); err != nil {
+		t.Fatalf("expected valid signature, got %v", err)
+	}
+}
+
+func TestSignedURLUsesBasePath(t *testing.T) {
+	t.Parallel()
+	svc, asset := newSignedURLTestService(t)
+	svc.SetURLBasePath("/api/")
+
+	signed, err := svc.SignedURL(context.Background(), "bot-1", asset.ContentHash, time.Minute)
+	if err != nil {
+		t.Fatalf("SignedURL failed: %v", err)
+	}
+	if !strings.HasPrefix(signed.URL, "/api/bots/bot-1/media/"+asset.ContentHash+"?") {
+		t.Fatalf("unexpected url %q", signed.URL)
+	}
+}
+
+func TestSignedURLRejectsTampering(t *testing.T) {
+	t.Parallel()
+	svc, asset := newSignedURLTestService(t)
+	signed, err := svc.SignedURL(context.Background(), "bot-1", asset.ContentHash, time.Minute)
+	if err != nil {
+		t.Fatalf("SignedURL failed: %v", err)
+	}
+	q := signedQuery(t, signed)
+
+	cases := []struct {
+		name                                   string
+		botID, contentHash, expires, signature string
+	}{
+		{"other bot", "bot-2", asset.ContentHash, q.Get("expires"), q.Get("signature")},
+		{"other asset", "bot-1", strings.Repeat("0", 64), q.Get("expires"), q.Get("signature")},
+		{"extended expiry", "bot-1", asset.ContentHash, "99999999999", q.Get("signature")},
+		{"garbage signature", "bot-1", asset.ContentHash, q.Get("expires"), "not-hex"},
+		{"missing expiry", "bot-1", asset.ContentHash, "", q.Get("signature")},
+	}
+	for _, tc := range cases {
+		if err := svc.VerifySignature(tc.botID, tc.contentHash, tc.expires, tc.signature); !errors.Is(err, ErrSignatureInvalid) {
+			t.Errorf("%s: expected ErrSignatureInvalid, got %v", tc.name, err)
+		}
+	}
+
+	other := NewService(nil, svc.provider)
+	other.SetURLSigningKey("another-secret")
+	if err := other.VerifySignature("bot-1", asset.ContentHash, q.Get("expires"), q.Get("signature")); !errors.Is(err, ErrSignatureInvalid) {
+		t.Fatalf("expected signature from another key to be rejected, got %v", err)
+	}
+}
+
+func TestSignedURLExpires(t *testing.T) {
+	t.Parallel()
+	svc, asset := newSignedURLTestService(t)
+	issuedAt := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return issuedAt }
+
+	signed, err := svc.SignedURL(context.Background(), "bot-1", asset.ContentHash, time.Minute)
+	if err != nil {
+		t.Fatalf("SignedURL failed: %v", err)
+	}
+	if !signed.ExpiresAt.Equal(issuedAt.Add(time.Minute)) {
+		t.Fatalf("unexpected expiry %s", signed.ExpiresAt)
+	}
+	q := signedQuery(t, signed)
+
+	svc.now = func() time.Time { return issuedAt.Add(59 * time.Second) }
+	if err := svc.VerifySignature("bot-1", asset.ContentHash, q.Get("expires"), q.Get("signature")); err != nil {
+		t.Fatalf("expected signature to be valid before expiry, got %v", err)
+	}
+	svc.now = func() time.Time { return issuedAt.Add(time.Minute) }
+	if err := svc.VerifySignature("bot-1", asset.ContentHash, q.Get("expires"), q.Get("signature")); !errors.Is(err, ErrSignatureExpired) {
+		t.Fatalf("expected ErrSignatureExpired, got %v", err)
+	}
+}
+
+func TestSignedURLCapsTTLAndRequiresKey(t *testing.T) {
+	t.Parallel()
+	svc, asset := newSignedURLTestService(t)
+	issuedAt := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return issuedAt }
+
+	signed, err := svc.SignedURL(context.Background(), "bot-1", asset.ContentHash, 30*24*time.Hour)
+	if err != nil {
+		t.Fatalf("SignedURL failed: %v", err)
+	}
+	if !signed.ExpiresAt.Equal(issuedAt.Add(MaxSignedURLTTL)) {
+		t.Fatalf("expected ttl capped to %s, got expiry %s", MaxSignedURLTTL, signed.ExpiresAt)
+	}
+	if _, err := svc.SignedURL(context.Background(), "bot-1", strings.Repeat("0", 64), time.Minute); !errors.Is(err, ErrAssetNotFound) {
+		t.Fatalf("expected ErrAssetNotFound for unknown asset, got %v", err)
+	}
+
+	svc.SetURLSigningKey("")
+	if _, err := svc.SignedURL(context.Background(), "bot-1", asset.ContentHash, time.Minute); !errors.Is(err, ErrURLSigningDisabled) {
+		t.Fatalf("expected ErrURLSigningDisabled, got %v", err)
+	}
+}
@@ -3,9 +3,11 @@ package builtin
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log/slog"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/memohai/memoh/internal/conversation"
 	"github.com/memohai/memoh/internal/mcp"
@@ -22,6 +24,10 @@ const (
 	toolSearchMemory       = "search_memory"
 )
 
+// errMemoryRuntimeNotConfigured is returned by CRUD calls when the provider was
+// built without a runtime backend.
+var errMemoryRuntimeNotConfigured = fmt.Errorf("%w: memory runtime not configured", adapters.ErrStoreUnreachable)
+
 // BuiltinProvider wraps the existing Service as a Provider.
 type BuiltinProvider struct {
 	service      memoryRuntime
@@ -30,6 +36,14 @@ type BuiltinProvider struct {
 	adminChecker AdminChecker
 	logger       *slog.Logger
 	packer       contextPackerConfig
+	// recencyHalfLife decays search scores by memory age. Zero disables it.
+	recencyHalfLife time.Duration
+	// dedupeThreshold is the cosine similarity above which Add merges into an
+	// existing memory. Zero disables it.
+	dedupeThreshold float64
+	// maxMemories caps the memories kept per bot; the least important are
+	// evicted first. Zero means unlimited.
+	maxMemories int
 }
 
 // memoryRuntime is the runtime memory backend required by the builtin provider.
@@ -50,6 +64,12 @@ type memoryRuntime interface {
 	Rebuild(ctx context.Context, botID string) (adapters.RebuildResult, error)
 }
 
+// indexRebuilder is implemented by runtimes that maintain a derived vector
+// index which can be rebuilt from scratch.
+type indexRebuilder interface {
+	Reindex(ctx context.Context, botID string) (adapters.RebuildResult, error)
+}
+
 // AdminChecker checks whether a channel identity has admin privileges.
 type AdminChecker interface {
 	IsAdmin(ctx context.Context, channelIdentityID string) (bool, error)
@@ -98,13 +118,51 @@ func (p *BuiltinProvider) SetPackerConfig(cfg contextPackerConfig) {
 	}
 }
 
-// ApplyProviderConfig reads context packing knobs from a provider config map
-// and applies any non-zero values to the provider's packer configuration.
+// SetRecencyHalfLife enables recency weighting in search: a memory's score is
+// halved for every halfLife of age. A non-positive value disables weighting.
+func (p *BuiltinProvider) SetRecencyHalfLife(halfLife time.Duration) {
+	if halfLife < 0 {
+		halfLife = 0
+	}
+	p.recencyHalfLife = halfLife
+}
+
+// SetDedupeThreshold enables merging of near-duplicate memories on Add when
+// the best match reaches the given cosine similarity. Values outside (0, 1]
+// disable merging.
+func (p *BuiltinProvider) SetDedupeThreshold(threshold float64) {
+	if threshold <= 0 || threshold > 1 {
+		threshold = 0
+	}
+	p.dedupeThreshold = threshold
+}
+
+// SetMaxMemories sets the per-bot memory cap enforced after writes. Once it is
+// exceeded, the lowest-importance memories are evicted. Zero disables the cap.
+func (p *BuiltinProvider) SetMaxMemories(limit int) {
+	if limit < 0 {
+		limit = 0
+	}
+	p.maxMemories = limit
+}
+
+// ApplyProviderConfig reads context packing, recency, dedupe and retention
+// knobs from a provider config map and applies any non-zero values to the
+// provider.
 func (p *BuiltinProvider) ApplyProviderConfig(providerConfig map[string]any) {
 	p.SetPackerConfig(contextPackerConfig{
 		TargetItems:   intFromConfig(providerConfig, "context_target_items"),
 		MaxTotalChars: intFromConfig(providerConfig, "context_max_total_chars"),
 	})
+	if days := intFromConfig(providerConfig, "recency_half_life_days"); days > 0 {
+		p.SetRecencyHalfLife(time.Duration(days) * 24 * time.Hour)
+	}
+	if threshold := floatFromConfig(providerConfig, "dedupe_threshold"); threshold > 0 {
+		p.SetDedupeThreshold(threshold)
+	}
+	if limit := intFromConfig(providerConfig, "max_memories"); limit > 0 {
+		p.SetMaxMemories(limit)
+	}
 }
 
 func intFromConfig(m map[string]any, key string) int {
@@ -126,6 +184,27 @@ func intFromConfig(m map[string]any, key string) int {
 	return 0
 }
 
+func floatFromConfig(m map[string]any, key string) float64 {
+	if m == nil {
+		return 0
+	}
+	v, ok := m[key]
+	if !ok || v == nil {
+		return 0
+	}
+	switch n := v.(type) {
+	case float64:
+		return n
+	case float32:
+		return float64(n)
+	case int:
+		return float64(n)
+	case int64:
+		return float64(n)
+	}
+	return 0
+}
+
 func (*BuiltinProvider) Type() string { return BuiltinType }
 
 func memorySourceLabel(item adapters.MemoryItem) string {
@@ -161,7 +240,7 @@ func (p *BuiltinProvider) OnBeforeChat(ctx context.Context, req adapters.BeforeC
 	}
 
 	fetchLimit := overfetchLimit(p.packer)
-	resp, err := p.service.Search(ctx, adapters.SearchRequest{
+	resp, err := p.search(ctx, adapters.SearchRequest{
 		Query: req.Query,
 		BotID: req.BotID,
 		Limit: fetchLimit,
@@ -220,7 +299,7 @@ func (p *BuiltinProvider) OnAfterChat(ctx context.Context, req adapters.AfterCha
 	}
 
 	if p.llm != nil {
-		result := runFormation(ctx, p.logger, p.llm, p.service, req)
+		result := runFormation(ctx, p.logger, p.llm, p.service, p.dedupeThreshold, req)
 		p.logger.Debug("memory formation completed",
 			slog.String("bot_id", botID),
 			slog.Int("extracted", result.ExtractedFacts),
@@ -229,6 +308,7 @@ func (p *BuiltinProvider) OnAfterChat(ctx context.Context, req adapters.AfterCha
 			slog.Int("deleted", result.Deleted),
 			slog.Int("skipped", result.Skipped),
 		)
+		p.applyRetention(ctx, botID)
 		return nil
 	}
 
@@ -239,7 +319,7 @@ func (p *BuiltinProvider) OnAfterChat(ctx context.Context, req adapters.AfterCha
 		"bot_id":    botID,
 	}
 	metadata := adapters.BuildProfileMetadata(req.UserID, req.ChannelIdentityID, req.DisplayName)
-	if _, err := p.service.Add(ctx, adapters.AddRequest{
+	if _, _, err := addOrMerge(ctx, p.service, p.dedupeThreshold, adapters.AddRequest{
 		Messages: req.Messages,
 		BotID:    botID,
 		Metadata: metadata,
@@ -247,9 +327,26 @@ func (p *BuiltinProvider) OnAfterChat(ctx context.Context, req adapters.AfterCha
 	}); err != nil {
 		p.logger.Warn("store memory failed", slog.String("bot_id", botID), slog.Any("error", err))
 	}
+	p.applyRetention(ctx, botID)
 	return nil
 }
 
+// applyRetention enforces the per-bot memory cap, logging instead of failing
+// so that a retention problem never breaks the chat that triggered it.
+func (p *BuiltinProvider) applyRetention(ctx context.Context, botID string) {
+	if p.maxMemories <= 0 {
+		return
+	}
+	evicted, err := enforceRetention(ctx, p.service, botID, p.maxMemories)
+	if err != nil {
+		p.logger.Warn("memory retention failed", slog.String("bot_id", botID), slog.Any("error", err))
+		return
+	}
+	if evicted > 0 {
+		p.logger.Debug("memory retention evicted memories", slog.String("bot_id", botID), slog.Int("evicted", evicted))
+	}
+}
+
 // --- MCP Tools ---
 
 func (p *BuiltinProvider) ListTools(_ context.Context, _ mcp.ToolSessionContext) ([]mcp.ToolDescriptor, error) {
@@ -335,7 +432,7 @@ func (p *BuiltinProvider) CallTool(ctx context.Context, session mcp.ToolSessionC
 		}
 	}
 
-	resp, err := p.service.Search(ctx, adapters.SearchRequest{
+	resp, err := p.search(ctx, adapters.SearchRequest{
 		Query: query,
 		BotID: botID,
 		Limit: limit,
@@ -394,77 +491,106 @@ func (p *BuiltinProvider) canAccessChat(ctx context.Context, chatID, channelIden
 
 func (p *BuiltinProvider) Add(ctx context.Context, req adapters.AddRequest) (adapters.SearchResponse, error) {
 	if p.service == nil {
-		return adapters.SearchResponse{}, errors.New("memory runtime not configured")
+		return adapters.SearchResponse{}, errMemoryRuntimeNotConfigured
 	}
-	return p.service.Add(ctx, req)
+	resp, merged, err := addOrMerge(ctx, p.service, p.dedupeThreshold, req)
+	if err != nil || merged {
+		return resp, err
+	}
+	if botID, err := runtimeBotID(req.BotID, req.Filters); err == nil {
+		p.applyRetention(ctx, botID)
+	}
+	return resp, nil
 }
 
 func (p *BuiltinProvider) Search(ctx context.Context, req adapters.SearchRequest) (adapters.SearchResponse, error) {
 	if p.service == nil {
-		return adapters.SearchResponse{}, errors.New("memory runtime not configured")
+		return adapters.SearchResponse{}, errMemoryRuntimeNotConfigured
+	}
+	return p.search(ctx, req)
+}
+
+// search queries the runtime and applies recency weighting when enabled.
+func (p *BuiltinProvider) search(ctx context.Context, req adapters.SearchRequest) (adapters.SearchResponse, error) {
+	resp, err := p.service.Search(ctx, req)
+	if err != nil {
+		return resp, err
 	}
-	return p.service.Search(ctx, req)
+	resp.Results = applyRecencyDecay(resp.Results, p.recencyHalfLife, time.Now())
+	return resp, nil
 }
 
 func (p *BuiltinProvider) GetAll(ctx context.Context, req adapters.GetAllRequest) (adapters.SearchResponse, error) {
 	if p.service == nil {
-		return adapters.SearchResponse{}, errors.New("memory runtime not configured")
+		return adapters.SearchResponse{}, errMemoryRuntimeNotConfigured
 	}
 	return p.service.GetAll(ctx, req)
 }
 
 func (p *BuiltinProvider) Update(ctx context.Context, req adapters.UpdateRequest) (adapters.MemoryItem, error) {
 	if p.service == nil {
-		return adapters.MemoryItem{}, errors.New("memory runtime not configured")
+		return adapters.MemoryItem{}, errMemoryRuntimeNotConfigured
 	}
 	return p.service.Update(ctx, req)
 }
 
 func (p *BuiltinProvider) Delete(ctx context.Context, memoryID string) (adapters.DeleteResponse, error) {
 	if p.service == nil {
-		return adapters.DeleteResponse{}, errors.New("memory runtime not configured")
+		return adapters.DeleteResponse{}, errMemoryRuntimeNotConfigured
 	}
 	return p.service.Delete(ctx, memoryID)
 }
 
 func (p *BuiltinProvider) DeleteBatch(ctx context.Context, memoryIDs []string) (adapters.DeleteResponse, error) {
 	if p.service == nil {
-		return adapters.DeleteResponse{}, errors.New("memory runtime not configured")
+		return adapters.DeleteResponse{}, errMemoryRuntimeNotConfigured
 	}
 	return p.service.DeleteBatch(ctx, memoryIDs)
 }
 
 func (p *BuiltinProvider) DeleteAll(ctx context.Context, req adapters.DeleteAllRequest) (adapters.DeleteResponse, error) {
 	if p.service == nil {
-		return adapters.DeleteResponse{}, errors.New("memory runtime not configured")
+		return adapters.DeleteResponse{}, errMemoryRuntimeNotConfigured
 	}
 	return p.service.DeleteAll(ctx, req)
 }
 
 func (p *BuiltinProvider) Compact(ctx context.Context, filters map[string]any, ratio float64, decayDays int) (adapters.CompactResult, error) {
 	if p.service == nil {
-		return adapters.CompactResult{}, errors.New("memory runtime not configured")
+		return adapters.CompactResult{}, errMemoryRuntimeNotConfigured
 	}
 	return p.service.Compact(ctx, filters, ratio, decayDays)
 }
 
 func (p *BuiltinProvider) Usage(ctx context.Context, filters map[string]any) (adapters.UsageResponse, error) {
 	if p.service == nil {
-		return adapters.UsageResponse{}, errors.New("memory runtime not configured")
+		return adapters.UsageResponse{}, errMemoryRuntimeNotConfigured
 	}
 	return p.service.Usage(ctx, filters)
 }
 
 func (p *BuiltinProvider) Status(ctx context.Context, botID string) (adapters.MemoryStatusResponse, error) {
 	if p.service == nil {
-		return adapters.MemoryStatusResponse{}, errors.New("memory runtime not configured")
+		return adapters.MemoryStatusResponse{}, errMemoryRuntimeNotConfigured
 	}
 	return p.service.Status(ctx, botID)
 }
 
 func (p *BuiltinProvider) Rebuild(ctx context.Context, botID string) (adapters.RebuildResult, error) {
 	if p.service == nil {
-		return adapters.RebuildResult{}, errors.New("memory runtime not configured")
+		return adapters.RebuildResult{}, errMemoryRuntimeNotConfigured
+	}
+	return p.service.Rebuild(ctx, botID)
+}
+
+// Reindex rebuilds the bot's derived index from scratch. Runtimes without a
+// vector index (file mode) fall back to a plain source rebuild.
+func (p *BuiltinProvider) Reindex(ctx context.Context, botID string) (adapters.RebuildResult, error) {
+	if p.service == nil {
+		return adapters.RebuildResult{}, errMemoryRuntimeNotConfigured
+	}
+	if rebuilder, ok := p.service.(indexRebuilder); ok {
+		return rebuilder.Reindex(ctx, botID)
 	}
 	return p.service.Rebuild(ctx, botID)
 }
@@ -0,0 +1,82 @@
+package builtin
+
+import (
+	"context"
+	"strings"
+
+	adapters "github.com/memohai/memoh/internal/memory/adapters"
+)
+
+// findNearDuplicate searches the runtime for the existing memory most similar
+// to text and returns it when its cosine similarity reaches threshold. Only the
+// dense runtime reports cosine scores, so other runtimes never match.
+func findNearDuplicate(ctx context.Context, runtime memoryRuntime, botID, text string, threshold float64) (adapters.MemoryItem, bool, error) {
+	if threshold <= 0 || runtime.Mode() != string(ModeDense) {
+		return adapters.MemoryItem{}, false, nil
+	}
+	resp, err := runtime.Search(ctx, adapters.SearchRequest{
+		Query: text,
+		BotID: botID,
+		Limit: 1,
+		Filters: map[string]any{
+			"scopeId": botID,
+			"bot_id":  botID,
+		},
+		NoStats: true,
+	})
+	if err != nil {
+		return adapters.MemoryItem{}, false, err
+	}
+	for _, item := range resp.Results {
+		if strings.TrimSpace(item.ID) != "" && item.Score >= threshold {
+			return item, true, nil
+		}
+	}
+	return adapters.MemoryItem{}, false, nil
+}
+
+// mergeNearDuplicate rewrites a near-identical existing memory with the new
+// text instead of storing a second copy. The new entry's metadata is merged
+// into the existing memory's, so neither side's keys are lost. It reports
+// false when no existing memory is similar enough and the caller should add
+// a new one.
+func mergeNearDuplicate(ctx context.Context, runtime memoryRuntime, threshold float64, req adapters.AddRequest) (adapters.MemoryItem, bool, error) {
+	if threshold <= 0 {
+		return adapters.MemoryItem{}, false, nil
+	}
+	botID, err := runtimeBotID(req.BotID, req.Filters)
+	if err != nil {
+		return adapters.MemoryItem{}, false, err
+	}
+	text := runtimeText(req.Message, req.Messages)
+	if text == "" {
+		return adapters.MemoryItem{}, false, nil
+	}
+	existing, ok, err := findNearDuplicate(ctx, runtime, botID, text, threshold)
+	if err != nil || !ok {
+		return adapters.MemoryItem{}, false, err
+	}
+	item, err := runtime.Update(ctx, adapters.UpdateRequest{
+		MemoryID: existing.ID,
+		Memory:   text,
+		Metadata: req.Metadata,
+	})
+	if err != nil {
+		return adapters.MemoryItem{}, false, err
+	}
+	return item, true, nil
+}
+
+// addOrMerge stores req as a new memory unless mergeNearDuplicate folds it
+// into an existing one, in which case merged is true.
+func addOrMerge(ctx context.Context, runtime memoryRuntime, threshold float64, req adapters.AddRequest) (resp adapters.SearchResponse, merged bool, err error) {
+	item, ok, err := mergeNearDuplicate(ctx, runtime, threshold, req)
+	if err != nil {
+		return adapters.SearchResponse{}, false, err
+	}
+	if ok {
+		return adapters.SearchResponse{Results: []adapters.MemoryItem{item}}, true, nil
+	}
+	resp, err = runtime.Add(ctx, req)
+	return resp, false, err
+}
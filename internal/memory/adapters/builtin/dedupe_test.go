@@ -0,0 +1,235 @@
+package builtin
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"sort"
+	"strings"
+	"testing"
+
+	adapters "github.com/memohai/memoh/internal/memory/adapters"
+)
+
+// fakeDenseRuntime scores memories by bag-of-words cosine similarity so that
+// paraphrases land close together the way real embeddings do.
+type fakeDenseRuntime struct {
+	memoryRuntime
+	items map[string]adapters.MemoryItem
+	next  int
+}
+
+func newFakeDenseRuntime() *fakeDenseRuntime {
+	return &fakeDenseRuntime{items: map[string]adapters.MemoryItem{}}
+}
+
+func (*fakeDenseRuntime) Mode() string { return string(ModeDense) }
+
+func (r *fakeDenseRuntime) Add(_ context.Context, req adapters.AddRequest) (adapters.SearchResponse, error) {
+	r.next++
+	item := adapters.MemoryItem{
+		ID:       fmt.Sprintf("%s:mem_%d", req.BotID, r.next),
+		Memory:   runtimeText(req.Message, req.Messages),
+		BotID:    req.BotID,
+		Metadata: req.Metadata,
+	}
+	r.items[item.ID] = item
+	return adapters.SearchResponse{Results: []adapters.MemoryItem{item}}, nil
+}
+
+func (r *fakeDenseRuntime) Update(_ context.Context, req adapters.UpdateRequest) (adapters.MemoryItem, error) {
+	item, ok := r.items[req.MemoryID]
+	if !ok {
+		return adapters.MemoryItem{}, adapters.ErrMemoryNotFound
+	}
+	item.Memory = req.Memory
+	item.Metadata = adapters.MergeMetadata(item.Metadata, req.Metadata)
+	r.items[item.ID] = item
+	return item, nil
+}
+
+func (r *fakeDenseRuntime) Search(_ context.Context, req adapters.SearchRequest) (adapters.SearchResponse, error) {
+	results := make([]adapters.MemoryItem, 0, len(r.items))
+	for _, item := range r.items {
+		if item.BotID != req.BotID {
+			continue
+		}
+		item.Score = bagOfWordsCosine(req.Query, item.Memory)
+		results = append(results, item)
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].Score > results[j].Score })
+	if req.Limit > 0 && len(results) > req.Limit {
+		results = results[:req.Limit]
+	}
+	return adapters.SearchResponse{Results: results}, nil
+}
+
+func (r *fakeDenseRuntime) GetAll(_ context.Context, req adapters.GetAllRequest) (adapters.SearchResponse, error) {
+	results := make([]adapters.MemoryItem, 0, len(r.items))
+	for _, item := range r.items {
+		if item.BotID == req.BotID {
+			results = append(results, item)
+		}
+	}
+	return adapters.SearchResponse{Results: results}, nil
+}
+
+func bagOfWordsCosine(a, b string) float64 {
+	count := func(s string) map[string]float64 {
+		out := map[string]float64{}
+		for _, w := range strings.Fields(strings.ToLower(s)) {
+			out[w]++
+		}
+		return out
+	}
+	va, vb := count(a), count(b)
+	var dot, na, nb float64
+	for w, x := range va {
+		dot += x * vb[w]
+		na += x * x
+	}
+	for _, y := range vb {
+		nb += y * y
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(na) * math.Sqrt(nb))
+}
+
+func TestBuiltinProviderAddMergesParaphrases(t *testing.T) {
+	t.Parallel()
+	runtime := newFakeDenseRuntime()
+	p := NewBuiltinProvider(slog.Default(), runtime, nil, nil)
+	p.ApplyProviderConfig(map[string]any{"dedupe_threshold": 0.7})
+	ctx := context.Background()
+
+	first, err := p.Add(ctx, adapters.AddRequest{BotID: "bot-1", Message: "User prefers oolong tea in the morning"})
+	if err != nil {
+		t.Fatalf("first add: %v", err)
+	}
+	second, err := p.Add(ctx, adapters.AddRequest{BotID: "bot-1", Message: "User prefers oolong tea every morning"})
+	if err != nil {
+		t.Fatalf("second add: %v", err)
+	}
+
+	if len(runtime.items) != 1 {
+		t.Fatalf("expected a single merged memory, got %d", len(runtime.items))
+	}
+	if second.Results[0].ID != first.Results[0].ID {
+		t.Fatalf("expected merge into %s, got %s", first.Results[0].ID, second.Results[0].ID)
+	}
+	if got := runtime.items[first.Results[0].ID].Memory; got != "User prefers oolong tea every morning" {
+		t.Fatalf("expected merged memory to carry the latest text, got %q", got)
+	}
+
+	if _, err := p.Add(ctx, adapters.AddRequest{BotID: "bot-1", Message: "User lives in Berlin"}); err != nil {
+		t.Fatalf("unrelated add: %v", err)
+	}
+	if len(runtime.items) != 2 {
+		t.Fatalf("expected unrelated fact to be stored separately, got %d items", len(runtime.items))
+	}
+}
+
+func TestBuiltinProviderAddMergeKeepsBothMetadata(t *testing.T) {
+	t.Parallel()
+	runtime := newFakeDenseRuntime()
+	p := NewBuiltinProvider(slog.Default(), runtime, nil, nil)
+	p.ApplyProviderConfig(map[string]any{"dedupe_threshold": 0.7})
+	ctx := context.Background()
+
+	first, err := p.Add(ctx, adapters.AddRequest{
+		BotID:    "bot-1",
+		Message:  "User prefers oolong tea in the morning",
+		Metadata: map[string]any{"source": "chat", "topic": "drinks"},
+	})
+	if err != nil {
+		t.Fatalf("first add: %v", err)
+	}
+	if _, err := p.Add(ctx, adapters.AddRequest{
+		BotID:    "bot-1",
+		Message:  "User prefers oolong tea every morning",
+		Metadata: map[string]any{"source": "schedule", "channel_identity_id": "ci-1"},
+	}); err != nil {
+		t.Fatalf("second add: %v", err)
+	}
+
+	got := runtime.items[first.Results[0].ID].Metadata
+	want := map[string]any{"source": "schedule", "topic": "drinks", "channel_identity_id": "ci-1"}
+	if len(got) != len(want) {
+		t.Fatalf("merged metadata = %v, want %v", got, want)
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Fatalf("merged metadata = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestBuiltinProviderOnAfterChatMergesParaphrasedFacts(t *testing.T) {
+	t.Parallel()
+	runtime := newFakeDenseRuntime()
+	p := NewBuiltinProvider(slog.Default(), runtime, nil, nil)
+	p.ApplyProviderConfig(map[string]any{"dedupe_threshold": 0.7})
+	llm := &fakeLLM{}
+	p.SetLLM(llm)
+	ctx := context.Background()
+
+	for _, fact := range []string{"User prefers oolong tea in the morning", "User prefers oolong tea every morning"} {
+		llm.extractFacts = []string{fact}
+		llm.decideActions = []adapters.DecisionAction{{Event: "ADD", Text: fact}}
+		if err := p.OnAfterChat(ctx, adapters.AfterChatRequest{
+			BotID:    "bot-1",
+			Messages: []adapters.Message{{Role: "user", Content: fact}},
+		}); err != nil {
+			t.Fatalf("after chat: %v", err)
+		}
+	}
+
+	if len(runtime.items) != 1 {
+		t.Fatalf("expected a single merged memory, got %d", len(runtime.items))
+	}
+	for _, item := range runtime.items {
+		if item.Memory != "User prefers oolong tea every morning" {
+			t.Fatalf("expected merged memory to carry the latest text, got %q", item.Memory)
+		}
+	}
+}
+
+func TestBuiltinProviderOnAfterChatWithoutLLMMergesParaphrases(t *testing.T) {
+	t.Parallel()
+	runtime := newFakeDenseRuntime()
+	p := NewBuiltinProvider(slog.Default(), runtime, nil, nil)
+	p.ApplyProviderConfig(map[string]any{"dedupe_threshold": 0.7})
+	ctx := context.Background()
+
+	for _, msg := range []string{"I prefer oolong tea in the morning", "I prefer oolong tea every morning"} {
+		if err := p.OnAfterChat(ctx, adapters.AfterChatRequest{
+			BotID:    "bot-1",
+			Messages: []adapters.Message{{Role: "user", Content: msg}},
+		}); err != nil {
+			t.Fatalf("after chat: %v", err)
+		}
+	}
+
+	if len(runtime.items) != 1 {
+		t.Fatalf("expected a single merged memory, got %d", len(runtime.items))
+	}
+}
+
+func TestBuiltinProviderAddDedupeDisabledByDefault(t *testing.T) {
+	t.Parallel()
+	runtime := newFakeDenseRuntime()
+	p := NewBuiltinProvider(slog.Default(), runtime, nil, nil)
+	ctx := context.Background()
+
+	for _, msg := range []string{"User prefers oolong tea", "User prefers oolong tea"} {
+		if _, err := p.Add(ctx, adapters.AddRequest{BotID: "bot-1", Message: msg}); err != nil {
+			t.Fatalf("add: %v", err)
+		}
+	}
+	if len(runtime.items) != 2 {
+		t.Fatalf("expected 2 memories with dedupe disabled, got %d", len(runtime.items))
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
 	"path"
 	"sort"
 	"strings"
@@ -38,6 +39,7 @@ type denseModelSpec struct {
 	clientType string
 	baseURL    string
 	apiKey     string
+	headers    map[string]string
 	dimensions int
 }
 
@@ -75,7 +77,8 @@ func newDenseRuntime(providerConfig map[string]any, queries *dbsqlc.Queries, cfg
 		return nil, fmt.Errorf("dense runtime: %w", err)
 	}
 
-	embedModel := models.NewSDKEmbeddingModel(spec.clientType, spec.baseURL, spec.apiKey, spec.modelID, denseEmbedTimeout, nil)
+	httpClient := models.WithHeaders(&http.Client{Timeout: denseEmbedTimeout}, spec.headers)
+	embedModel := models.NewSDKEmbeddingModel(spec.clientType, spec.baseURL, spec.apiKey, spec.modelID, denseEmbedTimeout, httpClient)
 
 	return &denseRuntime{
 		qdrant:     qClient,
@@ -92,7 +95,10 @@ func (r *denseRuntime) embedQuery(ctx context.Context, text string) ([]float32,
 	client := sdk.NewClient()
 	vec, err := client.Embed(ctx, text, sdk.WithEmbeddingModel(r.embedModel))
 	if err != nil {
-		return nil, fmt.Errorf("dense embed query: %w", err)
+		return nil, fmt.Errorf("dense embed query: %w: %w", adapters.ErrEmbedderUnavailable, err)
+	}
+	if r.dimensions > 0 && len(vec) != r.dimensions {
+		return nil, fmt.Errorf("dense embed query: %w: expected %d, got %d", adapters.ErrDimensionMismatch, r.dimensions, len(vec))
 	}
 	return float64sToFloat32s(vec), nil
 }
@@ -101,10 +107,13 @@ func (r *denseRuntime) embedDocuments(ctx context.Context, texts []string) ([][]
 	client := sdk.NewClient()
 	result, err := client.EmbedMany(ctx, texts, sdk.WithEmbeddingModel(r.embedModel))
 	if err != nil {
-		return nil, fmt.Errorf("dense embed documents: %w", err)
+		return nil, fmt.Errorf("dense embed documents: %w: %w", adapters.ErrEmbedderUnavailable, err)
 	}
 	out := make([][]float32, len(result.Embeddings))
 	for i, emb := range result.Embeddings {
+		if r.dimensions > 0 && len(emb) != r.dimensions {
+			return nil, fmt.Errorf("dense embed documents: %w: expected %d, got %d", adapters.ErrDimensionMismatch, r.dimensions, len(emb))
+		}
 		out[i] = float64sToFloat32s(emb)
 	}
 	return out, nil
@@ -165,7 +174,7 @@ func (r *denseRuntime) Search(ctx context.Context, req adapters.SearchRequest) (
 		return adapters.SearchResponse{}, err
 	}
 	if err := r.qdrant.EnsureDenseCollection(ctx, r.dimensions); err != nil {
-		return adapters.SearchResponse{}, err
+		return adapters.SearchResponse{}, fmt.Errorf("%w: %w", adapters.ErrStoreUnreachable, err)
 	}
 	limit := req.Limit
 	if limit <= 0 {
@@ -177,7 +186,7 @@ func (r *denseRuntime) Search(ctx context.Context, req adapters.SearchRequest) (
 	}
 	results, err := r.qdrant.SearchDense(ctx, qdrantclient.DenseVector{Values: vec}, botID, limit)
 	if err != nil {
-		return adapters.SearchResponse{}, err
+		return adapters.SearchResponse{}, fmt.Errorf("%w: %w", adapters.ErrStoreUnreachable, err)
 	}
 	items := make([]adapters.MemoryItem, 0, len(results))
 	for _, result := range results {
@@ -234,11 +243,12 @@ func (r *denseRuntime) Update(ctx context.Context, req adapters.UpdateRequest) (
 		}
 	}
 	if existing == nil {
-		return adapters.MemoryItem{}, errors.New("dense runtime: memory not found")
+		return adapters.MemoryItem{}, fmt.Errorf("dense runtime: %w", adapters.ErrMemoryNotFound)
 	}
 	existing.Memory = text
 	existing.Hash = runtimeHash(text)
 	existing.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+	existing.Metadata = withImportance(adapters.MergeMetadata(existing.Metadata, req.Metadata), req.Importance)
 	if err := r.store.PersistMemories(ctx, botID, []storefs.MemoryItem{*existing}, nil); err != nil {
 		return adapters.MemoryItem{}, err
 	}
@@ -415,6 +425,33 @@ func (r *denseRuntime) Rebuild(ctx context.Context, botID string) (adapters.Rebu
 	return r.syncSourceItems(ctx, botID, items)
 }
 
+// Reindex drops every indexed point for the bot and re-embeds all source
+// memories with the currently configured embedding model.
+func (r *denseRuntime) Reindex(ctx context.Context, botID string) (adapters.RebuildResult, error) {
+	items, err := r.store.ReadAllMemoryFiles(ctx, botID)
+	if err != nil {
+		return adapters.RebuildResult{}, err
+	}
+	if err := r.qdrant.EnsureDenseCollection(ctx, r.dimensions); err != nil {
+		return adapters.RebuildResult{}, fmt.Errorf("%w: %w", adapters.ErrStoreUnreachable, err)
+	}
+	if err := r.qdrant.DeleteByBotID(ctx, botID); err != nil {
+		return adapters.RebuildResult{}, err
+	}
+	if err := r.upsertSourceItems(ctx, botID, items); err != nil {
+		return adapters.RebuildResult{}, err
+	}
+	count, err := r.qdrant.Count(ctx, botID)
+	if err != nil {
+		return adapters.RebuildResult{}, err
+	}
+	return adapters.RebuildResult{
+		FsCount:       len(items),
+		StorageCount:  count,
+		RestoredCount: count,
+	}, nil
+}
+
 func (r *denseRuntime) syncSourceItems(ctx context.Context, botID string, items []storefs.MemoryItem) (adapters.RebuildResult, error) {
 	if err := r.qdrant.EnsureDenseCollection(ctx, r.dimensions); err != nil {
 		return adapters.RebuildResult{}, err
@@ -575,6 +612,7 @@ func resolveDenseEmbeddingModel(ctx context.Context, queries *dbsqlc.Queries, mo
 		clientType: strings.TrimSpace(provider.ClientType),
 		baseURL:    strings.TrimSpace(baseURL),
 		apiKey:     strings.TrimSpace(apiKey),
+		headers:    models.ConfigHeaders(providerCfg),
 		dimensions: *cfg.Dimensions,
 	}, nil
 }
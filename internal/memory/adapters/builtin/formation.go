@@ -30,8 +30,10 @@ type formationResult struct {
 	Skipped        int
 }
 
-// runFormation executes the Extract -> candidate retrieval -> Decide -> apply pipeline.
-func runFormation(ctx context.Context, logger *slog.Logger, llm adapters.LLM, runtime memoryRuntime, req adapters.AfterChatRequest) formationResult {
+// runFormation executes the Extract -> candidate retrieval -> Decide -> apply
+// pipeline. ADD actions within dedupeThreshold of an existing memory are
+// merged into it; a zero threshold disables the check.
+func runFormation(ctx context.Context, logger *slog.Logger, llm adapters.LLM, runtime memoryRuntime, dedupeThreshold float64, req adapters.AfterChatRequest) formationResult {
 	ctx, cancel := context.WithTimeout(ctx, formationTimeout)
 	defer cancel()
 
@@ -72,7 +74,7 @@ func runFormation(ctx context.Context, logger *slog.Logger, llm adapters.LLM, ru
 	}
 	metadata := adapters.BuildProfileMetadata(req.UserID, req.ChannelIdentityID, req.DisplayName)
 
-	applyActions(ctx, logger, runtime, botID, decided.Actions, filters, metadata, &result)
+	applyActions(ctx, logger, runtime, dedupeThreshold, botID, decided.Actions, filters, metadata, &result)
 	return result
 }
 
@@ -156,8 +158,9 @@ func gatherCandidates(ctx context.Context, logger *slog.Logger, runtime memoryRu
 	return candidates
 }
 
-// applyActions executes the decided CRUD actions against the runtime.
-func applyActions(ctx context.Context, logger *slog.Logger, runtime memoryRuntime, botID string, actions []adapters.DecisionAction, filters map[string]any, metadata map[string]any, result *formationResult) {
+// applyActions executes the decided CRUD actions against the runtime. An ADD
+// merged into a near-duplicate counts as an update of that memory.
+func applyActions(ctx context.Context, logger *slog.Logger, runtime memoryRuntime, dedupeThreshold float64, botID string, actions []adapters.DecisionAction, filters map[string]any, metadata map[string]any, result *formationResult) {
 	deleted := make(map[string]struct{})
 	updated := make(map[string]struct{})
 
@@ -171,14 +174,21 @@ func applyActions(ctx context.Context, logger *slog.Logger, runtime memoryRuntim
 				result.Skipped++
 				continue
 			}
-			if _, err := runtime.Add(ctx, adapters.AddRequest{
+			resp, merged, err := addOrMerge(ctx, runtime, dedupeThreshold, adapters.AddRequest{
 				Message:  text,
 				BotID:    botID,
-				Metadata: metadata,
+				Metadata: withImportance(metadata, action.Importance),
 				Filters:  filters,
-			}); err != nil {
+			})
+			switch {
+			case err != nil:
 				logger.Warn("memory formation: ADD failed", slog.String("bot_id", botID), slog.Any("error", err))
-			} else {
+			case merged:
+				for _, item := range resp.Results {
+					updated[item.ID] = struct{}{}
+				}
+				result.Updated++
+			default:
 				result.Added++
 			}
 
@@ -195,8 +205,9 @@ func applyActions(ctx context.Context, logger *slog.Logger, runtime memoryRuntim
 				continue
 			}
 			if _, err := runtime.Update(ctx, adapters.UpdateRequest{
-				MemoryID: id,
-				Memory:   text,
+				MemoryID:   id,
+				Memory:     text,
+				Importance: action.Importance,
 			}); err != nil {
 				logger.Warn("memory formation: UPDATE failed", slog.String("bot_id", botID), slog.String("memory_id", id), slog.Any("error", err))
 			} else {
@@ -51,7 +51,7 @@ func TestFormationExtractAndAdd(t *testing.T) {
 		},
 	}
 
-	result := runFormation(context.Background(), slog.Default(), llm, runtime, adapters.AfterChatRequest{
+	result := runFormation(context.Background(), slog.Default(), llm, runtime, 0, adapters.AfterChatRequest{
 		BotID: "bot-1",
 		Messages: []adapters.Message{
 			{Role: "user", Content: "I like oolong tea and I live in Berlin"},
@@ -100,7 +100,7 @@ func TestFormationUpdate(t *testing.T) {
 		},
 	}
 
-	result := runFormation(context.Background(), slog.Default(), llm, runtime, adapters.AfterChatRequest{
+	result := runFormation(context.Background(), slog.Default(), llm, runtime, 0, adapters.AfterChatRequest{
 		BotID: "bot-1",
 		Messages: []adapters.Message{
 			{Role: "user", Content: "Actually, I moved to Berlin"},
@@ -147,7 +147,7 @@ func TestFormationDelete(t *testing.T) {
 		},
 	}
 
-	result := runFormation(context.Background(), slog.Default(), llm, runtime, adapters.AfterChatRequest{
+	result := runFormation(context.Background(), slog.Default(), llm, runtime, 0, adapters.AfterChatRequest{
 		BotID: "bot-1",
 		Messages: []adapters.Message{
 			{Role: "user", Content: "I stopped drinking coffee"},
@@ -176,7 +176,7 @@ func TestFormationNOOP(t *testing.T) {
 		},
 	}
 
-	result := runFormation(context.Background(), slog.Default(), llm, runtime, adapters.AfterChatRequest{
+	result := runFormation(context.Background(), slog.Default(), llm, runtime, 0, adapters.AfterChatRequest{
 		BotID: "bot-1",
 		Messages: []adapters.Message{
 			{Role: "user", Content: "I like tea"},
@@ -205,7 +205,7 @@ func TestFormationNoFacts(t *testing.T) {
 		extractFacts: []string{},
 	}
 
-	result := runFormation(context.Background(), slog.Default(), llm, runtime, adapters.AfterChatRequest{
+	result := runFormation(context.Background(), slog.Default(), llm, runtime, 0, adapters.AfterChatRequest{
 		BotID: "bot-1",
 		Messages: []adapters.Message{
 			{Role: "user", Content: "Hello"},
@@ -244,7 +244,7 @@ func TestFormationMixedActions(t *testing.T) {
 		},
 	}
 
-	result := runFormation(context.Background(), slog.Default(), llm, runtime, adapters.AfterChatRequest{
+	result := runFormation(context.Background(), slog.Default(), llm, runtime, 0, adapters.AfterChatRequest{
 		BotID: "bot-1",
 		Messages: []adapters.Message{
 			{Role: "user", Content: "I moved to Berlin and I like dark mode"},
@@ -283,7 +283,7 @@ func TestFormationInvalidActionsSkipped(t *testing.T) {
 		},
 	}
 
-	result := runFormation(context.Background(), slog.Default(), llm, runtime, adapters.AfterChatRequest{
+	result := runFormation(context.Background(), slog.Default(), llm, runtime, 0, adapters.AfterChatRequest{
 		BotID: "bot-1",
 		Messages: []adapters.Message{
 			{Role: "user", Content: "I like cats"},
@@ -320,7 +320,7 @@ func TestFormationDuplicateActionsSameID(t *testing.T) {
 		},
 	}
 
-	result := runFormation(context.Background(), slog.Default(), llm, runtime, adapters.AfterChatRequest{
+	result := runFormation(context.Background(), slog.Default(), llm, runtime, 0, adapters.AfterChatRequest{
 		BotID: "bot-1",
 		Messages: []adapters.Message{
 			{Role: "user", Content: "I changed my mind"},
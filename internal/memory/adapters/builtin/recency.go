@@ -0,0 +1,50 @@
+package builtin
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	adapters "github.com/memohai/memoh/internal/memory/adapters"
+)
+
+// applyRecencyDecay scales each item's relevance score by 0.5^(age/halfLife),
+// so that a memory one half-life old counts half as much as a fresh one, and
+// re-sorts the items by the decayed score. Items whose timestamps cannot be
+// parsed keep their original score. A non-positive halfLife disables decay.
+func applyRecencyDecay(items []adapters.MemoryItem, halfLife time.Duration, now time.Time) []adapters.MemoryItem {
+	if halfLife <= 0 || len(items) == 0 {
+		return items
+	}
+	for i := range items {
+		ts, ok := memoryTimestamp(items[i])
+		if !ok {
+			continue
+		}
+		age := now.Sub(ts)
+		if age <= 0 {
+			continue
+		}
+		items[i].Score *= math.Pow(0.5, float64(age)/float64(halfLife))
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Score > items[j].Score
+	})
+	return items
+}
+
+// memoryTimestamp returns the last-updated time of an item, falling back to
+// its creation time.
+func memoryTimestamp(item adapters.MemoryItem) (time.Time, bool) {
+	for _, raw := range []string{item.UpdatedAt, item.CreatedAt} {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+		if ts, err := time.Parse(time.RFC3339, raw); err == nil {
+			return ts, true
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,71 @@
+package builtin
+
+import (
+	"log/slog"
+	"testing"
+	"time"
+
+	adapters "github.com/memohai/memoh/internal/memory/adapters"
+)
+
+func TestApplyRecencyDecayReordersEqualScores(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	items := []adapters.MemoryItem{
+		{ID: "stale", Score: 0.8, CreatedAt: now.Add(-60 * 24 * time.Hour).Format(time.RFC3339)},
+		{ID: "fresh", Score: 0.8, CreatedAt: now.Add(-24 * time.Hour).Format(time.RFC3339)},
+	}
+
+	got := applyRecencyDecay(items, 30*24*time.Hour, now)
+	if got[0].ID != "fresh" || got[1].ID != "stale" {
+		t.Fatalf("expected fresh before stale, got %s, %s", got[0].ID, got[1].ID)
+	}
+	if got[1].Score < 0.19 || got[1].Score > 0.21 {
+		t.Fatalf("expected stale score ~0.2 after two half-lives, got %f", got[1].Score)
+	}
+}
+
+func TestApplyRecencyDecayPrefersUpdatedAt(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	old := now.Add(-90 * 24 * time.Hour).Format(time.RFC3339)
+	items := []adapters.MemoryItem{
+		{ID: "a", Score: 0.5, CreatedAt: old},
+		{ID: "b", Score: 0.5, CreatedAt: old, UpdatedAt: now.Format(time.RFC3339)},
+		{ID: "c", Score: 0.5, CreatedAt: "not-a-time"},
+	}
+
+	got := applyRecencyDecay(items, 7*24*time.Hour, now)
+	if got[0].ID != "b" || got[1].ID != "c" {
+		t.Fatalf("expected updated then undated items first, got %s, %s", got[0].ID, got[1].ID)
+	}
+	if got[2].ID != "a" {
+		t.Fatalf("expected stale item last, got %s", got[2].ID)
+	}
+}
+
+func TestApplyRecencyDecayDisabled(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	items := []adapters.MemoryItem{
+		{ID: "stale", Score: 0.8, CreatedAt: now.Add(-365 * 24 * time.Hour).Format(time.RFC3339)},
+		{ID: "fresh", Score: 0.8, CreatedAt: now.Format(time.RFC3339)},
+	}
+
+	got := applyRecencyDecay(items, 0, now)
+	if got[0].ID != "stale" || got[0].Score != 0.8 {
+		t.Fatalf("expected items untouched when disabled, got %+v", got)
+	}
+}
+
+func TestBuiltinProviderApplyRecencyConfig(t *testing.T) {
+	t.Parallel()
+	p := NewBuiltinProvider(slog.Default(), nil, nil, nil)
+	if p.recencyHalfLife != 0 {
+		t.Fatalf("expected recency weighting disabled by default, got %s", p.recencyHalfLife)
+	}
+	p.ApplyProviderConfig(map[string]any{"recency_half_life_days": float64(14)})
+	if p.recencyHalfLife != 14*24*time.Hour {
+		t.Fatalf("expected 14 day half-life, got %s", p.recencyHalfLife)
+	}
+}
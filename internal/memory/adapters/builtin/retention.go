@@ -0,0 +1,105 @@
+package builtin
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	adapters "github.com/memohai/memoh/internal/memory/adapters"
+)
+
+const (
+	// metadataImportance is the memory metadata key holding the 0-1
+	// importance score assigned during formation.
+	metadataImportance = "importance"
+	// defaultImportance is assumed for memories that were never scored, so
+	// they rank between clearly low- and high-value facts.
+	defaultImportance = 0.5
+)
+
+// memoryImportance returns the importance score stored in an item's metadata.
+func memoryImportance(item adapters.MemoryItem) float64 {
+	switch v := item.Metadata[metadataImportance].(type) {
+	case float64:
+		return v
+	case float32:
+		return float64(v)
+	case int:
+		return float64(v)
+	case int64:
+		return float64(v)
+	}
+	return defaultImportance
+}
+
+// withImportance returns a copy of metadata carrying the importance score.
+// A nil score leaves the metadata unchanged.
+func withImportance(metadata map[string]any, importance *float64) map[string]any {
+	if importance == nil {
+		return metadata
+	}
+	out := make(map[string]any, len(metadata)+1)
+	for k, v := range metadata {
+		out[k] = v
+	}
+	out[metadataImportance] = *importance
+	return out
+}
+
+// selectEvictions picks the IDs to remove so that at most limit items remain.
+// Lower-importance memories go first; ties are broken by age, oldest first.
+func selectEvictions(items []adapters.MemoryItem, limit int) []string {
+	if limit <= 0 || len(items) <= limit {
+		return nil
+	}
+	ranked := make([]adapters.MemoryItem, len(items))
+	copy(ranked, items)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		ii, ij := memoryImportance(ranked[i]), memoryImportance(ranked[j])
+		if ii != ij {
+			return ii < ij
+		}
+		return memoryAge(ranked[i]) < memoryAge(ranked[j])
+	})
+	ids := make([]string, 0, len(ranked)-limit)
+	for _, item := range ranked[:len(ranked)-limit] {
+		ids = append(ids, item.ID)
+	}
+	return ids
+}
+
+// memoryAge returns the sortable timestamp used to order equally important
+// memories.
+func memoryAge(item adapters.MemoryItem) string {
+	if ts := strings.TrimSpace(item.UpdatedAt); ts != "" {
+		return ts
+	}
+	return strings.TrimSpace(item.CreatedAt)
+}
+
+// enforceRetention evicts the least important memories of a bot once the
+// configured cap is exceeded. It returns the number of evicted memories.
+func enforceRetention(ctx context.Context, runtime memoryRuntime, botID string, limit int) (int, error) {
+	if limit <= 0 {
+		return 0, nil
+	}
+	resp, err := runtime.GetAll(ctx, adapters.GetAllRequest{
+		BotID: botID,
+		Filters: map[string]any{
+			"scopeId": botID,
+			"bot_id":  botID,
+		},
+		NoStats: true,
+	})
+	if err != nil {
+		return 0, err
+	}
+	ids := selectEvictions(resp.Results, limit)
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	if _, err := runtime.DeleteBatch(ctx, ids); err != nil {
+		return 0, err
+	}
+	return len(ids), nil
+}
@@ -0,0 +1,125 @@
+package builtin
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	adapters "github.com/memohai/memoh/internal/memory/adapters"
+)
+
+func TestSelectEvictionsLowestImportanceFirst(t *testing.T) {
+	t.Parallel()
+	items := []adapters.MemoryItem{
+		{ID: "high", UpdatedAt: "2026-01-01T00:00:00Z", Metadata: map[string]any{"importance": 0.9}},
+		{ID: "low", UpdatedAt: "2026-01-05T00:00:00Z", Metadata: map[string]any{"importance": 0.1}},
+		{ID: "unscored", UpdatedAt: "2026-01-02T00:00:00Z"},
+		{ID: "mid-new", UpdatedAt: "2026-01-04T00:00:00Z", Metadata: map[string]any{"importance": 0.5}},
+	}
+
+	cases := []struct {
+		limit int
+		want  []string
+	}{
+		{limit: 4, want: nil},
+		{limit: 3, want: []string{"low"}},
+		// Unscored memories default to 0.5 and tie with mid-new; the older goes first.
+		{limit: 2, want: []string{"low", "unscored"}},
+		{limit: 1, want: []string{"low", "unscored", "mid-new"}},
+		{limit: 0, want: nil},
+	}
+	for _, tc := range cases {
+		got := selectEvictions(items, tc.limit)
+		if len(got) != len(tc.want) {
+			t.Fatalf("limit %d: expected %v, got %v", tc.limit, tc.want, got)
+		}
+		for i := range tc.want {
+			if got[i] != tc.want[i] {
+				t.Fatalf("limit %d: expected %v, got %v", tc.limit, tc.want, got)
+			}
+		}
+	}
+}
+
+func TestBuiltinProviderOnAfterChatEvictsLeastImportant(t *testing.T) {
+	t.Parallel()
+	encoder := &fakeSparseEncoder{}
+	index := newFakeSparseIndex(encoder)
+	store := newFakeSparseStore()
+	runtime := &sparseRuntime{qdrant: index, encoder: encoder, store: store}
+	p := NewBuiltinProvider(slog.Default(), runtime, nil, nil)
+	p.SetLLM(&fakeLLM{
+		extractFacts: []string{"User's name is Ada", "User had toast today", "User works in Berlin"},
+		decideActions: []adapters.DecisionAction{
+			{Event: "ADD", Text: "User's name is Ada", Importance: floatPtr(0.9)},
+			{Event: "ADD", Text: "User had toast today", Importance: floatPtr(0.1)},
+			{Event: "ADD", Text: "User works in Berlin", Importance: floatPtr(0.6)},
+		},
+	})
+	p.ApplyProviderConfig(map[string]any{"max_memories": float64(2)})
+
+	err := p.OnAfterChat(context.Background(), adapters.AfterChatRequest{
+		BotID:    "bot-1",
+		Messages: []adapters.Message{{Role: "user", Content: "I'm Ada, I work in Berlin and had toast today"}},
+	})
+	if err != nil {
+		t.Fatalf("OnAfterChat: %v", err)
+	}
+
+	if len(store.items) != 2 {
+		t.Fatalf("expected 2 memories after eviction, got %d", len(store.items))
+	}
+	for _, item := range store.items {
+		if item.Memory == "User had toast today" {
+			t.Fatal("expected lowest-importance memory to be evicted")
+		}
+	}
+	if len(index.points) != 2 {
+		t.Fatalf("expected evicted memory removed from index, got %d points", len(index.points))
+	}
+}
+
+func floatPtr(v float64) *float64 { return &v }
+
+func TestApplyActionsUpdateKeepsImportance(t *testing.T) {
+	t.Parallel()
+	encoder := &fakeSparseEncoder{}
+	index := newFakeSparseIndex(encoder)
+	store := newFakeSparseStore()
+	runtime := &sparseRuntime{qdrant: index, encoder: encoder, store: store}
+	ctx := context.Background()
+	filters := map[string]any{"bot_id": "bot-1"}
+	var result formationResult
+
+	applyActions(ctx, slog.Default(), runtime, 0, "bot-1", []adapters.DecisionAction{
+		{Event: "ADD", Text: "User likes tea", Importance: floatPtr(0.8)},
+	}, filters, nil, &result)
+	all, err := runtime.GetAll(ctx, adapters.GetAllRequest{BotID: "bot-1", Filters: filters, NoStats: true})
+	if err != nil || len(all.Results) != 1 {
+		t.Fatalf("expected one memory, got %+v (err %v)", all.Results, err)
+	}
+	id := all.Results[0].ID
+
+	importanceOf := func() float64 {
+		t.Helper()
+		all, err := runtime.GetAll(ctx, adapters.GetAllRequest{BotID: "bot-1", Filters: filters, NoStats: true})
+		if err != nil || len(all.Results) != 1 {
+			t.Fatalf("expected one memory, got %+v (err %v)", all.Results, err)
+		}
+		return memoryImportance(all.Results[0])
+	}
+
+	applyActions(ctx, slog.Default(), runtime, 0, "bot-1", []adapters.DecisionAction{
+		{Event: "UPDATE", ID: id, Text: "User likes green tea"},
+	}, filters, nil, &result)
+	if got := importanceOf(); got != 0.8 {
+		t.Fatalf("expected unscored update to keep importance 0.8, got %v", got)
+	}
+
+	applyActions(ctx, slog.Default(), runtime, 0, "bot-1", []adapters.DecisionAction{
+		{Event: "UPDATE", ID: id, Text: "User used to like tea", Importance: floatPtr(0)},
+	}, filters, nil, &result)
+	if got := importanceOf(); got != 0 {
+		t.Fatalf("expected explicit zero importance to be stored, got %v", got)
+	}
+}
@@ -121,7 +121,7 @@ func (r *sparseRuntime) Search(ctx context.Context, req adapters.SearchRequest)
 		return adapters.SearchResponse{}, err
 	}
 	if err := r.ensureCollection(ctx); err != nil {
-		return adapters.SearchResponse{}, err
+		return adapters.SearchResponse{}, fmt.Errorf("%w: %w", adapters.ErrStoreUnreachable, err)
 	}
 
 	limit := req.Limit
@@ -131,14 +131,14 @@ func (r *sparseRuntime) Search(ctx context.Context, req adapters.SearchRequest)
 
 	vec, err := r.encoder.EncodeQuery(ctx, req.Query)
 	if err != nil {
-		return adapters.SearchResponse{}, fmt.Errorf("sparse encode query: %w", err)
+		return adapters.SearchResponse{}, fmt.Errorf("sparse encode query: %w: %w", adapters.ErrEmbedderUnavailable, err)
 	}
 	results, err := r.qdrant.Search(ctx, qdrantclient.SparseVector{
 		Indices: vec.Indices,
 		Values:  vec.Values,
 	}, botID, limit)
 	if err != nil {
-		return adapters.SearchResponse{}, err
+		return adapters.SearchResponse{}, fmt.Errorf("%w: %w", adapters.ErrStoreUnreachable, err)
 	}
 	items := make([]adapters.MemoryItem, 0, len(results))
 	for _, r := range results {
@@ -196,11 +196,12 @@ func (r *sparseRuntime) Update(ctx context.Context, req adapters.UpdateRequest)
 		}
 	}
 	if existing == nil {
-		return adapters.MemoryItem{}, errors.New("sparse runtime: memory not found")
+		return adapters.MemoryItem{}, fmt.Errorf("sparse runtime: %w", adapters.ErrMemoryNotFound)
 	}
 	existing.Memory = text
 	existing.Hash = runtimeHash(text)
 	existing.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+	existing.Metadata = withImportance(adapters.MergeMetadata(existing.Metadata, req.Metadata), req.Importance)
 	if err := r.store.PersistMemories(ctx, botID, []storefs.MemoryItem{*existing}, nil); err != nil {
 		return adapters.MemoryItem{}, err
 	}
@@ -379,6 +380,33 @@ func (r *sparseRuntime) Rebuild(ctx context.Context, botID string) (adapters.Reb
 	return r.syncSourceItems(ctx, botID, items)
 }
 
+// Reindex drops every indexed point for the bot and re-encodes all source
+// memories into fresh BM25 sparse vectors.
+func (r *sparseRuntime) Reindex(ctx context.Context, botID string) (adapters.RebuildResult, error) {
+	items, err := r.store.ReadAllMemoryFiles(ctx, botID)
+	if err != nil {
+		return adapters.RebuildResult{}, err
+	}
+	if err := r.ensureCollection(ctx); err != nil {
+		return adapters.RebuildResult{}, fmt.Errorf("%w: %w", adapters.ErrStoreUnreachable, err)
+	}
+	if err := r.qdrant.DeleteByBotID(ctx, botID); err != nil {
+		return adapters.RebuildResult{}, err
+	}
+	if err := r.upsertSourceItems(ctx, botID, items); err != nil {
+		return adapters.RebuildResult{}, err
+	}
+	count, err := r.qdrant.Count(ctx, botID)
+	if err != nil {
+		return adapters.RebuildResult{}, err
+	}
+	return adapters.RebuildResult{
+		FsCount:       len(items),
+		StorageCount:  count,
+		RestoredCount: count,
+	}, nil
+}
+
 // --- helpers ---
 
 func (r *sparseRuntime) syncSourceItems(ctx context.Context, botID string, items []storefs.MemoryItem) (adapters.RebuildResult, error) {
@@ -480,7 +508,7 @@ func (r *sparseRuntime) upsertSourceItems(ctx context.Context, botID string, ite
 	}
 	vectors, err := r.encoder.EncodeDocuments(ctx, texts)
 	if err != nil {
-		return fmt.Errorf("sparse encode documents: %w", err)
+		return fmt.Errorf("sparse encode documents: %w: %w", adapters.ErrEmbedderUnavailable, err)
 	}
 	if len(vectors) != len(canonical) {
 		return fmt.Errorf("sparse encode documents: expected %d vectors, got %d", len(canonical), len(vectors))
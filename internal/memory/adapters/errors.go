@@ -0,0 +1,14 @@
+package adapters
+
+import "errors"
+
+var (
+	// ErrMemoryNotFound indicates the requested memory item does not exist.
+	ErrMemoryNotFound = errors.New("memory not found")
+	// ErrDimensionMismatch indicates the embedding vector size does not match the configured index dimensions.
+	ErrDimensionMismatch = errors.New("embedding dimension mismatch")
+	// ErrEmbedderUnavailable indicates the embedding/encoder model could not be reached or failed.
+	ErrEmbedderUnavailable = errors.New("embedder unavailable")
+	// ErrStoreUnreachable indicates the backing vector store or runtime is not configured or reachable.
+	ErrStoreUnreachable = errors.New("memory store unreachable")
+)
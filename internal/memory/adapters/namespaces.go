@@ -0,0 +1,76 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+)
+
+// SharedNamespace is the namespace used for bot-shared memories when an item
+// does not carry an explicit namespace in its metadata.
+const SharedNamespace = "bot"
+
+// NamespaceStats summarises the memories stored under one namespace.
+type NamespaceStats struct {
+	Namespace     string `json:"namespace"`
+	Count         int    `json:"count"`
+	LastUpdatedAt string `json:"last_updated_at,omitempty"`
+}
+
+// NamespaceLister is implemented by providers that can enumerate memory
+// namespaces natively instead of scanning every item.
+type NamespaceLister interface {
+	ListNamespaces(ctx context.Context, botID string) ([]NamespaceStats, error)
+}
+
+// ListNamespaces returns the namespaces holding memories for a bot together
+// with their item counts, sorted by namespace name.
+func ListNamespaces(ctx context.Context, provider Provider, botID string) ([]NamespaceStats, error) {
+	botID = strings.TrimSpace(botID)
+	if botID == "" {
+		return nil, errors.New("bot id is required")
+	}
+	if lister, ok := provider.(NamespaceLister); ok {
+		return lister.ListNamespaces(ctx, botID)
+	}
+	resp, err := provider.GetAll(ctx, GetAllRequest{
+		BotID: botID,
+		Filters: map[string]any{
+			"scopeId": botID,
+			"bot_id":  botID,
+		},
+		NoStats: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return CountNamespaces(resp.Results), nil
+}
+
+// CountNamespaces groups items by their metadata namespace. Items without one
+// are counted under SharedNamespace.
+func CountNamespaces(items []MemoryItem) []NamespaceStats {
+	byName := map[string]*NamespaceStats{}
+	for _, item := range DeduplicateItems(items) {
+		name := SharedNamespace
+		if ns, ok := item.Metadata["namespace"].(string); ok && strings.TrimSpace(ns) != "" {
+			name = strings.TrimSpace(ns)
+		}
+		stats, ok := byName[name]
+		if !ok {
+			stats = &NamespaceStats{Namespace: name}
+			byName[name] = stats
+		}
+		stats.Count++
+		if item.UpdatedAt > stats.LastUpdatedAt {
+			stats.LastUpdatedAt = item.UpdatedAt
+		}
+	}
+	out := make([]NamespaceStats, 0, len(byName))
+	for _, stats := range byName {
+		out = append(out, *stats)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Namespace < out[j].Namespace })
+	return out
+}
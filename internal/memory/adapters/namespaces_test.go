@@ -0,0 +1,74 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type fakeNamespaceStore struct {
+	Provider
+	items   []MemoryItem
+	err     error
+	lastReq GetAllRequest
+}
+
+func (f *fakeNamespaceStore) GetAll(_ context.Context, req GetAllRequest) (SearchResponse, error) {
+	f.lastReq = req
+	if f.err != nil {
+		return SearchResponse{}, f.err
+	}
+	return SearchResponse{Results: f.items}, nil
+}
+
+func TestListNamespacesGroupsByMetadata(t *testing.T) {
+	t.Parallel()
+	store := &fakeNamespaceStore{items: []MemoryItem{
+		{ID: "bot-1:mem_1", Memory: "likes tea", UpdatedAt: "2026-01-01T00:00:00Z"},
+		{ID: "bot-1:mem_2", Memory: "works remote", UpdatedAt: "2026-01-03T00:00:00Z", Metadata: map[string]any{"namespace": "bot"}},
+		{ID: "bot-1:mem_3", Memory: "project alpha", UpdatedAt: "2026-01-02T00:00:00Z", Metadata: map[string]any{"namespace": "projects"}},
+		{ID: "bot-1:mem_4", Memory: "project beta", UpdatedAt: "2026-01-05T00:00:00Z", Metadata: map[string]any{"namespace": "projects"}},
+		{ID: "bot-1:mem_4", Memory: "project beta", UpdatedAt: "2026-01-05T00:00:00Z", Metadata: map[string]any{"namespace": "projects"}},
+		{ID: "bot-1:mem_5", Memory: "prefers email", Metadata: map[string]any{"namespace": "contacts"}},
+	}}
+
+	got, err := ListNamespaces(context.Background(), store, " bot-1 ")
+	if err != nil {
+		t.Fatalf("ListNamespaces: %v", err)
+	}
+	if store.lastReq.BotID != "bot-1" {
+		t.Fatalf("expected trimmed bot id, got %q", store.lastReq.BotID)
+	}
+	want := []NamespaceStats{
+		{Namespace: "bot", Count: 2, LastUpdatedAt: "2026-01-03T00:00:00Z"},
+		{Namespace: "contacts", Count: 1},
+		{Namespace: "projects", Count: 2, LastUpdatedAt: "2026-01-05T00:00:00Z"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d namespaces, got %d: %+v", len(want), len(got), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("namespace[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestListNamespacesEmptyAndErrors(t *testing.T) {
+	t.Parallel()
+	got, err := ListNamespaces(context.Background(), &fakeNamespaceStore{}, "bot-1")
+	if err != nil {
+		t.Fatalf("ListNamespaces: %v", err)
+	}
+	if len(got) != 0 {
+		t.Fatalf("expected no namespaces, got %+v", got)
+	}
+
+	storeErr := errors.New("store down")
+	if _, err := ListNamespaces(context.Background(), &fakeNamespaceStore{err: storeErr}, "bot-1"); !errors.Is(err, storeErr) {
+		t.Fatalf("expected store error, got %v", err)
+	}
+	if _, err := ListNamespaces(context.Background(), &fakeNamespaceStore{}, "  "); err == nil {
+		t.Fatal("expected error for empty bot id")
+	}
+}
@@ -45,3 +45,10 @@ type SourceSyncProvider interface {
 	Status(ctx context.Context, botID string) (MemoryStatusResponse, error)
 	Rebuild(ctx context.Context, botID string) (RebuildResult, error)
 }
+
+// ReindexProvider is implemented by providers whose derived index can be
+// discarded and rebuilt in full from the canonical source (e.g. after the
+// embedding model changes).
+type ReindexProvider interface {
+	Reindex(ctx context.Context, botID string) (RebuildResult, error)
+}
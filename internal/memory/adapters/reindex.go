@@ -0,0 +1,127 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrReindexInProgress is returned when a reindex is requested for a provider
+// that already has one running.
+var ErrReindexInProgress = errors.New("memory reindex already in progress")
+
+const (
+	ReindexStatusRunning   = "running"
+	ReindexStatusCompleted = "completed"
+	ReindexStatusFailed    = "failed"
+)
+
+// ReindexProgress reports the state of a provider-wide reindex run.
+type ReindexProgress struct {
+	ProviderID    string     `json:"provider_id"`
+	Status        string     `json:"status"`
+	TotalBots     int        `json:"total_bots"`
+	ProcessedBots int        `json:"processed_bots"`
+	FailedBots    int        `json:"failed_bots"`
+	IndexedCount  int        `json:"indexed_count"`
+	LastError     string     `json:"last_error,omitempty"`
+	StartedAt     time.Time  `json:"started_at"`
+	FinishedAt    *time.Time `json:"finished_at,omitempty"`
+}
+
+// Reindexer runs background reindex jobs, at most one per provider.
+type Reindexer struct {
+	mu     sync.Mutex
+	runs   map[string]*ReindexProgress
+	logger *slog.Logger
+	now    func() time.Time
+}
+
+func NewReindexer(log *slog.Logger) *Reindexer {
+	if log == nil {
+		log = slog.Default()
+	}
+	return &Reindexer{
+		runs:   map[string]*ReindexProgress{},
+		logger: log.With(slog.String("component", "memory_reindexer")),
+		now:    time.Now,
+	}
+}
+
+// Start launches a background reindex of every bot in botIDs. It returns
+// ErrReindexInProgress if a run for the same provider has not finished yet.
+// The run is detached from ctx cancellation so it outlives the request that
+// started it. The returned snapshot reflects the state at launch time.
+func (r *Reindexer) Start(ctx context.Context, providerID string, provider ReindexProvider, botIDs []string) (ReindexProgress, error) {
+	providerID = strings.TrimSpace(providerID)
+	if providerID == "" {
+		return ReindexProgress{}, errors.New("provider id is required")
+	}
+	if provider == nil {
+		return ReindexProgress{}, errors.New("provider does not support reindex")
+	}
+	r.mu.Lock()
+	if current, ok := r.runs[providerID]; ok && current.Status == ReindexStatusRunning {
+		snapshot := *current
+		r.mu.Unlock()
+		return snapshot, ErrReindexInProgress
+	}
+	progress := &ReindexProgress{
+		ProviderID: providerID,
+		Status:     ReindexStatusRunning,
+		TotalBots:  len(botIDs),
+		StartedAt:  r.now().UTC(),
+	}
+	r.runs[providerID] = progress
+	snapshot := *progress
+	r.mu.Unlock()
+
+	ids := append([]string(nil), botIDs...)
+	go r.run(context.WithoutCancel(ctx), progress, provider, ids)
+	return snapshot, nil
+}
+
+// Progress returns the latest progress for a provider, if any run was started.
+func (r *Reindexer) Progress(providerID string) (ReindexProgress, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	progress, ok := r.runs[strings.TrimSpace(providerID)]
+	if !ok {
+		return ReindexProgress{}, false
+	}
+	return *progress, true
+}
+
+func (r *Reindexer) run(ctx context.Context, progress *ReindexProgress, provider ReindexProvider, botIDs []string) {
+	for _, botID := range botIDs {
+		result, err := provider.Reindex(ctx, botID)
+		r.mu.Lock()
+		progress.ProcessedBots++
+		if err != nil {
+			progress.FailedBots++
+			progress.LastError = err.Error()
+		} else {
+			progress.IndexedCount += result.StorageCount
+		}
+		r.mu.Unlock()
+		if err != nil {
+			r.logger.Warn("memory reindex bot failed",
+				slog.String("provider_id", progress.ProviderID), slog.String("bot_id", botID), slog.Any("error", err))
+		}
+	}
+	r.mu.Lock()
+	finished := r.now().UTC()
+	progress.FinishedAt = &finished
+	if progress.FailedBots > 0 {
+		progress.Status = ReindexStatusFailed
+	} else {
+		progress.Status = ReindexStatusCompleted
+	}
+	total, failed := progress.TotalBots, progress.FailedBots
+	r.mu.Unlock()
+	r.logger.Info("memory reindex finished",
+		slog.String("provider_id", progress.ProviderID), slog.Int("bots", total), slog.Int("failed", failed))
+}
@@ -0,0 +1,109 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeReindexProvider struct {
+	mu      sync.Mutex
+	release chan struct{}
+	calls   []string
+	failFor map[string]bool
+}
+
+func (f *fakeReindexProvider) Reindex(_ context.Context, botID string) (RebuildResult, error) {
+	if f.release != nil {
+		<-f.release
+	}
+	f.mu.Lock()
+	f.calls = append(f.calls, botID)
+	f.mu.Unlock()
+	if f.failFor[botID] {
+		return RebuildResult{}, errors.New("embed failed")
+	}
+	return RebuildResult{FsCount: 2, StorageCount: 2, RestoredCount: 2}, nil
+}
+
+func waitForReindex(t *testing.T, r *Reindexer, providerID string) ReindexProgress {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		progress, ok := r.Progress(providerID)
+		if ok && progress.Status != ReindexStatusRunning {
+			return progress
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("reindex for %s did not finish", providerID)
+	return ReindexProgress{}
+}
+
+func TestReindexerReportsProgress(t *testing.T) {
+	t.Parallel()
+	r := NewReindexer(nil)
+	provider := &fakeReindexProvider{failFor: map[string]bool{"bot-2": true}}
+
+	started, err := r.Start(context.Background(), "p1", provider, []string{"bot-1", "bot-2", "bot-3"})
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	if started.Status != ReindexStatusRunning || started.TotalBots != 3 {
+		t.Fatalf("unexpected start snapshot: %+v", started)
+	}
+
+	progress := waitForReindex(t, r, "p1")
+	if progress.ProcessedBots != 3 {
+		t.Fatalf("expected 3 processed bots, got %d", progress.ProcessedBots)
+	}
+	if progress.FailedBots != 1 {
+		t.Fatalf("expected 1 failed bot, got %d", progress.FailedBots)
+	}
+	if progress.IndexedCount != 4 {
+		t.Fatalf("expected indexed count 4, got %d", progress.IndexedCount)
+	}
+	if progress.Status != ReindexStatusFailed || progress.LastError != "embed failed" {
+		t.Fatalf("unexpected final status: %+v", progress)
+	}
+	if progress.FinishedAt == nil {
+		t.Fatal("expected finished_at to be set")
+	}
+}
+
+func TestReindexerRejectsConcurrentRuns(t *testing.T) {
+	t.Parallel()
+	r := NewReindexer(nil)
+	provider := &fakeReindexProvider{release: make(chan struct{})}
+
+	if _, err := r.Start(context.Background(), "p1", provider, []string{"bot-1"}); err != nil {
+		t.Fatalf("first start: %v", err)
+	}
+	if _, err := r.Start(context.Background(), "p1", provider, []string{"bot-1"}); !errors.Is(err, ErrReindexInProgress) {
+		t.Fatalf("expected ErrReindexInProgress, got %v", err)
+	}
+	// Runs for other providers are independent.
+	other := &fakeReindexProvider{}
+	if _, err := r.Start(context.Background(), "p2", other, []string{"bot-9"}); err != nil {
+		t.Fatalf("start other provider: %v", err)
+	}
+
+	close(provider.release)
+	if progress := waitForReindex(t, r, "p1"); progress.Status != ReindexStatusCompleted {
+		t.Fatalf("expected completed, got %+v", progress)
+	}
+	if _, err := r.Start(context.Background(), "p1", &fakeReindexProvider{}, []string{"bot-1"}); err != nil {
+		t.Fatalf("restart after completion: %v", err)
+	}
+	waitForReindex(t, r, "p1")
+}
+
+func TestReindexerProgressUnknownProvider(t *testing.T) {
+	t.Parallel()
+	r := NewReindexer(nil)
+	if _, ok := r.Progress("missing"); ok {
+		t.Fatal("expected no progress for unknown provider")
+	}
+}
@@ -15,17 +15,19 @@ import (
 )
 
 type Service struct {
-	queries  *sqlc.Queries
-	registry *Registry
-	logger   *slog.Logger
-	cfg      config.Config
+	queries   *sqlc.Queries
+	registry  *Registry
+	reindexer *Reindexer
+	logger    *slog.Logger
+	cfg       config.Config
 }
 
 func NewService(log *slog.Logger, queries *sqlc.Queries, cfg config.Config) *Service {
 	return &Service{
-		queries: queries,
-		logger:  log.With(slog.String("service", "memory_providers")),
-		cfg:     cfg,
+		queries:   queries,
+		reindexer: NewReindexer(log),
+		logger:    log.With(slog.String("service", "memory_providers")),
+		cfg:       cfg,
 	}
 }
 
@@ -75,6 +77,27 @@ func (*Service) ListMeta(_ context.Context) []ProviderMeta {
 						Required:    false,
 						Example:     1800,
 					},
+					"recency_half_life_days": {
+						Type:        "integer",
+						Title:       "Recency Half-Life (days)",
+						Description: "Halve a memory's search score for every N days of age so recent facts outrank stale ones. Disabled when empty or 0.",
+						Required:    false,
+						Example:     30,
+					},
+					"dedupe_threshold": {
+						Type:        "number",
+						Title:       "Dedupe Threshold",
+						Description: "Cosine similarity (0-1) above which a new memory is merged into the closest existing one instead of being stored separately (dense mode only). Disabled when empty or 0.",
+						Required:    false,
+						Example:     0.92,
+					},
+					"max_memories": {
+						Type:        "integer",
+						Title:       "Max Memories",
+						Description: "Maximum memories kept per bot. When exceeded, the lowest-importance memories are evicted first. Unlimited when empty or 0.",
+						Required:    false,
+						Example:     500,
+					},
 				},
 			},
 		},
@@ -274,6 +297,40 @@ func (s *Service) Delete(ctx context.Context, id string) error {
 	return nil
 }
 
+// Reindex starts a background rebuild of the derived index for every bot bound
+// to the provider, re-embedding all memories with the current configuration.
+func (s *Service) Reindex(ctx context.Context, id string) (ReindexProgress, error) {
+	pgID, err := db.ParseUUID(id)
+	if err != nil {
+		return ReindexProgress{}, err
+	}
+	if s.registry == nil {
+		return ReindexProgress{}, ErrStoreUnreachable
+	}
+	provider, err := s.registry.Get(id)
+	if err != nil {
+		return ReindexProgress{}, err
+	}
+	reindexProvider, ok := provider.(ReindexProvider)
+	if !ok {
+		return ReindexProgress{}, fmt.Errorf("memory provider %s does not support reindex", provider.Type())
+	}
+	rows, err := s.queries.ListBotIDsByMemoryProvider(ctx, pgID)
+	if err != nil {
+		return ReindexProgress{}, fmt.Errorf("list bots for memory provider: %w", err)
+	}
+	botIDs := make([]string, 0, len(rows))
+	for _, row := range rows {
+		botIDs = append(botIDs, row.String())
+	}
+	return s.reindexer.Start(ctx, id, reindexProvider, botIDs)
+}
+
+// ReindexProgress returns the progress of the latest reindex run for a provider.
+func (s *Service) ReindexProgress(id string) (ReindexProgress, bool) {
+	return s.reindexer.Progress(id)
+}
+
 // EnsureDefault creates a default builtin provider if none exists.
 func (s *Service) EnsureDefault(ctx context.Context) (ProviderGetResponse, error) {
 	row, err := s.queries.GetDefaultMemoryProvider(ctx)
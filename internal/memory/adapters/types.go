@@ -68,6 +68,12 @@ type UpdateRequest struct {
 	MemoryID         string `json:"memory_id"`
 	Memory           string `json:"memory"`
 	EmbeddingEnabled *bool  `json:"embedding_enabled,omitempty"`
+	// Importance replaces the stored importance score when set; otherwise
+	// the existing score is kept.
+	Importance *float64 `json:"importance,omitempty"`
+	// Metadata is merged into the stored metadata; its keys replace stored
+	// ones and other stored keys are kept.
+	Metadata map[string]any `json:"metadata,omitempty"`
 }
 
 type GetAllRequest struct {
@@ -180,6 +186,9 @@ type DecisionAction struct {
 	ID        string `json:"id,omitempty"`
 	Text      string `json:"text"`
 	OldMemory string `json:"old_memory,omitempty"`
+	// Importance is the LLM's 0-1 estimate of how valuable the memory is to
+	// retain. Nil means the model did not provide one.
+	Importance *float64 `json:"importance,omitempty"`
 }
 
 type DecideResponse struct {
@@ -26,6 +26,7 @@ type Config struct {
 	BaseURL    string
 	APIKey     string `json:"-"`
 	ClientType string
+	Headers    map[string]string
 	Timeout    time.Duration
 }
 
@@ -48,6 +49,7 @@ func (c *Client) model() *sdk.Model {
 		ClientType: c.cfg.ClientType,
 		APIKey:     c.cfg.APIKey,
 		BaseURL:    c.cfg.BaseURL,
+		Headers:    c.cfg.Headers,
 	})
 }
 
@@ -192,7 +194,8 @@ func buildUpdateUserMessage(candidates []adapters.CandidateMemory, facts []strin
       "id" : " ",
       "text" : " ",
       "event" : " ",
-      "old_memory" : " "
+      "old_memory" : " ",
+      "importance" : 0.5
     }
   ]
 }
@@ -204,6 +207,7 @@ Follow the instruction mentioned below:
 - If there is an addition, generate a new key and add the new memory corresponding to it.
 - If there is a deletion, the memory key-value pair should be removed from the memory.
 - If there is an update, the ID key should remain the same and only the value needs to be updated.
+- For ADD and UPDATE events, set "importance" to a number between 0 and 1 estimating how valuable the memory is to keep long-term (stable identity facts and preferences score high, transient details score low).
 
 Do not return anything except the JSON format.
 `)
@@ -237,10 +241,11 @@ func parseJSONStringArray(text string) []string {
 
 // updateResponseEntry mirrors a single item in Mem0's {"memory": [...]} response.
 type updateResponseEntry struct {
-	ID        string `json:"id"`
-	Text      string `json:"text"`
-	Event     string `json:"event"`
-	OldMemory string `json:"old_memory"`
+	ID         string   `json:"id"`
+	Text       string   `json:"text"`
+	Event      string   `json:"event"`
+	OldMemory  string   `json:"old_memory"`
+	Importance *float64 `json:"importance"`
 }
 
 // parseUpdateResponse parses the {"memory": [...]} response from Decide.
@@ -258,10 +263,11 @@ func parseUpdateResponse(text string) []adapters.DecisionAction {
 				event = "NOOP"
 			}
 			actions = append(actions, adapters.DecisionAction{
-				Event:     event,
-				ID:        strings.TrimSpace(entry.ID),
-				Text:      strings.TrimSpace(entry.Text),
-				OldMemory: strings.TrimSpace(entry.OldMemory),
+				Event:      event,
+				ID:         strings.TrimSpace(entry.ID),
+				Text:       strings.TrimSpace(entry.Text),
+				OldMemory:  strings.TrimSpace(entry.OldMemory),
+				Importance: clampImportance(entry.Importance),
 			})
 		}
 		return actions
@@ -269,11 +275,24 @@ func parseUpdateResponse(text string) []adapters.DecisionAction {
 
 	var flat []adapters.DecisionAction
 	if json.Unmarshal([]byte(text), &flat) == nil {
+		for i := range flat {
+			flat[i].Importance = clampImportance(flat[i].Importance)
+		}
 		return flat
 	}
 	return nil
 }
 
+// clampImportance bounds an LLM-provided importance score to [0, 1]. A
+// missing score stays nil.
+func clampImportance(v *float64) *float64 {
+	if v == nil {
+		return nil
+	}
+	clamped := min(max(*v, 0), 1)
+	return &clamped
+}
+
 func extractJSONBlock(text string) string {
 	text = strings.TrimSpace(text)
 	if start := strings.Index(text, "```json"); start >= 0 {
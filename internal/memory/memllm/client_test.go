@@ -114,6 +114,28 @@ func TestParseUpdateResponse_Mem0Format(t *testing.T) {
 	}
 }
 
+func TestParseUpdateResponse_Importance(t *testing.T) {
+	t.Parallel()
+	input := `{"memory": [
+		{"id": "0", "text": "Name is John", "event": "ADD", "importance": 0.9},
+		{"id": "1", "text": "Had toast today", "event": "ADD", "importance": 3},
+		{"id": "2", "text": "Likes tea", "event": "ADD"}
+	]}`
+	result := parseUpdateResponse(input)
+	if len(result) != 3 {
+		t.Fatalf("expected 3 actions, got %d", len(result))
+	}
+	if result[0].Importance == nil || *result[0].Importance != 0.9 {
+		t.Fatalf("expected importance 0.9, got %v", result[0].Importance)
+	}
+	if result[1].Importance == nil || *result[1].Importance != 1 {
+		t.Fatalf("expected out-of-range importance clamped to 1, got %v", result[1].Importance)
+	}
+	if result[2].Importance != nil {
+		t.Fatalf("expected missing importance to be nil, got %v", *result[2].Importance)
+	}
+}
+
 func TestParseUpdateResponse_FlatArrayFallback(t *testing.T) {
 	t.Parallel()
 	input := `[{"event":"ADD","text":"User likes tea"},{"event":"NOOP"},{"event":"DELETE","id":"bot-1:mem_123"}]`
@@ -0,0 +1,62 @@
+package message
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// SetDB enables transactional PersistBatch. Without it PersistBatch writes
+// the messages one by one and stops at the first failure.
+func (s *DBService) SetDB(db *pgxpool.Pool) {
+	s.db = db
+}
+
+// PersistBatch writes messages in order within a single transaction, so
+// either all of them are stored or none are. Created events are published
+// after the commit, in input order, for the messages that were not already
+// stored.
+func (s *DBService) PersistBatch(ctx context.Context, inputs []PersistInput) ([]Message, error) {
+	if len(inputs) == 0 {
+		return nil, nil
+	}
+	if s.db == nil {
+		results := make([]Message, 0, len(inputs))
+		for _, input := range inputs {
+			result, err := s.Persist(ctx, input)
+			if err != nil {
+				return results, err
+			}
+			results = append(results, result)
+		}
+		return results, nil
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin message batch: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	qtx := s.queries.WithTx(tx)
+	results := make([]Message, 0, len(inputs))
+	created := make([]bool, 0, len(inputs))
+	for i, input := range inputs {
+		result, isNew, err := s.insertMessage(ctx, qtx, input)
+		if err != nil {
+			return nil, fmt.Errorf("persist message %d of batch: %w", i, err)
+		}
+		results = append(results, result)
+		created = append(created, isNew)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit message batch: %w", err)
+	}
+	for i, result := range results {
+		if created[i] {
+			s.publishMessageCreated(result)
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,74 @@
+package message
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/memohai/memoh/internal/bots"
+	"github.com/memohai/memoh/internal/db/sqlc"
+)
+
+// PassiveMetadataKey marks a group message that was synced into history
+// without triggering the bot. Only messages carrying it are pruned.
+const PassiveMetadataKey = "passive"
+
+// DefaultPassivePruneInterval is how often passive messages beyond each bot's
+// limit are pruned.
+const DefaultPassivePruneInterval = 10 * time.Minute
+
+// PrunePassive deletes the oldest passive messages in every session of bots
+// that set a passive message limit, keeping the newest ones up to the limit.
+// Sessions of pinned routes are left alone. It returns the number of messages
+// deleted.
+func (s *DBService) PrunePassive(ctx context.Context) (int64, error) {
+	rows, err := s.queries.ListBotsWithPassiveMessageLimit(ctx)
+	if err != nil {
+		return 0, err
+	}
+	var total int64
+	for _, row := range rows {
+		limit := bots.ParseFeatures(row.Metadata).PassiveMessageLimit
+		if limit <= 0 {
+			continue
+		}
+		deleted, err := s.queries.PrunePassiveMessages(ctx, sqlc.PrunePassiveMessagesParams{
+			BotID: row.ID,
+			Keep:  int64(limit),
+		})
+		if err != nil {
+			s.logger.Warn("prune passive messages failed",
+				slog.String("bot_id", row.ID.String()),
+				slog.Any("error", err))
+			continue
+		}
+		total += deleted
+	}
+	return total, nil
+}
+
+// StartPassivePruneLoop periodically prunes passive messages until ctx is done.
+func (s *DBService) StartPassivePruneLoop(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultPassivePruneInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			deleted, err := s.PrunePassive(ctx)
+			if err != nil {
+				s.logger.Warn("list bots for passive message pruning failed", slog.Any("error", err))
+				continue
+			}
+			if deleted > 0 {
+				s.logger.Info("pruned passive messages", slog.Int64("deleted", deleted))
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package message_test
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/memohai/memoh/internal/db/sqlc"
+	"github.com/memohai/memoh/internal/message"
+)
+
+func TestPrunePassiveSkipsPinnedRoutes(t *testing.T) {
+	dsn := os.Getenv("TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("skip integration test: TEST_POSTGRES_DSN is not set")
+	}
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		t.Skipf("skip integration test: cannot connect to database: %v", err)
+	}
+	defer pool.Close()
+	if err := pool.Ping(ctx); err != nil {
+		t.Skipf("skip integration test: database ping failed: %v", err)
+	}
+	queries := sqlc.New(pool)
+	svc := message.NewService(slog.New(slog.DiscardHandler), queries)
+
+	user, err := queries.CreateUser(ctx, sqlc.CreateUserParams{IsActive: true, Metadata: []byte("{}")})
+	if err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	defer func() { _, _ = pool.Exec(ctx, "DELETE FROM users WHERE id = $1", user.ID) }()
+	bot, err := queries.CreateBot(ctx, sqlc.CreateBotParams{
+		OwnerUserID: user.ID,
+		DisplayName: pgtype.Text{String: "passive-pin-test-bot", Valid: true},
+		IsActive:    true,
+		Metadata:    []byte(`{"features":{"passive_message_limit":1}}`),
+		Status:      "ready",
+	})
+	if err != nil {
+		t.Fatalf("create bot: %v", err)
+	}
+	defer func() { _, _ = pool.Exec(ctx, "DELETE FROM bots WHERE id = $1", bot.ID) }()
+
+	// seedSession creates a route and session holding three passive messages.
+	seedSession := func(conversationID string, pinned bool) pgtype.UUID {
+		t.Helper()
+		rt, err := queries.CreateChatRoute(ctx, sqlc.CreateChatRouteParams{
+			ChatID:         bot.ID,
+			BotID:          bot.ID,
+			Platform:       "telegram",
+			ConversationID: conversationID,
+			Metadata:       []byte("{}"),
+		})
+		if err != nil {
+			t.Fatalf("create route: %v", err)
+		}
+		if err := queries.SetChatRoutePinned(ctx, sqlc.SetChatRoutePinnedParams{ID: rt.ID, Pinned: pinned}); err != nil {
+			t.Fatalf("pin route: %v", err)
+		}
+		session, err := queries.CreateSession(ctx, sqlc.CreateSessionParams{
+			BotID:    bot.ID,
+			RouteID:  rt.ID,
+			Type:     "chat",
+			Metadata: []byte("{}"),
+		})
+		if err != nil {
+			t.Fatalf("create session: %v", err)
+		}
+		for range 3 {
+			if _, err := queries.CreateMessage(ctx, sqlc.CreateMessageParams{
+				BotID:     bot.ID,
+				SessionID: session.ID,
+				Role:      "user",
+				Content:   []byte(`{"text":"hi"}`),
+				Metadata:  []byte(`{"passive":true}`),
+			}); err != nil {
+				t.Fatalf("create message: %v", err)
+			}
+		}
+		return session.ID
+	}
+	pinnedSession := seedSession("pinned", true)
+	plainSession := seedSession("plain", false)
+
+	if _, err := svc.PrunePassive(ctx); err != nil {
+		t.Fatalf("prune passive messages: %v", err)
+	}
+
+	countMessages := func(sessionID pgtype.UUID) int {
+		t.Helper()
+		var count int
+		if err := pool.QueryRow(ctx, "SELECT count(*) FROM bot_history_messages WHERE session_id = $1", sessionID).Scan(&count); err != nil {
+			t.Fatalf("count messages: %v", err)
+		}
+		return count
+	}
+	if got := countMessages(pinnedSession); got != 3 {
+		t.Fatalf("expected pinned session to keep 3 messages, got %d", got)
+	}
+	if got := countMessages(plainSession); got != 1 {
+		t.Fatalf("expected unpinned session to be pruned to 1 message, got %d", got)
+	}
+}
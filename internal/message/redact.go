@@ -0,0 +1,350 @@
+package message
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	dbpkg "github.com/memohai/memoh/internal/db"
+	"github.com/memohai/memoh/internal/db/sqlc"
+)
+
+const (
+	// RedactionMarker replaces redacted message text.
+	RedactionMarker = "[redacted]"
+	// RedactedMetadataKey is the message metadata key recording who redacted
+	// a message, when and how.
+	RedactedMetadataKey = "redacted"
+)
+
+// ErrMessageNotFound is returned when a message does not exist for the bot.
+var ErrMessageNotFound = errors.New("message not found")
+
+// RedactInput describes a redaction of a stored message.
+type RedactInput struct {
+	// Phrases are the texts replaced by RedactionMarker wherever they occur in
+	// the message. When empty the whole message content is redacted.
+	Phrases []string
+	// RedactedBy identifies who requested the redaction.
+	RedactedBy string
+	Reason     string
+}
+
+// Redact replaces content of a stored message with RedactionMarker while
+// keeping the row, and records the redaction in the message metadata. The
+// same redaction is applied to the message's metadata, its tool call records
+// and the session event it was stored from. Fully redacted messages are left
+// out of the agent's context.
+func (s *DBService) Redact(ctx context.Context, botID, messageID string, input RedactInput) (Message, error) {
+	pgBotID, err := dbpkg.ParseUUID(botID)
+	if err != nil {
+		return Message{}, fmt.Errorf("invalid bot id: %w", err)
+	}
+	pgMessageID, err := dbpkg.ParseUUID(messageID)
+	if err != nil {
+		return Message{}, fmt.Errorf("invalid message id: %w", err)
+	}
+	if s.db == nil {
+		return s.redact(ctx, s.queries, pgBotID, pgMessageID, input)
+	}
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return Message{}, fmt.Errorf("begin message redaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+	result, err := s.redact(ctx, s.queries.WithTx(tx), pgBotID, pgMessageID, input)
+	if err != nil {
+		return Message{}, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return Message{}, fmt.Errorf("commit message redaction: %w", err)
+	}
+	return result, nil
+}
+
+func (*DBService) redact(ctx context.Context, q *sqlc.Queries, pgBotID, pgMessageID pgtype.UUID, input RedactInput) (Message, error) {
+	row, err := q.GetMessageByID(ctx, sqlc.GetMessageByIDParams{ID: pgMessageID, BotID: pgBotID})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Message{}, ErrMessageNotFound
+	}
+	if err != nil {
+		return Message{}, err
+	}
+
+	phrases := make([]string, 0, len(input.Phrases))
+	for _, phrase := range input.Phrases {
+		if phrase = strings.TrimSpace(phrase); phrase != "" {
+			phrases = append(phrases, phrase)
+		}
+	}
+	full := len(phrases) == 0
+
+	var content []byte
+	var displayText string
+	if full {
+		content, err = redactWholeContent(row.Content, row.Role)
+		if err != nil {
+			return Message{}, fmt.Errorf("redact message content: %w", err)
+		}
+		if row.DisplayText.Valid {
+			displayText = RedactionMarker
+		}
+	} else {
+		content, err = redactJSONStrings(row.Content, phrases)
+		if err != nil {
+			return Message{}, fmt.Errorf("redact message content: %w", err)
+		}
+		displayText = RedactText(dbpkg.TextToString(row.DisplayText), phrases)
+	}
+
+	record := map[string]any{
+		"at":   time.Now().UTC().Format(time.RFC3339),
+		"full": full,
+	}
+	if by := strings.TrimSpace(input.RedactedBy); by != "" {
+		record["by"] = by
+	}
+	if reason := strings.TrimSpace(input.Reason); reason != "" {
+		record["reason"] = reason
+	}
+	patch := redactMetadata(row.Metadata, phrases)
+	patch[RedactedMetadataKey] = record
+	metadata, err := json.Marshal(patch)
+	if err != nil {
+		return Message{}, err
+	}
+
+	updated, err := q.RedactMessage(ctx, sqlc.RedactMessageParams{
+		Content:     content,
+		DisplayText: toPgText(displayText),
+		Metadata:    metadata,
+		ID:          pgMessageID,
+		BotID:       pgBotID,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Message{}, ErrMessageNotFound
+	}
+	if err != nil {
+		return Message{}, err
+	}
+	if err := redactToolCalls(ctx, q, pgMessageID, phrases); err != nil {
+		return Message{}, err
+	}
+	if row.EventID.Valid {
+		if err := redactSessionEvent(ctx, q, row.EventID, phrases); err != nil {
+			return Message{}, err
+		}
+	}
+	return toMessageFromCreate(sqlc.CreateMessageRow(updated)), nil
+}
+
+// redactMetadata returns the metadata keys to overwrite for a redaction. With
+// phrases every string value is redacted; without them only the values that
+// repeat message content, such as the reasoning trace, are replaced.
+func redactMetadata(raw []byte, phrases []string) map[string]any {
+	var stored map[string]any
+	if len(raw) == 0 || json.Unmarshal(raw, &stored) != nil || stored == nil {
+		return map[string]any{}
+	}
+	delete(stored, RedactedMetadataKey)
+	if len(phrases) > 0 {
+		return redactValue(stored, phrases).(map[string]any)
+	}
+	patch := map[string]any{}
+	for _, key := range contentMetadataKeys {
+		if _, ok := stored[key]; ok {
+			patch[key] = RedactionMarker
+		}
+	}
+	return patch
+}
+
+// contentMetadataKeys are the message metadata keys that hold message
+// content.
+var contentMetadataKeys = []string{"reasoning"}
+
+// redactToolCalls applies a redaction to the tool call records of a message.
+// Without phrases their arguments and results are cleared.
+func redactToolCalls(ctx context.Context, q *sqlc.Queries, messageID pgtype.UUID, phrases []string) error {
+	calls, err := q.ListToolCallsByMessage(ctx, messageID)
+	if err != nil {
+		return fmt.Errorf("list message tool calls: %w", err)
+	}
+	for _, call := range calls {
+		arguments, result := []byte(`{}`), []byte(`"`+RedactionMarker+`"`)
+		if len(phrases) > 0 {
+			if arguments, err = redactJSONStrings(call.Arguments, phrases); err != nil {
+				return fmt.Errorf("redact tool call arguments: %w", err)
+			}
+			if result, err = redactJSONStrings(call.Result, phrases); err != nil {
+				return fmt.Errorf("redact tool call result: %w", err)
+			}
+		}
+		if err := q.RedactMessageToolCall(ctx, sqlc.RedactMessageToolCallParams{
+			Arguments: arguments,
+			Result:    result,
+			ID:        call.ID,
+		}); err != nil {
+			return fmt.Errorf("redact tool call: %w", err)
+		}
+	}
+	return nil
+}
+
+// redactSessionEvent applies a redaction to the session event a message was
+// stored from, so replaying the session does not bring the content back.
+// Without phrases the event's content and attachments are replaced.
+func redactSessionEvent(ctx context.Context, q *sqlc.Queries, eventID pgtype.UUID, phrases []string) error {
+	event, err := q.GetSessionEventData(ctx, eventID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("load session event: %w", err)
+	}
+	var data []byte
+	if len(phrases) > 0 {
+		data, err = redactJSONStrings(event.EventData, phrases)
+	} else {
+		data, err = redactWholeEvent(event.EventData)
+	}
+	if err != nil {
+		return fmt.Errorf("redact session event: %w", err)
+	}
+	if err := q.UpdateSessionEventData(ctx, sqlc.UpdateSessionEventDataParams{ID: eventID, EventData: data}); err != nil {
+		return fmt.Errorf("update session event: %w", err)
+	}
+	return nil
+}
+
+// redactWholeEvent replaces the content of a stored session event with
+// RedactionMarker and drops its attachments and reply preview.
+func redactWholeEvent(raw []byte) ([]byte, error) {
+	var doc map[string]any
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, err
+	}
+	doc["content"] = []any{map[string]any{"type": "text", "text": RedactionMarker}}
+	if _, ok := doc["attachments"]; ok {
+		doc["attachments"] = []any{}
+	}
+	delete(doc, "reply_to_preview")
+	return json.Marshal(doc)
+}
+
+// FullyRedacted reports whether message metadata records a redaction of the
+// whole message.
+func FullyRedacted(metadata map[string]any) bool {
+	record, ok := metadata[RedactedMetadataKey].(map[string]any)
+	if !ok {
+		return false
+	}
+	full, _ := record["full"].(bool)
+	return full
+}
+
+// redactWholeContent replaces the content of a stored model message with
+// RedactionMarker. Tool call and tool result parts keep their IDs and tool
+// names, with inputs and outputs cleared, so that tool calls and the results
+// answering them stay paired for providers.
+func redactWholeContent(raw []byte, role string) ([]byte, error) {
+	redacted := map[string]any{"role": role, "content": RedactionMarker}
+	var stored map[string]any
+	if len(raw) == 0 || json.Unmarshal(raw, &stored) != nil {
+		return json.Marshal(redacted)
+	}
+	for _, key := range []string{"tool_call_id", "name"} {
+		if value, ok := stored[key]; ok {
+			redacted[key] = value
+		}
+	}
+	if calls, ok := stored["tool_calls"].([]any); ok {
+		for _, call := range calls {
+			if fields, ok := call.(map[string]any); ok {
+				if fn, ok := fields["function"].(map[string]any); ok {
+					fn["arguments"] = "{}"
+				}
+			}
+		}
+		redacted["tool_calls"] = calls
+	}
+	if parts, ok := stored["content"].([]any); ok {
+		kept := []any{map[string]any{"type": "text", "text": RedactionMarker}}
+		for _, item := range parts {
+			part, ok := item.(map[string]any)
+			if !ok {
+				continue
+			}
+			switch part["type"] {
+			case "tool-call":
+				kept = append(kept, map[string]any{
+					"type":       "tool-call",
+					"toolCallId": part["toolCallId"],
+					"toolName":   part["toolName"],
+					"input":      map[string]any{},
+				})
+			case "tool-result":
+				kept = append(kept, map[string]any{
+					"type":       "tool-result",
+					"toolCallId": part["toolCallId"],
+					"toolName":   part["toolName"],
+					"output":     map[string]any{"type": "text", "value": RedactionMarker},
+				})
+			}
+		}
+		redacted["content"] = kept
+	}
+	return json.Marshal(redacted)
+}
+
+// redactJSONStrings replaces phrases in the string values of a JSON
+// document, leaving object keys, roles and part types untouched.
+func redactJSONStrings(raw []byte, phrases []string) ([]byte, error) {
+	if len(raw) == 0 {
+		return raw, nil
+	}
+	var doc any
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, err
+	}
+	return json.Marshal(redactValue(doc, phrases))
+}
+
+func redactValue(value any, phrases []string) any {
+	switch v := value.(type) {
+	case string:
+		return RedactText(v, phrases)
+	case []any:
+		for i := range v {
+			v[i] = redactValue(v[i], phrases)
+		}
+		return v
+	case map[string]any:
+		for k := range v {
+			if k == "role" || k == "type" {
+				continue
+			}
+			v[k] = redactValue(v[k], phrases)
+		}
+		return v
+	default:
+		return value
+	}
+}
+
+// RedactText replaces every occurrence of phrases in text with
+// RedactionMarker. Blank phrases are ignored.
+func RedactText(text string, phrases []string) string {
+	for _, phrase := range phrases {
+		if phrase = strings.TrimSpace(phrase); phrase != "" {
+			text = strings.ReplaceAll(text, phrase, RedactionMarker)
+		}
+	}
+	return text
+}
@@ -3,13 +3,16 @@ package message
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"math"
 	"strings"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
 
 	dbpkg "github.com/memohai/memoh/internal/db"
 	"github.com/memohai/memoh/internal/db/sqlc"
@@ -19,6 +22,7 @@ import (
 // DBService persists and reads bot history messages.
 type DBService struct {
 	queries   *sqlc.Queries
+	db        *pgxpool.Pool
 	logger    *slog.Logger
 	publisher event.Publisher
 }
@@ -39,37 +43,58 @@ func NewService(log *slog.Logger, queries *sqlc.Queries, publishers ...event.Pub
 	}
 }
 
-// Persist writes a single message to bot_history_messages.
+// Persist writes a single message to bot_history_messages. Connection
+// errors are retried briefly so a Postgres restart does not lose the message.
 func (s *DBService) Persist(ctx context.Context, input PersistInput) (Message, error) {
+	var created bool
+	result, err := dbpkg.Retry(ctx, func(ctx context.Context) (Message, error) {
+		msg, isNew, err := s.insertMessage(ctx, s.queries, input)
+		created = isNew
+		return msg, err
+	})
+	if err != nil {
+		return Message{}, err
+	}
+	if created {
+		s.publishMessageCreated(result)
+	}
+	return result, nil
+}
+
+// insertMessage writes a message and its asset links through q without
+// publishing it. A message whose external message ID, platform and role are
+// already stored in the same session is not written again; the stored one
+// is returned with created false.
+func (s *DBService) insertMessage(ctx context.Context, q *sqlc.Queries, input PersistInput) (Message, bool, error) {
 	pgBotID, err := dbpkg.ParseUUID(input.BotID)
 	if err != nil {
-		return Message{}, fmt.Errorf("invalid bot id: %w", err)
+		return Message{}, false, fmt.Errorf("invalid bot id: %w", err)
 	}
 
 	pgSessionID, err := parseOptionalUUID(input.SessionID)
 	if err != nil {
-		return Message{}, fmt.Errorf("invalid session id: %w", err)
+		return Message{}, false, fmt.Errorf("invalid session id: %w", err)
 	}
 	pgSenderChannelIdentityID, err := parseOptionalUUID(input.SenderChannelIdentityID)
 	if err != nil {
-		return Message{}, fmt.Errorf("invalid sender channel identity id: %w", err)
+		return Message{}, false, fmt.Errorf("invalid sender channel identity id: %w", err)
 	}
 	pgSenderUserID, err := parseOptionalUUID(input.SenderUserID)
 	if err != nil {
-		return Message{}, fmt.Errorf("invalid sender user id: %w", err)
+		return Message{}, false, fmt.Errorf("invalid sender user id: %w", err)
 	}
 	pgModelID, err := parseOptionalUUID(input.ModelID)
 	if err != nil {
-		return Message{}, fmt.Errorf("invalid model id: %w", err)
+		return Message{}, false, fmt.Errorf("invalid model id: %w", err)
 	}
 	pgEventID, err := parseOptionalUUID(input.EventID)
 	if err != nil {
-		return Message{}, fmt.Errorf("invalid event id: %w", err)
+		return Message{}, false, fmt.Errorf("invalid event id: %w", err)
 	}
 
 	metaBytes, err := json.Marshal(nonNilMap(input.Metadata))
 	if err != nil {
-		return Message{}, fmt.Errorf("marshal message metadata: %w", err)
+		return Message{}, false, fmt.Errorf("marshal message metadata: %w", err)
 	}
 
 	content := input.Content
@@ -77,7 +102,7 @@ func (s *DBService) Persist(ctx context.Context, input PersistInput) (Message, e
 		content = []byte("{}")
 	}
 
-	row, err := s.queries.CreateMessage(ctx, sqlc.CreateMessageParams{
+	row, err := q.CreateMessage(ctx, sqlc.CreateMessageParams{
 		BotID:                   pgBotID,
 		SessionID:               pgSessionID,
 		SenderChannelIdentityID: pgSenderChannelIdentityID,
@@ -92,8 +117,21 @@ func (s *DBService) Persist(ctx context.Context, input PersistInput) (Message, e
 		EventID:                 pgEventID,
 		DisplayText:             toPgText(input.DisplayText),
 	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		existing, getErr := q.GetMessageByExternalID(ctx, sqlc.GetMessageByExternalIDParams{
+			BotID:             pgBotID,
+			SessionID:         pgSessionID,
+			Platform:          platformFromMetadata(input.Metadata),
+			ExternalMessageID: strings.TrimSpace(input.ExternalMessageID),
+			Role:              input.Role,
+		})
+		if getErr != nil {
+			return Message{}, false, fmt.Errorf("load duplicate message: %w", getErr)
+		}
+		return toMessageFromCreate(sqlc.CreateMessageRow(existing)), false, nil
+	}
 	if err != nil {
-		return Message{}, err
+		return Message{}, false, err
 	}
 
 	result := toMessageFromCreate(row)
@@ -102,7 +140,7 @@ func (s *DBService) Persist(ctx context.Context, input PersistInput) (Message, e
 		pgMsgID := row.ID
 		role := ref.Role
 		if strings.TrimSpace(role) == "" {
-			role = "attachment"
+			role = DefaultAssetRole
 		}
 		contentHash := strings.TrimSpace(ref.ContentHash)
 		if contentHash == "" {
@@ -110,9 +148,9 @@ func (s *DBService) Persist(ctx context.Context, input PersistInput) (Message, e
 			continue
 		}
 		if ref.Ordinal < math.MinInt32 || ref.Ordinal > math.MaxInt32 {
-			return Message{}, fmt.Errorf("asset ordinal out of range: %d", ref.Ordinal)
+			return Message{}, false, fmt.Errorf("asset ordinal out of range: %d", ref.Ordinal)
 		}
-		if _, assetErr := s.queries.CreateMessageAsset(ctx, sqlc.CreateMessageAssetParams{
+		if _, assetErr := q.CreateMessageAsset(ctx, sqlc.CreateMessageAssetParams{
 			MessageID:   pgMsgID,
 			Role:        role,
 			Ordinal:     int32(ref.Ordinal),
@@ -133,7 +171,7 @@ func (s *DBService) Persist(ctx context.Context, input PersistInput) (Message, e
 			}
 			assets = append(assets, MessageAsset{
 				ContentHash: ch,
-				Role:        coalesce(ref.Role, "attachment"),
+				Role:        coalesce(ref.Role, DefaultAssetRole),
 				Ordinal:     ref.Ordinal,
 				Mime:        ref.Mime,
 				SizeBytes:   ref.SizeBytes,
@@ -144,9 +182,14 @@ func (s *DBService) Persist(ctx context.Context, input PersistInput) (Message, e
 		}
 		result.Assets = assets
 	}
+	return result, true, nil
+}
 
-	s.publishMessageCreated(result)
-	return result, nil
+// platformFromMetadata returns the platform recorded in message metadata, or
+// "" when there is none.
+func platformFromMetadata(metadata map[string]any) string {
+	platform, _ := metadata["platform"].(string)
+	return platform
 }
 
 // List returns all messages for a bot.
@@ -161,6 +204,7 @@ func (s *DBService) List(ctx context.Context, botID string) ([]Message, error) {
 	}
 	msgs := toMessagesFromList(rows)
 	s.enrichAssets(ctx, msgs)
+	s.enrichDeliveries(ctx, msgs)
 	return msgs, nil
 }
 
@@ -179,6 +223,7 @@ func (s *DBService) ListSince(ctx context.Context, botID string, since time.Time
 	}
 	msgs := toMessagesFromSince(rows)
 	s.enrichAssets(ctx, msgs)
+	s.enrichDeliveries(ctx, msgs)
 	return msgs, nil
 }
 
@@ -197,6 +242,7 @@ func (s *DBService) ListActiveSince(ctx context.Context, botID string, since tim
 	}
 	msgs := toMessagesFromActiveSince(rows)
 	s.enrichAssets(ctx, msgs)
+	s.enrichDeliveries(ctx, msgs)
 	return msgs, nil
 }
 
@@ -215,6 +261,7 @@ func (s *DBService) ListLatest(ctx context.Context, botID string, limit int32) (
 	}
 	msgs := toMessagesFromLatest(rows)
 	s.enrichAssets(ctx, msgs)
+	s.enrichDeliveries(ctx, msgs)
 	return msgs, nil
 }
 
@@ -234,6 +281,7 @@ func (s *DBService) ListBefore(ctx context.Context, botID string, before time.Ti
 	}
 	msgs := toMessagesFromBefore(rows)
 	s.enrichAssets(ctx, msgs)
+	s.enrichDeliveries(ctx, msgs)
 	return msgs, nil
 }
 
@@ -251,6 +299,7 @@ func (s *DBService) ListBySession(ctx context.Context, sessionID string) ([]Mess
 	}
 	msgs := toMessagesFromSessionList(rows)
 	s.enrichAssets(ctx, msgs)
+	s.enrichDeliveries(ctx, msgs)
 	return msgs, nil
 }
 
@@ -269,6 +318,7 @@ func (s *DBService) ListSinceBySession(ctx context.Context, sessionID string, si
 	}
 	msgs := toMessagesFromSinceBySession(rows)
 	s.enrichAssets(ctx, msgs)
+	s.enrichDeliveries(ctx, msgs)
 	return msgs, nil
 }
 
@@ -287,6 +337,7 @@ func (s *DBService) ListActiveSinceBySession(ctx context.Context, sessionID stri
 	}
 	msgs := toMessagesFromActiveSinceBySession(rows)
 	s.enrichAssets(ctx, msgs)
+	s.enrichDeliveries(ctx, msgs)
 	return msgs, nil
 }
 
@@ -305,6 +356,7 @@ func (s *DBService) ListLatestBySession(ctx context.Context, sessionID string, l
 	}
 	msgs := toMessagesFromLatestBySession(rows)
 	s.enrichAssets(ctx, msgs)
+	s.enrichDeliveries(ctx, msgs)
 	return msgs, nil
 }
 
@@ -324,6 +376,7 @@ func (s *DBService) ListBeforeBySession(ctx context.Context, sessionID string, b
 	}
 	msgs := toMessagesFromBeforeBySession(rows)
 	s.enrichAssets(ctx, msgs)
+	s.enrichDeliveries(ctx, msgs)
 	return msgs, nil
 }
 
@@ -340,7 +393,7 @@ func (s *DBService) LinkAssets(ctx context.Context, messageID string, assets []A
 		}
 		role := ref.Role
 		if strings.TrimSpace(role) == "" {
-			role = "attachment"
+			role = DefaultAssetRole
 		}
 		if ref.Ordinal < math.MinInt32 || ref.Ordinal > math.MaxInt32 {
 			return fmt.Errorf("asset ordinal out of range: %d", ref.Ordinal)
@@ -865,6 +918,44 @@ func (s *DBService) enrichAssets(ctx context.Context, messages []Message) {
 	}
 }
 
+// enrichDeliveries batch-loads outbound delivery receipts for a list of messages.
+func (s *DBService) enrichDeliveries(ctx context.Context, messages []Message) {
+	if len(messages) == 0 {
+		return
+	}
+	ids := make([]pgtype.UUID, 0, len(messages))
+	for _, m := range messages {
+		pgID, err := dbpkg.ParseUUID(m.ID)
+		if err != nil {
+			continue
+		}
+		ids = append(ids, pgID)
+	}
+	if len(ids) == 0 {
+		return
+	}
+	rows, err := s.queries.ListDeliveryReceiptsBatch(ctx, ids)
+	if err != nil {
+		s.logger.Warn("enrich deliveries failed, returning messages without receipts", slog.Any("error", err))
+		return
+	}
+	receiptMap := map[string][]DeliveryReceipt{}
+	for _, row := range rows {
+		msgID := row.MessageID.String()
+		receiptMap[msgID] = append(receiptMap[msgID], DeliveryReceipt{
+			Platform:          row.ChannelType,
+			Target:            row.Target,
+			PlatformMessageID: row.PlatformMessageID,
+			Status:            row.Status,
+			Error:             row.Error,
+			At:                dbpkg.TimeFromPg(row.CreatedAt),
+		})
+	}
+	for i := range messages {
+		messages[i].Deliveries = receiptMap[messages[i].ID]
+	}
+}
+
 func ensureAssetsSlice(messages []Message) {
 	for i := range messages {
 		if messages[i].Assets == nil {
@@ -0,0 +1,496 @@
+package message
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/memohai/memoh/internal/db/sqlc"
+	"github.com/memohai/memoh/internal/message/event"
+)
+
+// historyDB stores bot_history_messages rows in memory and enforces the
+// external message dedup index the way CreateMessage's ON CONFLICT does.
+type historyDB struct {
+	rows   []historyRow
+	nextID byte
+	// unavailable fails that many CreateMessage calls as if Postgres were
+	// restarting.
+	unavailable int
+	toolCalls   []toolCallRow
+	events      map[pgtype.UUID][]byte
+}
+
+type toolCallRow struct {
+	id        pgtype.UUID
+	messageID pgtype.UUID
+	arguments []byte
+	result    []byte
+}
+
+type historyRow struct {
+	id          pgtype.UUID
+	botID       pgtype.UUID
+	sessionID   pgtype.UUID
+	platform    string
+	externalID  pgtype.Text
+	role        string
+	content     []byte
+	metadata    []byte
+	eventID     pgtype.UUID
+	displayText pgtype.Text
+}
+
+func (f *historyDB) Exec(_ context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	switch {
+	case strings.Contains(sql, "name: RedactMessageToolCall "):
+		for i, call := range f.toolCalls {
+			if call.id == args[2].(pgtype.UUID) {
+				f.toolCalls[i].arguments = args[0].([]byte)
+				f.toolCalls[i].result = args[1].([]byte)
+			}
+		}
+		return pgconn.CommandTag{}, nil
+	case strings.Contains(sql, "name: UpdateSessionEventData "):
+		f.events[args[0].(pgtype.UUID)] = args[1].([]byte)
+		return pgconn.CommandTag{}, nil
+	}
+	return pgconn.CommandTag{}, errors.New("not implemented")
+}
+
+func (f *historyDB) Query(_ context.Context, sql string, args ...any) (pgx.Rows, error) {
+	if !strings.Contains(sql, "name: ListToolCallsByMessage ") {
+		return nil, errors.New("not implemented")
+	}
+	rows := &toolCallRows{}
+	for _, call := range f.toolCalls {
+		if call.messageID == args[0].(pgtype.UUID) {
+			rows.calls = append(rows.calls, call)
+		}
+	}
+	return rows, nil
+}
+
+type toolCallRows struct {
+	calls []toolCallRow
+	idx   int
+}
+
+func (*toolCallRows) Close()                                       {}
+func (*toolCallRows) Err() error                                   { return nil }
+func (*toolCallRows) CommandTag() pgconn.CommandTag                { return pgconn.CommandTag{} }
+func (*toolCallRows) FieldDescriptions() []pgconn.FieldDescription { return nil }
+func (r *toolCallRows) Next() bool {
+	if r.idx >= len(r.calls) {
+		return false
+	}
+	r.idx++
+	return true
+}
+
+func (r *toolCallRows) Scan(dest ...any) error {
+	call := r.calls[r.idx-1]
+	*dest[0].(*pgtype.UUID) = call.id
+	*dest[1].(*[]byte) = call.arguments
+	*dest[2].(*[]byte) = call.result
+	return nil
+}
+func (*toolCallRows) Values() ([]any, error) { return nil, nil }
+func (*toolCallRows) RawValues() [][]byte    { return nil }
+func (*toolCallRows) Conn() *pgx.Conn        { return nil }
+
+func (f *historyDB) QueryRow(_ context.Context, sql string, args ...any) pgx.Row {
+	switch {
+	case strings.Contains(sql, "name: CreateMessage "):
+		if f.unavailable > 0 {
+			f.unavailable--
+			return historyScan{err: &pgconn.PgError{Code: "57P03", Message: "the database system is starting up"}}
+		}
+		row := historyRow{
+			botID:       args[0].(pgtype.UUID),
+			sessionID:   args[1].(pgtype.UUID),
+			externalID:  args[4].(pgtype.Text),
+			role:        args[6].(string),
+			content:     args[7].([]byte),
+			metadata:    args[8].([]byte),
+			eventID:     args[11].(pgtype.UUID),
+			displayText: args[12].(pgtype.Text),
+		}
+		var meta map[string]any
+		_ = json.Unmarshal(row.metadata, &meta)
+		row.platform, _ = meta["platform"].(string)
+		if row.sessionID.Valid && row.externalID.Valid {
+			if _, ok := f.find(row.botID, row.sessionID, row.platform, row.externalID.String, row.role); ok {
+				return historyScan{err: pgx.ErrNoRows}
+			}
+		}
+		f.nextID++
+		row.id = pgtype.UUID{Valid: true}
+		row.id.Bytes[15] = f.nextID
+		f.rows = append(f.rows, row)
+		return historyScan{row: row}
+	case strings.Contains(sql, "name: GetMessageByID "):
+		for _, row := range f.rows {
+			if row.id == args[0].(pgtype.UUID) && row.botID == args[1].(pgtype.UUID) {
+				return historyScan{row: row}
+			}
+		}
+		return historyScan{err: pgx.ErrNoRows}
+	case strings.Contains(sql, "name: RedactMessage "):
+		for i, row := range f.rows {
+			if row.id != args[3].(pgtype.UUID) || row.botID != args[4].(pgtype.UUID) {
+				continue
+			}
+			var meta, patch map[string]any
+			_ = json.Unmarshal(row.metadata, &meta)
+			_ = json.Unmarshal(args[2].([]byte), &patch)
+			if meta == nil {
+				meta = map[string]any{}
+			}
+			for k, v := range patch {
+				meta[k] = v
+			}
+			row.metadata, _ = json.Marshal(meta)
+			row.content = args[0].([]byte)
+			row.displayText = args[1].(pgtype.Text)
+			f.rows[i] = row
+			return historyScan{row: row}
+		}
+		return historyScan{err: pgx.ErrNoRows}
+	case strings.Contains(sql, "name: GetSessionEventData "):
+		data, ok := f.events[args[0].(pgtype.UUID)]
+		if !ok {
+			return historyScan{err: pgx.ErrNoRows}
+		}
+		return eventScan{data: data}
+	case strings.Contains(sql, "name: GetMessageByExternalID "):
+		row, ok := f.find(args[0].(pgtype.UUID), args[1].(pgtype.UUID), args[2].(string), args[3].(string), args[4].(string))
+		if !ok {
+			return historyScan{err: pgx.ErrNoRows}
+		}
+		return historyScan{row: row}
+	}
+	return historyScan{err: fmt.Errorf("unexpected query: %s", sql)}
+}
+
+func (f *historyDB) find(botID, sessionID pgtype.UUID, platform, externalID, role string) (historyRow, bool) {
+	for _, row := range f.rows {
+		if row.botID == botID && row.sessionID == sessionID && row.platform == platform && row.externalID.String == externalID && row.role == role {
+			return row, true
+		}
+	}
+	return historyRow{}, false
+}
+
+type historyScan struct {
+	row historyRow
+	err error
+}
+
+func (s historyScan) Scan(dest ...any) error {
+	if s.err != nil {
+		return s.err
+	}
+	*dest[0].(*pgtype.UUID) = s.row.id
+	*dest[1].(*pgtype.UUID) = s.row.botID
+	*dest[2].(*pgtype.UUID) = s.row.sessionID
+	*dest[5].(*pgtype.Text) = s.row.externalID
+	*dest[7].(*string) = s.row.role
+	*dest[8].(*[]byte) = s.row.content
+	*dest[9].(*[]byte) = s.row.metadata
+	*dest[11].(*pgtype.UUID) = s.row.eventID
+	*dest[12].(*pgtype.Text) = s.row.displayText
+	return nil
+}
+
+type eventScan struct {
+	data []byte
+}
+
+func (s eventScan) Scan(dest ...any) error {
+	*dest[1].(*[]byte) = s.data
+	return nil
+}
+
+type countingPublisher struct {
+	events []event.Event
+}
+
+func (p *countingPublisher) Publish(e event.Event) {
+	p.events = append(p.events, e)
+}
+
+const (
+	testBotID      = "11111111-1111-1111-1111-111111111111"
+	testSessionID  = "22222222-2222-2222-2222-222222222222"
+	otherSessionID = "33333333-3333-3333-3333-333333333333"
+)
+
+func TestPersistSameExternalMessageTwiceStoresOneRow(t *testing.T) {
+	db := &historyDB{}
+	publisher := &countingPublisher{}
+	svc := NewService(slog.New(slog.DiscardHandler), sqlc.New(db), publisher)
+
+	input := PersistInput{
+		BotID:             testBotID,
+		SessionID:         testSessionID,
+		ExternalMessageID: "msg-1",
+		Role:              "user",
+		Content:           []byte(`{"role":"user","content":"hi"}`),
+		Metadata:          map[string]any{"platform": "telegram"},
+	}
+	first, err := svc.Persist(context.Background(), input)
+	if err != nil {
+		t.Fatalf("first persist: %v", err)
+	}
+	second, err := svc.Persist(context.Background(), input)
+	if err != nil {
+		t.Fatalf("second persist: %v", err)
+	}
+
+	if len(db.rows) != 1 {
+		t.Fatalf("stored rows = %d, want 1", len(db.rows))
+	}
+	if first.ID == "" || second.ID != first.ID {
+		t.Fatalf("re-delivered message returned id %q, want %q", second.ID, first.ID)
+	}
+	if len(publisher.events) != 1 {
+		t.Fatalf("published events = %d, want 1", len(publisher.events))
+	}
+}
+
+func TestPersistKeepsDistinctExternalMessages(t *testing.T) {
+	db := &historyDB{}
+	svc := NewService(slog.New(slog.DiscardHandler), sqlc.New(db))
+
+	inputs := []PersistInput{
+		{BotID: testBotID, SessionID: testSessionID, ExternalMessageID: "msg-1", Role: "user", Metadata: map[string]any{"platform": "telegram"}},
+		{BotID: testBotID, SessionID: testSessionID, ExternalMessageID: "msg-1", Role: "user", Metadata: map[string]any{"platform": "discord"}},
+		{BotID: testBotID, SessionID: testSessionID, ExternalMessageID: "msg-1", Role: "assistant", Metadata: map[string]any{"platform": "telegram"}},
+		{BotID: testBotID, SessionID: otherSessionID, ExternalMessageID: "msg-1", Role: "user", Metadata: map[string]any{"platform": "telegram"}},
+		{BotID: testBotID, Role: "assistant"},
+		{BotID: testBotID, Role: "assistant"},
+	}
+	for i, input := range inputs {
+		if _, err := svc.Persist(context.Background(), input); err != nil {
+			t.Fatalf("persist %d: %v", i, err)
+		}
+	}
+	if len(db.rows) != len(inputs) {
+		t.Fatalf("stored rows = %d, want %d", len(db.rows), len(inputs))
+	}
+}
+
+func TestPersistRetriesWhileDatabaseRestarts(t *testing.T) {
+	db := &historyDB{unavailable: 1}
+	publisher := &countingPublisher{}
+	svc := NewService(slog.New(slog.DiscardHandler), sqlc.New(db), publisher)
+
+	msg, err := svc.Persist(context.Background(), PersistInput{BotID: testBotID, Role: "user"})
+	if err != nil {
+		t.Fatalf("persist: %v", err)
+	}
+	if msg.ID == "" || len(db.rows) != 1 || db.unavailable != 0 {
+		t.Fatalf("stored rows = %d, id %q, want one row after the retry", len(db.rows), msg.ID)
+	}
+	if len(publisher.events) != 1 {
+		t.Fatalf("published events = %d, want 1", len(publisher.events))
+	}
+}
+
+func TestPersistBatchSkipsRedeliveredMessage(t *testing.T) {
+	db := &historyDB{}
+	publisher := &countingPublisher{}
+	svc := NewService(slog.New(slog.DiscardHandler), sqlc.New(db), publisher)
+
+	user := PersistInput{BotID: testBotID, SessionID: testSessionID, ExternalMessageID: "msg-1", Role: "user", Metadata: map[string]any{"platform": "telegram"}}
+	if _, err := svc.Persist(context.Background(), user); err != nil {
+		t.Fatalf("persist: %v", err)
+	}
+	results, err := svc.PersistBatch(context.Background(), []PersistInput{
+		user,
+		{BotID: testBotID, Role: "assistant", Metadata: map[string]any{"platform": "telegram"}},
+	})
+	if err != nil {
+		t.Fatalf("persist batch: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("results = %d, want 2", len(results))
+	}
+	if len(db.rows) != 2 {
+		t.Fatalf("stored rows = %d, want 2", len(db.rows))
+	}
+	if len(publisher.events) != 2 {
+		t.Fatalf("published events = %d, want 2", len(publisher.events))
+	}
+}
+
+func TestAssetRoleFromMetadata(t *testing.T) {
+	cases := []struct {
+		metadata map[string]any
+		want     string
+	}{
+		{nil, DefaultAssetRole},
+		{map[string]any{AssetRoleMetadataKey: " chart "}, "chart"},
+		{map[string]any{AssetRoleMetadataKey: "  "}, DefaultAssetRole},
+		{map[string]any{AssetRoleMetadataKey: 3}, DefaultAssetRole},
+	}
+	for _, tc := range cases {
+		if got := AssetRoleFromMetadata(tc.metadata); got != tc.want {
+			t.Fatalf("AssetRoleFromMetadata(%v) = %q, want %q", tc.metadata, got, tc.want)
+		}
+	}
+}
+
+func TestRedactWholeMessageKeepsToolPairing(t *testing.T) {
+	db := &historyDB{}
+	svc := NewService(slog.New(slog.DiscardHandler), sqlc.New(db))
+	ctx := context.Background()
+
+	stored, err := svc.Persist(ctx, PersistInput{
+		BotID: testBotID,
+		Role:  "assistant",
+		Content: []byte(`{"role":"assistant","content":[` +
+			`{"type":"text","text":"Your card 4111 is on file."},` +
+			`{"type":"tool-call","toolCallId":"call-1","toolName":"lookup","input":{"card":"4111"}}]}`),
+		DisplayText: "Your card 4111 is on file.",
+	})
+	if err != nil {
+		t.Fatalf("persist: %v", err)
+	}
+
+	redacted, err := svc.Redact(ctx, testBotID, stored.ID, RedactInput{RedactedBy: "admin", Reason: "pii"})
+	if err != nil {
+		t.Fatalf("redact: %v", err)
+	}
+	if strings.Contains(string(redacted.Content), "4111") || strings.Contains(redacted.DisplayContent, "4111") {
+		t.Fatalf("redacted message still holds the original text: %s / %q", redacted.Content, redacted.DisplayContent)
+	}
+	if !strings.Contains(string(redacted.Content), `"toolCallId":"call-1"`) {
+		t.Fatalf("expected tool call pairing to survive, got %s", redacted.Content)
+	}
+	if !FullyRedacted(redacted.Metadata) {
+		t.Fatalf("expected full redaction record, got %+v", redacted.Metadata)
+	}
+	record := redacted.Metadata[RedactedMetadataKey].(map[string]any)
+	if record["by"] != "admin" || record["reason"] != "pii" || record["at"] == "" {
+		t.Fatalf("unexpected redaction record: %+v", record)
+	}
+	if len(db.rows) != 1 {
+		t.Fatalf("stored rows = %d, want 1", len(db.rows))
+	}
+}
+
+func TestRedactPhrasesKeepsRestOfMessage(t *testing.T) {
+	db := &historyDB{}
+	svc := NewService(slog.New(slog.DiscardHandler), sqlc.New(db))
+	ctx := context.Background()
+
+	stored, err := svc.Persist(ctx, PersistInput{
+		BotID:       testBotID,
+		Role:        "user",
+		Content:     []byte(`{"role":"user","content":[{"type":"text","text":"Call me at 555-0100 tomorrow."}]}`),
+		DisplayText: "Call me at 555-0100 tomorrow.",
+	})
+	if err != nil {
+		t.Fatalf("persist: %v", err)
+	}
+
+	redacted, err := svc.Redact(ctx, testBotID, stored.ID, RedactInput{Phrases: []string{" 555-0100 ", ""}})
+	if err != nil {
+		t.Fatalf("redact: %v", err)
+	}
+	want := `{"content":[{"text":"Call me at [redacted] tomorrow.","type":"text"}],"role":"user"}`
+	if string(redacted.Content) != want {
+		t.Fatalf("content = %s, want %s", redacted.Content, want)
+	}
+	if redacted.DisplayContent != "Call me at [redacted] tomorrow." {
+		t.Fatalf("display content = %q", redacted.DisplayContent)
+	}
+	if FullyRedacted(redacted.Metadata) {
+		t.Fatal("phrase redaction must not be recorded as full")
+	}
+}
+
+func TestRedactCoversToolCallsEventAndMetadata(t *testing.T) {
+	eventID := pgtype.UUID{Valid: true}
+	eventID.Bytes[0] = 0xe1
+	db := &historyDB{events: map[pgtype.UUID][]byte{
+		eventID: []byte(`{"content":[{"type":"text","text":"my card is 4111"}],"attachments":[{"url":"a.png"}],"reply_to_preview":"4111?"}`),
+	}}
+	svc := NewService(slog.New(slog.DiscardHandler), sqlc.New(db))
+	ctx := context.Background()
+
+	stored, err := svc.Persist(ctx, PersistInput{
+		BotID:       testBotID,
+		Role:        "assistant",
+		Content:     []byte(`{"role":"assistant","content":[{"type":"text","text":"card 4111 saved"}]}`),
+		Metadata:    map[string]any{"reasoning": "the card is 4111", "platform": "telegram"},
+		EventID:     eventID.String(),
+		DisplayText: "card 4111 saved",
+	})
+	if err != nil {
+		t.Fatalf("persist: %v", err)
+	}
+	messageID := db.rows[0].id
+	db.toolCalls = []toolCallRow{{
+		id:        pgtype.UUID{Bytes: [16]byte{0xc1}, Valid: true},
+		messageID: messageID,
+		arguments: []byte(`{"card":"4111"}`),
+		result:    []byte(`{"saved":"4111"}`),
+	}}
+
+	redacted, err := svc.Redact(ctx, testBotID, stored.ID, RedactInput{Phrases: []string{"4111"}})
+	if err != nil {
+		t.Fatalf("redact: %v", err)
+	}
+	if got := redacted.Metadata["reasoning"]; got != "the card is [redacted]" {
+		t.Fatalf("metadata reasoning = %v", got)
+	}
+	if redacted.Metadata["platform"] != "telegram" {
+		t.Fatalf("metadata platform = %v", redacted.Metadata["platform"])
+	}
+	call := db.toolCalls[0]
+	if strings.Contains(string(call.arguments)+string(call.result), "4111") {
+		t.Fatalf("tool call still holds the phrase: %s / %s", call.arguments, call.result)
+	}
+	if strings.Contains(string(db.events[eventID]), "4111") {
+		t.Fatalf("session event still holds the phrase: %s", db.events[eventID])
+	}
+
+	if _, err := svc.Redact(ctx, testBotID, stored.ID, RedactInput{}); err != nil {
+		t.Fatalf("full redact: %v", err)
+	}
+	if string(db.toolCalls[0].arguments) != `{}` {
+		t.Fatalf("tool call arguments = %s, want {}", db.toolCalls[0].arguments)
+	}
+	var event map[string]any
+	if err := json.Unmarshal(db.events[eventID], &event); err != nil {
+		t.Fatalf("decode event: %v", err)
+	}
+	if _, ok := event["reply_to_preview"]; ok {
+		t.Fatalf("expected reply preview to be dropped: %v", event)
+	}
+	if attachments := event["attachments"].([]any); len(attachments) != 0 {
+		t.Fatalf("expected attachments to be dropped: %v", attachments)
+	}
+	if !strings.Contains(string(db.events[eventID]), RedactionMarker) {
+		t.Fatalf("expected event content to be the marker: %s", db.events[eventID])
+	}
+}
+
+func TestRedactUnknownMessage(t *testing.T) {
+	svc := NewService(slog.New(slog.DiscardHandler), sqlc.New(&historyDB{}))
+	_, err := svc.Redact(context.Background(), testBotID, "22222222-2222-2222-2222-222222222222", RedactInput{})
+	if !errors.Is(err, ErrMessageNotFound) {
+		t.Fatalf("expected ErrMessageNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,141 @@
+package message
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	dbpkg "github.com/memohai/memoh/internal/db"
+	"github.com/memohai/memoh/internal/db/sqlc"
+)
+
+const (
+	defaultToolCallListLimit = 50
+	maxToolCallListLimit     = 200
+)
+
+// RecordToolCalls stores tool calls against their assistant messages. Calls
+// without a message ID or tool name are skipped; a failed insert is logged and
+// does not stop the remaining calls.
+func (s *DBService) RecordToolCalls(ctx context.Context, calls []ToolCallInput) error {
+	for _, call := range calls {
+		toolName := strings.TrimSpace(call.ToolName)
+		if toolName == "" || strings.TrimSpace(call.MessageID) == "" {
+			continue
+		}
+		pgBotID, err := dbpkg.ParseUUID(call.BotID)
+		if err != nil {
+			return fmt.Errorf("invalid bot id: %w", err)
+		}
+		pgMessageID, err := dbpkg.ParseUUID(call.MessageID)
+		if err != nil {
+			return fmt.Errorf("invalid message id: %w", err)
+		}
+		pgSessionID, err := parseOptionalUUID(call.SessionID)
+		if err != nil {
+			return fmt.Errorf("invalid session id: %w", err)
+		}
+		var duration pgtype.Int8
+		if call.Duration > 0 {
+			duration = pgtype.Int8{Int64: call.Duration.Milliseconds(), Valid: true}
+		}
+		if err := s.queries.CreateMessageToolCall(ctx, sqlc.CreateMessageToolCallParams{
+			BotID:      pgBotID,
+			SessionID:  pgSessionID,
+			MessageID:  pgMessageID,
+			ToolCallID: strings.TrimSpace(call.ToolCallID),
+			ToolName:   toolName,
+			Arguments:  toolCallJSON(call.Arguments),
+			Result:     toolCallJSON(call.Result),
+			IsError:    call.IsError,
+			DurationMs: duration,
+		}); err != nil {
+			s.logger.Warn("record tool call failed",
+				slog.String("message_id", call.MessageID),
+				slog.String("tool_name", toolName),
+				slog.Any("error", err))
+		}
+	}
+	return nil
+}
+
+// ListToolCalls returns the most recent tool calls of a bot, newest first.
+func (s *DBService) ListToolCalls(ctx context.Context, botID string, filter ToolCallFilter) ([]ToolCall, error) {
+	pgBotID, err := dbpkg.ParseUUID(botID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid bot id: %w", err)
+	}
+	pgSessionID, err := parseOptionalUUID(filter.SessionID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid session id: %w", err)
+	}
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = defaultToolCallListLimit
+	}
+	if limit > maxToolCallListLimit {
+		limit = maxToolCallListLimit
+	}
+	var before pgtype.Timestamptz
+	if !filter.Before.IsZero() {
+		before = pgtype.Timestamptz{Time: filter.Before, Valid: true}
+	}
+	rows, err := s.queries.ListMessageToolCalls(ctx, sqlc.ListMessageToolCallsParams{
+		BotID:     pgBotID,
+		ToolName:  toPgText(filter.ToolName),
+		SessionID: pgSessionID,
+		Before:    before,
+		MaxCount:  limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	calls := make([]ToolCall, 0, len(rows))
+	for _, row := range rows {
+		calls = append(calls, toToolCall(row))
+	}
+	return calls, nil
+}
+
+func toToolCall(row sqlc.BotMessageToolCall) ToolCall {
+	call := ToolCall{
+		ID:         row.ID.String(),
+		BotID:      row.BotID.String(),
+		MessageID:  row.MessageID.String(),
+		ToolCallID: row.ToolCallID,
+		ToolName:   row.ToolName,
+		Arguments:  json.RawMessage(row.Arguments),
+		Result:     json.RawMessage(row.Result),
+		IsError:    row.IsError,
+		CreatedAt:  row.CreatedAt.Time,
+	}
+	if row.SessionID.Valid {
+		call.SessionID = row.SessionID.String()
+	}
+	if row.DurationMs.Valid {
+		ms := row.DurationMs.Int64
+		call.DurationMs = &ms
+	}
+	return call
+}
+
+// toolCallJSON returns raw when it is valid JSON, the raw text encoded as a
+// JSON string otherwise, and nil when empty.
+func toolCallJSON(raw json.RawMessage) []byte {
+	trimmed := strings.TrimSpace(string(raw))
+	if trimmed == "" {
+		return nil
+	}
+	if json.Valid([]byte(trimmed)) {
+		return []byte(trimmed)
+	}
+	encoded, err := json.Marshal(trimmed)
+	if err != nil {
+		return nil
+	}
+	return encoded
+}
@@ -3,6 +3,7 @@ package message
 import (
 	"context"
 	"encoding/json"
+	"strings"
 	"time"
 )
 
@@ -21,25 +22,57 @@ type MessageAsset struct {
 
 // Message represents a single persisted bot message.
 type Message struct {
-	ID                      string          `json:"id"`
-	BotID                   string          `json:"bot_id"`
-	SessionID               string          `json:"session_id,omitempty"`
-	SenderChannelIdentityID string          `json:"sender_channel_identity_id,omitempty"`
-	SenderUserID            string          `json:"sender_user_id,omitempty"`
-	SenderDisplayName       string          `json:"sender_display_name,omitempty"`
-	SenderAvatarURL         string          `json:"sender_avatar_url,omitempty"`
-	Platform                string          `json:"platform,omitempty"`
-	ExternalMessageID       string          `json:"external_message_id,omitempty"`
-	SourceReplyToMessageID  string          `json:"source_reply_to_message_id,omitempty"`
-	Role                    string          `json:"role"`
-	Content                 json.RawMessage `json:"content"`
-	Metadata                map[string]any  `json:"metadata,omitempty"`
-	Usage                   json.RawMessage `json:"usage,omitempty"`
-	Assets                  []MessageAsset  `json:"assets,omitempty"`
-	CompactID               string          `json:"compact_id,omitempty"`
-	EventID                 string          `json:"event_id,omitempty"`
-	DisplayContent          string          `json:"display_content,omitempty"`
-	CreatedAt               time.Time       `json:"created_at"`
+	ID                      string            `json:"id"`
+	BotID                   string            `json:"bot_id"`
+	SessionID               string            `json:"session_id,omitempty"`
+	SenderChannelIdentityID string            `json:"sender_channel_identity_id,omitempty"`
+	SenderUserID            string            `json:"sender_user_id,omitempty"`
+	SenderDisplayName       string            `json:"sender_display_name,omitempty"`
+	SenderAvatarURL         string            `json:"sender_avatar_url,omitempty"`
+	Platform                string            `json:"platform,omitempty"`
+	ExternalMessageID       string            `json:"external_message_id,omitempty"`
+	SourceReplyToMessageID  string            `json:"source_reply_to_message_id,omitempty"`
+	Role                    string            `json:"role"`
+	Content                 json.RawMessage   `json:"content"`
+	Metadata                map[string]any    `json:"metadata,omitempty"`
+	Usage                   json.RawMessage   `json:"usage,omitempty"`
+	Assets                  []MessageAsset    `json:"assets,omitempty"`
+	Deliveries              []DeliveryReceipt `json:"deliveries,omitempty"`
+	CompactID               string            `json:"compact_id,omitempty"`
+	EventID                 string            `json:"event_id,omitempty"`
+	DisplayContent          string            `json:"display_content,omitempty"`
+	CreatedAt               time.Time         `json:"created_at"`
+}
+
+// DeliveryReceipt is the outcome of delivering a message to a channel.
+// Status is "delivered" or "failed".
+type DeliveryReceipt struct {
+	Platform          string    `json:"platform"`
+	Target            string    `json:"target,omitempty"`
+	PlatformMessageID string    `json:"platform_message_id,omitempty"`
+	Status            string    `json:"status"`
+	Error             string    `json:"error,omitempty"`
+	At                time.Time `json:"at"`
+}
+
+const (
+	// DefaultAssetRole is the role of asset links that name no other role.
+	DefaultAssetRole = "attachment"
+	// AssetRoleMetadataKey is the attachment metadata key through which
+	// tools name the role of an outbound asset, such as "image", "chart" or
+	// "file", so the WebUI can render each kind differently.
+	AssetRoleMetadataKey = "asset_role"
+)
+
+// AssetRoleFromMetadata returns the asset role named in attachment metadata,
+// or DefaultAssetRole when there is none.
+func AssetRoleFromMetadata(metadata map[string]any) string {
+	if role, ok := metadata[AssetRoleMetadataKey].(string); ok {
+		if role = strings.TrimSpace(role); role != "" {
+			return role
+		}
+	}
+	return DefaultAssetRole
 }
 
 // AssetRef links a media asset to a persisted message.
@@ -73,6 +106,45 @@ type PersistInput struct {
 	DisplayText             string
 }
 
+// ToolCall is a tool invocation made by the assistant, stored alongside the
+// message that issued it. DurationMs is nil when the duration was not measured.
+type ToolCall struct {
+	ID         string          `json:"id"`
+	BotID      string          `json:"bot_id"`
+	SessionID  string          `json:"session_id,omitempty"`
+	MessageID  string          `json:"message_id"`
+	ToolCallID string          `json:"tool_call_id,omitempty"`
+	ToolName   string          `json:"tool_name"`
+	Arguments  json.RawMessage `json:"arguments,omitempty"`
+	Result     json.RawMessage `json:"result,omitempty"`
+	IsError    bool            `json:"is_error"`
+	DurationMs *int64          `json:"duration_ms,omitempty"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+// ToolCallInput is the input for recording a tool call against a persisted
+// assistant message.
+type ToolCallInput struct {
+	BotID      string
+	SessionID  string
+	MessageID  string
+	ToolCallID string
+	ToolName   string
+	Arguments  json.RawMessage
+	Result     json.RawMessage
+	IsError    bool
+	Duration   time.Duration // zero when unknown
+}
+
+// ToolCallFilter narrows ListToolCalls. Empty fields are ignored; Limit
+// defaults to 50.
+type ToolCallFilter struct {
+	ToolName  string
+	SessionID string
+	Before    time.Time
+	Limit     int32
+}
+
 // Writer defines write behavior needed by the inbound router.
 type Writer interface {
 	Persist(ctx context.Context, input PersistInput) (Message, error)
@@ -81,6 +153,10 @@ type Writer interface {
 // Service defines message read/write behavior.
 type Service interface {
 	Writer
+	// PersistBatch writes messages in input order and returns them in the
+	// same order. On error, the returned messages are the ones that were
+	// stored, always a prefix of inputs.
+	PersistBatch(ctx context.Context, inputs []PersistInput) ([]Message, error)
 	List(ctx context.Context, botID string) ([]Message, error)
 	ListSince(ctx context.Context, botID string, since time.Time) ([]Message, error)
 	ListActiveSince(ctx context.Context, botID string, since time.Time) ([]Message, error)
@@ -94,4 +170,9 @@ type Service interface {
 	DeleteByBot(ctx context.Context, botID string) error
 	DeleteBySession(ctx context.Context, sessionID string) error
 	LinkAssets(ctx context.Context, messageID string, assets []AssetRef) error
+	// Redact replaces the given phrases, or the whole content when none are
+	// given, of a stored message with a redaction marker.
+	Redact(ctx context.Context, botID, messageID string, input RedactInput) (Message, error)
+	RecordToolCalls(ctx context.Context, calls []ToolCallInput) error
+	ListToolCalls(ctx context.Context, botID string, filter ToolCallFilter) ([]ToolCall, error)
 }
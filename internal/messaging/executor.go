@@ -60,6 +60,9 @@ type SendResult struct {
 	Platform  string
 	Target    string
 	MessageID string
+	// Queued is true when delivery failed and the message was queued for
+	// background retries.
+	Queued bool
 	// Local is true when the message targets the current conversation.
 	// The caller should emit the resolved attachments as stream events.
 	Local            bool
@@ -139,10 +142,19 @@ func (e *Executor) sendWithMode(
 		e.promoteDataPathAttachmentsToAssets(ctx, plan.botID, plan.channelType, &plan.message)
 	}
 
-	if err := e.Sender.Send(ctx, plan.botID, plan.channelType, channel.SendRequest{
+	err = e.Sender.Send(ctx, plan.botID, plan.channelType, channel.SendRequest{
 		Target:  plan.target,
 		Message: plan.message,
-	}); err != nil {
+	})
+	if errors.Is(err, channel.ErrOutboundQueued) {
+		return &SendResult{
+			BotID:    plan.botID,
+			Platform: plan.channelType.String(),
+			Target:   plan.target,
+			Queued:   true,
+		}, nil
+	}
+	if err != nil {
 		if e.Logger != nil {
 			e.Logger.Warn("outbound send failed",
 				slog.String("mode", mode.name),
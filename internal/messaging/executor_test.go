@@ -2,6 +2,7 @@ package messaging
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"testing"
 
@@ -12,12 +13,13 @@ import (
 type testSender struct {
 	called int
 	req    channel.SendRequest
+	err    error
 }
 
 func (s *testSender) Send(_ context.Context, _ string, _ channel.ChannelType, req channel.SendRequest) error {
 	s.called++
 	s.req = req
-	return nil
+	return s.err
 }
 
 type testResolver struct{}
@@ -104,6 +106,26 @@ func TestSendDirectSameConversationWithAttachments(t *testing.T) {
 	}
 }
 
+func TestSendDirectReportsQueuedDelivery(t *testing.T) {
+	t.Parallel()
+
+	sender := &testSender{err: fmt.Errorf("%w: telegram is unavailable", channel.ErrOutboundQueued)}
+	exec := &Executor{
+		Sender:   sender,
+		Resolver: testResolver{},
+	}
+
+	result, err := exec.SendDirect(context.Background(), SessionContext{BotID: "bot_1", CurrentPlatform: "telegram"}, "100", map[string]any{
+		"text": "hello",
+	})
+	if err != nil {
+		t.Fatalf("SendDirect returned error: %v", err)
+	}
+	if !result.Queued || result.Target != "100" {
+		t.Fatalf("expected a queued result for target 100, got %+v", result)
+	}
+}
+
 func TestSendSameConversationWithAttachmentsUsesLocalResult(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,129 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"golang.org/x/net/http/httpguts"
+)
+
+// ProviderHeadersKey is the provider config key holding extra request
+// headers, an object of header name to value.
+const ProviderHeadersKey = "headers"
+
+// reservedProviderHeaders are set by the SDK clients themselves and may not
+// be overridden from provider config.
+var reservedProviderHeaders = map[string]bool{
+	"Authorization":     true,
+	"X-Api-Key":         true,
+	"Host":              true,
+	"Content-Type":      true,
+	"Content-Length":    true,
+	"Content-Encoding":  true,
+	"Transfer-Encoding": true,
+	"Connection":        true,
+}
+
+// ConfigHeaders returns the extra request headers from a decoded provider
+// config. Malformed entries are skipped.
+func ConfigHeaders(cfg map[string]any) map[string]string {
+	raw, ok := cfg[ProviderHeadersKey].(map[string]any)
+	if !ok || len(raw) == 0 {
+		return nil
+	}
+	headers := make(map[string]string, len(raw))
+	for name, value := range raw {
+		text, ok := value.(string)
+		if !ok || validateProviderHeader(name, text) != nil {
+			continue
+		}
+		headers[http.CanonicalHeaderKey(strings.TrimSpace(name))] = text
+	}
+	if len(headers) == 0 {
+		return nil
+	}
+	return headers
+}
+
+// ValidateConfigHeaders checks the extra request headers of a decoded
+// provider config.
+func ValidateConfigHeaders(cfg map[string]any) error {
+	value, ok := cfg[ProviderHeadersKey]
+	if !ok || value == nil {
+		return nil
+	}
+	raw, ok := value.(map[string]any)
+	if !ok {
+		return errors.New("headers must be an object of header names to values")
+	}
+	for name, value := range raw {
+		text, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("header %q must have a string value", name)
+		}
+		if err := validateProviderHeader(name, text); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateProviderHeader(name, value string) error {
+	name = strings.TrimSpace(name)
+	if !httpguts.ValidHeaderFieldName(name) {
+		return fmt.Errorf("invalid header name %q", name)
+	}
+	if reservedProviderHeaders[http.CanonicalHeaderKey(name)] {
+		return fmt.Errorf("header %q is set by the client and cannot be configured", name)
+	}
+	if !httpguts.ValidHeaderFieldValue(value) {
+		return fmt.Errorf("invalid value for header %q", name)
+	}
+	return nil
+}
+
+func providerConfigHeaders(raw []byte) map[string]string {
+	if len(raw) == 0 {
+		return nil
+	}
+	var cfg map[string]any
+	if err := json.Unmarshal(raw, &cfg); err != nil {
+		return nil
+	}
+	return ConfigHeaders(cfg)
+}
+
+// WithHeaders returns a copy of client that adds headers to every request.
+// It returns client unchanged when headers is empty; a nil client gets a
+// default one.
+func WithHeaders(client *http.Client, headers map[string]string) *http.Client {
+	if len(headers) == 0 {
+		return client
+	}
+	wrapped := &http.Client{}
+	if client != nil {
+		*wrapped = *client
+	}
+	wrapped.Transport = &headerTransport{base: wrapped.Transport, headers: headers}
+	return wrapped
+}
+
+type headerTransport struct {
+	base    http.RoundTripper
+	headers map[string]string
+}
+
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	out := req.Clone(req.Context())
+	for name, value := range t.headers {
+		out.Header.Set(name, value)
+	}
+	return base.RoundTrip(out)
+}
@@ -0,0 +1,104 @@
+package models_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	sdk "github.com/memohai/twilight-ai/sdk"
+
+	"github.com/memohai/memoh/internal/models"
+)
+
+func TestConfigHeaders(t *testing.T) {
+	cfg := map[string]any{
+		"headers": map[string]any{
+			"x-org-id":      "org-1",
+			"X-Trace":       "on",
+			"Authorization": "Bearer override",
+			"bad header":    "x",
+			"X-Number":      42,
+		},
+	}
+	got := models.ConfigHeaders(cfg)
+	want := map[string]string{"X-Org-Id": "org-1", "X-Trace": "on"}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for name, value := range want {
+		if got[name] != value {
+			t.Fatalf("expected %s=%q, got %q", name, value, got[name])
+		}
+	}
+	if models.ConfigHeaders(map[string]any{"base_url": "x"}) != nil {
+		t.Fatal("expected nil headers when none are configured")
+	}
+}
+
+func TestValidateConfigHeaders(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     map[string]any
+		wantErr bool
+	}{
+		{name: "absent", cfg: map[string]any{}},
+		{name: "valid", cfg: map[string]any{"headers": map[string]any{"X-Org-Id": "org-1"}}},
+		{name: "not an object", cfg: map[string]any{"headers": "X-Org-Id: org-1"}, wantErr: true},
+		{name: "non-string value", cfg: map[string]any{"headers": map[string]any{"X-Org-Id": 1}}, wantErr: true},
+		{name: "invalid name", cfg: map[string]any{"headers": map[string]any{"X Org": "1"}}, wantErr: true},
+		{name: "invalid value", cfg: map[string]any{"headers": map[string]any{"X-Org-Id": "a\nb"}}, wantErr: true},
+		{name: "reserved", cfg: map[string]any{"headers": map[string]any{"authorization": "x"}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := models.ValidateConfigHeaders(tt.cfg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateConfigHeaders() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWithHeadersLeavesClientWithoutHeaders(t *testing.T) {
+	client := &http.Client{}
+	if models.WithHeaders(client, nil) != client {
+		t.Fatal("expected client to be returned unchanged")
+	}
+}
+
+func TestNewSDKChatModelSendsConfiguredHeaders(t *testing.T) {
+	received := make(chan http.Header, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case received <- r.Header.Clone():
+		default:
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	model := models.NewSDKChatModel(models.SDKModelConfig{
+		ModelID:    "gpt-test",
+		ClientType: string(models.ClientTypeOpenAICompletions),
+		APIKey:     "sk-test",
+		BaseURL:    srv.URL,
+		Headers:    map[string]string{"X-Org-Id": "org-1"},
+	})
+	_, _ = sdk.GenerateTextResult(context.Background(),
+		sdk.WithModel(model),
+		sdk.WithMessages([]sdk.Message{sdk.UserMessage("hi")}),
+	)
+
+	select {
+	case header := <-received:
+		if got := header.Get("X-Org-Id"); got != "org-1" {
+			t.Fatalf("expected X-Org-Id header org-1, got %q", got)
+		}
+		if got := header.Get("Authorization"); got != "Bearer sk-test" {
+			t.Fatalf("expected client auth header to be kept, got %q", got)
+		}
+	default:
+		t.Fatal("expected a request to reach the provider")
+	}
+}
@@ -126,6 +126,22 @@ func (s *Service) List(ctx context.Context) ([]GetResponse, error) {
 	return s.convertToGetResponseList(dbModels), nil
 }
 
+// Pricing returns the configured pricing of every priced model, keyed by
+// model UUID.
+func (s *Service) Pricing(ctx context.Context) (map[string]ModelPricing, error) {
+	all, err := s.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	pricing := make(map[string]ModelPricing)
+	for _, m := range all {
+		if m.Config.Pricing != nil {
+			pricing[m.ID] = *m.Config.Pricing
+		}
+	}
+	return pricing, nil
+}
+
 // ListByType returns models filtered by type (chat, embedding, or speech).
 func (s *Service) ListByType(ctx context.Context, modelType ModelType) ([]GetResponse, error) {
 	if modelType != ModelTypeChat && modelType != ModelTypeEmbedding && modelType != ModelTypeSpeech {
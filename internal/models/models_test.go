@@ -105,6 +105,18 @@ func TestModel_Validate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "negative pricing",
+			model: models.Model{
+				ModelID:    "gpt-4",
+				ProviderID: "11111111-1111-1111-1111-111111111111",
+				Type:       models.ModelTypeChat,
+				Config: models.ModelConfig{
+					Pricing: &models.ModelPricing{InputPerMillion: -1},
+				},
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -131,6 +143,13 @@ func TestModel_HasCompatibility(t *testing.T) {
 	assert.False(t, m.HasCompatibility("image-output"))
 }
 
+func TestModelPricing_Cost(t *testing.T) {
+	pricing := models.ModelPricing{InputPerMillion: 3, OutputPerMillion: 15}
+	assert.InDelta(t, 0.0105, pricing.Cost(1_000, 500), 1e-12)
+	assert.InDelta(t, 18.0, pricing.Cost(1_000_000, 1_000_000), 1e-9)
+	assert.Zero(t, models.ModelPricing{}.Cost(1_000_000, 1_000_000))
+}
+
 func TestModelTypes(t *testing.T) {
 	t.Run("ModelType constants", func(t *testing.T) {
 		assert.Equal(t, models.ModelTypeChat, models.ModelType("chat"))
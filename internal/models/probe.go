@@ -50,11 +50,12 @@ func (s *Service) Test(ctx context.Context, id string) (TestResponse, error) {
 		return TestResponse{}, err
 	}
 
+	httpClient := WithHeaders(&http.Client{Timeout: probeTimeout}, providerConfigHeaders(provider.Config))
 	if model.Type == string(ModelTypeEmbedding) {
-		return s.testEmbeddingModel(ctx, baseURL, creds.APIKey, model.ModelID, nil)
+		return s.testEmbeddingModel(ctx, baseURL, creds.APIKey, model.ModelID, httpClient)
 	}
 
-	sdkProvider := NewSDKProvider(baseURL, creds.APIKey, creds.CodexAccountID, clientType, probeTimeout, nil)
+	sdkProvider := NewSDKProvider(baseURL, creds.APIKey, creds.CodexAccountID, clientType, probeTimeout, httpClient)
 
 	start := time.Now()
 
@@ -24,6 +24,8 @@ type SDKModelConfig struct {
 	BaseURL         string
 	HTTPClient      *http.Client
 	ReasoningConfig *ReasoningConfig
+	// Headers are extra request headers configured on the provider.
+	Headers map[string]string
 }
 
 // ReasoningConfig controls extended thinking/reasoning behavior.
@@ -39,6 +41,7 @@ var (
 
 // NewSDKChatModel builds a Twilight AI SDK Model from the resolved model config.
 func NewSDKChatModel(cfg SDKModelConfig) *sdk.Model {
+	cfg.HTTPClient = WithHeaders(cfg.HTTPClient, cfg.Headers)
 	switch ClientType(cfg.ClientType) {
 	case ClientTypeOpenAICompletions:
 		opts := []openaicompletions.Option{
@@ -60,6 +60,20 @@ type ModelConfig struct {
 	Compatibilities  []string `json:"compatibilities,omitempty"`
 	ContextWindow    *int     `json:"context_window,omitempty"`
 	ReasoningEfforts []string `json:"reasoning_efforts,omitempty"`
+	// Pricing is used to estimate the cost of stored usage. Unpriced models
+	// are estimated at zero cost.
+	Pricing *ModelPricing `json:"pricing,omitempty"`
+}
+
+// ModelPricing is the price of a model in USD per million tokens.
+type ModelPricing struct {
+	InputPerMillion  float64 `json:"input_per_million"`
+	OutputPerMillion float64 `json:"output_per_million"`
+}
+
+// Cost estimates the USD cost of the given token counts.
+func (p ModelPricing) Cost(inputTokens, outputTokens int64) float64 {
+	return (float64(inputTokens)*p.InputPerMillion + float64(outputTokens)*p.OutputPerMillion) / 1_000_000
 }
 
 type Model struct {
@@ -98,6 +112,9 @@ func (m *Model) Validate() error {
 			return errors.New("invalid reasoning effort: " + effort)
 		}
 	}
+	if p := m.Config.Pricing; p != nil && (p.InputPerMillion < 0 || p.OutputPerMillion < 0) {
+		return errors.New("pricing must not be negative")
+	}
 	return nil
 }
 
@@ -3,6 +3,7 @@ package providers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
@@ -21,6 +22,9 @@ import (
 	"github.com/memohai/memoh/internal/models"
 )
 
+// ErrInvalidConfig is returned when a provider config fails validation.
+var ErrInvalidConfig = errors.New("invalid provider config")
+
 // Service handles provider operations.
 type Service struct {
 	queries     *sqlc.Queries
@@ -53,6 +57,9 @@ func (s *Service) Create(ctx context.Context, req CreateRequest) (GetResponse, e
 	if clientType == "" {
 		clientType = string(models.ClientTypeOpenAICompletions)
 	}
+	if err := models.ValidateConfigHeaders(req.Config); err != nil {
+		return GetResponse{}, fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+	}
 	configJSON, err := json.Marshal(normalizeProviderConfig(clientType, req.Config))
 	if err != nil {
 		return GetResponse{}, fmt.Errorf("marshal config: %w", err)
@@ -151,8 +158,12 @@ func (s *Service) Update(ctx context.Context, id string, req UpdateRequest) (Get
 
 	existingConfig := providerConfig(existing.Config)
 	if req.Config != nil {
+		if err := models.ValidateConfigHeaders(req.Config); err != nil {
+			return GetResponse{}, fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+		}
 		mergedConfig := mergeProviderConfig(existingConfig, req.Config)
 		preserveMaskedConfigSecret(mergedConfig, existingConfig, req.Config, "api_key")
+		preserveMaskedConfigHeaders(mergedConfig, existingConfig, req.Config)
 		existingConfig = normalizeProviderConfig(clientType, mergedConfig)
 	} else {
 		existingConfig = normalizeProviderConfig(clientType, existingConfig)
@@ -233,7 +244,8 @@ func (s *Service) Test(ctx context.Context, id string) (TestResponse, error) {
 		return TestResponse{}, err
 	}
 
-	sdkProvider := models.NewSDKProvider(baseURL, creds.APIKey, creds.CodexAccountID, clientType, probeTimeout, nil)
+	httpClient := models.WithHeaders(&http.Client{Timeout: probeTimeout}, models.ConfigHeaders(cfg))
+	sdkProvider := models.NewSDKProvider(baseURL, creds.APIKey, creds.CodexAccountID, clientType, probeTimeout, httpClient)
 
 	start := time.Now()
 	result := sdkProvider.Test(ctx)
@@ -322,6 +334,9 @@ func (s *Service) FetchRemoteModels(ctx context.Context, id string) ([]RemoteMod
 		return nil, fmt.Errorf("create request: %w", err)
 	}
 
+	for name, value := range models.ConfigHeaders(cfg) {
+		req.Header.Set(name, value)
+	}
 	if apiKey != "" && !supportsOAuth(provider) {
 		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", apiKey))
 	}
@@ -406,6 +421,11 @@ func ProviderConfigString(provider sqlc.Provider, key string) string {
 	return configString(providerConfig(provider.Config), key)
 }
 
+// ProviderConfigHeaders returns the extra request headers configured on a provider.
+func ProviderConfigHeaders(provider sqlc.Provider) map[string]string {
+	return models.ConfigHeaders(providerConfig(provider.Config))
+}
+
 func cloneConfig(cfg map[string]any) map[string]any {
 	result := make(map[string]any, len(cfg))
 	for k, v := range cfg {
@@ -433,6 +453,29 @@ func preserveMaskedConfigSecret(merged, existing, incoming map[string]any, key s
 	}
 }
 
+// preserveMaskedConfigHeaders keeps the stored value of every incoming header
+// that was sent back in its masked form.
+func preserveMaskedConfigHeaders(merged, existing, incoming map[string]any) {
+	incomingHeaders, ok := incoming[models.ProviderHeadersKey].(map[string]any)
+	if !ok || len(incomingHeaders) == 0 {
+		return
+	}
+	existingHeaders := models.ConfigHeaders(existing)
+	if len(existingHeaders) == 0 {
+		return
+	}
+	headers := make(map[string]any, len(incomingHeaders))
+	for name, value := range incomingHeaders {
+		headers[name] = value
+		text, _ := value.(string)
+		existingValue := existingHeaders[http.CanonicalHeaderKey(strings.TrimSpace(name))]
+		if existingValue != "" && text != "" && text == maskAPIKey(existingValue) {
+			headers[name] = existingValue
+		}
+	}
+	merged[models.ProviderHeadersKey] = headers
+}
+
 // normalizeProviderConfig keeps provider-specific secrets under stable keys while
 // preserving backward compatibility for legacy stored configs.
 func normalizeProviderConfig(clientType string, cfg map[string]any) map[string]any {
@@ -452,6 +495,17 @@ func maskConfigSecrets(clientType string, cfg map[string]any) map[string]any {
 			result[key] = maskAPIKey(value)
 		}
 	}
+	// Header values often carry credentials, so they are masked like keys.
+	if headers, ok := result[models.ProviderHeadersKey].(map[string]any); ok {
+		masked := make(map[string]any, len(headers))
+		for name, value := range headers {
+			if text, ok := value.(string); ok {
+				value = maskAPIKey(text)
+			}
+			masked[name] = value
+		}
+		result[models.ProviderHeadersKey] = masked
+	}
 	return result
 }
 
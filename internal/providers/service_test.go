@@ -107,6 +107,48 @@ func TestPreserveMaskedConfigSecret(t *testing.T) {
 	}
 }
 
+func TestMaskConfigSecretsMasksHeaders(t *testing.T) {
+	t.Parallel()
+
+	stored := map[string]any{
+		"headers": map[string]any{"X-Api-Token": "tok-secret-123456"},
+	}
+	cfg := maskConfigSecrets("openai-completions", stored)
+
+	headers, _ := cfg["headers"].(map[string]any)
+	if got, _ := headers["X-Api-Token"].(string); got != maskAPIKey("tok-secret-123456") {
+		t.Fatalf("expected header value to be masked, got %q", got)
+	}
+	if got := stored["headers"].(map[string]any)["X-Api-Token"]; got != "tok-secret-123456" {
+		t.Fatalf("masking changed the stored header value to %q", got)
+	}
+}
+
+func TestPreserveMaskedConfigHeaders(t *testing.T) {
+	t.Parallel()
+
+	existing := map[string]any{
+		"headers": map[string]any{"X-Api-Token": "tok-secret-123456"},
+	}
+	incoming := map[string]any{
+		"headers": map[string]any{
+			"x-api-token": maskAPIKey("tok-secret-123456"),
+			"X-Team":      "blue",
+		},
+	}
+	merged := mergeProviderConfig(existing, incoming)
+
+	preserveMaskedConfigHeaders(merged, existing, incoming)
+
+	headers, _ := merged["headers"].(map[string]any)
+	if got, _ := headers["x-api-token"].(string); got != "tok-secret-123456" {
+		t.Fatalf("expected masked header to be restored, got %q", got)
+	}
+	if got, _ := headers["X-Team"].(string); got != "blue" {
+		t.Fatalf("expected new header to be kept, got %q", got)
+	}
+}
+
 func TestDeviceMetadataRoundTrip(t *testing.T) {
 	t.Parallel()
 
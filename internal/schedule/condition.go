@@ -0,0 +1,115 @@
+package schedule
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Variables a schedule condition can compare against.
+const (
+	// ConditionVarNewMessages counts user messages the bot received in chat
+	// sessions since the schedule last ran, or since it was created.
+	ConditionVarNewMessages = "messages.new"
+	// ConditionVarFailedRuns counts the schedule's consecutive failed runs.
+	ConditionVarFailedRuns = "runs.failed"
+	// ConditionVarCalls is the number of times the schedule has run.
+	ConditionVarCalls = "calls"
+)
+
+var knownConditionVars = map[string]struct{}{
+	ConditionVarNewMessages: {},
+	ConditionVarFailedRuns:  {},
+	ConditionVarCalls:       {},
+}
+
+var conditionClausePattern = regexp.MustCompile(`^([a-z_.]+)\s*(>=|<=|==|!=|>|<)\s*(-?\d+)$`)
+
+type conditionClause struct {
+	name  string
+	op    string
+	value int64
+}
+
+// Condition is a parsed schedule pre-condition: one or more comparisons such
+// as "messages.new > 0", joined with "&&". The zero Condition always holds.
+type Condition struct {
+	clauses []conditionClause
+}
+
+// ParseCondition parses a condition expression. An empty expression yields a
+// Condition that always holds.
+func ParseCondition(expr string) (Condition, error) {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return Condition{}, nil
+	}
+	parts := strings.Split(expr, "&&")
+	clauses := make([]conditionClause, 0, len(parts))
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+		match := conditionClausePattern.FindStringSubmatch(part)
+		if match == nil {
+			return Condition{}, fmt.Errorf("invalid condition clause %q: expected <variable> <op> <integer>", part)
+		}
+		if _, ok := knownConditionVars[match[1]]; !ok {
+			return Condition{}, fmt.Errorf("unknown condition variable %q", match[1])
+		}
+		value, err := strconv.ParseInt(match[3], 10, 64)
+		if err != nil {
+			return Condition{}, fmt.Errorf("invalid condition value %q: %w", match[3], err)
+		}
+		clauses = append(clauses, conditionClause{name: match[1], op: match[2], value: value})
+	}
+	return Condition{clauses: clauses}, nil
+}
+
+// IsZero reports whether the condition has no clauses and so always holds.
+func (c Condition) IsZero() bool {
+	return len(c.clauses) == 0
+}
+
+// Vars returns the variables the condition reads, without duplicates.
+func (c Condition) Vars() []string {
+	names := make([]string, 0, len(c.clauses))
+	seen := map[string]struct{}{}
+	for _, clause := range c.clauses {
+		if _, ok := seen[clause.name]; ok {
+			continue
+		}
+		seen[clause.name] = struct{}{}
+		names = append(names, clause.name)
+	}
+	return names
+}
+
+// Eval reports whether every clause holds for vars. Missing variables read
+// as zero.
+func (c Condition) Eval(vars map[string]int64) bool {
+	for _, clause := range c.clauses {
+		if !compare(vars[clause.name], clause.op, clause.value) {
+			return false
+		}
+	}
+	return true
+}
+
+func compare(left int64, op string, right int64) bool {
+	switch op {
+	case ">":
+		return left > right
+	case ">=":
+		return left >= right
+	case "<":
+		return left < right
+	case "<=":
+		return left <= right
+	case "==":
+		return left == right
+	case "!=":
+		return left != right
+	default:
+		return false
+	}
+}
@@ -0,0 +1,94 @@
+package schedule
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestParseCondition(t *testing.T) {
+	cases := []struct {
+		expr    string
+		wantErr bool
+		vars    int
+	}{
+		{expr: "", vars: 0},
+		{expr: "messages.new > 0", vars: 1},
+		{expr: "messages.new>=3 && runs.failed < 2", vars: 2},
+		{expr: "calls != 0 && calls <= 10", vars: 1},
+		{expr: "inbox.unread > 0", wantErr: true},
+		{expr: "messages.new >", wantErr: true},
+		{expr: "messages.new > 0 || calls > 1", wantErr: true},
+		{expr: "messages.new > 0 &&", wantErr: true},
+	}
+	for _, tc := range cases {
+		cond, err := ParseCondition(tc.expr)
+		if tc.wantErr {
+			if err == nil {
+				t.Errorf("ParseCondition(%q) expected error", tc.expr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseCondition(%q) returned error: %v", tc.expr, err)
+			continue
+		}
+		if got := len(cond.Vars()); got != tc.vars {
+			t.Errorf("ParseCondition(%q) vars = %d, want %d", tc.expr, got, tc.vars)
+		}
+	}
+}
+
+func TestConditionEval(t *testing.T) {
+	cond, err := ParseCondition("messages.new > 0 && runs.failed < 3")
+	if err != nil {
+		t.Fatalf("ParseCondition returned error: %v", err)
+	}
+	if !cond.Eval(map[string]int64{ConditionVarNewMessages: 2, ConditionVarFailedRuns: 1}) {
+		t.Error("expected condition to hold")
+	}
+	if cond.Eval(map[string]int64{ConditionVarNewMessages: 0, ConditionVarFailedRuns: 1}) {
+		t.Error("expected condition to fail without new messages")
+	}
+	if cond.Eval(map[string]int64{ConditionVarNewMessages: 2, ConditionVarFailedRuns: 3}) {
+		t.Error("expected condition to fail after repeated failures")
+	}
+}
+
+func fixedConditionVars(vars map[string]int64) func(context.Context, Schedule, []string) (map[string]int64, error) {
+	return func(context.Context, Schedule, []string) (map[string]int64, error) {
+		return vars, nil
+	}
+}
+
+func TestShouldRunSkipsWhenConditionFalse(t *testing.T) {
+	svc := &Service{conditionVars: fixedConditionVars(map[string]int64{ConditionVarNewMessages: 0})}
+	run, err := svc.shouldRun(context.Background(), Schedule{ID: "s1", Condition: "messages.new > 0"})
+	if err != nil {
+		t.Fatalf("shouldRun returned error: %v", err)
+	}
+	if run {
+		t.Fatal("expected schedule to be skipped")
+	}
+}
+
+func TestShouldRunFiresWhenConditionTrue(t *testing.T) {
+	svc := &Service{conditionVars: fixedConditionVars(map[string]int64{ConditionVarNewMessages: 4})}
+	run, err := svc.shouldRun(context.Background(), Schedule{ID: "s1", Condition: "messages.new > 0"})
+	if err != nil {
+		t.Fatalf("shouldRun returned error: %v", err)
+	}
+	if !run {
+		t.Fatal("expected schedule to fire")
+	}
+}
+
+func TestShouldRunWithoutConditionSkipsLookup(t *testing.T) {
+	svc := &Service{conditionVars: func(context.Context, Schedule, []string) (map[string]int64, error) {
+		return nil, errors.New("unexpected lookup")
+	}}
+	run, err := svc.shouldRun(context.Background(), Schedule{ID: "s1"})
+	if err != nil || !run {
+		t.Fatalf("expected unconditional run, got run=%v err=%v", run, err)
+	}
+}
@@ -0,0 +1,84 @@
+package schedule
+
+import (
+	"context"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+const (
+	// dispatchWindow is the longest random delay added to a cron-fired run,
+	// so schedules sharing a cron time start spread over this window.
+	dispatchWindow = 10 * time.Second
+	// dispatchMinGap is the minimum spacing between two dispatched runs.
+	dispatchMinGap = 200 * time.Millisecond
+)
+
+// dispatcher staggers cron-fired runs to avoid a thundering herd when many
+// schedules share the same cron time. Each run is delayed by a random jitter
+// within window, and no two runs start less than minGap apart.
+type dispatcher struct {
+	window time.Duration
+	minGap time.Duration
+
+	mu   sync.Mutex
+	next time.Time
+
+	now    func() time.Time
+	jitter func(window time.Duration) time.Duration
+	sleep  func(ctx context.Context, d time.Duration) error
+}
+
+func newDispatcher(window, minGap time.Duration) *dispatcher {
+	return &dispatcher{
+		window: window,
+		minGap: minGap,
+		now:    time.Now,
+		jitter: randomJitter,
+		sleep:  sleepContext,
+	}
+}
+
+// wait blocks until the caller's dispatch slot, or until ctx is done.
+func (d *dispatcher) wait(ctx context.Context) error {
+	if d == nil {
+		return nil
+	}
+	delay := d.reserve()
+	if delay <= 0 {
+		return nil
+	}
+	return d.sleep(ctx, delay)
+}
+
+// reserve claims the next dispatch slot and returns how long to wait for it.
+func (d *dispatcher) reserve() time.Duration {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	now := d.now()
+	start := now
+	if d.window > 0 {
+		start = start.Add(d.jitter(d.window))
+	}
+	if start.Before(d.next) {
+		start = d.next
+	}
+	d.next = start.Add(d.minGap)
+	return start.Sub(now)
+}
+
+func randomJitter(window time.Duration) time.Duration {
+	return time.Duration(rand.Int64N(int64(window))) //nolint:gosec // G404: jitter does not need crypto/rand
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
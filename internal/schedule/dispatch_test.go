@@ -0,0 +1,108 @@
+package schedule
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingDispatcher returns a dispatcher on a frozen clock that records the
+// delays it would sleep instead of sleeping.
+func recordingDispatcher(window, minGap time.Duration, jitter func(time.Duration) time.Duration) (*dispatcher, func() []time.Duration) {
+	var mu sync.Mutex
+	var delays []time.Duration
+	now := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	d := newDispatcher(window, minGap)
+	d.now = func() time.Time { return now }
+	d.jitter = jitter
+	d.sleep = func(_ context.Context, delay time.Duration) error {
+		mu.Lock()
+		defer mu.Unlock()
+		delays = append(delays, delay)
+		return nil
+	}
+	return d, func() []time.Duration {
+		mu.Lock()
+		defer mu.Unlock()
+		out := append([]time.Duration(nil), delays...)
+		sort.Slice(out, func(i, j int) bool { return out[i] < out[j] })
+		return out
+	}
+}
+
+func TestDispatcherStaggersCoScheduledRuns(t *testing.T) {
+	d, delays := recordingDispatcher(10*time.Second, 200*time.Millisecond, func(time.Duration) time.Duration { return 0 })
+
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := d.wait(context.Background()); err != nil {
+				t.Errorf("wait returned error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// The first run starts immediately and does not sleep.
+	got := delays()
+	want := []time.Duration{200 * time.Millisecond, 400 * time.Millisecond, 600 * time.Millisecond, 800 * time.Millisecond}
+	if len(got) != len(want) {
+		t.Fatalf("expected delays %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("expected delays %v, got %v", want, got)
+		}
+	}
+}
+
+func TestDispatcherSpreadsRunsWithinWindow(t *testing.T) {
+	window := 10 * time.Second
+	offsets := []time.Duration{7 * time.Second, 3 * time.Second, 3100 * time.Millisecond, 9 * time.Second}
+	var i int
+	d, delays := recordingDispatcher(window, 200*time.Millisecond, func(time.Duration) time.Duration {
+		offset := offsets[i]
+		i++
+		return offset
+	})
+
+	for range offsets {
+		if err := d.wait(context.Background()); err != nil {
+			t.Fatalf("wait returned error: %v", err)
+		}
+	}
+
+	got := delays()
+	if len(got) != len(offsets) {
+		t.Fatalf("expected %d delays, got %v", len(offsets), got)
+	}
+	for j := 1; j < len(got); j++ {
+		if gap := got[j] - got[j-1]; gap < 200*time.Millisecond {
+			t.Fatalf("runs %v are closer than the minimum gap", got)
+		}
+	}
+	if got[len(got)-1] >= window {
+		t.Fatalf("expected all runs within the %s window, got %v", window, got)
+	}
+}
+
+func TestDispatcherWaitHonorsContext(t *testing.T) {
+	d := newDispatcher(time.Hour, 0)
+	d.jitter = func(time.Duration) time.Duration { return time.Hour }
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := d.wait(ctx); err == nil {
+		t.Fatal("expected wait to stop when the context is canceled")
+	}
+}
+
+func TestNilDispatcherDoesNotWait(t *testing.T) {
+	var d *dispatcher
+	if err := d.wait(context.Background()); err != nil {
+		t.Fatalf("expected nil dispatcher to return immediately, got %v", err)
+	}
+}
@@ -37,6 +37,19 @@ type Service struct {
 	defaultLocation *time.Location
 	mu              sync.Mutex
 	jobs            map[string]cron.EntryID
+	runMu           sync.Mutex
+	inflight        map[string]*scheduleRun
+	// conditionVars resolves the variables a schedule condition reads. It
+	// defaults to loadConditionVars and is replaced in tests.
+	conditionVars func(ctx context.Context, sched Schedule, names []string) (map[string]int64, error)
+	dispatch      *dispatcher
+}
+
+// scheduleRun is one in-flight schedule execution that concurrent triggers
+// for the same fire time wait on instead of running the command again.
+type scheduleRun struct {
+	done chan struct{}
+	err  error
 }
 
 func NewService(log *slog.Logger, queries *sqlc.Queries, triggerer Triggerer, sessionCreator SessionCreator, runtimeConfig *boot.RuntimeConfig) *Service {
@@ -56,7 +69,10 @@ func NewService(log *slog.Logger, queries *sqlc.Queries, triggerer Triggerer, se
 		logger:          log.With(slog.String("service", "schedule")),
 		defaultLocation: location,
 		jobs:            map[string]cron.EntryID{},
+		inflight:        map[string]*scheduleRun{},
+		dispatch:        newDispatcher(dispatchWindow, dispatchMinGap),
 	}
+	service.conditionVars = service.loadConditionVars
 	c.Start()
 	return service
 }
@@ -87,6 +103,9 @@ func (s *Service) Create(ctx context.Context, botID string, req CreateRequest) (
 	if _, err := s.parser.Parse(req.Pattern); err != nil {
 		return Schedule{}, fmt.Errorf("invalid cron pattern: %w", err)
 	}
+	if _, err := ParseCondition(req.Condition); err != nil {
+		return Schedule{}, err
+	}
 	pgBotID, err := db.ParseUUID(botID)
 	if err != nil {
 		return Schedule{}, err
@@ -98,18 +117,27 @@ func (s *Service) Create(ctx context.Context, botID string, req CreateRequest) (
 		}
 		maxCalls = pgtype.Int4{Int32: int32(*req.MaxCalls.Value), Valid: true} //nolint:gosec // bounds checked above
 	}
+	maxRuntime := pgtype.Int4{Valid: false}
+	if req.MaxRuntimeSeconds.Set {
+		maxRuntime, err = parseMaxRuntime(req.MaxRuntimeSeconds)
+		if err != nil {
+			return Schedule{}, err
+		}
+	}
 	enabled := true
 	if req.Enabled != nil {
 		enabled = *req.Enabled
 	}
 	row, err := s.queries.CreateSchedule(ctx, sqlc.CreateScheduleParams{
-		Name:        req.Name,
-		Description: req.Description,
-		Pattern:     req.Pattern,
-		MaxCalls:    maxCalls,
-		Enabled:     enabled,
-		Command:     req.Command,
-		BotID:       pgBotID,
+		Name:              req.Name,
+		Description:       req.Description,
+		Pattern:           req.Pattern,
+		MaxCalls:          maxCalls,
+		Enabled:           enabled,
+		Command:           req.Command,
+		BotID:             pgBotID,
+		MaxRuntimeSeconds: maxRuntime,
+		Condition:         strings.TrimSpace(req.Condition),
 	})
 	if err != nil {
 		return Schedule{}, err
@@ -192,18 +220,34 @@ func (s *Service) Update(ctx context.Context, id string, req UpdateRequest) (Sch
 			maxCalls = pgtype.Int4{Int32: int32(*req.MaxCalls.Value), Valid: true} //nolint:gosec // bounds checked above
 		}
 	}
+	maxRuntime := existing.MaxRuntimeSeconds
+	if req.MaxRuntimeSeconds.Set {
+		maxRuntime, err = parseMaxRuntime(req.MaxRuntimeSeconds)
+		if err != nil {
+			return Schedule{}, err
+		}
+	}
+	condition := existing.Condition
+	if req.Condition != nil {
+		if _, err := ParseCondition(*req.Condition); err != nil {
+			return Schedule{}, err
+		}
+		condition = strings.TrimSpace(*req.Condition)
+	}
 	enabled := existing.Enabled
 	if req.Enabled != nil {
 		enabled = *req.Enabled
 	}
 	updated, err := s.queries.UpdateSchedule(ctx, sqlc.UpdateScheduleParams{
-		ID:          pgID,
-		Name:        name,
-		Description: description,
-		Pattern:     pattern,
-		MaxCalls:    maxCalls,
-		Enabled:     enabled,
-		Command:     command,
+		ID:                pgID,
+		Name:              name,
+		Description:       description,
+		Pattern:           pattern,
+		MaxCalls:          maxCalls,
+		Enabled:           enabled,
+		Command:           command,
+		MaxRuntimeSeconds: maxRuntime,
+		Condition:         condition,
 	})
 	if err != nil {
 		return Schedule{}, err
@@ -226,6 +270,43 @@ func (s *Service) Delete(ctx context.Context, id string) error {
 	return nil
 }
 
+// PauseBot stops the bot's schedules while it is pending deletion.
+func (s *Service) PauseBot(ctx context.Context, botID string) error {
+	items, err := s.listBotSchedules(ctx, botID)
+	if err != nil {
+		return err
+	}
+	for _, item := range items {
+		s.removeJob(item.ID.String())
+	}
+	return nil
+}
+
+// ResumeBot reschedules the bot's enabled schedules after it is undeleted.
+func (s *Service) ResumeBot(ctx context.Context, botID string) error {
+	items, err := s.listBotSchedules(ctx, botID)
+	if err != nil {
+		return err
+	}
+	for _, item := range items {
+		if err := s.rescheduleJob(ctx, item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Service) listBotSchedules(ctx context.Context, botID string) ([]sqlc.Schedule, error) {
+	if s.queries == nil {
+		return nil, errors.New("schedule queries not configured")
+	}
+	pgBotID, err := db.ParseUUID(botID)
+	if err != nil {
+		return nil, err
+	}
+	return s.queries.ListSchedulesByBot(ctx, pgBotID)
+}
+
 func (s *Service) Trigger(ctx context.Context, scheduleID string) error {
 	if s.triggerer == nil {
 		return errors.New("schedule triggerer not configured")
@@ -237,19 +318,130 @@ func (s *Service) Trigger(ctx context.Context, scheduleID string) error {
 	if !sched.Enabled {
 		return errors.New("schedule is disabled")
 	}
-	return s.runSchedule(ctx, sched)
+	return s.runScheduleOnce(ctx, sched, time.Now())
 }
 
 const scheduleTokenTTL = 10 * time.Minute
 
 // scheduleRunTimeout caps how long a single schedule execution may take.
-// This prevents unbounded Generate() calls from hanging forever.
+// This prevents unbounded Generate() calls from hanging forever. A schedule's
+// max_runtime_seconds can only lower it.
 const scheduleRunTimeout = 5 * time.Minute
 
+// parseMaxRuntime validates a max_runtime_seconds value. Null clears the cap.
+func parseMaxRuntime(value NullableInt) (pgtype.Int4, error) {
+	if value.Value == nil {
+		return pgtype.Int4{Valid: false}, nil
+	}
+	seconds := *value.Value
+	if seconds < 1 || time.Duration(seconds)*time.Second > scheduleRunTimeout {
+		return pgtype.Int4{}, fmt.Errorf("max_runtime_seconds must be between 1 and %d", int(scheduleRunTimeout/time.Second))
+	}
+	return pgtype.Int4{Int32: int32(seconds), Valid: true}, nil //nolint:gosec // bounds checked above
+}
+
+// runtimeLimit returns how long one run of sched may take.
+func runtimeLimit(sched Schedule) time.Duration {
+	if sched.MaxRuntimeSeconds == nil || *sched.MaxRuntimeSeconds <= 0 {
+		return scheduleRunTimeout
+	}
+	return min(time.Duration(*sched.MaxRuntimeSeconds)*time.Second, scheduleRunTimeout)
+}
+
+// errRunTimeout marks a trigger call cut off by the schedule's runtime limit.
+var errRunTimeout = errors.New("schedule run exceeded its max runtime")
+
+// triggerWithLimit calls the triggerer with a deadline of limit. When the
+// deadline, rather than ctx, ends the call, the error wraps errRunTimeout.
+func (s *Service) triggerWithLimit(ctx context.Context, limit time.Duration, botID string, payload TriggerPayload, token string) (TriggerResult, error) {
+	triggerCtx, cancel := context.WithTimeout(ctx, limit)
+	defer cancel()
+	result, err := s.triggerer.TriggerSchedule(triggerCtx, botID, payload, token)
+	if err != nil && ctx.Err() == nil && errors.Is(triggerCtx.Err(), context.DeadlineExceeded) {
+		return TriggerResult{}, fmt.Errorf("%w (%s)", errRunTimeout, limit)
+	}
+	return result, err
+}
+
+// runScheduleOnce runs sched unless a run for the same bot, schedule and fire
+// time is already in flight, in which case it waits for that run and returns
+// its error. This collapses duplicate triggers, such as two cron entries left
+// by a bootstrap race, into a single execution.
+func (s *Service) runScheduleOnce(ctx context.Context, sched Schedule, fireTime time.Time) error {
+	return s.dedupeRun(ctx, scheduleRunKey(sched.BotID, sched.ID, fireTime), func() error {
+		return s.runSchedule(ctx, sched)
+	})
+}
+
+// runScheduled is runScheduleOnce for cron-fired runs: the run first waits
+// for its dispatch slot so co-scheduled runs do not all start at once. The
+// wait happens inside the deduplicated run, so duplicate triggers still
+// collapse into one execution. scheduleRunTimeout starts once the slot is
+// reached, so time spent waiting does not count against the run.
+func (s *Service) runScheduled(ctx context.Context, sched Schedule, fireTime time.Time) error {
+	return s.dedupeRun(ctx, scheduleRunKey(sched.BotID, sched.ID, fireTime), func() error {
+		if err := s.dispatch.wait(ctx); err != nil {
+			return err
+		}
+		runCtx, cancel := context.WithTimeout(ctx, scheduleRunTimeout)
+		defer cancel()
+		return s.runSchedule(runCtx, sched)
+	})
+}
+
+// scheduleRunKey identifies one firing of a schedule. Cron fires at whole
+// seconds, so the fire time is truncated to the second.
+func scheduleRunKey(botID, scheduleID string, fireTime time.Time) string {
+	return botID + "/" + scheduleID + "/" + fireTime.UTC().Truncate(time.Second).Format(time.RFC3339)
+}
+
+// dedupeRun calls run for the first caller with key and makes concurrent
+// callers with the same key wait for its result. The key is released once
+// run returns.
+func (s *Service) dedupeRun(ctx context.Context, key string, run func() error) error {
+	s.runMu.Lock()
+	if s.inflight == nil {
+		s.inflight = map[string]*scheduleRun{}
+	}
+	if existing, ok := s.inflight[key]; ok {
+		s.runMu.Unlock()
+		s.logger.Info("schedule run already in flight, skipping duplicate trigger", slog.String("key", key))
+		select {
+		case <-existing.done:
+			return existing.err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	current := &scheduleRun{done: make(chan struct{})}
+	s.inflight[key] = current
+	s.runMu.Unlock()
+
+	defer func() {
+		s.runMu.Lock()
+		delete(s.inflight, key)
+		s.runMu.Unlock()
+		close(current.done)
+	}()
+	current.err = run()
+	return current.err
+}
+
 func (s *Service) runSchedule(ctx context.Context, sched Schedule) error {
 	if s.triggerer == nil {
 		return errors.New("schedule triggerer not configured")
 	}
+	run, err := s.shouldRun(ctx, sched)
+	if err != nil {
+		return fmt.Errorf("evaluate schedule condition: %w", err)
+	}
+	if !run {
+		s.logger.Info("schedule condition unmet, skipping run", slog.String("schedule_id", sched.ID), slog.String("condition", sched.Condition))
+		if err := s.queries.IncrementScheduleSkips(ctx, toUUID(sched.ID)); err != nil {
+			s.logger.Error("increment schedule skips failed", slog.String("schedule_id", sched.ID), slog.Any("error", err))
+		}
+		return nil
+	}
 	updated, err := s.queries.IncrementScheduleCalls(ctx, toUUID(sched.ID))
 	if err != nil {
 		return err
@@ -293,7 +485,7 @@ func (s *Service) runSchedule(ctx context.Context, sched Schedule) error {
 		return fmt.Errorf("generate trigger token: %w", err)
 	}
 
-	result, triggerErr := s.triggerer.TriggerSchedule(ctx, sched.BotID, TriggerPayload{
+	result, triggerErr := s.triggerWithLimit(ctx, runtimeLimit(sched), sched.BotID, TriggerPayload{
 		ID:          sched.ID,
 		Name:        sched.Name,
 		Description: sched.Description,
@@ -304,7 +496,11 @@ func (s *Service) runSchedule(ctx context.Context, sched Schedule) error {
 		SessionID:   sessionID,
 	}, token)
 	if triggerErr != nil {
-		s.completeLog(ctx, logRow.ID, "error", "", triggerErr.Error(), nil, pgtype.UUID{})
+		status := "error"
+		if errors.Is(triggerErr, errRunTimeout) {
+			status = "timeout"
+		}
+		s.completeLog(ctx, logRow.ID, status, "", triggerErr.Error(), nil, pgtype.UUID{})
 		return triggerErr
 	}
 
@@ -314,6 +510,58 @@ func (s *Service) runSchedule(ctx context.Context, sched Schedule) error {
 	return nil
 }
 
+// shouldRun evaluates sched's condition. Schedules without a condition
+// always run.
+func (s *Service) shouldRun(ctx context.Context, sched Schedule) (bool, error) {
+	cond, err := ParseCondition(sched.Condition)
+	if err != nil {
+		return false, err
+	}
+	if cond.IsZero() {
+		return true, nil
+	}
+	vars, err := s.conditionVars(ctx, sched, cond.Vars())
+	if err != nil {
+		return false, err
+	}
+	return cond.Eval(vars), nil
+}
+
+// loadConditionVars reads the named condition variables for sched.
+func (s *Service) loadConditionVars(ctx context.Context, sched Schedule, names []string) (map[string]int64, error) {
+	vars := make(map[string]int64, len(names))
+	for _, name := range names {
+		switch name {
+		case ConditionVarCalls:
+			vars[name] = int64(sched.CurrentCalls)
+		case ConditionVarFailedRuns:
+			logs, _, err := s.ListLogsBySchedule(ctx, sched.ID, 50, 0)
+			if err != nil {
+				return nil, err
+			}
+			vars[name] = int64(ConsecutiveFailures(logs).Count)
+		case ConditionVarNewMessages:
+			since := sched.CreatedAt
+			logs, _, err := s.ListLogsBySchedule(ctx, sched.ID, 1, 0)
+			if err != nil {
+				return nil, err
+			}
+			if len(logs) > 0 {
+				since = logs[0].StartedAt
+			}
+			count, err := s.queries.CountBotUserMessagesSince(ctx, sqlc.CountBotUserMessagesSinceParams{
+				BotID: toUUID(sched.BotID),
+				Since: pgtype.Timestamptz{Time: since, Valid: true},
+			})
+			if err != nil {
+				return nil, err
+			}
+			vars[name] = count
+		}
+	}
+	return vars, nil
+}
+
 func (s *Service) completeLog(ctx context.Context, logID pgtype.UUID, status, resultText, errorMessage string, usageBytes []byte, modelID pgtype.UUID) {
 	if !logID.Valid {
 		return
@@ -490,9 +738,7 @@ func (s *Service) scheduleJob(ctx context.Context, schedule sqlc.Schedule) error
 		return errors.New("schedule id missing")
 	}
 	job := func() {
-		runCtx, runCancel := context.WithTimeout(context.WithoutCancel(ctx), scheduleRunTimeout)
-		defer runCancel()
-		if err := s.runSchedule(runCtx, toSchedule(schedule)); err != nil {
+		if err := s.runScheduled(context.WithoutCancel(ctx), toSchedule(schedule), time.Now()); err != nil {
 			s.logger.Error("scheduled job failed", slog.String("schedule_id", schedule.ID.String()), slog.Any("error", err))
 		}
 	}
@@ -543,11 +789,17 @@ func toSchedule(row sqlc.Schedule) Schedule {
 		Enabled:      row.Enabled,
 		Command:      row.Command,
 		BotID:        row.BotID.String(),
+		Condition:    row.Condition,
+		SkippedCalls: int(row.SkippedCalls),
 	}
 	if row.MaxCalls.Valid {
 		maxCalls := int(row.MaxCalls.Int32)
 		item.MaxCalls = &maxCalls
 	}
+	if row.MaxRuntimeSeconds.Valid {
+		maxRuntime := int(row.MaxRuntimeSeconds.Int32)
+		item.MaxRuntimeSeconds = &maxRuntime
+	}
 	if row.CreatedAt.Valid {
 		item.CreatedAt = row.CreatedAt.Time
 	}
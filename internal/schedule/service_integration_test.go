@@ -6,7 +6,10 @@ import (
 	"log/slog"
 	"os"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -154,3 +157,113 @@ func TestIntegrationTrigger_CallsTriggererWithCorrectPayload(t *testing.T) {
 		t.Errorf("token should have Bearer prefix, got: %s", mock.token)
 	}
 }
+
+// slowTriggerer counts calls and holds each one long enough for a concurrent
+// duplicate trigger to arrive.
+type slowTriggerer struct {
+	calls atomic.Int32
+}
+
+func (m *slowTriggerer) TriggerSchedule(_ context.Context, _ string, _ schedule.TriggerPayload, _ string) (schedule.TriggerResult, error) {
+	m.calls.Add(1)
+	time.Sleep(200 * time.Millisecond)
+	return schedule.TriggerResult{Status: "ok"}, nil
+}
+
+func TestIntegrationTrigger_ConcurrentDuplicateRunsOnce(t *testing.T) {
+	_, queries, pool, _, cleanup := setupScheduleIntegrationTest(t)
+	defer cleanup()
+
+	triggerer := &slowTriggerer{}
+	svc := schedule.NewService(slog.New(slog.DiscardHandler), queries, triggerer, nil, &boot.RuntimeConfig{JwtSecret: "integration-test-jwt-secret"})
+
+	ctx := context.Background()
+	ownerUserID, botID, scheduleID := createUserBotAndSchedule(ctx, t, queries)
+	defer cleanupScheduleTestData(ctx, t, queries, pool, ownerUserID, botID, scheduleID)
+
+	// Align to the start of a second so both triggers share a fire time.
+	time.Sleep(time.Until(time.Now().Truncate(time.Second).Add(time.Second)))
+	var wg sync.WaitGroup
+	for range 2 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := svc.Trigger(ctx, scheduleID); err != nil {
+				t.Errorf("Trigger failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := triggerer.calls.Load(); got != 1 {
+		t.Fatalf("expected a single triggerer call, got %d", got)
+	}
+}
+
+// runawayTriggerer blocks until the run is canceled.
+type runawayTriggerer struct{}
+
+func (runawayTriggerer) TriggerSchedule(ctx context.Context, _ string, _ schedule.TriggerPayload, _ string) (schedule.TriggerResult, error) {
+	<-ctx.Done()
+	return schedule.TriggerResult{}, ctx.Err()
+}
+
+func TestIntegrationTrigger_MaxRuntimeRecordsTimeout(t *testing.T) {
+	_, queries, pool, _, cleanup := setupScheduleIntegrationTest(t)
+	defer cleanup()
+
+	svc := schedule.NewService(slog.New(slog.DiscardHandler), queries, runawayTriggerer{}, nil, &boot.RuntimeConfig{JwtSecret: "integration-test-jwt-secret"})
+
+	ctx := context.Background()
+	ownerUserID, botID, scheduleID := createUserBotAndSchedule(ctx, t, queries)
+	defer cleanupScheduleTestData(ctx, t, queries, pool, ownerUserID, botID, scheduleID)
+
+	one := 1
+	if _, err := svc.Update(ctx, scheduleID, schedule.UpdateRequest{MaxRuntimeSeconds: schedule.NullableInt{Set: true, Value: &one}}); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+
+	started := time.Now()
+	if err := svc.Trigger(ctx, scheduleID); err == nil {
+		t.Fatal("expected Trigger to fail on timeout")
+	}
+	if elapsed := time.Since(started); elapsed > 10*time.Second {
+		t.Fatalf("expected run to be canceled after ~1s, took %s", elapsed)
+	}
+
+	logs, _, err := svc.ListLogsBySchedule(ctx, scheduleID, 10, 0)
+	if err != nil {
+		t.Fatalf("ListLogsBySchedule failed: %v", err)
+	}
+	if len(logs) != 1 || logs[0].Status != "timeout" {
+		t.Fatalf("expected one timeout log, got %+v", logs)
+	}
+}
+
+func TestIntegrationTrigger_UnmetConditionSkipsRun(t *testing.T) {
+	svc, queries, pool, mock, cleanup := setupScheduleIntegrationTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	ownerUserID, botID, scheduleID := createUserBotAndSchedule(ctx, t, queries)
+	defer cleanupScheduleTestData(ctx, t, queries, pool, ownerUserID, botID, scheduleID)
+
+	condition := "messages.new > 0"
+	if _, err := svc.Update(ctx, scheduleID, schedule.UpdateRequest{Condition: &condition}); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if err := svc.Trigger(ctx, scheduleID); err != nil {
+		t.Fatalf("Trigger failed: %v", err)
+	}
+	if mock.called {
+		t.Fatal("expected triggerer not to be called while the condition is unmet")
+	}
+
+	sched, err := svc.Get(ctx, scheduleID)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if sched.SkippedCalls != 1 || sched.CurrentCalls != 0 {
+		t.Fatalf("expected one skipped run and no calls, got skipped=%d calls=%d", sched.SkippedCalls, sched.CurrentCalls)
+	}
+}
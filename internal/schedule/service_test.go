@@ -1,8 +1,12 @@
 package schedule
 
 import (
+	"context"
+	"errors"
 	"log/slog"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -73,3 +77,161 @@ func TestGenerateTriggerToken_EmptyUserID(t *testing.T) {
 		t.Fatal("expected error for empty user ID")
 	}
 }
+
+// countingTriggerer counts calls and blocks each one until release is closed.
+type countingTriggerer struct {
+	calls   atomic.Int32
+	started chan struct{}
+	release chan struct{}
+}
+
+func (c *countingTriggerer) TriggerSchedule(_ context.Context, _ string, _ TriggerPayload, _ string) (TriggerResult, error) {
+	if c.calls.Add(1) == 1 {
+		close(c.started)
+	}
+	<-c.release
+	return TriggerResult{Status: "ok"}, nil
+}
+
+func TestDedupeRunCollapsesConcurrentTriggers(t *testing.T) {
+	triggerer := &countingTriggerer{started: make(chan struct{}), release: make(chan struct{})}
+	svc := &Service{triggerer: triggerer, logger: slog.New(slog.DiscardHandler)}
+	fireTime := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	fire := func(at time.Time) error {
+		key := scheduleRunKey("bot-1", "sched-1", at)
+		return svc.dedupeRun(context.Background(), key, func() error {
+			_, err := svc.triggerer.TriggerSchedule(context.Background(), "bot-1", TriggerPayload{ID: "sched-1"}, "")
+			return err
+		})
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, 2)
+	wg.Add(2)
+	go func() { defer wg.Done(); errs[0] = fire(fireTime) }()
+	<-triggerer.started
+	go func() { defer wg.Done(); errs[1] = fire(fireTime.Add(300 * time.Millisecond)) }()
+	time.Sleep(50 * time.Millisecond)
+	close(triggerer.release)
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("trigger %d: %v", i, err)
+		}
+	}
+	if got := triggerer.calls.Load(); got != 1 {
+		t.Fatalf("expected a single triggerer call, got %d", got)
+	}
+}
+
+func TestDedupeRunRunsDistinctFireTimes(t *testing.T) {
+	triggerer := &countingTriggerer{started: make(chan struct{}), release: make(chan struct{})}
+	close(triggerer.release)
+	svc := &Service{triggerer: triggerer, logger: slog.New(slog.DiscardHandler)}
+	fireTime := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+
+	for _, at := range []time.Time{fireTime, fireTime.Add(time.Second), fireTime} {
+		err := svc.dedupeRun(context.Background(), scheduleRunKey("bot-1", "sched-1", at), func() error {
+			_, err := svc.triggerer.TriggerSchedule(context.Background(), "bot-1", TriggerPayload{}, "")
+			return err
+		})
+		if err != nil {
+			t.Fatalf("trigger: %v", err)
+		}
+	}
+	if got := triggerer.calls.Load(); got != 3 {
+		t.Fatalf("expected sequential and distinct fire times to each run, got %d calls", got)
+	}
+}
+
+func TestConsecutiveFailuresSkipsRunsInProgress(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	done := now.Add(time.Second)
+	logs := []Log{
+		{Status: "ok", StartedAt: now},
+		{Status: "error", ErrorMessage: "latest", StartedAt: now.Add(-time.Hour), CompletedAt: &done},
+		{Status: "error", ErrorMessage: "older", StartedAt: now.Add(-2 * time.Hour), CompletedAt: &done},
+		{Status: "ok", StartedAt: now.Add(-3 * time.Hour), CompletedAt: &done},
+		{Status: "error", StartedAt: now.Add(-4 * time.Hour), CompletedAt: &done},
+	}
+
+	streak := ConsecutiveFailures(logs)
+	if streak.Count != 2 {
+		t.Fatalf("expected 2 consecutive failures, got %d", streak.Count)
+	}
+	if streak.LastError != "latest" || !streak.LastRunAt.Equal(now.Add(-time.Hour)) {
+		t.Fatalf("unexpected newest failure: %+v", streak)
+	}
+	if got := ConsecutiveFailures(nil); got.Count != 0 {
+		t.Fatalf("expected no failures for empty history, got %+v", got)
+	}
+}
+
+// slowTriggerer blocks until its context is done, like a runaway command.
+type slowTriggerer struct {
+	canceled chan struct{}
+}
+
+func (s *slowTriggerer) TriggerSchedule(ctx context.Context, _ string, _ TriggerPayload, _ string) (TriggerResult, error) {
+	<-ctx.Done()
+	close(s.canceled)
+	return TriggerResult{}, ctx.Err()
+}
+
+func TestTriggerWithLimitCancelsSlowRunEarly(t *testing.T) {
+	triggerer := &slowTriggerer{canceled: make(chan struct{})}
+	svc := &Service{triggerer: triggerer, logger: slog.New(slog.DiscardHandler)}
+
+	started := time.Now()
+	_, err := svc.triggerWithLimit(context.Background(), 50*time.Millisecond, "bot-1", TriggerPayload{}, "")
+	if !errors.Is(err, errRunTimeout) {
+		t.Fatalf("expected run timeout error, got %v", err)
+	}
+	if elapsed := time.Since(started); elapsed > 2*time.Second {
+		t.Fatalf("expected early cancellation, took %s", elapsed)
+	}
+	select {
+	case <-triggerer.canceled:
+	default:
+		t.Fatal("expected the trigger call to observe cancellation")
+	}
+}
+
+func TestTriggerWithLimitKeepsCallerCancellation(t *testing.T) {
+	svc := &Service{triggerer: &slowTriggerer{canceled: make(chan struct{})}, logger: slog.New(slog.DiscardHandler)}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := svc.triggerWithLimit(ctx, time.Minute, "bot-1", TriggerPayload{}, "")
+	if errors.Is(err, errRunTimeout) || !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected caller cancellation, got %v", err)
+	}
+}
+
+func TestRuntimeLimit(t *testing.T) {
+	seconds := func(n int) *int { return &n }
+	cases := []struct {
+		name string
+		max  *int
+		want time.Duration
+	}{
+		{name: "unset uses default", max: nil, want: scheduleRunTimeout},
+		{name: "per-schedule cap", max: seconds(30), want: 30 * time.Second},
+		{name: "cannot exceed default", max: seconds(3600), want: scheduleRunTimeout},
+	}
+	for _, tc := range cases {
+		if got := runtimeLimit(Schedule{MaxRuntimeSeconds: tc.max}); got != tc.want {
+			t.Fatalf("%s: got %s, want %s", tc.name, got, tc.want)
+		}
+	}
+	if _, err := parseMaxRuntime(NullableInt{Set: true, Value: seconds(0)}); err == nil {
+		t.Fatal("expected zero max runtime to be rejected")
+	}
+	if _, err := parseMaxRuntime(NullableInt{Set: true, Value: seconds(301)}); err == nil {
+		t.Fatal("expected max runtime above the default timeout to be rejected")
+	}
+	if got, err := parseMaxRuntime(NullableInt{Set: true}); err != nil || got.Valid {
+		t.Fatalf("expected null to clear the cap, got %+v, %v", got, err)
+	}
+}
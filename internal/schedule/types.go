@@ -6,17 +6,20 @@ import (
 )
 
 type Schedule struct {
-	ID           string    `json:"id"`
-	Name         string    `json:"name"`
-	Description  string    `json:"description"`
-	Pattern      string    `json:"pattern"`
-	MaxCalls     *int      `json:"max_calls,omitempty"`
-	CurrentCalls int       `json:"current_calls"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
-	Enabled      bool      `json:"enabled"`
-	Command      string    `json:"command"`
-	BotID        string    `json:"bot_id"`
+	ID                string    `json:"id"`
+	Name              string    `json:"name"`
+	Description       string    `json:"description"`
+	Pattern           string    `json:"pattern"`
+	MaxCalls          *int      `json:"max_calls,omitempty"`
+	CurrentCalls      int       `json:"current_calls"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+	Enabled           bool      `json:"enabled"`
+	Command           string    `json:"command"`
+	BotID             string    `json:"bot_id"`
+	MaxRuntimeSeconds *int      `json:"max_runtime_seconds,omitempty"`
+	Condition         string    `json:"condition,omitempty"`
+	SkippedCalls      int       `json:"skipped_calls"`
 }
 
 type NullableInt struct {
@@ -50,21 +53,25 @@ func (n *NullableInt) UnmarshalJSON(data []byte) error {
 }
 
 type CreateRequest struct {
-	Name        string      `json:"name"`
-	Description string      `json:"description"`
-	Pattern     string      `json:"pattern"`
-	MaxCalls    NullableInt `json:"max_calls,omitempty"`
-	Command     string      `json:"command"`
-	Enabled     *bool       `json:"enabled,omitempty"`
+	Name              string      `json:"name"`
+	Description       string      `json:"description"`
+	Pattern           string      `json:"pattern"`
+	MaxCalls          NullableInt `json:"max_calls,omitempty"`
+	Command           string      `json:"command"`
+	Enabled           *bool       `json:"enabled,omitempty"`
+	MaxRuntimeSeconds NullableInt `json:"max_runtime_seconds,omitempty"`
+	Condition         string      `json:"condition,omitempty"`
 }
 
 type UpdateRequest struct {
-	Name        *string     `json:"name,omitempty"`
-	Description *string     `json:"description,omitempty"`
-	Pattern     *string     `json:"pattern,omitempty"`
-	MaxCalls    NullableInt `json:"max_calls,omitempty"`
-	Command     *string     `json:"command,omitempty"`
-	Enabled     *bool       `json:"enabled,omitempty"`
+	Name              *string     `json:"name,omitempty"`
+	Description       *string     `json:"description,omitempty"`
+	Pattern           *string     `json:"pattern,omitempty"`
+	MaxCalls          NullableInt `json:"max_calls,omitempty"`
+	Command           *string     `json:"command,omitempty"`
+	Enabled           *bool       `json:"enabled,omitempty"`
+	MaxRuntimeSeconds NullableInt `json:"max_runtime_seconds,omitempty"`
+	Condition         *string     `json:"condition,omitempty"`
 }
 
 type ListResponse struct {
@@ -88,3 +95,35 @@ type ListLogsResponse struct {
 	Items      []Log `json:"items"`
 	TotalCount int64 `json:"total_count"`
 }
+
+// DefaultFailureAlertThreshold is the number of consecutive failed runs after
+// which a schedule is reported as failing.
+const DefaultFailureAlertThreshold = 3
+
+// FailureStreak describes the failed runs at the head of a schedule's history.
+type FailureStreak struct {
+	Count     int
+	LastError string
+	LastRunAt time.Time
+}
+
+// ConsecutiveFailures counts the completed runs that failed in a row, starting
+// from the newest. logs must be ordered newest first, as ListLogsBySchedule
+// returns them. Runs still in progress are skipped.
+func ConsecutiveFailures(logs []Log) FailureStreak {
+	var streak FailureStreak
+	for _, log := range logs {
+		if log.CompletedAt == nil {
+			continue
+		}
+		if log.Status != "error" && log.Status != "timeout" {
+			break
+		}
+		if streak.Count == 0 {
+			streak.LastError = log.ErrorMessage
+			streak.LastRunAt = log.StartedAt
+		}
+		streak.Count++
+	}
+	return streak
+}
@@ -0,0 +1,197 @@
+package server
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+	"sync/atomic"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+
+	"github.com/memohai/memoh/internal/config"
+)
+
+const redactedValue = "[REDACTED]"
+
+// Access log fields.
+const (
+	accessFieldMethod    = "method"
+	accessFieldURI       = "uri"
+	accessFieldRoute     = "route"
+	accessFieldStatus    = "status"
+	accessFieldLatency   = "latency"
+	accessFieldRequestID = "request_id"
+	accessFieldRemoteIP  = "remote_ip"
+	accessFieldUserAgent = "user_agent"
+	accessFieldBytesIn   = "bytes_in"
+	accessFieldBytesOut  = "bytes_out"
+	accessFieldError     = "error"
+)
+
+var accessLogFields = []string{
+	accessFieldMethod, accessFieldURI, accessFieldRoute, accessFieldStatus,
+	accessFieldLatency, accessFieldRequestID, accessFieldRemoteIP,
+	accessFieldUserAgent, accessFieldBytesIn, accessFieldBytesOut, accessFieldError,
+}
+
+var defaultAccessLogFields = []string{
+	accessFieldMethod, accessFieldURI, accessFieldStatus, accessFieldLatency,
+	accessFieldRequestID, accessFieldRemoteIP,
+}
+
+// sensitiveHeaders are request headers logged as redactedValue.
+var sensitiveHeaders = []string{
+	echo.HeaderAuthorization,
+	"Proxy-Authorization",
+	echo.HeaderCookie,
+	"X-Api-Key",
+}
+
+// sensitiveQueryParams are query parameters logged as redactedValue. JWTs
+// may be passed as ?token= and media URLs carry a signature.
+var sensitiveQueryParams = []string{"token", "access_token", "signature"}
+
+// AccessLogMiddleware logs one "request" line per HTTP request with the
+// configured fields and request headers. Requests to the sample paths are
+// logged one in SampleEvery unless they fail. Credentials in headers and
+// query parameters are redacted. Pair it with middleware.RequestID so every
+// line carries a request ID.
+func AccessLogMiddleware(log *slog.Logger, cfg config.AccessLogConfig) echo.MiddlewareFunc {
+	fields := map[string]bool{}
+	for _, field := range cfg.Fields {
+		field = strings.ToLower(strings.TrimSpace(field))
+		if !slices.Contains(accessLogFields, field) {
+			log.Warn("unknown access log field ignored", slog.String("field", field))
+			continue
+		}
+		fields[field] = true
+	}
+	if len(fields) == 0 {
+		for _, field := range defaultAccessLogFields {
+			fields[field] = true
+		}
+	}
+	headers := trimmedList(cfg.Headers)
+	for i, header := range headers {
+		headers[i] = http.CanonicalHeaderKey(header)
+	}
+	sampler := newAccessLogSampler(trimmedList(cfg.SamplePaths), cfg.SampleEvery)
+
+	return middleware.RequestLoggerWithConfig(middleware.RequestLoggerConfig{
+		LogMethod:        true,
+		LogURI:           true,
+		LogRoutePath:     true,
+		LogStatus:        true,
+		LogLatency:       true,
+		LogRequestID:     true,
+		LogRemoteIP:      true,
+		LogUserAgent:     true,
+		LogContentLength: true,
+		LogResponseSize:  true,
+		LogError:         true,
+		LogHeaders:       headers,
+		HandleError:      true,
+		LogValuesFunc: func(c echo.Context, v middleware.RequestLoggerValues) error {
+			failed := v.Error != nil || v.Status >= http.StatusBadRequest
+			if !failed && !sampler.keep(c.Request().URL.Path) {
+				return nil
+			}
+			attrs := make([]slog.Attr, 0, len(fields)+1)
+			add := func(field string, attr slog.Attr) {
+				if fields[field] {
+					attrs = append(attrs, attr)
+				}
+			}
+			add(accessFieldMethod, slog.String("method", v.Method))
+			add(accessFieldURI, slog.String("uri", redactURI(v.URI)))
+			add(accessFieldRoute, slog.String("route", v.RoutePath))
+			add(accessFieldStatus, slog.Int("status", v.Status))
+			add(accessFieldLatency, slog.Duration("latency", v.Latency))
+			add(accessFieldRequestID, slog.String("request_id", v.RequestID))
+			add(accessFieldRemoteIP, slog.String("remote_ip", v.RemoteIP))
+			add(accessFieldUserAgent, slog.String("user_agent", v.UserAgent))
+			add(accessFieldBytesIn, slog.String("bytes_in", v.ContentLength))
+			add(accessFieldBytesOut, slog.Int64("bytes_out", v.ResponseSize))
+			if v.Error != nil {
+				add(accessFieldError, slog.String("error", v.Error.Error()))
+			}
+			if len(headers) > 0 {
+				headerAttrs := make([]any, 0, len(v.Headers))
+				for _, name := range headers {
+					if values, ok := v.Headers[name]; ok {
+						headerAttrs = append(headerAttrs, slog.String(name, redactHeader(name, values)))
+					}
+				}
+				attrs = append(attrs, slog.Group("headers", headerAttrs...))
+			}
+			log.LogAttrs(context.Background(), slog.LevelInfo, "request", attrs...)
+			return nil
+		},
+	})
+}
+
+func redactHeader(name string, values []string) string {
+	for _, sensitive := range sensitiveHeaders {
+		if strings.EqualFold(name, sensitive) {
+			return redactedValue
+		}
+	}
+	return strings.Join(values, ", ")
+}
+
+// redactURI replaces the values of sensitive query parameters in uri.
+func redactURI(uri string) string {
+	base, rawQuery, ok := strings.Cut(uri, "?")
+	if !ok {
+		return uri
+	}
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return base + "?" + redactedValue
+	}
+	changed := false
+	for _, param := range sensitiveQueryParams {
+		if query.Has(param) {
+			query.Set(param, redactedValue)
+			changed = true
+		}
+	}
+	if !changed {
+		return uri
+	}
+	return base + "?" + query.Encode()
+}
+
+// accessLogSampler lets one in every successful requests through per sample
+// path pattern. Patterns use path.Match syntax, so "*" matches one segment.
+type accessLogSampler struct {
+	patterns []string
+	every    uint64
+	counts   []atomic.Uint64
+}
+
+func newAccessLogSampler(patterns []string, every int) *accessLogSampler {
+	if len(patterns) == 0 || every <= 1 {
+		return &accessLogSampler{}
+	}
+	return &accessLogSampler{
+		patterns: patterns,
+		every:    uint64(every),
+		counts:   make([]atomic.Uint64, len(patterns)),
+	}
+}
+
+// keep reports whether a successful request to urlPath should be logged.
+func (s *accessLogSampler) keep(urlPath string) bool {
+	for i, pattern := range s.patterns {
+		if ok, _ := path.Match(pattern, urlPath); ok {
+			return s.counts[i].Add(1)%s.every == 1
+		}
+	}
+	return true
+}
@@ -0,0 +1,128 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+
+	"github.com/memohai/memoh/internal/config"
+)
+
+// newAccessLogEcho returns a server logging to the returned buffer as JSON.
+func newAccessLogEcho(cfg config.AccessLogConfig) (*echo.Echo, *bytes.Buffer) {
+	var buf bytes.Buffer
+	log := slog.New(slog.NewJSONHandler(&buf, nil))
+	e := echo.New()
+	e.Use(middleware.RequestID())
+	e.Use(AccessLogMiddleware(log, cfg))
+	e.GET("/bots/:bot_id/messages", func(c echo.Context) error { return c.String(http.StatusOK, "ok") })
+	e.GET("/health", func(c echo.Context) error { return c.NoContent(http.StatusOK) })
+	e.GET("/fail", func(_ echo.Context) error { return echo.NewHTTPError(http.StatusBadGateway, "upstream down") })
+	return e, &buf
+}
+
+func accessLogLines(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var lines []map[string]any
+	for _, raw := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if raw == "" {
+			continue
+		}
+		var line map[string]any
+		if err := json.Unmarshal([]byte(raw), &line); err != nil {
+			t.Fatalf("decode log line %q: %v", raw, err)
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func TestAccessLogFields(t *testing.T) {
+	t.Parallel()
+
+	e, buf := newAccessLogEcho(config.AccessLogConfig{
+		Fields:  []string{"method", "uri", "route", "status", "latency", "request_id", "bytes_out"},
+		Headers: []string{"authorization", "User-Agent"},
+	})
+	req := httptest.NewRequest(http.MethodGet, "/bots/b1/messages?limit=5&token=secret-jwt", nil)
+	req.Header.Set(echo.HeaderAuthorization, "Bearer secret-jwt")
+	req.Header.Set("User-Agent", "webui/1.0")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	lines := accessLogLines(t, buf)
+	if len(lines) != 1 {
+		t.Fatalf("expected one log line, got %d", len(lines))
+	}
+	line := lines[0]
+	if line["msg"] != "request" || line["method"] != "GET" || line["route"] != "/bots/:bot_id/messages" {
+		t.Fatalf("unexpected log line: %v", line)
+	}
+	if line["status"] != float64(http.StatusOK) || line["bytes_out"] != float64(2) {
+		t.Fatalf("unexpected status or size: %v", line)
+	}
+	if _, ok := line["latency"]; !ok {
+		t.Fatal("expected latency")
+	}
+	if id := rec.Header().Get(echo.HeaderXRequestID); id == "" || line["request_id"] != id {
+		t.Fatalf("request_id = %v, response header %q", line["request_id"], id)
+	}
+	if uri, _ := line["uri"].(string); strings.Contains(uri, "secret-jwt") || !strings.Contains(uri, "limit=5") {
+		t.Fatalf("expected token to be redacted from uri, got %q", uri)
+	}
+	if _, ok := line["remote_ip"]; ok {
+		t.Fatal("expected remote_ip to be left out")
+	}
+	headers, _ := line["headers"].(map[string]any)
+	if headers["Authorization"] != redactedValue || headers["User-Agent"] != "webui/1.0" {
+		t.Fatalf("unexpected headers: %v", headers)
+	}
+	if strings.Contains(buf.String(), "secret-jwt") {
+		t.Fatal("credentials leaked into the access log")
+	}
+}
+
+func TestAccessLogSamplesHighVolumePaths(t *testing.T) {
+	t.Parallel()
+
+	e, buf := newAccessLogEcho(config.AccessLogConfig{
+		SamplePaths: []string{"/health", "/bots/*/messages", "/fail"},
+		SampleEvery: 3,
+	})
+	for i := 0; i < 6; i++ {
+		e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/health", nil))
+	}
+	e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/fail", nil))
+
+	lines := accessLogLines(t, buf)
+	if len(lines) != 3 {
+		t.Fatalf("expected 2 sampled lines and the failure, got %d", len(lines))
+	}
+	failure := lines[2]
+	if failure["status"] != float64(http.StatusBadGateway) || failure["uri"] != "/fail" {
+		t.Fatalf("expected the failed request to be logged, got %v", failure)
+	}
+}
+
+func TestRedactURI(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]string{
+		"/bots":                            "/bots",
+		"/bots?limit=5":                    "/bots?limit=5",
+		"/media/x?expires=1&signature=abc": "/media/x?expires=1&signature=%5BREDACTED%5D",
+		"/bots?token=%zz":                  "/bots?" + redactedValue,
+	}
+	for uri, want := range cases {
+		if got := redactURI(uri); got != want {
+			t.Fatalf("redactURI(%q) = %q, want %q", uri, got, want)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package server
+
+import (
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+
+	"github.com/memohai/memoh/internal/config"
+)
+
+// CORSMiddleware returns the CORS middleware for cfg, or nil when no origins
+// are allowed. It answers preflight requests itself, so it must run before
+// authentication.
+func CORSMiddleware(cfg config.CORSConfig) echo.MiddlewareFunc {
+	origins := trimmedList(cfg.AllowOrigins)
+	if len(origins) == 0 {
+		return nil
+	}
+	for i, origin := range origins {
+		origins[i] = strings.TrimRight(origin, "/")
+	}
+	corsCfg := middleware.CORSConfig{
+		AllowOrigins:     origins,
+		AllowMethods:     trimmedList(cfg.AllowMethods),
+		AllowHeaders:     trimmedList(cfg.AllowHeaders),
+		AllowCredentials: cfg.AllowCredentials,
+	}
+	if len(corsCfg.AllowMethods) == 0 {
+		corsCfg.AllowMethods = middleware.DefaultCORSConfig.AllowMethods
+	}
+	return middleware.CORSWithConfig(corsCfg)
+}
+
+func trimmedList(values []string) []string {
+	out := make([]string, 0, len(values))
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
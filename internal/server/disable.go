@@ -0,0 +1,43 @@
+package server
+
+import (
+	"log/slog"
+	"strings"
+)
+
+// NamedHandler is a Handler that can be disabled in config by its name.
+type NamedHandler interface {
+	Handler
+	HandlerName() string
+}
+
+// FilterHandlers drops the named handlers listed in disabled, so their
+// routes are never registered and answer 404. Names are matched case
+// insensitively; names matching no handler are logged and ignored.
+func FilterHandlers(log *slog.Logger, handlers []Handler, disabled []string) []Handler {
+	off := map[string]bool{}
+	for _, name := range trimmedList(disabled) {
+		off[strings.ToLower(name)] = false
+	}
+	if len(off) == 0 {
+		return handlers
+	}
+
+	kept := make([]Handler, 0, len(handlers))
+	for _, h := range handlers {
+		if named, ok := h.(NamedHandler); ok {
+			name := strings.ToLower(named.HandlerName())
+			if _, disable := off[name]; disable {
+				off[name] = true
+				continue
+			}
+		}
+		kept = append(kept, h)
+	}
+	for name, matched := range off {
+		if !matched {
+			log.Warn("unknown disabled handler ignored", slog.String("handler", name))
+		}
+	}
+	return kept
+}
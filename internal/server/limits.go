@@ -0,0 +1,111 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+// bulkUploadRoutes are bot routes that accept archives and files larger
+// than the body limit.
+var bulkUploadRoutes = []string{
+	"/container/data/import",
+	"/container/fs/upload",
+}
+
+// streamingRoutes are bot routes whose responses stream for as long as the
+// client stays connected, that may stream MCP responses, or that move whole
+// archives and files.
+var streamingRoutes = []string{
+	"/web/stream",
+	"/web/ws",
+	"/messages/events",
+	"/container/logs",
+	"/container/terminal/ws",
+	"/container/data/export",
+	"/container/data/import",
+	"/container/fs/upload",
+	"/container/fs/download",
+	"/tools",
+}
+
+// BodyLimitMiddleware rejects request bodies over maxMB megabytes with 413,
+// except on bulk upload routes. It returns nil when maxMB is not positive.
+func BodyLimitMiddleware(maxMB int) echo.MiddlewareFunc {
+	if maxMB <= 0 {
+		return nil
+	}
+	return middleware.BodyLimitWithConfig(middleware.BodyLimitConfig{
+		Skipper: func(c echo.Context) bool {
+			route, ok := botRoute(c.Request().URL.Path)
+			return ok && slices.Contains(bulkUploadRoutes, route)
+		},
+		Limit: strconv.Itoa(maxMB) + "M",
+	})
+}
+
+// RequestTimeoutMiddleware gives each request a context deadline of
+// timeoutSeconds and answers 503 when a handler fails after it passes.
+// Streaming and bulk transfer requests are exempt. It returns nil when
+// timeoutSeconds is not positive.
+func RequestTimeoutMiddleware(timeoutSeconds int) echo.MiddlewareFunc {
+	if timeoutSeconds <= 0 {
+		return nil
+	}
+	return requestTimeout(time.Duration(timeoutSeconds) * time.Second)
+}
+
+func requestTimeout(timeout time.Duration) echo.MiddlewareFunc {
+	return middleware.ContextTimeoutWithConfig(middleware.ContextTimeoutConfig{
+		Skipper: func(c echo.Context) bool {
+			return isStreamingRequest(c.Request())
+		},
+		// Handlers often turn the context error into an HTTP error of their
+		// own, so check the request context rather than err.
+		ErrorHandler: func(err error, c echo.Context) error {
+			if errors.Is(c.Request().Context().Err(), context.DeadlineExceeded) && !c.Response().Committed {
+				return echo.NewHTTPError(http.StatusServiceUnavailable, "request timed out")
+			}
+			return err
+		},
+		Timeout: timeout,
+	})
+}
+
+// isStreamingRequest reports whether r opens a long-lived stream: a
+// WebSocket, an event stream, container creation (which streams progress),
+// an MCP stdio session or one of the streaming bot routes.
+func isStreamingRequest(r *http.Request) bool {
+	if strings.EqualFold(r.Header.Get(echo.HeaderUpgrade), "websocket") ||
+		strings.Contains(r.Header.Get(echo.HeaderAccept), "text/event-stream") {
+		return true
+	}
+	route, ok := botRoute(r.URL.Path)
+	if !ok {
+		return false
+	}
+	if r.Method == http.MethodPost && route == "/container" {
+		return true
+	}
+	return slices.Contains(streamingRoutes, route) || strings.HasPrefix(route, "/mcp-stdio/")
+}
+
+// botRoute returns the part of a /bots/{bot_id}/... path after the bot ID.
+func botRoute(path string) (string, bool) {
+	rest, ok := strings.CutPrefix(strings.TrimRight(path, "/"), "/bots/")
+	if !ok {
+		return "", false
+	}
+	botID, route, ok := strings.Cut(rest, "/")
+	if !ok || strings.TrimSpace(botID) == "" {
+		return "", false
+	}
+	return "/" + route, true
+}
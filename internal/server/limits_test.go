@@ -0,0 +1,110 @@
+package server
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestBodyLimitRejectsOversizedBodies(t *testing.T) {
+	t.Parallel()
+
+	e := echo.New()
+	e.Use(BodyLimitMiddleware(1))
+	readBody := func(c echo.Context) error {
+		if _, err := io.Copy(io.Discard, c.Request().Body); err != nil {
+			return err
+		}
+		return c.NoContent(http.StatusOK)
+	}
+	e.POST("/bots/:bot_id/settings", readBody)
+	e.POST("/bots/:bot_id/container/fs/upload", readBody)
+
+	oversized := strings.Repeat("x", 2<<20)
+	cases := []struct {
+		path string
+		body string
+		want int
+	}{
+		{path: "/bots/b1/settings", body: "{}", want: http.StatusOK},
+		{path: "/bots/b1/settings", body: oversized, want: http.StatusRequestEntityTooLarge},
+		{path: "/bots/b1/container/fs/upload", body: oversized, want: http.StatusOK},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest(http.MethodPost, tc.path, strings.NewReader(tc.body))
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		if rec.Code != tc.want {
+			t.Fatalf("path=%s size=%d status=%d want=%d", tc.path, len(tc.body), rec.Code, tc.want)
+		}
+	}
+}
+
+func TestRequestTimeout(t *testing.T) {
+	t.Parallel()
+
+	e := echo.New()
+	e.Use(requestTimeout(20 * time.Millisecond))
+	// waitForDeadline fails with the context error if the request context
+	// expires within 200ms.
+	waitForDeadline := func(c echo.Context) error {
+		select {
+		case <-c.Request().Context().Done():
+			return echo.NewHTTPError(http.StatusInternalServerError, c.Request().Context().Err().Error())
+		case <-time.After(200 * time.Millisecond):
+			return c.NoContent(http.StatusOK)
+		}
+	}
+	e.GET("/bots/:bot_id/messages", waitForDeadline)
+	e.GET("/bots/:bot_id/messages/events", waitForDeadline)
+
+	cases := []struct {
+		path   string
+		accept string
+		want   int
+	}{
+		{path: "/bots/b1/messages", want: http.StatusServiceUnavailable},
+		{path: "/bots/b1/messages/events", want: http.StatusOK},
+		{path: "/bots/b1/messages", accept: "text/event-stream", want: http.StatusOK},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest(http.MethodGet, tc.path, nil).WithContext(context.Background())
+		if tc.accept != "" {
+			req.Header.Set(echo.HeaderAccept, tc.accept)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		if rec.Code != tc.want {
+			t.Fatalf("path=%s accept=%q status=%d want=%d", tc.path, tc.accept, rec.Code, tc.want)
+		}
+	}
+}
+
+func TestIsStreamingRequest(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		method string
+		path   string
+		want   bool
+	}{
+		{method: http.MethodGet, path: "/bots/b1/web/stream", want: true},
+		{method: http.MethodPost, path: "/bots/b1/container", want: true},
+		{method: http.MethodGet, path: "/bots/b1/container", want: false},
+		{method: http.MethodPost, path: "/bots/b1/mcp-stdio/conn-1", want: true},
+		{method: http.MethodPost, path: "/bots/b1/web/messages", want: false},
+		{method: http.MethodGet, path: "/web/stream", want: false},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest(tc.method, tc.path, nil)
+		if got := isStreamingRequest(req); got != tc.want {
+			t.Fatalf("%s %s: got %v want %v", tc.method, tc.path, got, tc.want)
+		}
+	}
+}
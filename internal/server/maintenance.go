@@ -0,0 +1,94 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/memohai/memoh/internal/config"
+)
+
+// DefaultMaintenanceMessage is returned to blocked requests when no message
+// is set.
+const DefaultMaintenanceMessage = "server is in maintenance mode"
+
+// maintenanceExemptRoutes stay writable in maintenance mode so admins can
+// sign in and turn it off. memoh serve keeps the /api prefix on auth routes.
+var maintenanceExemptRoutes = []string{
+	"/auth/login",
+	"/auth/refresh",
+	"/api/auth/login",
+	"/api/auth/refresh",
+	"/maintenance",
+}
+
+// MaintenanceStatus is the maintenance mode state.
+type MaintenanceStatus struct {
+	Enabled bool   `json:"enabled"`
+	Message string `json:"message"`
+}
+
+// Maintenance holds the maintenance mode state shared by the middleware and
+// the admin endpoint. The state is not persisted; a restart returns to the
+// configured mode.
+type Maintenance struct {
+	mu     sync.RWMutex
+	status MaintenanceStatus
+}
+
+// NewMaintenance creates a Maintenance in the configured mode.
+func NewMaintenance(cfg config.MaintenanceConfig) *Maintenance {
+	m := &Maintenance{}
+	m.Set(cfg.Enabled, cfg.Message)
+	return m
+}
+
+// Status returns the current maintenance mode state.
+func (m *Maintenance) Status() MaintenanceStatus {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.status
+}
+
+// Set turns maintenance mode on or off. An empty message falls back to
+// DefaultMaintenanceMessage.
+func (m *Maintenance) Set(enabled bool, message string) MaintenanceStatus {
+	message = strings.TrimSpace(message)
+	if message == "" {
+		message = DefaultMaintenanceMessage
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.status = MaintenanceStatus{Enabled: enabled, Message: message}
+	return m.status
+}
+
+// Middleware answers mutating requests with 503 and the maintenance message
+// while maintenance mode is on. Reads, sign-in and the maintenance endpoint
+// itself pass through. WebSocket connections open with a GET upgrade, so
+// messages sent over an open socket are not blocked.
+func (m *Maintenance) Middleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		status := m.Status()
+		if !status.Enabled || !isMutatingRequest(c.Request()) {
+			return next(c)
+		}
+		path := strings.TrimRight(c.Request().URL.Path, "/")
+		for _, route := range maintenanceExemptRoutes {
+			if path == route {
+				return next(c)
+			}
+		}
+		return echo.NewHTTPError(http.StatusServiceUnavailable, status.Message)
+	}
+}
+
+func isMutatingRequest(r *http.Request) bool {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,88 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/memohai/memoh/internal/config"
+)
+
+func serveMaintenance(m *Maintenance, method, path string) *httptest.ResponseRecorder {
+	e := echo.New()
+	e.Use(m.Middleware)
+	ok := func(c echo.Context) error { return c.NoContent(http.StatusNoContent) }
+	e.Any("/*", ok)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(method, path, nil))
+	return rec
+}
+
+func TestMaintenanceBlocksWritesAndAllowsReads(t *testing.T) {
+	t.Parallel()
+
+	m := NewMaintenance(config.MaintenanceConfig{Enabled: true, Message: " Upgrading the database. "})
+
+	for _, tc := range []struct {
+		method string
+		path   string
+	}{
+		{http.MethodPost, "/bots"},
+		{http.MethodPut, "/bots/bot-1/settings"},
+		{http.MethodPatch, "/users/me"},
+		{http.MethodDelete, "/bots/bot-1"},
+		{http.MethodPost, "/channels/telegram/webhook/cfg-1"},
+	} {
+		rec := serveMaintenance(m, tc.method, tc.path)
+		if rec.Code != http.StatusServiceUnavailable {
+			t.Fatalf("%s %s status = %d, want %d", tc.method, tc.path, rec.Code, http.StatusServiceUnavailable)
+		}
+		if !strings.Contains(rec.Body.String(), "Upgrading the database.") {
+			t.Fatalf("%s %s body = %q, want maintenance message", tc.method, tc.path, rec.Body.String())
+		}
+	}
+
+	for _, tc := range []struct {
+		method string
+		path   string
+	}{
+		{http.MethodGet, "/bots"},
+		{http.MethodHead, "/health"},
+		{http.MethodOptions, "/bots"},
+		{http.MethodPost, "/auth/login"},
+		{http.MethodPost, "/auth/refresh"},
+		{http.MethodPut, "/maintenance/"},
+	} {
+		if rec := serveMaintenance(m, tc.method, tc.path); rec.Code != http.StatusNoContent {
+			t.Fatalf("%s %s status = %d, want %d", tc.method, tc.path, rec.Code, http.StatusNoContent)
+		}
+	}
+}
+
+func TestMaintenanceToggle(t *testing.T) {
+	t.Parallel()
+
+	m := NewMaintenance(config.MaintenanceConfig{})
+	if rec := serveMaintenance(m, http.MethodPost, "/bots"); rec.Code != http.StatusNoContent {
+		t.Fatalf("write outside maintenance status = %d, want %d", rec.Code, http.StatusNoContent)
+	}
+
+	status := m.Set(true, "")
+	if !status.Enabled || status.Message != DefaultMaintenanceMessage {
+		t.Fatalf("status = %+v, want enabled with default message", status)
+	}
+	if rec := serveMaintenance(m, http.MethodPost, "/bots"); rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("write in maintenance status = %d, want %d", rec.Code, http.StatusServiceUnavailable)
+	}
+
+	m.Set(false, "")
+	if m.Status().Enabled {
+		t.Fatal("expected maintenance mode off")
+	}
+	if rec := serveMaintenance(m, http.MethodPost, "/bots"); rec.Code != http.StatusNoContent {
+		t.Fatalf("write after maintenance status = %d, want %d", rec.Code, http.StatusNoContent)
+	}
+}
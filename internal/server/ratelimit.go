@@ -0,0 +1,192 @@
+package server
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"golang.org/x/time/rate"
+
+	"github.com/memohai/memoh/internal/auth"
+	"github.com/memohai/memoh/internal/config"
+)
+
+// limiterIdleTTL is how long an unused client limiter is kept. A limiter
+// idle this long has refilled completely, so dropping it changes nothing.
+const limiterIdleTTL = 10 * time.Minute
+
+// Rate limited endpoint classes.
+const (
+	rateClassLogin  = "login"
+	rateClassSend   = "send"
+	rateClassCreate = "create"
+)
+
+// sendRoutes are the bot routes that send messages.
+var sendRoutes = []string{
+	"/web/messages",
+}
+
+// sendActions are the channel actions under /bots/{id}/channel/{platform}
+// that send messages.
+var sendActions = []string{"send", "send_chat", "broadcast"}
+
+type clientLimiter struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// rateLimiter keeps a token bucket per endpoint class and client.
+type rateLimiter struct {
+	perMinute map[string]int
+	now       func() time.Time
+
+	mu        sync.Mutex
+	clients   map[string]*clientLimiter
+	lastSweep time.Time
+}
+
+// RateLimitMiddleware limits logins per client IP, and message sends and
+// bot or user creation per account, answering 429 with Retry-After once a
+// client exceeds its per-minute allowance. Other endpoints, health checks
+// included, are not limited. It must run after JWT authentication so
+// accounts can be told apart, and returns nil when no limit is set.
+func RateLimitMiddleware(cfg config.RateLimitConfig) echo.MiddlewareFunc {
+	l := newRateLimiter(cfg, time.Now)
+	if l == nil {
+		return nil
+	}
+	return l.middleware
+}
+
+// IPExtractor returns how the client IP is determined. Without trusted
+// proxies it is the connection address; otherwise X-Forwarded-For is read,
+// trusting only the listed proxies. Entries that are neither an IP nor a
+// CIDR are ignored.
+func IPExtractor(trustedProxies []string) echo.IPExtractor {
+	options := []echo.TrustOption{
+		echo.TrustLoopback(false),
+		echo.TrustLinkLocal(false),
+		echo.TrustPrivateNet(false),
+	}
+	for _, entry := range trustedProxies {
+		entry = strings.TrimSpace(entry)
+		if !strings.Contains(entry, "/") {
+			if ip := net.ParseIP(entry); ip != nil {
+				if ip.To4() != nil {
+					entry += "/32"
+				} else {
+					entry += "/128"
+				}
+			}
+		}
+		if _, ipNet, err := net.ParseCIDR(entry); err == nil {
+			options = append(options, echo.TrustIPRange(ipNet))
+		}
+	}
+	if len(options) == 3 {
+		return echo.ExtractIPDirect()
+	}
+	return echo.ExtractIPFromXFFHeader(options...)
+}
+
+func newRateLimiter(cfg config.RateLimitConfig, now func() time.Time) *rateLimiter {
+	perMinute := map[string]int{}
+	for class, limit := range map[string]int{
+		rateClassLogin:  cfg.LoginPerMinute,
+		rateClassSend:   cfg.SendPerMinute,
+		rateClassCreate: cfg.CreatePerMinute,
+	} {
+		if limit > 0 {
+			perMinute[class] = limit
+		}
+	}
+	if len(perMinute) == 0 {
+		return nil
+	}
+	return &rateLimiter{perMinute: perMinute, now: now, clients: map[string]*clientLimiter{}}
+}
+
+func (l *rateLimiter) middleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		class := rateClass(c.Request())
+		limit, ok := l.perMinute[class]
+		if !ok {
+			return next(c)
+		}
+		client := "ip:" + c.RealIP()
+		if class != rateClassLogin {
+			if userID, err := auth.UserIDFromContext(c); err == nil {
+				client = "user:" + userID
+			}
+		}
+		if wait := l.reserve(class+"|"+client, limit); wait > 0 {
+			seconds := int(math.Ceil(wait.Seconds()))
+			c.Response().Header().Set("Retry-After", strconv.Itoa(seconds))
+			return echo.NewHTTPError(http.StatusTooManyRequests, "rate limit exceeded")
+		}
+		return next(c)
+	}
+}
+
+// reserve takes a token for key and returns how long to wait when none is
+// available, or zero when the request may proceed.
+func (l *rateLimiter) reserve(key string, perMinute int) time.Duration {
+	now := l.now()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Sub(l.lastSweep) >= limiterIdleTTL {
+		for k, cl := range l.clients {
+			if now.Sub(cl.lastSeen) >= limiterIdleTTL {
+				delete(l.clients, k)
+			}
+		}
+		l.lastSweep = now
+	}
+	cl, ok := l.clients[key]
+	if !ok {
+		cl = &clientLimiter{limiter: rate.NewLimiter(rate.Limit(float64(perMinute)/60), perMinute)}
+		l.clients[key] = cl
+	}
+	cl.lastSeen = now
+
+	r := cl.limiter.ReserveN(now, 1)
+	if wait := r.DelayFrom(now); wait > 0 {
+		r.CancelAt(now)
+		return wait
+	}
+	return 0
+}
+
+// rateClass returns the rate limited endpoint class of r, or "".
+func rateClass(r *http.Request) string {
+	if r.Method != http.MethodPost {
+		return ""
+	}
+	path := strings.TrimRight(r.URL.Path, "/")
+	switch path {
+	case "/auth/login", "/api/auth/login":
+		return rateClassLogin
+	case "/bots", "/users":
+		return rateClassCreate
+	}
+	route, ok := botRoute(path)
+	if !ok {
+		return ""
+	}
+	if slices.Contains(sendRoutes, route) {
+		return rateClassSend
+	}
+	parts := strings.Split(strings.TrimPrefix(route, "/"), "/")
+	if len(parts) == 3 && parts[0] == "channel" && slices.Contains(sendActions, parts[2]) {
+		return rateClassSend
+	}
+	return ""
+}
@@ -0,0 +1,189 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+
+	"github.com/memohai/memoh/internal/config"
+)
+
+func newRateLimitedEcho(t *testing.T, cfg config.RateLimitConfig, now func() time.Time) *echo.Echo {
+	t.Helper()
+	l := newRateLimiter(cfg, now)
+	if l == nil {
+		t.Fatal("expected a rate limiter")
+	}
+	e := echo.New()
+	e.IPExtractor = IPExtractor(nil)
+	// Stand in for the JWT middleware: X-Test-User becomes the token user.
+	e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if userID := c.Request().Header.Get("X-Test-User"); userID != "" {
+				c.Set("user", &jwt.Token{Valid: true, Claims: jwt.MapClaims{"user_id": userID}})
+			}
+			return next(c)
+		}
+	})
+	e.Use(l.middleware)
+	ok := func(c echo.Context) error { return c.NoContent(http.StatusOK) }
+	e.POST("/auth/login", ok)
+	e.POST("/bots/:id/channel/:platform/send", ok)
+	e.GET("/health", ok)
+	return e
+}
+
+func doRequest(e *echo.Echo, method, path, ip, user string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	req.RemoteAddr = ip + ":1234"
+	if user != "" {
+		req.Header.Set("X-Test-User", user)
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestRateLimitLoginPerIP(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(1_700_000_000, 0)
+	e := newRateLimitedEcho(t, config.RateLimitConfig{LoginPerMinute: 3}, func() time.Time { return now })
+
+	for i := 0; i < 3; i++ {
+		if rec := doRequest(e, http.MethodPost, "/auth/login", "10.0.0.1", ""); rec.Code != http.StatusOK {
+			t.Fatalf("login %d status = %d", i, rec.Code)
+		}
+	}
+	rec := doRequest(e, http.MethodPost, "/auth/login", "10.0.0.1", "")
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429 past the limit, got %d", rec.Code)
+	}
+	if got := rec.Header().Get("Retry-After"); got != "20" {
+		t.Fatalf("Retry-After = %q, want 20", got)
+	}
+	if rec := doRequest(e, http.MethodPost, "/auth/login", "10.0.0.2", ""); rec.Code != http.StatusOK {
+		t.Fatalf("expected another IP to be allowed, got %d", rec.Code)
+	}
+
+	now = now.Add(20 * time.Second)
+	if rec := doRequest(e, http.MethodPost, "/auth/login", "10.0.0.1", ""); rec.Code != http.StatusOK {
+		t.Fatalf("expected login after Retry-After to be allowed, got %d", rec.Code)
+	}
+}
+
+func TestRateLimitSendPerAccount(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(1_700_000_000, 0)
+	e := newRateLimitedEcho(t, config.RateLimitConfig{SendPerMinute: 1}, func() time.Time { return now })
+	send := "/bots/b1/channel/telegram/send"
+
+	if rec := doRequest(e, http.MethodPost, send, "10.0.0.1", "u1"); rec.Code != http.StatusOK {
+		t.Fatalf("first send status = %d", rec.Code)
+	}
+	// The same account is limited from another IP.
+	if rec := doRequest(e, http.MethodPost, send, "10.0.0.2", "u1"); rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429 for the same account, got %d", rec.Code)
+	}
+	if rec := doRequest(e, http.MethodPost, send, "10.0.0.1", "u2"); rec.Code != http.StatusOK {
+		t.Fatalf("expected another account to be allowed, got %d", rec.Code)
+	}
+	// Logins are not limited when only sends are.
+	if rec := doRequest(e, http.MethodPost, "/auth/login", "10.0.0.1", ""); rec.Code != http.StatusOK {
+		t.Fatalf("expected unlimited login, got %d", rec.Code)
+	}
+}
+
+func TestRateLimitExemptsHealthChecks(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(1_700_000_000, 0)
+	e := newRateLimitedEcho(t, config.RateLimitConfig{LoginPerMinute: 1, SendPerMinute: 1, CreatePerMinute: 1}, func() time.Time { return now })
+	for i := 0; i < 5; i++ {
+		if rec := doRequest(e, http.MethodGet, "/health", "10.0.0.1", ""); rec.Code != http.StatusOK {
+			t.Fatalf("health %d status = %d", i, rec.Code)
+		}
+	}
+}
+
+func TestRateClass(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		method string
+		path   string
+		want   string
+	}{
+		{method: http.MethodPost, path: "/auth/login", want: rateClassLogin},
+		{method: http.MethodPost, path: "/api/auth/login", want: rateClassLogin},
+		{method: http.MethodPost, path: "/bots", want: rateClassCreate},
+		{method: http.MethodPost, path: "/users/", want: rateClassCreate},
+		{method: http.MethodGet, path: "/bots", want: ""},
+		{method: http.MethodPost, path: "/bots/b1/web/messages", want: rateClassSend},
+		{method: http.MethodPost, path: "/bots/b1/channel/telegram/send_chat", want: rateClassSend},
+		{method: http.MethodPost, path: "/bots/b1/channel/telegram/broadcast", want: rateClassSend},
+		{method: http.MethodPost, path: "/bots/b1/undelete", want: ""},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest(tc.method, tc.path, nil)
+		if got := rateClass(req); got != tc.want {
+			t.Fatalf("%s %s: got %q want %q", tc.method, tc.path, got, tc.want)
+		}
+	}
+	if RateLimitMiddleware(config.RateLimitConfig{}) != nil {
+		t.Fatal("expected no middleware without limits")
+	}
+}
+
+func doRequestFrom(e *echo.Echo, remoteIP, forwardedFor string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/auth/login", nil)
+	req.RemoteAddr = remoteIP + ":1234"
+	req.Header.Set(echo.HeaderXForwardedFor, forwardedFor)
+	req.Header.Set(echo.HeaderXRealIP, forwardedFor)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestRateLimitLoginIgnoresSpoofedForwardedFor(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(1_700_000_000, 0)
+	e := newRateLimitedEcho(t, config.RateLimitConfig{LoginPerMinute: 2}, func() time.Time { return now })
+
+	for i := 0; i < 2; i++ {
+		if rec := doRequestFrom(e, "10.0.0.1", "203.0.113."+strconv.Itoa(i+1)); rec.Code != http.StatusOK {
+			t.Fatalf("login %d status = %d", i, rec.Code)
+		}
+	}
+	if rec := doRequestFrom(e, "10.0.0.1", "203.0.113.99"); rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("spoofed forwarded-for status = %d, want 429", rec.Code)
+	}
+}
+
+func TestRateLimitLoginTrustedProxyForwardedFor(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(1_700_000_000, 0)
+	e := newRateLimitedEcho(t, config.RateLimitConfig{LoginPerMinute: 1}, func() time.Time { return now })
+	e.IPExtractor = IPExtractor([]string{"10.0.0.1"})
+
+	if rec := doRequestFrom(e, "10.0.0.1", "203.0.113.1"); rec.Code != http.StatusOK {
+		t.Fatalf("first client status = %d", rec.Code)
+	}
+	if rec := doRequestFrom(e, "10.0.0.1", "203.0.113.2"); rec.Code != http.StatusOK {
+		t.Fatalf("second client behind proxy status = %d", rec.Code)
+	}
+	if rec := doRequestFrom(e, "10.0.0.2", "203.0.113.3"); rec.Code != http.StatusOK {
+		t.Fatalf("untrusted sender status = %d", rec.Code)
+	}
+	if rec := doRequestFrom(e, "10.0.0.2", "203.0.113.4"); rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("untrusted sender forwarded-for status = %d, want 429", rec.Code)
+	}
+}
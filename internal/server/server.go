@@ -3,12 +3,14 @@ package server
 import (
 	"context"
 	"log/slog"
+	"net/url"
 	"strings"
 
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 
 	"github.com/memohai/memoh/internal/auth"
+	"github.com/memohai/memoh/internal/config"
 )
 
 type Server struct {
@@ -21,8 +23,8 @@ type Handler interface {
 	Register(e *echo.Echo)
 }
 
-func NewServer(log *slog.Logger, addr string, jwtSecret string,
-	handlers ...Handler,
+func NewServer(log *slog.Logger, addr string, jwtSecret string, serverCfg config.ServerConfig,
+	maintenance *Maintenance, handlers ...Handler,
 ) *Server {
 	if addr == "" {
 		addr = ":8080"
@@ -30,27 +32,30 @@ func NewServer(log *slog.Logger, addr string, jwtSecret string,
 
 	e := echo.New()
 	e.HideBanner = true
+	e.IPExtractor = IPExtractor(serverCfg.TrustedProxies)
 	e.Use(middleware.Recover())
-	e.Use(middleware.RequestLoggerWithConfig(middleware.RequestLoggerConfig{
-		LogStatus: true,
-		LogURI:    true,
-		LogMethod: true,
-		LogValuesFunc: func(c echo.Context, v middleware.RequestLoggerValues) error {
-			log.Info("request",
-				slog.String("method", v.Method),
-				slog.String("uri", v.URI),
-				slog.Int("status", v.Status),
-				slog.Duration("latency", v.Latency),
-				slog.String("remote_ip", c.RealIP()),
-			)
-			return nil
-		},
-	}))
+	e.Use(middleware.RequestID())
+	e.Use(AccessLogMiddleware(log, serverCfg.AccessLog))
+	for _, mw := range []echo.MiddlewareFunc{
+		CORSMiddleware(serverCfg.CORS),
+		BodyLimitMiddleware(serverCfg.MaxBodyMB),
+		RequestTimeoutMiddleware(serverCfg.RequestTimeoutSeconds),
+	} {
+		if mw != nil {
+			e.Use(mw)
+		}
+	}
 	e.Use(auth.JWTMiddleware(jwtSecret, func(c echo.Context) bool {
-		return shouldSkipJWT(c.Request().URL.Path)
+		return shouldSkipJWT(c.Request().URL.Path) || IsSignedMediaRequest(c.Request().URL)
 	}))
+	if mw := RateLimitMiddleware(serverCfg.RateLimit); mw != nil {
+		e.Use(mw)
+	}
+	if maintenance != nil {
+		e.Use(maintenance.Middleware)
+	}
 
-	for _, h := range handlers {
+	for _, h := range FilterHandlers(log, handlers, serverCfg.DisabledHandlers) {
 		if h != nil {
 			h.Register(e)
 		}
@@ -107,3 +112,13 @@ func isPublicChannelWebhookPath(path string) bool {
 	parts := strings.Split(trimmed, "/")
 	return len(parts) >= 3 && strings.TrimSpace(parts[0]) != "" && parts[1] == "webhook" && strings.TrimSpace(parts[2]) != ""
 }
+
+// IsSignedMediaRequest reports whether u fetches a media asset with a signed
+// URL. The media handler validates the signature instead of a JWT.
+func IsSignedMediaRequest(u *url.URL) bool {
+	if u.Query().Get("signature") == "" || !strings.HasPrefix(u.Path, "/bots/") {
+		return false
+	}
+	parts := strings.Split(strings.TrimPrefix(u.Path, "/bots/"), "/")
+	return len(parts) == 3 && strings.TrimSpace(parts[0]) != "" && parts[1] == "media" && strings.TrimSpace(parts[2]) != ""
+}
@@ -1,6 +1,16 @@
 package server
 
-import "testing"
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/memohai/memoh/internal/config"
+)
 
 func TestShouldSkipJWT_ChannelWebhookPaths(t *testing.T) {
 	t.Parallel()
@@ -22,3 +32,134 @@ func TestShouldSkipJWT_ChannelWebhookPaths(t *testing.T) {
 		}
 	}
 }
+
+func TestIsSignedMediaRequest(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		uri  string
+		want bool
+	}{
+		{uri: "/bots/bot-1/media/abc?expires=1&signature=ff", want: true},
+		{uri: "/bots/bot-1/media/abc", want: false},
+		{uri: "/bots/bot-1/media/abc/signed-url?signature=ff", want: false},
+		{uri: "/bots/bot-1/messages?signature=ff", want: false},
+		{uri: "/api/bots/bot-1/media/abc?signature=ff", want: false},
+	}
+
+	for _, tc := range cases {
+		u, err := url.Parse(tc.uri)
+		if err != nil {
+			t.Fatalf("parse %q: %v", tc.uri, err)
+		}
+		if got := IsSignedMediaRequest(u); got != tc.want {
+			t.Fatalf("uri=%q want=%v got=%v", tc.uri, tc.want, got)
+		}
+	}
+}
+
+func preflight(t *testing.T, srv *Server, origin string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodOptions, "/bots", nil)
+	req.Header.Set("Origin", origin)
+	req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	req.Header.Set("Access-Control-Request-Headers", "Authorization, Content-Type")
+	rec := httptest.NewRecorder()
+	srv.echo.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestCORSPreflight(t *testing.T) {
+	t.Parallel()
+
+	srv := NewServer(slog.New(slog.DiscardHandler), "", "secret", config.ServerConfig{
+		CORS: config.CORSConfig{
+			AllowOrigins:     []string{" https://webui.example.com/ "},
+			AllowHeaders:     []string{"Authorization", "Content-Type"},
+			AllowCredentials: true,
+		},
+	}, nil)
+
+	rec := preflight(t, srv, "https://webui.example.com")
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("allowed preflight status = %d, want %d", rec.Code, http.StatusNoContent)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "https://webui.example.com" {
+		t.Fatalf("allow origin = %q", got)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Credentials"); got != "true" {
+		t.Fatalf("allow credentials = %q", got)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Methods"); got == "" {
+		t.Fatal("expected allowed methods on preflight")
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Headers"); got != "Authorization,Content-Type" {
+		t.Fatalf("allow headers = %q", got)
+	}
+
+	rec = preflight(t, srv, "https://evil.example.com")
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Fatalf("expected disallowed origin to get no allow origin, got %q", got)
+	}
+}
+
+func TestCORSDisabledByDefault(t *testing.T) {
+	t.Parallel()
+
+	if CORSMiddleware(config.CORSConfig{AllowOrigins: []string{" "}}) != nil {
+		t.Fatal("expected no CORS middleware without origins")
+	}
+	srv := NewServer(slog.New(slog.DiscardHandler), "", "secret", config.ServerConfig{}, nil)
+	rec := preflight(t, srv, "https://webui.example.com")
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Fatalf("expected same-origin only, got allow origin %q", got)
+	}
+}
+
+type routeHandler struct {
+	name string
+	path string
+}
+
+func (h routeHandler) Register(e *echo.Echo) {
+	e.GET(h.path, func(c echo.Context) error { return c.NoContent(http.StatusNoContent) })
+}
+
+type namedRouteHandler struct{ routeHandler }
+
+func (h namedRouteHandler) HandlerName() string { return h.name }
+
+func TestDisabledHandlersAreNotRegistered(t *testing.T) {
+	t.Parallel()
+
+	srv := NewServer(slog.New(slog.DiscardHandler), "", "secret", config.ServerConfig{
+		DisabledHandlers: []string{" MCP ", "web", "unknown"},
+	}, nil,
+		namedRouteHandler{routeHandler{name: "mcp", path: "/ping"}},
+		namedRouteHandler{routeHandler{name: "web", path: "/health"}},
+		namedRouteHandler{routeHandler{name: "cli", path: "/auth/login"}},
+		routeHandler{name: "web", path: "/"},
+	)
+
+	for path, want := range map[string]int{
+		"/ping":       http.StatusNotFound,
+		"/health":     http.StatusNotFound,
+		"/auth/login": http.StatusNoContent,
+		"/":           http.StatusNoContent,
+	} {
+		rec := httptest.NewRecorder()
+		srv.echo.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != want {
+			t.Fatalf("GET %s status = %d, want %d", path, rec.Code, want)
+		}
+	}
+}
+
+func TestFilterHandlersKeepsAllWhenNoneDisabled(t *testing.T) {
+	t.Parallel()
+
+	handlers := []Handler{namedRouteHandler{routeHandler{name: "mcp"}}, routeHandler{}}
+	if got := FilterHandlers(slog.New(slog.DiscardHandler), handlers, []string{" "}); len(got) != len(handlers) {
+		t.Fatalf("kept %d handlers, want %d", len(got), len(handlers))
+	}
+}
@@ -102,6 +102,15 @@ func (s *Service) UpsertBot(ctx context.Context, botID string, req UpsertRequest
 	if req.PersistFullToolResults != nil {
 		current.PersistFullToolResults = *req.PersistFullToolResults
 	}
+	if req.PersistReasoning != nil {
+		current.PersistReasoning = *req.PersistReasoning
+	}
+	if req.MonthlyTokenCap != nil && *req.MonthlyTokenCap >= 0 {
+		current.MonthlyTokenCap = *req.MonthlyTokenCap
+	}
+	if req.MonthlyCostCapUSD != nil && *req.MonthlyCostCapUSD >= 0 {
+		current.MonthlyCostCapUSD = *req.MonthlyCostCapUSD
+	}
 	timezoneValue := pgtype.Text{}
 	if req.Timezone != nil {
 		normalized, err := normalizeOptionalTimezone(*req.Timezone)
@@ -160,6 +169,13 @@ func (s *Service) UpsertBot(ctx context.Context, botID string, req UpsertRequest
 		}
 		searchProviderUUID = providerID
 	}
+	var searchFallbackUUIDs []pgtype.UUID
+	if req.SearchFallbackProviderIDs != nil {
+		searchFallbackUUIDs, err = parseSearchFallbackProviderIDs(*req.SearchFallbackProviderIDs)
+		if err != nil {
+			return Settings{}, err
+		}
+	}
 	memoryProviderUUID := pgtype.UUID{}
 	if value := strings.TrimSpace(req.MemoryProviderID); value != "" {
 		providerID, err := db.ParseUUID(value)
@@ -192,30 +208,43 @@ func (s *Service) UpsertBot(ctx context.Context, botID string, req UpsertRequest
 		}
 		contextTokenBudgetValue = pgtype.Int4{Int32: int32(v), Valid: true} //nolint:gosec // G115: clamped above
 	}
+	systemPromptReserveValue := pgtype.Int4{}
+	if req.SystemPromptReserve != nil && *req.SystemPromptReserve >= 0 {
+		v := *req.SystemPromptReserve
+		if v > math.MaxInt32 {
+			v = math.MaxInt32
+		}
+		systemPromptReserveValue = pgtype.Int4{Int32: int32(v), Valid: true} //nolint:gosec // G115: clamped above
+	}
 
 	updated, err := s.queries.UpsertBotSettings(ctx, sqlc.UpsertBotSettingsParams{
-		ID:                     pgID,
-		Timezone:               timezoneValue,
-		Language:               current.Language,
-		ReasoningEnabled:       current.ReasoningEnabled,
-		ReasoningEffort:        current.ReasoningEffort,
-		HeartbeatEnabled:       current.HeartbeatEnabled,
-		HeartbeatInterval:      int32(current.HeartbeatInterval), //nolint:gosec // bounded by positive-only setter above
-		HeartbeatPrompt:        "",
-		CompactionEnabled:      current.CompactionEnabled,
-		CompactionThreshold:    int32(current.CompactionThreshold), //nolint:gosec // bounded by non-negative setter above
-		CompactionRatio:        int32(current.CompactionRatio),     //nolint:gosec // bounded 1-100 above
-		ChatModelID:            chatModelUUID,
-		HeartbeatModelID:       heartbeatModelUUID,
-		CompactionModelID:      compactionModelUUID,
-		TitleModelID:           titleModelUUID,
-		ImageModelID:           imageModelUUID,
-		SearchProviderID:       searchProviderUUID,
-		MemoryProviderID:       memoryProviderUUID,
-		TtsModelID:             ttsModelUUID,
-		BrowserContextID:       browserContextUUID,
-		ContextTokenBudget:     contextTokenBudgetValue,
-		PersistFullToolResults: current.PersistFullToolResults,
+		ID:                        pgID,
+		Timezone:                  timezoneValue,
+		Language:                  current.Language,
+		ReasoningEnabled:          current.ReasoningEnabled,
+		ReasoningEffort:           current.ReasoningEffort,
+		HeartbeatEnabled:          current.HeartbeatEnabled,
+		HeartbeatInterval:         int32(current.HeartbeatInterval), //nolint:gosec // bounded by positive-only setter above
+		HeartbeatPrompt:           "",
+		CompactionEnabled:         current.CompactionEnabled,
+		CompactionThreshold:       int32(current.CompactionThreshold), //nolint:gosec // bounded by non-negative setter above
+		CompactionRatio:           int32(current.CompactionRatio),     //nolint:gosec // bounded 1-100 above
+		ChatModelID:               chatModelUUID,
+		HeartbeatModelID:          heartbeatModelUUID,
+		CompactionModelID:         compactionModelUUID,
+		TitleModelID:              titleModelUUID,
+		ImageModelID:              imageModelUUID,
+		SearchProviderID:          searchProviderUUID,
+		SearchFallbackProviderIds: searchFallbackUUIDs,
+		MemoryProviderID:          memoryProviderUUID,
+		TtsModelID:                ttsModelUUID,
+		BrowserContextID:          browserContextUUID,
+		ContextTokenBudget:        contextTokenBudgetValue,
+		SystemPromptReserve:       systemPromptReserveValue,
+		PersistFullToolResults:    current.PersistFullToolResults,
+		PersistReasoning:          current.PersistReasoning,
+		MonthlyTokenCap:           current.MonthlyTokenCap,
+		MonthlyCostCapUsd:         current.MonthlyCostCapUSD,
 	})
 	if err != nil {
 		return Settings{}, err
@@ -310,7 +339,12 @@ func normalizeBotSettingsReadRow(row sqlc.GetSettingsByBotIDRow) Settings {
 		row.TtsModelID,
 		row.BrowserContextID,
 		row.ContextTokenBudget,
+		row.SystemPromptReserve,
 		row.PersistFullToolResults,
+		row.PersistReasoning,
+		row.MonthlyTokenCap,
+		row.MonthlyCostCapUsd,
+		row.SearchFallbackProviderIds,
 	)
 }
 
@@ -335,7 +369,12 @@ func normalizeBotSettingsWriteRow(row sqlc.UpsertBotSettingsRow) Settings {
 		row.TtsModelID,
 		row.BrowserContextID,
 		row.ContextTokenBudget,
+		row.SystemPromptReserve,
 		row.PersistFullToolResults,
+		row.PersistReasoning,
+		row.MonthlyTokenCap,
+		row.MonthlyCostCapUsd,
+		row.SearchFallbackProviderIds,
 	)
 }
 
@@ -359,7 +398,12 @@ func normalizeBotSettingsFields(
 	ttsModelID pgtype.UUID,
 	browserContextID pgtype.UUID,
 	contextTokenBudget pgtype.Int4,
+	systemPromptReserve pgtype.Int4,
 	persistFullToolResults bool,
+	persistReasoning bool,
+	monthlyTokenCap int64,
+	monthlyCostCapUSD float64,
+	searchFallbackProviderIDs []pgtype.UUID,
 ) Settings {
 	settings := normalizeBotSetting(language, "", reasoningEnabled, reasoningEffort, heartbeatEnabled, heartbeatInterval, compactionEnabled, compactionThreshold, compactionRatio)
 	if timezone.Valid {
@@ -395,10 +439,46 @@ func normalizeBotSettingsFields(
 	if contextTokenBudget.Valid {
 		settings.ContextTokenBudget = int(contextTokenBudget.Int32)
 	}
+	if systemPromptReserve.Valid {
+		settings.SystemPromptReserve = int(systemPromptReserve.Int32)
+	}
 	settings.PersistFullToolResults = persistFullToolResults
+	settings.PersistReasoning = persistReasoning
+	settings.MonthlyTokenCap = monthlyTokenCap
+	settings.MonthlyCostCapUSD = monthlyCostCapUSD
+	settings.SearchFallbackProviderIDs = make([]string, 0, len(searchFallbackProviderIDs))
+	for _, id := range searchFallbackProviderIDs {
+		if id.Valid {
+			settings.SearchFallbackProviderIDs = append(settings.SearchFallbackProviderIDs, uuid.UUID(id.Bytes).String())
+		}
+	}
 	return settings
 }
 
+// parseSearchFallbackProviderIDs parses an ordered fallback list, dropping
+// blanks and repeats. The result is never nil so an empty list clears the
+// stored one.
+func parseSearchFallbackProviderIDs(ids []string) ([]pgtype.UUID, error) {
+	parsed := make([]pgtype.UUID, 0, len(ids))
+	seen := make(map[[16]byte]struct{}, len(ids))
+	for _, value := range ids {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		id, err := db.ParseUUID(value)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := seen[id.Bytes]; ok {
+			continue
+		}
+		seen[id.Bytes] = struct{}{}
+		parsed = append(parsed, id)
+	}
+	return parsed, nil
+}
+
 func (s *Service) getDefaultEffect(ctx context.Context, botID string) (string, error) {
 	if s.acl == nil {
 		return "deny", nil
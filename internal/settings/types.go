@@ -7,51 +7,112 @@ const (
 )
 
 type Settings struct {
-	ChatModelID            string `json:"chat_model_id"`
-	ImageModelID           string `json:"image_model_id"`
-	SearchProviderID       string `json:"search_provider_id"`
-	MemoryProviderID       string `json:"memory_provider_id"`
-	TtsModelID             string `json:"tts_model_id"`
-	BrowserContextID       string `json:"browser_context_id"`
-	Language               string `json:"language"`
-	AclDefaultEffect       string `json:"acl_default_effect"`
-	Timezone               string `json:"timezone"`
-	ReasoningEnabled       bool   `json:"reasoning_enabled"`
-	ReasoningEffort        string `json:"reasoning_effort"`
-	HeartbeatEnabled       bool   `json:"heartbeat_enabled"`
-	HeartbeatInterval      int    `json:"heartbeat_interval"`
-	HeartbeatModelID       string `json:"heartbeat_model_id"`
-	TitleModelID           string `json:"title_model_id"`
-	CompactionEnabled      bool   `json:"compaction_enabled"`
-	CompactionThreshold    int    `json:"compaction_threshold"`
-	CompactionRatio        int    `json:"compaction_ratio"`
-	CompactionModelID      string `json:"compaction_model_id,omitempty"`
-	DiscussProbeModelID    string `json:"discuss_probe_model_id,omitempty"`
-	ContextTokenBudget     int    `json:"context_token_budget"`
-	PersistFullToolResults bool   `json:"persist_full_tool_results"`
+	ChatModelID      string `json:"chat_model_id"`
+	ImageModelID     string `json:"image_model_id"`
+	SearchProviderID string `json:"search_provider_id"`
+	// SearchFallbackProviderIDs are tried in order when the search provider
+	// fails.
+	SearchFallbackProviderIDs []string `json:"search_fallback_provider_ids"`
+	MemoryProviderID          string   `json:"memory_provider_id"`
+	TtsModelID                string   `json:"tts_model_id"`
+	BrowserContextID          string   `json:"browser_context_id"`
+	Language                  string   `json:"language"`
+	AclDefaultEffect          string   `json:"acl_default_effect"`
+	Timezone                  string   `json:"timezone"`
+	ReasoningEnabled          bool     `json:"reasoning_enabled"`
+	ReasoningEffort           string   `json:"reasoning_effort"`
+	HeartbeatEnabled          bool     `json:"heartbeat_enabled"`
+	HeartbeatInterval         int      `json:"heartbeat_interval"`
+	HeartbeatModelID          string   `json:"heartbeat_model_id"`
+	TitleModelID              string   `json:"title_model_id"`
+	CompactionEnabled         bool     `json:"compaction_enabled"`
+	CompactionThreshold       int      `json:"compaction_threshold"`
+	CompactionRatio           int      `json:"compaction_ratio"`
+	CompactionModelID         string   `json:"compaction_model_id,omitempty"`
+	DiscussProbeModelID       string   `json:"discuss_probe_model_id,omitempty"`
+	ContextTokenBudget        int      `json:"context_token_budget"`
+	SystemPromptReserve       int      `json:"system_prompt_reserve"`
+	PersistFullToolResults    bool     `json:"persist_full_tool_results"`
+	PersistReasoning          bool     `json:"persist_reasoning"`
+	// MonthlyTokenCap and MonthlyCostCapUSD stop the bot from chatting once
+	// its usage this calendar month (UTC) reaches them. Zero means no cap.
+	MonthlyTokenCap   int64   `json:"monthly_token_cap"`
+	MonthlyCostCapUSD float64 `json:"monthly_cost_cap_usd"`
 }
 
 type UpsertRequest struct {
-	ChatModelID            string  `json:"chat_model_id,omitempty"`
-	ImageModelID           string  `json:"image_model_id,omitempty"`
-	SearchProviderID       string  `json:"search_provider_id,omitempty"`
-	MemoryProviderID       string  `json:"memory_provider_id,omitempty"`
-	TtsModelID             string  `json:"tts_model_id,omitempty"`
-	BrowserContextID       string  `json:"browser_context_id,omitempty"`
-	Language               string  `json:"language,omitempty"`
-	AclDefaultEffect       string  `json:"acl_default_effect,omitempty"`
-	Timezone               *string `json:"timezone,omitempty"`
-	ReasoningEnabled       *bool   `json:"reasoning_enabled,omitempty"`
-	ReasoningEffort        *string `json:"reasoning_effort,omitempty"`
-	HeartbeatEnabled       *bool   `json:"heartbeat_enabled,omitempty"`
-	HeartbeatInterval      *int    `json:"heartbeat_interval,omitempty"`
-	HeartbeatModelID       string  `json:"heartbeat_model_id,omitempty"`
-	TitleModelID           string  `json:"title_model_id,omitempty"`
-	CompactionEnabled      *bool   `json:"compaction_enabled,omitempty"`
-	CompactionThreshold    *int    `json:"compaction_threshold,omitempty"`
-	CompactionRatio        *int    `json:"compaction_ratio,omitempty"`
-	CompactionModelID      *string `json:"compaction_model_id,omitempty"`
-	DiscussProbeModelID    string  `json:"discuss_probe_model_id,omitempty"`
-	ContextTokenBudget     *int    `json:"context_token_budget,omitempty"`
-	PersistFullToolResults *bool   `json:"persist_full_tool_results,omitempty"`
+	ChatModelID      string `json:"chat_model_id,omitempty"`
+	ImageModelID     string `json:"image_model_id,omitempty"`
+	SearchProviderID string `json:"search_provider_id,omitempty"`
+	// SearchFallbackProviderIDs replaces the fallback list when set; an
+	// empty list clears it.
+	SearchFallbackProviderIDs *[]string `json:"search_fallback_provider_ids,omitempty"`
+	MemoryProviderID          string    `json:"memory_provider_id,omitempty"`
+	TtsModelID                string    `json:"tts_model_id,omitempty"`
+	BrowserContextID          string    `json:"browser_context_id,omitempty"`
+	Language                  string    `json:"language,omitempty"`
+	AclDefaultEffect          string    `json:"acl_default_effect,omitempty"`
+	Timezone                  *string   `json:"timezone,omitempty"`
+	ReasoningEnabled          *bool     `json:"reasoning_enabled,omitempty"`
+	ReasoningEffort           *string   `json:"reasoning_effort,omitempty"`
+	HeartbeatEnabled          *bool     `json:"heartbeat_enabled,omitempty"`
+	HeartbeatInterval         *int      `json:"heartbeat_interval,omitempty"`
+	HeartbeatModelID          string    `json:"heartbeat_model_id,omitempty"`
+	TitleModelID              string    `json:"title_model_id,omitempty"`
+	CompactionEnabled         *bool     `json:"compaction_enabled,omitempty"`
+	CompactionThreshold       *int      `json:"compaction_threshold,omitempty"`
+	CompactionRatio           *int      `json:"compaction_ratio,omitempty"`
+	CompactionModelID         *string   `json:"compaction_model_id,omitempty"`
+	DiscussProbeModelID       string    `json:"discuss_probe_model_id,omitempty"`
+	ContextTokenBudget        *int      `json:"context_token_budget,omitempty"`
+	SystemPromptReserve       *int      `json:"system_prompt_reserve,omitempty"`
+	PersistFullToolResults    *bool     `json:"persist_full_tool_results,omitempty"`
+	PersistReasoning          *bool     `json:"persist_reasoning,omitempty"`
+	MonthlyTokenCap           *int64    `json:"monthly_token_cap,omitempty"`
+	MonthlyCostCapUSD         *float64  `json:"monthly_cost_cap_usd,omitempty"`
+}
+
+// CloneRequest returns an upsert request that gives another bot these
+// settings.
+func (s Settings) CloneRequest() UpsertRequest {
+	req := UpsertRequest{
+		ChatModelID:            s.ChatModelID,
+		ImageModelID:           s.ImageModelID,
+		SearchProviderID:       s.SearchProviderID,
+		MemoryProviderID:       s.MemoryProviderID,
+		TtsModelID:             s.TtsModelID,
+		BrowserContextID:       s.BrowserContextID,
+		Language:               s.Language,
+		AclDefaultEffect:       s.AclDefaultEffect,
+		ReasoningEnabled:       &s.ReasoningEnabled,
+		ReasoningEffort:        &s.ReasoningEffort,
+		HeartbeatEnabled:       &s.HeartbeatEnabled,
+		HeartbeatInterval:      &s.HeartbeatInterval,
+		HeartbeatModelID:       s.HeartbeatModelID,
+		TitleModelID:           s.TitleModelID,
+		CompactionEnabled:      &s.CompactionEnabled,
+		CompactionThreshold:    &s.CompactionThreshold,
+		CompactionRatio:        &s.CompactionRatio,
+		CompactionModelID:      &s.CompactionModelID,
+		DiscussProbeModelID:    s.DiscussProbeModelID,
+		PersistFullToolResults: &s.PersistFullToolResults,
+		PersistReasoning:       &s.PersistReasoning,
+		MonthlyTokenCap:        &s.MonthlyTokenCap,
+		MonthlyCostCapUSD:      &s.MonthlyCostCapUSD,
+	}
+	if s.Timezone != "" {
+		req.Timezone = &s.Timezone
+	}
+	if len(s.SearchFallbackProviderIDs) > 0 {
+		fallbacks := append([]string(nil), s.SearchFallbackProviderIDs...)
+		req.SearchFallbackProviderIDs = &fallbacks
+	}
+	// Zero budgets are unset and stay unset on the clone.
+	if s.ContextTokenBudget > 0 {
+		req.ContextTokenBudget = &s.ContextTokenBudget
+	}
+	if s.SystemPromptReserve > 0 {
+		req.SystemPromptReserve = &s.SystemPromptReserve
+	}
+	return req
 }
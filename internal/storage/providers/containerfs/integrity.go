@@ -0,0 +1,133 @@
+package containerfs
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/memohai/memoh/internal/storage"
+)
+
+// SetVerifyOnRead makes Open re-hash content-addressed media while it is
+// read. A reader whose bytes do not match the key's hash returns a
+// *storage.IntegrityError instead of io.EOF.
+func (p *Provider) SetVerifyOnRead(enabled bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.verifyOnRead = enabled
+}
+
+func (p *Provider) verifyReads() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.verifyOnRead
+}
+
+// Verify re-hashes the media object at key and returns a
+// *storage.IntegrityError when its content does not match the hash in the
+// key. Keys that are not content-addressed are not checked.
+func (p *Provider) Verify(ctx context.Context, key string) error {
+	expected, ok := contentHashFromKey(key)
+	if !ok {
+		return nil
+	}
+	rc, err := p.open(ctx, key)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = rc.Close() }()
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, rc); err != nil {
+		return fmt.Errorf("read %s: %w", key, err)
+	}
+	if actual := hex.EncodeToString(hasher.Sum(nil)); actual != expected {
+		return &storage.IntegrityError{Key: key, Expected: expected, Actual: actual}
+	}
+	return nil
+}
+
+// ScanIntegrity verifies every content-addressed media object of a bot and
+// returns the corrupted ones. Read failures on single objects are skipped so
+// one unreadable file does not abort the scan.
+func (p *Provider) ScanIntegrity(ctx context.Context, botID string) ([]storage.IntegrityError, error) {
+	botID = strings.TrimSpace(botID)
+	if botID == "" {
+		return nil, errors.New("bot id is required")
+	}
+	client, err := p.clients.MCPClient(ctx, botID)
+	if err != nil {
+		return nil, fmt.Errorf("get client: %w", err)
+	}
+	entries, err := client.ListDirAll(ctx, containerMediaRoot, true)
+	if err != nil {
+		return nil, fmt.Errorf("list media: %w", err)
+	}
+	corrupted := make([]storage.IntegrityError, 0)
+	for _, entry := range entries {
+		if entry.GetIsDir() {
+			continue
+		}
+		key := filepath.Join(botID, entry.GetPath())
+		if _, ok := contentHashFromKey(key); !ok {
+			continue
+		}
+		err := p.Verify(ctx, key)
+		var integrityErr *storage.IntegrityError
+		if errors.As(err, &integrityErr) {
+			corrupted = append(corrupted, *integrityErr)
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+	}
+	return corrupted, nil
+}
+
+// contentHashFromKey returns the SHA-256 hex digest a media key is named
+// after (<prefix>/<hash><ext>), if it has one.
+func contentHashFromKey(key string) (string, bool) {
+	base := path.Base(filepath.ToSlash(key))
+	hash := strings.ToLower(strings.TrimSuffix(base, path.Ext(base)))
+	if len(hash) != sha256.Size*2 {
+		return "", false
+	}
+	if _, err := hex.DecodeString(hash); err != nil {
+		return "", false
+	}
+	return hash, true
+}
+
+// verifyingReader hashes content as it is read and checks it against the
+// expected digest at EOF.
+type verifyingReader struct {
+	rc       io.ReadCloser
+	hasher   hash.Hash
+	key      string
+	expected string
+}
+
+func newVerifyingReader(rc io.ReadCloser, key, expected string) *verifyingReader {
+	return &verifyingReader{rc: rc, hasher: sha256.New(), key: key, expected: expected}
+}
+
+func (v *verifyingReader) Read(b []byte) (int, error) {
+	n, err := v.rc.Read(b)
+	v.hasher.Write(b[:n])
+	if errors.Is(err, io.EOF) {
+		if actual := hex.EncodeToString(v.hasher.Sum(nil)); actual != v.expected {
+			return n, &storage.IntegrityError{Key: v.key, Expected: v.expected, Actual: actual}
+		}
+	}
+	return n, err
+}
+
+func (v *verifyingReader) Close() error {
+	return v.rc.Close()
+}
@@ -0,0 +1,108 @@
+package containerfs
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/memohai/memoh/internal/storage"
+)
+
+// storeAsset writes data under its content-addressed key and returns the key.
+func storeAsset(t *testing.T, p *Provider, data []byte) string {
+	t.Helper()
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+	key := "bot-1/" + hash[:2] + "/" + hash + ".txt"
+	if err := p.Put(context.Background(), key, bytes.NewReader(data)); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	return key
+}
+
+func TestProviderVerifyDetectsCorruption(t *testing.T) {
+	t.Parallel()
+	p, container := newTestProvider(t, 0)
+	ctx := context.Background()
+	key := storeAsset(t, p, []byte("hello world"))
+
+	if err := p.Verify(ctx, key); err != nil {
+		t.Fatalf("expected intact asset to verify, got %v", err)
+	}
+
+	_, sub := splitRoutingKey(key)
+	container.put("media/"+sub, []byte("hello w0rld"))
+
+	err := p.Verify(ctx, key)
+	if !errors.Is(err, storage.ErrIntegrity) {
+		t.Fatalf("expected integrity error, got %v", err)
+	}
+	var integrityErr *storage.IntegrityError
+	if !errors.As(err, &integrityErr) || integrityErr.Key != key || integrityErr.Expected == integrityErr.Actual {
+		t.Fatalf("unexpected integrity error: %+v", err)
+	}
+}
+
+func TestProviderOpenVerifiesOnReadWhenEnabled(t *testing.T) {
+	t.Parallel()
+	p, container := newTestProvider(t, 0)
+	ctx := context.Background()
+	key := storeAsset(t, p, []byte("original bytes"))
+	_, sub := splitRoutingKey(key)
+	container.put("media/"+sub, []byte("tampered bytes"))
+
+	rc, err := p.Open(ctx, key)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	if _, err := io.ReadAll(rc); err != nil {
+		t.Fatalf("expected unverified read to succeed, got %v", err)
+	}
+	_ = rc.Close()
+
+	p.SetVerifyOnRead(true)
+	rc, err = p.Open(ctx, key)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer func() { _ = rc.Close() }()
+	if _, err := io.ReadAll(rc); !errors.Is(err, storage.ErrIntegrity) {
+		t.Fatalf("expected integrity error on verified read, got %v", err)
+	}
+}
+
+func TestProviderScanIntegrityReportsCorruptedAssets(t *testing.T) {
+	t.Parallel()
+	p, container := newTestProvider(t, 0)
+	ctx := context.Background()
+	intact := storeAsset(t, p, []byte("intact"))
+	corrupt := storeAsset(t, p, []byte("will be corrupted"))
+	_, sub := splitRoutingKey(corrupt)
+	container.put("media/"+sub, []byte("corrupted"))
+	container.put("media/notes/readme.txt", []byte("not content addressed"))
+
+	items, err := p.ScanIntegrity(ctx, "bot-1")
+	if err != nil {
+		t.Fatalf("ScanIntegrity failed: %v", err)
+	}
+	if len(items) != 1 || items[0].Key != corrupt {
+		t.Fatalf("expected only %s to be reported, got %+v (intact: %s)", corrupt, items, intact)
+	}
+}
+
+func TestContentHashFromKey(t *testing.T) {
+	t.Parallel()
+	hash := "b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9"
+	if got, ok := contentHashFromKey("bot-1/b9/" + hash + ".png"); !ok || got != hash {
+		t.Fatalf("expected hash %s, got %q (ok=%v)", hash, got, ok)
+	}
+	for _, key := range []string{"bot-1/image/photo.png", "bot-1/b9/" + hash[:63] + "z.png"} {
+		if _, ok := contentHashFromKey(key); ok {
+			t.Errorf("expected %q not to be content-addressed", key)
+		}
+	}
+}
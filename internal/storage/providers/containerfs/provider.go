@@ -10,6 +10,7 @@ import (
 	"io"
 	"path/filepath"
 	"strings"
+	"sync"
 
 	"github.com/memohai/memoh/internal/workspace/bridge"
 )
@@ -19,6 +20,11 @@ const containerMediaRoot = "media"
 // Provider stores media assets inside bot containers via gRPC.
 type Provider struct {
 	clients bridge.Provider
+
+	mu           sync.Mutex
+	quota        int64
+	usageByBot   map[string]botUsage
+	verifyOnRead bool
 }
 
 // New creates a container-based storage provider.
@@ -36,15 +42,23 @@ func (p *Provider) Put(ctx context.Context, key string, reader io.Reader) error
 	if err != nil {
 		return fmt.Errorf("get client: %w", err)
 	}
-	containerPath := filepath.Join(containerMediaRoot, sub)
-	if _, err := client.WriteRaw(ctx, containerPath, reader); err != nil {
-		return fmt.Errorf("write file: %w", err)
-	}
-	return nil
+	return p.write(ctx, client, botID, filepath.Join(containerMediaRoot, sub), reader)
 }
 
-// Open reads a file from the bot container via gRPC streaming.
+// Open reads a file from the bot container via gRPC streaming. With
+// SetVerifyOnRead enabled, content-addressed media is re-hashed as it is read.
 func (p *Provider) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	rc, err := p.open(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if expected, ok := contentHashFromKey(key); ok && p.verifyReads() {
+		return newVerifyingReader(rc, key, expected), nil
+	}
+	return rc, nil
+}
+
+func (p *Provider) open(ctx context.Context, key string) (io.ReadCloser, error) {
 	botID, sub, err := parseRoutingKey(key)
 	if err != nil {
 		return nil, err
@@ -67,8 +81,7 @@ func (p *Provider) Delete(ctx context.Context, key string) error {
 	if err != nil {
 		return fmt.Errorf("get client: %w", err)
 	}
-	containerPath := filepath.Join(containerMediaRoot, sub)
-	return client.DeleteFile(ctx, containerPath, false)
+	return p.remove(ctx, client, botID, filepath.Join(containerMediaRoot, sub))
 }
 
 // AccessPath returns the container-internal path for a storage key.
@@ -79,13 +92,9 @@ func (*Provider) AccessPath(key string) string {
 
 // OpenContainerFile opens a file from a bot's /data/ directory.
 func (p *Provider) OpenContainerFile(ctx context.Context, botID, containerPath string) (io.ReadCloser, error) {
-	dataPrefix := "/data/"
-	if !strings.HasPrefix(containerPath, dataPrefix) {
-		return nil, fmt.Errorf("path must start with %s", dataPrefix)
-	}
-	subPath := containerPath[len(dataPrefix):]
-	if subPath == "" || strings.Contains(subPath, "..") {
-		return nil, errors.New("invalid container path")
+	subPath, err := containerDataSubpath(containerPath)
+	if err != nil {
+		return nil, err
 	}
 	client, err := p.clients.MCPClient(ctx, botID)
 	if err != nil {
@@ -94,6 +103,33 @@ func (p *Provider) OpenContainerFile(ctx context.Context, botID, containerPath s
 	return client.ReadRaw(ctx, subPath)
 }
 
+// WriteContainerFile writes a file into a bot's /data/ directory.
+func (p *Provider) WriteContainerFile(ctx context.Context, botID, containerPath string, reader io.Reader) error {
+	subPath, err := containerDataSubpath(containerPath)
+	if err != nil {
+		return err
+	}
+	client, err := p.clients.MCPClient(ctx, botID)
+	if err != nil {
+		return fmt.Errorf("get client: %w", err)
+	}
+	return p.write(ctx, client, botID, subPath, reader)
+}
+
+// containerDataSubpath returns containerPath relative to /data/, rejecting
+// paths outside of it.
+func containerDataSubpath(containerPath string) (string, error) {
+	dataPrefix := "/data/"
+	if !strings.HasPrefix(containerPath, dataPrefix) {
+		return "", fmt.Errorf("path must start with %s", dataPrefix)
+	}
+	subPath := containerPath[len(dataPrefix):]
+	if subPath == "" || strings.Contains(subPath, "..") {
+		return "", errors.New("invalid container path")
+	}
+	return subPath, nil
+}
+
 // ListPrefix returns all keys under the given routing prefix.
 func (p *Provider) ListPrefix(ctx context.Context, prefix string) ([]string, error) {
 	botID, sub := splitRoutingKey(prefix)
@@ -0,0 +1,167 @@
+package containerfs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"time"
+
+	"github.com/memohai/memoh/internal/storage"
+	"github.com/memohai/memoh/internal/workspace/bridge"
+)
+
+const (
+	containerDataDir = "/data"
+
+	// usageTTL bounds how long a cached usage figure is trusted. Bots can
+	// also write to /data from their own shell, so usage is re-read
+	// periodically instead of only being tracked from our writes.
+	usageTTL = time.Minute
+)
+
+type botUsage struct {
+	bytes    int64
+	loadedAt time.Time
+}
+
+// SetQuota limits how many bytes each bot may store under /data. Writes that
+// would exceed it fail with storage.ErrQuotaExceeded. Zero or less disables
+// the quota.
+func (p *Provider) SetQuota(bytes int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.quota = bytes
+}
+
+// write stores reader at path inside the bot container, enforcing the quota
+// when one is set. With a quota, data is streamed to a temporary file and
+// renamed into place, so a rejected write leaves any existing file intact.
+func (p *Provider) write(ctx context.Context, client *bridge.Client, botID, path string, reader io.Reader) error {
+	quota := p.quotaBytes()
+	if quota <= 0 {
+		if _, err := client.WriteRaw(ctx, path, reader); err != nil {
+			return fmt.Errorf("write file: %w", err)
+		}
+		return nil
+	}
+
+	used, err := p.usage(ctx, client, botID)
+	if err != nil {
+		return fmt.Errorf("read storage usage: %w", err)
+	}
+	var existing int64
+	if entry, err := client.Stat(ctx, path); err == nil && !entry.GetIsDir() {
+		existing = entry.GetSize()
+	}
+	remaining := quota - (used - existing)
+	if remaining <= 0 {
+		return fmt.Errorf("bot %s uses %d of %d bytes: %w", botID, used, quota, storage.ErrQuotaExceeded)
+	}
+
+	// Canceling the write context tears down the upload stream when the
+	// quota reader aborts it half way.
+	writeCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	tmpPath := filepath.Join(filepath.Dir(path), "."+filepath.Base(path)+".upload")
+	written, err := client.WriteRaw(writeCtx, tmpPath, &quotaReader{r: reader, remaining: remaining})
+	if err != nil {
+		cancel()
+		_ = client.DeleteFile(context.WithoutCancel(ctx), tmpPath, false)
+		if errors.Is(err, storage.ErrQuotaExceeded) {
+			return fmt.Errorf("bot %s write to %s exceeds %d byte quota: %w", botID, path, quota, err)
+		}
+		return fmt.Errorf("write file: %w", err)
+	}
+	if err := client.Rename(ctx, tmpPath, path); err != nil {
+		_ = client.DeleteFile(ctx, tmpPath, false)
+		return fmt.Errorf("rename file: %w", err)
+	}
+	p.addUsage(botID, written-existing)
+	return nil
+}
+
+// remove deletes path inside the bot container and credits its size back to
+// the bot's cached usage.
+func (p *Provider) remove(ctx context.Context, client *bridge.Client, botID, path string) error {
+	var size int64
+	if p.quotaBytes() > 0 {
+		if entry, err := client.Stat(ctx, path); err == nil && !entry.GetIsDir() {
+			size = entry.GetSize()
+		}
+	}
+	if err := client.DeleteFile(ctx, path, false); err != nil {
+		return err
+	}
+	p.addUsage(botID, -size)
+	return nil
+}
+
+func (p *Provider) quotaBytes() int64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.quota
+}
+
+// usage returns the bytes stored under the bot's /data directory, reading it
+// from the container when the cached figure is missing or stale.
+func (p *Provider) usage(ctx context.Context, client *bridge.Client, botID string) (int64, error) {
+	p.mu.Lock()
+	cached, ok := p.usageByBot[botID]
+	p.mu.Unlock()
+	if ok && time.Since(cached.loadedAt) < usageTTL {
+		return cached.bytes, nil
+	}
+
+	entries, err := client.ListDirAll(ctx, containerDataDir, true)
+	if err != nil {
+		return 0, err
+	}
+	var total int64
+	for _, entry := range entries {
+		if !entry.GetIsDir() {
+			total += entry.GetSize()
+		}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.usageByBot == nil {
+		p.usageByBot = map[string]botUsage{}
+	}
+	p.usageByBot[botID] = botUsage{bytes: total, loadedAt: time.Now()}
+	return total, nil
+}
+
+func (p *Provider) addUsage(botID string, delta int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	cached, ok := p.usageByBot[botID]
+	if !ok {
+		return
+	}
+	cached.bytes = max(cached.bytes+delta, 0)
+	p.usageByBot[botID] = cached
+}
+
+// quotaReader passes through at most remaining bytes and fails with
+// storage.ErrQuotaExceeded once the source holds more.
+type quotaReader struct {
+	r         io.Reader
+	remaining int64
+}
+
+func (q *quotaReader) Read(b []byte) (int, error) {
+	// Read one byte past the limit so an exactly-full write still succeeds
+	// while any overflow is detected before it is sent.
+	if int64(len(b)) > q.remaining+1 {
+		b = b[:q.remaining+1]
+	}
+	n, err := q.r.Read(b)
+	if int64(n) > q.remaining {
+		return 0, storage.ErrQuotaExceeded
+	}
+	q.remaining -= int64(n)
+	return n, err
+}
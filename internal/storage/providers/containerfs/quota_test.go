@@ -0,0 +1,241 @@
+package containerfs
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/memohai/memoh/internal/storage"
+	"github.com/memohai/memoh/internal/workspace/bridge"
+	pb "github.com/memohai/memoh/internal/workspace/bridgepb"
+)
+
+// fakeContainer is an in-memory container file service. Paths are relative
+// to /data, as the provider writes them.
+type fakeContainer struct {
+	pb.UnimplementedContainerServiceServer
+	mu    sync.Mutex
+	files map[string][]byte
+}
+
+func dataRelative(path string) string {
+	return strings.TrimPrefix(strings.TrimPrefix(path, "/data"), "/")
+}
+
+func (f *fakeContainer) WriteRaw(stream grpc.ClientStreamingServer[pb.WriteRawChunk, pb.WriteRawResponse]) error {
+	var path string
+	var buf bytes.Buffer
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if chunk.GetPath() != "" {
+			path = chunk.GetPath()
+		}
+		buf.Write(chunk.GetData())
+	}
+	f.mu.Lock()
+	f.files[dataRelative(path)] = buf.Bytes()
+	f.mu.Unlock()
+	return stream.SendAndClose(&pb.WriteRawResponse{BytesWritten: int64(buf.Len())})
+}
+
+func (f *fakeContainer) ReadRaw(req *pb.ReadRawRequest, stream grpc.ServerStreamingServer[pb.DataChunk]) error {
+	f.mu.Lock()
+	data, ok := f.files[dataRelative(req.GetPath())]
+	f.mu.Unlock()
+	if !ok {
+		return status.Error(codes.NotFound, "no such file")
+	}
+	if len(data) == 0 {
+		return nil
+	}
+	return stream.Send(&pb.DataChunk{Data: data})
+}
+
+func (f *fakeContainer) Stat(_ context.Context, req *pb.StatRequest) (*pb.StatResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	data, ok := f.files[dataRelative(req.GetPath())]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "no such file")
+	}
+	return &pb.StatResponse{Entry: &pb.FileEntry{Path: req.GetPath(), Size: int64(len(data))}}, nil
+}
+
+// ListDir lists files recursively with paths relative to the requested
+// directory, like the bridge server.
+func (f *fakeContainer) ListDir(_ context.Context, req *pb.ListDirRequest) (*pb.ListDirResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	dir := dataRelative(req.GetPath())
+	entries := make([]*pb.FileEntry, 0, len(f.files))
+	for path, data := range f.files {
+		rel := path
+		if dir != "" {
+			var ok bool
+			if rel, ok = strings.CutPrefix(path, dir+"/"); !ok {
+				continue
+			}
+		}
+		entries = append(entries, &pb.FileEntry{Path: rel, Size: int64(len(data))})
+	}
+	return &pb.ListDirResponse{Entries: entries, TotalCount: int32(len(entries))}, nil //nolint:gosec // test data is tiny
+}
+
+func (f *fakeContainer) Rename(_ context.Context, req *pb.RenameRequest) (*pb.RenameResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	oldPath, newPath := dataRelative(req.GetOldPath()), dataRelative(req.GetNewPath())
+	data, ok := f.files[oldPath]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "no such file")
+	}
+	delete(f.files, oldPath)
+	f.files[newPath] = data
+	return &pb.RenameResponse{}, nil
+}
+
+func (f *fakeContainer) DeleteFile(_ context.Context, req *pb.DeleteFileRequest) (*pb.DeleteFileResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.files, dataRelative(req.GetPath()))
+	return &pb.DeleteFileResponse{}, nil
+}
+
+func (f *fakeContainer) put(path string, data []byte) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.files[path] = data
+}
+
+func (f *fakeContainer) size(path string) (int, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	data, ok := f.files[path]
+	return len(data), ok
+}
+
+type fakeClients struct {
+	client *bridge.Client
+}
+
+func (f fakeClients) MCPClient(context.Context, string) (*bridge.Client, error) {
+	return f.client, nil
+}
+
+func newTestProvider(t *testing.T, quota int64) (*Provider, *fakeContainer) {
+	t.Helper()
+
+	container := &fakeContainer{files: map[string][]byte{}}
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer()
+	pb.RegisterContainerServiceServer(srv, container)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = srv.Serve(lis)
+	}()
+	t.Cleanup(func() {
+		srv.Stop()
+		<-done
+	})
+
+	conn, err := grpc.NewClient("passthrough://bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("grpc.NewClient: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	p := New(fakeClients{client: bridge.NewClientFromConn(conn)})
+	p.SetQuota(quota)
+	return p, container
+}
+
+func TestProviderPutRejectsWritesPastQuota(t *testing.T) {
+	t.Parallel()
+	p, container := newTestProvider(t, 100)
+	ctx := context.Background()
+
+	if err := p.Put(ctx, "bot-1/image/a.png", bytes.NewReader(make([]byte, 60))); err != nil {
+		t.Fatalf("first Put failed: %v", err)
+	}
+	err := p.Put(ctx, "bot-1/image/b.png", bytes.NewReader(make([]byte, 50)))
+	if !errors.Is(err, storage.ErrQuotaExceeded) {
+		t.Fatalf("expected ErrQuotaExceeded, got %v", err)
+	}
+	if _, ok := container.size("media/image/b.png"); ok {
+		t.Fatal("expected rejected file not to be stored")
+	}
+	if _, ok := container.size("media/image/.b.png.upload"); ok {
+		t.Fatal("expected partial upload to be cleaned up")
+	}
+
+	// Exactly filling the quota is allowed.
+	if err := p.Put(ctx, "bot-1/image/c.png", bytes.NewReader(make([]byte, 40))); err != nil {
+		t.Fatalf("Put filling the quota failed: %v", err)
+	}
+	if err := p.Put(ctx, "bot-1/image/d.png", bytes.NewReader([]byte{1})); !errors.Is(err, storage.ErrQuotaExceeded) {
+		t.Fatalf("expected ErrQuotaExceeded on a full quota, got %v", err)
+	}
+}
+
+func TestProviderQuotaCountsOverwritesAndDeletes(t *testing.T) {
+	t.Parallel()
+	p, container := newTestProvider(t, 100)
+	ctx := context.Background()
+
+	if err := p.Put(ctx, "bot-1/file/doc.txt", bytes.NewReader(make([]byte, 90))); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	// Replacing a file only counts the size difference.
+	if err := p.WriteContainerFile(ctx, "bot-1", "/data/media/file/doc.txt", bytes.NewReader(make([]byte, 95))); err != nil {
+		t.Fatalf("overwrite failed: %v", err)
+	}
+	err := p.WriteContainerFile(ctx, "bot-1", "/data/media/file/doc.txt", bytes.NewReader(make([]byte, 120)))
+	if !errors.Is(err, storage.ErrQuotaExceeded) {
+		t.Fatalf("expected ErrQuotaExceeded, got %v", err)
+	}
+	if size, _ := container.size("media/file/doc.txt"); size != 95 {
+		t.Fatalf("expected rejected overwrite to keep the old file, got %d bytes", size)
+	}
+
+	if err := p.Delete(ctx, "bot-1/file/doc.txt"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if err := p.Put(ctx, "bot-1/file/new.txt", bytes.NewReader(make([]byte, 80))); err != nil {
+		t.Fatalf("Put after delete failed: %v", err)
+	}
+}
+
+func TestProviderWithoutQuotaWritesDirectly(t *testing.T) {
+	t.Parallel()
+	p, container := newTestProvider(t, 0)
+
+	if err := p.Put(context.Background(), "bot-1/image/big.png", bytes.NewReader(make([]byte, 4096))); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	if size, _ := container.size("media/image/big.png"); size != 4096 {
+		t.Fatalf("expected file to be stored, got %d bytes", size)
+	}
+}
@@ -5,13 +5,18 @@ package fallback
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 
 	"github.com/memohai/memoh/internal/storage"
 )
 
-var _ storage.ContainerFileOpener = (*Provider)(nil)
+var (
+	_ storage.ContainerFileOpener = (*Provider)(nil)
+	_ storage.ContainerFileWriter = (*Provider)(nil)
+	_ storage.IntegrityScanner    = (*Provider)(nil)
+)
 
 // Provider delegates to primary and falls back to secondary on write errors.
 type Provider struct {
@@ -29,6 +34,11 @@ func (p *Provider) Put(ctx context.Context, key string, reader io.Reader) error
 	if err == nil {
 		return nil
 	}
+	// A full quota is a decision, not an outage; writing to the secondary
+	// would bypass it.
+	if errors.Is(err, storage.ErrQuotaExceeded) {
+		return err
+	}
 	if seeker, ok := reader.(io.Seeker); ok {
 		if _, seekErr := seeker.Seek(0, io.SeekStart); seekErr != nil {
 			return err
@@ -101,3 +111,32 @@ func (p *Provider) OpenContainerFile(ctx context.Context, botID, containerPath s
 	}
 	return nil, storage.ErrContainerFileNotSupported
 }
+
+// WriteContainerFile delegates to whichever inner provider implements
+// storage.ContainerFileWriter, trying the primary first. Like
+// OpenContainerFile, a primary error is propagated rather than retried on the
+// secondary.
+func (p *Provider) WriteContainerFile(ctx context.Context, botID, containerPath string, reader io.Reader) error {
+	if writer, ok := p.primary.(storage.ContainerFileWriter); ok {
+		if err := writer.WriteContainerFile(ctx, botID, containerPath, reader); err != nil {
+			return fmt.Errorf("primary provider: %w", err)
+		}
+		return nil
+	}
+	if writer, ok := p.secondary.(storage.ContainerFileWriter); ok {
+		return writer.WriteContainerFile(ctx, botID, containerPath, reader)
+	}
+	return storage.ErrContainerFileNotSupported
+}
+
+// ScanIntegrity delegates to whichever inner provider implements
+// storage.IntegrityScanner, trying the primary first.
+func (p *Provider) ScanIntegrity(ctx context.Context, botID string) ([]storage.IntegrityError, error) {
+	if scanner, ok := p.primary.(storage.IntegrityScanner); ok {
+		return scanner.ScanIntegrity(ctx, botID)
+	}
+	if scanner, ok := p.secondary.(storage.IntegrityScanner); ok {
+		return scanner.ScanIntegrity(ctx, botID)
+	}
+	return nil, storage.ErrIntegrityScanNotSupported
+}
@@ -0,0 +1,133 @@
+// Package mirror implements storage.Provider that writes every object to a
+// primary provider (e.g. containerfs) and copies it to a replica (e.g. an S3
+// bucket). Consumers keep seeing the primary's access paths; the replica
+// serves reads the primary can no longer answer.
+package mirror
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/memohai/memoh/internal/storage"
+)
+
+var (
+	_ storage.ContainerFileOpener = (*Provider)(nil)
+	_ storage.ContainerFileWriter = (*Provider)(nil)
+	_ storage.IntegrityScanner    = (*Provider)(nil)
+)
+
+// Provider writes to primary and replica and reads from primary first.
+type Provider struct {
+	primary storage.Provider
+	replica storage.Provider
+}
+
+// New creates a mirrored provider.
+func New(primary, replica storage.Provider) *Provider {
+	return &Provider{primary: primary, replica: replica}
+}
+
+// Put writes data to the primary, then copies it to the replica. The copy is
+// read back from the primary when reader cannot be rewound.
+func (p *Provider) Put(ctx context.Context, key string, reader io.Reader) error {
+	if err := p.primary.Put(ctx, key, reader); err != nil {
+		return err
+	}
+	if seeker, ok := reader.(io.Seeker); ok {
+		if _, err := seeker.Seek(0, io.SeekStart); err == nil {
+			return p.replicate(ctx, key, reader)
+		}
+	}
+	rc, err := p.primary.Open(ctx, key)
+	if err != nil {
+		return fmt.Errorf("replicate: reopen from primary: %w", err)
+	}
+	defer func() { _ = rc.Close() }()
+	return p.replicate(ctx, key, rc)
+}
+
+func (p *Provider) replicate(ctx context.Context, key string, reader io.Reader) error {
+	if err := p.replica.Put(ctx, key, reader); err != nil {
+		return fmt.Errorf("replicate: %w", err)
+	}
+	return nil
+}
+
+// Open reads from the primary and falls back to the replica.
+func (p *Provider) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	rc, err := p.primary.Open(ctx, key)
+	if err == nil {
+		return rc, nil
+	}
+	return p.replica.Open(ctx, key)
+}
+
+// Delete removes the object from both providers. It fails only when neither
+// copy could be removed.
+func (p *Provider) Delete(ctx context.Context, key string) error {
+	primaryErr := p.primary.Delete(ctx, key)
+	replicaErr := p.replica.Delete(ctx, key)
+	if primaryErr != nil && replicaErr != nil {
+		return errors.Join(primaryErr, replicaErr)
+	}
+	return nil
+}
+
+// AccessPath returns the primary's access path, so consumers keep getting
+// the same references (e.g. container paths) as without the replica.
+func (p *Provider) AccessPath(key string) string {
+	return p.primary.AccessPath(key)
+}
+
+// ListPrefix lists keys from both providers and deduplicates them.
+func (p *Provider) ListPrefix(ctx context.Context, prefix string) ([]string, error) {
+	var keys []string
+	seen := map[string]struct{}{}
+	for _, provider := range []storage.Provider{p.primary, p.replica} {
+		lister, ok := provider.(storage.PrefixLister)
+		if !ok {
+			continue
+		}
+		listed, err := lister.ListPrefix(ctx, prefix)
+		if err != nil {
+			continue
+		}
+		for _, k := range listed {
+			if _, ok := seen[k]; !ok {
+				seen[k] = struct{}{}
+				keys = append(keys, k)
+			}
+		}
+	}
+	return keys, nil
+}
+
+// OpenContainerFile delegates to the primary.
+func (p *Provider) OpenContainerFile(ctx context.Context, botID, containerPath string) (io.ReadCloser, error) {
+	opener, ok := p.primary.(storage.ContainerFileOpener)
+	if !ok {
+		return nil, storage.ErrContainerFileNotSupported
+	}
+	return opener.OpenContainerFile(ctx, botID, containerPath)
+}
+
+// WriteContainerFile delegates to the primary.
+func (p *Provider) WriteContainerFile(ctx context.Context, botID, containerPath string, reader io.Reader) error {
+	writer, ok := p.primary.(storage.ContainerFileWriter)
+	if !ok {
+		return storage.ErrContainerFileNotSupported
+	}
+	return writer.WriteContainerFile(ctx, botID, containerPath, reader)
+}
+
+// ScanIntegrity delegates to the primary.
+func (p *Provider) ScanIntegrity(ctx context.Context, botID string) ([]storage.IntegrityError, error) {
+	scanner, ok := p.primary.(storage.IntegrityScanner)
+	if !ok {
+		return nil, storage.ErrIntegrityScanNotSupported
+	}
+	return scanner.ScanIntegrity(ctx, botID)
+}
@@ -0,0 +1,190 @@
+// Package s3 implements storage.Provider on an S3-compatible object store
+// such as AWS S3 or MinIO. Objects are stored at {prefix}/{routingKey}.
+package s3
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// Config configures the object store connection.
+type Config struct {
+	// Endpoint is the base URL of the service, e.g.
+	// "https://s3.us-east-1.amazonaws.com" or "http://minio:9000".
+	Endpoint        string
+	Region          string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+	// Prefix is prepended to every object key.
+	Prefix string
+	// PathStyle addresses objects as endpoint/bucket/key instead of
+	// bucket.endpoint/key.
+	PathStyle bool
+}
+
+// Provider stores media assets in an S3 bucket.
+type Provider struct {
+	cfg    Config
+	client *minio.Client
+}
+
+// New creates an S3 storage provider. A nil client uses the default
+// transport.
+func New(cfg Config, client *http.Client) (*Provider, error) {
+	cfg.Endpoint = strings.TrimRight(strings.TrimSpace(cfg.Endpoint), "/")
+	cfg.Bucket = strings.TrimSpace(cfg.Bucket)
+	cfg.Prefix = strings.Trim(strings.TrimSpace(cfg.Prefix), "/")
+	if cfg.Endpoint == "" || cfg.Bucket == "" {
+		return nil, errors.New("s3 endpoint and bucket are required")
+	}
+	endpoint, err := url.Parse(cfg.Endpoint)
+	if err != nil || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid s3 endpoint %q", cfg.Endpoint)
+	}
+	if cfg.Region == "" {
+		cfg.Region = "us-east-1"
+	}
+	lookup := minio.BucketLookupDNS
+	if cfg.PathStyle {
+		lookup = minio.BucketLookupPath
+	}
+	opts := &minio.Options{
+		Creds:        credentials.NewStaticV4(cfg.AccessKeyID, cfg.SecretAccessKey, ""),
+		Secure:       endpoint.Scheme == "https",
+		Region:       cfg.Region,
+		BucketLookup: lookup,
+	}
+	if client != nil {
+		opts.Transport = client.Transport
+	}
+	mc, err := minio.New(endpoint.Host, opts)
+	if err != nil {
+		return nil, fmt.Errorf("create s3 client: %w", err)
+	}
+	return &Provider{cfg: cfg, client: mc}, nil
+}
+
+// Put uploads data under key. Readers that are not seekable are buffered in
+// memory so the object is sent in one request.
+func (p *Provider) Put(ctx context.Context, key string, reader io.Reader) error {
+	objectKey, err := p.objectKey(key)
+	if err != nil {
+		return err
+	}
+	body, size, err := sizedBody(reader)
+	if err != nil {
+		return fmt.Errorf("read body: %w", err)
+	}
+	if _, err := p.client.PutObject(ctx, p.cfg.Bucket, objectKey, body, size, minio.PutObjectOptions{}); err != nil {
+		return fmt.Errorf("put object: %w", err)
+	}
+	return nil
+}
+
+// Open downloads the object at key. Missing objects return an error wrapping
+// fs.ErrNotExist.
+func (p *Provider) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	objectKey, err := p.objectKey(key)
+	if err != nil {
+		return nil, err
+	}
+	obj, err := p.client.GetObject(ctx, p.cfg.Bucket, objectKey, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("get object: %w", mapError(err))
+	}
+	// GetObject is lazy; Stat surfaces a missing object before the first read.
+	if _, err := obj.Stat(); err != nil {
+		_ = obj.Close()
+		return nil, fmt.Errorf("get object: %w", mapError(err))
+	}
+	return obj, nil
+}
+
+// Delete removes the object at key.
+func (p *Provider) Delete(ctx context.Context, key string) error {
+	objectKey, err := p.objectKey(key)
+	if err != nil {
+		return err
+	}
+	if err := p.client.RemoveObject(ctx, p.cfg.Bucket, objectKey, minio.RemoveObjectOptions{}); err != nil {
+		return fmt.Errorf("delete object: %w", mapError(err))
+	}
+	return nil
+}
+
+// AccessPath returns an s3://bucket/key reference. It names the object but is
+// not readable from inside a bot container, so the bucket is used as a
+// replica behind a provider whose access paths consumers can read.
+func (p *Provider) AccessPath(key string) string {
+	return "s3://" + p.cfg.Bucket + "/" + path.Join(p.cfg.Prefix, path.Clean(key))
+}
+
+// ListPrefix returns all keys that start with prefix.
+func (p *Provider) ListPrefix(ctx context.Context, prefix string) ([]string, error) {
+	objectPrefix := path.Join(p.cfg.Prefix, path.Clean(prefix))
+	var keys []string
+	for obj := range p.client.ListObjects(ctx, p.cfg.Bucket, minio.ListObjectsOptions{Prefix: objectPrefix, Recursive: true}) {
+		if obj.Err != nil {
+			return nil, fmt.Errorf("list objects: %w", mapError(obj.Err))
+		}
+		key := obj.Key
+		if p.cfg.Prefix != "" {
+			key = strings.TrimPrefix(key, p.cfg.Prefix+"/")
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// objectKey maps a routing key to its object key, rejecting keys that escape
+// the prefix.
+func (p *Provider) objectKey(key string) (string, error) {
+	clean := path.Clean(strings.ReplaceAll(key, "\\", "/"))
+	if clean == "." || clean == ".." || strings.HasPrefix(clean, "../") || strings.HasPrefix(clean, "/") {
+		return "", fmt.Errorf("invalid storage key: %s", key)
+	}
+	return path.Join(p.cfg.Prefix, clean), nil
+}
+
+// mapError turns S3 "not found" responses into fs.ErrNotExist.
+func mapError(err error) error {
+	resp := minio.ToErrorResponse(err)
+	if resp.StatusCode == http.StatusNotFound || resp.Code == "NoSuchKey" {
+		return fmt.Errorf("%s: %w", resp.Key, fs.ErrNotExist)
+	}
+	return err
+}
+
+// sizedBody returns reader with its remaining length, buffering it when the
+// length cannot be learned by seeking.
+func sizedBody(reader io.Reader) (io.Reader, int64, error) {
+	if seeker, ok := reader.(io.Seeker); ok {
+		current, err := seeker.Seek(0, io.SeekCurrent)
+		if err == nil {
+			end, err := seeker.Seek(0, io.SeekEnd)
+			if err == nil {
+				if _, err := seeker.Seek(current, io.SeekStart); err != nil {
+					return nil, 0, err
+				}
+				return reader, end - current, nil
+			}
+		}
+	}
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, 0, err
+	}
+	return bytes.NewReader(data), int64(len(data)), nil
+}
@@ -0,0 +1,243 @@
+package s3
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"io"
+	"io/fs"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/memohai/memoh/internal/media"
+	"github.com/memohai/memoh/internal/storage/providers/localfs"
+	"github.com/memohai/memoh/internal/storage/providers/mirror"
+)
+
+const (
+	testAccessKey = "AKIDEXAMPLE"
+	testSecretKey = "test-secret-key" //nolint:gosec // test fixture
+	testBucket    = "media-bucket"
+)
+
+// fakeS3 is a path-style, in-memory S3 server that checks the signing
+// credential of each request.
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.Contains(r.Header.Get("Authorization"), "Credential="+testAccessKey+"/") {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`<Error><Code>InvalidAccessKeyId</Code><Message>unknown access key</Message></Error>`))
+		return
+	}
+	key, ok := strings.CutPrefix(r.URL.Path, "/"+testBucket+"/")
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch {
+	case r.Method == http.MethodGet && r.URL.Query().Get("list-type") == "2":
+		prefix := r.URL.Query().Get("prefix")
+		type object struct {
+			Key string `xml:"Key"`
+		}
+		result := struct {
+			XMLName  xml.Name `xml:"ListBucketResult"`
+			Contents []object `xml:"Contents"`
+		}{}
+		keys := make([]string, 0, len(f.objects))
+		for k := range f.objects {
+			if strings.HasPrefix(k, prefix) {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			result.Contents = append(result.Contents, object{Key: k})
+		}
+		_ = xml.NewEncoder(w).Encode(result)
+	case r.Method == http.MethodPut:
+		if r.ContentLength < 0 {
+			w.WriteHeader(http.StatusLengthRequired)
+			return
+		}
+		data, _ := io.ReadAll(r.Body)
+		f.objects[key] = data
+		w.Header().Set("ETag", `"etag"`)
+	case r.Method == http.MethodGet || r.Method == http.MethodHead:
+		data, ok := f.objects[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`<Error><Code>NoSuchKey</Code><Message>missing</Message></Error>`))
+			return
+		}
+		w.Header().Set("ETag", `"etag"`)
+		w.Header().Set("Last-Modified", time.Unix(0, 0).UTC().Format(http.TimeFormat))
+		w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+		if r.Method == http.MethodGet {
+			_, _ = w.Write(data)
+		}
+	case r.Method == http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func newTestProvider(t *testing.T, prefix string) (*Provider, *fakeS3) {
+	t.Helper()
+	fake := &fakeS3{objects: map[string][]byte{}}
+	// TLS keeps minio-go from sending aws-chunked bodies.
+	srv := httptest.NewTLSServer(fake)
+	t.Cleanup(srv.Close)
+	p, err := New(Config{
+		Endpoint:        srv.URL,
+		Bucket:          testBucket,
+		AccessKeyID:     testAccessKey,
+		SecretAccessKey: testSecretKey,
+		Prefix:          prefix,
+		PathStyle:       true,
+	}, srv.Client())
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	return p, fake
+}
+
+func TestProviderPutOpenDelete(t *testing.T) {
+	t.Parallel()
+	p, fake := newTestProvider(t, "assets")
+	ctx := context.Background()
+	key := "bot-1/ab/ab12 cd+ef.png"
+
+	if err := p.Put(ctx, key, strings.NewReader("png bytes")); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	if _, ok := fake.objects["assets/"+key]; !ok {
+		t.Fatalf("expected object under prefix, got %v", fake.objects)
+	}
+
+	rc, err := p.Open(ctx, key)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	data, _ := io.ReadAll(rc)
+	_ = rc.Close()
+	if string(data) != "png bytes" {
+		t.Fatalf("unexpected content %q", data)
+	}
+
+	if err := p.Delete(ctx, key); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if _, err := p.Open(ctx, key); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("expected not-exist error after delete, got %v", err)
+	}
+}
+
+func TestProviderPutBuffersUnseekableReaders(t *testing.T) {
+	t.Parallel()
+	p, fake := newTestProvider(t, "")
+	reader := io.MultiReader(strings.NewReader("part one, "), strings.NewReader("part two"))
+
+	if err := p.Put(context.Background(), "bot-1/file.txt", reader); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	if got := string(fake.objects["bot-1/file.txt"]); got != "part one, part two" {
+		t.Fatalf("unexpected stored content %q", got)
+	}
+}
+
+func TestProviderRejectsBadCredentials(t *testing.T) {
+	t.Parallel()
+	_, fake := newTestProvider(t, "")
+	srv := httptest.NewTLSServer(fake)
+	t.Cleanup(srv.Close)
+	p, err := New(Config{Endpoint: srv.URL, Bucket: testBucket, AccessKeyID: "wrong", SecretAccessKey: testSecretKey, PathStyle: true}, srv.Client())
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	err = p.Put(context.Background(), "bot-1/file.txt", bytes.NewReader([]byte("x")))
+	if err == nil || !strings.Contains(err.Error(), "unknown access key") {
+		t.Fatalf("expected credential error, got %v", err)
+	}
+}
+
+func TestProviderRejectsTraversalKeys(t *testing.T) {
+	t.Parallel()
+	p, _ := newTestProvider(t, "")
+	for _, key := range []string{"../escape", "/absolute", ""} {
+		if err := p.Put(context.Background(), key, strings.NewReader("x")); err == nil {
+			t.Errorf("Put(%q) expected error", key)
+		}
+	}
+}
+
+func TestProviderAccessPath(t *testing.T) {
+	t.Parallel()
+	p, _ := newTestProvider(t, "assets")
+	if got := p.AccessPath("bot-1/ab/ab12.png"); got != "s3://"+testBucket+"/assets/bot-1/ab/ab12.png" {
+		t.Fatalf("unexpected access path %q", got)
+	}
+}
+
+func TestMediaServiceMirroredToS3(t *testing.T) {
+	t.Parallel()
+	p, fake := newTestProvider(t, "media")
+	primaryRoot := t.TempDir()
+	svc := media.NewService(slog.New(slog.DiscardHandler), mirror.New(localfs.New(primaryRoot), p))
+	ctx := context.Background()
+
+	asset, err := svc.Ingest(ctx, media.IngestInput{
+		BotID:       "bot-1",
+		Mime:        "application/octet-stream",
+		Reader:      strings.NewReader("custom format"),
+		OriginalExt: ".custom",
+	})
+	if err != nil {
+		t.Fatalf("Ingest failed: %v", err)
+	}
+	if _, ok := fake.objects["media/bot-1/"+asset.StorageKey]; !ok {
+		t.Fatalf("expected the asset to be copied to the bucket, got %v", fake.objects)
+	}
+	// Consumers keep getting the primary's path, not an s3:// reference.
+	if path := svc.AccessPath(asset); !strings.HasPrefix(path, primaryRoot) {
+		t.Fatalf("unexpected access path %q", path)
+	}
+
+	// Losing the primary copy falls back to the bucket. .custom is not a
+	// known extension, so this also resolves through ListPrefix.
+	if err := os.RemoveAll(primaryRoot); err != nil {
+		t.Fatalf("remove primary: %v", err)
+	}
+	rc, opened, err := svc.Open(ctx, "bot-1", asset.ContentHash)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	data, _ := io.ReadAll(rc)
+	_ = rc.Close()
+	if string(data) != "custom format" || opened.StorageKey != asset.StorageKey {
+		t.Fatalf("unexpected asset %+v with content %q", opened, data)
+	}
+
+	byKey, err := svc.GetByStorageKey(ctx, "bot-1", asset.StorageKey)
+	if err != nil || byKey.ContentHash != asset.ContentHash {
+		t.Fatalf("GetByStorageKey = %+v, %v", byKey, err)
+	}
+}
@@ -4,6 +4,7 @@ package storage
 import (
 	"context"
 	"errors"
+	"fmt"
 	"io"
 )
 
@@ -11,6 +12,35 @@ import (
 // implements ContainerFileOpener.
 var ErrContainerFileNotSupported = errors.New("provider does not support container file reading")
 
+// ErrIntegrityScanNotSupported is returned when no underlying provider
+// implements IntegrityScanner.
+var ErrIntegrityScanNotSupported = errors.New("provider does not support integrity scans")
+
+// ErrQuotaExceeded is returned when a write would take a bot past its storage
+// quota.
+var ErrQuotaExceeded = errors.New("storage quota exceeded")
+
+// ErrIntegrity marks stored content that no longer matches the content hash
+// in its key. errors.Is matches it against any *IntegrityError.
+var ErrIntegrity = errors.New("storage integrity check failed")
+
+// IntegrityError reports a content-addressed object whose bytes hash to
+// something other than the hash in its key.
+type IntegrityError struct {
+	Key      string `json:"key"`
+	Expected string `json:"expected"`
+	Actual   string `json:"actual"`
+}
+
+func (e *IntegrityError) Error() string {
+	return fmt.Sprintf("%s: content hash %s does not match key hash %s", e.Key, e.Actual, e.Expected)
+}
+
+// Is reports whether target is ErrIntegrity.
+func (*IntegrityError) Is(target error) bool {
+	return target == ErrIntegrity
+}
+
 // Provider abstracts object storage operations.
 type Provider interface {
 	// Put writes data to storage under the given key.
@@ -30,8 +60,20 @@ type ContainerFileOpener interface {
 	OpenContainerFile(ctx context.Context, botID, containerPath string) (io.ReadCloser, error)
 }
 
+// ContainerFileWriter is an optional interface that providers can implement
+// to write arbitrary files into a bot's container data directory.
+type ContainerFileWriter interface {
+	WriteContainerFile(ctx context.Context, botID, containerPath string, reader io.Reader) error
+}
+
 // PrefixLister is an optional interface for providers that can list keys
 // sharing a common prefix (e.g. directory listing on a filesystem backend).
 type PrefixLister interface {
 	ListPrefix(ctx context.Context, prefix string) ([]string, error)
 }
+
+// IntegrityScanner is an optional interface for providers that can re-hash a
+// bot's content-addressed objects and report the corrupted ones.
+type IntegrityScanner interface {
+	ScanIntegrity(ctx context.Context, botID string) ([]IntegrityError, error)
+}
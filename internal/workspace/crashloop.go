@@ -0,0 +1,193 @@
+package workspace
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// ErrCrashLoopBackoff is returned when a bot container was restarted too many
+// times in a short window and further restarts are deferred.
+var ErrCrashLoopBackoff = errors.New("container is crash looping")
+
+const (
+	crashLoopMaxRestarts = 5
+	crashLoopWindow      = 10 * time.Minute
+	crashLoopBaseBackoff = 30 * time.Second
+	crashLoopMaxBackoff  = 15 * time.Minute
+)
+
+// RestartStatus summarises the restart history of a bot container since the
+// server started.
+type RestartStatus struct {
+	RestartCount  int
+	LastError     string
+	LastRestartAt time.Time
+	BackoffUntil  time.Time
+	CrashLooping  bool
+}
+
+// restartTracker counts container restarts per bot and enforces an
+// exponential backoff once a bot restarts maxRestarts times within window.
+type restartTracker struct {
+	mu          sync.Mutex
+	now         func() time.Time
+	maxRestarts int
+	window      time.Duration
+	baseBackoff time.Duration
+	maxBackoff  time.Duration
+	bots        map[string]*restartHistory
+}
+
+type restartHistory struct {
+	recent        []time.Time
+	total         int
+	lastError     string
+	lastRestartAt time.Time
+	backoffUntil  time.Time
+	backoffs      int
+}
+
+func newRestartTracker() *restartTracker {
+	return &restartTracker{
+		now:         time.Now,
+		maxRestarts: crashLoopMaxRestarts,
+		window:      crashLoopWindow,
+		baseBackoff: crashLoopBaseBackoff,
+		maxBackoff:  crashLoopMaxBackoff,
+		bots:        make(map[string]*restartHistory),
+	}
+}
+
+// allowRestart returns ErrCrashLoopBackoff while the bot is backing off.
+func (t *restartTracker) allowRestart(botID string) error {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	h, ok := t.bots[botID]
+	if !ok {
+		return nil
+	}
+	if now := t.now(); now.Before(h.backoffUntil) {
+		return fmt.Errorf("%w: next restart allowed in %s", ErrCrashLoopBackoff, h.backoffUntil.Sub(now).Round(time.Second))
+	}
+	return nil
+}
+
+// recordRestart registers a restart attempt and the reason it was needed.
+// Reaching maxRestarts within the window starts a backoff that doubles with
+// every consecutive crash loop.
+func (t *restartTracker) recordRestart(botID, reason string) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := t.now()
+	h := t.history(botID)
+	h.recent = append(pruneRestarts(h.recent, now.Add(-t.window)), now)
+	h.total++
+	h.lastRestartAt = now
+	if reason != "" {
+		h.lastError = reason
+	}
+	if len(h.recent) >= t.maxRestarts {
+		backoff := t.maxBackoff
+		if h.backoffs < 16 {
+			backoff = min(t.baseBackoff<<h.backoffs, t.maxBackoff)
+		}
+		h.backoffs++
+		h.backoffUntil = now.Add(backoff)
+		h.recent = nil
+	}
+}
+
+// recordError stores the error of a failed restart as the last error.
+func (t *restartTracker) recordError(botID string, err error) {
+	if t == nil || err == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.history(botID).lastError = err.Error()
+}
+
+// recordRunning notes that the bot's task was found running. Once it has
+// stayed up for a full window since the last restart, the backoff resets.
+func (t *restartTracker) recordRunning(botID string) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	h, ok := t.bots[botID]
+	if !ok {
+		return
+	}
+	if t.now().Sub(h.lastRestartAt) >= t.window {
+		h.recent = nil
+		h.backoffs = 0
+		h.backoffUntil = time.Time{}
+	}
+}
+
+// status returns the restart history of a bot, if any restarts were recorded.
+func (t *restartTracker) status(botID string) (RestartStatus, bool) {
+	if t == nil {
+		return RestartStatus{}, false
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	h, ok := t.bots[botID]
+	if !ok {
+		return RestartStatus{}, false
+	}
+	return RestartStatus{
+		RestartCount:  h.total,
+		LastError:     h.lastError,
+		LastRestartAt: h.lastRestartAt,
+		BackoffUntil:  h.backoffUntil,
+		CrashLooping:  t.now().Before(h.backoffUntil),
+	}, true
+}
+
+func (t *restartTracker) history(botID string) *restartHistory {
+	h, ok := t.bots[botID]
+	if !ok {
+		h = &restartHistory{}
+		t.bots[botID] = h
+	}
+	return h
+}
+
+func pruneRestarts(times []time.Time, cutoff time.Time) []time.Time {
+	kept := times[:0]
+	for _, ts := range times {
+		if ts.After(cutoff) {
+			kept = append(kept, ts)
+		}
+	}
+	return kept
+}
+
+// beginRestart checks the crash-loop backoff for a bot and, when a restart is
+// allowed, records the attempt with the reason it was needed.
+func (m *Manager) beginRestart(botID, reason string) error {
+	if err := m.restarts.allowRestart(botID); err != nil {
+		m.logger.Warn("container restart deferred by crash-loop backoff",
+			slog.String("bot_id", botID), slog.Any("error", err))
+		return err
+	}
+	m.restarts.recordRestart(botID, reason)
+	return nil
+}
+
+// RestartStatus reports the restart history of a bot container. The boolean
+// is false when the container has not been restarted since the server started.
+func (m *Manager) RestartStatus(botID string) (RestartStatus, bool) {
+	return m.restarts.status(botID)
+}
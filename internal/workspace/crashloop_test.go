@@ -0,0 +1,116 @@
+package workspace
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/memohai/memoh/internal/config"
+	ctr "github.com/memohai/memoh/internal/containerd"
+)
+
+// crashingTaskService reports the bot's task as exited every time it is
+// inspected, simulating a container that crashes right after starting.
+type crashingTaskService struct {
+	legacyRouteTestService
+}
+
+func (*crashingTaskService) GetTaskInfo(context.Context, string) (ctr.TaskInfo, error) {
+	return ctr.TaskInfo{Status: ctr.TaskStatusStopped, ExitCode: 137}, nil
+}
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func TestEnsureRunningBacksOffCrashLoopingContainer(t *testing.T) {
+	botID := "00000000-0000-0000-0000-000000000003"
+	container := ctr.ContainerInfo{ID: "workspace-" + botID, Labels: map[string]string{BotLabelKey: botID}}
+	svc := &crashingTaskService{}
+	svc.created = true
+	svc.container = container
+	svc.byLabel = []ctr.ContainerInfo{container}
+
+	m := newLegacyRouteTestManager(t, svc, config.WorkspaceConfig{})
+	clock := &fakeClock{now: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+	m.restarts = newRestartTracker()
+	m.restarts.now = clock.Now
+	ctx := context.Background()
+
+	for i := range crashLoopMaxRestarts {
+		if err := m.EnsureRunning(ctx, botID); err != nil {
+			t.Fatalf("restart %d: unexpected error: %v", i+1, err)
+		}
+		clock.now = clock.now.Add(5 * time.Second)
+	}
+	if svc.startCalls != crashLoopMaxRestarts {
+		t.Fatalf("expected %d starts, got %d", crashLoopMaxRestarts, svc.startCalls)
+	}
+
+	if err := m.EnsureRunning(ctx, botID); !errors.Is(err, ErrCrashLoopBackoff) {
+		t.Fatalf("expected ErrCrashLoopBackoff, got %v", err)
+	}
+	if svc.startCalls != crashLoopMaxRestarts {
+		t.Fatalf("expected no start during backoff, got %d starts", svc.startCalls)
+	}
+
+	status, ok := m.RestartStatus(botID)
+	if !ok {
+		t.Fatal("expected restart status to be recorded")
+	}
+	if !status.CrashLooping {
+		t.Fatal("expected bot to be marked as crash looping")
+	}
+	if status.RestartCount != crashLoopMaxRestarts {
+		t.Fatalf("expected restart count %d, got %d", crashLoopMaxRestarts, status.RestartCount)
+	}
+	if !strings.Contains(status.LastError, "exit code 137") {
+		t.Fatalf("expected last error to mention exit code, got %q", status.LastError)
+	}
+
+	// After the backoff expires a restart is allowed again.
+	clock.now = status.BackoffUntil
+	if err := m.EnsureRunning(ctx, botID); err != nil {
+		t.Fatalf("expected restart after backoff, got %v", err)
+	}
+	if svc.startCalls != crashLoopMaxRestarts+1 {
+		t.Fatalf("expected restart after backoff, got %d starts", svc.startCalls)
+	}
+}
+
+func TestRestartTrackerBackoffGrowsAndResets(t *testing.T) {
+	t.Parallel()
+	clock := &fakeClock{now: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+	tr := newRestartTracker()
+	tr.now = clock.Now
+
+	crashLoop := func() time.Duration {
+		for range crashLoopMaxRestarts {
+			tr.recordRestart("bot-1", "task stopped")
+		}
+		status, _ := tr.status("bot-1")
+		return status.BackoffUntil.Sub(clock.now)
+	}
+
+	if got := crashLoop(); got != crashLoopBaseBackoff {
+		t.Fatalf("expected first backoff %s, got %s", crashLoopBaseBackoff, got)
+	}
+	clock.now = clock.now.Add(crashLoopBaseBackoff)
+	if got := crashLoop(); got != 2*crashLoopBaseBackoff {
+		t.Fatalf("expected doubled backoff %s, got %s", 2*crashLoopBaseBackoff, got)
+	}
+
+	// Staying up for a full window resets the backoff.
+	clock.now = clock.now.Add(crashLoopWindow)
+	tr.recordRunning("bot-1")
+	if err := tr.allowRestart("bot-1"); err != nil {
+		t.Fatalf("expected restart allowed after stable window, got %v", err)
+	}
+	if got := crashLoop(); got != crashLoopBaseBackoff {
+		t.Fatalf("expected backoff reset to %s after stable run, got %s", crashLoopBaseBackoff, got)
+	}
+}
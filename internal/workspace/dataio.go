@@ -29,12 +29,27 @@ const (
 // ExportData streams a tar.gz archive of the container's /data directory.
 // The container is stopped during export and restarted afterwards.
 // Caller must consume the returned reader before the context is cancelled.
+//
+// When the bot has no container but preserved data exists (e.g. the container
+// was deleted with preserveData before a migration), the preserved archive is
+// streamed instead.
 func (m *Manager) ExportData(ctx context.Context, botID string) (io.ReadCloser, error) {
-	containerID := m.resolveContainerID(ctx, botID)
+	containerID, err := m.resolveContainerID(ctx, botID)
+	if err != nil {
+		if f, openErr := os.Open(m.backupPath(botID)); openErr == nil {
+			return f, nil
+		}
+		return nil, err
+	}
 	unlock := m.lockContainer(containerID)
 	defer unlock()
 
 	info, err := m.service.GetContainer(ctx, containerID)
+	if errdefs.IsNotFound(err) {
+		if f, openErr := os.Open(m.backupPath(botID)); openErr == nil {
+			return f, nil
+		}
+	}
 	if err != nil {
 		return nil, fmt.Errorf("get container: %w", err)
 	}
@@ -75,7 +90,10 @@ func (m *Manager) ExportData(ctx context.Context, botID string) (io.ReadCloser,
 // ImportData extracts a tar.gz archive into the container's /data directory.
 // The container is stopped during import and restarted afterwards.
 func (m *Manager) ImportData(ctx context.Context, botID string, r io.Reader) error {
-	containerID := m.resolveContainerID(ctx, botID)
+	containerID, err := m.resolveContainerID(ctx, botID)
+	if err != nil {
+		return err
+	}
 	unlock := m.lockContainer(containerID)
 	defer unlock()
 
@@ -106,13 +124,65 @@ func (m *Manager) ImportData(ctx context.Context, botID string, r io.Reader) err
 	})
 }
 
+// ImportOrStageData imports a tar.gz archive into the bot's container /data.
+// When the bot has no container yet (e.g. it is being migrated from another
+// host), the archive is staged as preserved data instead and restored into
+// the fresh snapshot when the container is created. It reports whether the
+// archive was staged.
+func (m *Manager) ImportOrStageData(ctx context.Context, botID string, r io.Reader) (bool, error) {
+	containerID := m.containerIDOrDefault(ctx, botID)
+	if _, err := m.service.GetContainer(ctx, containerID); err != nil {
+		if !errdefs.IsNotFound(err) {
+			return false, fmt.Errorf("get container: %w", err)
+		}
+		unlock := m.lockContainer(containerID)
+		defer unlock()
+		if err := m.StageData(botID, r); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+	return false, m.ImportData(ctx, botID, r)
+}
+
+// StageData streams a tar.gz archive to the bot's preserved-data backup so it
+// is restored on the next container creation. The archive is validated before
+// it replaces any existing backup.
+func (m *Manager) StageData(botID string, r io.Reader) error {
+	backupPath := m.backupPath(botID)
+	if err := os.MkdirAll(filepath.Dir(backupPath), 0o750); err != nil {
+		return fmt.Errorf("create backup dir: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(backupPath), botID+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("create staging file: %w", err)
+	}
+	tmpPath := tmp.Name()
+	defer func() { _ = os.Remove(tmpPath) }()
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("write staging file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := validateTarGz(tmpPath); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, backupPath) //nolint:gosec // G703: operator-controlled path
+}
+
 // PreserveData exports /data to a backup tar.gz on the host. Used before
 // deleting a container when the user chooses to preserve data.
 // For snapshot-mount backends the caller must stop the task first so the
 // mounted snapshot is consistent; the Apple fallback uses gRPC and does not
 // require a stop.
 func (m *Manager) PreserveData(ctx context.Context, botID string) error {
-	containerID := m.resolveContainerID(ctx, botID)
+	containerID, err := m.resolveContainerID(ctx, botID)
+	if err != nil {
+		return err
+	}
 
 	info, err := m.service.GetContainer(ctx, containerID)
 	if err != nil {
@@ -202,7 +272,10 @@ func (m *Manager) HasPreservedData(botID string) bool {
 // importLegacyDir copies a legacy bind-mount directory into the container
 // via snapshot mount, then renames the source to .migrated.
 func (m *Manager) importLegacyDir(ctx context.Context, botID, srcDir string) error {
-	containerID := m.resolveContainerID(ctx, botID)
+	containerID, err := m.resolveContainerID(ctx, botID)
+	if err != nil {
+		return err
+	}
 
 	info, err := m.service.GetContainer(ctx, containerID)
 	if err != nil {
@@ -250,7 +323,7 @@ func (m *Manager) recoverOrphanedSnapshot(ctx context.Context, botID string) boo
 		return false
 	}
 
-	snapshotKey := m.resolveContainerID(ctx, botID)
+	snapshotKey := m.containerIDOrDefault(ctx, botID)
 	raw, err := m.service.SnapshotMounts(ctx, snapshotter, snapshotKey)
 	if err != nil {
 		return false
@@ -308,7 +381,7 @@ func (m *Manager) restorePreservedIntoSnapshot(ctx context.Context, botID string
 	}
 	defer func() { _ = f.Close() }()
 
-	containerID := m.resolveContainerID(ctx, botID)
+	containerID := m.containerIDOrDefault(ctx, botID)
 	info, err := m.service.GetContainer(ctx, containerID)
 	if err != nil {
 		return fmt.Errorf("get container: %w", err)
@@ -656,6 +729,34 @@ func untarGzDir(r io.Reader, dst string) error {
 	}
 }
 
+// validateTarGz checks that path is a readable tar.gz archive whose entries
+// stay inside the extraction root.
+func validateTarGz(path string) error {
+	f, err := os.Open(path) //nolint:gosec // G304: operator-controlled path
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+	gr, err := gzip.NewReader(f)
+	if err != nil {
+		return fmt.Errorf("gzip reader: %w", err)
+	}
+	defer func() { _ = gr.Close() }()
+	tr := tar.NewReader(gr)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("tar next: %w", err)
+		}
+		if _, err := sanitizeArchivePath(header.Name); err != nil {
+			return err
+		}
+	}
+}
+
 // copyDirContents copies all files from src into dst (both must be directories).
 func copyDirContents(src, dst string) error {
 	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
@@ -0,0 +1,112 @@
+package workspace
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/memohai/memoh/internal/config"
+)
+
+func writeTestDataDir(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("write file: %v", err)
+		}
+	}
+	return dir
+}
+
+func TestStagedDataExportImportRoundTrip(t *testing.T) {
+	dataRoot := t.TempDir()
+	svc := &legacyRouteTestService{}
+	m := newLegacyRouteTestManager(t, svc, config.WorkspaceConfig{DataRoot: dataRoot})
+	botID := "00000000-0000-0000-0000-000000000001"
+
+	files := map[string]string{
+		"skills/weather/SKILL.md": "# Weather\n",
+		"notes/todo.txt":          "buy milk\n",
+	}
+	var archive bytes.Buffer
+	if err := tarGzDir(&archive, writeTestDataDir(t, files)); err != nil {
+		t.Fatalf("tarGzDir: %v", err)
+	}
+
+	staged, err := m.ImportOrStageData(context.Background(), botID, bytes.NewReader(archive.Bytes()))
+	if err != nil {
+		t.Fatalf("ImportOrStageData: %v", err)
+	}
+	if !staged {
+		t.Fatal("expected archive to be staged for a bot without a container")
+	}
+	if !m.HasPreservedData(botID) {
+		t.Fatal("expected staged archive to count as preserved data")
+	}
+
+	reader, err := m.ExportData(context.Background(), botID)
+	if err != nil {
+		t.Fatalf("ExportData: %v", err)
+	}
+	defer func() { _ = reader.Close() }()
+
+	restored := t.TempDir()
+	if err := untarGzDir(reader, restored); err != nil {
+		t.Fatalf("untarGzDir: %v", err)
+	}
+	for name, want := range files {
+		got, err := os.ReadFile(filepath.Join(restored, filepath.FromSlash(name))) //nolint:gosec // G304: test temp dir
+		if err != nil {
+			t.Fatalf("read %s: %v", name, err)
+		}
+		if string(got) != want {
+			t.Fatalf("%s = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestStageDataRejectsInvalidArchives(t *testing.T) {
+	dataRoot := t.TempDir()
+	m := newLegacyRouteTestManager(t, &legacyRouteTestService{}, config.WorkspaceConfig{DataRoot: dataRoot})
+	botID := "00000000-0000-0000-0000-000000000002"
+
+	if err := m.StageData(botID, bytes.NewReader([]byte("not a tarball"))); err == nil {
+		t.Fatal("expected error for non-gzip input")
+	}
+
+	var traversal bytes.Buffer
+	gw := gzip.NewWriter(&traversal)
+	tw := tar.NewWriter(gw)
+	if err := tw.WriteHeader(&tar.Header{Name: "../escape.txt", Mode: 0o600, Size: 1, Typeflag: tar.TypeReg}); err != nil {
+		t.Fatalf("write header: %v", err)
+	}
+	if _, err := io.WriteString(tw, "x"); err != nil {
+		t.Fatalf("write body: %v", err)
+	}
+	_ = tw.Close()
+	_ = gw.Close()
+	if err := m.StageData(botID, &traversal); err == nil {
+		t.Fatal("expected error for path traversal entry")
+	}
+
+	if m.HasPreservedData(botID) {
+		t.Fatal("expected rejected archives to leave no preserved data")
+	}
+	entries, err := os.ReadDir(filepath.Join(dataRoot, backupsSubdir))
+	if err != nil {
+		t.Fatalf("read backups dir: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Fatalf("expected staging files to be cleaned up, found %d", len(entries))
+	}
+}
@@ -62,6 +62,7 @@ type Manager struct {
 	grpcPool        *bridge.Pool
 	legacyMu        sync.RWMutex
 	legacyIPs       map[string]string // botID → IP for pre-bridge containers
+	restarts        *restartTracker
 }
 
 func NewManager(log *slog.Logger, service ctr.Service, cfg config.WorkspaceConfig, namespace string, conn *pgxpool.Pool) *Manager {
@@ -77,6 +78,7 @@ func NewManager(log *slog.Logger, service ctr.Service, cfg config.WorkspaceConfi
 		logger:         log.With(slog.String("component", "workspace")),
 		containerLocks: make(map[string]*sync.Mutex),
 		legacyIPs:      make(map[string]string),
+		restarts:       newRestartTracker(),
 	}
 	m.grpcPool = bridge.NewPool(m.dialTarget)
 	return m
@@ -84,9 +86,24 @@ func NewManager(log *slog.Logger, service ctr.Service, cfg config.WorkspaceConfi
 
 // resolveContainerID resolves the actual containerd container ID for a bot.
 // This is the SINGLE point of container ID resolution for all lookup operations.
-// It delegates to ContainerID (DB → label → scan) and falls back to the
-// new-style prefix if no container exists yet.
-func (m *Manager) resolveContainerID(ctx context.Context, botID string) string {
+// It delegates to ContainerID (DB → label → scan). When no container is found
+// it falls back to the new-style prefix, unless strict_container_id is set,
+// in which case the lookup error is returned.
+func (m *Manager) resolveContainerID(ctx context.Context, botID string) (string, error) {
+	id, err := m.ContainerID(ctx, botID)
+	if err != nil {
+		if m.cfg.StrictContainerID {
+			return "", fmt.Errorf("resolve container for bot %s: %w", botID, err)
+		}
+		return ContainerPrefix + botID, nil
+	}
+	return id, nil
+}
+
+// containerIDOrDefault resolves the container ID for a bot, falling back to
+// the new-style prefix in every mode. It is used where the container is about
+// to be created or was just created, so the default name is the right guess.
+func (m *Manager) containerIDOrDefault(ctx context.Context, botID string) string {
 	id, err := m.ContainerID(ctx, botID)
 	if err != nil {
 		return ContainerPrefix + botID
@@ -356,7 +373,7 @@ func (m *Manager) StartWithResolvedConfig(ctx context.Context, botID, image stri
 }
 
 func (m *Manager) startWithResolvedConfig(ctx context.Context, botID, image string, gpu WorkspaceGPUConfig) error {
-	containerID := m.resolveContainerID(ctx, botID)
+	containerID := m.containerIDOrDefault(ctx, botID)
 
 	// Before creating a new container, check for an orphaned snapshot
 	// (container deleted but snapshot with /data survived). Export /data
@@ -406,7 +423,11 @@ func (m *Manager) Stop(ctx context.Context, botID string, timeout time.Duration)
 	if err := validateBotID(botID); err != nil {
 		return err
 	}
-	return m.service.StopContainer(ctx, m.resolveContainerID(ctx, botID), &ctr.StopTaskOptions{
+	containerID, err := m.resolveContainerID(ctx, botID)
+	if err != nil {
+		return err
+	}
+	return m.service.StopContainer(ctx, containerID, &ctr.StopTaskOptions{
 		Timeout: timeout,
 		Force:   true,
 	})
@@ -417,7 +438,10 @@ func (m *Manager) Delete(ctx context.Context, botID string, preserveData bool) e
 		return err
 	}
 
-	containerID := m.resolveContainerID(ctx, botID)
+	containerID, err := m.resolveContainerID(ctx, botID)
+	if err != nil {
+		return err
+	}
 
 	stoppedForPreserve := false
 
@@ -2,6 +2,8 @@ package workspace
 
 import (
 	"context"
+	"errors"
+	"io"
 	"log/slog"
 	"os"
 	"path/filepath"
@@ -152,6 +154,10 @@ func (*legacyRouteTestService) SnapshotMounts(context.Context, string, string) (
 	return nil, ctr.ErrNotSupported
 }
 
+func (*legacyRouteTestService) TaskLogs(context.Context, string, *ctr.TaskLogsOptions) (io.ReadCloser, error) {
+	return nil, os.ErrNotExist
+}
+
 func newLegacyRouteTestManager(t *testing.T, svc ctr.Service, cfg config.WorkspaceConfig) *Manager {
 	t.Helper()
 	logger := slog.New(slog.DiscardHandler)
@@ -290,3 +296,57 @@ func TestContainerIDFallsBackToNameInference(t *testing.T) {
 		t.Fatalf("expected inferred container ID, got %q", containerID)
 	}
 }
+
+func TestResolveContainerIDLenientFallsBackToDefaultName(t *testing.T) {
+	t.Parallel()
+
+	botID := "00000000-0000-0000-0000-000000000001"
+	m := newLegacyRouteTestManager(t, &legacyRouteTestService{}, config.WorkspaceConfig{})
+
+	containerID, err := m.resolveContainerID(context.Background(), botID)
+	if err != nil {
+		t.Fatalf("resolveContainerID failed: %v", err)
+	}
+	if containerID != ContainerPrefix+botID {
+		t.Fatalf("expected default container ID, got %q", containerID)
+	}
+}
+
+func TestResolveContainerIDStrictReturnsNotFound(t *testing.T) {
+	t.Parallel()
+
+	botID := "00000000-0000-0000-0000-000000000001"
+	m := newLegacyRouteTestManager(t, &legacyRouteTestService{}, config.WorkspaceConfig{StrictContainerID: true})
+
+	if _, err := m.resolveContainerID(context.Background(), botID); !errors.Is(err, ErrContainerNotFound) {
+		t.Fatalf("expected ErrContainerNotFound, got %v", err)
+	}
+	if err := m.Stop(context.Background(), botID, time.Second); !errors.Is(err, ErrContainerNotFound) {
+		t.Fatalf("expected Stop to fail fast, got %v", err)
+	}
+	if got := m.containerIDOrDefault(context.Background(), botID); got != ContainerPrefix+botID {
+		t.Fatalf("expected create path to keep the default name, got %q", got)
+	}
+}
+
+func TestResolveContainerIDStrictFindsExistingContainer(t *testing.T) {
+	t.Parallel()
+
+	botID := "00000000-0000-0000-0000-000000000001"
+	svc := &legacyRouteTestService{
+		byLabel: []ctr.ContainerInfo{{
+			ID:        "workspace-from-label",
+			Labels:    map[string]string{BotLabelKey: botID},
+			UpdatedAt: time.Now(),
+		}},
+	}
+	m := newLegacyRouteTestManager(t, svc, config.WorkspaceConfig{StrictContainerID: true})
+
+	containerID, err := m.resolveContainerID(context.Background(), botID)
+	if err != nil {
+		t.Fatalf("resolveContainerID failed: %v", err)
+	}
+	if containerID != "workspace-from-label" {
+		t.Fatalf("expected label-resolved container ID, got %q", containerID)
+	}
+}
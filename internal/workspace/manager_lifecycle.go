@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"strings"
 	"time"
@@ -130,12 +131,14 @@ func (m *Manager) setupNetworkOrFail(ctx context.Context, containerID, botID str
 // EnsureRunning verifies the container exists and its task is running.
 // If the container is missing, it rebuilds via SetupBotContainer.
 // If the task is stopped, it restarts and sets up networking.
+// Rebuilds and restarts return ErrCrashLoopBackoff while the bot is backing
+// off after restarting too often.
 func (m *Manager) EnsureRunning(ctx context.Context, botID string) error {
 	containerID, err := m.ContainerID(ctx, botID)
 	if err != nil {
 		if errors.Is(err, ErrContainerNotFound) {
 			m.logger.Warn("container missing, rebuilding", slog.String("bot_id", botID))
-			return m.SetupBotContainer(ctx, botID)
+			return m.rebuildBotContainer(ctx, botID, "container missing")
 		}
 		return err
 	}
@@ -147,31 +150,54 @@ func (m *Manager) EnsureRunning(ctx context.Context, botID string) error {
 		}
 		m.logger.Warn("container missing in containerd, rebuilding",
 			slog.String("bot_id", botID), slog.String("container_id", containerID))
-		return m.SetupBotContainer(ctx, botID)
+		return m.rebuildBotContainer(ctx, botID, "container missing in containerd")
 	}
 
+	reason := "task not found"
 	taskInfo, err := m.service.GetTaskInfo(ctx, containerID)
 	if err == nil {
 		if taskInfo.Status == ctr.TaskStatusRunning {
+			m.restarts.recordRunning(botID)
 			return m.setupNetworkOrFail(ctx, containerID, botID)
 		}
+		reason = fmt.Sprintf("task %s with exit code %d", taskInfo.Status, taskInfo.ExitCode)
+		if err := m.beginRestart(botID, reason); err != nil {
+			return err
+		}
 		if err := m.service.DeleteTask(ctx, containerID, &ctr.DeleteTaskOptions{Force: true}); err != nil {
 			if !errdefs.IsNotFound(err) {
 				m.logger.Warn("cleanup: delete task failed",
 					slog.String("container_id", containerID), slog.Any("error", err))
+				m.restarts.recordError(botID, err)
 				return err
 			}
 		}
 	} else if !errdefs.IsNotFound(err) {
 		return err
+	} else if err := m.beginRestart(botID, reason); err != nil {
+		return err
 	}
 
 	if err := m.service.StartContainer(ctx, containerID, nil); err != nil {
+		m.restarts.recordError(botID, err)
 		return err
 	}
 	return m.setupNetworkOrFail(ctx, containerID, botID)
 }
 
+// rebuildBotContainer recreates a missing container, subject to the
+// crash-loop backoff.
+func (m *Manager) rebuildBotContainer(ctx context.Context, botID, reason string) error {
+	if err := m.beginRestart(botID, reason); err != nil {
+		return err
+	}
+	if err := m.SetupBotContainer(ctx, botID); err != nil {
+		m.restarts.recordError(botID, err)
+		return err
+	}
+	return nil
+}
+
 // StopBot stops the container task for a bot and marks it stopped in DB.
 func (m *Manager) StopBot(ctx context.Context, botID string) error {
 	containerID, err := m.ContainerID(ctx, botID)
@@ -262,6 +288,16 @@ func (m *Manager) PullImage(ctx context.Context, image string, opts *ctr.PullIma
 	return m.service.PullImage(ctx, image, opts)
 }
 
+// ContainerLogs opens the stdout/stderr stream of a bot's container task.
+// The caller must close the returned reader.
+func (m *Manager) ContainerLogs(ctx context.Context, botID string, opts *ctr.TaskLogsOptions) (io.ReadCloser, error) {
+	containerID, err := m.ContainerID(ctx, botID)
+	if err != nil {
+		return nil, err
+	}
+	return m.service.TaskLogs(ctx, containerID, opts)
+}
+
 // ---------------------------------------------------------------------------
 // Container lifecycle (bots.ContainerLifecycle interface)
 // ---------------------------------------------------------------------------
@@ -289,7 +325,7 @@ func (m *Manager) SetupBotContainer(ctx context.Context, botID string) error {
 			slog.Any("error", err))
 	}
 
-	containerID := m.resolveContainerID(ctx, botID)
+	containerID := m.containerIDOrDefault(ctx, botID)
 	m.upsertContainerRecord(ctx, botID, containerID, "running", image)
 	return nil
 }
@@ -351,7 +387,7 @@ func (m *Manager) ReconcileContainers(ctx context.Context) {
 			// Container missing in containerd — rebuild.
 			m.logger.Warn("reconcile: container missing, rebuilding",
 				slog.String("bot_id", botID), slog.String("container_id", containerID))
-			if setupErr := m.SetupBotContainer(ctx, botID); setupErr != nil {
+			if setupErr := m.rebuildBotContainer(ctx, botID, "container missing in containerd"); setupErr != nil {
 				m.logger.Error("reconcile: rebuild failed",
 					slog.String("bot_id", botID), slog.Any("error", setupErr))
 				m.markContainerStatus(ctx, botID, "error")
@@ -387,6 +423,7 @@ func (m *Manager) ReconcileContainers(ctx context.Context) {
 		// Container exists — ensure the task is running.
 		running := m.isTaskRunning(ctx, containerID)
 		if running {
+			m.restarts.recordRunning(botID)
 			if row.Status != "running" {
 				m.markContainerStarted(ctx, botID)
 			}
@@ -66,7 +66,10 @@ func (m *Manager) CreateSnapshot(ctx context.Context, botID, snapshotName, sourc
 		return nil, err
 	}
 
-	containerID := m.resolveContainerID(ctx, botID)
+	containerID, err := m.resolveContainerID(ctx, botID)
+	if err != nil {
+		return nil, err
+	}
 	unlock := m.lockContainer(containerID)
 	defer unlock()
 
@@ -142,7 +145,10 @@ func (m *Manager) CreateVersion(ctx context.Context, botID string) (*VersionInfo
 		return nil, err
 	}
 
-	containerID := m.resolveContainerID(ctx, botID)
+	containerID, err := m.resolveContainerID(ctx, botID)
+	if err != nil {
+		return nil, err
+	}
 	unlock := m.lockContainer(containerID)
 	defer unlock()
 
@@ -210,7 +216,10 @@ func (m *Manager) ListBotSnapshotData(ctx context.Context, botID string) (*BotSn
 		return nil, err
 	}
 
-	containerID := m.resolveContainerID(ctx, botID)
+	containerID, err := m.resolveContainerID(ctx, botID)
+	if err != nil {
+		return nil, err
+	}
 	unlock := m.lockContainer(containerID)
 	defer unlock()
 
@@ -272,7 +281,10 @@ func (m *Manager) ListVersions(ctx context.Context, botID string) ([]VersionInfo
 		return nil, err
 	}
 
-	containerID := m.resolveContainerID(ctx, botID)
+	containerID, err := m.resolveContainerID(ctx, botID)
+	if err != nil {
+		return nil, err
+	}
 	versions, err := m.queries.ListVersionsByContainerID(ctx, containerID)
 	if err != nil {
 		return nil, err
@@ -307,7 +319,10 @@ func (m *Manager) RollbackVersion(ctx context.Context, botID string, version int
 		return errors.New("version out of range")
 	}
 
-	containerID := m.resolveContainerID(ctx, botID)
+	containerID, err := m.resolveContainerID(ctx, botID)
+	if err != nil {
+		return err
+	}
 	unlock := m.lockContainer(containerID)
 	defer unlock()
 
@@ -353,7 +368,10 @@ func (m *Manager) VersionSnapshotName(ctx context.Context, botID string, version
 		return "", errors.New("version out of range")
 	}
 
-	containerID := m.resolveContainerID(ctx, botID)
+	containerID, err := m.resolveContainerID(ctx, botID)
+	if err != nil {
+		return "", err
+	}
 	return m.queries.GetVersionSnapshotRuntimeName(ctx, dbsqlc.GetVersionSnapshotRuntimeNameParams{
 		ContainerID: containerID,
 		Version:     int32(version),
@@ -5183,6 +5183,69 @@ const docTemplate = `{
                 }
             }
         },
+        "/bots/{id}/channel/{platform}/restart": {
+            "post": {
+                "description": "Stop and reconnect the bot's channel adapter without changing its config",
+                "tags": [
+                    "bots"
+                ],
+                "summary": "Restart bot channel connection",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bot ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Channel platform",
+                        "name": "platform",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/channel.ChannelConfig"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/bots/{id}/channel/{platform}/send": {
             "post": {
                 "description": "Send a message using bot channel configuration",
@@ -5437,6 +5500,64 @@ const docTemplate = `{
                 }
             }
         },
+        "/bots/{id}/clone": {
+            "post": {
+                "description": "Create a bot with the profile, metadata, settings, channel configs, skills and shared memories of an existing bot (owner/admin only). The clone is owned by the source bot's owner. Channel configs are copied disabled and without credentials. If any part cannot be copied the clone is deleted again.",
+                "tags": [
+                    "bots"
+                ],
+                "summary": "Clone bot",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Source bot ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Clone options",
+                        "name": "payload",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/bots.CloneBotRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/bots.Bot"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/bots/{id}/owner": {
             "put": {
                 "description": "Transfer bot ownership to another human user",
@@ -5496,6 +5617,68 @@ const docTemplate = `{
                 }
             }
         },
+        "/bots/{id}/undelete": {
+            "post": {
+                "description": "Restore a deleted bot before its delete grace period ends (owner/admin only)",
+                "tags": [
+                    "bots"
+                ],
+                "summary": "Undelete bot",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bot ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/bots.Bot"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "410": {
+                        "description": "Gone",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/browser-contexts": {
             "get": {
                 "description": "List all browser context configurations",
@@ -5720,6 +5903,64 @@ const docTemplate = `{
                 }
             }
         },
+        "/channel-connections": {
+            "get": {
+                "description": "Lists the channel configs the server currently runs, with their connection state, last error and the time of their last inbound event.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "channel"
+                ],
+                "summary": "List active channel connections (admin only)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only connections of this bot",
+                        "name": "bot_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only connections of this channel type",
+                        "name": "channel_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only connections in this state: connected, error or disconnected",
+                        "name": "state",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ChannelConnectionListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/channels": {
             "get": {
                 "description": "List channel meta information including capabilities and schemas",
@@ -9455,6 +9696,26 @@ const docTemplate = `{
                 }
             }
         },
+        "bots.CloneBotRequest": {
+            "type": "object",
+            "properties": {
+                "channels": {
+                    "type": "boolean"
+                },
+                "display_name": {
+                    "type": "string"
+                },
+                "memories": {
+                    "type": "boolean"
+                },
+                "settings": {
+                    "type": "boolean"
+                },
+                "skills": {
+                    "type": "boolean"
+                }
+            }
+        },
         "bots.CreateBotRequest": {
             "type": "object",
             "properties": {
@@ -10477,6 +10738,50 @@ const docTemplate = `{
                 }
             }
         },
+        "handlers.ChannelConnection": {
+            "type": "object",
+            "properties": {
+                "bot_id": {
+                    "type": "string"
+                },
+                "channel_type": {
+                    "$ref": "#/definitions/channel.ChannelType"
+                },
+                "config_id": {
+                    "type": "string"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "last_event_at": {
+                    "type": "string"
+                },
+                "state": {
+                    "description": "State is \"connected\", \"error\" or \"disconnected\".",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.ChannelConnectionListResponse": {
+            "type": "object",
+            "properties": {
+                "counts": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.ChannelConnection"
+                    }
+                }
+            }
+        },
         "handlers.ChannelMeta": {
             "type": "object",
             "properties": {